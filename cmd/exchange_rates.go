@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	clis "github.com/kyy-me/ezbookkeeping/pkg/cli"
+	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/log"
+	"github.com/kyy-me/ezbookkeeping/pkg/utils"
+)
+
+// ExchangeRates represents the exchange rates command
+var ExchangeRates = &cli.Command{
+	Name:  "exchangerates",
+	Usage: "ezBookkeeping exchange rates maintenance",
+	Subcommands: []*cli.Command{
+		{
+			Name:   "update-latest",
+			Usage:  "Request the latest exchange rates from current data source and save them",
+			Action: updateLatestExchangeRates,
+		},
+		{
+			Name:   "backfill",
+			Usage:  "Load the historical exchange rates published by all configured data sources and save them",
+			Action: backfillHistoricalExchangeRates,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "data-source",
+					Aliases:  []string{"d"},
+					Required: false,
+					Usage:    "Only load the historical exchange rates of the specified configured data source (e.g. euro_central_bank)",
+				},
+				&cli.StringFlag{
+					Name:     "file",
+					Aliases:  []string{"f"},
+					Required: false,
+					Usage:    "Load the historical exchange rates from the specified local file instead of requesting data source (e.g. downloaded eurofxref-hist.xml), the file would be parsed by the specified data source or current data source",
+				},
+				&cli.StringFlag{
+					Name:     "start-date",
+					Aliases:  []string{"s"},
+					Required: false,
+					Usage:    "Only save the exchange rates on or after this date (YYYY-MM-DD)",
+				},
+				&cli.StringFlag{
+					Name:     "end-date",
+					Aliases:  []string{"e"},
+					Required: false,
+					Usage:    "Only save the exchange rates on or before this date (YYYY-MM-DD)",
+				},
+			},
+		},
+	},
+}
+
+func updateLatestExchangeRates(c *cli.Context) error {
+	_, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	savedCount, err := clis.ExchangeRates.UpdateLatestExchangeRates(c)
+
+	if err != nil {
+		log.BootErrorf("[exchange_rates.updateLatestExchangeRates] error occurs when updating latest exchange rates")
+		return err
+	}
+
+	fmt.Printf("[Saved Exchange Rates] %d\n", savedCount)
+
+	return nil
+}
+
+func backfillHistoricalExchangeRates(c *cli.Context) error {
+	_, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	var content []byte
+	var startDate, endDate int32
+
+	if c.String("file") != "" {
+		content, err = os.ReadFile(c.String("file"))
+
+		if err != nil {
+			log.BootErrorf("[exchange_rates.backfillHistoricalExchangeRates] cannot read file \"%s\", because %s", c.String("file"), err.Error())
+			return err
+		}
+	}
+
+	if c.String("start-date") != "" {
+		startDate, err = utils.ParseNumericDate(c.String("start-date"))
+
+		if err != nil {
+			log.BootErrorf("[exchange_rates.backfillHistoricalExchangeRates] start date \"%s\" is invalid", c.String("start-date"))
+			return err
+		}
+	}
+
+	if c.String("end-date") != "" {
+		endDate, err = utils.ParseNumericDate(c.String("end-date"))
+
+		if err != nil {
+			log.BootErrorf("[exchange_rates.backfillHistoricalExchangeRates] end date \"%s\" is invalid", c.String("end-date"))
+			return err
+		}
+	}
+
+	results, err := clis.ExchangeRates.BackfillHistoricalExchangeRates(c, c.String("data-source"), content, startDate, endDate)
+
+	if err != nil {
+		log.BootErrorf("[exchange_rates.backfillHistoricalExchangeRates] error occurs when backfilling historical exchange rates")
+		return err
+	}
+
+	var lastErr error
+	supportedCount := 0
+
+	for i := 0; i < len(results); i++ {
+		result := results[i]
+
+		if result.Error == errs.ErrHistoricalExchangeRatesNotSupported {
+			fmt.Printf("[%s] [Skipped] This data source does not provide historical exchange rates\n", result.DataSource)
+			continue
+		}
+
+		supportedCount++
+
+		if result.Error != nil {
+			fmt.Printf("[%s] [Failed] %s\n", result.DataSource, result.Error.Error())
+			lastErr = result.Error
+			continue
+		}
+
+		fmt.Printf("[%s] [Saved Exchange Rates] %d\n", result.DataSource, result.SavedCount)
+	}
+
+	if lastErr != nil {
+		log.BootErrorf("[exchange_rates.backfillHistoricalExchangeRates] error occurs when backfilling historical exchange rates of some data sources")
+		return lastErr
+	}
+
+	if supportedCount < 1 {
+		return errs.ErrHistoricalExchangeRatesNotSupported
+	}
+
+	return nil
+}
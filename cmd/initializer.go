@@ -10,9 +10,11 @@ import (
 	"github.com/kyy-me/ezbookkeeping/pkg/exchangerates"
 	"github.com/kyy-me/ezbookkeeping/pkg/log"
 	"github.com/kyy-me/ezbookkeeping/pkg/mail"
+	"github.com/kyy-me/ezbookkeeping/pkg/oidc"
 	"github.com/kyy-me/ezbookkeeping/pkg/settings"
 	"github.com/kyy-me/ezbookkeeping/pkg/utils"
 	"github.com/kyy-me/ezbookkeeping/pkg/uuid"
+	"github.com/kyy-me/ezbookkeeping/pkg/webauthn"
 )
 
 func initializeSystem(c *cli.Context) (*settings.Config, error) {
@@ -93,6 +95,24 @@ func initializeSystem(c *cli.Context) (*settings.Config, error) {
 		return nil, err
 	}
 
+	err = oidc.InitializeOIDCProvider(config)
+
+	if err != nil {
+		if !isDisableBootLog {
+			log.BootErrorf("[initializer.initializeSystem] initializes openid connect provider failed, because %s", err.Error())
+		}
+		return nil, err
+	}
+
+	err = webauthn.InitializeRelyingParty(config)
+
+	if err != nil {
+		if !isDisableBootLog {
+			log.BootErrorf("[initializer.initializeSystem] initializes webauthn relying party failed, because %s", err.Error())
+		}
+		return nil, err
+	}
+
 	err = exchangerates.InitializeExchangeRatesDataSource(config)
 
 	if err != nil {
@@ -123,5 +143,13 @@ func getConfigWithoutSensitiveData(config *settings.Config) *settings.Config {
 	clonedConfig.SMTPConfig.SMTPPasswd = "****"
 	clonedConfig.SecretKey = "****"
 
+	if clonedConfig.OIDCClientSecret != "" {
+		clonedConfig.OIDCClientSecret = "****"
+	}
+
+	if clonedConfig.LDAPBindPassword != "" {
+		clonedConfig.LDAPBindPassword = "****"
+	}
+
 	return clonedConfig
 }
@@ -3,10 +3,12 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/urfave/cli/v2"
 
 	clis "github.com/kyy-me/ezbookkeeping/pkg/cli"
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
 	"github.com/kyy-me/ezbookkeeping/pkg/errs"
 	"github.com/kyy-me/ezbookkeeping/pkg/log"
 	"github.com/kyy-me/ezbookkeeping/pkg/models"
@@ -113,6 +115,32 @@ var UserData = &cli.Command{
 				},
 			},
 		},
+		{
+			Name:   "user-set-admin",
+			Usage:  "Grant administrator role to specified user",
+			Action: setUserAdmin,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "username",
+					Aliases:  []string{"n"},
+					Required: true,
+					Usage:    "Specific user name",
+				},
+			},
+		},
+		{
+			Name:   "user-unset-admin",
+			Usage:  "Revoke administrator role from specified user",
+			Action: unsetUserAdmin,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "username",
+					Aliases:  []string{"n"},
+					Required: true,
+					Usage:    "Specific user name",
+				},
+			},
+		},
 		{
 			Name:   "user-resend-verify-email",
 			Usage:  "Resend user verify email",
@@ -167,7 +195,7 @@ var UserData = &cli.Command{
 		},
 		{
 			Name:   "user-2fa-disable",
-			Usage:  "Disable user 2fa setting",
+			Usage:  "Disable user 2fa setting and remove all webauthn credentials",
 			Action: disableUser2FA,
 			Flags: []cli.Flag{
 				&cli.StringFlag{
@@ -178,6 +206,19 @@ var UserData = &cli.Command{
 				},
 			},
 		},
+		{
+			Name:   "user-unlock",
+			Usage:  "Unlock specified user which is locked because of too many failed login attempts",
+			Action: unlockUser,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "username",
+					Aliases:  []string{"n"},
+					Required: true,
+					Usage:    "Specific user name",
+				},
+			},
+		},
 		{
 			Name:   "user-session-list",
 			Usage:  "List all user sessions",
@@ -191,6 +232,19 @@ var UserData = &cli.Command{
 				},
 			},
 		},
+		{
+			Name:   "user-audit-list",
+			Usage:  "List all security audit events of specified user",
+			Action: listUserAuditEvents,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "username",
+					Aliases:  []string{"n"},
+					Required: true,
+					Usage:    "Specific user name",
+				},
+			},
+		},
 		{
 			Name:   "user-session-clear",
 			Usage:  "Clear user all sessions",
@@ -204,6 +258,52 @@ var UserData = &cli.Command{
 				},
 			},
 		},
+		{
+			Name:   "user-token-create",
+			Usage:  "Create a new personal access token for specified user",
+			Action: createUserPersonalAccessToken,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "username",
+					Aliases:  []string{"n"},
+					Required: true,
+					Usage:    "Specific user name",
+				},
+				&cli.StringFlag{
+					Name:     "name",
+					Required: true,
+					Usage:    "Personal access token name",
+				},
+				&cli.StringFlag{
+					Name:     "scopes",
+					Required: true,
+					Usage:    "Comma separated personal access token scopes, support accounts:read, accounts:write, transactions:read, transactions:write and export",
+				},
+				&cli.UintFlag{
+					Name:     "expires-in-days",
+					Required: false,
+					Usage:    "Personal access token will be expired after specified days, default is never expired",
+				},
+			},
+		},
+		{
+			Name:   "user-token-revoke",
+			Usage:  "Revoke specified session or personal access token of specified user",
+			Action: revokeUserToken,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "username",
+					Aliases:  []string{"n"},
+					Required: true,
+					Usage:    "Specific user name",
+				},
+				&cli.StringFlag{
+					Name:     "token-id",
+					Required: true,
+					Usage:    "Specific token id which is shown in user session list",
+				},
+			},
+		},
 		{
 			Name:   "send-password-reset-mail",
 			Usage:  "Send password reset mail",
@@ -255,6 +355,48 @@ var UserData = &cli.Command{
 				},
 			},
 		},
+		{
+			Name:   "invitation-code-create",
+			Usage:  "Create a new invitation code for registering",
+			Action: createInvitationCode,
+			Flags: []cli.Flag{
+				&cli.UintFlag{
+					Name:     "max-uses",
+					Required: false,
+					Value:    1,
+					Usage:    "Maximum times the invitation code can be used, default is 1",
+				},
+				&cli.UintFlag{
+					Name:     "expires-in-days",
+					Required: false,
+					Value:    7,
+					Usage:    "Invitation code will be expired after specified days, default is 7",
+				},
+				&cli.StringFlag{
+					Name:     "email",
+					Aliases:  []string{"e"},
+					Required: false,
+					Usage:    "Only the user registering with specified email can use the invitation code",
+				},
+			},
+		},
+		{
+			Name:   "invitation-code-list",
+			Usage:  "List all invitation codes which are not revoked",
+			Action: listInvitationCodes,
+		},
+		{
+			Name:   "invitation-code-revoke",
+			Usage:  "Revoke specified invitation code",
+			Action: revokeInvitationCode,
+			Flags: []cli.Flag{
+				&cli.Int64Flag{
+					Name:     "id",
+					Required: true,
+					Usage:    "Specific invitation code id",
+				},
+			},
+		},
 	},
 }
 
@@ -364,6 +506,46 @@ func enableUser(c *cli.Context) error {
 	return nil
 }
 
+func setUserAdmin(c *cli.Context) error {
+	_, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	username := c.String("username")
+	err = clis.UserData.SetUserAdmin(c, username, true)
+
+	if err != nil {
+		log.BootErrorf("[user_data.setUserAdmin] error occurs when granting administrator role to user")
+		return err
+	}
+
+	log.BootInfof("[user_data.setUserAdmin] user \"%s\" has been granted administrator role", username)
+
+	return nil
+}
+
+func unsetUserAdmin(c *cli.Context) error {
+	_, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	username := c.String("username")
+	err = clis.UserData.SetUserAdmin(c, username, false)
+
+	if err != nil {
+		log.BootErrorf("[user_data.unsetUserAdmin] error occurs when revoking administrator role from user")
+		return err
+	}
+
+	log.BootInfof("[user_data.unsetUserAdmin] administrator role has been revoked from user \"%s\"", username)
+
+	return nil
+}
+
 func disableUser(c *cli.Context) error {
 	_, err := initializeSystem(c)
 
@@ -484,6 +666,26 @@ func disableUser2FA(c *cli.Context) error {
 	return nil
 }
 
+func unlockUser(c *cli.Context) error {
+	_, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	username := c.String("username")
+	err = clis.UserData.UnlockUser(c, username)
+
+	if err != nil {
+		log.BootErrorf("[user_data.unlockUser] error occurs when unlocking user")
+		return err
+	}
+
+	log.BootInfof("[user_data.unlockUser] user \"%s\" has been unlocked", username)
+
+	return nil
+}
+
 func listUserTokens(c *cli.Context) error {
 	_, err := initializeSystem(c)
 
@@ -500,7 +702,7 @@ func listUserTokens(c *cli.Context) error {
 	}
 
 	for i := 0; i < len(tokens); i++ {
-		printTokenInfo(tokens[i])
+		printTokenInfo(clis.UserData.GetUserTokenId(tokens[i]), tokens[i])
 
 		if i < len(tokens)-1 {
 			fmt.Printf("---\n")
@@ -510,6 +712,86 @@ func listUserTokens(c *cli.Context) error {
 	return nil
 }
 
+func listUserAuditEvents(c *cli.Context) error {
+	_, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	username := c.String("username")
+	events, err := clis.UserData.ListUserAuditEvents(c, username)
+
+	if err != nil {
+		log.BootErrorf("[user_data.listUserAuditEvents] error occurs when getting user audit events")
+		return err
+	}
+
+	for i := 0; i < len(events); i++ {
+		printUserAuditEventInfo(events[i])
+
+		if i < len(events)-1 {
+			fmt.Printf("---\n")
+		}
+	}
+
+	return nil
+}
+
+func createUserPersonalAccessToken(c *cli.Context) error {
+	_, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	username := c.String("username")
+	name := c.String("name")
+	scopeNames := strings.Split(c.String("scopes"), ",")
+	scopes := make([]core.TokenScope, 0, len(scopeNames))
+
+	for i := 0; i < len(scopeNames); i++ {
+		scopeName := strings.TrimSpace(scopeNames[i])
+
+		if scopeName != "" {
+			scopes = append(scopes, core.TokenScope(scopeName))
+		}
+	}
+
+	token, tokenRecord, err := clis.UserData.CreateUserPersonalAccessToken(c, username, name, scopes, uint32(c.Uint("expires-in-days")))
+
+	if err != nil {
+		log.BootErrorf("[user_data.createUserPersonalAccessToken] error occurs when creating personal access token")
+		return err
+	}
+
+	printTokenInfo(clis.UserData.GetUserTokenId(tokenRecord), tokenRecord)
+	fmt.Printf("[Token] %s\n", token)
+
+	return nil
+}
+
+func revokeUserToken(c *cli.Context) error {
+	_, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	username := c.String("username")
+	tokenId := c.String("token-id")
+	err = clis.UserData.RevokeUserToken(c, username, tokenId)
+
+	if err != nil {
+		log.BootErrorf("[user_data.revokeUserToken] error occurs when revoking user token")
+		return err
+	}
+
+	log.BootInfof("[user_data.revokeUserToken] token \"%s\" of user \"%s\" has been revoked", tokenId, username)
+
+	return nil
+}
+
 func clearUserTokens(c *cli.Context) error {
 	_, err := initializeSystem(c)
 
@@ -602,6 +884,70 @@ func exportUserTransaction(c *cli.Context) error {
 	return nil
 }
 
+func createInvitationCode(c *cli.Context) error {
+	_, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	invitationCode, err := clis.UserData.CreateInvitationCode(c, uint32(c.Uint("max-uses")), uint32(c.Uint("expires-in-days")), c.String("email"))
+
+	if err != nil {
+		log.BootErrorf("[user_data.createInvitationCode] error occurs when creating invitation code")
+		return err
+	}
+
+	printInvitationCodeInfo(invitationCode)
+
+	return nil
+}
+
+func listInvitationCodes(c *cli.Context) error {
+	_, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	invitationCodes, err := clis.UserData.ListInvitationCodes(c)
+
+	if err != nil {
+		log.BootErrorf("[user_data.listInvitationCodes] error occurs when getting invitation codes")
+		return err
+	}
+
+	for i := 0; i < len(invitationCodes); i++ {
+		printInvitationCodeInfo(invitationCodes[i])
+
+		if i < len(invitationCodes)-1 {
+			fmt.Printf("---\n")
+		}
+	}
+
+	return nil
+}
+
+func revokeInvitationCode(c *cli.Context) error {
+	_, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	codeId := c.Int64("id")
+	err = clis.UserData.RevokeInvitationCode(c, codeId)
+
+	if err != nil {
+		log.BootErrorf("[user_data.revokeInvitationCode] error occurs when revoking invitation code")
+		return err
+	}
+
+	log.BootInfof("[user_data.revokeInvitationCode] invitation code \"id:%d\" has been revoked", codeId)
+
+	return nil
+}
+
 func printUserInfo(user *models.User) {
 	fmt.Printf("[Uid] %d\n", user.Uid)
 	fmt.Printf("[Username] %s\n", user.Username)
@@ -614,6 +960,8 @@ func printUserInfo(user *models.User) {
 	fmt.Printf("[Language] %s\n", user.Language)
 	fmt.Printf("[DefaultCurrency] %s\n", user.DefaultCurrency)
 	fmt.Printf("[FirstDayOfWeek] %s (%d)\n", user.FirstDayOfWeek, user.FirstDayOfWeek)
+	fmt.Printf("[FiscalYearStartMonth] %d\n", user.GetFiscalYearStartMonth())
+	fmt.Printf("[MonthStartDay] %d\n", user.GetMonthStartDay())
 	fmt.Printf("[LongDateFormat] %s (%d)\n", user.LongDateFormat, user.LongDateFormat)
 	fmt.Printf("[ShortDateFormat] %s (%d)\n", user.ShortDateFormat, user.ShortDateFormat)
 	fmt.Printf("[LongTimeFormat] %s (%d)\n", user.LongTimeFormat, user.LongTimeFormat)
@@ -622,8 +970,15 @@ func printUserInfo(user *models.User) {
 	fmt.Printf("[DigitGroupingSymbol] %s (%d)\n", user.DigitGroupingSymbol, user.DigitGroupingSymbol)
 	fmt.Printf("[DigitGrouping] %s (%d)\n", user.DigitGrouping, user.DigitGrouping)
 	fmt.Printf("[CurrencyDisplayType] %s (%d)\n", user.CurrencyDisplayType, user.CurrencyDisplayType)
+	fmt.Printf("[Disabled] %t\n", user.Disabled)
+	fmt.Printf("[IsAdmin] %t\n", user.IsAdmin)
 	fmt.Printf("[Deleted] %t\n", user.Deleted)
 	fmt.Printf("[EmailVerified] %t\n", user.EmailVerified)
+
+	if user.InvitationCodeId > 0 {
+		fmt.Printf("[InvitationCodeId] %d\n", user.InvitationCodeId)
+	}
+
 	fmt.Printf("[CreatedAt] %s (%d)\n", utils.FormatUnixTimeToLongDateTimeInServerTimezone(user.CreatedUnixTime), user.CreatedUnixTime)
 
 	if user.UpdatedUnixTime > 0 {
@@ -639,8 +994,39 @@ func printUserInfo(user *models.User) {
 	}
 }
 
-func printTokenInfo(token *models.TokenRecord) {
+func printInvitationCodeInfo(invitationCode *models.InvitationCode) {
+	fmt.Printf("[CodeId] %d\n", invitationCode.CodeId)
+	fmt.Printf("[Code] %s\n", invitationCode.Code)
+	fmt.Printf("[CreatorUid] %d\n", invitationCode.CreatorUid)
+
+	if invitationCode.BoundEmail != "" {
+		fmt.Printf("[BoundEmail] %s\n", invitationCode.BoundEmail)
+	}
+
+	fmt.Printf("[UsedCount] %d/%d\n", invitationCode.UsedCount, invitationCode.MaxUses)
+	fmt.Printf("[CreatedAt] %s (%d)\n", utils.FormatUnixTimeToLongDateTimeInServerTimezone(invitationCode.CreatedUnixTime), invitationCode.CreatedUnixTime)
+	fmt.Printf("[ExpiredAt] %s (%d)\n", utils.FormatUnixTimeToLongDateTimeInServerTimezone(invitationCode.ExpiredUnixTime), invitationCode.ExpiredUnixTime)
+}
+
+func printTokenInfo(tokenId string, token *models.TokenRecord) {
+	fmt.Printf("[TokenId] %s\n", tokenId)
+
+	if token.TokenType == core.USER_TOKEN_TYPE_PERSONAL_ACCESS {
+		fmt.Printf("[Name] %s\n", token.Name)
+		fmt.Printf("[Scopes] %s\n", token.Scopes)
+	}
+
 	fmt.Printf("[CreatedAt] %s (%d)\n", utils.FormatUnixTimeToLongDateTimeInServerTimezone(token.CreatedUnixTime), token.CreatedUnixTime)
 	fmt.Printf("[ExpiredAt] %s (%d)\n", utils.FormatUnixTimeToLongDateTimeInServerTimezone(token.ExpiredUnixTime), token.ExpiredUnixTime)
 	fmt.Printf("[UserAgent] %s\n", token.UserAgent)
 }
+
+func printUserAuditEventInfo(event *models.UserAuditEvent) {
+	fmt.Printf("[EventId] %d\n", event.EventId)
+	fmt.Printf("[EventType] %s (%d)\n", event.EventType, event.EventType)
+	fmt.Printf("[CreatedAt] %s (%d)\n", utils.FormatUnixTimeToLongDateTimeInServerTimezone(event.CreatedUnixTime), event.CreatedUnixTime)
+	fmt.Printf("[ClientIp] %s\n", event.ClientIp)
+	fmt.Printf("[UserAgent] %s\n", event.UserAgent)
+	fmt.Printf("[RequestId] %s\n", event.RequestId)
+	fmt.Printf("[Details] %s\n", event.Details)
+}
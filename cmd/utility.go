@@ -4,11 +4,15 @@ import (
 	"encoding/binary"
 	"fmt"
 	"net"
+	"strings"
 
 	"github.com/urfave/cli/v2"
 
+	clis "github.com/kyy-me/ezbookkeeping/pkg/cli"
 	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/log"
 	"github.com/kyy-me/ezbookkeeping/pkg/mail"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
 	"github.com/kyy-me/ezbookkeeping/pkg/requestid"
 	"github.com/kyy-me/ezbookkeeping/pkg/utils"
 )
@@ -30,6 +34,11 @@ var Utilities = &cli.Command{
 				},
 			},
 		},
+		{
+			Name:   "exchange-rates-check",
+			Usage:  "Request the latest exchange rates from every configured data source once and show the health status",
+			Action: checkExchangeRatesDataSources,
+		},
 		{
 			Name:   "send-test-mail",
 			Usage:  "Send an email to specified e-mail address",
@@ -73,6 +82,29 @@ func parseRequestId(c *cli.Context) error {
 	return nil
 }
 
+func checkExchangeRatesDataSources(c *cli.Context) error {
+	_, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	health, err := clis.ExchangeRates.CheckExchangeRatesDataSources(c)
+
+	if err != nil {
+		log.BootErrorf("[utility.checkExchangeRatesDataSources] error occurs when checking exchange rates data sources")
+		return err
+	}
+
+	healthy := printExchangeRatesHealth(health)
+
+	if !healthy {
+		return errs.ErrExchangeRatesDataSourceUnhealthy
+	}
+
+	return nil
+}
+
 func sendTestMail(c *cli.Context) error {
 	config, err := initializeSystem(c)
 
@@ -126,3 +158,47 @@ func printRequestIdInfo(requestId string, requestIdInfo *requestid.RequestIdInfo
 		fmt.Printf("[ClientIpv4] %s\n", ip.String())
 	}
 }
+
+func printExchangeRatesHealth(health *models.ExchangeRatesHealthResponse) bool {
+	healthy := true
+
+	for i := 0; i < len(health.DataSources); i++ {
+		dataSource := health.DataSources[i]
+
+		fmt.Printf("[DataSource] %s\n", dataSource.DataSource)
+
+		if dataSource.LastSuccessfulFetchTime > 0 {
+			fmt.Printf("[UpdateTime] %s\n", utils.FormatUnixTimeToLongDateTimeInServerTimezone(dataSource.UpdateTime))
+			fmt.Printf("[Currencies] %s\n", strings.Join(dataSource.Currencies, ","))
+		}
+
+		if dataSource.LastError != "" {
+			fmt.Printf("[LastError] %s\n", dataSource.LastError)
+		}
+
+		fmt.Printf("[FetchFailureCount] %d\n", dataSource.FetchFailureCount)
+		fmt.Printf("[ParseFailureCount] %d\n", dataSource.ParseFailureCount)
+
+		for j := 0; j < len(dataSource.Problems); j++ {
+			fmt.Printf("[Problem] %s\n", dataSource.Problems[j])
+		}
+
+		if dataSource.LastSuccessfulFetchTime < dataSource.LastAttemptTime || len(dataSource.Problems) > 0 {
+			healthy = false
+			fmt.Printf("[Status] unhealthy\n")
+		} else {
+			fmt.Printf("[Status] ok\n")
+		}
+
+		fmt.Printf("\n")
+	}
+
+	if len(health.UncoveredCurrencies) > 0 {
+		healthy = false
+		fmt.Printf("[UncoveredCurrencies] %s\n", strings.Join(health.UncoveredCurrencies, ","))
+	} else {
+		fmt.Printf("[UncoveredCurrencies] none\n")
+	}
+
+	return healthy
+}
@@ -16,9 +16,11 @@ import (
 	"github.com/kyy-me/ezbookkeeping/pkg/api"
 	"github.com/kyy-me/ezbookkeeping/pkg/core"
 	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/exchangerates"
 	"github.com/kyy-me/ezbookkeeping/pkg/log"
 	"github.com/kyy-me/ezbookkeeping/pkg/middlewares"
 	"github.com/kyy-me/ezbookkeeping/pkg/requestid"
+	"github.com/kyy-me/ezbookkeeping/pkg/services"
 	"github.com/kyy-me/ezbookkeeping/pkg/settings"
 	"github.com/kyy-me/ezbookkeeping/pkg/utils"
 	"github.com/kyy-me/ezbookkeeping/pkg/validators"
@@ -74,6 +76,13 @@ func startWebServer(c *cli.Context) error {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
+	for i := 0; i < len(exchangerates.Container.Caches); i++ {
+		exchangerates.Container.Caches[i].SetFetchedCallback(api.ExchangeRates.SaveLatestExchangeRates)
+		exchangerates.Container.Caches[i].StartBackgroundRefresh()
+	}
+
+	services.UserDeletions.StartBackgroundPurge()
+
 	workboxFileNames := utils.ListFileNamesWithPrefixAndSuffix(config.StaticRootPath, "workbox-", ".js")
 
 	router := gin.New()
@@ -88,6 +97,8 @@ func startWebServer(c *cli.Context) error {
 		_ = v.RegisterValidation("validUsername", validators.ValidUsername)
 		_ = v.RegisterValidation("validEmail", validators.ValidEmail)
 		_ = v.RegisterValidation("validCurrency", validators.ValidCurrency)
+		_ = v.RegisterValidation("validCommodityCode", validators.ValidCommodityCode)
+		_ = v.RegisterValidation("validCurrencyOrCommodityCode", validators.ValidCurrencyOrCommodityCode)
 		_ = v.RegisterValidation("validHexRGBColor", validators.ValidHexRGBColor)
 		_ = v.RegisterValidation("validAmountFilter", validators.ValidAmountFilter)
 	}
@@ -197,6 +208,27 @@ func startWebServer(c *cli.Context) error {
 			{
 				twoFactorRoute.POST("/authorize.json", bindApiWithTokenUpdate(api.Authorizations.TwoFactorAuthorizeHandler, config))
 				twoFactorRoute.POST("/recovery.json", bindApiWithTokenUpdate(api.Authorizations.TwoFactorAuthorizeByRecoveryCodeHandler, config))
+
+				if config.EnableWebAuthn {
+					twoFactorRoute.POST("/webauthn/request.json", bindApi(api.Authorizations.TwoFactorWebAuthnRequestHandler))
+					twoFactorRoute.POST("/webauthn/authorize.json", bindApiWithTokenUpdate(api.Authorizations.TwoFactorWebAuthnAuthorizeHandler, config))
+				}
+			}
+		}
+
+		if config.EnableWebAuthnPasswordlessLogin {
+			webAuthnLoginRoute := apiRoute.Group("/webauthn/login")
+			{
+				webAuthnLoginRoute.POST("/request.json", bindApi(api.Authorizations.WebAuthnLoginRequestHandler))
+				webAuthnLoginRoute.POST("/authorize.json", bindApiWithTokenUpdate(api.Authorizations.WebAuthnLoginAuthorizeHandler, config))
+			}
+		}
+
+		if config.EnableOIDC {
+			oidcRoute := apiRoute.Group("/oidc")
+			{
+				oidcRoute.GET("/authorize_url.json", bindApi(api.Authorizations.OIDCAuthorizeUrlHandler))
+				oidcRoute.POST("/authorize.json", bindApiWithTokenUpdate(api.Authorizations.OIDCAuthorizeHandler, config))
 			}
 		}
 
@@ -214,6 +246,14 @@ func startWebServer(c *cli.Context) error {
 			}
 		}
 
+		if config.EnableSMTP {
+			emailChangeRoute := apiRoute.Group("/email_change")
+			emailChangeRoute.Use(bindMiddleware(middlewares.JWTEmailChangeAuthorization))
+			{
+				emailChangeRoute.POST("/by_token.json", bindApi(api.Users.UserEmailChangeConfirmHandler))
+			}
+		}
+
 		if config.EnableUserForgetPassword {
 			apiRoute.POST("/forget_password/request.json", bindApi(api.ForgetPasswords.UserForgetPasswordRequestHandler))
 
@@ -226,11 +266,14 @@ func startWebServer(c *cli.Context) error {
 
 		apiRoute.GET("/logout.json", bindApiWithTokenUpdate(api.Tokens.TokenRevokeCurrentHandler, config))
 
-		apiV1Route := apiRoute.Group("/v1")
+		apiV1BaseRoute := apiRoute.Group("/v1")
+
+		apiV1Route := apiV1BaseRoute.Group("")
 		apiV1Route.Use(bindMiddleware(middlewares.JWTAuthorization))
 		{
 			// Tokens
 			apiV1Route.GET("/tokens/list.json", bindApi(api.Tokens.TokenListHandler))
+			apiV1Route.POST("/tokens/personal/create.json", bindApi(api.Tokens.PersonalAccessTokenCreateHandler))
 			apiV1Route.POST("/tokens/revoke.json", bindApi(api.Tokens.TokenRevokeHandler))
 			apiV1Route.POST("/tokens/revoke_all.json", bindApi(api.Tokens.TokenRevokeAllHandler))
 			apiV1Route.POST("/tokens/refresh.json", bindApiWithTokenUpdate(api.Tokens.TokenRefreshHandler, config))
@@ -243,6 +286,17 @@ func startWebServer(c *cli.Context) error {
 				apiV1Route.POST("/users/verify_email/resend.json", bindApi(api.Users.UserSendVerifyEmailByLoginedUserHandler))
 			}
 
+			apiV1Route.GET("/users/audit/list.json", bindApi(api.UserAuditEvents.UserAuditEventListHandler))
+
+			if config.EnableUserAccountDeletion {
+				apiV1Route.POST("/users/delete/request.json", bindApi(api.Users.UserAccountDeletionRequestHandler))
+				apiV1Route.POST("/users/delete/cancel.json", bindApi(api.Users.UserAccountDeletionCancelHandler))
+
+				if config.EnableWebAuthn {
+					apiV1Route.POST("/users/delete/webauthn/request.json", bindApi(api.Authorizations.TwoFactorWebAuthnRequestHandler))
+				}
+			}
+
 			// Two-Factor Authorization
 			if config.EnableTwoFactor {
 				apiV1Route.GET("/users/2fa/status.json", bindApi(api.TwoFactorAuthorizations.TwoFactorStatusHandler))
@@ -252,39 +306,19 @@ func startWebServer(c *cli.Context) error {
 				apiV1Route.POST("/users/2fa/recovery/regenerate.json", bindApi(api.TwoFactorAuthorizations.TwoFactorRecoveryCodeRegenerateHandler))
 			}
 
+			// WebAuthn Credentials
+			if config.EnableWebAuthn {
+				apiV1Route.GET("/users/webauthn/list.json", bindApi(api.WebAuthnCredentials.WebAuthnCredentialListHandler))
+				apiV1Route.POST("/users/webauthn/register/request.json", bindApi(api.WebAuthnCredentials.WebAuthnRegistrationRequestHandler))
+				apiV1Route.POST("/users/webauthn/register/confirm.json", bindApi(api.WebAuthnCredentials.WebAuthnRegistrationConfirmHandler))
+				apiV1Route.POST("/users/webauthn/delete.json", bindApi(api.WebAuthnCredentials.WebAuthnCredentialDeleteHandler))
+			}
+
 			// Data
 			apiV1Route.GET("/data/statistics.json", bindApi(api.DataManagements.DataStatisticsHandler))
 			apiV1Route.POST("/data/clear.json", bindApi(api.DataManagements.ClearDataHandler))
 
-			if config.EnableDataExport {
-				apiV1Route.GET("/data/export.csv", bindCsv(api.DataManagements.ExportDataToEzbookkeepingCSVHandler))
-				apiV1Route.GET("/data/export.tsv", bindTsv(api.DataManagements.ExportDataToEzbookkeepingTSVHandler))
-			}
-
-			// Accounts
-			apiV1Route.GET("/accounts/list.json", bindApi(api.Accounts.AccountListHandler))
-			apiV1Route.GET("/accounts/get.json", bindApi(api.Accounts.AccountGetHandler))
-			apiV1Route.POST("/accounts/add.json", bindApi(api.Accounts.AccountCreateHandler))
-			apiV1Route.POST("/accounts/modify.json", bindApi(api.Accounts.AccountModifyHandler))
-			apiV1Route.POST("/accounts/hide.json", bindApi(api.Accounts.AccountHideHandler))
-			apiV1Route.POST("/accounts/move.json", bindApi(api.Accounts.AccountMoveHandler))
-			apiV1Route.POST("/accounts/delete.json", bindApi(api.Accounts.AccountDeleteHandler))
-
-			// Transactions
-			apiV1Route.GET("/transactions/count.json", bindApi(api.Transactions.TransactionCountHandler))
-			apiV1Route.GET("/transactions/list.json", bindApi(api.Transactions.TransactionListHandler))
-			apiV1Route.GET("/transactions/list/by_month.json", bindApi(api.Transactions.TransactionMonthListHandler))
-			apiV1Route.GET("/transactions/statistics.json", bindApi(api.Transactions.TransactionStatisticsHandler))
-			apiV1Route.GET("/transactions/statistics/trends.json", bindApi(api.Transactions.TransactionStatisticsTrendsHandler))
-			apiV1Route.GET("/transactions/amounts.json", bindApi(api.Transactions.TransactionAmountsHandler))
-			apiV1Route.GET("/transactions/get.json", bindApi(api.Transactions.TransactionGetHandler))
-			apiV1Route.POST("/transactions/add.json", bindApi(api.Transactions.TransactionCreateHandler))
-			apiV1Route.POST("/transactions/modify.json", bindApi(api.Transactions.TransactionModifyHandler))
-			apiV1Route.POST("/transactions/delete.json", bindApi(api.Transactions.TransactionDeleteHandler))
-
 			// Transaction Categories
-			apiV1Route.GET("/transaction/categories/list.json", bindApi(api.TransactionCategories.CategoryListHandler))
-			apiV1Route.GET("/transaction/categories/get.json", bindApi(api.TransactionCategories.CategoryGetHandler))
 			apiV1Route.POST("/transaction/categories/add.json", bindApi(api.TransactionCategories.CategoryCreateHandler))
 			apiV1Route.POST("/transaction/categories/add_batch.json", bindApi(api.TransactionCategories.CategoryCreateBatchHandler))
 			apiV1Route.POST("/transaction/categories/modify.json", bindApi(api.TransactionCategories.CategoryModifyHandler))
@@ -293,16 +327,123 @@ func startWebServer(c *cli.Context) error {
 			apiV1Route.POST("/transaction/categories/delete.json", bindApi(api.TransactionCategories.CategoryDeleteHandler))
 
 			// Transaction Tags
-			apiV1Route.GET("/transaction/tags/list.json", bindApi(api.TransactionTags.TagListHandler))
-			apiV1Route.GET("/transaction/tags/get.json", bindApi(api.TransactionTags.TagGetHandler))
 			apiV1Route.POST("/transaction/tags/add.json", bindApi(api.TransactionTags.TagCreateHandler))
 			apiV1Route.POST("/transaction/tags/modify.json", bindApi(api.TransactionTags.TagModifyHandler))
 			apiV1Route.POST("/transaction/tags/hide.json", bindApi(api.TransactionTags.TagHideHandler))
 			apiV1Route.POST("/transaction/tags/move.json", bindApi(api.TransactionTags.TagMoveHandler))
 			apiV1Route.POST("/transaction/tags/delete.json", bindApi(api.TransactionTags.TagDeleteHandler))
 
+			// Reports
+			apiV1Route.GET("/reports/income_statement.json", bindApi(api.Reports.IncomeStatementHandler))
+			apiV1Route.GET("/reports/income_statement.csv", bindCsv(api.Reports.IncomeStatementToCSVHandler))
+			apiV1Route.GET("/reports/income_statement.xlsx", bindXlsx(api.Reports.IncomeStatementToXLSXHandler))
+			apiV1Route.GET("/reports/balance_sheet.json", bindApi(api.Reports.BalanceSheetHandler))
+			apiV1Route.GET("/reports/balance_sheet.csv", bindCsv(api.Reports.BalanceSheetToCSVHandler))
+			apiV1Route.GET("/reports/balance_sheet.xlsx", bindXlsx(api.Reports.BalanceSheetToXLSXHandler))
+			apiV1Route.GET("/reports/cash_flow_forecast.json", bindApi(api.Reports.CashFlowForecastHandler))
+			apiV1Route.GET("/reports/exchange_gain_loss.json", bindApi(api.Reports.ExchangeGainLossHandler))
+
 			// Exchange Rates
 			apiV1Route.GET("/exchange_rates/latest.json", bindApi(api.ExchangeRates.LatestExchangeRateHandler))
+			apiV1Route.GET("/exchange_rates/historical.json", bindApi(api.ExchangeRates.HistoricalExchangeRateHandler))
+			apiV1Route.GET("/exchange_rates/convert.json", bindApi(api.ExchangeRates.ConvertExchangeRateHandler))
+			apiV1Route.GET("/exchange_rates/user_rates/list.json", bindApi(api.ExchangeRates.UserExchangeRateListHandler))
+			apiV1Route.POST("/exchange_rates/user_rates/save.json", bindApi(api.ExchangeRates.UserExchangeRateSaveHandler))
+			apiV1Route.POST("/exchange_rates/user_rates/delete.json", bindApi(api.ExchangeRates.UserExchangeRateDeleteHandler))
+
+			// Commodities
+			apiV1Route.GET("/commodities/list.json", bindApi(api.Commodities.CommodityListHandler))
+			apiV1Route.GET("/commodities/get.json", bindApi(api.Commodities.CommodityGetHandler))
+			apiV1Route.POST("/commodities/add.json", bindApi(api.Commodities.CommodityCreateHandler))
+			apiV1Route.POST("/commodities/modify.json", bindApi(api.Commodities.CommodityModifyHandler))
+			apiV1Route.POST("/commodities/refresh_price.json", bindApi(api.Commodities.CommodityRefreshPriceHandler))
+			apiV1Route.POST("/commodities/delete.json", bindApi(api.Commodities.CommodityDeleteHandler))
+		}
+
+		adminRoute := apiV1Route.Group("/admin")
+		adminRoute.Use(bindMiddleware(middlewares.AdminAuthorization(config)))
+		{
+			// Exchange Rates
+			adminRoute.GET("/exchange_rates/health.json", bindApi(api.ExchangeRates.ExchangeRatesHealthHandler))
+
+			// Users
+			adminRoute.GET("/users/list.json", bindApi(api.AdminUsers.AdminUserListHandler))
+			adminRoute.GET("/users/get.json", bindApi(api.AdminUsers.AdminUserGetHandler))
+			adminRoute.GET("/users/data/statistics.json", bindApi(api.AdminUsers.AdminUserDataStatisticsHandler))
+			adminRoute.GET("/users/transactions/check.json", bindApi(api.AdminUsers.AdminUserTransactionCheckHandler))
+			adminRoute.POST("/users/enable.json", bindApi(api.AdminUsers.AdminUserEnableHandler))
+			adminRoute.POST("/users/disable.json", bindApi(api.AdminUsers.AdminUserDisableHandler))
+			adminRoute.POST("/users/verify_email.json", bindApi(api.AdminUsers.AdminUserSetEmailVerifiedHandler))
+			adminRoute.POST("/users/2fa/disable.json", bindApi(api.AdminUsers.AdminUserDisableTwoFactorHandler))
+			adminRoute.POST("/users/sessions/clear.json", bindApi(api.AdminUsers.AdminUserClearSessionsHandler))
+			adminRoute.POST("/users/unlock.json", bindApi(api.AdminUsers.AdminUserUnlockHandler))
+			adminRoute.POST("/users/send_password_reset_mail.json", bindApi(api.AdminUsers.AdminUserSendPasswordResetMailHandler))
+
+			// Invitation Codes
+			adminRoute.GET("/invitation_codes/list.json", bindApi(api.InvitationCodes.InvitationCodeListHandler))
+			adminRoute.POST("/invitation_codes/add.json", bindApi(api.InvitationCodes.InvitationCodeCreateHandler))
+			adminRoute.POST("/invitation_codes/revoke.json", bindApi(api.InvitationCodes.InvitationCodeRevokeHandler))
+		}
+
+		accountsReadRoute := apiV1BaseRoute.Group("")
+		accountsReadRoute.Use(bindMiddleware(middlewares.JWTAuthorizationWithScope(core.TOKEN_SCOPE_ACCOUNTS_READ)))
+		{
+			// Accounts
+			accountsReadRoute.GET("/accounts/list.json", bindApi(api.Accounts.AccountListHandler))
+			accountsReadRoute.GET("/accounts/get.json", bindApi(api.Accounts.AccountGetHandler))
+		}
+
+		accountsWriteRoute := apiV1BaseRoute.Group("")
+		accountsWriteRoute.Use(bindMiddleware(middlewares.JWTAuthorizationWithScope(core.TOKEN_SCOPE_ACCOUNTS_WRITE)))
+		{
+			// Accounts
+			accountsWriteRoute.POST("/accounts/add.json", bindApi(api.Accounts.AccountCreateHandler))
+			accountsWriteRoute.POST("/accounts/modify.json", bindApi(api.Accounts.AccountModifyHandler))
+			accountsWriteRoute.POST("/accounts/hide.json", bindApi(api.Accounts.AccountHideHandler))
+			accountsWriteRoute.POST("/accounts/move.json", bindApi(api.Accounts.AccountMoveHandler))
+			accountsWriteRoute.POST("/accounts/delete.json", bindApi(api.Accounts.AccountDeleteHandler))
+		}
+
+		transactionsReadRoute := apiV1BaseRoute.Group("")
+		transactionsReadRoute.Use(bindMiddleware(middlewares.JWTAuthorizationWithScope(core.TOKEN_SCOPE_TRANSACTIONS_READ)))
+		{
+			// Transactions
+			transactionsReadRoute.GET("/transactions/count.json", bindApi(api.Transactions.TransactionCountHandler))
+			transactionsReadRoute.GET("/transactions/list.json", bindApi(api.Transactions.TransactionListHandler))
+			transactionsReadRoute.GET("/transactions/list/by_month.json", bindApi(api.Transactions.TransactionMonthListHandler))
+			transactionsReadRoute.GET("/transactions/statistics.json", bindApi(api.Transactions.TransactionStatisticsHandler))
+			transactionsReadRoute.GET("/transactions/statistics/trends.json", bindApi(api.Transactions.TransactionStatisticsTrendsHandler))
+			transactionsReadRoute.GET("/transactions/statistics/tags.json", bindApi(api.Transactions.TransactionTagStatisticsHandler))
+			transactionsReadRoute.GET("/transactions/statistics/geo.json", bindApi(api.Transactions.TransactionGeoStatisticsHandler))
+			transactionsReadRoute.GET("/transactions/amounts.json", bindApi(api.Transactions.TransactionAmountsHandler))
+			transactionsReadRoute.GET("/transactions/get.json", bindApi(api.Transactions.TransactionGetHandler))
+
+			// Transaction Categories
+			transactionsReadRoute.GET("/transaction/categories/list.json", bindApi(api.TransactionCategories.CategoryListHandler))
+			transactionsReadRoute.GET("/transaction/categories/get.json", bindApi(api.TransactionCategories.CategoryGetHandler))
+
+			// Transaction Tags
+			transactionsReadRoute.GET("/transaction/tags/list.json", bindApi(api.TransactionTags.TagListHandler))
+			transactionsReadRoute.GET("/transaction/tags/get.json", bindApi(api.TransactionTags.TagGetHandler))
+		}
+
+		transactionsWriteRoute := apiV1BaseRoute.Group("")
+		transactionsWriteRoute.Use(bindMiddleware(middlewares.JWTAuthorizationWithScope(core.TOKEN_SCOPE_TRANSACTIONS_WRITE)))
+		{
+			// Transactions
+			transactionsWriteRoute.POST("/transactions/add.json", bindApi(api.Transactions.TransactionCreateHandler))
+			transactionsWriteRoute.POST("/transactions/modify.json", bindApi(api.Transactions.TransactionModifyHandler))
+			transactionsWriteRoute.POST("/transactions/delete.json", bindApi(api.Transactions.TransactionDeleteHandler))
+		}
+
+		if config.EnableDataExport {
+			exportRoute := apiV1BaseRoute.Group("")
+			exportRoute.Use(bindMiddleware(middlewares.JWTAuthorizationWithScope(core.TOKEN_SCOPE_EXPORT)))
+			{
+				// Data
+				exportRoute.GET("/data/export.csv", bindCsv(api.DataManagements.ExportDataToEzbookkeepingCSVHandler))
+				exportRoute.GET("/data/export.tsv", bindTsv(api.DataManagements.ExportDataToEzbookkeepingTSVHandler))
+			}
 		}
 	}
 
@@ -391,6 +532,19 @@ func bindTsv(fn core.DataHandlerFunc) gin.HandlerFunc {
 	}
 }
 
+func bindXlsx(fn core.DataHandlerFunc) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		c := core.WrapContext(ginCtx)
+		result, fileName, err := fn(c)
+
+		if err != nil {
+			utils.PrintDataErrorResult(c, "text/text", err)
+		} else {
+			utils.PrintDataSuccessResult(c, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", fileName, result)
+		}
+	}
+}
+
 func bindCachedPngImage(fn core.DataHandlerFunc, store persistence.CacheStore) gin.HandlerFunc {
 	return cache.CachePage(store, time.Minute, func(ginCtx *gin.Context) {
 		c := core.WrapContext(ginCtx)
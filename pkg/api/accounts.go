@@ -13,13 +13,15 @@ import (
 
 // AccountsApi represents account api
 type AccountsApi struct {
-	accounts *services.AccountService
+	accounts    *services.AccountService
+	commodities *services.CommodityService
 }
 
 // Initialize an account api singleton instance
 var (
 	Accounts = &AccountsApi{
-		accounts: services.Accounts,
+		accounts:    services.Accounts,
+		commodities: services.Commodities,
 	}
 )
 
@@ -193,6 +195,13 @@ func (a *AccountsApi) AccountCreateHandler(c *core.Context) (any, *errs.Error) {
 	}
 
 	uid := c.GetCurrentUid()
+	err = a.checkAccountCommodityCurrencies(c, uid, &accountCreateReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[accounts.AccountCreateHandler] failed to check account currency for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
 	maxOrderId, err := a.accounts.GetMaxDisplayOrder(c, uid, accountCreateReq.Category)
 
 	if err != nil {
@@ -410,6 +419,41 @@ func (a *AccountsApi) AccountDeleteHandler(c *core.Context) (any, *errs.Error) {
 	return true, nil
 }
 
+func (a *AccountsApi) checkAccountCommodityCurrencies(c *core.Context, uid int64, accountCreateReq *models.AccountCreateRequest) error {
+	var commodityCodes []string
+	allAccountCreateReqs := append([]*models.AccountCreateRequest{accountCreateReq}, accountCreateReq.SubAccounts...)
+
+	for i := 0; i < len(allAccountCreateReqs); i++ {
+		currency := allAccountCreateReqs[i].Currency
+
+		if currency == validators.ParentAccountCurrencyPlaceholder {
+			continue
+		}
+
+		if _, exists := validators.AllCurrencyNames[currency]; !exists {
+			commodityCodes = append(commodityCodes, currency)
+		}
+	}
+
+	if len(commodityCodes) < 1 {
+		return nil
+	}
+
+	commodityMap, err := a.commodities.GetCommoditiesByCodes(c, uid, commodityCodes)
+
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(commodityCodes); i++ {
+		if _, exists := commodityMap[commodityCodes[i]]; !exists {
+			return errs.ErrAccountCurrencyInvalid
+		}
+	}
+
+	return nil
+}
+
 func (a *AccountsApi) createNewAccountModel(uid int64, accountCreateReq *models.AccountCreateRequest, order int32) *models.Account {
 	return &models.Account{
 		Uid:            uid,
@@ -0,0 +1,447 @@
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/log"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+	"github.com/kyy-me/ezbookkeeping/pkg/services"
+	"github.com/kyy-me/ezbookkeeping/pkg/settings"
+)
+
+// AdminUsersApi represents user administration api
+type AdminUsersApi struct {
+	users                   *services.UserService
+	tokens                  *services.TokenService
+	accounts                *services.AccountService
+	transactions            *services.TransactionService
+	categories              *services.TransactionCategoryService
+	tags                    *services.TransactionTagService
+	twoFactorAuthorizations *services.TwoFactorAuthorizationService
+	userWebAuthnCredentials *services.UserWebAuthnCredentialService
+	forgetPasswords         *services.ForgetPasswordService
+	loginAttempts           *services.LoginAttemptService
+	userDataChecks          *services.UserDataCheckService
+}
+
+// Initialize a user administration api singleton instance
+var (
+	AdminUsers = &AdminUsersApi{
+		users:                   services.Users,
+		tokens:                  services.Tokens,
+		accounts:                services.Accounts,
+		transactions:            services.Transactions,
+		categories:              services.TransactionCategories,
+		tags:                    services.TransactionTags,
+		twoFactorAuthorizations: services.TwoFactorAuthorizations,
+		userWebAuthnCredentials: services.UserWebAuthnCredentials,
+		forgetPasswords:         services.ForgetPasswords,
+		loginAttempts:           services.LoginAttempts,
+		userDataChecks:          services.UserDataChecks,
+	}
+)
+
+// AdminUserListHandler returns the users which match the keyword
+func (a *AdminUsersApi) AdminUserListHandler(c *core.Context) (any, *errs.Error) {
+	var listReq models.AdminUserListRequest
+	err := c.ShouldBindQuery(&listReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[admin_users.AdminUserListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	users, err := a.users.GetUsers(c, listReq.Keyword, listReq.MaxId, listReq.Count+1)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[admin_users.AdminUserListHandler] failed to get users, because %s", err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	recordUserAuditEvent(c, c.GetCurrentUid(), models.USER_AUDIT_EVENT_TYPE_ADMIN_ACTION, fmt.Sprintf("list users with keyword \"%s\"", listReq.Keyword))
+
+	finalCount := len(users)
+	var nextMaxId *int64
+
+	if finalCount > int(listReq.Count) {
+		finalCount = int(listReq.Count)
+		nextMaxId = &users[finalCount].Uid
+	}
+
+	userResps := make([]*models.AdminUserInfoResponse, finalCount)
+
+	for i := 0; i < finalCount; i++ {
+		userResps[i] = users[i].ToAdminUserInfoResponse()
+	}
+
+	return &models.AdminUserInfoPageWrapperResponse{
+		Items:     userResps,
+		NextMaxId: nextMaxId,
+	}, nil
+}
+
+// AdminUserGetHandler returns the specified user
+func (a *AdminUsersApi) AdminUserGetHandler(c *core.Context) (any, *errs.Error) {
+	var getReq models.AdminUserGetRequest
+	err := c.ShouldBindQuery(&getReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[admin_users.AdminUserGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	user, errResp := a.getUser(c, getReq.Id, "AdminUserGetHandler")
+
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	a.recordAdminAction(c, user, "get user", false)
+
+	return user.ToAdminUserInfoResponse(), nil
+}
+
+// AdminUserDataStatisticsHandler returns the data statistics of the specified user
+func (a *AdminUsersApi) AdminUserDataStatisticsHandler(c *core.Context) (any, *errs.Error) {
+	var getReq models.AdminUserGetRequest
+	err := c.ShouldBindQuery(&getReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[admin_users.AdminUserDataStatisticsHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	user, errResp := a.getUser(c, getReq.Id, "AdminUserDataStatisticsHandler")
+
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	totalAccountCount, err := a.accounts.GetTotalAccountCountByUid(c, user.Uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[admin_users.AdminUserDataStatisticsHandler] failed to get total account count for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	totalTransactionCategoryCount, err := a.categories.GetTotalCategoryCountByUid(c, user.Uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[admin_users.AdminUserDataStatisticsHandler] failed to get total transaction category count for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	totalTransactionTagCount, err := a.tags.GetTotalTagCountByUid(c, user.Uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[admin_users.AdminUserDataStatisticsHandler] failed to get total transaction tag count for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	totalTransactionCount, err := a.transactions.GetTotalTransactionCountByUid(c, user.Uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[admin_users.AdminUserDataStatisticsHandler] failed to get total transaction count for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	a.recordAdminAction(c, user, "get data statistics of user", false)
+
+	return &models.DataStatisticsResponse{
+		TotalAccountCount:             totalAccountCount,
+		TotalTransactionCategoryCount: totalTransactionCategoryCount,
+		TotalTransactionTagCount:      totalTransactionTagCount,
+		TotalTransactionCount:         totalTransactionCount,
+	}, nil
+}
+
+// AdminUserEnableHandler sets the specified user enabled
+func (a *AdminUsersApi) AdminUserEnableHandler(c *core.Context) (any, *errs.Error) {
+	user, errResp := a.getOperatedUser(c, "AdminUserEnableHandler")
+
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	err := a.users.EnableUser(c, user.Username)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[admin_users.AdminUserEnableHandler] failed to set user \"uid:%d\" enabled, because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[admin_users.AdminUserEnableHandler] user \"uid:%d\" has been enabled by administrator \"uid:%d\"", user.Uid, c.GetCurrentUid())
+	a.recordAdminAction(c, user, "enable user", true)
+
+	return true, nil
+}
+
+// AdminUserDisableHandler sets the specified user disabled
+func (a *AdminUsersApi) AdminUserDisableHandler(c *core.Context) (any, *errs.Error) {
+	user, errResp := a.getOperatedUser(c, "AdminUserDisableHandler")
+
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	if user.Uid == c.GetCurrentUid() {
+		return nil, errs.ErrCannotOperateCurrentUserByAdmin
+	}
+
+	err := a.users.DisableUser(c, user.Username)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[admin_users.AdminUserDisableHandler] failed to set user \"uid:%d\" disabled, because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[admin_users.AdminUserDisableHandler] user \"uid:%d\" has been disabled by administrator \"uid:%d\"", user.Uid, c.GetCurrentUid())
+	a.recordAdminAction(c, user, "disable user", true)
+
+	return true, nil
+}
+
+// AdminUserSetEmailVerifiedHandler sets the email address of the specified user verified
+func (a *AdminUsersApi) AdminUserSetEmailVerifiedHandler(c *core.Context) (any, *errs.Error) {
+	user, errResp := a.getOperatedUser(c, "AdminUserSetEmailVerifiedHandler")
+
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	if user.EmailVerified {
+		return nil, errs.ErrEmailIsVerified
+	}
+
+	err := a.users.SetUserEmailVerified(c, user.Username)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[admin_users.AdminUserSetEmailVerifiedHandler] failed to set user \"uid:%d\" email address verified, because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[admin_users.AdminUserSetEmailVerifiedHandler] user \"uid:%d\" email address has been set verified by administrator \"uid:%d\"", user.Uid, c.GetCurrentUid())
+	a.recordAdminAction(c, user, "set email verified of user", true)
+
+	return true, nil
+}
+
+// AdminUserDisableTwoFactorHandler disables 2fa and removes all webauthn credentials for the specified user
+func (a *AdminUsersApi) AdminUserDisableTwoFactorHandler(c *core.Context) (any, *errs.Error) {
+	user, errResp := a.getOperatedUser(c, "AdminUserDisableTwoFactorHandler")
+
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	enableTwoFactor, err := a.twoFactorAuthorizations.ExistsTwoFactorSetting(c, user.Uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[admin_users.AdminUserDisableTwoFactorHandler] failed to check two-factor setting for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	existsWebAuthnCredential, err := a.userWebAuthnCredentials.ExistsCredential(c, user.Uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[admin_users.AdminUserDisableTwoFactorHandler] failed to check webauthn credentials for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if !enableTwoFactor && !existsWebAuthnCredential {
+		return nil, errs.ErrTwoFactorIsNotEnabled
+	}
+
+	if enableTwoFactor {
+		err = a.twoFactorAuthorizations.DeleteTwoFactorRecoveryCodes(c, user.Uid)
+
+		if err != nil {
+			log.ErrorfWithRequestId(c, "[admin_users.AdminUserDisableTwoFactorHandler] failed to delete two-factor recovery codes for user \"uid:%d\", because %s", user.Uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		err = a.twoFactorAuthorizations.DeleteTwoFactorSetting(c, user.Uid)
+
+		if err != nil {
+			log.ErrorfWithRequestId(c, "[admin_users.AdminUserDisableTwoFactorHandler] failed to delete two-factor setting for user \"uid:%d\", because %s", user.Uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
+	if existsWebAuthnCredential {
+		err = a.userWebAuthnCredentials.DeleteAllCredentials(c, user.Uid)
+
+		if err != nil {
+			log.ErrorfWithRequestId(c, "[admin_users.AdminUserDisableTwoFactorHandler] failed to delete webauthn credentials for user \"uid:%d\", because %s", user.Uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
+	log.InfofWithRequestId(c, "[admin_users.AdminUserDisableTwoFactorHandler] two-factor authorization of user \"uid:%d\" has been disabled by administrator \"uid:%d\"", user.Uid, c.GetCurrentUid())
+	a.recordAdminAction(c, user, "disable two-factor authorization of user", true)
+
+	return true, nil
+}
+
+// AdminUserClearSessionsHandler revokes all tokens of the specified user
+func (a *AdminUsersApi) AdminUserClearSessionsHandler(c *core.Context) (any, *errs.Error) {
+	user, errResp := a.getOperatedUser(c, "AdminUserClearSessionsHandler")
+
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	if user.Uid == c.GetCurrentUid() {
+		return nil, errs.ErrCannotOperateCurrentUserByAdmin
+	}
+
+	now := time.Now().Unix()
+	err := a.tokens.DeleteTokensBeforeTime(c, user.Uid, now)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[admin_users.AdminUserClearSessionsHandler] failed to revoke tokens of user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[admin_users.AdminUserClearSessionsHandler] tokens of user \"uid:%d\" have been revoked by administrator \"uid:%d\"", user.Uid, c.GetCurrentUid())
+	a.recordAdminAction(c, user, "clear sessions of user", true)
+
+	return true, nil
+}
+
+// AdminUserUnlockHandler clears the failed login attempts and the lockout of the specified user
+func (a *AdminUsersApi) AdminUserUnlockHandler(c *core.Context) (any, *errs.Error) {
+	user, errResp := a.getOperatedUser(c, "AdminUserUnlockHandler")
+
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	deleted, err := a.loginAttempts.ClearFailedAttempts(c, models.GetLoginAttemptKeyByUid(user.Uid))
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[admin_users.AdminUserUnlockHandler] failed to clear failed login attempts of user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if !deleted {
+		return nil, errs.ErrUserIsNotLocked
+	}
+
+	log.InfofWithRequestId(c, "[admin_users.AdminUserUnlockHandler] user \"uid:%d\" has been unlocked by administrator \"uid:%d\"", user.Uid, c.GetCurrentUid())
+	a.recordAdminAction(c, user, "unlock user", true)
+
+	return true, nil
+}
+
+// AdminUserSendPasswordResetMailHandler sends an email with password reset link to the specified user
+func (a *AdminUsersApi) AdminUserSendPasswordResetMailHandler(c *core.Context) (any, *errs.Error) {
+	if !settings.Container.Current.EnableSMTP {
+		return nil, errs.ErrSMTPServerNotEnabled
+	}
+
+	user, errResp := a.getOperatedUser(c, "AdminUserSendPasswordResetMailHandler")
+
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	if settings.Container.Current.ForgetPasswordRequireVerifyEmail && !user.EmailVerified {
+		log.WarnfWithRequestId(c, "[admin_users.AdminUserSendPasswordResetMailHandler] user \"uid:%d\" has not verified email", user.Uid)
+		return nil, errs.ErrEmailIsNotVerified
+	}
+
+	token, _, err := a.tokens.CreatePasswordResetToken(c, user)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[admin_users.AdminUserSendPasswordResetMailHandler] failed to create token for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.ErrTokenGenerating
+	}
+
+	err = a.forgetPasswords.SendPasswordResetEmail(c, user, token, c.GetClientLocale())
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[admin_users.AdminUserSendPasswordResetMailHandler] cannot send email to \"%s\", because %s", user.Email, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[admin_users.AdminUserSendPasswordResetMailHandler] password reset mail of user \"uid:%d\" has been sent by administrator \"uid:%d\"", user.Uid, c.GetCurrentUid())
+	a.recordAdminAction(c, user, "send password reset mail to user", true)
+
+	return true, nil
+}
+
+// AdminUserTransactionCheckHandler checks whether all transactions and all accounts of the specified user are correct
+func (a *AdminUsersApi) AdminUserTransactionCheckHandler(c *core.Context) (any, *errs.Error) {
+	var getReq models.AdminUserGetRequest
+	err := c.ShouldBindQuery(&getReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[admin_users.AdminUserTransactionCheckHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	user, errResp := a.getUser(c, getReq.Id, "AdminUserTransactionCheckHandler")
+
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	a.recordAdminAction(c, user, "check transactions of user", false)
+
+	passed, err := a.userDataChecks.CheckTransactionAndAccount(c, user.Uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[admin_users.AdminUserTransactionCheckHandler] transactions and accounts of user \"uid:%d\" are not correct, because %s", user.Uid, err.Error())
+
+		return &models.AdminUserTransactionCheckResponse{
+			Passed:  false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	return &models.AdminUserTransactionCheckResponse{
+		Passed: passed,
+	}, nil
+}
+
+func (a *AdminUsersApi) getOperatedUser(c *core.Context, handlerName string) (*models.User, *errs.Error) {
+	var operateReq models.AdminUserOperateRequest
+	err := c.ShouldBindJSON(&operateReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[admin_users.%s] parse request failed, because %s", handlerName, err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	return a.getUser(c, operateReq.Id, handlerName)
+}
+
+func (a *AdminUsersApi) getUser(c *core.Context, uid int64, handlerName string) (*models.User, *errs.Error) {
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.ErrorfWithRequestId(c, "[admin_users.%s] failed to get user \"uid:%d\", because %s", handlerName, uid, err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+// recordAdminAction records the action in the audit log of current administrator, and also in the audit log of
+// the operated user if the action has modified the user
+func (a *AdminUsersApi) recordAdminAction(c *core.Context, user *models.User, action string, modified bool) {
+	adminUid := c.GetCurrentUid()
+
+	recordUserAuditEvent(c, adminUid, models.USER_AUDIT_EVENT_TYPE_ADMIN_ACTION, fmt.Sprintf("%s \"%s\" (uid:%d)", action, user.Username, user.Uid))
+
+	if modified {
+		recordUserAuditEvent(c, user.Uid, models.USER_AUDIT_EVENT_TYPE_MODIFIED_BY_ADMIN, fmt.Sprintf("%s by administrator \"uid:%d\"", action, adminUid))
+	}
+}
@@ -1,14 +1,44 @@
 package api
 
 import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/pquerna/otp/totp"
 
 	"github.com/kyy-me/ezbookkeeping/pkg/core"
 	"github.com/kyy-me/ezbookkeeping/pkg/errs"
 	"github.com/kyy-me/ezbookkeeping/pkg/log"
 	"github.com/kyy-me/ezbookkeeping/pkg/models"
+	"github.com/kyy-me/ezbookkeeping/pkg/oidc"
 	"github.com/kyy-me/ezbookkeeping/pkg/services"
 	"github.com/kyy-me/ezbookkeeping/pkg/settings"
+	"github.com/kyy-me/ezbookkeeping/pkg/utils"
+	"github.com/kyy-me/ezbookkeeping/pkg/validators"
+	"github.com/kyy-me/ezbookkeeping/pkg/webauthn"
+)
+
+const (
+	oidcAuthorizationStateCookieName = "ebk_oidc_state"
+	oidcAuthorizationStateCookiePath = "/api/oidc"
+	oidcRandomUsernameMaxRetryTimes  = 3
+
+	externalReauthenticationMaxAge = 5 * time.Minute
+)
+
+// Login methods which are recorded in the details of login audit events
+const (
+	loginMethodPassword              = "password"
+	loginMethodOIDC                  = "oidc"
+	loginMethodWebAuthn              = "webauthn"
+	loginMethodTwoFactorPasscode     = "2fa_passcode"
+	loginMethodTwoFactorRecoveryCode = "2fa_recovery_code"
+	loginMethodTwoFactorWebAuthn     = "2fa_webauthn"
 )
 
 // AuthorizationsApi represents authorization api
@@ -16,6 +46,9 @@ type AuthorizationsApi struct {
 	users                   *services.UserService
 	tokens                  *services.TokenService
 	twoFactorAuthorizations *services.TwoFactorAuthorizationService
+	userExternalAuths       *services.UserExternalAuthService
+	userWebAuthnCredentials *services.UserWebAuthnCredentialService
+	loginAttempts           *services.LoginAttemptService
 }
 
 // Initialize a authorization api singleton instance
@@ -24,6 +57,9 @@ var (
 		users:                   services.Users,
 		tokens:                  services.Tokens,
 		twoFactorAuthorizations: services.TwoFactorAuthorizations,
+		userExternalAuths:       services.UserExternalAuths,
+		userWebAuthnCredentials: services.UserWebAuthnCredentials,
+		loginAttempts:           services.LoginAttempts,
 	}
 )
 
@@ -37,75 +73,42 @@ func (a *AuthorizationsApi) AuthorizeHandler(c *core.Context) (any, *errs.Error)
 		return nil, errs.ErrLoginNameOrPasswordInvalid
 	}
 
-	user, err := a.users.GetUserByUsernameOrEmailAndPassword(c, credential.LoginName, credential.Password)
-
-	if err != nil {
-		log.WarnfWithRequestId(c, "[authorizations.AuthorizeHandler] login failed for user \"%s\", because %s", credential.LoginName, err.Error())
-		return nil, errs.ErrLoginNameOrPasswordWrong
-	}
+	// the account of login name is only used to check and record failed login attempts,
+	// the login name which does not belong to any user is also tracked to avoid leaking whether the account exists
+	var loginUid int64
+	loginUser, err := a.users.GetUserByUsernameOrEmail(c, credential.LoginName)
 
-	if user.Disabled {
-		log.WarnfWithRequestId(c, "[authorizations.AuthorizeHandler] login failed for user \"%s\", because user is disabled", credential.LoginName)
-		return nil, errs.ErrUserIsDisabled
+	if err == nil {
+		loginUid = loginUser.Uid
 	}
 
-	if settings.Container.Current.EnableUserForceVerifyEmail && !user.EmailVerified {
-		hasValidEmailVerifyToken, err := a.tokens.ExistsValidTokenByType(c, user.Uid, core.USER_TOKEN_TYPE_EMAIL_VERIFY)
-
-		if err != nil {
-			log.WarnfWithRequestId(c, "[authorizations.AuthorizeHandler] failed check whether user \"uid:%d\" has valid verify email token, because %s", user.Uid, err.Error())
-			hasValidEmailVerifyToken = false
-		}
-
-		log.WarnfWithRequestId(c, "[authorizations.AuthorizeHandler] login failed for user \"%s\", because user has not verified email", credential.LoginName)
+	var errResult *errs.Error
 
-		return nil, errs.NewErrorWithContext(errs.ErrEmailIsNotVerified, map[string]any{
-			"email":                    user.Email,
-			"hasValidEmailVerifyToken": hasValidEmailVerifyToken,
-		})
+	if loginUid > 0 {
+		errResult = a.checkLoginAttempts(c, loginUid)
+	} else {
+		errResult = a.checkLoginAttemptsOfLoginName(c, credential.LoginName)
 	}
 
-	err = a.users.UpdateUserLastLoginTime(c, user.Uid)
-
-	if err != nil {
-		log.WarnfWithRequestId(c, "[authorizations.AuthorizeHandler] failed to update last login time for user \"uid:%d\", because %s", user.Uid, err.Error())
+	if errResult != nil {
+		return nil, errResult
 	}
 
-	twoFactorEnable := a.tokens.CurrentConfig().EnableTwoFactor
+	user, err := a.users.GetUserByUsernameOrEmailAndPassword(c, credential.LoginName, credential.Password)
 
-	if twoFactorEnable {
-		twoFactorEnable, err = a.twoFactorAuthorizations.ExistsTwoFactorSetting(c, user.Uid)
+	if err != nil {
+		log.WarnfWithRequestId(c, "[authorizations.AuthorizeHandler] login failed for user \"%s\", because %s", credential.LoginName, err.Error())
 
-		if err != nil {
-			log.ErrorfWithRequestId(c, "[authorizations.AuthorizeHandler] failed to check two-factor setting for user \"uid:%d\", because %s", user.Uid, err.Error())
-			return nil, errs.Or(err, errs.ErrSystemError)
+		if loginUid > 0 {
+			a.recordFailedLoginAttempt(c, loginUid, loginMethodPassword)
+		} else {
+			a.recordFailedLoginAttemptOfLoginName(c, credential.LoginName)
 		}
-	}
-
-	var token string
-	var claims *core.UserTokenClaims
-
-	if twoFactorEnable {
-		token, claims, err = a.tokens.CreateRequire2FAToken(c, user)
-	} else {
-		token, claims, err = a.tokens.CreateToken(c, user)
-	}
-
-	if err != nil {
-		log.ErrorfWithRequestId(c, "[authorizations.AuthorizeHandler] failed to create token for user \"uid:%d\", because %s", user.Uid, err.Error())
-		return nil, errs.ErrTokenGenerating
-	}
 
-	if !twoFactorEnable {
-		c.SetTextualToken(token)
+		return nil, errs.ErrLoginNameOrPasswordWrong
 	}
 
-	c.SetTokenClaims(claims)
-
-	log.InfofWithRequestId(c, "[authorizations.AuthorizeHandler] user \"uid:%d\" has logined, token type is %d, token will be expired at %d", user.Uid, claims.Type, claims.ExpiresAt)
-
-	authResp := a.getAuthResponse(token, twoFactorEnable, user)
-	return authResp, nil
+	return a.authorizeUser(c, user, false, loginMethodPassword)
 }
 
 // TwoFactorAuthorizeHandler verifies and authorizes current 2fa login by passcode
@@ -119,6 +122,12 @@ func (a *AuthorizationsApi) TwoFactorAuthorizeHandler(c *core.Context) (any, *er
 	}
 
 	uid := c.GetCurrentUid()
+	errResult := a.checkLoginAttempts(c, uid)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	twoFactorSetting, err := a.twoFactorAuthorizations.GetUserTwoFactorSettingByUid(c, uid)
 
 	if err != nil {
@@ -128,6 +137,7 @@ func (a *AuthorizationsApi) TwoFactorAuthorizeHandler(c *core.Context) (any, *er
 
 	if !totp.Validate(credential.Passcode, twoFactorSetting.Secret) {
 		log.WarnfWithRequestId(c, "[authorizations.TwoFactorAuthorizeHandler] passcode is invalid for user \"uid:%d\"", uid)
+		a.recordFailedLoginAttempt(c, uid, loginMethodTwoFactorPasscode)
 		return nil, errs.ErrPasscodeInvalid
 	}
 
@@ -148,6 +158,9 @@ func (a *AuthorizationsApi) TwoFactorAuthorizeHandler(c *core.Context) (any, *er
 		return nil, errs.ErrEmailIsNotVerified
 	}
 
+	a.clearFailedLoginAttempts(c, user.Uid)
+	recordUserAuditEvent(c, user.Uid, models.USER_AUDIT_EVENT_TYPE_LOGIN, loginMethodTwoFactorPasscode)
+
 	oldTokenClaims := c.GetTokenClaims()
 	err = a.tokens.DeleteTokenByClaims(c, oldTokenClaims)
 
@@ -182,6 +195,12 @@ func (a *AuthorizationsApi) TwoFactorAuthorizeByRecoveryCodeHandler(c *core.Cont
 	}
 
 	uid := c.GetCurrentUid()
+	errResult := a.checkLoginAttempts(c, uid)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	enableTwoFactor, err := a.twoFactorAuthorizations.ExistsTwoFactorSetting(c, uid)
 
 	if err != nil {
@@ -214,9 +233,13 @@ func (a *AuthorizationsApi) TwoFactorAuthorizeByRecoveryCodeHandler(c *core.Cont
 
 	if err != nil {
 		log.WarnfWithRequestId(c, "[authorizations.TwoFactorAuthorizeByRecoveryCodeHandler] failed to get two-factor recovery code for user \"uid:%d\", because %s", uid, err.Error())
+		a.recordFailedLoginAttempt(c, uid, loginMethodTwoFactorRecoveryCode)
 		return nil, errs.Or(err, errs.ErrTwoFactorRecoveryCodeNotExist)
 	}
 
+	a.clearFailedLoginAttempts(c, user.Uid)
+	recordUserAuditEvent(c, user.Uid, models.USER_AUDIT_EVENT_TYPE_LOGIN, loginMethodTwoFactorRecoveryCode)
+
 	oldTokenClaims := c.GetTokenClaims()
 	err = a.tokens.DeleteTokenByClaims(c, oldTokenClaims)
 
@@ -240,6 +263,656 @@ func (a *AuthorizationsApi) TwoFactorAuthorizeByRecoveryCodeHandler(c *core.Cont
 	return authResp, nil
 }
 
+// TwoFactorWebAuthnRequestHandler returns the credential request options for current 2fa login by webauthn credential
+func (a *AuthorizationsApi) TwoFactorWebAuthnRequestHandler(c *core.Context) (any, *errs.Error) {
+	relyingParty := webauthn.Container.Current
+
+	if relyingParty == nil {
+		return nil, errs.ErrWebAuthnNotEnabled
+	}
+
+	uid := c.GetCurrentUid()
+	credentials, err := a.userWebAuthnCredentials.GetAllCredentialsByUid(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[authorizations.TwoFactorWebAuthnRequestHandler] failed to get webauthn credentials for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrSystemError)
+	}
+
+	if len(credentials) < 1 {
+		return nil, errs.ErrWebAuthnCredentialNotFound
+	}
+
+	options, session, err := relyingParty.BeginLogin(strconv.FormatInt(uid, 10), getWebAuthnCredentialDescriptors(credentials), false)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[authorizations.TwoFactorWebAuthnRequestHandler] failed to begin login for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrSystemError
+	}
+
+	return a.getWebAuthnLoginRequestResponse(c, options, session)
+}
+
+// TwoFactorWebAuthnAuthorizeHandler verifies and authorizes current 2fa login by webauthn credential
+func (a *AuthorizationsApi) TwoFactorWebAuthnAuthorizeHandler(c *core.Context) (any, *errs.Error) {
+	var loginReq models.WebAuthnLoginRequest
+	err := c.ShouldBindJSON(&loginReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[authorizations.TwoFactorWebAuthnAuthorizeHandler] parse request failed, because %s", err.Error())
+		return nil, errs.ErrWebAuthnCredentialInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	errResult := a.checkLoginAttempts(c, uid)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	credential, errResult := a.verifyWebAuthnCredentialAssertion(c, &loginReq, uid)
+
+	if errResult != nil {
+		a.recordFailedLoginAttempt(c, uid, loginMethodTwoFactorWebAuthn)
+		return nil, errResult
+	}
+
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[authorizations.TwoFactorWebAuthnAuthorizeHandler] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
+		return nil, errs.ErrUserNotFound
+	}
+
+	if user.Disabled {
+		log.WarnfWithRequestId(c, "[authorizations.TwoFactorWebAuthnAuthorizeHandler] user \"uid:%d\" is disabled", user.Uid)
+		return nil, errs.ErrUserIsDisabled
+	}
+
+	if settings.Container.Current.EnableUserForceVerifyEmail && !user.EmailVerified {
+		log.WarnfWithRequestId(c, "[authorizations.TwoFactorWebAuthnAuthorizeHandler] user \"uid:%d\" has not verified email", user.Uid)
+		return nil, errs.ErrEmailIsNotVerified
+	}
+
+	a.clearFailedLoginAttempts(c, user.Uid)
+	recordUserAuditEvent(c, user.Uid, models.USER_AUDIT_EVENT_TYPE_LOGIN, loginMethodTwoFactorWebAuthn)
+
+	oldTokenClaims := c.GetTokenClaims()
+	err = a.tokens.DeleteTokenByClaims(c, oldTokenClaims)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[authorizations.TwoFactorWebAuthnAuthorizeHandler] failed to revoke temporary token \"utid:%s\" for user \"uid:%d\", because %s", oldTokenClaims.UserTokenId, user.Uid, err.Error())
+	}
+
+	token, claims, err := a.tokens.CreateToken(c, user)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[authorizations.TwoFactorWebAuthnAuthorizeHandler] failed to create token for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.ErrTokenGenerating
+	}
+
+	c.SetTextualToken(token)
+	c.SetTokenClaims(claims)
+
+	log.InfofWithRequestId(c, "[authorizations.TwoFactorWebAuthnAuthorizeHandler] user \"uid:%d\" has authorized two-factor via webauthn credential \"%s\", token will be expired at %d", user.Uid, credential.CredentialId, claims.ExpiresAt)
+
+	authResp := a.getAuthResponse(token, false, user)
+	return authResp, nil
+}
+
+// WebAuthnLoginRequestHandler returns the credential request options for passwordless login by discoverable webauthn credential
+func (a *AuthorizationsApi) WebAuthnLoginRequestHandler(c *core.Context) (any, *errs.Error) {
+	relyingParty := webauthn.Container.Current
+
+	if relyingParty == nil {
+		return nil, errs.ErrWebAuthnNotEnabled
+	}
+
+	if !settings.Container.Current.EnableWebAuthnPasswordlessLogin {
+		return nil, errs.ErrWebAuthnPasswordlessLoginDisabled
+	}
+
+	// the user is not identified yet, so the authenticator would let user choose a discoverable credential
+	options, session, err := relyingParty.BeginLogin("", nil, true)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[authorizations.WebAuthnLoginRequestHandler] failed to begin login, because %s", err.Error())
+		return nil, errs.ErrSystemError
+	}
+
+	return a.getWebAuthnLoginRequestResponse(c, options, session)
+}
+
+// WebAuthnLoginAuthorizeHandler verifies the assertion of discoverable webauthn credential and authorizes the owner of credential,
+// the second factor is not required as the authenticator has verified the user
+func (a *AuthorizationsApi) WebAuthnLoginAuthorizeHandler(c *core.Context) (any, *errs.Error) {
+	if !settings.Container.Current.EnableWebAuthnPasswordlessLogin {
+		return nil, errs.ErrWebAuthnPasswordlessLoginDisabled
+	}
+
+	var loginReq models.WebAuthnLoginRequest
+	err := c.ShouldBindJSON(&loginReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[authorizations.WebAuthnLoginAuthorizeHandler] parse request failed, because %s", err.Error())
+		return nil, errs.ErrWebAuthnCredentialInvalid
+	}
+
+	errResult := a.checkLoginAttempts(c, 0)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	credential, errResult := a.verifyWebAuthnCredentialAssertion(c, &loginReq, 0)
+
+	if errResult != nil {
+		a.recordFailedLoginAttempt(c, 0, loginMethodWebAuthn)
+		return nil, errResult
+	}
+
+	user, err := a.users.GetUserById(c, credential.Uid)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[authorizations.WebAuthnLoginAuthorizeHandler] failed to get user \"uid:%d\" of webauthn credential, because %s", credential.Uid, err.Error())
+		return nil, errs.ErrWebAuthnCredentialInvalid
+	}
+
+	log.InfofWithRequestId(c, "[authorizations.WebAuthnLoginAuthorizeHandler] user \"uid:%d\" is authorized via webauthn credential \"%s\"", user.Uid, credential.CredentialId)
+
+	return a.authorizeUser(c, user, true, loginMethodWebAuthn)
+}
+
+// OIDCAuthorizeUrlHandler returns the authorization url of OpenID Connect provider and saves the authorization state to cookie
+func (a *AuthorizationsApi) OIDCAuthorizeUrlHandler(c *core.Context) (any, *errs.Error) {
+	provider := oidc.Container.Current
+
+	if provider == nil {
+		return nil, errs.ErrOIDCNotEnabled
+	}
+
+	state, err := oidc.NewAuthorizationState()
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[authorizations.OIDCAuthorizeUrlHandler] failed to generate authorization state, because %s", err.Error())
+		return nil, errs.ErrSystemError
+	}
+
+	encodedState, err := state.Encode(settings.Container.Current.SecretKey, time.Now())
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[authorizations.OIDCAuthorizeUrlHandler] failed to encode authorization state, because %s", err.Error())
+		return nil, errs.ErrSystemError
+	}
+
+	authorizeUrl, err := provider.GetAuthorizationUrl(c, state)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[authorizations.OIDCAuthorizeUrlHandler] failed to get authorization url, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOIDCProviderRequestFailed)
+	}
+
+	a.setOIDCAuthorizationStateCookie(c, encodedState, int(oidc.AuthorizationStateValidDuration/time.Second))
+
+	return &models.OIDCAuthorizeUrlResponse{
+		ProviderName: settings.Container.Current.OIDCProviderName,
+		AuthorizeUrl: authorizeUrl,
+	}, nil
+}
+
+// OIDCAuthorizeHandler verifies the authorization code which is returned by OpenID Connect provider and authorizes the linked user
+func (a *AuthorizationsApi) OIDCAuthorizeHandler(c *core.Context) (any, *errs.Error) {
+	provider := oidc.Container.Current
+
+	if provider == nil {
+		return nil, errs.ErrOIDCNotEnabled
+	}
+
+	var oidcAuthorizeReq models.OIDCAuthorizeRequest
+	err := c.ShouldBindJSON(&oidcAuthorizeReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[authorizations.OIDCAuthorizeHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	encodedState, err := c.Cookie(oidcAuthorizationStateCookieName)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[authorizations.OIDCAuthorizeHandler] authorization state cookie does not exist")
+		return nil, errs.ErrOIDCStateInvalid
+	}
+
+	// the authorization state can only be used once
+	a.setOIDCAuthorizationStateCookie(c, "", -1)
+
+	state, err := oidc.ParseAuthorizationState(encodedState, settings.Container.Current.SecretKey)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[authorizations.OIDCAuthorizeHandler] failed to parse authorization state, because %s", err.Error())
+		return nil, errs.ErrOIDCStateInvalid
+	}
+
+	if subtle.ConstantTimeCompare([]byte(state.State), []byte(oidcAuthorizeReq.State)) != 1 {
+		log.WarnfWithRequestId(c, "[authorizations.OIDCAuthorizeHandler] state in request does not match the authorization state")
+		return nil, errs.ErrOIDCStateInvalid
+	}
+
+	claims, err := provider.Authorize(c, oidcAuthorizeReq.Code, state)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[authorizations.OIDCAuthorizeHandler] failed to authorize by openid connect provider, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOIDCIdTokenInvalid)
+	}
+
+	user, err := a.getUserByOIDCClaims(c, claims)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[authorizations.OIDCAuthorizeHandler] failed to get user of external user \"%s\", because %s", claims.Subject, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return a.authorizeUser(c, user, false, loginMethodOIDC)
+}
+
+// authorizeUser creates a new token for user, the token would be a temporary token which requires 2fa
+// if user has set any second factor and the user has not been verified by multiple factors
+func (a *AuthorizationsApi) authorizeUser(c *core.Context, user *models.User, twoFactorVerified bool, loginMethod string) (any, *errs.Error) {
+	if user.Disabled {
+		log.WarnfWithRequestId(c, "[authorizations.authorizeUser] login failed for user \"uid:%d\", because user is disabled", user.Uid)
+		return nil, errs.ErrUserIsDisabled
+	}
+
+	if settings.Container.Current.EnableUserForceVerifyEmail && !user.EmailVerified {
+		hasValidEmailVerifyToken, err := a.tokens.ExistsValidTokenByType(c, user.Uid, core.USER_TOKEN_TYPE_EMAIL_VERIFY)
+
+		if err != nil {
+			log.WarnfWithRequestId(c, "[authorizations.authorizeUser] failed check whether user \"uid:%d\" has valid verify email token, because %s", user.Uid, err.Error())
+			hasValidEmailVerifyToken = false
+		}
+
+		log.WarnfWithRequestId(c, "[authorizations.authorizeUser] login failed for user \"uid:%d\", because user has not verified email", user.Uid)
+
+		return nil, errs.NewErrorWithContext(errs.ErrEmailIsNotVerified, map[string]any{
+			"email":                    user.Email,
+			"hasValidEmailVerifyToken": hasValidEmailVerifyToken,
+		})
+	}
+
+	err := a.users.UpdateUserLastLoginTime(c, user.Uid)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[authorizations.authorizeUser] failed to update last login time for user \"uid:%d\", because %s", user.Uid, err.Error())
+	}
+
+	var twoFactorMethods []string
+
+	if !twoFactorVerified {
+		twoFactorMethods, err = a.getTwoFactorMethods(c, user.Uid)
+
+		if err != nil {
+			log.ErrorfWithRequestId(c, "[authorizations.authorizeUser] failed to check two-factor setting for user \"uid:%d\", because %s", user.Uid, err.Error())
+			return nil, errs.Or(err, errs.ErrSystemError)
+		}
+	}
+
+	twoFactorEnable := len(twoFactorMethods) > 0
+
+	var token string
+	var claims *core.UserTokenClaims
+
+	if twoFactorEnable {
+		token, claims, err = a.tokens.CreateRequire2FAToken(c, user)
+	} else {
+		token, claims, err = a.tokens.CreateToken(c, user)
+	}
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[authorizations.authorizeUser] failed to create token for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.ErrTokenGenerating
+	}
+
+	if !twoFactorEnable {
+		c.SetTextualToken(token)
+		a.clearFailedLoginAttempts(c, user.Uid)
+		recordUserAuditEvent(c, user.Uid, models.USER_AUDIT_EVENT_TYPE_LOGIN, loginMethod)
+	}
+
+	c.SetTokenClaims(claims)
+
+	log.InfofWithRequestId(c, "[authorizations.authorizeUser] user \"uid:%d\" has logined, token type is %d, token will be expired at %d", user.Uid, claims.Type, claims.ExpiresAt)
+
+	authResp := a.getAuthResponse(token, twoFactorEnable, user)
+	authResp.TwoFactorMethods = twoFactorMethods
+
+	return authResp, nil
+}
+
+// checkLoginAttempts returns an error if the client ip address or the account is blocked because of too many failed login attempts
+func (a *AuthorizationsApi) checkLoginAttempts(c *core.Context, uid int64) *errs.Error {
+	attemptKeys := []string{models.GetLoginAttemptKeyByIp(c.ClientIP())}
+
+	if uid > 0 {
+		attemptKeys = append(attemptKeys, models.GetLoginAttemptKeyByUid(uid))
+	}
+
+	return a.checkLoginAttemptKeys(c, attemptKeys)
+}
+
+// checkLoginAttemptsOfLoginName returns an error if the client ip address or the login name which does not belong to any user
+// is blocked, so that the response of unknown login name is the same as the response of existed account
+func (a *AuthorizationsApi) checkLoginAttemptsOfLoginName(c *core.Context, loginName string) *errs.Error {
+	return a.checkLoginAttemptKeys(c, []string{
+		models.GetLoginAttemptKeyByIp(c.ClientIP()),
+		models.GetLoginAttemptKeyByLoginName(loginName),
+	})
+}
+
+func (a *AuthorizationsApi) checkLoginAttemptKeys(c *core.Context, attemptKeys []string) *errs.Error {
+	for i := 0; i < len(attemptKeys); i++ {
+		remainingSeconds, err := a.loginAttempts.GetBlockedRemainingSeconds(c, attemptKeys[i])
+
+		if err != nil {
+			log.ErrorfWithRequestId(c, "[authorizations.checkLoginAttemptKeys] failed to get login attempts of \"%s\", because %s", attemptKeys[i], err.Error())
+			return errs.ErrSystemError
+		}
+
+		if remainingSeconds > 0 {
+			log.WarnfWithRequestId(c, "[authorizations.checkLoginAttemptKeys] login of \"%s\" is blocked for %d seconds", attemptKeys[i], remainingSeconds)
+			c.Header("Retry-After", strconv.FormatInt(remainingSeconds, 10))
+
+			return errs.NewErrorWithContext(errs.ErrTooManyFailedLoginAttempts, map[string]any{
+				"retryAfter": remainingSeconds,
+			})
+		}
+	}
+
+	return nil
+}
+
+// recordFailedLoginAttempt records the failed login attempt of the client ip address and the account,
+// and notifies the user by email when the account is locked
+func (a *AuthorizationsApi) recordFailedLoginAttempt(c *core.Context, uid int64, loginMethod string) {
+	config := a.tokens.CurrentConfig()
+	clientIp := c.ClientIP()
+
+	_, _, err := a.loginAttempts.RecordFailedAttempt(c, models.GetLoginAttemptKeyByIp(clientIp), config.MaxFailedLoginAttemptsPerIp)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[authorizations.recordFailedLoginAttempt] failed to record failed login attempt of ip \"%s\", because %s", clientIp, err.Error())
+	}
+
+	if uid <= 0 {
+		return
+	}
+
+	recordUserAuditEvent(c, uid, models.USER_AUDIT_EVENT_TYPE_LOGIN_FAILED, loginMethod)
+
+	loginAttempt, lockoutDuration, err := a.loginAttempts.RecordFailedAttempt(c, models.GetLoginAttemptKeyByUid(uid), config.MaxFailedLoginAttempts)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[authorizations.recordFailedLoginAttempt] failed to record failed login attempt of user \"uid:%d\", because %s", uid, err.Error())
+		return
+	}
+
+	if lockoutDuration <= 0 {
+		return
+	}
+
+	log.WarnfWithRequestId(c, "[authorizations.recordFailedLoginAttempt] user \"uid:%d\" has been locked for %d seconds after %d failed login attempts", uid, int64(lockoutDuration/time.Second), loginAttempt.FailedCount)
+	recordUserAuditEvent(c, uid, models.USER_AUDIT_EVENT_TYPE_ACCOUNT_LOCKED, fmt.Sprintf("locked for %d seconds after %d failed login attempts", int64(lockoutDuration/time.Second), loginAttempt.FailedCount))
+
+	if !config.EnableSMTP {
+		return
+	}
+
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[authorizations.recordFailedLoginAttempt] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
+		return
+	}
+
+	locale := c.GetClientLocale()
+	requestId := c.GetRequestId()
+
+	go func() {
+		err := a.loginAttempts.SendAccountLockedEmail(user, loginAttempt, lockoutDuration, clientIp, locale)
+
+		if err != nil {
+			log.Warnf("[authorizations.recordFailedLoginAttempt] cannot send account locked email to \"%s\" in request \"%s\", because %s", user.Email, requestId, err.Error())
+		}
+	}()
+}
+
+// recordFailedLoginAttemptOfLoginName records the failed login attempt of the client ip address and the login name which does not belong to any user
+func (a *AuthorizationsApi) recordFailedLoginAttemptOfLoginName(c *core.Context, loginName string) {
+	a.recordFailedLoginAttempt(c, 0, loginMethodPassword)
+
+	_, _, err := a.loginAttempts.RecordFailedAttempt(c, models.GetLoginAttemptKeyByLoginName(loginName), a.tokens.CurrentConfig().MaxFailedLoginAttempts)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[authorizations.recordFailedLoginAttemptOfLoginName] failed to record failed login attempt of login name \"%s\", because %s", loginName, err.Error())
+	}
+}
+
+// clearFailedLoginAttempts resets the failed login attempts of the account and the client ip address after the user has passed all login factors
+func (a *AuthorizationsApi) clearFailedLoginAttempts(c *core.Context, uid int64) {
+	_, err := a.loginAttempts.ClearFailedAttempts(c, models.GetLoginAttemptKeyByUid(uid))
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[authorizations.clearFailedLoginAttempts] failed to clear failed login attempts of user \"uid:%d\", because %s", uid, err.Error())
+	}
+
+	clientIp := c.ClientIP()
+	_, err = a.loginAttempts.ClearFailedAttempts(c, models.GetLoginAttemptKeyByIp(clientIp))
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[authorizations.clearFailedLoginAttempts] failed to clear failed login attempts of ip \"%s\", because %s", clientIp, err.Error())
+	}
+}
+
+// reauthenticateUser verifies all login factors of current user again before a sensitive operation,
+// the first factor is the password which is verified by all user authenticators or a recent login via OpenID Connect provider,
+// and the second factor is the passcode or webauthn credential if user has set any
+func (a *AuthorizationsApi) reauthenticateUser(c *core.Context, user *models.User, password string, passcode string, webAuthnReq *models.WebAuthnLoginRequest) *errs.Error {
+	errResult := a.checkLoginAttempts(c, user.Uid)
+
+	if errResult != nil {
+		return errResult
+	}
+
+	if password != "" {
+		authenticatedUser, err := a.users.GetUserByUsernameOrEmailAndPassword(c, user.Username, password)
+
+		if err != nil || authenticatedUser.Uid != user.Uid {
+			log.WarnfWithRequestId(c, "[authorizations.reauthenticateUser] password is wrong for user \"uid:%d\"", user.Uid)
+			a.recordFailedLoginAttempt(c, user.Uid, loginMethodPassword)
+			return errs.ErrUserPasswordWrong
+		}
+	} else {
+		userExternalAuth, err := a.userExternalAuths.GetUserExternalAuthByUid(c, user.Uid, models.USER_EXTERNAL_AUTH_TYPE_OIDC)
+
+		if err != nil && err != errs.ErrExternalUserNotLinked {
+			log.ErrorfWithRequestId(c, "[authorizations.reauthenticateUser] failed to get external auth of user \"uid:%d\", because %s", user.Uid, err.Error())
+			return errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		if userExternalAuth == nil || time.Now().Unix()-userExternalAuth.LastLoginUnixTime > int64(externalReauthenticationMaxAge/time.Second) {
+			log.WarnfWithRequestId(c, "[authorizations.reauthenticateUser] user \"uid:%d\" has neither provided password nor logged in via external provider recently", user.Uid)
+			return errs.ErrUserReauthenticationRequired
+		}
+	}
+
+	twoFactorMethods, err := a.getTwoFactorMethods(c, user.Uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[authorizations.reauthenticateUser] failed to get two-factor methods for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if len(twoFactorMethods) > 0 {
+		if webAuthnReq != nil && slices.Contains(twoFactorMethods, models.TWO_FACTOR_METHOD_WEBAUTHN) {
+			_, errResult = a.verifyWebAuthnCredentialAssertion(c, webAuthnReq, user.Uid)
+
+			if errResult != nil {
+				a.recordFailedLoginAttempt(c, user.Uid, loginMethodTwoFactorWebAuthn)
+				return errResult
+			}
+		} else if slices.Contains(twoFactorMethods, models.TWO_FACTOR_METHOD_PASSCODE) {
+			twoFactorSetting, err := a.twoFactorAuthorizations.GetUserTwoFactorSettingByUid(c, user.Uid)
+
+			if err != nil {
+				log.ErrorfWithRequestId(c, "[authorizations.reauthenticateUser] failed to get two-factor setting for user \"uid:%d\", because %s", user.Uid, err.Error())
+				return errs.Or(err, errs.ErrOperationFailed)
+			}
+
+			if !totp.Validate(passcode, twoFactorSetting.Secret) {
+				log.WarnfWithRequestId(c, "[authorizations.reauthenticateUser] passcode is invalid for user \"uid:%d\"", user.Uid)
+				a.recordFailedLoginAttempt(c, user.Uid, loginMethodTwoFactorPasscode)
+				return errs.ErrPasscodeInvalid
+			}
+		} else {
+			log.WarnfWithRequestId(c, "[authorizations.reauthenticateUser] webauthn credential is not provided for user \"uid:%d\"", user.Uid)
+			a.recordFailedLoginAttempt(c, user.Uid, loginMethodTwoFactorWebAuthn)
+			return errs.ErrWebAuthnCredentialInvalid
+		}
+	}
+
+	a.clearFailedLoginAttempts(c, user.Uid)
+
+	return nil
+}
+
+func (a *AuthorizationsApi) getTwoFactorMethods(c *core.Context, uid int64) ([]string, error) {
+	config := a.tokens.CurrentConfig()
+
+	if !config.EnableTwoFactor {
+		return nil, nil
+	}
+
+	twoFactorMethods := make([]string, 0, 2)
+	totpEnabled, err := a.twoFactorAuthorizations.ExistsTwoFactorSetting(c, uid)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if totpEnabled {
+		twoFactorMethods = append(twoFactorMethods, models.TWO_FACTOR_METHOD_PASSCODE)
+	}
+
+	if config.EnableWebAuthn {
+		webAuthnEnabled, err := a.userWebAuthnCredentials.ExistsCredential(c, uid)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if webAuthnEnabled {
+			twoFactorMethods = append(twoFactorMethods, models.TWO_FACTOR_METHOD_WEBAUTHN)
+		}
+	}
+
+	return twoFactorMethods, nil
+}
+
+func (a *AuthorizationsApi) verifyWebAuthnCredentialAssertion(c *core.Context, loginReq *models.WebAuthnLoginRequest, uid int64) (*models.UserWebAuthnCredential, *errs.Error) {
+	relyingParty := webauthn.Container.Current
+
+	if relyingParty == nil {
+		return nil, errs.ErrWebAuthnNotEnabled
+	}
+
+	expectedUserId := ""
+
+	if uid > 0 {
+		expectedUserId = strconv.FormatInt(uid, 10)
+	}
+
+	session, err := webauthn.ParseSession(loginReq.Session, settings.Container.Current.SecretKey, webauthn.SESSION_TYPE_AUTHENTICATION)
+
+	if err != nil || session.UserId != expectedUserId {
+		log.WarnfWithRequestId(c, "[authorizations.verifyWebAuthnCredentialAssertion] webauthn session is invalid")
+		return nil, errs.ErrWebAuthnSessionInvalid
+	}
+
+	err = a.userWebAuthnCredentials.ConsumeChallenge(c, session.Challenge)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[authorizations.verifyWebAuthnCredentialAssertion] webauthn session has been used or expired, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrWebAuthnSessionInvalid)
+	}
+
+	credential, err := a.userWebAuthnCredentials.GetCredentialById(c, strings.TrimRight(loginReq.Credential.Id, "="))
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[authorizations.verifyWebAuthnCredentialAssertion] failed to get webauthn credential \"%s\", because %s", loginReq.Credential.Id, err.Error())
+		return nil, errs.ErrWebAuthnCredentialInvalid
+	}
+
+	if uid > 0 && credential.Uid != uid {
+		log.WarnfWithRequestId(c, "[authorizations.verifyWebAuthnCredentialAssertion] webauthn credential \"%s\" does not belong to user \"uid:%d\"", credential.CredentialId, uid)
+		return nil, errs.ErrWebAuthnCredentialInvalid
+	}
+
+	credentialId, err := webauthn.DecodeBase64Url(credential.CredentialId)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[authorizations.verifyWebAuthnCredentialAssertion] failed to decode id of webauthn credential \"%s\", because %s", credential.CredentialId, err.Error())
+		return nil, errs.ErrSystemError
+	}
+
+	publicKey, err := webauthn.DecodeBase64Url(credential.PublicKey)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[authorizations.verifyWebAuthnCredentialAssertion] failed to decode public key of webauthn credential \"%s\", because %s", credential.CredentialId, err.Error())
+		return nil, errs.ErrSystemError
+	}
+
+	signCount, err := relyingParty.FinishLogin(session, loginReq.Credential, &webauthn.Credential{
+		Id:        credentialId,
+		PublicKey: publicKey,
+		SignCount: uint32(credential.SignCount),
+	}, strconv.FormatInt(credential.Uid, 10))
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[authorizations.verifyWebAuthnCredentialAssertion] failed to verify assertion of webauthn credential \"%s\" for user \"uid:%d\", because %s", credential.CredentialId, credential.Uid, err.Error())
+		return nil, errs.ErrWebAuthnCredentialInvalid
+	}
+
+	credential.SignCount = int64(signCount)
+	err = a.userWebAuthnCredentials.UpdateCredentialLastUsed(c, credential)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[authorizations.verifyWebAuthnCredentialAssertion] failed to update signature counter of webauthn credential \"%s\", because %s", credential.CredentialId, err.Error())
+	}
+
+	return credential, nil
+}
+
+func (a *AuthorizationsApi) getWebAuthnLoginRequestResponse(c *core.Context, options *webauthn.CredentialRequestOptions, session *webauthn.Session) (any, *errs.Error) {
+	now := time.Now()
+	encodedSession, err := session.Encode(settings.Container.Current.SecretKey, now)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[authorizations.getWebAuthnLoginRequestResponse] failed to encode webauthn session, because %s", err.Error())
+		return nil, errs.ErrSystemError
+	}
+
+	err = a.userWebAuthnCredentials.CreateChallenge(c, session.Challenge, now.Add(webauthn.SessionValidDuration).Unix())
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[authorizations.getWebAuthnLoginRequestResponse] failed to save webauthn challenge, because %s", err.Error())
+		return nil, errs.ErrSystemError
+	}
+
+	return &models.WebAuthnLoginRequestResponse{
+		Options: options,
+		Session: encodedSession,
+	}, nil
+}
+
 func (a *AuthorizationsApi) getAuthResponse(token string, need2FA bool, user *models.User) *models.AuthResponse {
 	return &models.AuthResponse{
 		Token:   token,
@@ -247,3 +920,192 @@ func (a *AuthorizationsApi) getAuthResponse(token string, need2FA bool, user *mo
 		User:    user.ToUserBasicInfo(),
 	}
 }
+
+func (a *AuthorizationsApi) getUserByOIDCClaims(c *core.Context, claims *oidc.IdTokenClaims) (*models.User, error) {
+	userExternalAuth, err := a.userExternalAuths.GetUserExternalAuthByExternalUserId(c, models.USER_EXTERNAL_AUTH_TYPE_OIDC, claims.Subject)
+
+	if err == nil {
+		user, err := a.users.GetUserById(c, userExternalAuth.Uid)
+
+		if err != nil {
+			return nil, err
+		}
+
+		userExternalAuth.ExternalUsername = claims.Username
+		userExternalAuth.ExternalEmail = claims.Email
+		err = a.userExternalAuths.UpdateUserExternalAuthLastLogin(c, userExternalAuth)
+
+		if err != nil {
+			log.WarnfWithRequestId(c, "[authorizations.getUserByOIDCClaims] failed to update external auth last login time for user \"uid:%d\", because %s", user.Uid, err.Error())
+		}
+
+		a.setUserEmailVerifiedByOIDCClaims(c, user, claims)
+
+		return user, nil
+	} else if err != errs.ErrExternalUserNotLinked {
+		return nil, err
+	}
+
+	var user *models.User
+
+	// only link to the existed user which has the same email when it is allowed and the provider has verified that the external user owns the email
+	if settings.Container.Current.OIDCLinkExistingUserByEmail && claims.Email != "" && claims.EmailVerified {
+		user, err = a.users.GetUserByEmail(c, claims.Email)
+
+		if err != nil && err != errs.ErrUserNotFound {
+			return nil, err
+		}
+	}
+
+	if user == nil {
+		if !settings.Container.Current.OIDCAutoProvisionUser {
+			return nil, errs.ErrExternalUserNotLinked
+		}
+
+		user, err = a.createUserByOIDCClaims(c, claims)
+
+		if err != nil {
+			return nil, err
+		}
+
+		log.InfofWithRequestId(c, "[authorizations.getUserByOIDCClaims] user \"%s\" has been provisioned for external user \"%s\", uid is %d", user.Username, claims.Subject, user.Uid)
+	}
+
+	err = a.userExternalAuths.CreateUserExternalAuth(c, &models.UserExternalAuth{
+		Uid:              user.Uid,
+		ExternalAuthType: models.USER_EXTERNAL_AUTH_TYPE_OIDC,
+		ExternalUserId:   claims.Subject,
+		ExternalUsername: claims.Username,
+		ExternalEmail:    claims.Email,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	log.InfofWithRequestId(c, "[authorizations.getUserByOIDCClaims] external user \"%s\" has been linked to user \"uid:%d\"", claims.Subject, user.Uid)
+
+	a.setUserEmailVerifiedByOIDCClaims(c, user, claims)
+
+	return user, nil
+}
+
+func (a *AuthorizationsApi) createUserByOIDCClaims(c *core.Context, claims *oidc.IdTokenClaims) (*models.User, error) {
+	email := strings.TrimSpace(claims.Email)
+
+	if email == "" {
+		return nil, errs.ErrExternalUserEmailIsEmpty
+	}
+
+	if len(email) > 100 || !utils.IsValidEmail(email) {
+		return nil, errs.ErrEmailIsEmptyOrInvalid
+	}
+
+	defaultCurrency := settings.Container.Current.OIDCDefaultUserCurrency
+
+	if _, exists := validators.AllCurrencyNames[defaultCurrency]; !exists {
+		return nil, errs.ErrUserDefaultCurrencyIsInvalid
+	}
+
+	username, err := a.getAvailableUsernameForOIDCUser(c, claims)
+
+	if err != nil {
+		return nil, err
+	}
+
+	nickname := strings.TrimSpace(claims.Nickname)
+
+	if nickname == "" {
+		nickname = username
+	}
+
+	// the user can only login via provider until resetting the password, so the password is random
+	password, err := utils.GetRandomString(32)
+
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		Username:             username,
+		Email:                email,
+		Nickname:             utils.SubString(nickname, 0, 64),
+		Password:             password,
+		DefaultCurrency:      defaultCurrency,
+		FirstDayOfWeek:       models.WEEKDAY_SUNDAY,
+		TransactionEditScope: models.TRANSACTION_EDIT_SCOPE_ALL,
+		EmailVerified:        claims.EmailVerified,
+	}
+
+	err = a.users.CreateUser(c, user)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (a *AuthorizationsApi) getAvailableUsernameForOIDCUser(c *core.Context, claims *oidc.IdTokenClaims) (string, error) {
+	candidateUsernames := []string{strings.TrimSpace(claims.Username)}
+
+	if atIndex := strings.Index(claims.Email, "@"); atIndex > 0 {
+		candidateUsernames = append(candidateUsernames, claims.Email[:atIndex])
+	}
+
+	for i := 0; i < len(candidateUsernames); i++ {
+		username := candidateUsernames[i]
+
+		if username == "" || len(username) > 32 || !utils.IsValidUsername(username) {
+			continue
+		}
+
+		exists, err := a.users.ExistsUsername(c, username)
+
+		if err != nil {
+			return "", err
+		} else if !exists {
+			return username, nil
+		}
+	}
+
+	for i := 0; i < oidcRandomUsernameMaxRetryTimes; i++ {
+		randomString, err := utils.GetRandomNumberOrLowercaseLetter(10)
+
+		if err != nil {
+			return "", err
+		}
+
+		username := "user_" + randomString
+		exists, err := a.users.ExistsUsername(c, username)
+
+		if err != nil {
+			return "", err
+		} else if !exists {
+			return username, nil
+		}
+	}
+
+	return "", errs.ErrUsernameAlreadyExists
+}
+
+func (a *AuthorizationsApi) setUserEmailVerifiedByOIDCClaims(c *core.Context, user *models.User, claims *oidc.IdTokenClaims) {
+	if user.EmailVerified || !claims.EmailVerified || !strings.EqualFold(user.Email, claims.Email) {
+		return
+	}
+
+	err := a.users.SetUserEmailVerified(c, user.Username)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[authorizations.setUserEmailVerifiedByOIDCClaims] failed to set email verified for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return
+	}
+
+	user.EmailVerified = true
+}
+
+func (a *AuthorizationsApi) setOIDCAuthorizationStateCookie(c *core.Context, value string, maxAge int) {
+	secure := strings.HasPrefix(settings.Container.Current.RootUrl, "https://")
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcAuthorizationStateCookieName, value, maxAge, oidcAuthorizationStateCookiePath, "", secure, true)
+}
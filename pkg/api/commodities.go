@@ -0,0 +1,294 @@
+package api
+
+import (
+	"sort"
+	"time"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/exchangerates"
+	"github.com/kyy-me/ezbookkeeping/pkg/log"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+	"github.com/kyy-me/ezbookkeeping/pkg/services"
+	"github.com/kyy-me/ezbookkeeping/pkg/settings"
+)
+
+// CommoditiesApi represents user-defined commodity api
+type CommoditiesApi struct {
+	commodities *services.CommodityService
+}
+
+// Initialize a commodity api singleton instance
+var (
+	Commodities = &CommoditiesApi{
+		commodities: services.Commodities,
+	}
+)
+
+// CommodityListHandler returns commodity list of current user
+func (a *CommoditiesApi) CommodityListHandler(c *core.Context) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	commodities, err := a.commodities.GetAllCommoditiesByUid(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[commodities.CommodityListHandler] failed to get commodities for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	commodityResps := make(models.CommodityInfoResponseSlice, len(commodities))
+
+	for i := 0; i < len(commodities); i++ {
+		commodityResps[i] = commodities[i].ToCommodityInfoResponse()
+	}
+
+	sort.Sort(commodityResps)
+
+	return commodityResps, nil
+}
+
+// CommodityGetHandler returns one specific commodity of current user
+func (a *CommoditiesApi) CommodityGetHandler(c *core.Context) (any, *errs.Error) {
+	var commodityGetReq models.CommodityGetRequest
+	err := c.ShouldBindQuery(&commodityGetReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[commodities.CommodityGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	commodity, err := a.commodities.GetCommodityByCommodityId(c, uid, commodityGetReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[commodities.CommodityGetHandler] failed to get commodity \"id:%d\" for user \"uid:%d\", because %s", commodityGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return commodity.ToCommodityInfoResponse(), nil
+}
+
+// CommodityCreateHandler saves a new commodity by request parameters for current user
+func (a *CommoditiesApi) CommodityCreateHandler(c *core.Context) (any, *errs.Error) {
+	var commodityCreateReq models.CommodityCreateRequest
+	err := c.ShouldBindJSON(&commodityCreateReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[commodities.CommodityCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if !commodityCreateReq.PriceSource.IsValid() {
+		log.WarnfWithRequestId(c, "[commodities.CommodityCreateHandler] price source invalid, source is %d", commodityCreateReq.PriceSource)
+		return nil, errs.ErrCommodityPriceSourceInvalid
+	}
+
+	if commodityCreateReq.PriceSource == models.COMMODITY_PRICE_SOURCE_QUOTE && settings.Container.Current.CommodityQuoteUrl == "" {
+		log.WarnfWithRequestId(c, "[commodities.CommodityCreateHandler] commodity quote endpoint is not set")
+		return nil, errs.ErrCommodityQuoteEndpointNotSet
+	}
+
+	if !models.IsValidCommodityPrice(commodityCreateReq.Price) {
+		log.WarnfWithRequestId(c, "[commodities.CommodityCreateHandler] price invalid, price is %s", commodityCreateReq.Price)
+		return nil, errs.ErrCommodityPriceInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	commodity := &models.Commodity{
+		Uid:           uid,
+		Code:          commodityCreateReq.Code,
+		Name:          commodityCreateReq.Name,
+		DecimalPlaces: commodityCreateReq.DecimalPlaces,
+		PriceSource:   commodityCreateReq.PriceSource,
+		PriceCurrency: commodityCreateReq.PriceCurrency,
+	}
+
+	if commodity.PriceSource == models.COMMODITY_PRICE_SOURCE_MANUAL {
+		a.setCommodityManualPrice(commodity, commodityCreateReq.Price)
+	} else {
+		a.setCommodityQuotePrice(c, commodity)
+	}
+
+	err = a.commodities.CreateCommodity(c, commodity)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[commodities.CommodityCreateHandler] failed to create commodity \"id:%d\" for user \"uid:%d\", because %s", commodity.CommodityId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[commodities.CommodityCreateHandler] user \"uid:%d\" has created a new commodity \"id:%d\" successfully", uid, commodity.CommodityId)
+
+	return commodity.ToCommodityInfoResponse(), nil
+}
+
+// CommodityModifyHandler saves an existed commodity by request parameters for current user
+func (a *CommoditiesApi) CommodityModifyHandler(c *core.Context) (any, *errs.Error) {
+	var commodityModifyReq models.CommodityModifyRequest
+	err := c.ShouldBindJSON(&commodityModifyReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[commodities.CommodityModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if !commodityModifyReq.PriceSource.IsValid() {
+		log.WarnfWithRequestId(c, "[commodities.CommodityModifyHandler] price source invalid, source is %d", commodityModifyReq.PriceSource)
+		return nil, errs.ErrCommodityPriceSourceInvalid
+	}
+
+	if commodityModifyReq.PriceSource == models.COMMODITY_PRICE_SOURCE_QUOTE && settings.Container.Current.CommodityQuoteUrl == "" {
+		log.WarnfWithRequestId(c, "[commodities.CommodityModifyHandler] commodity quote endpoint is not set")
+		return nil, errs.ErrCommodityQuoteEndpointNotSet
+	}
+
+	if !models.IsValidCommodityPrice(commodityModifyReq.Price) {
+		log.WarnfWithRequestId(c, "[commodities.CommodityModifyHandler] price invalid, price is %s", commodityModifyReq.Price)
+		return nil, errs.ErrCommodityPriceInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	commodity, err := a.commodities.GetCommodityByCommodityId(c, uid, commodityModifyReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[commodities.CommodityModifyHandler] failed to get commodity \"id:%d\" for user \"uid:%d\", because %s", commodityModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	newCommodity := &models.Commodity{
+		CommodityId:          commodity.CommodityId,
+		Uid:                  uid,
+		Code:                 commodity.Code,
+		Name:                 commodityModifyReq.Name,
+		DecimalPlaces:        commodity.DecimalPlaces,
+		PriceSource:          commodityModifyReq.PriceSource,
+		PriceCurrency:        commodityModifyReq.PriceCurrency,
+		Price:                commodity.Price,
+		PriceUpdatedUnixTime: commodity.PriceUpdatedUnixTime,
+	}
+
+	if newCommodity.PriceSource == models.COMMODITY_PRICE_SOURCE_MANUAL {
+		if commodityModifyReq.Price != commodity.Price || newCommodity.PriceCurrency != commodity.PriceCurrency {
+			a.setCommodityManualPrice(newCommodity, commodityModifyReq.Price)
+		}
+	} else if commodity.PriceSource != models.COMMODITY_PRICE_SOURCE_QUOTE || newCommodity.PriceCurrency != commodity.PriceCurrency {
+		a.setCommodityQuotePrice(c, newCommodity)
+	}
+
+	if newCommodity.Name == commodity.Name &&
+		newCommodity.PriceSource == commodity.PriceSource &&
+		newCommodity.PriceCurrency == commodity.PriceCurrency &&
+		newCommodity.Price == commodity.Price &&
+		newCommodity.PriceUpdatedUnixTime == commodity.PriceUpdatedUnixTime {
+		return nil, errs.ErrNothingWillBeUpdated
+	}
+
+	err = a.commodities.ModifyCommodity(c, newCommodity)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[commodities.CommodityModifyHandler] failed to update commodity \"id:%d\" for user \"uid:%d\", because %s", commodityModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[commodities.CommodityModifyHandler] user \"uid:%d\" has updated commodity \"id:%d\" successfully", uid, commodityModifyReq.Id)
+
+	return newCommodity.ToCommodityInfoResponse(), nil
+}
+
+// CommodityRefreshPriceHandler requests the latest price of an existed commodity from quote endpoint for current user
+func (a *CommoditiesApi) CommodityRefreshPriceHandler(c *core.Context) (any, *errs.Error) {
+	var commodityRefreshPriceReq models.CommodityRefreshPriceRequest
+	err := c.ShouldBindJSON(&commodityRefreshPriceReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[commodities.CommodityRefreshPriceHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	commodity, err := a.commodities.GetCommodityByCommodityId(c, uid, commodityRefreshPriceReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[commodities.CommodityRefreshPriceHandler] failed to get commodity \"id:%d\" for user \"uid:%d\", because %s", commodityRefreshPriceReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if commodity.PriceSource != models.COMMODITY_PRICE_SOURCE_QUOTE {
+		log.WarnfWithRequestId(c, "[commodities.CommodityRefreshPriceHandler] price of commodity \"id:%d\" is not from quote endpoint", commodity.CommodityId)
+		return nil, errs.ErrCommodityPriceNotFromQuote
+	}
+
+	price, err := exchangerates.GetCommodityQuotePrice(c, commodity.Code, commodity.PriceCurrency)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[commodities.CommodityRefreshPriceHandler] failed to request price of commodity \"id:%d\" for user \"uid:%d\", because %s", commodity.CommodityId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	commodity.Price = price
+	commodity.PriceUpdatedUnixTime = time.Now().Unix()
+
+	err = a.commodities.ModifyCommodity(c, commodity)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[commodities.CommodityRefreshPriceHandler] failed to update price of commodity \"id:%d\" for user \"uid:%d\", because %s", commodity.CommodityId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[commodities.CommodityRefreshPriceHandler] user \"uid:%d\" has refreshed price of commodity \"id:%d\" successfully", uid, commodity.CommodityId)
+
+	return commodity.ToCommodityInfoResponse(), nil
+}
+
+// CommodityDeleteHandler deletes an existed commodity by request parameters for current user
+func (a *CommoditiesApi) CommodityDeleteHandler(c *core.Context) (any, *errs.Error) {
+	var commodityDeleteReq models.CommodityDeleteRequest
+	err := c.ShouldBindJSON(&commodityDeleteReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[commodities.CommodityDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.commodities.DeleteCommodity(c, uid, commodityDeleteReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[commodities.CommodityDeleteHandler] failed to delete commodity \"id:%d\" for user \"uid:%d\", because %s", commodityDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[commodities.CommodityDeleteHandler] user \"uid:%d\" has deleted commodity \"id:%d\"", uid, commodityDeleteReq.Id)
+	return true, nil
+}
+
+func (a *CommoditiesApi) setCommodityManualPrice(commodity *models.Commodity, price string) {
+	commodity.Price = price
+	commodity.PriceUpdatedUnixTime = 0
+
+	if price != "" {
+		commodity.PriceUpdatedUnixTime = time.Now().Unix()
+	}
+}
+
+func (a *CommoditiesApi) setCommodityQuotePrice(c *core.Context, commodity *models.Commodity) {
+	price, err := exchangerates.GetCommodityQuotePrice(c, commodity.Code, commodity.PriceCurrency)
+
+	// the commodity can still be saved without price and the price can be refreshed later
+	if err != nil {
+		log.WarnfWithRequestId(c, "[commodities.setCommodityQuotePrice] failed to request price of commodity \"%s\", because %s", commodity.Code, err.Error())
+		commodity.Price = ""
+		commodity.PriceUpdatedUnixTime = 0
+		return
+	}
+
+	commodity.Price = price
+	commodity.PriceUpdatedUnixTime = time.Now().Unix()
+}
+
+// getAccountPriceCurrency returns the currency of account, or the price currency of commodity if the account is denominated in commodity
+func getAccountPriceCurrency(account *models.Account, commodityMap map[string]*models.Commodity) string {
+	if commodity, exists := commodityMap[account.Currency]; exists {
+		return commodity.PriceCurrency
+	}
+
+	return account.Currency
+}
@@ -141,6 +141,7 @@ func (a *DataManagementsApi) ClearDataHandler(c *core.Context) (any, *errs.Error
 	}
 
 	log.InfofWithRequestId(c, "[data_managements.ClearDataHandler] user \"uid:%d\" has cleared all data", uid)
+	recordUserAuditEvent(c, uid, models.USER_AUDIT_EVENT_TYPE_DATA_CLEAR, "")
 	return true, nil
 }
 
@@ -224,6 +225,7 @@ func (a *DataManagementsApi) getExportedFileContent(c *core.Context, fileType st
 	}
 
 	fileName := a.getFileName(user, timezone, fileType)
+	recordUserAuditEvent(c, uid, models.USER_AUDIT_EVENT_TYPE_DATA_EXPORT, fileType)
 
 	return result, fileName, nil
 }
@@ -1,11 +1,8 @@
 package api
 
 import (
-	"crypto/tls"
-	"fmt"
-	"io"
-	"net/http"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/kyy-me/ezbookkeeping/pkg/core"
@@ -13,104 +10,624 @@ import (
 	"github.com/kyy-me/ezbookkeeping/pkg/exchangerates"
 	"github.com/kyy-me/ezbookkeeping/pkg/log"
 	"github.com/kyy-me/ezbookkeeping/pkg/models"
+	"github.com/kyy-me/ezbookkeeping/pkg/services"
 	"github.com/kyy-me/ezbookkeeping/pkg/settings"
 	"github.com/kyy-me/ezbookkeeping/pkg/utils"
+	"github.com/kyy-me/ezbookkeeping/pkg/validators"
 )
 
+const exchangeRateConversionRateDecimalPlaces = 10
+
 // ExchangeRatesApi represents exchange rate api
-type ExchangeRatesApi struct{}
+type ExchangeRatesApi struct {
+	exchangeRates *services.ExchangeRateService
+	users         *services.UserService
+	accounts      *services.AccountService
+}
 
 // Initialize a exchange rate api singleton instance
 var (
-	ExchangeRates = &ExchangeRatesApi{}
+	ExchangeRates = &ExchangeRatesApi{
+		exchangeRates: services.ExchangeRates,
+		users:         services.Users,
+		accounts:      services.Accounts,
+	}
 )
 
 // LatestExchangeRateHandler returns latest exchange rate data
 func (a *ExchangeRatesApi) LatestExchangeRateHandler(c *core.Context) (any, *errs.Error) {
-	dataSource := exchangerates.Container.Current
+	if exchangerates.Container.Current == nil || len(exchangerates.Container.Caches) < 1 {
+		return nil, errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	uid := c.GetCurrentUid()
+	latestExchangeRateResponse, err := a.getUserLatestExchangeRates(c, uid)
 
-	if dataSource == nil {
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return latestExchangeRateResponse, nil
+}
+
+// ExchangeRatesHealthHandler returns the fetching status of all configured data sources and the currencies of accounts which are not covered by any data source
+func (a *ExchangeRatesApi) ExchangeRatesHealthHandler(c *core.Context) (any, *errs.Error) {
+	if exchangerates.Container.Current == nil || len(exchangerates.Container.Caches) < 1 {
 		return nil, errs.ErrInvalidExchangeRatesDataSource
 	}
 
+	usedCurrencies, err := a.accounts.GetAllUsedCurrencies(c)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[exchange_rates.ExchangeRatesHealthHandler] failed to get used currencies of all accounts, because %s", err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	return exchangerates.Container.GetHealth(usedCurrencies, time.Now().Unix()), nil
+}
+
+// SaveLatestExchangeRates saves the latest exchange rates which are fetched from the specified data source into database
+func (a *ExchangeRatesApi) SaveLatestExchangeRates(c *core.Context, dataSourceName string, latestExchangeRateResponse *models.LatestExchangeRateResponse) {
+	_, err := a.exchangeRates.SaveExchangeRates(c, latestExchangeRateResponse.ToExchangeRates(dataSourceName))
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[exchange_rates.SaveLatestExchangeRates] failed to save latest exchange rate data of data source \"%s\", because %s", dataSourceName, err.Error())
+	}
+}
+
+// HistoricalExchangeRateHandler returns the exchange rate data of specified date, or the nearest earlier date if there is no exchange rate on that date
+func (a *ExchangeRatesApi) HistoricalExchangeRateHandler(c *core.Context) (any, *errs.Error) {
+	var historicalExchangeRateReq models.HistoricalExchangeRateRequest
+	err := c.ShouldBindQuery(&historicalExchangeRateReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[exchange_rates.HistoricalExchangeRateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	date, err := utils.ParseNumericDate(historicalExchangeRateReq.Date)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[exchange_rates.HistoricalExchangeRateHandler] cannot parse date \"%s\", because %s", historicalExchangeRateReq.Date, err.Error())
+		return nil, errs.ErrExchangeRateDateInvalid
+	}
+
 	uid := c.GetCurrentUid()
+	historicalExchangeRateResp, err := a.getUserHistoricalExchangeRates(c, uid, date)
 
-	transport := http.DefaultTransport.(*http.Transport).Clone()
-	utils.SetProxyUrl(transport, settings.Container.Current.ExchangeRatesProxy)
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
 
-	if settings.Container.Current.ExchangeRatesSkipTLSVerify {
-		transport.TLSClientConfig = &tls.Config{
-			InsecureSkipVerify: true,
-		}
+	return historicalExchangeRateResp, nil
+}
+
+// ConvertExchangeRateHandler returns the amount converted from one currency into another currency by the latest exchange rates, or by the exchange rates of specified date
+func (a *ExchangeRatesApi) ConvertExchangeRateHandler(c *core.Context) (any, *errs.Error) {
+	var convertReq models.ExchangeRateConvertRequest
+	err := c.ShouldBindQuery(&convertReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[exchange_rates.ConvertExchangeRateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	client := &http.Client{
-		Transport: transport,
-		Timeout:   time.Duration(settings.Container.Current.ExchangeRatesRequestTimeout) * time.Millisecond,
+	uid := c.GetCurrentUid()
+	convertResp := &models.ExchangeRateConvertResponse{
+		FromCurrency: convertReq.From,
+		ToCurrency:   convertReq.To,
+		Amount:       convertReq.Amount,
 	}
 
-	urls := dataSource.GetRequestUrls()
-	exchangeRateResps := make([]*models.LatestExchangeRateResponse, 0, len(urls))
+	if convertReq.From == convertReq.To {
+		convertResp.ConvertedAmount = convertReq.Amount
+		convertResp.Rate = "1"
+		return convertResp, nil
+	}
+
+	var fromExchangeRate, toExchangeRate *models.HistoricalExchangeRate
 
-	for i := 0; i < len(urls); i++ {
-		req, _ := http.NewRequest("GET", urls[i], nil)
-		req.Header.Set("User-Agent", fmt.Sprintf("ezBookkeeping/%s ", settings.Version))
+	if convertReq.Date == "" {
+		if exchangerates.Container.Current == nil || len(exchangerates.Container.Caches) < 1 {
+			return nil, errs.ErrInvalidExchangeRatesDataSource
+		}
 
-		resp, err := client.Do(req)
+		latestExchangeRateResponse, err := a.getUserLatestExchangeRates(c, uid)
 
 		if err != nil {
-			log.ErrorfWithRequestId(c, "[exchange_rates.LatestExchangeRateHandler] failed to request latest exchange rate data for user \"uid:%d\", because %s", uid, err.Error())
-			return nil, errs.ErrFailedToRequestRemoteApi
+			return nil, errs.Or(err, errs.ErrOperationFailed)
 		}
 
-		if resp.StatusCode != 200 {
-			log.ErrorfWithRequestId(c, "[exchange_rates.LatestExchangeRateHandler] failed to get latest exchange rate data response for user \"uid:%d\", because response code is not 200", uid)
-			return nil, errs.ErrFailedToRequestRemoteApi
+		convertResp.DataSource = latestExchangeRateResponse.DataSource
+
+		for i := 0; i < len(latestExchangeRateResponse.ExchangeRates); i++ {
+			exchangeRate := latestExchangeRateResponse.ExchangeRates[i]
+
+			if exchangeRate.Currency == convertReq.From {
+				fromExchangeRate = &models.HistoricalExchangeRate{
+					Currency:     exchangeRate.Currency,
+					Rate:         exchangeRate.Rate,
+					UserProvided: exchangeRate.UserProvided,
+				}
+			}
+
+			if exchangeRate.Currency == convertReq.To {
+				toExchangeRate = &models.HistoricalExchangeRate{
+					Currency:     exchangeRate.Currency,
+					Rate:         exchangeRate.Rate,
+					UserProvided: exchangeRate.UserProvided,
+				}
+			}
 		}
+	} else {
+		date, err := utils.ParseNumericDate(convertReq.Date)
 
-		defer resp.Body.Close()
-		body, err := io.ReadAll(resp.Body)
-		exchangeRateResp, err := dataSource.Parse(c, body)
+		if err != nil {
+			log.WarnfWithRequestId(c, "[exchange_rates.ConvertExchangeRateHandler] cannot parse date \"%s\", because %s", convertReq.Date, err.Error())
+			return nil, errs.ErrExchangeRateDateInvalid
+		}
+
+		historicalExchangeRateResp, err := a.getUserHistoricalExchangeRates(c, uid, date)
 
 		if err != nil {
-			log.ErrorfWithRequestId(c, "[exchange_rates.LatestExchangeRateHandler] failed to parse response for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		convertResp.DataSource = historicalExchangeRateResp.DataSource
+		convertResp.Date = historicalExchangeRateResp.Date
+
+		for i := 0; i < len(historicalExchangeRateResp.ExchangeRates); i++ {
+			exchangeRate := historicalExchangeRateResp.ExchangeRates[i]
+
+			if exchangeRate.Currency == convertReq.From {
+				fromExchangeRate = exchangeRate
+			}
+
+			if exchangeRate.Currency == convertReq.To {
+				toExchangeRate = exchangeRate
+			}
+		}
+	}
+
+	if fromExchangeRate == nil || toExchangeRate == nil {
+		log.WarnfWithRequestId(c, "[exchange_rates.ConvertExchangeRateHandler] exchange rate of \"%s\" or \"%s\" does not exist", convertReq.From, convertReq.To)
+		return nil, errs.ErrExchangeRateNotFound
+	}
+
+	convertResp.UserProvided = fromExchangeRate.UserProvided || toExchangeRate.UserProvided
+	convertResp.Rate, err = utils.GetCrossRate(fromExchangeRate.Rate, toExchangeRate.Rate, exchangeRateConversionRateDecimalPlaces)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[exchange_rates.ConvertExchangeRateHandler] failed to calculate rate from \"%s\" to \"%s\", because %s", convertReq.From, convertReq.To, err.Error())
+		return nil, errs.ErrExchangeRateNotFound
+	}
+
+	convertResp.ConvertedAmount, err = utils.ConvertAmountByExchangeRates(convertReq.Amount, fromExchangeRate.Rate, toExchangeRate.Rate, getExchangeRatesRoundingMode())
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[exchange_rates.ConvertExchangeRateHandler] failed to convert amount \"%d\" from \"%s\" to \"%s\", because %s", convertReq.Amount, convertReq.From, convertReq.To, err.Error())
+		return nil, errs.ErrExchangeRateNotFound
+	}
+
+	return convertResp, nil
+}
+
+// UserExchangeRateListHandler returns all exchange rates provided by current user
+func (a *ExchangeRatesApi) UserExchangeRateListHandler(c *core.Context) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	userExchangeRates, err := a.exchangeRates.GetAllUserExchangeRatesByUid(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[exchange_rates.UserExchangeRateListHandler] failed to get user exchange rates for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	userExchangeRateResps := make(models.UserExchangeRateInfoResponseSlice, len(userExchangeRates))
+
+	for i := 0; i < len(userExchangeRates); i++ {
+		userExchangeRateResps[i] = userExchangeRates[i].ToUserExchangeRateInfoResponse()
+	}
+
+	sort.Sort(userExchangeRateResps)
+
+	return userExchangeRateResps, nil
+}
+
+// UserExchangeRateSaveHandler saves an exchange rate provided by current user, the rate without date would be a permanent override
+func (a *ExchangeRatesApi) UserExchangeRateSaveHandler(c *core.Context) (any, *errs.Error) {
+	var userExchangeRateSaveReq models.UserExchangeRateSaveRequest
+	err := c.ShouldBindJSON(&userExchangeRateSaveReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[exchange_rates.UserExchangeRateSaveHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if userExchangeRateSaveReq.Currency == userExchangeRateSaveReq.BaseCurrency {
+		return nil, errs.ErrUserExchangeRateCurrencyIsSameAsBase
+	}
+
+	userExchangeRateSaveReq.Rate = strings.TrimSpace(userExchangeRateSaveReq.Rate)
+	rate, err := utils.ParseDecimal(userExchangeRateSaveReq.Rate)
+
+	if err != nil || rate.Sign() <= 0 {
+		log.WarnfWithRequestId(c, "[exchange_rates.UserExchangeRateSaveHandler] rate \"%s\" is invalid", userExchangeRateSaveReq.Rate)
+		return nil, errs.ErrUserExchangeRateInvalid
+	}
+
+	date, err := a.parseUserExchangeRateDate(userExchangeRateSaveReq.Date)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[exchange_rates.UserExchangeRateSaveHandler] cannot parse date \"%s\", because %s", userExchangeRateSaveReq.Date, err.Error())
+		return nil, errs.ErrExchangeRateDateInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	userExchangeRate := &models.UserExchangeRate{
+		Uid:          uid,
+		Currency:     userExchangeRateSaveReq.Currency,
+		Date:         date,
+		BaseCurrency: userExchangeRateSaveReq.BaseCurrency,
+		Rate:         userExchangeRateSaveReq.Rate,
+	}
+
+	err = a.exchangeRates.SaveUserExchangeRate(c, userExchangeRate)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[exchange_rates.UserExchangeRateSaveHandler] failed to save user exchange rate of currency \"%s\" for user \"uid:%d\", because %s", userExchangeRate.Currency, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[exchange_rates.UserExchangeRateSaveHandler] user \"uid:%d\" has saved exchange rate of currency \"%s\" successfully", uid, userExchangeRate.Currency)
+
+	return userExchangeRate.ToUserExchangeRateInfoResponse(), nil
+}
+
+// UserExchangeRateDeleteHandler deletes an exchange rate provided by current user
+func (a *ExchangeRatesApi) UserExchangeRateDeleteHandler(c *core.Context) (any, *errs.Error) {
+	var userExchangeRateDeleteReq models.UserExchangeRateDeleteRequest
+	err := c.ShouldBindJSON(&userExchangeRateDeleteReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[exchange_rates.UserExchangeRateDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	date, err := a.parseUserExchangeRateDate(userExchangeRateDeleteReq.Date)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[exchange_rates.UserExchangeRateDeleteHandler] cannot parse date \"%s\", because %s", userExchangeRateDeleteReq.Date, err.Error())
+		return nil, errs.ErrExchangeRateDateInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.exchangeRates.DeleteUserExchangeRate(c, uid, userExchangeRateDeleteReq.Currency, date)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[exchange_rates.UserExchangeRateDeleteHandler] failed to delete user exchange rate of currency \"%s\" for user \"uid:%d\", because %s", userExchangeRateDeleteReq.Currency, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[exchange_rates.UserExchangeRateDeleteHandler] user \"uid:%d\" has deleted exchange rate of currency \"%s\"", uid, userExchangeRateDeleteReq.Currency)
+
+	return true, nil
+}
+
+func (a *ExchangeRatesApi) getUserLatestExchangeRates(c *core.Context, uid int64) (*models.LatestExchangeRateResponse, error) {
+	requiredCurrencies, err := a.getUserUsedCurrencies(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[exchange_rates.getUserLatestExchangeRates] failed to get currencies used by user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
+	latestExchangeRateResponse, fetchUnixTime, err := exchangerates.Container.GetLatestExchangeRates(c, requiredCurrencies)
+	var finalExchangeRateResponse *models.LatestExchangeRateResponse
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[exchange_rates.getUserLatestExchangeRates] failed to get latest exchange rate data for user \"uid:%d\", because %s", uid, err.Error())
+
+		storedExchangeRateResponse, storedErr := a.getStoredLatestExchangeRates(c)
+
+		if storedErr != nil {
+			log.ErrorfWithRequestId(c, "[exchange_rates.getUserLatestExchangeRates] failed to get stored exchange rate data for user \"uid:%d\", because %s", uid, storedErr.Error())
 			return nil, errs.Or(err, errs.ErrFailedToRequestRemoteApi)
 		}
 
-		exchangeRateResps = append(exchangeRateResps, exchangeRateResp)
+		finalExchangeRateResponse = storedExchangeRateResponse
+	} else {
+		finalExchangeRateResponse = &models.LatestExchangeRateResponse{
+			DataSource:    latestExchangeRateResponse.DataSource,
+			ReferenceUrl:  latestExchangeRateResponse.ReferenceUrl,
+			UpdateTime:    latestExchangeRateResponse.UpdateTime,
+			BaseCurrency:  latestExchangeRateResponse.BaseCurrency,
+			ExchangeRates: latestExchangeRateResponse.ExchangeRates,
+			FetchTime:     fetchUnixTime,
+			CacheAge:      time.Now().Unix() - fetchUnixTime,
+		}
+	}
+
+	clientTimezone := time.UTC
+	utcOffset, err := c.GetClientTimezoneOffset()
+
+	if err == nil {
+		clientTimezone = time.FixedZone("Client Timezone", int(utcOffset)*60)
+	}
+
+	today := utils.FormatUnixTimeToNumericDate(time.Now().Unix(), clientTimezone)
+	finalExchangeRateResponse.ExchangeRates, err = a.mergeUserLatestExchangeRates(c, uid, today, finalExchangeRateResponse.BaseCurrency, finalExchangeRateResponse.ExchangeRates)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[exchange_rates.getUserLatestExchangeRates] failed to get user exchange rates for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
+	return finalExchangeRateResponse, nil
+}
+
+func (a *ExchangeRatesApi) getUserHistoricalExchangeRates(c *core.Context, uid int64, date int32) (*models.HistoricalExchangeRateResponse, error) {
+	exchangeRateHistory, err := a.exchangeRates.GetMergedExchangeRateHistory(c, settings.Container.Current.ExchangeRatesDataSources, date, date)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[exchange_rates.getUserHistoricalExchangeRates] failed to get exchange rates of date \"%d\", because %s", date, err.Error())
+		return nil, err
 	}
 
-	lastExchangeRateResponse := exchangeRateResps[len(exchangeRateResps)-1]
-	allExchangeRatesMap := make(map[string]string)
+	currencies := exchangeRateHistory.GetCurrencies()
+	exchangeRates := make(models.HistoricalExchangeRateSlice, 0, len(currencies)+1)
+
+	exchangeRates = append(exchangeRates, &models.HistoricalExchangeRate{
+		Currency: exchangeRateHistory.BaseCurrency,
+		Rate:     "1",
+		Date:     utils.FormatNumericDateToLongDate(date),
+	})
 
-	for i := 0; i < len(exchangeRateResps); i++ {
-		exchangeRateResp := exchangeRateResps[i]
+	for i := 0; i < len(currencies); i++ {
+		exchangeRate := exchangeRateHistory.GetExchangeRate(currencies[i], date)
 
-		for j := 0; j < len(exchangeRateResp.ExchangeRates); j++ {
-			exchangeRate := exchangeRateResp.ExchangeRates[j]
-			allExchangeRatesMap[exchangeRate.Currency] = exchangeRate.Rate
+		if exchangeRate == nil {
+			continue
 		}
+
+		exchangeRates = append(exchangeRates, &models.HistoricalExchangeRate{
+			Currency: exchangeRate.Currency,
+			Rate:     exchangeRate.Rate,
+			Date:     utils.FormatNumericDateToLongDate(exchangeRate.Date),
+		})
+	}
+
+	exchangeRates, err = a.mergeUserHistoricalExchangeRates(c, uid, date, exchangeRateHistory.BaseCurrency, exchangeRates)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[exchange_rates.getUserHistoricalExchangeRates] failed to get user exchange rates for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
+	sort.Sort(exchangeRates)
+
+	historicalExchangeRateResp := &models.HistoricalExchangeRateResponse{
+		DataSource:    exchangeRateHistory.DataSource,
+		Date:          utils.FormatNumericDateToLongDate(date),
+		BaseCurrency:  exchangeRateHistory.BaseCurrency,
+		ExchangeRates: exchangeRates,
 	}
 
-	allExchangeRatesMap[lastExchangeRateResponse.BaseCurrency] = "1"
-	allExchangeRates := make(models.LatestExchangeRateSlice, 0, len(allExchangeRatesMap))
+	return historicalExchangeRateResp, nil
+}
+
+func (a *ExchangeRatesApi) getStoredLatestExchangeRates(c *core.Context) (*models.LatestExchangeRateResponse, error) {
+	now := time.Now().Unix()
+	today := utils.FormatUnixTimeToNumericDate(now, time.UTC)
+	exchangeRateHistory, err := a.exchangeRates.GetMergedExchangeRateHistory(c, settings.Container.Current.ExchangeRatesDataSources, today, today)
+
+	if err != nil {
+		return nil, err
+	}
+
+	currencies := exchangeRateHistory.GetCurrencies()
+	exchangeRates := make(models.LatestExchangeRateSlice, 0, len(currencies)+1)
+	maxDate := int32(0)
+	maxUpdatedUnixTime := int64(0)
+
+	exchangeRates = append(exchangeRates, &models.LatestExchangeRate{
+		Currency: exchangeRateHistory.BaseCurrency,
+		Rate:     "1",
+	})
+
+	for i := 0; i < len(currencies); i++ {
+		exchangeRate := exchangeRateHistory.GetExchangeRate(currencies[i], today)
 
-	for currency, rate := range allExchangeRatesMap {
-		allExchangeRates = append(allExchangeRates, &models.LatestExchangeRate{
-			Currency: currency,
-			Rate:     rate,
+		if exchangeRate == nil {
+			continue
+		}
+
+		if exchangeRate.Date > maxDate {
+			maxDate = exchangeRate.Date
+		}
+
+		if exchangeRate.UpdatedUnixTime > maxUpdatedUnixTime {
+			maxUpdatedUnixTime = exchangeRate.UpdatedUnixTime
+		}
+
+		exchangeRates = append(exchangeRates, &models.LatestExchangeRate{
+			Currency: exchangeRate.Currency,
+			Rate:     exchangeRate.Rate,
 		})
 	}
 
-	sort.Sort(allExchangeRates)
+	sort.Sort(exchangeRates)
+
+	updateTime := time.Date(int(maxDate/10000), time.Month(maxDate/100%100), int(maxDate%100), 0, 0, 0, 0, time.UTC)
+
+	storedExchangeRateResponse := &models.LatestExchangeRateResponse{
+		DataSource:    exchangeRateHistory.DataSource,
+		UpdateTime:    updateTime.Unix(),
+		BaseCurrency:  exchangeRateHistory.BaseCurrency,
+		ExchangeRates: exchangeRates,
+		FetchTime:     maxUpdatedUnixTime,
+		CacheAge:      now - maxUpdatedUnixTime,
+	}
+
+	return storedExchangeRateResponse, nil
+}
 
-	finalExchangeRateResponse := &models.LatestExchangeRateResponse{
-		DataSource:    lastExchangeRateResponse.DataSource,
-		ReferenceUrl:  lastExchangeRateResponse.ReferenceUrl,
-		UpdateTime:    lastExchangeRateResponse.UpdateTime,
-		BaseCurrency:  lastExchangeRateResponse.BaseCurrency,
-		ExchangeRates: allExchangeRates,
+func (a *ExchangeRatesApi) getUserUsedCurrencies(c *core.Context, uid int64) ([]string, error) {
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		return nil, err
 	}
 
-	return finalExchangeRateResponse, nil
+	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		return nil, err
+	}
+
+	currencies := make([]string, 0, len(accounts)+1)
+	currencyMap := make(map[string]bool, len(accounts)+1)
+
+	if user.DefaultCurrency != "" {
+		currencies = append(currencies, user.DefaultCurrency)
+		currencyMap[user.DefaultCurrency] = true
+	}
+
+	for i := 0; i < len(accounts); i++ {
+		currency := accounts[i].Currency
+
+		if currency == "" || currency == validators.ParentAccountCurrencyPlaceholder || currencyMap[currency] {
+			continue
+		}
+
+		// the accounts denominated in user-defined commodities do not require exchange rates from data sources
+		if _, exists := validators.AllCurrencyNames[currency]; !exists {
+			continue
+		}
+
+		currencies = append(currencies, currency)
+		currencyMap[currency] = true
+	}
+
+	return currencies, nil
+}
+
+func (a *ExchangeRatesApi) mergeUserLatestExchangeRates(c *core.Context, uid int64, date int32, baseCurrency string, exchangeRates models.LatestExchangeRateSlice) (models.LatestExchangeRateSlice, error) {
+	baseCurrencyRates := make(map[string]string, len(exchangeRates))
+
+	for i := 0; i < len(exchangeRates); i++ {
+		baseCurrencyRates[exchangeRates[i].Currency] = exchangeRates[i].Rate
+	}
+
+	userRates, err := a.getUserExchangeRatesInBaseCurrency(c, uid, date, baseCurrency, baseCurrencyRates)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(userRates) < 1 {
+		return exchangeRates, nil
+	}
+
+	mergedExchangeRates := make(models.LatestExchangeRateSlice, 0, len(exchangeRates)+len(userRates))
+
+	for i := 0; i < len(exchangeRates); i++ {
+		if _, exists := userRates[exchangeRates[i].Currency]; !exists {
+			mergedExchangeRates = append(mergedExchangeRates, exchangeRates[i])
+		}
+	}
+
+	for currency, rate := range userRates {
+		mergedExchangeRates = append(mergedExchangeRates, &models.LatestExchangeRate{
+			Currency:     currency,
+			Rate:         rate,
+			UserProvided: true,
+		})
+	}
+
+	sort.Sort(mergedExchangeRates)
+
+	return mergedExchangeRates, nil
+}
+
+func (a *ExchangeRatesApi) mergeUserHistoricalExchangeRates(c *core.Context, uid int64, date int32, baseCurrency string, exchangeRates models.HistoricalExchangeRateSlice) (models.HistoricalExchangeRateSlice, error) {
+	baseCurrencyRates := make(map[string]string, len(exchangeRates))
+
+	for i := 0; i < len(exchangeRates); i++ {
+		baseCurrencyRates[exchangeRates[i].Currency] = exchangeRates[i].Rate
+	}
+
+	userRates, err := a.getUserExchangeRatesInBaseCurrency(c, uid, date, baseCurrency, baseCurrencyRates)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(userRates) < 1 {
+		return exchangeRates, nil
+	}
+
+	mergedExchangeRates := make(models.HistoricalExchangeRateSlice, 0, len(exchangeRates)+len(userRates))
+
+	for i := 0; i < len(exchangeRates); i++ {
+		if _, exists := userRates[exchangeRates[i].Currency]; !exists {
+			mergedExchangeRates = append(mergedExchangeRates, exchangeRates[i])
+		}
+	}
+
+	for currency, rate := range userRates {
+		mergedExchangeRates = append(mergedExchangeRates, &models.HistoricalExchangeRate{
+			Currency:     currency,
+			Rate:         rate,
+			Date:         utils.FormatNumericDateToLongDate(date),
+			UserProvided: true,
+		})
+	}
+
+	return mergedExchangeRates, nil
+}
+
+func (a *ExchangeRatesApi) getUserExchangeRatesInBaseCurrency(c *core.Context, uid int64, date int32, baseCurrency string, baseCurrencyRates map[string]string) (map[string]string, error) {
+	allUserExchangeRates, err := a.exchangeRates.GetAllUserExchangeRatesByUid(c, uid)
+
+	if err != nil {
+		return nil, err
+	}
+
+	userExchangeRates := models.GetUserExchangeRatesOfDate(allUserExchangeRates, date)
+	userRates := make(map[string]string, len(userExchangeRates))
+
+	for i := 0; i < len(userExchangeRates); i++ {
+		currency, rate, ok := userExchangeRates[i].GetRateInBaseCurrency(baseCurrency, baseCurrencyRates)
+
+		if !ok || currency == baseCurrency {
+			log.WarnfWithRequestId(c, "[exchange_rates.getUserExchangeRatesInBaseCurrency] cannot convert user exchange rate of currency \"%s\" into base currency \"%s\" for user \"uid:%d\"", userExchangeRates[i].Currency, baseCurrency, uid)
+			continue
+		}
+
+		userRates[currency] = rate
+	}
+
+	return userRates, nil
+}
+
+func (a *ExchangeRatesApi) parseUserExchangeRateDate(date string) (int32, error) {
+	if date == "" {
+		return 0, nil
+	}
+
+	return utils.ParseNumericDate(date)
+}
+
+func getExchangeRatesRoundingMode() utils.RoundingMode {
+	roundingMode := settings.Container.Current.ExchangeRatesRoundingMode
+
+	if roundingMode == settings.HalfEvenRoundingMode {
+		return utils.ROUNDING_MODE_HALF_EVEN
+	} else if roundingMode == settings.DownRoundingMode {
+		return utils.ROUNDING_MODE_DOWN
+	} else if roundingMode == settings.UpRoundingMode {
+		return utils.ROUNDING_MODE_UP
+	}
+
+	return utils.ROUNDING_MODE_HALF_UP
 }
@@ -139,6 +139,8 @@ func (a *ForgetPasswordsApi) UserResetPasswordHandler(c *core.Context) (any, *er
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	recordUserAuditEvent(c, user.Uid, models.USER_AUDIT_EVENT_TYPE_PASSWORD_RESET, "")
+
 	now := time.Now().Unix()
 	err = a.tokens.DeleteTokensBeforeTime(c, uid, now)
 
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/log"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+	"github.com/kyy-me/ezbookkeeping/pkg/services"
+)
+
+// InvitationCodesApi represents invitation code api
+type InvitationCodesApi struct {
+	invitationCodes *services.InvitationCodeService
+}
+
+// Initialize an invitation code api singleton instance
+var (
+	InvitationCodes = &InvitationCodesApi{
+		invitationCodes: services.InvitationCodes,
+	}
+)
+
+// InvitationCodeListHandler returns the invitation codes which are not revoked
+func (a *InvitationCodesApi) InvitationCodeListHandler(c *core.Context) (any, *errs.Error) {
+	var listReq models.InvitationCodeListRequest
+	err := c.ShouldBindQuery(&listReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[invitation_codes.InvitationCodeListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	invitationCodes, err := a.invitationCodes.GetInvitationCodes(c, listReq.MaxId, listReq.Count+1)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[invitation_codes.InvitationCodeListHandler] failed to get invitation codes, because %s", err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	recordUserAuditEvent(c, c.GetCurrentUid(), models.USER_AUDIT_EVENT_TYPE_ADMIN_ACTION, "list invitation codes")
+
+	finalCount := len(invitationCodes)
+	var nextMaxId *int64
+
+	if finalCount > int(listReq.Count) {
+		finalCount = int(listReq.Count)
+		nextMaxId = &invitationCodes[finalCount].CodeId
+	}
+
+	invitationCodeResps := make([]*models.InvitationCodeInfoResponse, finalCount)
+
+	for i := 0; i < finalCount; i++ {
+		invitationCodeResps[i] = invitationCodes[i].ToInvitationCodeInfoResponse()
+	}
+
+	return &models.InvitationCodeInfoPageWrapperResponse{
+		Items:     invitationCodeResps,
+		NextMaxId: nextMaxId,
+	}, nil
+}
+
+// InvitationCodeCreateHandler generates a new invitation code by request parameters
+func (a *InvitationCodesApi) InvitationCodeCreateHandler(c *core.Context) (any, *errs.Error) {
+	var createReq models.InvitationCodeCreateRequest
+	err := c.ShouldBindJSON(&createReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[invitation_codes.InvitationCodeCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	invitationCode, err := a.invitationCodes.CreateInvitationCode(c, uid, createReq.MaxUses, createReq.ExpiresInDays, createReq.BoundEmail)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[invitation_codes.InvitationCodeCreateHandler] failed to create invitation code for administrator \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[invitation_codes.InvitationCodeCreateHandler] invitation code \"id:%d\" has been created by administrator \"uid:%d\"", invitationCode.CodeId, uid)
+	recordUserAuditEvent(c, uid, models.USER_AUDIT_EVENT_TYPE_INVITATION_CODE_CREATE, fmt.Sprintf("invitation code \"id:%d\"", invitationCode.CodeId))
+
+	return invitationCode.ToInvitationCodeInfoResponse(), nil
+}
+
+// InvitationCodeRevokeHandler revokes an invitation code by request parameters
+func (a *InvitationCodesApi) InvitationCodeRevokeHandler(c *core.Context) (any, *errs.Error) {
+	var revokeReq models.InvitationCodeRevokeRequest
+	err := c.ShouldBindJSON(&revokeReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[invitation_codes.InvitationCodeRevokeHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.invitationCodes.RevokeInvitationCode(c, revokeReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[invitation_codes.InvitationCodeRevokeHandler] failed to revoke invitation code \"id:%d\", because %s", revokeReq.Id, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[invitation_codes.InvitationCodeRevokeHandler] invitation code \"id:%d\" has been revoked by administrator \"uid:%d\"", revokeReq.Id, uid)
+	recordUserAuditEvent(c, uid, models.USER_AUDIT_EVENT_TYPE_INVITATION_CODE_REVOKE, fmt.Sprintf("invitation code \"id:%d\"", revokeReq.Id))
+
+	return true, nil
+}
@@ -0,0 +1,1128 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/converters"
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/log"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+	"github.com/kyy-me/ezbookkeeping/pkg/services"
+	"github.com/kyy-me/ezbookkeeping/pkg/settings"
+	"github.com/kyy-me/ezbookkeeping/pkg/utils"
+	"github.com/kyy-me/ezbookkeeping/pkg/validators"
+)
+
+const cashFlowForecastRecurringDetectionMonths = 6
+const cashFlowForecastRecurringMinOccurrences = 3
+
+// ReportsApi represents report api
+type ReportsApi struct {
+	reportCsvExporter     *converters.ReportCSVFileExporter
+	reportXlsxExporter    *converters.ReportXLSXFileExporter
+	users                 *services.UserService
+	accounts              *services.AccountService
+	transactions          *services.TransactionService
+	transactionCategories *services.TransactionCategoryService
+	exchangeRates         *services.ExchangeRateService
+	commodities           *services.CommodityService
+}
+
+// Initialize a report api singleton instance
+var (
+	Reports = &ReportsApi{
+		reportCsvExporter:     &converters.ReportCSVFileExporter{},
+		reportXlsxExporter:    &converters.ReportXLSXFileExporter{},
+		users:                 services.Users,
+		accounts:              services.Accounts,
+		transactions:          services.Transactions,
+		transactionCategories: services.TransactionCategories,
+		exchangeRates:         services.ExchangeRates,
+		commodities:           services.Commodities,
+	}
+)
+
+// IncomeStatementHandler returns income statement report of current user
+func (a *ReportsApi) IncomeStatementHandler(c *core.Context) (any, *errs.Error) {
+	return a.getIncomeStatement(c)
+}
+
+// IncomeStatementToCSVHandler returns income statement report of current user in csv format
+func (a *ReportsApi) IncomeStatementToCSVHandler(c *core.Context) ([]byte, string, *errs.Error) {
+	return a.getIncomeStatementFileContent(c, "csv")
+}
+
+// IncomeStatementToXLSXHandler returns income statement report of current user in xlsx format
+func (a *ReportsApi) IncomeStatementToXLSXHandler(c *core.Context) ([]byte, string, *errs.Error) {
+	return a.getIncomeStatementFileContent(c, "xlsx")
+}
+
+// BalanceSheetHandler returns balance sheet report of current user
+func (a *ReportsApi) BalanceSheetHandler(c *core.Context) (any, *errs.Error) {
+	return a.getBalanceSheet(c)
+}
+
+// BalanceSheetToCSVHandler returns balance sheet report of current user in csv format
+func (a *ReportsApi) BalanceSheetToCSVHandler(c *core.Context) ([]byte, string, *errs.Error) {
+	return a.getBalanceSheetFileContent(c, "csv")
+}
+
+// BalanceSheetToXLSXHandler returns balance sheet report of current user in xlsx format
+func (a *ReportsApi) BalanceSheetToXLSXHandler(c *core.Context) ([]byte, string, *errs.Error) {
+	return a.getBalanceSheetFileContent(c, "xlsx")
+}
+
+// CashFlowForecastHandler returns cash flow forecast report of current user
+func (a *ReportsApi) CashFlowForecastHandler(c *core.Context) (any, *errs.Error) {
+	var reportReq models.ReportCashFlowForecastRequest
+	err := c.ShouldBindQuery(&reportReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[reports.CashFlowForecastHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	utcOffset, err := c.GetClientTimezoneOffset()
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[reports.CashFlowForecastHandler] cannot get client timezone offset, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	clientTimezone := time.FixedZone("Client Timezone", int(utcOffset)*60)
+	now := time.Now().Unix()
+	today := utils.GetStartOfDay(time.Unix(now, 0).In(clientTimezone))
+	endDay := today.AddDate(0, int(reportReq.Months), 0)
+	dayCount := int(endDay.Sub(today).Hours() / 24)
+	historyMonths := int(reportReq.HistoryMonths)
+
+	if historyMonths < cashFlowForecastRecurringDetectionMonths {
+		historyMonths = cashFlowForecastRecurringDetectionMonths
+	}
+
+	uid := c.GetCurrentUid()
+	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[reports.CashFlowForecastHandler] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	todayDate := utils.FormatTimeToNumericDate(today)
+	amountConverter, err := a.getReportAmountConverter(c, uid, &reportReq.ExchangeRateConversionRequest, accounts, now, now, todayDate)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[reports.CashFlowForecastHandler] failed to get exchange rate amount converter for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	accountsBalance, err := a.transactions.GetAccountsBalanceByMaxTime(c, uid, now)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[reports.CashFlowForecastHandler] failed to get accounts balance for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	historyTransactions, err := a.transactions.GetAllTransactionsInTimeRange(c, uid, today.AddDate(0, -historyMonths, 0).Unix(), now)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[reports.CashFlowForecastHandler] failed to get history transactions for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	futureTransactions, err := a.transactions.GetAllTransactionsInTimeRange(c, uid, now+1, endDay.Unix()-1)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[reports.CashFlowForecastHandler] failed to get future transactions for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	accountDailyChanges := make(map[int64][]int64)
+	getDailyChanges := func(accountId int64) []int64 {
+		dailyChanges, exists := accountDailyChanges[accountId]
+
+		if !exists {
+			dailyChanges = make([]int64, dayCount)
+			accountDailyChanges[accountId] = dailyChanges
+		}
+
+		return dailyChanges
+	}
+
+	// known future items
+	futureItemMonths := make(map[string]bool)
+
+	for i := 0; i < len(futureTransactions); i++ {
+		transaction := futureTransactions[i]
+		transactionTime := time.Unix(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime), 0).In(clientTimezone)
+		dayIndex := int(utils.GetStartOfDay(transactionTime).Sub(today).Hours() / 24)
+
+		if dayIndex < 0 || dayIndex >= dayCount {
+			continue
+		}
+
+		getDailyChanges(transaction.AccountId)[dayIndex] += a.getBalanceChange(transaction)
+		futureItemMonths[fmt.Sprintf("%s_%d", a.getRecurringItemKey(transaction), utils.FormatUnixTimeToNumericYearMonth(transactionTime.Unix(), clientTimezone))] = true
+	}
+
+	// monthly repeated items, which are repeated once a month in the same account and category,
+	// and the amount may change every month, so the median amount would be projected
+	recurringDetectionStartTime := today.AddDate(0, -cashFlowForecastRecurringDetectionMonths, 0).Unix()
+	recurringItemMonths := make(map[string]map[int32]int)
+	recurringItemAmounts := make(map[string][]int64)
+	recurringItemLatestTransactions := make(map[string]*models.Transaction)
+
+	for i := 0; i < len(historyTransactions); i++ {
+		transaction := historyTransactions[i]
+		transactionUnixTime := utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
+
+		if (transaction.Type != models.TRANSACTION_DB_TYPE_INCOME && transaction.Type != models.TRANSACTION_DB_TYPE_EXPENSE) || transactionUnixTime < recurringDetectionStartTime {
+			continue
+		}
+
+		itemKey := a.getRecurringItemKey(transaction)
+		months, exists := recurringItemMonths[itemKey]
+
+		if !exists {
+			months = make(map[int32]int)
+			recurringItemMonths[itemKey] = months
+			recurringItemLatestTransactions[itemKey] = transaction
+		}
+
+		months[utils.FormatUnixTimeToNumericYearMonth(transactionUnixTime, clientTimezone)]++
+		recurringItemAmounts[itemKey] = append(recurringItemAmounts[itemKey], transaction.Amount)
+	}
+
+	lastMonthStartTime := time.Date(today.Year(), today.Month()-1, 1, 0, 0, 0, 0, clientTimezone).Unix()
+	recurringItems := make(models.ReportCashFlowForecastRecurringItemSlice, 0)
+
+	for itemKey, months := range recurringItemMonths {
+		latestTransaction := recurringItemLatestTransactions[itemKey]
+		latestTime := time.Unix(utils.GetUnixTimeFromTransactionTime(latestTransaction.TransactionTime), 0).In(clientTimezone)
+
+		if len(months) < cashFlowForecastRecurringMinOccurrences || latestTime.Unix() < lastMonthStartTime || !a.isRepeatedOnceAMonth(months) {
+			delete(recurringItemMonths, itemKey)
+			continue
+		}
+
+		dayOfMonth := latestTime.Day()
+		amount := a.getMedianAmount(recurringItemAmounts[itemKey])
+		balanceChange := amount
+		transactionType := models.TRANSACTION_TYPE_EXPENSE
+
+		if latestTransaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+			transactionType = models.TRANSACTION_TYPE_INCOME
+		} else {
+			balanceChange = -amount
+		}
+
+		recurringItems = append(recurringItems, &models.ReportCashFlowForecastRecurringItem{
+			AccountId:  latestTransaction.AccountId,
+			CategoryId: latestTransaction.CategoryId,
+			Type:       transactionType,
+			Amount:     amount,
+			DayOfMonth: int32(dayOfMonth),
+		})
+
+		for month := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, clientTimezone); month.Before(endDay); month = month.AddDate(0, 1, 0) {
+			lastDayOfMonth := month.AddDate(0, 1, -1).Day()
+			itemDay := time.Date(month.Year(), month.Month(), dayOfMonth, 0, 0, 0, 0, clientTimezone)
+
+			if dayOfMonth > lastDayOfMonth {
+				itemDay = time.Date(month.Year(), month.Month(), lastDayOfMonth, 0, 0, 0, 0, clientTimezone)
+			}
+
+			dayIndex := int(itemDay.Sub(today).Hours() / 24)
+
+			if !itemDay.After(latestTime) || dayIndex < 0 || dayIndex >= dayCount {
+				continue
+			}
+
+			if futureItemMonths[fmt.Sprintf("%s_%d", itemKey, utils.FormatUnixTimeToNumericYearMonth(itemDay.Unix(), clientTimezone))] {
+				continue
+			}
+
+			getDailyChanges(latestTransaction.AccountId)[dayIndex] += balanceChange
+		}
+	}
+
+	sort.Sort(recurringItems)
+
+	// trailing average of variable items of each category
+	averageStartDay := today.AddDate(0, -int(reportReq.HistoryMonths), 0)
+	averageDayCount := int(today.Sub(averageStartDay).Hours()/24) + 1
+	variableItemAmounts := make(map[string]int64)
+	variableItemTransactions := make(map[string]*models.Transaction)
+
+	for i := 0; i < len(historyTransactions); i++ {
+		transaction := historyTransactions[i]
+
+		if transaction.Type != models.TRANSACTION_DB_TYPE_INCOME && transaction.Type != models.TRANSACTION_DB_TYPE_EXPENSE {
+			continue
+		}
+
+		if utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime) < averageStartDay.Unix() {
+			continue
+		}
+
+		itemKey := a.getRecurringItemKey(transaction)
+
+		if _, recurring := recurringItemMonths[itemKey]; recurring {
+			continue
+		}
+
+		variableItemAmounts[itemKey] += transaction.Amount
+		variableItemTransactions[itemKey] = transaction
+	}
+
+	variableItems := make(models.ReportCashFlowForecastVariableItemSlice, 0, len(variableItemAmounts))
+
+	for itemKey, variableAmount := range variableItemAmounts {
+		transaction := variableItemTransactions[itemKey]
+		transactionType := models.TRANSACTION_TYPE_EXPENSE
+		sign := int64(-1)
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+			transactionType = models.TRANSACTION_TYPE_INCOME
+			sign = 1
+		}
+
+		dailyChanges := getDailyChanges(transaction.AccountId)
+		previousEstimate := int64(0)
+
+		for i := 1; i < dayCount; i++ {
+			estimate := int64(math.Round(float64(variableAmount) * float64(i) / float64(averageDayCount)))
+			dailyChanges[i] += sign * (estimate - previousEstimate)
+			previousEstimate = estimate
+		}
+
+		variableItems = append(variableItems, &models.ReportCashFlowForecastVariableItem{
+			AccountId:     transaction.AccountId,
+			CategoryId:    transaction.CategoryId,
+			Type:          transactionType,
+			HistoryAmount: variableAmount,
+			Amount:        previousEstimate,
+		})
+	}
+
+	sort.Sort(variableItems)
+
+	// daily projected balances
+	reportResp := &models.ReportCashFlowForecastResponse{
+		StartDate:           utils.FormatNumericDateToLongDate(todayDate),
+		EndDate:             utils.FormatNumericDateToLongDate(utils.FormatTimeToNumericDate(endDay.AddDate(0, 0, -1))),
+		Currency:            amountConverter.GetTargetCurrency(),
+		LowBalanceThreshold: reportReq.LowBalanceThreshold,
+		Balances:            make([]*models.ReportCashFlowForecastDailyBalance, dayCount),
+		Accounts:            make([]*models.ReportCashFlowForecastAccount, 0, len(accounts)),
+		RecurringItems:      recurringItems,
+		VariableItems:       variableItems,
+	}
+
+	dates := make([]string, dayCount)
+
+	for i := 0; i < dayCount; i++ {
+		dates[i] = utils.FormatNumericDateToLongDate(utils.FormatTimeToNumericDate(today.AddDate(0, 0, i)))
+		reportResp.Balances[i] = &models.ReportCashFlowForecastDailyBalance{
+			Date: dates[i],
+		}
+	}
+
+	for i := 0; i < len(accounts); i++ {
+		account := accounts[i]
+
+		if account.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS {
+			continue
+		}
+
+		balance := accountsBalance[account.AccountId]
+		dailyChanges := accountDailyChanges[account.AccountId]
+		accountResp := &models.ReportCashFlowForecastAccount{
+			AccountId: account.AccountId,
+			Currency:  account.Currency,
+			Balances:  make([]*models.ReportCashFlowForecastDailyBalance, dayCount),
+		}
+
+		for j := 0; j < dayCount; j++ {
+			if dailyChanges != nil {
+				balance += dailyChanges[j]
+			}
+
+			accountResp.Balances[j] = &models.ReportCashFlowForecastDailyBalance{
+				Date:    dates[j],
+				Balance: balance,
+			}
+
+			if accountResp.LowBalanceDate == "" && balance < 0 && models.IsAssetAccountCategory(account.Category) {
+				accountResp.LowBalanceDate = dates[j]
+			}
+
+			amount, err := amountConverter.ConvertAccountAmount(0, account.AccountId, balance, todayDate)
+
+			if err != nil {
+				log.ErrorfWithRequestId(c, "[reports.CashFlowForecastHandler] failed to convert balance of account \"id:%d\" for user \"uid:%d\", because %s", account.AccountId, uid, err.Error())
+				return nil, errs.Or(err, errs.ErrOperationFailed)
+			}
+
+			reportResp.Balances[j].Balance += amount
+		}
+
+		reportResp.Accounts = append(reportResp.Accounts, accountResp)
+	}
+
+	for i := 0; i < dayCount; i++ {
+		if reportResp.Balances[i].Balance < reportReq.LowBalanceThreshold {
+			reportResp.LowBalanceDate = dates[i]
+			break
+		}
+	}
+
+	reportResp.ExchangeRates = amountConverter.GetConversionInfos(0)
+
+	return reportResp, nil
+}
+
+// ExchangeGainLossHandler returns exchange gain and loss report of current user, which contains the costs of cross-currency transfers
+// compared with reference exchange rates and the unrealized gains or losses of foreign-currency account balances in the period
+func (a *ReportsApi) ExchangeGainLossHandler(c *core.Context) (any, *errs.Error) {
+	var reportReq models.ReportExchangeGainLossRequest
+	err := c.ShouldBindQuery(&reportReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[reports.ExchangeGainLossHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	utcOffset, err := c.GetClientTimezoneOffset()
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[reports.ExchangeGainLossHandler] cannot get client timezone offset, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	startTime := reportReq.StartTime
+	endTime := reportReq.EndTime
+
+	if endTime <= 0 {
+		endTime = time.Now().Unix()
+	}
+
+	if startTime > endTime {
+		log.WarnfWithRequestId(c, "[reports.ExchangeGainLossHandler] start time \"%d\" is later than end time \"%d\"", startTime, endTime)
+		return nil, errs.ErrParameterInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	targetCurrency := reportReq.TargetCurrency
+
+	if targetCurrency == "" {
+		user, err := a.users.GetUserById(c, uid)
+
+		if err != nil {
+			log.ErrorfWithRequestId(c, "[reports.ExchangeGainLossHandler] failed to get user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		targetCurrency = user.DefaultCurrency
+	}
+
+	if targetCurrency == "" {
+		log.WarnfWithRequestId(c, "[reports.ExchangeGainLossHandler] target currency is not specified and user \"uid:%d\" has no default currency", uid)
+		return nil, errs.ErrUserDefaultCurrencyIsEmpty
+	}
+
+	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[reports.ExchangeGainLossHandler] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	transfers, err := a.transactions.GetTransferOutTransactionsInTimeRange(c, uid, startTime, endTime)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[reports.ExchangeGainLossHandler] failed to get transfer transactions for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	transactions, err := a.transactions.GetAllTransactionsInTimeRange(c, uid, startTime, endTime)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[reports.ExchangeGainLossHandler] failed to get transactions for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	startAccountsBalance := make(map[int64]int64)
+
+	if startTime > 0 {
+		startAccountsBalance, err = a.transactions.GetAccountsBalanceByMaxTime(c, uid, startTime-1)
+
+		if err != nil {
+			log.ErrorfWithRequestId(c, "[reports.ExchangeGainLossHandler] failed to get accounts balance at start time for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
+	endAccountsBalance, err := a.transactions.GetAccountsBalanceByMaxTime(c, uid, endTime)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[reports.ExchangeGainLossHandler] failed to get accounts balance at end time for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	clientTimezone := time.FixedZone("Client Timezone", int(utcOffset)*60)
+	startDate := int32(0)
+
+	if startTime > 0 {
+		startDate = utils.FormatUnixTimeToNumericDate(startTime, clientTimezone)
+	}
+
+	endDate := utils.FormatUnixTimeToNumericDate(endTime, clientTimezone)
+	utcTimezone := time.FixedZone("UTC", 0)
+
+	// transactions may be in any timezone, so the date range of exchange rates is extended by one day at each end
+	historyStartDate := int32(0)
+
+	if startTime > 0 {
+		historyStartDate = utils.FormatUnixTimeToNumericDate(startTime-24*60*60, utcTimezone)
+	}
+
+	historyEndDate := utils.FormatUnixTimeToNumericDate(endTime+24*60*60, utcTimezone)
+	exchangeRateHistory, err := a.exchangeRates.GetMergedExchangeRateHistory(c, settings.Container.Current.ExchangeRatesDataSources, historyStartDate, historyEndDate)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[reports.ExchangeGainLossHandler] failed to get exchange rate history for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	roundingMode := getExchangeRatesRoundingMode()
+	accountMap := a.accounts.GetAccountMapByList(accounts)
+	reportResp := &models.ReportExchangeGainLossResponse{
+		StartTime: startTime,
+		EndTime:   endTime,
+		Currency:  targetCurrency,
+		Transfers: make([]*models.ReportExchangeTransferCost, 0),
+		Accounts:  make([]*models.ReportExchangeAccountUnrealizedGainLoss, 0),
+	}
+
+	// realized costs of cross-currency transfers
+	for i := len(transfers) - 1; i >= 0; i-- {
+		transfer := transfers[i]
+		sourceAccount := accountMap[transfer.AccountId]
+		destinationAccount := accountMap[transfer.RelatedAccountId]
+
+		if sourceAccount == nil || destinationAccount == nil || sourceAccount.Currency == destinationAccount.Currency ||
+			!a.isExchangeGainLossCurrency(sourceAccount.Currency) || !a.isExchangeGainLossCurrency(destinationAccount.Currency) ||
+			transfer.Amount <= 0 || transfer.RelatedAccountAmount <= 0 {
+			continue
+		}
+
+		transactionUnixTime := utils.GetUnixTimeFromTransactionTime(transfer.TransactionTime)
+		transactionTimezone := time.FixedZone("Transaction Timezone", int(transfer.TimezoneUtcOffset)*60)
+		transactionDate := utils.FormatUnixTimeToNumericDate(transactionUnixTime, transactionTimezone)
+		sourceExchangeRate := exchangeRateHistory.GetExchangeRate(sourceAccount.Currency, transactionDate)
+		destinationExchangeRate := exchangeRateHistory.GetExchangeRate(destinationAccount.Currency, transactionDate)
+
+		if sourceExchangeRate == nil || destinationExchangeRate == nil {
+			log.WarnfWithRequestId(c, "[reports.ExchangeGainLossHandler] there is no exchange rate between \"%s\" and \"%s\" on \"%d\"", sourceAccount.Currency, destinationAccount.Currency, transactionDate)
+			continue
+		}
+
+		executedRate, err := utils.GetCrossRate(utils.Int64ToString(transfer.Amount), utils.Int64ToString(transfer.RelatedAccountAmount), exchangeRateConversionRateDecimalPlaces)
+
+		if err != nil {
+			continue
+		}
+
+		referenceRate, err := utils.GetCrossRate(sourceExchangeRate.Rate, destinationExchangeRate.Rate, exchangeRateConversionRateDecimalPlaces)
+
+		if err != nil {
+			log.WarnfWithRequestId(c, "[reports.ExchangeGainLossHandler] cannot get reference rate between \"%s\" and \"%s\" on \"%d\"", sourceAccount.Currency, destinationAccount.Currency, transactionDate)
+			continue
+		}
+
+		referenceAmount, err := exchangeRateHistory.ConvertAmount(transfer.Amount, sourceAccount.Currency, destinationAccount.Currency, transactionDate, roundingMode)
+
+		if err != nil {
+			continue
+		}
+
+		cost := referenceAmount - transfer.RelatedAccountAmount
+		amount, err := exchangeRateHistory.ConvertAmount(cost, destinationAccount.Currency, targetCurrency, transactionDate, roundingMode)
+
+		if err != nil {
+			log.WarnfWithRequestId(c, "[reports.ExchangeGainLossHandler] cannot convert cost of transaction \"id:%d\" from \"%s\" to \"%s\", because %s", transfer.TransactionId, destinationAccount.Currency, targetCurrency, err.Error())
+			continue
+		}
+
+		reportResp.Transfers = append(reportResp.Transfers, &models.ReportExchangeTransferCost{
+			TransactionId:        transfer.TransactionId,
+			Time:                 transactionUnixTime,
+			SourceAccountId:      sourceAccount.AccountId,
+			SourceCurrency:       sourceAccount.Currency,
+			SourceAmount:         transfer.Amount,
+			DestinationAccountId: destinationAccount.AccountId,
+			DestinationCurrency:  destinationAccount.Currency,
+			DestinationAmount:    transfer.RelatedAccountAmount,
+			ExecutedRate:         executedRate,
+			ReferenceRate:        referenceRate,
+			ReferenceAmount:      referenceAmount,
+			Cost:                 cost,
+			Amount:               amount,
+		})
+
+		reportResp.TotalTransferCost += amount
+	}
+
+	// unrealized gains or losses of foreign-currency account balances
+	accountFlows := make(map[int64][]*models.Transaction)
+
+	for i := 0; i < len(transactions); i++ {
+		accountFlows[transactions[i].AccountId] = append(accountFlows[transactions[i].AccountId], transactions[i])
+	}
+
+	for i := 0; i < len(accounts); i++ {
+		account := accounts[i]
+
+		if account.Type != models.ACCOUNT_TYPE_SINGLE_ACCOUNT || account.Currency == targetCurrency || !a.isExchangeGainLossCurrency(account.Currency) {
+			continue
+		}
+
+		startBalance := startAccountsBalance[account.AccountId]
+		endBalance := endAccountsBalance[account.AccountId]
+		flows := accountFlows[account.AccountId]
+
+		if startBalance == 0 && endBalance == 0 && len(flows) == 0 {
+			continue
+		}
+
+		gainLoss, err := a.getAccountUnrealizedGainLoss(exchangeRateHistory, account, targetCurrency, startBalance, startDate, endBalance, endDate, flows, roundingMode)
+
+		if err != nil {
+			log.WarnfWithRequestId(c, "[reports.ExchangeGainLossHandler] cannot get unrealized gain or loss of account \"id:%d\" for user \"uid:%d\", because %s", account.AccountId, uid, err.Error())
+			continue
+		}
+
+		reportResp.Accounts = append(reportResp.Accounts, gainLoss)
+		reportResp.TotalUnrealizedGainLoss += gainLoss.GainLoss
+	}
+
+	return reportResp, nil
+}
+
+func (a *ReportsApi) getIncomeStatementFileContent(c *core.Context, fileType string) ([]byte, string, *errs.Error) {
+	report, apiErr := a.getIncomeStatement(c)
+
+	if apiErr != nil {
+		return nil, "", apiErr
+	}
+
+	uid := c.GetCurrentUid()
+	result, err := a.getReportExporter(fileType).IncomeStatementToExportedContent(report)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[reports.getIncomeStatementFileContent] failed to get %s format income statement for \"uid:%d\", because %s", fileType, uid, err.Error())
+		return nil, "", errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return result, a.getFileName(c, "income_statement", fileType), nil
+}
+
+func (a *ReportsApi) getBalanceSheetFileContent(c *core.Context, fileType string) ([]byte, string, *errs.Error) {
+	report, apiErr := a.getBalanceSheet(c)
+
+	if apiErr != nil {
+		return nil, "", apiErr
+	}
+
+	uid := c.GetCurrentUid()
+	result, err := a.getReportExporter(fileType).BalanceSheetToExportedContent(report)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[reports.getBalanceSheetFileContent] failed to get %s format balance sheet for \"uid:%d\", because %s", fileType, uid, err.Error())
+		return nil, "", errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return result, a.getFileName(c, "balance_sheet", fileType), nil
+}
+
+func (a *ReportsApi) getIncomeStatement(c *core.Context) (*models.ReportIncomeStatementResponse, *errs.Error) {
+	var reportReq models.ReportIncomeStatementRequest
+	err := c.ShouldBindQuery(&reportReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[reports.getIncomeStatement] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	utcOffset, err := c.GetClientTimezoneOffset()
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[reports.getIncomeStatement] cannot get client timezone offset, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[reports.getIncomeStatement] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	categories, err := a.transactionCategories.GetAllCategoriesByUid(c, uid, 0, -1)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[reports.getIncomeStatement] failed to get categories for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	amountConverter, err := a.getReportAmountConverter(c, uid, &reportReq.ExchangeRateConversionRequest, accounts, reportReq.StartTime, reportReq.EndTime, 0)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[reports.getIncomeStatement] failed to get exchange rate amount converter for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	totalAmounts, err := a.transactions.GetAccountsAndCategoriesTotalIncomeAndExpense(c, uid, reportReq.StartTime, reportReq.EndTime, utcOffset, reportReq.UseTransactionTimezone, amountConverter)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[reports.getIncomeStatement] failed to get accounts and categories total income and expense for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	categoryMap := a.transactionCategories.GetCategoryMapByList(categories)
+	incomeSection := &models.ReportCategorySection{Items: make(models.ReportCategoryItemSlice, 0)}
+	expenseSection := &models.ReportCategorySection{Items: make(models.ReportCategoryItemSlice, 0)}
+	categoryItems := make(map[int64]*models.ReportCategoryItem)
+
+	for i := 0; i < len(totalAmounts); i++ {
+		totalAmount := totalAmounts[i]
+		category, exists := categoryMap[totalAmount.CategoryId]
+
+		if !exists {
+			log.WarnfWithRequestId(c, "[reports.getIncomeStatement] category \"id:%d\" of user \"uid:%d\" does not exist", totalAmount.CategoryId, uid)
+			continue
+		}
+
+		section := incomeSection
+
+		if category.Type == models.CATEGORY_TYPE_EXPENSE {
+			section = expenseSection
+		} else if category.Type != models.CATEGORY_TYPE_INCOME {
+			continue
+		}
+
+		primaryCategory := category
+
+		if category.ParentCategoryId != models.LevelOneTransactionParentId {
+			primaryCategory, exists = categoryMap[category.ParentCategoryId]
+
+			if !exists {
+				log.WarnfWithRequestId(c, "[reports.getIncomeStatement] parent category \"id:%d\" of user \"uid:%d\" does not exist", category.ParentCategoryId, uid)
+				continue
+			}
+		}
+
+		primaryItem, exists := categoryItems[primaryCategory.CategoryId]
+
+		if !exists {
+			primaryItem = &models.ReportCategoryItem{
+				CategoryId:   primaryCategory.CategoryId,
+				Name:         primaryCategory.Name,
+				DisplayOrder: primaryCategory.DisplayOrder,
+			}
+
+			categoryItems[primaryCategory.CategoryId] = primaryItem
+			section.Items = append(section.Items, primaryItem)
+		}
+
+		primaryItem.Amount += totalAmount.Amount
+		section.Total += totalAmount.Amount
+
+		if primaryCategory == category {
+			continue
+		}
+
+		subItem, exists := categoryItems[category.CategoryId]
+
+		if !exists {
+			subItem = &models.ReportCategoryItem{
+				CategoryId:   category.CategoryId,
+				Name:         category.Name,
+				DisplayOrder: category.DisplayOrder,
+			}
+
+			categoryItems[category.CategoryId] = subItem
+			primaryItem.SubCategories = append(primaryItem.SubCategories, subItem)
+		}
+
+		subItem.Amount += totalAmount.Amount
+	}
+
+	for _, section := range []*models.ReportCategorySection{incomeSection, expenseSection} {
+		sort.Sort(section.Items)
+
+		for i := 0; i < len(section.Items); i++ {
+			sort.Sort(section.Items[i].SubCategories)
+		}
+	}
+
+	return &models.ReportIncomeStatementResponse{
+		StartTime:     reportReq.StartTime,
+		EndTime:       reportReq.EndTime,
+		Currency:      amountConverter.GetTargetCurrency(),
+		Income:        incomeSection,
+		Expense:       expenseSection,
+		NetIncome:     incomeSection.Total - expenseSection.Total,
+		ExchangeRates: amountConverter.GetConversionInfos(0),
+	}, nil
+}
+
+func (a *ReportsApi) getBalanceSheet(c *core.Context) (*models.ReportBalanceSheetResponse, *errs.Error) {
+	var reportReq models.ReportBalanceSheetRequest
+	err := c.ShouldBindQuery(&reportReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[reports.getBalanceSheet] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	utcOffset, err := c.GetClientTimezoneOffset()
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[reports.getBalanceSheet] cannot get client timezone offset, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	reportTime := reportReq.Time
+
+	if reportTime <= 0 {
+		reportTime = time.Now().Unix()
+	}
+
+	uid := c.GetCurrentUid()
+	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[reports.getBalanceSheet] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	clientTimezone := time.FixedZone("Client Timezone", int(utcOffset)*60)
+	reportDate := utils.FormatUnixTimeToNumericDate(reportTime, clientTimezone)
+	amountConverter, err := a.getReportAmountConverter(c, uid, &reportReq.ExchangeRateConversionRequest, accounts, reportTime, reportTime, reportDate)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[reports.getBalanceSheet] failed to get exchange rate amount converter for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	accountsBalance, err := a.transactions.GetAccountsBalanceByMaxTime(c, uid, reportTime)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[reports.getBalanceSheet] failed to get accounts balance for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	subAccounts := make(map[int64][]*models.Account)
+	categoryGroups := make(map[models.AccountCategory]*models.ReportAccountGroup)
+
+	for i := 0; i < len(accounts); i++ {
+		account := accounts[i]
+
+		if account.ParentAccountId != models.LevelOneAccountParentId {
+			subAccounts[account.ParentAccountId] = append(subAccounts[account.ParentAccountId], account)
+		}
+	}
+
+	for i := 0; i < len(accounts); i++ {
+		account := accounts[i]
+
+		if account.ParentAccountId != models.LevelOneAccountParentId {
+			continue
+		}
+
+		group, exists := categoryGroups[account.Category]
+
+		if !exists {
+			group = &models.ReportAccountGroup{
+				Category: account.Category,
+				Accounts: make([]*models.ReportAccountBalance, 0),
+			}
+
+			categoryGroups[account.Category] = group
+		}
+
+		balanceAccounts := []*models.Account{account}
+
+		if account.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS {
+			balanceAccounts = subAccounts[account.AccountId]
+		}
+
+		for j := 0; j < len(balanceAccounts); j++ {
+			balanceAccount := balanceAccounts[j]
+			balance := accountsBalance[balanceAccount.AccountId]
+			amount, err := amountConverter.ConvertAccountAmount(0, balanceAccount.AccountId, balance, reportDate)
+
+			if err != nil {
+				log.ErrorfWithRequestId(c, "[reports.getBalanceSheet] failed to convert balance of account \"id:%d\" for user \"uid:%d\", because %s", balanceAccount.AccountId, uid, err.Error())
+				return nil, errs.Or(err, errs.ErrOperationFailed)
+			}
+
+			if models.IsLiabilityAccountCategory(account.Category) {
+				amount = -amount
+			}
+
+			group.Accounts = append(group.Accounts, &models.ReportAccountBalance{
+				AccountId: balanceAccount.AccountId,
+				Name:      balanceAccount.Name,
+				Currency:  balanceAccount.Currency,
+				Balance:   balance,
+				Amount:    amount,
+			})
+
+			group.Subtotal += amount
+		}
+	}
+
+	assetSection := &models.ReportAccountSection{Groups: make([]*models.ReportAccountGroup, 0)}
+	liabilitySection := &models.ReportAccountSection{Groups: make([]*models.ReportAccountGroup, 0)}
+
+	for category := models.ACCOUNT_CATEGORY_CASH; category <= models.ACCOUNT_CATEGORY_SAVING; category++ {
+		group, exists := categoryGroups[category]
+
+		if !exists {
+			continue
+		}
+
+		if models.IsAssetAccountCategory(category) {
+			assetSection.Groups = append(assetSection.Groups, group)
+			assetSection.Total += group.Subtotal
+		} else if models.IsLiabilityAccountCategory(category) {
+			liabilitySection.Groups = append(liabilitySection.Groups, group)
+			liabilitySection.Total += group.Subtotal
+		}
+	}
+
+	return &models.ReportBalanceSheetResponse{
+		Time:          reportTime,
+		Currency:      amountConverter.GetTargetCurrency(),
+		Assets:        assetSection,
+		Liabilities:   liabilitySection,
+		NetEquity:     assetSection.Total - liabilitySection.Total,
+		ExchangeRates: amountConverter.GetConversionInfos(0),
+	}, nil
+}
+
+// getReportAmountConverter returns an amount converter which converts amounts into the requested currency or the default currency of user,
+// the exchange rates are only loaded when there is any account in other currency
+func (a *ReportsApi) getReportAmountConverter(c *core.Context, uid int64, conversionReq *models.ExchangeRateConversionRequest, accounts []*models.Account, startUnixTime int64, endUnixTime int64, defaultFixedDate int32) (*models.ExchangeRateAmountConverter, error) {
+	targetCurrency := conversionReq.TargetCurrency
+
+	if targetCurrency == "" {
+		user, err := a.users.GetUserById(c, uid)
+
+		if err != nil {
+			return nil, err
+		}
+
+		targetCurrency = user.DefaultCurrency
+	}
+
+	fixedDate, err := conversionReq.GetNumericExchangeRateDate()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if fixedDate == 0 {
+		fixedDate = defaultFixedDate
+	}
+
+	accountMap := a.accounts.GetAccountMapByList(accounts)
+	commodityMap, err := a.commodities.GetCommodityMapOfAccounts(c, uid, accounts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	conversionRequired := false
+
+	for i := 0; i < len(accounts); i++ {
+		if getAccountPriceCurrency(accounts[i], commodityMap) != targetCurrency {
+			conversionRequired = true
+			break
+		}
+	}
+
+	if !conversionRequired {
+		amountConverter := models.NewExchangeRateAmountConverter(nil, targetCurrency, fixedDate, accountMap, getExchangeRatesRoundingMode())
+		amountConverter.SetCommodities(commodityMap)
+
+		return amountConverter, nil
+	}
+
+	var startDate, endDate int32
+
+	if fixedDate > 0 {
+		startDate = fixedDate
+		endDate = fixedDate
+	} else {
+		utcTimezone := time.FixedZone("UTC", 0)
+
+		// transactions may be in any timezone, so the date range of exchange rates is extended by one day at each end
+		if startUnixTime > 0 {
+			startDate = utils.FormatUnixTimeToNumericDate(startUnixTime-24*60*60, utcTimezone)
+		}
+
+		if endUnixTime > 0 {
+			endDate = utils.FormatUnixTimeToNumericDate(endUnixTime+24*60*60, utcTimezone)
+		}
+	}
+
+	exchangeRateHistory, err := a.exchangeRates.GetMergedExchangeRateHistory(c, settings.Container.Current.ExchangeRatesDataSources, startDate, endDate)
+
+	if err != nil {
+		return nil, err
+	}
+
+	amountConverter := models.NewExchangeRateAmountConverter(exchangeRateHistory, targetCurrency, fixedDate, accountMap, getExchangeRatesRoundingMode())
+	amountConverter.SetCommodities(commodityMap)
+
+	return amountConverter, nil
+}
+
+func (a *ReportsApi) getAccountUnrealizedGainLoss(exchangeRateHistory *models.ExchangeRateHistory, account *models.Account, targetCurrency string, startBalance int64, startDate int32, endBalance int64, endDate int32, flows []*models.Transaction, roundingMode utils.RoundingMode) (*models.ReportExchangeAccountUnrealizedGainLoss, error) {
+	startAmount := int64(0)
+
+	if startBalance != 0 {
+		amount, err := exchangeRateHistory.ConvertAmount(startBalance, account.Currency, targetCurrency, startDate, roundingMode)
+
+		if err != nil {
+			return nil, err
+		}
+
+		startAmount = amount
+	}
+
+	endAmount, err := exchangeRateHistory.ConvertAmount(endBalance, account.Currency, targetCurrency, endDate, roundingMode)
+
+	if err != nil {
+		return nil, err
+	}
+
+	netFlowAmount := int64(0)
+
+	// each transaction is valued at the exchange rate of its own date, so only the changes of exchange rates contribute to the gain or loss
+	for i := 0; i < len(flows); i++ {
+		flow := flows[i]
+		balanceChange := a.getBalanceChange(flow)
+
+		if balanceChange == 0 {
+			continue
+		}
+
+		transactionTimezone := time.FixedZone("Transaction Timezone", int(flow.TimezoneUtcOffset)*60)
+		transactionDate := utils.FormatUnixTimeToNumericDate(utils.GetUnixTimeFromTransactionTime(flow.TransactionTime), transactionTimezone)
+		amount, err := exchangeRateHistory.ConvertAmount(balanceChange, account.Currency, targetCurrency, transactionDate, roundingMode)
+
+		if err != nil {
+			return nil, err
+		}
+
+		netFlowAmount += amount
+	}
+
+	return &models.ReportExchangeAccountUnrealizedGainLoss{
+		AccountId:     account.AccountId,
+		Name:          account.Name,
+		Currency:      account.Currency,
+		StartBalance:  startBalance,
+		EndBalance:    endBalance,
+		StartAmount:   startAmount,
+		EndAmount:     endAmount,
+		NetFlowAmount: netFlowAmount,
+		GainLoss:      endAmount - startAmount - netFlowAmount,
+	}, nil
+}
+
+// isExchangeGainLossCurrency returns whether the currency has exchange rates, the user-defined commodities are not included
+func (a *ReportsApi) isExchangeGainLossCurrency(currency string) bool {
+	_, exists := validators.AllCurrencyNames[currency]
+	return exists
+}
+
+func (a *ReportsApi) getBalanceChange(transaction *models.Transaction) int64 {
+	switch transaction.Type {
+	case models.TRANSACTION_DB_TYPE_MODIFY_BALANCE:
+		return transaction.RelatedAccountAmount
+	case models.TRANSACTION_DB_TYPE_INCOME, models.TRANSACTION_DB_TYPE_TRANSFER_IN:
+		return transaction.Amount
+	case models.TRANSACTION_DB_TYPE_EXPENSE, models.TRANSACTION_DB_TYPE_TRANSFER_OUT:
+		return -transaction.Amount
+	default:
+		return 0
+	}
+}
+
+func (a *ReportsApi) getRecurringItemKey(transaction *models.Transaction) string {
+	return fmt.Sprintf("%d_%d_%d", transaction.AccountId, transaction.CategoryId, transaction.Type)
+}
+
+// isRepeatedOnceAMonth returns whether the item has only one transaction in every month which it occurs,
+// the items which occur many times a month (e.g. groceries) are variable items
+func (a *ReportsApi) isRepeatedOnceAMonth(monthTransactionCounts map[int32]int) bool {
+	for _, count := range monthTransactionCounts {
+		if count > 1 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (a *ReportsApi) getMedianAmount(amounts []int64) int64 {
+	sortedAmounts := make([]int64, len(amounts))
+	copy(sortedAmounts, amounts)
+	sort.Slice(sortedAmounts, func(i, j int) bool {
+		return sortedAmounts[i] < sortedAmounts[j]
+	})
+
+	middle := len(sortedAmounts) / 2
+
+	if len(sortedAmounts)%2 == 0 {
+		return (sortedAmounts[middle-1] + sortedAmounts[middle]) / 2
+	}
+
+	return sortedAmounts[middle]
+}
+
+func (a *ReportsApi) getReportExporter(fileType string) converters.ReportConverter {
+	if fileType == "xlsx" {
+		return a.reportXlsxExporter
+	}
+
+	return a.reportCsvExporter
+}
+
+func (a *ReportsApi) getFileName(c *core.Context, reportName string, fileExtension string) string {
+	timezone := time.Local
+	utcOffset, err := c.GetClientTimezoneOffset()
+
+	if err == nil {
+		timezone = time.FixedZone("Client Timezone", int(utcOffset)*60)
+	}
+
+	currentTime := utils.FormatUnixTimeToLongDateTimeWithoutSecond(time.Now().Unix(), timezone)
+	currentTime = strings.Replace(currentTime, "-", "_", -1)
+	currentTime = strings.Replace(currentTime, " ", "_", -1)
+	currentTime = strings.Replace(currentTime, ":", "_", -1)
+
+	return fmt.Sprintf("%s_%s.%s", reportName, currentTime, fileExtension)
+}
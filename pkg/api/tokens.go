@@ -28,7 +28,7 @@ var (
 // TokenListHandler returns available token list of current user
 func (a *TokensApi) TokenListHandler(c *core.Context) (any, *errs.Error) {
 	uid := c.GetCurrentUid()
-	tokens, err := a.tokens.GetAllUnexpiredNormalTokensByUid(c, uid)
+	tokens, err := a.tokens.GetAllUnexpiredNormalAndPersonalAccessTokensByUid(c, uid)
 
 	if err != nil {
 		log.ErrorfWithRequestId(c, "[tokens.TokenListHandler] failed to get all tokens for user \"uid:%d\", because %s", uid, err.Error())
@@ -44,6 +44,8 @@ func (a *TokensApi) TokenListHandler(c *core.Context) (any, *errs.Error) {
 			TokenId:   a.tokens.GenerateTokenId(token),
 			TokenType: token.TokenType,
 			UserAgent: token.UserAgent,
+			Name:      token.Name,
+			Scopes:    token.GetScopes(),
 			CreatedAt: token.CreatedUnixTime,
 			ExpiredAt: token.ExpiredUnixTime,
 		}
@@ -60,6 +62,48 @@ func (a *TokensApi) TokenListHandler(c *core.Context) (any, *errs.Error) {
 	return tokenResps, nil
 }
 
+// PersonalAccessTokenCreateHandler creates a new personal access token for current user
+func (a *TokensApi) PersonalAccessTokenCreateHandler(c *core.Context) (any, *errs.Error) {
+	var tokenCreateReq models.PersonalAccessTokenCreateRequest
+	err := c.ShouldBindJSON(&tokenCreateReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[tokens.PersonalAccessTokenCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[tokens.PersonalAccessTokenCreateHandler] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
+		return nil, errs.ErrUserNotFound
+	}
+
+	token, tokenRecord, err := a.tokens.CreatePersonalAccessToken(c, user, tokenCreateReq.Name, tokenCreateReq.Scopes, tokenCreateReq.ExpiresInDays)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[tokens.PersonalAccessTokenCreateHandler] failed to create personal access token for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrTokenGenerating)
+	}
+
+	tokenId := a.tokens.GenerateTokenId(tokenRecord)
+
+	log.InfofWithRequestId(c, "[tokens.PersonalAccessTokenCreateHandler] user \"uid:%d\" has created personal access token \"id:%s\"", uid, tokenId)
+	recordUserAuditEvent(c, uid, models.USER_AUDIT_EVENT_TYPE_PERSONAL_ACCESS_TOKEN_CREATE, tokenRecord.Name)
+
+	tokenCreateResp := &models.PersonalAccessTokenCreateResponse{
+		Token:     token,
+		TokenId:   tokenId,
+		Name:      tokenRecord.Name,
+		Scopes:    tokenRecord.GetScopes(),
+		CreatedAt: tokenRecord.CreatedUnixTime,
+		ExpiredAt: tokenRecord.ExpiredUnixTime,
+	}
+
+	return tokenCreateResp, nil
+}
+
 // TokenRevokeCurrentHandler revokes current token of current user
 func (a *TokensApi) TokenRevokeCurrentHandler(c *core.Context) (any, *errs.Error) {
 	_, claims, err := a.tokens.ParseTokenByHeader(c)
@@ -90,6 +134,7 @@ func (a *TokensApi) TokenRevokeCurrentHandler(c *core.Context) (any, *errs.Error
 	}
 
 	log.InfofWithRequestId(c, "[token.TokenRevokeCurrentHandler] user \"uid:%d\" has revoked token \"id:%s\"", claims.Uid, tokenId)
+	recordUserAuditEvent(c, claims.Uid, models.USER_AUDIT_EVENT_TYPE_LOGOUT, "")
 	return true, nil
 }
 
@@ -128,10 +173,11 @@ func (a *TokensApi) TokenRevokeHandler(c *core.Context) (any, *errs.Error) {
 	}
 
 	log.InfofWithRequestId(c, "[token.TokenRevokeHandler] user \"uid:%d\" has revoked token \"id:%s\"", uid, tokenRevokeReq.TokenId)
+	recordUserAuditEvent(c, uid, models.USER_AUDIT_EVENT_TYPE_TOKEN_REVOKE, tokenRevokeReq.TokenId)
 	return true, nil
 }
 
-// TokenRevokeAllHandler revokes all tokens of current user except current token
+// TokenRevokeAllHandler revokes all tokens of current user except current token and personal access tokens
 func (a *TokensApi) TokenRevokeAllHandler(c *core.Context) (any, *errs.Error) {
 	uid := c.GetCurrentUid()
 	tokens, err := a.tokens.GetAllTokensByUid(c, uid)
@@ -154,8 +200,15 @@ func (a *TokensApi) TokenRevokeAllHandler(c *core.Context) (any, *errs.Error) {
 	}
 
 	tokens = append(tokens[:currentTokenIndex], tokens[currentTokenIndex+1:]...)
+	revokedTokens := make([]*models.TokenRecord, 0, len(tokens))
+
+	for i := 0; i < len(tokens); i++ {
+		if tokens[i].TokenType != core.USER_TOKEN_TYPE_PERSONAL_ACCESS {
+			revokedTokens = append(revokedTokens, tokens[i])
+		}
+	}
 
-	err = a.tokens.DeleteTokens(c, uid, tokens)
+	err = a.tokens.DeleteTokens(c, uid, revokedTokens)
 
 	if err != nil {
 		log.ErrorfWithRequestId(c, "[token.TokenRevokeAllHandler] failed to revoke all tokens for user \"uid:%d\", because %s", uid, err.Error())
@@ -163,6 +216,7 @@ func (a *TokensApi) TokenRevokeAllHandler(c *core.Context) (any, *errs.Error) {
 	}
 
 	log.InfofWithRequestId(c, "[token.TokenRevokeAllHandler] user \"uid:%d\" has revoked all tokens", uid)
+	recordUserAuditEvent(c, uid, models.USER_AUDIT_EVENT_TYPE_TOKEN_REVOKE_ALL, "")
 	return true, nil
 }
 
@@ -2,6 +2,7 @@ package api
 
 import (
 	"sort"
+	"time"
 
 	orderedmap "github.com/wk8/go-ordered-map/v2"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/kyy-me/ezbookkeeping/pkg/log"
 	"github.com/kyy-me/ezbookkeeping/pkg/models"
 	"github.com/kyy-me/ezbookkeeping/pkg/services"
+	"github.com/kyy-me/ezbookkeeping/pkg/settings"
 	"github.com/kyy-me/ezbookkeeping/pkg/utils"
 )
 
@@ -20,6 +22,8 @@ type TransactionsApi struct {
 	transactionTags       *services.TransactionTagService
 	accounts              *services.AccountService
 	users                 *services.UserService
+	exchangeRates         *services.ExchangeRateService
+	commodities           *services.CommodityService
 }
 
 // Initialize a transaction api singleton instance
@@ -30,6 +34,8 @@ var (
 		transactionTags:       services.TransactionTags,
 		accounts:              services.Accounts,
 		users:                 services.Users,
+		exchangeRates:         services.ExchangeRates,
+		commodities:           services.Commodities,
 	}
 )
 
@@ -142,7 +148,7 @@ func (a *TransactionsApi) TransactionListHandler(c *core.Context) (any, *errs.Er
 		transactions = transactions[:transactionListReq.Count]
 	}
 
-	transactionResult, err := a.getTransactionListResult(c, user, transactions, utcOffset, transactionListReq.TrimAccount, transactionListReq.TrimCategory, transactionListReq.TrimTag)
+	transactionResult, err := a.getTransactionListResult(c, user, transactions, utcOffset, transactionListReq.TrimAccount, transactionListReq.TrimCategory, transactionListReq.TrimTag, transactionListReq.WithConvertedAmount)
 
 	if err != nil {
 		log.ErrorfWithRequestId(c, "[transactions.TransactionListHandler] failed to assemble transaction result for user \"uid:%d\", because %s", uid, err.Error())
@@ -213,7 +219,7 @@ func (a *TransactionsApi) TransactionMonthListHandler(c *core.Context) (any, *er
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	transactionResult, err := a.getTransactionListResult(c, user, transactions, utcOffset, transactionListReq.TrimAccount, transactionListReq.TrimCategory, transactionListReq.TrimTag)
+	transactionResult, err := a.getTransactionListResult(c, user, transactions, utcOffset, transactionListReq.TrimAccount, transactionListReq.TrimCategory, transactionListReq.TrimTag, transactionListReq.WithConvertedAmount)
 
 	if err != nil {
 		log.ErrorfWithRequestId(c, "[transactions.TransactionMonthListHandler] failed to assemble transaction result for user \"uid:%d\", because %s", uid, err.Error())
@@ -246,7 +252,14 @@ func (a *TransactionsApi) TransactionStatisticsHandler(c *core.Context) (any, *e
 	}
 
 	uid := c.GetCurrentUid()
-	totalAmounts, err := a.transactions.GetAccountsAndCategoriesTotalIncomeAndExpense(c, uid, statisticReq.StartTime, statisticReq.EndTime, utcOffset, statisticReq.UseTransactionTimezone)
+	amountConverter, err := a.getExchangeRateAmountConverter(c, uid, &statisticReq.ExchangeRateConversionRequest, statisticReq.StartTime, statisticReq.EndTime)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transactions.TransactionStatisticsHandler] failed to get exchange rate amount converter for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	totalAmounts, err := a.transactions.GetAccountsAndCategoriesTotalIncomeAndExpense(c, uid, statisticReq.StartTime, statisticReq.EndTime, utcOffset, statisticReq.UseTransactionTimezone, amountConverter)
 
 	if err != nil {
 		log.ErrorfWithRequestId(c, "[transactions.TransactionStatisticsHandler] failed to get accounts and categories total income and expense for user \"uid:%d\", because %s", uid, err.Error())
@@ -258,6 +271,11 @@ func (a *TransactionsApi) TransactionStatisticsHandler(c *core.Context) (any, *e
 		EndTime:   statisticReq.EndTime,
 	}
 
+	if amountConverter != nil {
+		statisticResp.Currency = amountConverter.GetTargetCurrency()
+		statisticResp.ExchangeRates = amountConverter.GetConversionInfos(0)
+	}
+
 	statisticResp.Items = make([]*models.TransactionStatisticResponseItem, len(totalAmounts))
 
 	for i := 0; i < len(totalAmounts); i++ {
@@ -297,32 +315,91 @@ func (a *TransactionsApi) TransactionStatisticsTrendsHandler(c *core.Context) (a
 	}
 
 	uid := c.GetCurrentUid()
-	allMonthlyTotalAmounts, err := a.transactions.GetAccountsAndCategoriesMonthlyIncomeAndExpense(c, uid, startYear, startMonth, endYear, endMonth, utcOffset, statisticTrendsReq.UseTransactionTimezone)
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.ErrorfWithRequestId(c, "[transactions.TransactionStatisticsTrendsHandler] failed to get user, because %s", err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	period := &models.TransactionStatisticTrendsPeriod{
+		AggregationType:      statisticTrendsReq.AggregationType,
+		FirstDayOfWeek:       user.FirstDayOfWeek,
+		FiscalYearStartMonth: user.GetFiscalYearStartMonth(),
+		MonthStartDay:        user.GetMonthStartDay(),
+	}
+
+	var startUnixTime, endUnixTime int64
+
+	if startYear > 0 && startMonth > 0 {
+		startTransactionTime, _, err := utils.GetTransactionTimeRangeByYearMonth(startYear, startMonth)
+
+		if err != nil {
+			log.WarnfWithRequestId(c, "[transactions.TransactionStatisticsTrendsHandler] cannot get start time range, because %s", err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		startUnixTime = utils.GetUnixTimeFromTransactionTime(startTransactionTime)
+	}
+
+	if endYear > 0 && endMonth > 0 {
+		_, endTransactionTime, err := utils.GetTransactionTimeRangeByYearMonth(endYear, endMonth)
+
+		if err != nil {
+			log.WarnfWithRequestId(c, "[transactions.TransactionStatisticsTrendsHandler] cannot get end time range, because %s", err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		// the last month in range ends on the day before month start day of next month
+		endUnixTime = utils.GetUnixTimeFromTransactionTime(endTransactionTime) + int64(period.MonthStartDay-1)*24*60*60
+	}
+
+	amountConverter, err := a.getExchangeRateAmountConverter(c, uid, &statisticTrendsReq.ExchangeRateConversionRequest, startUnixTime, endUnixTime)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transactions.TransactionStatisticsTrendsHandler] failed to get exchange rate amount converter for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	allPeriodicTotalAmounts, err := a.transactions.GetAccountsAndCategoriesPeriodicIncomeAndExpense(c, uid, startYear, startMonth, endYear, endMonth, period, utcOffset, statisticTrendsReq.UseTransactionTimezone, amountConverter)
 
 	if err != nil {
 		log.ErrorfWithRequestId(c, "[transactions.TransactionStatisticsTrendsHandler] failed to get accounts and categories total income and expense for user \"uid:%d\", because %s", uid, err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	statisticTrendsResp := make(models.TransactionStatisticTrendsItemSlice, 0, len(allMonthlyTotalAmounts))
+	statisticTrendsResp := make(models.TransactionStatisticTrendsItemSlice, 0, len(allPeriodicTotalAmounts))
 
-	for yearMonth, monthlyTotalAmounts := range allMonthlyTotalAmounts {
-		monthlyStatisticResp := &models.TransactionStatisticTrendsItem{
-			Year:  yearMonth / 100,
-			Month: yearMonth % 100,
-			Items: make([]*models.TransactionStatisticResponseItem, len(monthlyTotalAmounts)),
+	for periodStartDate, periodicTotalAmounts := range allPeriodicTotalAmounts {
+		periodicStatisticResp := &models.TransactionStatisticTrendsItem{
+			Year:    periodStartDate / 10000,
+			Month:   periodStartDate / 100 % 100,
+			Quarter: period.GetQuarter(periodStartDate),
+			Items:   make([]*models.TransactionStatisticResponseItem, len(periodicTotalAmounts)),
 		}
 
-		for i := 0; i < len(monthlyTotalAmounts); i++ {
-			totalAmountItem := monthlyTotalAmounts[i]
-			monthlyStatisticResp.Items[i] = &models.TransactionStatisticResponseItem{
+		if period.AggregationType == models.TRANSACTION_STATISTIC_TRENDS_AGGREGATION_TYPE_DAY || period.AggregationType == models.TRANSACTION_STATISTIC_TRENDS_AGGREGATION_TYPE_WEEK || period.MonthStartDay > 1 {
+			periodicStatisticResp.Day = periodStartDate % 100
+		}
+
+		if amountConverter != nil {
+			periodicStatisticResp.Currency = amountConverter.GetTargetCurrency()
+			periodicStatisticResp.ExchangeRates = amountConverter.GetConversionInfos(periodStartDate)
+		}
+
+		for i := 0; i < len(periodicTotalAmounts); i++ {
+			totalAmountItem := periodicTotalAmounts[i]
+			periodicStatisticResp.Items[i] = &models.TransactionStatisticResponseItem{
 				CategoryId:  totalAmountItem.CategoryId,
 				AccountId:   totalAmountItem.AccountId,
 				TotalAmount: totalAmountItem.Amount,
 			}
 		}
 
-		statisticTrendsResp = append(statisticTrendsResp, monthlyStatisticResp)
+		statisticTrendsResp = append(statisticTrendsResp, periodicStatisticResp)
 	}
 
 	sort.Sort(statisticTrendsResp)
@@ -330,6 +407,190 @@ func (a *TransactionsApi) TransactionStatisticsTrendsHandler(c *core.Context) (a
 	return statisticTrendsResp, nil
 }
 
+// TransactionTagStatisticsHandler returns transaction statistics grouped by tag of current user
+func (a *TransactionsApi) TransactionTagStatisticsHandler(c *core.Context) (any, *errs.Error) {
+	var statisticReq models.TransactionStatisticRequest
+	err := c.ShouldBindQuery(&statisticReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[transactions.TransactionTagStatisticsHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	utcOffset, err := c.GetClientTimezoneOffset()
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[transactions.TransactionTagStatisticsHandler] cannot get client timezone offset, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	amountConverter, err := a.getExchangeRateAmountConverter(c, uid, &statisticReq.ExchangeRateConversionRequest, statisticReq.StartTime, statisticReq.EndTime)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transactions.TransactionTagStatisticsHandler] failed to get exchange rate amount converter for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	allTagTotalAmounts, err := a.transactions.GetTagsTotalIncomeAndExpense(c, uid, statisticReq.StartTime, statisticReq.EndTime, utcOffset, statisticReq.UseTransactionTimezone, amountConverter)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transactions.TransactionTagStatisticsHandler] failed to get tags total income and expense for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	statisticResp := &models.TransactionTagStatisticResponse{
+		StartTime: statisticReq.StartTime,
+		EndTime:   statisticReq.EndTime,
+		Items:     make(models.TransactionTagStatisticResponseItemSlice, 0),
+	}
+
+	if amountConverter != nil {
+		statisticResp.Currency = amountConverter.GetTargetCurrency()
+		statisticResp.ExchangeRates = amountConverter.GetConversionInfos(0)
+	}
+
+	for tagId, tagTotalAmounts := range allTagTotalAmounts {
+		for i := 0; i < len(tagTotalAmounts); i++ {
+			totalAmountItem := tagTotalAmounts[i]
+			transactionType := models.TRANSACTION_TYPE_EXPENSE
+
+			if totalAmountItem.Type == models.TRANSACTION_DB_TYPE_INCOME {
+				transactionType = models.TRANSACTION_TYPE_INCOME
+			}
+
+			statisticResp.Items = append(statisticResp.Items, &models.TransactionTagStatisticResponseItem{
+				TagId:       tagId,
+				AccountId:   totalAmountItem.AccountId,
+				Type:        transactionType,
+				TotalAmount: totalAmountItem.Amount,
+			})
+		}
+	}
+
+	sort.Sort(statisticResp.Items)
+
+	return statisticResp, nil
+}
+
+// TransactionGeoStatisticsHandler returns clustered expense amounts in geographic range of current user
+func (a *TransactionsApi) TransactionGeoStatisticsHandler(c *core.Context) (any, *errs.Error) {
+	var statisticReq models.TransactionGeoStatisticRequest
+	err := c.ShouldBindQuery(&statisticReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[transactions.TransactionGeoStatisticsHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	minLongitude := statisticReq.MinLongitude
+	maxLongitude := statisticReq.MaxLongitude
+	minLatitude := statisticReq.MinLatitude
+	maxLatitude := statisticReq.MaxLatitude
+
+	if minLongitude == 0 && maxLongitude == 0 && minLatitude == 0 && maxLatitude == 0 {
+		minLongitude, maxLongitude, minLatitude, maxLatitude = -180, 180, -90, 90
+	}
+
+	uid := c.GetCurrentUid()
+	conversionReq := statisticReq.ExchangeRateConversionRequest
+
+	if conversionReq.TargetCurrency == "" {
+		user, err := a.users.GetUserById(c, uid)
+
+		if err != nil {
+			if !errs.IsCustomError(err) {
+				log.ErrorfWithRequestId(c, "[transactions.TransactionGeoStatisticsHandler] failed to get user, because %s", err.Error())
+			}
+
+			return nil, errs.ErrUserNotFound
+		}
+
+		conversionReq.TargetCurrency = user.DefaultCurrency
+	}
+
+	amountConverter, err := a.getExchangeRateAmountConverter(c, uid, &conversionReq, statisticReq.StartTime, statisticReq.EndTime)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transactions.TransactionGeoStatisticsHandler] failed to get exchange rate amount converter for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	transactions, err := a.transactions.GetExpenseTransactionsInGeoRange(c, uid, statisticReq.StartTime, statisticReq.EndTime, minLongitude, maxLongitude, minLatitude, maxLatitude)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transactions.TransactionGeoStatisticsHandler] failed to get expense transactions in geographic range for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	longitudeSpan := maxLongitude - minLongitude
+
+	if longitudeSpan < 0 {
+		longitudeSpan += 360
+	}
+
+	latitudeSpan := maxLatitude - minLatitude
+	gridSize := int64(statisticReq.GridSize)
+	clusters := make(map[int64]*models.TransactionGeoClusterResponse)
+	longitudeSums := make(map[int64]float64)
+	latitudeSums := make(map[int64]float64)
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+		transactionTimezone := time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
+		transactionDate := utils.FormatUnixTimeToNumericDate(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime), transactionTimezone)
+		amount, err := amountConverter.ConvertAccountAmount(0, transaction.AccountId, transaction.Amount, transactionDate)
+
+		if err != nil {
+			log.ErrorfWithRequestId(c, "[transactions.TransactionGeoStatisticsHandler] failed to convert amount of account \"id:%d\" for user \"uid:%d\", because %s", transaction.AccountId, uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		longitudeOffset := transaction.GeoLongitude - minLongitude
+
+		if longitudeOffset < 0 {
+			longitudeOffset += 360
+		}
+
+		clusterKey := a.getGeoGridIndex(longitudeOffset, longitudeSpan, gridSize)*gridSize + a.getGeoGridIndex(transaction.GeoLatitude-minLatitude, latitudeSpan, gridSize)
+		cluster, exists := clusters[clusterKey]
+
+		if !exists {
+			cluster = &models.TransactionGeoClusterResponse{}
+			clusters[clusterKey] = cluster
+		}
+
+		cluster.Count++
+		cluster.TotalAmount += amount
+		longitudeSums[clusterKey] += longitudeOffset
+		latitudeSums[clusterKey] += transaction.GeoLatitude
+	}
+
+	statisticResp := &models.TransactionGeoStatisticResponse{
+		StartTime:     statisticReq.StartTime,
+		EndTime:       statisticReq.EndTime,
+		Currency:      amountConverter.GetTargetCurrency(),
+		ExchangeRates: amountConverter.GetConversionInfos(0),
+		Clusters:      make(models.TransactionGeoClusterResponseSlice, 0, len(clusters)),
+	}
+
+	for clusterKey, cluster := range clusters {
+		longitude := minLongitude + longitudeSums[clusterKey]/float64(cluster.Count)
+
+		if longitude > 180 {
+			longitude -= 360
+		}
+
+		cluster.Longitude = longitude
+		cluster.Latitude = latitudeSums[clusterKey] / float64(cluster.Count)
+		statisticResp.Clusters = append(statisticResp.Clusters, cluster)
+	}
+
+	sort.Sort(statisticResp.Clusters)
+
+	return statisticResp, nil
+}
+
 // TransactionAmountsHandler returns transaction amounts of current user
 func (a *TransactionsApi) TransactionAmountsHandler(c *core.Context) (any, *errs.Error) {
 	var transactionAmountsReq models.TransactionAmountsRequest
@@ -374,12 +635,51 @@ func (a *TransactionsApi) TransactionAmountsHandler(c *core.Context) (any, *errs
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	var exchangeRateHistory *models.ExchangeRateHistory
+	var exchangeRateFixedDate int32
+	var commodityMap map[string]*models.Commodity
+
+	if transactionAmountsReq.IsConversionRequired() {
+		commodityMap, err = a.commodities.GetCommodityMapOfAccounts(c, uid, accounts)
+
+		if err != nil {
+			log.ErrorfWithRequestId(c, "[transactions.TransactionAmountsHandler] failed to get commodities of accounts for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		minStartTime := requestItems[0].StartTime
+		maxEndTime := requestItems[0].EndTime
+
+		for i := 1; i < len(requestItems); i++ {
+			if requestItems[i].StartTime < minStartTime {
+				minStartTime = requestItems[i].StartTime
+			}
+
+			if requestItems[i].EndTime > maxEndTime {
+				maxEndTime = requestItems[i].EndTime
+			}
+		}
+
+		exchangeRateHistory, exchangeRateFixedDate, err = a.getExchangeRateHistory(c, &transactionAmountsReq.ExchangeRateConversionRequest, minStartTime, maxEndTime)
+
+		if err != nil {
+			log.ErrorfWithRequestId(c, "[transactions.TransactionAmountsHandler] failed to get exchange rate history for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
 	amountsResp := orderedmap.New[string, *models.TransactionAmountsResponseItem]()
 
 	for i := 0; i < len(requestItems); i++ {
 		requestItem := requestItems[i]
+		var amountConverter *models.ExchangeRateAmountConverter
 
-		incomeAmounts, expenseAmounts, err := a.transactions.GetAccountsTotalIncomeAndExpense(c, uid, requestItem.StartTime, requestItem.EndTime, utcOffset, transactionAmountsReq.UseTransactionTimezone)
+		if exchangeRateHistory != nil {
+			amountConverter = models.NewExchangeRateAmountConverter(exchangeRateHistory, transactionAmountsReq.TargetCurrency, exchangeRateFixedDate, accountMap, getExchangeRatesRoundingMode())
+			amountConverter.SetCommodities(commodityMap)
+		}
+
+		incomeAmounts, expenseAmounts, err := a.transactions.GetAccountsTotalIncomeAndExpense(c, uid, requestItem.StartTime, requestItem.EndTime, utcOffset, transactionAmountsReq.UseTransactionTimezone, amountConverter)
 
 		if err != nil {
 			log.ErrorfWithRequestId(c, "[transactions.TransactionAmountsHandler] failed to get transaction amounts item for user \"uid:%d\", because %s", uid, err.Error())
@@ -396,18 +696,24 @@ func (a *TransactionsApi) TransactionAmountsHandler(c *core.Context) (any, *errs
 				continue
 			}
 
-			totalAmounts, exists := amountsMap[account.Currency]
+			currency := account.Currency
+
+			if amountConverter != nil {
+				currency = amountConverter.GetTargetCurrency()
+			}
+
+			totalAmounts, exists := amountsMap[currency]
 
 			if !exists {
 				totalAmounts = &models.TransactionAmountsResponseItemAmountInfo{
-					Currency:      account.Currency,
+					Currency:      currency,
 					IncomeAmount:  0,
 					ExpenseAmount: 0,
 				}
 			}
 
 			totalAmounts.IncomeAmount += incomeAmount
-			amountsMap[account.Currency] = totalAmounts
+			amountsMap[currency] = totalAmounts
 		}
 
 		for accountId, expenseAmount := range expenseAmounts {
@@ -418,18 +724,24 @@ func (a *TransactionsApi) TransactionAmountsHandler(c *core.Context) (any, *errs
 				continue
 			}
 
-			totalAmounts, exists := amountsMap[account.Currency]
+			currency := account.Currency
+
+			if amountConverter != nil {
+				currency = amountConverter.GetTargetCurrency()
+			}
+
+			totalAmounts, exists := amountsMap[currency]
 
 			if !exists {
 				totalAmounts = &models.TransactionAmountsResponseItemAmountInfo{
-					Currency:      account.Currency,
+					Currency:      currency,
 					IncomeAmount:  0,
 					ExpenseAmount: 0,
 				}
 			}
 
 			totalAmounts.ExpenseAmount += expenseAmount
-			amountsMap[account.Currency] = totalAmounts
+			amountsMap[currency] = totalAmounts
 		}
 
 		allTotalAmounts := make(models.TransactionAmountsResponseItemAmountInfoSlice, 0)
@@ -440,11 +752,17 @@ func (a *TransactionsApi) TransactionAmountsHandler(c *core.Context) (any, *errs
 
 		sort.Sort(allTotalAmounts)
 
-		amountsResp.Set(requestItem.Name, &models.TransactionAmountsResponseItem{
+		amountsRespItem := &models.TransactionAmountsResponseItem{
 			StartTime: requestItem.StartTime,
 			EndTime:   requestItem.EndTime,
 			Amounts:   allTotalAmounts,
-		})
+		}
+
+		if amountConverter != nil {
+			amountsRespItem.ExchangeRates = amountConverter.GetConversionInfos(0)
+		}
+
+		amountsResp.Set(requestItem.Name, amountsRespItem)
 	}
 
 	return amountsResp, nil
@@ -563,6 +881,15 @@ func (a *TransactionsApi) TransactionGetHandler(c *core.Context) (any, *errs.Err
 		transactionResp.Tags = a.getTransactionTagInfoResponses(transactionTagIds, tagMap)
 	}
 
+	if transactionGetReq.WithConvertedAmount {
+		err = a.setTransactionConvertedAmounts(c, user, []*models.TransactionInfoResponse{transactionResp}, accountMap)
+
+		if err != nil {
+			log.ErrorfWithRequestId(c, "[transactions.TransactionGetHandler] failed to convert transaction amount for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
 	return transactionResp, nil
 }
 
@@ -839,6 +1166,103 @@ func (a *TransactionsApi) filterTransactions(c *core.Context, uid int64, transac
 	return finalTransactions
 }
 
+func (a *TransactionsApi) getExchangeRateAmountConverter(c *core.Context, uid int64, conversionReq *models.ExchangeRateConversionRequest, startUnixTime int64, endUnixTime int64) (*models.ExchangeRateAmountConverter, error) {
+	if !conversionReq.IsConversionRequired() {
+		return nil, nil
+	}
+
+	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		return nil, err
+	}
+
+	accountMap := a.accounts.GetAccountMapByList(accounts)
+	commodityMap, err := a.commodities.GetCommodityMapOfAccounts(c, uid, accounts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	exchangeRatesRequired := false
+
+	for i := 0; i < len(accounts); i++ {
+		if getAccountPriceCurrency(accounts[i], commodityMap) != conversionReq.TargetCurrency {
+			exchangeRatesRequired = true
+			break
+		}
+	}
+
+	// amounts in target currency are never converted, so the exchange rates are not required when all accounts are in target currency
+	if !exchangeRatesRequired {
+		amountConverter := models.NewExchangeRateAmountConverter(nil, conversionReq.TargetCurrency, 0, accountMap, getExchangeRatesRoundingMode())
+		amountConverter.SetCommodities(commodityMap)
+
+		return amountConverter, nil
+	}
+
+	exchangeRateHistory, fixedDate, err := a.getExchangeRateHistory(c, conversionReq, startUnixTime, endUnixTime)
+
+	if err != nil {
+		return nil, err
+	}
+
+	amountConverter := models.NewExchangeRateAmountConverter(exchangeRateHistory, conversionReq.TargetCurrency, fixedDate, accountMap, getExchangeRatesRoundingMode())
+	amountConverter.SetCommodities(commodityMap)
+
+	return amountConverter, nil
+}
+
+func (a *TransactionsApi) getExchangeRateHistory(c *core.Context, conversionReq *models.ExchangeRateConversionRequest, startUnixTime int64, endUnixTime int64) (*models.ExchangeRateHistory, int32, error) {
+	fixedDate, err := conversionReq.GetNumericExchangeRateDate()
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var startDate, endDate int32
+
+	if fixedDate > 0 {
+		startDate = fixedDate
+		endDate = fixedDate
+	} else {
+		utcTimezone := time.FixedZone("UTC", 0)
+
+		// transactions may be in any timezone, so the date range of exchange rates is extended by one day at each end
+		if startUnixTime > 0 {
+			startDate = utils.FormatUnixTimeToNumericDate(startUnixTime-24*60*60, utcTimezone)
+		}
+
+		if endUnixTime > 0 {
+			endDate = utils.FormatUnixTimeToNumericDate(endUnixTime+24*60*60, utcTimezone)
+		}
+	}
+
+	exchangeRateHistory, err := a.exchangeRates.GetMergedExchangeRateHistory(c, settings.Container.Current.ExchangeRatesDataSources, startDate, endDate)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return exchangeRateHistory, fixedDate, nil
+}
+
+func (a *TransactionsApi) getGeoGridIndex(offset float64, span float64, gridSize int64) int64 {
+	if span <= 0 {
+		return 0
+	}
+
+	index := int64(offset / span * float64(gridSize))
+
+	if index < 0 {
+		return 0
+	} else if index >= gridSize {
+		return gridSize - 1
+	}
+
+	return index
+}
+
 func (a *TransactionsApi) getAccountOrSubAccountIds(c *core.Context, accountId int64, uid int64) ([]int64, error) {
 	var allAccountIds []int64
 
@@ -909,7 +1333,7 @@ func (a *TransactionsApi) getTransactionTagInfoResponses(tagIds []int64, allTran
 	return allTags
 }
 
-func (a *TransactionsApi) getTransactionListResult(c *core.Context, user *models.User, transactions []*models.Transaction, utcOffset int16, trimAccount bool, trimCategory bool, trimTag bool) (models.TransactionInfoResponseSlice, error) {
+func (a *TransactionsApi) getTransactionListResult(c *core.Context, user *models.User, transactions []*models.Transaction, utcOffset int16, trimAccount bool, trimCategory bool, trimTag bool, withConvertedAmount bool) (models.TransactionInfoResponseSlice, error) {
 	uid := user.Uid
 	transactionIds := make([]int64, len(transactions))
 	accountIds := make([]int64, 0, len(transactions)*2)
@@ -1003,11 +1427,122 @@ func (a *TransactionsApi) getTransactionListResult(c *core.Context, user *models
 		}
 	}
 
+	if withConvertedAmount {
+		err = a.setTransactionConvertedAmounts(c, user, result, allAccounts)
+
+		if err != nil {
+			log.ErrorfWithRequestId(c, "[transactions.getTransactionListResult] failed to convert transaction amounts for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, err
+		}
+	}
+
 	sort.Sort(result)
 
 	return result, nil
 }
 
+func (a *TransactionsApi) setTransactionConvertedAmounts(c *core.Context, user *models.User, transactionResps []*models.TransactionInfoResponse, accountMap map[int64]*models.Account) error {
+	targetCurrency := user.DefaultCurrency
+
+	if targetCurrency == "" {
+		return nil
+	}
+
+	accounts := make([]*models.Account, 0, len(accountMap))
+
+	for _, account := range accountMap {
+		accounts = append(accounts, account)
+	}
+
+	commodityMap, err := a.commodities.GetCommodityMapOfAccounts(c, user.Uid, accounts)
+
+	if err != nil {
+		return err
+	}
+
+	transactionDates := make([]int32, len(transactionResps))
+	sourceAmounts := make([]int64, len(transactionResps))
+	sourceCurrencies := make([]string, len(transactionResps))
+	minDate := int32(0)
+	maxDate := int32(0)
+
+	for i := 0; i < len(transactionResps); i++ {
+		transactionResp := transactionResps[i]
+		account := accountMap[transactionResp.SourceAccountId]
+
+		if account == nil {
+			continue
+		}
+
+		sourceAmounts[i] = transactionResp.SourceAmount
+		sourceCurrencies[i] = account.Currency
+
+		// the amount in commodity is converted into its price currency by the current price at first
+		if commodity, exists := commodityMap[account.Currency]; exists {
+			priceCurrencyAmount, err := commodity.ConvertAmountToPriceCurrency(transactionResp.SourceAmount, getExchangeRatesRoundingMode())
+
+			if err != nil {
+				log.WarnfWithRequestId(c, "[transactions.setTransactionConvertedAmounts] cannot convert amount of transaction \"id:%d\" from commodity \"%s\", because %s", transactionResp.Id, account.Currency, err.Error())
+				continue
+			}
+
+			sourceAmounts[i] = priceCurrencyAmount
+			sourceCurrencies[i] = commodity.PriceCurrency
+		}
+
+		if sourceCurrencies[i] == targetCurrency {
+			convertedAmount := sourceAmounts[i]
+			transactionResp.ConvertedAmount = &convertedAmount
+			transactionResp.ConvertedCurrency = targetCurrency
+			continue
+		}
+
+		transactionTimezone := time.FixedZone("Transaction Timezone", int(transactionResp.UtcOffset)*60)
+		transactionDates[i] = utils.FormatUnixTimeToNumericDate(transactionResp.Time, transactionTimezone)
+
+		if minDate == 0 || transactionDates[i] < minDate {
+			minDate = transactionDates[i]
+		}
+
+		if transactionDates[i] > maxDate {
+			maxDate = transactionDates[i]
+		}
+	}
+
+	// all transactions are in target currency or their accounts do not exist
+	if maxDate == 0 {
+		return nil
+	}
+
+	exchangeRateHistory, err := a.exchangeRates.GetMergedExchangeRateHistory(c, settings.Container.Current.ExchangeRatesDataSources, minDate, maxDate)
+
+	if err == errs.ErrExchangeRateNotFound {
+		log.WarnfWithRequestId(c, "[transactions.setTransactionConvertedAmounts] there is no exchange rate between \"%d\" and \"%d\"", minDate, maxDate)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(transactionResps); i++ {
+		if transactionDates[i] == 0 {
+			continue
+		}
+
+		transactionResp := transactionResps[i]
+		convertedAmount, err := exchangeRateHistory.ConvertAmount(sourceAmounts[i], sourceCurrencies[i], targetCurrency, transactionDates[i], getExchangeRatesRoundingMode())
+
+		if err != nil {
+			log.WarnfWithRequestId(c, "[transactions.setTransactionConvertedAmounts] cannot convert amount of transaction \"id:%d\" from \"%s\" to \"%s\", because %s", transactionResp.Id, sourceCurrencies[i], targetCurrency, err.Error())
+			continue
+		}
+
+		transactionResp.ConvertedAmount = &convertedAmount
+		transactionResp.ConvertedCurrency = targetCurrency
+	}
+
+	return nil
+}
+
 func (a *TransactionsApi) createNewTransactionModel(uid int64, transactionCreateReq *models.TransactionCreateRequest, clientIp string) *models.Transaction {
 	var transactionDbType models.TransactionDbType
 
@@ -173,6 +173,7 @@ func (a *TwoFactorAuthorizationsApi) TwoFactorEnableConfirmHandler(c *core.Conte
 	}
 
 	log.InfofWithRequestId(c, "[twofactor_authorizations.TwoFactorEnableConfirmHandler] user \"uid:%d\" has enabled two-factor authorization", uid)
+	recordUserAuditEvent(c, uid, models.USER_AUDIT_EVENT_TYPE_TWO_FACTOR_ENABLE, "")
 
 	now := time.Now().Unix()
 	err = a.tokens.DeleteTokensBeforeTime(c, uid, now)
@@ -259,6 +260,7 @@ func (a *TwoFactorAuthorizationsApi) TwoFactorDisableHandler(c *core.Context) (a
 	}
 
 	log.InfofWithRequestId(c, "[twofactor_authorizations.TwoFactorDisableHandler] user \"uid:%d\" has disabled two-factor authorization", uid)
+	recordUserAuditEvent(c, uid, models.USER_AUDIT_EVENT_TYPE_TWO_FACTOR_DISABLE, "")
 
 	return true, nil
 }
@@ -318,6 +320,7 @@ func (a *TwoFactorAuthorizationsApi) TwoFactorRecoveryCodeRegenerateHandler(c *c
 	}
 
 	log.InfofWithRequestId(c, "[twofactor_authorizations.TwoFactorRecoveryCodeRegenerateHandler] user \"uid:%d\" has regenerated two-factor recovery codes", uid)
+	recordUserAuditEvent(c, uid, models.USER_AUDIT_EVENT_TYPE_TWO_FACTOR_RECOVERY_CODE_REGENERATE, "")
 
 	return recoveryCodesResp, nil
 }
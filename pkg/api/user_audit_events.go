@@ -0,0 +1,68 @@
+package api
+
+import (
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/log"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+	"github.com/kyy-me/ezbookkeeping/pkg/services"
+)
+
+// UserAuditEventsApi represents user audit event api
+type UserAuditEventsApi struct {
+	userAuditEvents *services.UserAuditEventService
+}
+
+// Initialize a user audit event api singleton instance
+var (
+	UserAuditEvents = &UserAuditEventsApi{
+		userAuditEvents: services.UserAuditEvents,
+	}
+)
+
+// UserAuditEventListHandler returns the audit events of current user
+func (a *UserAuditEventsApi) UserAuditEventListHandler(c *core.Context) (any, *errs.Error) {
+	var listReq models.UserAuditEventListRequest
+	err := c.ShouldBindQuery(&listReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[user_audit_events.UserAuditEventListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	events, err := a.userAuditEvents.GetEventsByUid(c, uid, listReq.MaxId, listReq.Count+1)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[user_audit_events.UserAuditEventListHandler] failed to get audit events for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	finalCount := len(events)
+	var nextMaxId *int64
+
+	if finalCount > int(listReq.Count) {
+		finalCount = int(listReq.Count)
+		nextMaxId = &events[finalCount].EventId
+	}
+
+	eventResps := make([]*models.UserAuditEventInfoResponse, finalCount)
+
+	for i := 0; i < finalCount; i++ {
+		eventResps[i] = events[i].ToUserAuditEventInfoResponse()
+	}
+
+	return &models.UserAuditEventInfoPageWrapperResponse{
+		Items:     eventResps,
+		NextMaxId: nextMaxId,
+	}, nil
+}
+
+// recordUserAuditEvent appends an audit event of user, the failure of recording would not interrupt the request
+func recordUserAuditEvent(c *core.Context, uid int64, eventType models.UserAuditEventType, details string) {
+	err := services.UserAuditEvents.CreateEvent(c, uid, eventType, details)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[user_audit_events.recordUserAuditEvent] failed to record audit event \"%s\" for user \"uid:%d\", because %s", eventType, uid, err.Error())
+	}
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
@@ -18,17 +19,21 @@ import (
 
 // UsersApi represents user api
 type UsersApi struct {
-	users    *services.UserService
-	tokens   *services.TokenService
-	accounts *services.AccountService
+	users           *services.UserService
+	tokens          *services.TokenService
+	accounts        *services.AccountService
+	invitationCodes *services.InvitationCodeService
+	authorizations  *AuthorizationsApi
 }
 
 // Initialize a user api singleton instance
 var (
 	Users = &UsersApi{
-		users:    services.Users,
-		tokens:   services.Tokens,
-		accounts: services.Accounts,
+		users:           services.Users,
+		tokens:          services.Tokens,
+		accounts:        services.Accounts,
+		invitationCodes: services.InvitationCodes,
+		authorizations:  Authorizations,
 	}
 )
 
@@ -54,6 +59,22 @@ func (a *UsersApi) UserRegisterHandler(c *core.Context) (any, *errs.Error) {
 	userRegisterReq.Username = strings.TrimSpace(userRegisterReq.Username)
 	userRegisterReq.Email = strings.TrimSpace(userRegisterReq.Email)
 	userRegisterReq.Nickname = strings.TrimSpace(userRegisterReq.Nickname)
+	userRegisterReq.InvitationCode = strings.TrimSpace(userRegisterReq.InvitationCode)
+
+	var invitationCode *models.InvitationCode
+
+	if settings.Container.Current.RegisterRequireInvitationCode {
+		if userRegisterReq.InvitationCode == "" {
+			return nil, errs.ErrInvitationCodeIsEmpty
+		}
+
+		invitationCode, err = a.invitationCodes.ConsumeInvitationCode(c, userRegisterReq.InvitationCode, userRegisterReq.Email)
+
+		if err != nil {
+			log.WarnfWithRequestId(c, "[users.UserRegisterHandler] failed to use invitation code for user \"%s\", because %s", userRegisterReq.Username, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
 
 	user := &models.User{
 		Username:             userRegisterReq.Username,
@@ -66,15 +87,32 @@ func (a *UsersApi) UserRegisterHandler(c *core.Context) (any, *errs.Error) {
 		TransactionEditScope: models.TRANSACTION_EDIT_SCOPE_ALL,
 	}
 
+	if invitationCode != nil {
+		user.InvitationCodeId = invitationCode.CodeId
+	}
+
 	err = a.users.CreateUser(c, user)
 
 	if err != nil {
 		log.ErrorfWithRequestId(c, "[users.UserRegisterHandler] failed to create user \"%s\", because %s", user.Username, err.Error())
+
+		if invitationCode != nil {
+			if releaseErr := a.invitationCodes.ReleaseInvitationCode(c, invitationCode.CodeId); releaseErr != nil {
+				log.WarnfWithRequestId(c, "[users.UserRegisterHandler] failed to release invitation code \"id:%d\", because %s", invitationCode.CodeId, releaseErr.Error())
+			}
+		}
+
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
 	log.InfofWithRequestId(c, "[users.UserRegisterHandler] user \"%s\" has registered successfully, uid is %d", user.Username, user.Uid)
 
+	if invitationCode != nil {
+		recordUserAuditEvent(c, user.Uid, models.USER_AUDIT_EVENT_TYPE_REGISTER, fmt.Sprintf("invitation code \"id:%d\"", invitationCode.CodeId))
+	} else {
+		recordUserAuditEvent(c, user.Uid, models.USER_AUDIT_EVENT_TYPE_REGISTER, "")
+	}
+
 	presetCategoriesSaved := false
 
 	if len(userRegisterReq.Categories) > 0 {
@@ -234,15 +272,22 @@ func (a *UsersApi) UserUpdateProfileHandler(c *core.Context) (any, *errs.Error)
 	userUpdateReq.Nickname = strings.TrimSpace(userUpdateReq.Nickname)
 
 	anythingUpdate := false
+	oldEmail := user.Email
+	pendingEmail := ""
 	userNew := &models.User{
 		Uid:  user.Uid,
 		Salt: user.Salt,
 	}
 
 	if userUpdateReq.Email != "" && userUpdateReq.Email != user.Email {
-		user.Email = userUpdateReq.Email
-		userNew.Email = userUpdateReq.Email
-		anythingUpdate = true
+		if settings.Container.Current.EnableSMTP {
+			// The new email address would not take effect until it is confirmed by the token sent to it
+			pendingEmail = userUpdateReq.Email
+		} else {
+			user.Email = userUpdateReq.Email
+			userNew.Email = userUpdateReq.Email
+			anythingUpdate = true
+		}
 	}
 
 	if userUpdateReq.Password != "" {
@@ -305,6 +350,18 @@ func (a *UsersApi) UserUpdateProfileHandler(c *core.Context) (any, *errs.Error)
 		userNew.FirstDayOfWeek = models.WEEKDAY_INVALID
 	}
 
+	if userUpdateReq.FiscalYearStartMonth != nil && *userUpdateReq.FiscalYearStartMonth != user.GetFiscalYearStartMonth() {
+		user.FiscalYearStartMonth = *userUpdateReq.FiscalYearStartMonth
+		userNew.FiscalYearStartMonth = *userUpdateReq.FiscalYearStartMonth
+		anythingUpdate = true
+	}
+
+	if userUpdateReq.MonthStartDay != nil && *userUpdateReq.MonthStartDay != user.GetMonthStartDay() {
+		user.MonthStartDay = *userUpdateReq.MonthStartDay
+		userNew.MonthStartDay = *userUpdateReq.MonthStartDay
+		anythingUpdate = true
+	}
+
 	if userUpdateReq.LongDateFormat != nil && *userUpdateReq.LongDateFormat != user.LongDateFormat {
 		user.LongDateFormat = *userUpdateReq.LongDateFormat
 		userNew.LongDateFormat = *userUpdateReq.LongDateFormat
@@ -396,15 +453,31 @@ func (a *UsersApi) UserUpdateProfileHandler(c *core.Context) (any, *errs.Error)
 		}
 	}
 
-	if !anythingUpdate {
+	if !anythingUpdate && pendingEmail == "" {
 		return nil, errs.ErrNothingWillBeUpdated
 	}
 
-	keyProfileUpdated, emailSetToUnverified, err := a.users.UpdateUser(c, userNew, modifyUserLanguage)
+	if pendingEmail != "" {
+		err = a.users.SetUserPendingEmail(c, user.Uid, pendingEmail)
 
-	if err != nil {
-		log.ErrorfWithRequestId(c, "[users.UserUpdateProfileHandler] failed to update user \"uid:%d\", because %s", user.Uid, err.Error())
-		return nil, errs.Or(err, errs.ErrOperationFailed)
+		if err != nil {
+			log.ErrorfWithRequestId(c, "[users.UserUpdateProfileHandler] failed to save pending email for user \"uid:%d\", because %s", user.Uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		user.PendingEmail = pendingEmail
+	}
+
+	keyProfileUpdated := false
+	emailSetToUnverified := false
+
+	if anythingUpdate {
+		keyProfileUpdated, emailSetToUnverified, err = a.users.UpdateUser(c, userNew, modifyUserLanguage)
+
+		if err != nil {
+			log.ErrorfWithRequestId(c, "[users.UserUpdateProfileHandler] failed to update user \"uid:%d\", because %s", user.Uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
 	}
 
 	if emailSetToUnverified {
@@ -413,8 +486,21 @@ func (a *UsersApi) UserUpdateProfileHandler(c *core.Context) (any, *errs.Error)
 
 	log.InfofWithRequestId(c, "[users.UserUpdateProfileHandler] user \"uid:%d\" has updated successfully", user.Uid)
 
+	if userNew.Email != "" {
+		recordUserAuditEvent(c, user.Uid, models.USER_AUDIT_EVENT_TYPE_EMAIL_CHANGE, fmt.Sprintf("%s -> %s", oldEmail, userNew.Email))
+	}
+
+	if userNew.Password != "" {
+		recordUserAuditEvent(c, user.Uid, models.USER_AUDIT_EVENT_TYPE_PASSWORD_CHANGE, "")
+	}
+
 	resp := &models.UserProfileUpdateResponse{
-		User: user.ToUserBasicInfo(),
+		User:         user.ToUserBasicInfo(),
+		PendingEmail: user.PendingEmail,
+	}
+
+	if pendingEmail != "" {
+		a.sendEmailChangeConfirmEmail(c, user, oldEmail, pendingEmail)
 	}
 
 	if emailSetToUnverified && settings.Container.Current.EnableUserVerifyEmail && settings.Container.Current.EnableSMTP {
@@ -468,6 +554,112 @@ func (a *UsersApi) UserUpdateProfileHandler(c *core.Context) (any, *errs.Error)
 	return resp, nil
 }
 
+// UserEmailChangeConfirmHandler replaces the email address of current user with the pending email address
+func (a *UsersApi) UserEmailChangeConfirmHandler(c *core.Context) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.ErrorfWithRequestId(c, "[users.UserEmailChangeConfirmHandler] failed to get user, because %s", err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	if user.Disabled {
+		log.WarnfWithRequestId(c, "[users.UserEmailChangeConfirmHandler] user \"uid:%d\" is disabled", user.Uid)
+		return nil, errs.ErrUserIsDisabled
+	}
+
+	oldEmail, newEmail, err := a.users.ConfirmUserPendingEmail(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[users.UserEmailChangeConfirmHandler] failed to confirm pending email for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[users.UserEmailChangeConfirmHandler] user \"uid:%d\" has changed email address", user.Uid)
+	recordUserAuditEvent(c, user.Uid, models.USER_AUDIT_EVENT_TYPE_EMAIL_CHANGE, fmt.Sprintf("%s -> %s", oldEmail, newEmail))
+
+	err = a.tokens.DeleteTokensByType(c, uid, core.USER_TOKEN_TYPE_EMAIL_CHANGE)
+
+	if err == nil {
+		log.InfofWithRequestId(c, "[users.UserEmailChangeConfirmHandler] revoke old email change tokens for user \"uid:%d\"", user.Uid)
+	} else {
+		log.WarnfWithRequestId(c, "[users.UserEmailChangeConfirmHandler] failed to revoke old email change tokens for user \"uid:%d\", because %s", user.Uid, err.Error())
+	}
+
+	return true, nil
+}
+
+// UserAccountDeletionRequestHandler schedules the deletion of current user after re-authentication
+func (a *UsersApi) UserAccountDeletionRequestHandler(c *core.Context) (any, *errs.Error) {
+	if !settings.Container.Current.EnableUserAccountDeletion {
+		return nil, errs.ErrAccountDeletionNotAllowed
+	}
+
+	var deletionReq models.UserAccountDeletionRequest
+	err := c.ShouldBindJSON(&deletionReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[users.UserAccountDeletionRequestHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.ErrorfWithRequestId(c, "[users.UserAccountDeletionRequestHandler] failed to get user, because %s", err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	if user.DeletionScheduledUnixTime > 0 {
+		return nil, errs.ErrAccountDeletionAlreadyScheduled
+	}
+
+	errResult := a.authorizations.reauthenticateUser(c, user, deletionReq.Password, deletionReq.Passcode, deletionReq.WebAuthnCredential)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	deletionScheduledUnixTime := time.Now().Add(settings.Container.Current.AccountDeletionGracePeriodDuration).Unix()
+	err = a.users.ScheduleUserDeletion(c, uid, deletionScheduledUnixTime)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[users.UserAccountDeletionRequestHandler] failed to schedule deletion for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[users.UserAccountDeletionRequestHandler] user \"uid:%d\" would be deleted at %d", uid, deletionScheduledUnixTime)
+	recordUserAuditEvent(c, uid, models.USER_AUDIT_EVENT_TYPE_ACCOUNT_DELETION_REQUEST, fmt.Sprintf("scheduled at %d", deletionScheduledUnixTime))
+
+	return &models.UserAccountDeletionResponse{
+		DeletionScheduledAt: deletionScheduledUnixTime,
+	}, nil
+}
+
+// UserAccountDeletionCancelHandler cancels the scheduled deletion of current user
+func (a *UsersApi) UserAccountDeletionCancelHandler(c *core.Context) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	err := a.users.CancelUserDeletion(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[users.UserAccountDeletionCancelHandler] failed to cancel deletion for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[users.UserAccountDeletionCancelHandler] user \"uid:%d\" has cancelled account deletion", uid)
+	recordUserAuditEvent(c, uid, models.USER_AUDIT_EVENT_TYPE_ACCOUNT_DELETION_CANCEL, "")
+
+	return true, nil
+}
+
 // UserSendVerifyEmailByUnloginUserHandler sends unlogin user verify email
 func (a *UsersApi) UserSendVerifyEmailByUnloginUserHandler(c *core.Context) (any, *errs.Error) {
 	if !settings.Container.Current.EnableUserVerifyEmail {
@@ -567,3 +759,37 @@ func (a *UsersApi) UserSendVerifyEmailByLoginedUserHandler(c *core.Context) (any
 
 	return true, nil
 }
+
+func (a *UsersApi) sendEmailChangeConfirmEmail(c *core.Context, user *models.User, oldEmail string, newEmail string) {
+	err := a.tokens.DeleteTokensByType(c, user.Uid, core.USER_TOKEN_TYPE_EMAIL_CHANGE)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[users.sendEmailChangeConfirmEmail] failed to revoke old email change tokens for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return
+	}
+
+	token, _, err := a.tokens.CreateEmailChangeToken(c, user)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[users.sendEmailChangeConfirmEmail] failed to create email change token for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return
+	}
+
+	recordUserAuditEvent(c, user.Uid, models.USER_AUDIT_EVENT_TYPE_EMAIL_CHANGE_REQUEST, fmt.Sprintf("%s -> %s", oldEmail, newEmail))
+
+	locale := c.GetClientLocale()
+
+	go func() {
+		err := a.users.SendEmailChangeConfirmEmail(user, newEmail, token, locale)
+
+		if err != nil {
+			log.WarnfWithRequestId(c, "[users.sendEmailChangeConfirmEmail] cannot send email change confirmation email to \"%s\", because %s", newEmail, err.Error())
+		}
+
+		err = a.users.SendEmailChangeNotifyEmail(user, newEmail, locale)
+
+		if err != nil {
+			log.WarnfWithRequestId(c, "[users.sendEmailChangeConfirmEmail] cannot send email change notification email to \"%s\", because %s", user.Email, err.Error())
+		}
+	}()
+}
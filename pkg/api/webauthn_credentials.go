@@ -0,0 +1,213 @@
+package api
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/log"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+	"github.com/kyy-me/ezbookkeeping/pkg/services"
+	"github.com/kyy-me/ezbookkeeping/pkg/settings"
+	"github.com/kyy-me/ezbookkeeping/pkg/webauthn"
+)
+
+const webAuthnCredentialIdMaxLength = 255
+
+// WebAuthnCredentialsApi represents webauthn credential api
+type WebAuthnCredentialsApi struct {
+	users                   *services.UserService
+	userWebAuthnCredentials *services.UserWebAuthnCredentialService
+}
+
+// Initialize a webauthn credential api singleton instance
+var (
+	WebAuthnCredentials = &WebAuthnCredentialsApi{
+		users:                   services.Users,
+		userWebAuthnCredentials: services.UserWebAuthnCredentials,
+	}
+)
+
+// WebAuthnCredentialListHandler returns all webauthn credentials of current user
+func (a *WebAuthnCredentialsApi) WebAuthnCredentialListHandler(c *core.Context) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	credentials, err := a.userWebAuthnCredentials.GetAllCredentialsByUid(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[webauthn_credentials.WebAuthnCredentialListHandler] failed to get webauthn credentials for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	credentialResps := make([]*models.WebAuthnCredentialInfoResponse, len(credentials))
+
+	for i := 0; i < len(credentials); i++ {
+		credentialResps[i] = credentials[i].ToWebAuthnCredentialInfoResponse()
+	}
+
+	return credentialResps, nil
+}
+
+// WebAuthnRegistrationRequestHandler returns the credential creation options for current user to register a new authenticator
+func (a *WebAuthnCredentialsApi) WebAuthnRegistrationRequestHandler(c *core.Context) (any, *errs.Error) {
+	relyingParty := webauthn.Container.Current
+
+	if relyingParty == nil {
+		return nil, errs.ErrWebAuthnNotEnabled
+	}
+
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.ErrorfWithRequestId(c, "[webauthn_credentials.WebAuthnRegistrationRequestHandler] failed to get user, because %s", err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	credentials, err := a.userWebAuthnCredentials.GetAllCredentialsByUid(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[webauthn_credentials.WebAuthnRegistrationRequestHandler] failed to get webauthn credentials for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	if len(credentials) >= models.UserWebAuthnCredentialMaxCount {
+		return nil, errs.ErrWebAuthnCredentialCountExceeded
+	}
+
+	options, session, err := relyingParty.BeginRegistration(strconv.FormatInt(uid, 10), user.Username, user.Nickname, getWebAuthnCredentialDescriptors(credentials))
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[webauthn_credentials.WebAuthnRegistrationRequestHandler] failed to begin registration for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrSystemError
+	}
+
+	now := time.Now()
+	encodedSession, err := session.Encode(settings.Container.Current.SecretKey, now)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[webauthn_credentials.WebAuthnRegistrationRequestHandler] failed to encode webauthn session, because %s", err.Error())
+		return nil, errs.ErrSystemError
+	}
+
+	err = a.userWebAuthnCredentials.CreateChallenge(c, session.Challenge, now.Add(webauthn.SessionValidDuration).Unix())
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[webauthn_credentials.WebAuthnRegistrationRequestHandler] failed to save webauthn challenge for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrSystemError
+	}
+
+	return &models.WebAuthnRegistrationRequestResponse{
+		Options: options,
+		Session: encodedSession,
+	}, nil
+}
+
+// WebAuthnRegistrationConfirmHandler verifies the new credential which is created by authenticator and saves it for current user
+func (a *WebAuthnCredentialsApi) WebAuthnRegistrationConfirmHandler(c *core.Context) (any, *errs.Error) {
+	relyingParty := webauthn.Container.Current
+
+	if relyingParty == nil {
+		return nil, errs.ErrWebAuthnNotEnabled
+	}
+
+	var confirmReq models.WebAuthnRegistrationConfirmRequest
+	err := c.ShouldBindJSON(&confirmReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[webauthn_credentials.WebAuthnRegistrationConfirmHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	session, err := webauthn.ParseSession(confirmReq.Session, settings.Container.Current.SecretKey, webauthn.SESSION_TYPE_REGISTRATION)
+
+	if err != nil || session.UserId != strconv.FormatInt(uid, 10) {
+		log.WarnfWithRequestId(c, "[webauthn_credentials.WebAuthnRegistrationConfirmHandler] webauthn session is invalid for user \"uid:%d\"", uid)
+		return nil, errs.ErrWebAuthnSessionInvalid
+	}
+
+	err = a.userWebAuthnCredentials.ConsumeChallenge(c, session.Challenge)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[webauthn_credentials.WebAuthnRegistrationConfirmHandler] webauthn session has been used or expired for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrWebAuthnSessionInvalid)
+	}
+
+	credential, err := relyingParty.FinishRegistration(session, confirmReq.Credential)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[webauthn_credentials.WebAuthnRegistrationConfirmHandler] failed to verify new credential for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrWebAuthnCredentialInvalid
+	}
+
+	credentialId := webauthn.EncodeBase64Url(credential.Id)
+
+	if len(credentialId) > webAuthnCredentialIdMaxLength {
+		log.WarnfWithRequestId(c, "[webauthn_credentials.WebAuthnRegistrationConfirmHandler] credential id of user \"uid:%d\" is too long", uid)
+		return nil, errs.ErrWebAuthnCredentialIdInvalid
+	}
+
+	userWebAuthnCredential := &models.UserWebAuthnCredential{
+		CredentialId: credentialId,
+		Uid:          uid,
+		Name:         strings.TrimSpace(confirmReq.Name),
+		PublicKey:    webauthn.EncodeBase64Url(credential.PublicKey),
+		SignCount:    int64(credential.SignCount),
+		Transports:   strings.Join(credential.Transports, ","),
+	}
+
+	err = a.userWebAuthnCredentials.CreateCredential(c, userWebAuthnCredential)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[webauthn_credentials.WebAuthnRegistrationConfirmHandler] failed to create webauthn credential for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[webauthn_credentials.WebAuthnRegistrationConfirmHandler] user \"uid:%d\" has registered webauthn credential \"%s\"", uid, userWebAuthnCredential.CredentialId)
+	recordUserAuditEvent(c, uid, models.USER_AUDIT_EVENT_TYPE_WEBAUTHN_CREDENTIAL_REGISTER, userWebAuthnCredential.Name)
+
+	return userWebAuthnCredential.ToWebAuthnCredentialInfoResponse(), nil
+}
+
+// WebAuthnCredentialDeleteHandler revokes a webauthn credential of current user
+func (a *WebAuthnCredentialsApi) WebAuthnCredentialDeleteHandler(c *core.Context) (any, *errs.Error) {
+	var deleteReq models.WebAuthnCredentialDeleteRequest
+	err := c.ShouldBindJSON(&deleteReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[webauthn_credentials.WebAuthnCredentialDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.userWebAuthnCredentials.DeleteCredential(c, uid, deleteReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[webauthn_credentials.WebAuthnCredentialDeleteHandler] failed to delete webauthn credential \"%s\" for user \"uid:%d\", because %s", deleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[webauthn_credentials.WebAuthnCredentialDeleteHandler] user \"uid:%d\" has deleted webauthn credential \"%s\"", uid, deleteReq.Id)
+	recordUserAuditEvent(c, uid, models.USER_AUDIT_EVENT_TYPE_WEBAUTHN_CREDENTIAL_DELETE, deleteReq.Id)
+
+	return true, nil
+}
+
+func getWebAuthnCredentialDescriptors(credentials []*models.UserWebAuthnCredential) []webauthn.CredentialDescriptor {
+	descriptors := make([]webauthn.CredentialDescriptor, len(credentials))
+
+	for i := 0; i < len(credentials); i++ {
+		descriptors[i] = webauthn.CredentialDescriptor{
+			Type:       webauthn.PublicKeyCredentialType,
+			Id:         credentials[i].CredentialId,
+			Transports: credentials[i].GetTransports(),
+		}
+	}
+
+	return descriptors
+}
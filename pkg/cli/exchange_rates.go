@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/urfave/cli/v2"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/exchangerates"
+	"github.com/kyy-me/ezbookkeeping/pkg/log"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+	"github.com/kyy-me/ezbookkeeping/pkg/services"
+)
+
+// ExchangeRatesCli represents exchange rates cli
+type ExchangeRatesCli struct {
+	exchangeRates *services.ExchangeRateService
+	accounts      *services.AccountService
+}
+
+// Initialize an exchange rates cli singleton instance
+var (
+	ExchangeRates = &ExchangeRatesCli{
+		exchangeRates: services.ExchangeRates,
+		accounts:      services.Accounts,
+	}
+)
+
+// UpdateLatestExchangeRates requests the latest exchange rates of current data source and saves them into database
+func (l *ExchangeRatesCli) UpdateLatestExchangeRates(c *cli.Context) (int, error) {
+	ctx := l.newContext()
+	latestExchangeRateResponse, err := exchangerates.GetLatestExchangeRates(ctx, exchangerates.Container.Current)
+
+	if err != nil {
+		log.BootErrorf("[exchange_rates.UpdateLatestExchangeRates] failed to get latest exchange rates, because %s", err.Error())
+		return 0, err
+	}
+
+	return l.saveExchangeRates(ctx, exchangerates.Container.DataSourceNames[0], []*models.LatestExchangeRateResponse{latestExchangeRateResponse}, 0, 0)
+}
+
+// ExchangeRatesBackfillResult represents the historical exchange rates backfilling result of one data source
+type ExchangeRatesBackfillResult struct {
+	DataSource string
+	SavedCount int
+	Error      error
+}
+
+// BackfillHistoricalExchangeRates requests the historical exchange rates of the specified data source or all configured data sources (or reads them of one data source from the specified content),
+// saves the exchange rates between start date and end date into database, and returns the backfilling result of each data source
+func (l *ExchangeRatesCli) BackfillHistoricalExchangeRates(c *cli.Context, dataSourceName string, content []byte, startDate int32, endDate int32) ([]*ExchangeRatesBackfillResult, error) {
+	ctx := l.newContext()
+
+	if content != nil && dataSourceName == "" {
+		dataSourceName = exchangerates.Container.DataSourceNames[0]
+	}
+
+	results := make([]*ExchangeRatesBackfillResult, 0, len(exchangerates.Container.DataSources))
+
+	for i := 0; i < len(exchangerates.Container.DataSources); i++ {
+		dataSource := exchangerates.Container.DataSources[i]
+		currentDataSourceName := exchangerates.Container.DataSourceNames[i]
+
+		if dataSourceName != "" && currentDataSourceName != dataSourceName {
+			continue
+		}
+
+		result := &ExchangeRatesBackfillResult{
+			DataSource: currentDataSourceName,
+		}
+
+		results = append(results, result)
+
+		var historicalExchangeRateResponses []*models.LatestExchangeRateResponse
+		var err error
+
+		if content != nil {
+			historicalExchangeRateResponses, err = exchangerates.ParseHistoricalExchangeRates(ctx, dataSource, content)
+		} else {
+			historicalExchangeRateResponses, err = exchangerates.GetHistoricalExchangeRates(ctx, dataSource)
+		}
+
+		if err == errs.ErrHistoricalExchangeRatesNotSupported {
+			log.BootWarnf("[exchange_rates.BackfillHistoricalExchangeRates] data source \"%s\" does not provide historical exchange rates", currentDataSourceName)
+			result.Error = err
+			continue
+		} else if err != nil {
+			log.BootErrorf("[exchange_rates.BackfillHistoricalExchangeRates] failed to get historical exchange rates of data source \"%s\", because %s", currentDataSourceName, err.Error())
+			result.Error = err
+			continue
+		}
+
+		result.SavedCount, result.Error = l.saveExchangeRates(ctx, currentDataSourceName, historicalExchangeRateResponses, startDate, endDate)
+	}
+
+	if len(results) < 1 {
+		log.BootErrorf("[exchange_rates.BackfillHistoricalExchangeRates] data source \"%s\" is not configured", dataSourceName)
+		return nil, errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	return results, nil
+}
+
+// CheckExchangeRatesDataSources requests the latest exchange rates of every configured data source once, and returns the fetching status and problems of all data sources,
+// and the currencies of accounts which are not covered by any data source
+func (l *ExchangeRatesCli) CheckExchangeRatesDataSources(c *cli.Context) (*models.ExchangeRatesHealthResponse, error) {
+	ctx := l.newContext()
+
+	// the failure of each data source is recorded in its cache and would be returned in the health status
+	for i := 0; i < len(exchangerates.Container.Caches); i++ {
+		_, _, _ = exchangerates.Container.Caches[i].Refresh(ctx)
+	}
+
+	usedCurrencies, err := l.accounts.GetAllUsedCurrencies(ctx)
+
+	if err != nil {
+		log.BootErrorf("[exchange_rates.CheckExchangeRatesDataSources] failed to get used currencies of all accounts, because %s", err.Error())
+		return nil, err
+	}
+
+	return exchangerates.Container.GetHealth(usedCurrencies, time.Now().Unix()), nil
+}
+
+func (l *ExchangeRatesCli) saveExchangeRates(ctx *core.Context, dataSource string, exchangeRateResponses []*models.LatestExchangeRateResponse, startDate int32, endDate int32) (int, error) {
+	exchangeRates := make([]*models.ExchangeRate, 0)
+
+	for i := 0; i < len(exchangeRateResponses); i++ {
+		rates := exchangeRateResponses[i].ToExchangeRates(dataSource)
+
+		for j := 0; j < len(rates); j++ {
+			if (startDate > 0 && rates[j].Date < startDate) || (endDate > 0 && rates[j].Date > endDate) {
+				continue
+			}
+
+			exchangeRates = append(exchangeRates, rates[j])
+		}
+	}
+
+	savedCount, err := l.exchangeRates.SaveExchangeRates(ctx, exchangeRates)
+
+	if err != nil {
+		log.BootErrorf("[exchange_rates.saveExchangeRates] failed to save exchange rates, because %s", err.Error())
+		return savedCount, err
+	}
+
+	return savedCount, nil
+}
+
+func (l *ExchangeRatesCli) newContext() *core.Context {
+	return &core.Context{
+		Context: &gin.Context{},
+	}
+}
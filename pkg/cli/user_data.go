@@ -6,6 +6,7 @@ import (
 	"github.com/urfave/cli/v2"
 
 	"github.com/kyy-me/ezbookkeeping/pkg/converters"
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
 	"github.com/kyy-me/ezbookkeeping/pkg/errs"
 	"github.com/kyy-me/ezbookkeeping/pkg/log"
 	"github.com/kyy-me/ezbookkeeping/pkg/models"
@@ -14,7 +15,6 @@ import (
 	"github.com/kyy-me/ezbookkeeping/pkg/validators"
 )
 
-const pageCountForGettingTransactions = 1000
 const pageCountForDataExport = 1000
 
 // UserDataCli represents user data cli
@@ -29,6 +29,11 @@ type UserDataCli struct {
 	twoFactorAuthorizations  *services.TwoFactorAuthorizationService
 	tokens                   *services.TokenService
 	forgetPasswords          *services.ForgetPasswordService
+	userWebAuthnCredentials  *services.UserWebAuthnCredentialService
+	loginAttempts            *services.LoginAttemptService
+	userAuditEvents          *services.UserAuditEventService
+	invitationCodes          *services.InvitationCodeService
+	userDataChecks           *services.UserDataCheckService
 }
 
 // Initialize an user data cli singleton instance
@@ -44,6 +49,11 @@ var (
 		twoFactorAuthorizations:  services.TwoFactorAuthorizations,
 		tokens:                   services.Tokens,
 		forgetPasswords:          services.ForgetPasswords,
+		userWebAuthnCredentials:  services.UserWebAuthnCredentials,
+		loginAttempts:            services.LoginAttempts,
+		userAuditEvents:          services.UserAuditEvents,
+		invitationCodes:          services.InvitationCodes,
+		userDataChecks:           services.UserDataChecks,
 	}
 )
 
@@ -236,6 +246,23 @@ func (l *UserDataCli) DisableUser(c *cli.Context, username string) error {
 	return nil
 }
 
+// SetUserAdmin grants or revokes the administrator role of the specified user
+func (l *UserDataCli) SetUserAdmin(c *cli.Context, username string, isAdmin bool) error {
+	if username == "" {
+		log.BootErrorf("[user_data.SetUserAdmin] user name is empty")
+		return errs.ErrUsernameIsEmpty
+	}
+
+	err := l.users.SetUserAdmin(nil, username, isAdmin)
+
+	if err != nil {
+		log.BootErrorf("[user_data.SetUserAdmin] failed to set administrator role of user \"%s\", because %s", username, err.Error())
+		return err
+	}
+
+	return nil
+}
+
 // ResendVerifyEmail resends an email with account activation link
 func (l *UserDataCli) ResendVerifyEmail(c *cli.Context, username string) error {
 	if !settings.Container.Current.EnableUserVerifyEmail {
@@ -341,7 +368,7 @@ func (l *UserDataCli) ListUserTokens(c *cli.Context, username string) ([]*models
 		return nil, err
 	}
 
-	tokens, err := l.tokens.GetAllUnexpiredNormalTokensByUid(nil, uid)
+	tokens, err := l.tokens.GetAllUnexpiredNormalAndPersonalAccessTokensByUid(nil, uid)
 
 	if err != nil {
 		log.BootErrorf("[user_data.ListUserTokens] failed to get tokens of user \"%s\", because %s", username, err.Error())
@@ -351,191 +378,259 @@ func (l *UserDataCli) ListUserTokens(c *cli.Context, username string) ([]*models
 	return tokens, nil
 }
 
-// ClearUserTokens clears all tokens of the specified user
-func (l *UserDataCli) ClearUserTokens(c *cli.Context, username string) error {
+// ListUserAuditEvents returns all audit events of the specified user
+func (l *UserDataCli) ListUserAuditEvents(c *cli.Context, username string) ([]*models.UserAuditEvent, error) {
 	if username == "" {
-		log.BootErrorf("[user_data.ClearUserTokens] user name is empty")
-		return errs.ErrUsernameIsEmpty
+		log.BootErrorf("[user_data.ListUserAuditEvents] user name is empty")
+		return nil, errs.ErrUsernameIsEmpty
 	}
 
 	uid, err := l.getUserIdByUsername(c, username)
 
 	if err != nil {
-		log.BootErrorf("[user_data.ClearUserTokens] error occurs when getting user id by user name")
-		return err
+		log.BootErrorf("[user_data.ListUserAuditEvents] error occurs when getting user id by user name")
+		return nil, err
 	}
 
-	now := time.Now().Unix()
-	err = l.tokens.DeleteTokensBeforeTime(nil, uid, now)
+	events, err := l.userAuditEvents.GetAllEventsByUid(nil, uid)
 
 	if err != nil {
-		log.BootErrorf("[user_data.ClearUserTokens] failed to delete tokens of user \"%s\", because %s", username, err.Error())
-		return err
+		log.BootErrorf("[user_data.ListUserAuditEvents] failed to get audit events of user \"%s\", because %s", username, err.Error())
+		return nil, err
 	}
 
-	return nil
+	return events, nil
 }
 
-// DisableUserTwoFactorAuthorization disables 2fa for the specified user
-func (l *UserDataCli) DisableUserTwoFactorAuthorization(c *cli.Context, username string) error {
+// GetUserTokenId returns the token id of the specified token which can be used for revoking
+func (l *UserDataCli) GetUserTokenId(tokenRecord *models.TokenRecord) string {
+	return l.tokens.GenerateTokenId(tokenRecord)
+}
+
+// CreateUserPersonalAccessToken creates a new personal access token with the specified name and scopes for the specified user
+func (l *UserDataCli) CreateUserPersonalAccessToken(c *cli.Context, username string, name string, scopes []core.TokenScope, expiresInDays uint32) (string, *models.TokenRecord, error) {
 	if username == "" {
-		log.BootErrorf("[user_data.DisableUserTwoFactorAuthorization] user name is empty")
-		return errs.ErrUsernameIsEmpty
+		log.BootErrorf("[user_data.CreateUserPersonalAccessToken] user name is empty")
+		return "", nil, errs.ErrUsernameIsEmpty
 	}
 
-	uid, err := l.getUserIdByUsername(c, username)
+	user, err := l.GetUserByUsername(c, username)
 
 	if err != nil {
-		log.BootErrorf("[user_data.DisableUserTwoFactorAuthorization] error occurs when getting user id by user name")
-		return err
+		log.BootErrorf("[user_data.CreateUserPersonalAccessToken] failed to get user by user name \"%s\", because %s", username, err.Error())
+		return "", nil, err
 	}
 
-	enableTwoFactor, err := l.twoFactorAuthorizations.ExistsTwoFactorSetting(nil, uid)
+	token, tokenRecord, err := l.tokens.CreatePersonalAccessToken(nil, user, name, scopes, expiresInDays)
 
 	if err != nil {
-		log.BootErrorf("[user_data.DisableUserTwoFactorAuthorization] failed to check two-factor setting, because %s", err.Error())
-		return err
+		log.BootErrorf("[user_data.CreateUserPersonalAccessToken] failed to create personal access token for user \"%s\", because %s", username, err.Error())
+		return "", nil, err
 	}
 
-	if !enableTwoFactor {
-		return errs.ErrTwoFactorIsNotEnabled
+	return token, tokenRecord, nil
+}
+
+// RevokeUserToken revokes the specified token of the specified user
+func (l *UserDataCli) RevokeUserToken(c *cli.Context, username string, tokenId string) error {
+	if username == "" {
+		log.BootErrorf("[user_data.RevokeUserToken] user name is empty")
+		return errs.ErrUsernameIsEmpty
+	}
+
+	uid, err := l.getUserIdByUsername(c, username)
+
+	if err != nil {
+		log.BootErrorf("[user_data.RevokeUserToken] error occurs when getting user id by user name")
+		return err
 	}
 
-	err = l.twoFactorAuthorizations.DeleteTwoFactorRecoveryCodes(nil, uid)
+	tokenRecord, err := l.tokens.ParseFromTokenId(tokenId)
 
 	if err != nil {
-		log.BootErrorf("[user_data.DisableUserTwoFactorAuthorization] failed to delete two-factor recovery codes for user \"%s\"", username)
+		log.BootErrorf("[user_data.RevokeUserToken] failed to parse token id \"%s\", because %s", tokenId, err.Error())
 		return err
 	}
 
-	err = l.twoFactorAuthorizations.DeleteTwoFactorSetting(nil, uid)
+	if tokenRecord.Uid != uid {
+		log.BootErrorf("[user_data.RevokeUserToken] token \"%s\" is not owned by user \"%s\"", tokenId, username)
+		return errs.ErrInvalidTokenId
+	}
+
+	err = l.tokens.DeleteToken(nil, tokenRecord)
 
 	if err != nil {
-		log.BootErrorf("[user_data.DisableUserTwoFactorAuthorization] failed to delete two-factor setting for user \"%s\"", username)
+		log.BootErrorf("[user_data.RevokeUserToken] failed to revoke token \"%s\" of user \"%s\", because %s", tokenId, username, err.Error())
 		return err
 	}
 
 	return nil
 }
 
-// CheckTransactionAndAccount checks whether all user transactions and all user accounts are correct
-func (l *UserDataCli) CheckTransactionAndAccount(c *cli.Context, username string) (bool, error) {
+// ClearUserTokens clears all tokens of the specified user
+func (l *UserDataCli) ClearUserTokens(c *cli.Context, username string) error {
 	if username == "" {
-		log.BootErrorf("[user_data.CheckTransactionAndAccount] user name is empty")
-		return false, errs.ErrUsernameIsEmpty
+		log.BootErrorf("[user_data.ClearUserTokens] user name is empty")
+		return errs.ErrUsernameIsEmpty
 	}
 
 	uid, err := l.getUserIdByUsername(c, username)
 
 	if err != nil {
-		log.BootErrorf("[user_data.CheckTransactionAndAccount] error occurs when getting user id by user name")
-		return false, err
+		log.BootErrorf("[user_data.ClearUserTokens] error occurs when getting user id by user name")
+		return err
 	}
 
-	accountMap, categoryMap, tagMap, tagIndexs, err := l.getUserEssentialData(uid, username)
+	now := time.Now().Unix()
+	err = l.tokens.DeleteTokensBeforeTime(nil, uid, now)
 
 	if err != nil {
-		log.BootErrorf("[user_data.CheckTransactionAndAccount] failed to get essential data for user \"%s\", because %s", username, err.Error())
-		return false, err
+		log.BootErrorf("[user_data.ClearUserTokens] failed to delete tokens of user \"%s\", because %s", username, err.Error())
+		return err
 	}
 
-	accountHasChild := make(map[int64]bool)
+	return nil
+}
 
-	for _, account := range accountMap {
-		if account.ParentAccountId > models.LevelOneAccountParentId {
-			accountHasChild[account.ParentAccountId] = true
-		}
+// DisableUserTwoFactorAuthorization disables 2fa and removes all webauthn credentials for the specified user
+func (l *UserDataCli) DisableUserTwoFactorAuthorization(c *cli.Context, username string) error {
+	if username == "" {
+		log.BootErrorf("[user_data.DisableUserTwoFactorAuthorization] user name is empty")
+		return errs.ErrUsernameIsEmpty
 	}
 
-	allTransactions, err := l.transactions.GetAllTransactions(nil, uid, pageCountForGettingTransactions, false)
+	uid, err := l.getUserIdByUsername(c, username)
 
 	if err != nil {
-		log.BootErrorf("[user_data.CheckTransactionAndAccount] failed to all transactions for user \"%s\", because %s", username, err.Error())
-		return false, err
+		log.BootErrorf("[user_data.DisableUserTwoFactorAuthorization] error occurs when getting user id by user name")
+		return err
 	}
 
-	transactionMap := l.transactions.GetTransactionMapByList(allTransactions)
-	accountBalance := make(map[int64]int64)
+	enableTwoFactor, err := l.twoFactorAuthorizations.ExistsTwoFactorSetting(nil, uid)
 
-	for i := len(allTransactions) - 1; i >= 0; i-- {
-		transaction := allTransactions[i]
+	if err != nil {
+		log.BootErrorf("[user_data.DisableUserTwoFactorAuthorization] failed to check two-factor setting, because %s", err.Error())
+		return err
+	}
 
-		err := l.checkTransactionAccount(c, transaction, accountMap, accountHasChild)
+	existsWebAuthnCredential, err := l.userWebAuthnCredentials.ExistsCredential(nil, uid)
 
-		if err != nil {
-			return false, err
-		}
+	if err != nil {
+		log.BootErrorf("[user_data.DisableUserTwoFactorAuthorization] failed to check webauthn credentials, because %s", err.Error())
+		return err
+	}
+
+	if !enableTwoFactor && !existsWebAuthnCredential {
+		return errs.ErrTwoFactorIsNotEnabled
+	}
 
-		err = l.checkTransactionCategory(c, transaction, categoryMap)
+	if enableTwoFactor {
+		err = l.twoFactorAuthorizations.DeleteTwoFactorRecoveryCodes(nil, uid)
 
 		if err != nil {
-			return false, err
+			log.BootErrorf("[user_data.DisableUserTwoFactorAuthorization] failed to delete two-factor recovery codes for user \"%s\"", username)
+			return err
 		}
 
-		err = l.checkTransactionTag(c, transaction.TransactionId, tagIndexs, tagMap)
+		err = l.twoFactorAuthorizations.DeleteTwoFactorSetting(nil, uid)
 
 		if err != nil {
-			return false, err
+			log.BootErrorf("[user_data.DisableUserTwoFactorAuthorization] failed to delete two-factor setting for user \"%s\"", username)
+			return err
 		}
+	}
 
-		err = l.checkTransactionRelatedTransaction(c, transaction, transactionMap, accountMap)
+	if existsWebAuthnCredential {
+		err = l.userWebAuthnCredentials.DeleteAllCredentials(nil, uid)
 
 		if err != nil {
-			return false, err
+			log.BootErrorf("[user_data.DisableUserTwoFactorAuthorization] failed to delete webauthn credentials for user \"%s\"", username)
+			return err
 		}
+	}
 
-		balance, exists := accountBalance[transaction.AccountId]
+	return nil
+}
 
-		if !exists {
-			balance = 0
-		}
+// UnlockUser clears the failed login attempts and the lockout of the specified user
+func (l *UserDataCli) UnlockUser(c *cli.Context, username string) error {
+	if username == "" {
+		log.BootErrorf("[user_data.UnlockUser] user name is empty")
+		return errs.ErrUsernameIsEmpty
+	}
 
-		if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
-			balance = balance + transaction.RelatedAccountAmount
-		} else if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
-			balance = balance + transaction.Amount
-		} else if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
-			balance = balance - transaction.Amount
-		} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
-			balance = balance - transaction.Amount
-		} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
-			balance = balance + transaction.Amount
-		} else {
-			log.BootErrorf("[user_data.CheckAccountBalance] transaction type of transaction \"id:%d\" is invalid", transaction.TransactionId)
-			return false, errs.ErrOperationFailed
-		}
+	uid, err := l.getUserIdByUsername(c, username)
 
-		accountBalance[transaction.AccountId] = balance
+	if err != nil {
+		log.BootErrorf("[user_data.UnlockUser] error occurs when getting user id by user name")
+		return err
 	}
 
-	for _, account := range accountMap {
-		actualBalance, exists := accountBalance[account.AccountId]
+	deleted, err := l.loginAttempts.ClearFailedAttempts(nil, models.GetLoginAttemptKeyByUid(uid))
 
-		if !exists && account.Balance == 0 {
-			continue
-		}
+	if err != nil {
+		log.BootErrorf("[user_data.UnlockUser] failed to clear failed login attempts of user \"%s\", because %s", username, err.Error())
+		return err
+	}
 
-		if !exists && account.Balance != 0 {
-			log.BootErrorf("[user_data.CheckAccountBalance] account \"id:%d\" balance is not correct, expected balance is %d, but there is no transaction actually", account.AccountId, account.Balance)
-			return false, errs.ErrOperationFailed
-		}
+	if !deleted {
+		return errs.ErrUserIsNotLocked
+	}
 
-		if account.Balance != actualBalance {
-			log.BootErrorf("[user_data.CheckAccountBalance] account \"id:%d\" balance is not correct, expected balance is %d, but actual balance is %d", account.AccountId, account.Balance, actualBalance)
-			return false, errs.ErrOperationFailed
-		}
+	return nil
+}
+
+// CreateInvitationCode generates a new invitation code which can be used for registering
+func (l *UserDataCli) CreateInvitationCode(c *cli.Context, maxUses uint32, expiresInDays uint32, boundEmail string) (*models.InvitationCode, error) {
+	invitationCode, err := l.invitationCodes.CreateInvitationCode(nil, 0, maxUses, expiresInDays, boundEmail)
+
+	if err != nil {
+		log.BootErrorf("[user_data.CreateInvitationCode] failed to create invitation code, because %s", err.Error())
+		return nil, err
 	}
 
-	for accountId, actualBalance := range accountBalance {
-		_, exists := accountMap[accountId]
+	return invitationCode, nil
+}
+
+// ListInvitationCodes returns all invitation codes which are not revoked
+func (l *UserDataCli) ListInvitationCodes(c *cli.Context) ([]*models.InvitationCode, error) {
+	invitationCodes, err := l.invitationCodes.GetAllInvitationCodes(nil)
 
-		if !exists {
-			log.BootErrorf("[user_data.CheckAccountBalance] account \"id:%d\" does not exist, but there are some transactions of this account actually, and actual balance is %d", accountId, actualBalance)
-			return false, errs.ErrOperationFailed
-		}
+	if err != nil {
+		log.BootErrorf("[user_data.ListInvitationCodes] failed to get invitation codes, because %s", err.Error())
+		return nil, err
+	}
+
+	return invitationCodes, nil
+}
+
+// RevokeInvitationCode revokes the specified invitation code
+func (l *UserDataCli) RevokeInvitationCode(c *cli.Context, codeId int64) error {
+	err := l.invitationCodes.RevokeInvitationCode(nil, codeId)
+
+	if err != nil {
+		log.BootErrorf("[user_data.RevokeInvitationCode] failed to revoke invitation code \"id:%d\", because %s", codeId, err.Error())
+		return err
 	}
 
-	return true, nil
+	return nil
+}
+
+// CheckTransactionAndAccount checks whether all user transactions and all user accounts are correct
+func (l *UserDataCli) CheckTransactionAndAccount(c *cli.Context, username string) (bool, error) {
+	if username == "" {
+		log.BootErrorf("[user_data.CheckTransactionAndAccount] user name is empty")
+		return false, errs.ErrUsernameIsEmpty
+	}
+
+	uid, err := l.getUserIdByUsername(c, username)
+
+	if err != nil {
+		log.BootErrorf("[user_data.CheckTransactionAndAccount] error occurs when getting user id by user name")
+		return false, err
+	}
+
+	return l.userDataChecks.CheckTransactionAndAccount(nil, uid)
 }
 
 // ExportTransaction returns csv file content according user all transactions
@@ -637,115 +732,3 @@ func (l *UserDataCli) getUserEssentialData(uid int64, username string) (accountM
 
 	return accountMap, categoryMap, tagMap, tagIndexs, nil
 }
-
-func (l *UserDataCli) checkTransactionAccount(c *cli.Context, transaction *models.Transaction, accountMap map[int64]*models.Account, accountHasChild map[int64]bool) error {
-	account, exists := accountMap[transaction.AccountId]
-
-	if !exists {
-		log.BootErrorf("[user_data.checkTransactionAccount] the account \"id:%d\" of transaction \"id:%d\" does not exist", transaction.AccountId, transaction.TransactionId)
-		return errs.ErrAccountNotFound
-	}
-
-	if account.ParentAccountId == models.LevelOneAccountParentId && accountHasChild[account.AccountId] {
-		log.BootErrorf("[user_data.checkTransactionAccount] the account \"id:%d\" of transaction \"id:%d\" is not a sub-account", transaction.AccountId, transaction.TransactionId)
-		return errs.ErrOperationFailed
-	}
-
-	if transaction.RelatedAccountId > 0 {
-		relatedAccount, exists := accountMap[transaction.RelatedAccountId]
-
-		if !exists {
-			log.BootErrorf("[user_data.checkTransactionAccount] the related account \"id:%d\" of transaction \"id:%d\" does not exist", transaction.RelatedAccountId, transaction.TransactionId)
-			return errs.ErrAccountNotFound
-		}
-
-		if relatedAccount.ParentAccountId == models.LevelOneAccountParentId && accountHasChild[relatedAccount.AccountId] {
-			log.BootErrorf("[user_data.checkTransactionAccount] the related account \"id:%d\" of transaction \"id:%d\" is not a sub-account", transaction.RelatedAccountId, transaction.TransactionId)
-			return errs.ErrOperationFailed
-		}
-	}
-
-	return nil
-}
-
-func (l *UserDataCli) checkTransactionCategory(c *cli.Context, transaction *models.Transaction, categoryMap map[int64]*models.TransactionCategory) error {
-	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
-		if transaction.CategoryId > 0 {
-			log.BootErrorf("[user_data.checkTransactionCategory] transaction \"id:%d\" is balance modification transaction, but has category \"id:%d\"", transaction.TransactionId, transaction.CategoryId)
-			return errs.ErrBalanceModificationTransactionCannotSetCategory
-		} else {
-			return nil
-		}
-	}
-
-	category, exists := categoryMap[transaction.CategoryId]
-
-	if !exists {
-		log.BootErrorf("[user_data.checkTransactionCategory] the transaction category \"id:%d\" of transaction \"id:%d\" does not exist", transaction.CategoryId, transaction.TransactionId)
-		return errs.ErrTransactionCategoryNotFound
-	}
-
-	if category.ParentCategoryId == models.LevelOneTransactionParentId {
-		log.BootErrorf("[user_data.checkTransactionCategory] the transaction category \"id:%d\" of transaction \"id:%d\" is not a sub category", transaction.CategoryId, transaction.TransactionId)
-		return errs.ErrOperationFailed
-	}
-
-	return nil
-}
-
-func (l *UserDataCli) checkTransactionTag(c *cli.Context, transactionId int64, allTagIndexs map[int64][]int64, tagMap map[int64]*models.TransactionTag) error {
-	tagIndexs, exists := allTagIndexs[transactionId]
-
-	if !exists {
-		return nil
-	}
-
-	for i := 0; i < len(tagIndexs); i++ {
-		tagIndex := tagIndexs[i]
-		tag, exists := tagMap[tagIndex]
-
-		if !exists {
-			log.BootErrorf("[user_data.checkTransactionTag] the transaction tag \"id:%d\" of transaction \"id:%d\" does not exist", tag.TagId, transactionId)
-			return errs.ErrTransactionTagNotFound
-		}
-	}
-
-	return nil
-}
-
-func (l *UserDataCli) checkTransactionRelatedTransaction(c *cli.Context, transaction *models.Transaction, transactionMap map[int64]*models.Transaction, accountMap map[int64]*models.Account) error {
-	if transaction.Type != models.TRANSACTION_DB_TYPE_TRANSFER_OUT && transaction.Type != models.TRANSACTION_DB_TYPE_TRANSFER_IN {
-		return nil
-	}
-
-	relatedTransaction, exists := transactionMap[transaction.RelatedId]
-
-	if !exists {
-		log.BootErrorf("[user_data.checkTransactionRelatedTransaction] the related transaction \"id:%d\" of transaction \"id:%d\" does not exist", transaction.RelatedId, transaction.TransactionId)
-		return errs.ErrTransactionNotFound
-	}
-
-	if transaction.RelatedId != relatedTransaction.TransactionId || transaction.TransactionId != relatedTransaction.RelatedId {
-		log.BootErrorf("[user_data.checkTransactionRelatedTransaction] related ids of transaction \"id:%d\" and transaction \"id:%d\" are not equal", transaction.RelatedId, transaction.TransactionId)
-		return errs.ErrOperationFailed
-	}
-
-	if transaction.RelatedAccountId != relatedTransaction.AccountId || transaction.AccountId != relatedTransaction.RelatedAccountId {
-		log.BootErrorf("[user_data.checkTransactionRelatedTransaction] related account ids of transaction \"id:%d\" and transaction \"id:%d\" are not equal", transaction.RelatedId, transaction.TransactionId)
-		return errs.ErrOperationFailed
-	}
-
-	if transaction.RelatedAccountAmount != relatedTransaction.Amount || transaction.Amount != relatedTransaction.RelatedAccountAmount {
-		log.BootErrorf("[user_data.checkTransactionRelatedTransaction] related amounts of transaction \"id:%d\" and transaction \"id:%d\" are not equal", transaction.RelatedId, transaction.TransactionId)
-		return errs.ErrOperationFailed
-	}
-
-	account := accountMap[transaction.AccountId]
-	relatedAccount := accountMap[transaction.RelatedAccountId]
-
-	if account.Currency == relatedAccount.Currency && transaction.Amount != transaction.RelatedAccountAmount {
-		log.BootWarnf("[user_data.checkTransactionRelatedTransaction] transfer-in amount and transfer-out amount of transaction \"id:%d\" are not equal", transaction.TransactionId)
-	}
-
-	return nil
-}
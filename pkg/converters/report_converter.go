@@ -0,0 +1,14 @@
+package converters
+
+import (
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+)
+
+// ReportConverter defines the structure of report exporter
+type ReportConverter interface {
+	// IncomeStatementToExportedContent returns the exported income statement report
+	IncomeStatementToExportedContent(report *models.ReportIncomeStatementResponse) ([]byte, error)
+
+	// BalanceSheetToExportedContent returns the exported balance sheet report
+	BalanceSheetToExportedContent(report *models.ReportBalanceSheetResponse) ([]byte, error)
+}
@@ -0,0 +1,36 @@
+package converters
+
+import (
+	"bytes"
+	"encoding/csv"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+)
+
+// ReportCSVFileExporter defines the structure of report CSV file exporter
+type ReportCSVFileExporter struct {
+	ReportPlainFileExporter
+}
+
+// IncomeStatementToExportedContent returns the exported CSV data of income statement report
+func (e *ReportCSVFileExporter) IncomeStatementToExportedContent(report *models.ReportIncomeStatementResponse) ([]byte, error) {
+	return e.toExportedContent(e.getIncomeStatementRows(report))
+}
+
+// BalanceSheetToExportedContent returns the exported CSV data of balance sheet report
+func (e *ReportCSVFileExporter) BalanceSheetToExportedContent(report *models.ReportBalanceSheetResponse) ([]byte, error) {
+	return e.toExportedContent(e.getBalanceSheetRows(report))
+}
+
+func (e *ReportCSVFileExporter) toExportedContent(rows [][]string) ([]byte, error) {
+	var ret bytes.Buffer
+	writer := csv.NewWriter(&ret)
+
+	err := writer.WriteAll(rows)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ret.Bytes(), nil
+}
@@ -0,0 +1,96 @@
+package converters
+
+import (
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+)
+
+// ReportPlainFileExporter defines the structure of report plain table exporter
+type ReportPlainFileExporter struct {
+}
+
+// getIncomeStatementRows returns the table rows of income statement report, the last column is amount
+func (e *ReportPlainFileExporter) getIncomeStatementRows(report *models.ReportIncomeStatementResponse) [][]string {
+	rows := make([][]string, 0, 16)
+	rows = append(rows, []string{"Section", "Category", "Sub Category", "Amount (" + report.Currency + ")"})
+	rows = e.appendCategorySectionRows(rows, "Income", report.Income)
+	rows = e.appendCategorySectionRows(rows, "Expense", report.Expense)
+	rows = append(rows, []string{"Net Income", "", "", getDisplayAmount(report.NetIncome)})
+
+	return rows
+}
+
+// getBalanceSheetRows returns the table rows of balance sheet report, the last two columns are amounts
+func (e *ReportPlainFileExporter) getBalanceSheetRows(report *models.ReportBalanceSheetResponse) [][]string {
+	rows := make([][]string, 0, 16)
+	rows = append(rows, []string{"Section", "Account Category", "Account", "Account Currency", "Account Balance", "Amount (" + report.Currency + ")"})
+	rows = e.appendAccountSectionRows(rows, "Assets", report.Assets)
+	rows = e.appendAccountSectionRows(rows, "Liabilities", report.Liabilities)
+	rows = append(rows, []string{"Net Equity", "", "", "", "", getDisplayAmount(report.NetEquity)})
+
+	return rows
+}
+
+func (e *ReportPlainFileExporter) appendCategorySectionRows(rows [][]string, sectionName string, section *models.ReportCategorySection) [][]string {
+	if section == nil {
+		return rows
+	}
+
+	for i := 0; i < len(section.Items); i++ {
+		category := section.Items[i]
+		rows = append(rows, []string{sectionName, category.Name, "", getDisplayAmount(category.Amount)})
+
+		for j := 0; j < len(category.SubCategories); j++ {
+			subCategory := category.SubCategories[j]
+			rows = append(rows, []string{sectionName, category.Name, subCategory.Name, getDisplayAmount(subCategory.Amount)})
+		}
+	}
+
+	rows = append(rows, []string{sectionName, "Total", "", getDisplayAmount(section.Total)})
+
+	return rows
+}
+
+func (e *ReportPlainFileExporter) appendAccountSectionRows(rows [][]string, sectionName string, section *models.ReportAccountSection) [][]string {
+	if section == nil {
+		return rows
+	}
+
+	for i := 0; i < len(section.Groups); i++ {
+		group := section.Groups[i]
+		categoryName := e.getAccountCategoryName(group.Category)
+
+		for j := 0; j < len(group.Accounts); j++ {
+			account := group.Accounts[j]
+			rows = append(rows, []string{sectionName, categoryName, account.Name, account.Currency, getDisplayAmount(account.Balance), getDisplayAmount(account.Amount)})
+		}
+
+		rows = append(rows, []string{sectionName, categoryName, "Subtotal", "", "", getDisplayAmount(group.Subtotal)})
+	}
+
+	rows = append(rows, []string{sectionName, "Total", "", "", "", getDisplayAmount(section.Total)})
+
+	return rows
+}
+
+func (e *ReportPlainFileExporter) getAccountCategoryName(category models.AccountCategory) string {
+	switch category {
+	case models.ACCOUNT_CATEGORY_CASH:
+		return "Cash"
+	case models.ACCOUNT_CATEGORY_DEBIT_CARD:
+		return "Debit Card"
+	case models.ACCOUNT_CATEGORY_CREDIT_CARD:
+		return "Credit Card"
+	case models.ACCOUNT_CATEGORY_VIRTUAL:
+		return "Virtual Account"
+	case models.ACCOUNT_CATEGORY_DEBT:
+		return "Debt Account"
+	case models.ACCOUNT_CATEGORY_RECEIVABLES:
+		return "Receivables"
+	case models.ACCOUNT_CATEGORY_INVESTMENT:
+		return "Investment Account"
+	case models.ACCOUNT_CATEGORY_SAVING:
+		return "Savings Account"
+	default:
+		return ""
+	}
+}
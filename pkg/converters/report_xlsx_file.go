@@ -0,0 +1,131 @@
+package converters
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+)
+
+// ReportXLSXFileExporter defines the structure of report XLSX file exporter
+type ReportXLSXFileExporter struct {
+	ReportPlainFileExporter
+}
+
+const xlsxContentTypesXml = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+
+const xlsxRootRelationshipsXml = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+
+const xlsxWorkbookXmlFormat = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+const xlsxWorkbookRelationshipsXml = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+
+const xlsxWorksheetXmlHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+
+const xlsxWorksheetXmlFooter = `</sheetData></worksheet>`
+
+// IncomeStatementToExportedContent returns the exported XLSX data of income statement report
+func (e *ReportXLSXFileExporter) IncomeStatementToExportedContent(report *models.ReportIncomeStatementResponse) ([]byte, error) {
+	return e.toExportedContent("Income Statement", e.getIncomeStatementRows(report), 1)
+}
+
+// BalanceSheetToExportedContent returns the exported XLSX data of balance sheet report
+func (e *ReportXLSXFileExporter) BalanceSheetToExportedContent(report *models.ReportBalanceSheetResponse) ([]byte, error) {
+	return e.toExportedContent("Balance Sheet", e.getBalanceSheetRows(report), 2)
+}
+
+// toExportedContent returns a workbook with only one worksheet, the last specified count of columns except the header row are written as numbers
+func (e *ReportXLSXFileExporter) toExportedContent(sheetName string, rows [][]string, numericColumnCount int) ([]byte, error) {
+	var ret bytes.Buffer
+	writer := zip.NewWriter(&ret)
+
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypesXml},
+		{"_rels/.rels", xlsxRootRelationshipsXml},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbookXmlFormat, e.escapeXml(sheetName))},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRelationshipsXml},
+		{"xl/worksheets/sheet1.xml", e.getWorksheetXml(rows, numericColumnCount)},
+	}
+
+	for i := 0; i < len(files); i++ {
+		fileWriter, err := writer.Create(files[i].name)
+
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = fileWriter.Write([]byte(files[i].content))
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := writer.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ret.Bytes(), nil
+}
+
+func (e *ReportXLSXFileExporter) getWorksheetXml(rows [][]string, numericColumnCount int) string {
+	var ret strings.Builder
+
+	ret.WriteString(xlsxWorksheetXmlHeader)
+
+	for i := 0; i < len(rows); i++ {
+		row := rows[i]
+		ret.WriteString(fmt.Sprintf(`<row r="%d">`, i+1))
+
+		for j := 0; j < len(row); j++ {
+			if row[j] == "" {
+				continue
+			}
+
+			cellReference := fmt.Sprintf("%s%d", e.getColumnName(j), i+1)
+
+			if i > 0 && j >= len(row)-numericColumnCount {
+				ret.WriteString(fmt.Sprintf(`<c r="%s"><v>%s</v></c>`, cellReference, row[j]))
+			} else {
+				ret.WriteString(fmt.Sprintf(`<c r="%s" t="inlineStr"><is><t>%s</t></is></c>`, cellReference, e.escapeXml(row[j])))
+			}
+		}
+
+		ret.WriteString(`</row>`)
+	}
+
+	ret.WriteString(xlsxWorksheetXmlFooter)
+
+	return ret.String()
+}
+
+func (e *ReportXLSXFileExporter) getColumnName(columnIndex int) string {
+	name := ""
+
+	for columnIndex >= 0 {
+		name = string(rune('A'+columnIndex%26)) + name
+		columnIndex = columnIndex/26 - 1
+	}
+
+	return name
+}
+
+func (e *ReportXLSXFileExporter) escapeXml(text string) string {
+	var ret strings.Builder
+	_ = xml.EscapeText(&ret, []byte(text))
+
+	return ret.String()
+}
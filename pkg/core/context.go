@@ -30,8 +30,12 @@ func (c *Context) SetRequestId(requestId string) {
 	c.Set(requestIdFieldKey, requestId)
 }
 
-// GetRequestId returns the current request id
+// GetRequestId returns the current request id, or returns empty if the context is nil (e.g. called from command line)
 func (c *Context) GetRequestId() string {
+	if c == nil || c.Context == nil {
+		return ""
+	}
+
 	requestId, exists := c.Get(requestIdFieldKey)
 
 	if !exists {
@@ -11,20 +11,55 @@ type TokenType byte
 
 // Token types
 const (
-	USER_TOKEN_TYPE_NORMAL         TokenType = 1
-	USER_TOKEN_TYPE_REQUIRE_2FA    TokenType = 2
-	USER_TOKEN_TYPE_EMAIL_VERIFY   TokenType = 3
-	USER_TOKEN_TYPE_PASSWORD_RESET TokenType = 4
+	USER_TOKEN_TYPE_NORMAL          TokenType = 1
+	USER_TOKEN_TYPE_REQUIRE_2FA     TokenType = 2
+	USER_TOKEN_TYPE_EMAIL_VERIFY    TokenType = 3
+	USER_TOKEN_TYPE_PASSWORD_RESET  TokenType = 4
+	USER_TOKEN_TYPE_PERSONAL_ACCESS TokenType = 5
+	USER_TOKEN_TYPE_EMAIL_CHANGE    TokenType = 6
 )
 
+// TokenScope represents the permission scope of personal access token
+type TokenScope string
+
+// Token scopes
+const (
+	TOKEN_SCOPE_ACCOUNTS_READ      TokenScope = "accounts:read"
+	TOKEN_SCOPE_ACCOUNTS_WRITE     TokenScope = "accounts:write"
+	TOKEN_SCOPE_TRANSACTIONS_READ  TokenScope = "transactions:read"
+	TOKEN_SCOPE_TRANSACTIONS_WRITE TokenScope = "transactions:write"
+	TOKEN_SCOPE_EXPORT             TokenScope = "export"
+)
+
+// AllTokenScopes represents all supported scopes of personal access token
+var AllTokenScopes = map[TokenScope]bool{
+	TOKEN_SCOPE_ACCOUNTS_READ:      true,
+	TOKEN_SCOPE_ACCOUNTS_WRITE:     true,
+	TOKEN_SCOPE_TRANSACTIONS_READ:  true,
+	TOKEN_SCOPE_TRANSACTIONS_WRITE: true,
+	TOKEN_SCOPE_EXPORT:             true,
+}
+
 // UserTokenClaims represents user token
 type UserTokenClaims struct {
-	UserTokenId string    `json:"userTokenId"`
-	Uid         int64     `json:"jti,string"`
-	Username    string    `json:"username,omitempty"`
-	Type        TokenType `json:"type"`
-	IssuedAt    int64     `json:"iat"`
-	ExpiresAt   int64     `json:"exp"`
+	UserTokenId string       `json:"userTokenId"`
+	Uid         int64        `json:"jti,string"`
+	Username    string       `json:"username,omitempty"`
+	Type        TokenType    `json:"type"`
+	Scopes      []TokenScope `json:"scopes,omitempty"`
+	IssuedAt    int64        `json:"iat"`
+	ExpiresAt   int64        `json:"exp"`
+}
+
+// HasScope returns whether this token has the specified scope
+func (c *UserTokenClaims) HasScope(scope TokenScope) bool {
+	for i := 0; i < len(c.Scopes); i++ {
+		if c.Scopes[i] == scope {
+			return true
+		}
+	}
+
+	return false
 }
 
 // GetExpirationTime returns the expiration time of this token
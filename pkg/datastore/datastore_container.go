@@ -19,9 +19,10 @@ import (
 
 // DataStoreContainer contains all data storages
 type DataStoreContainer struct {
-	UserStore     *DataStore
-	TokenStore    *DataStore
-	UserDataStore *DataStore
+	UserStore         *DataStore
+	TokenStore        *DataStore
+	UserDataStore     *DataStore
+	ExchangeRateStore *DataStore
 }
 
 // Initialize a data storage container singleton instance
@@ -57,6 +58,12 @@ func InitializeDataStore(config *settings.Config) error {
 		return err
 	}
 
+	Container.ExchangeRateStore, err = NewDataStore(database)
+
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
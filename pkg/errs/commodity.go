@@ -0,0 +1,15 @@
+package errs
+
+import "net/http"
+
+// Error codes related to commodities
+var (
+	ErrCommodityIdInvalid            = NewNormalError(NormalSubcategoryCommodity, 0, http.StatusBadRequest, "commodity id is invalid")
+	ErrCommodityNotFound             = NewNormalError(NormalSubcategoryCommodity, 1, http.StatusBadRequest, "commodity not found")
+	ErrCommodityCodeAlreadyExists    = NewNormalError(NormalSubcategoryCommodity, 2, http.StatusBadRequest, "commodity code already exists")
+	ErrCommodityInUseCannotBeDeleted = NewNormalError(NormalSubcategoryCommodity, 3, http.StatusBadRequest, "commodity is in use and cannot be deleted")
+	ErrCommodityPriceSourceInvalid   = NewNormalError(NormalSubcategoryCommodity, 4, http.StatusBadRequest, "commodity price source is invalid")
+	ErrCommodityPriceInvalid         = NewNormalError(NormalSubcategoryCommodity, 5, http.StatusBadRequest, "commodity price is invalid")
+	ErrCommodityQuoteEndpointNotSet  = NewNormalError(NormalSubcategoryCommodity, 6, http.StatusBadRequest, "commodity quote endpoint is not set")
+	ErrCommodityPriceNotFromQuote    = NewNormalError(NormalSubcategoryCommodity, 7, http.StatusBadRequest, "commodity price is not from quote endpoint")
+)
@@ -29,6 +29,11 @@ const (
 	NormalSubcategoryTag            = 7
 	NormalSubcategoryDataManagement = 8
 	NormalSubcategoryMapProxy       = 9
+	NormalSubcategoryExchangeRate   = 10
+	NormalSubcategoryCommodity      = 11
+	NormalSubcategoryExternalAuth   = 12
+	NormalSubcategoryWebAuthn       = 13
+	NormalSubcategoryInvitationCode = 14
 )
 
 // Error represents the specific error returned to user
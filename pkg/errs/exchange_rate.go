@@ -0,0 +1,14 @@
+package errs
+
+import "net/http"
+
+// Error codes related to exchange rates
+var (
+	ErrExchangeRateNotFound                 = NewNormalError(NormalSubcategoryExchangeRate, 0, http.StatusBadRequest, "exchange rate not found")
+	ErrExchangeRateDateInvalid              = NewNormalError(NormalSubcategoryExchangeRate, 1, http.StatusBadRequest, "exchange rate date is invalid")
+	ErrHistoricalExchangeRatesNotSupported  = NewNormalError(NormalSubcategoryExchangeRate, 2, http.StatusBadRequest, "current exchange rates data source does not support historical exchange rates")
+	ErrUserExchangeRateInvalid              = NewNormalError(NormalSubcategoryExchangeRate, 3, http.StatusBadRequest, "user exchange rate is invalid")
+	ErrUserExchangeRateNotFound             = NewNormalError(NormalSubcategoryExchangeRate, 4, http.StatusBadRequest, "user exchange rate not found")
+	ErrUserExchangeRateCurrencyIsSameAsBase = NewNormalError(NormalSubcategoryExchangeRate, 5, http.StatusBadRequest, "currency of user exchange rate cannot be the same as base currency")
+	ErrExchangeRatesDataSourceUnhealthy     = NewNormalError(NormalSubcategoryExchangeRate, 6, http.StatusBadRequest, "exchange rates data source is unhealthy")
+)
@@ -0,0 +1,19 @@
+package errs
+
+import "net/http"
+
+// Error codes related to external authentication
+var (
+	ErrOIDCNotEnabled               = NewNormalError(NormalSubcategoryExternalAuth, 0, http.StatusBadRequest, "openid connect login is not enabled")
+	ErrOIDCStateInvalid             = NewNormalError(NormalSubcategoryExternalAuth, 1, http.StatusBadRequest, "openid connect state is invalid")
+	ErrOIDCAuthorizationCodeInvalid = NewNormalError(NormalSubcategoryExternalAuth, 2, http.StatusBadRequest, "openid connect authorization code is invalid")
+	ErrOIDCProviderRequestFailed    = NewNormalError(NormalSubcategoryExternalAuth, 3, http.StatusBadGateway, "failed to request openid connect provider")
+	ErrOIDCIdTokenInvalid           = NewNormalError(NormalSubcategoryExternalAuth, 4, http.StatusUnauthorized, "openid connect id token is invalid")
+	ErrExternalUserIdIsEmpty        = NewNormalError(NormalSubcategoryExternalAuth, 5, http.StatusUnauthorized, "external user id is empty")
+	ErrExternalUserNotLinked        = NewNormalError(NormalSubcategoryExternalAuth, 6, http.StatusUnauthorized, "external user is not linked to any user")
+	ErrExternalUserEmailIsEmpty     = NewNormalError(NormalSubcategoryExternalAuth, 7, http.StatusBadRequest, "external user email is empty")
+	ErrExternalAuthAlreadyLinked    = NewNormalError(NormalSubcategoryExternalAuth, 8, http.StatusBadRequest, "external user has been linked to another user")
+	ErrLDAPServerRequestFailed      = NewNormalError(NormalSubcategoryExternalAuth, 9, http.StatusBadGateway, "failed to request ldap server")
+	ErrLDAPUserNotUnique            = NewNormalError(NormalSubcategoryExternalAuth, 10, http.StatusUnauthorized, "more than one ldap user matches login name")
+	ErrLDAPUserNotInRequiredGroup   = NewNormalError(NormalSubcategoryExternalAuth, 11, http.StatusUnauthorized, "ldap user is not in required group")
+)
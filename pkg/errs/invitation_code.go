@@ -0,0 +1,14 @@
+package errs
+
+import "net/http"
+
+// Error codes related to invitation codes
+var (
+	ErrInvitationCodeIsEmpty        = NewNormalError(NormalSubcategoryInvitationCode, 0, http.StatusBadRequest, "invitation code is required")
+	ErrInvitationCodeInvalid        = NewNormalError(NormalSubcategoryInvitationCode, 1, http.StatusBadRequest, "invitation code is invalid, expired or used up")
+	ErrInvitationCodeEmailMismatch  = NewNormalError(NormalSubcategoryInvitationCode, 2, http.StatusBadRequest, "invitation code is not for this email")
+	ErrInvitationCodeNotFound       = NewNormalError(NormalSubcategoryInvitationCode, 3, http.StatusBadRequest, "invitation code not found")
+	ErrInvitationCodeIdInvalid      = NewNormalError(NormalSubcategoryInvitationCode, 4, http.StatusBadRequest, "invitation code id is invalid")
+	ErrInvitationCodeMaxUsesInvalid = NewNormalError(NormalSubcategoryInvitationCode, 5, http.StatusBadRequest, "invitation code max uses is invalid")
+	ErrInvitationCodeExpiryInvalid  = NewNormalError(NormalSubcategoryInvitationCode, 6, http.StatusBadRequest, "invitation code expiry is invalid")
+)
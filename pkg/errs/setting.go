@@ -4,11 +4,18 @@ import "net/http"
 
 // Error codes related to settings
 var (
-	ErrInvalidProtocol                       = NewSystemError(SystemSubcategorySetting, 0, http.StatusInternalServerError, "invalid server protocol")
-	ErrInvalidLogMode                        = NewSystemError(SystemSubcategorySetting, 1, http.StatusInternalServerError, "invalid log mode")
-	ErrGettingLocalAddress                   = NewSystemError(SystemSubcategorySetting, 2, http.StatusInternalServerError, "failed to get local address")
-	ErrInvalidUuidMode                       = NewSystemError(SystemSubcategorySetting, 3, http.StatusInternalServerError, "invalid uuid mode")
-	ErrInvalidExchangeRatesDataSource        = NewSystemError(SystemSubcategorySetting, 4, http.StatusInternalServerError, "invalid exchange rates data source")
-	ErrInvalidMapProvider                    = NewSystemError(SystemSubcategorySetting, 5, http.StatusInternalServerError, "invalid map provider")
-	ErrInvalidAmapSecurityVerificationMethod = NewSystemError(SystemSubcategorySetting, 6, http.StatusInternalServerError, "invalid amap security verification method")
+	ErrInvalidProtocol                            = NewSystemError(SystemSubcategorySetting, 0, http.StatusInternalServerError, "invalid server protocol")
+	ErrInvalidLogMode                             = NewSystemError(SystemSubcategorySetting, 1, http.StatusInternalServerError, "invalid log mode")
+	ErrGettingLocalAddress                        = NewSystemError(SystemSubcategorySetting, 2, http.StatusInternalServerError, "failed to get local address")
+	ErrInvalidUuidMode                            = NewSystemError(SystemSubcategorySetting, 3, http.StatusInternalServerError, "invalid uuid mode")
+	ErrInvalidExchangeRatesDataSource             = NewSystemError(SystemSubcategorySetting, 4, http.StatusInternalServerError, "invalid exchange rates data source")
+	ErrInvalidMapProvider                         = NewSystemError(SystemSubcategorySetting, 5, http.StatusInternalServerError, "invalid map provider")
+	ErrInvalidAmapSecurityVerificationMethod      = NewSystemError(SystemSubcategorySetting, 6, http.StatusInternalServerError, "invalid amap security verification method")
+	ErrInvalidCustomExchangeRatesDataSourceConfig = NewSystemError(SystemSubcategorySetting, 7, http.StatusInternalServerError, "invalid custom exchange rates data source config")
+	ErrInvalidExchangeRatesRoundingMode           = NewSystemError(SystemSubcategorySetting, 8, http.StatusInternalServerError, "invalid exchange rates rounding mode")
+	ErrInvalidCommodityQuoteConfig                = NewSystemError(SystemSubcategorySetting, 9, http.StatusInternalServerError, "invalid commodity quote config")
+	ErrInvalidOIDCConfig                          = NewSystemError(SystemSubcategorySetting, 10, http.StatusInternalServerError, "invalid openid connect config")
+	ErrInvalidLDAPConfig                          = NewSystemError(SystemSubcategorySetting, 11, http.StatusInternalServerError, "invalid ldap config")
+	ErrInvalidWebAuthnConfig                      = NewSystemError(SystemSubcategorySetting, 12, http.StatusInternalServerError, "invalid webauthn config")
+	ErrInvalidPasswordHashConfig                  = NewSystemError(SystemSubcategorySetting, 13, http.StatusInternalServerError, "invalid password hash config")
 )
@@ -21,4 +21,7 @@ var (
 	ErrTokenIsEmpty                         = NewNormalError(NormalSubcategoryToken, 12, http.StatusBadRequest, "token is empty")
 	ErrEmailVerifyTokenIsInvalidOrExpired   = NewNormalError(NormalSubcategoryToken, 13, http.StatusBadRequest, "email verify token is invalid or expired")
 	ErrPasswordResetTokenIsInvalidOrExpired = NewNormalError(NormalSubcategoryToken, 14, http.StatusBadRequest, "password reset token is invalid or expired")
+	ErrCurrentTokenScopeNotAllowed          = NewNormalError(NormalSubcategoryToken, 15, http.StatusForbidden, "current token does not have the scope of this api")
+	ErrInvalidTokenScope                    = NewNormalError(NormalSubcategoryToken, 16, http.StatusBadRequest, "token scope is invalid")
+	ErrEmailChangeTokenIsInvalidOrExpired   = NewNormalError(NormalSubcategoryToken, 17, http.StatusBadRequest, "email change token is invalid or expired")
 )
@@ -30,4 +30,13 @@ var (
 	ErrEmailIsVerified                                     = NewNormalError(NormalSubcategoryUser, 21, http.StatusBadRequest, "email is verified")
 	ErrEmailValidationNotAllowed                           = NewNormalError(NormalSubcategoryUser, 22, http.StatusBadRequest, "email validation not allowed")
 	ErrDecimalSeparatorAndDigitGroupingSymbolCannotBeEqual = NewNormalError(NormalSubcategoryUser, 23, http.StatusBadRequest, "decimal separator and digit grouping symbol cannot be equal")
+	ErrUserIsNotAdministrator                              = NewNormalError(NormalSubcategoryUser, 24, http.StatusForbidden, "user is not administrator")
+	ErrTooManyFailedLoginAttempts                          = NewNormalError(NormalSubcategoryUser, 25, http.StatusTooManyRequests, "too many failed login attempts, please try again later")
+	ErrUserIsNotLocked                                     = NewNormalError(NormalSubcategoryUser, 26, http.StatusBadRequest, "user is not locked")
+	ErrUserEmailChangeNotRequested                         = NewNormalError(NormalSubcategoryUser, 27, http.StatusBadRequest, "user has not requested to change email")
+	ErrAccountDeletionNotAllowed                           = NewNormalError(NormalSubcategoryUser, 28, http.StatusBadRequest, "account deletion is not allowed")
+	ErrAccountDeletionAlreadyScheduled                     = NewNormalError(NormalSubcategoryUser, 29, http.StatusBadRequest, "account deletion has already been scheduled")
+	ErrAccountDeletionNotScheduled                         = NewNormalError(NormalSubcategoryUser, 30, http.StatusBadRequest, "account deletion has not been scheduled")
+	ErrCannotOperateCurrentUserByAdmin                     = NewNormalError(NormalSubcategoryUser, 31, http.StatusBadRequest, "cannot perform this operation on current user")
+	ErrUserReauthenticationRequired                        = NewNormalError(NormalSubcategoryUser, 32, http.StatusUnauthorized, "password or recent external login is required")
 )
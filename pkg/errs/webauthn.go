@@ -0,0 +1,15 @@
+package errs
+
+import "net/http"
+
+// Error codes related to webauthn
+var (
+	ErrWebAuthnNotEnabled                = NewNormalError(NormalSubcategoryWebAuthn, 0, http.StatusBadRequest, "webauthn is not enabled")
+	ErrWebAuthnSessionInvalid            = NewNormalError(NormalSubcategoryWebAuthn, 1, http.StatusBadRequest, "webauthn session is invalid or expired")
+	ErrWebAuthnCredentialInvalid         = NewNormalError(NormalSubcategoryWebAuthn, 2, http.StatusUnauthorized, "webauthn credential is invalid")
+	ErrWebAuthnCredentialNotFound        = NewNormalError(NormalSubcategoryWebAuthn, 3, http.StatusBadRequest, "webauthn credential not found")
+	ErrWebAuthnCredentialAlreadyExists   = NewNormalError(NormalSubcategoryWebAuthn, 4, http.StatusBadRequest, "webauthn credential already exists")
+	ErrWebAuthnCredentialCountExceeded   = NewNormalError(NormalSubcategoryWebAuthn, 5, http.StatusBadRequest, "too many webauthn credentials")
+	ErrWebAuthnCredentialIdInvalid       = NewNormalError(NormalSubcategoryWebAuthn, 6, http.StatusBadRequest, "webauthn credential id is invalid")
+	ErrWebAuthnPasswordlessLoginDisabled = NewNormalError(NormalSubcategoryWebAuthn, 7, http.StatusBadRequest, "webauthn passwordless login is not enabled")
+)
@@ -15,6 +15,7 @@ import (
 )
 
 const bankOfCanadaExchangeRateUrl = "https://www.bankofcanada.ca/valet/observations/group/FX_RATES_DAILY/json?recent=1"
+const bankOfCanadaHistoricalExchangeRateUrl = "https://www.bankofcanada.ca/valet/observations/group/FX_RATES_DAILY/json?start_date=2017-01-03" // The daily exchange rates are available from 2017-01-03
 const bankOfCanadaExchangeRateReferenceUrl = "https://www.bankofcanada.ca/rates/exchange/daily-exchange-rates/"
 const bankOfCanadaDataSource = "Bank of Canada"
 const bankOfCanadaBaseCurrency = "CAD"
@@ -47,31 +48,45 @@ func (e *BankOfCanadaExchangeRateData) ToLatestExchangeRateResponse(c *core.Cont
 
 	for i := 0; i < len(e.Observations); i++ {
 		observation := e.Observations[i]
-		updateDateData := observation["d"]
+		updateDate := observation.getUpdateDate()
 
-		if updateDate, ok := updateDateData.(string); ok {
-			if latestUpdateDate == "" || strings.Compare(updateDate, latestUpdateDate) > 0 {
-				latestUpdateDate = updateDate
-			}
+		if updateDate != "" && (latestUpdateDate == "" || strings.Compare(updateDate, latestUpdateDate) > 0) {
+			latestUpdateDate = updateDate
 		}
 
-		for typeName, exchangeRateData := range observation {
-			if len(typeName) < 8 || !strings.HasPrefix(typeName, "FX") || !strings.HasSuffix(typeName, bankOfCanadaBaseCurrency) {
-				continue
-			}
+		observation.fillExchangeRates(exchangeRateMap)
+	}
+
+	return e.toExchangeRateResponse(c, latestUpdateDate, exchangeRateMap)
+}
+
+// ToHistoricalExchangeRateResponses returns view-objects of all dates according to original data from bank of Canada
+func (e *BankOfCanadaExchangeRateData) ToHistoricalExchangeRateResponses(c *core.Context) []*models.LatestExchangeRateResponse {
+	if len(e.Observations) < 1 {
+		log.ErrorfWithRequestId(c, "[bank_of_canada_datasource.ToHistoricalExchangeRateResponses] observations is empty")
+		return nil
+	}
 
-			currencyCode := utils.SubString(typeName, 2, 3)
+	exchangeRateResps := make([]*models.LatestExchangeRateResponse, 0, len(e.Observations))
 
-			if data, ok := exchangeRateData.(map[string]any); ok {
-				exchangeRate := data["v"]
+	for i := 0; i < len(e.Observations); i++ {
+		observation := e.Observations[i]
+		exchangeRateMap := make(map[string]string)
+		observation.fillExchangeRates(exchangeRateMap)
 
-				if exchangeRateValue, ok2 := exchangeRate.(string); ok2 {
-					exchangeRateMap[currencyCode] = exchangeRateValue
-				}
-			}
+		exchangeRateResp := e.toExchangeRateResponse(c, observation.getUpdateDate(), exchangeRateMap)
+
+		if exchangeRateResp == nil {
+			return nil
 		}
+
+		exchangeRateResps = append(exchangeRateResps, exchangeRateResp)
 	}
 
+	return exchangeRateResps
+}
+
+func (e *BankOfCanadaExchangeRateData) toExchangeRateResponse(c *core.Context, updateDate string, exchangeRateMap map[string]string) *models.LatestExchangeRateResponse {
 	exchangeRates := make(models.LatestExchangeRateSlice, 0, len(exchangeRateMap))
 
 	for currencyCode, exchangeRate := range exchangeRateMap {
@@ -82,12 +97,12 @@ func (e *BankOfCanadaExchangeRateData) ToLatestExchangeRateResponse(c *core.Cont
 		rate, err := utils.StringToFloat64(exchangeRate)
 
 		if err != nil {
-			log.WarnfWithRequestId(c, "[bank_of_canada_datasource.ToLatestExchangeRateResponse] failed to parse rate, rate is %s", exchangeRate)
+			log.WarnfWithRequestId(c, "[bank_of_canada_datasource.toExchangeRateResponse] failed to parse rate, rate is %s", exchangeRate)
 			continue
 		}
 
 		if rate <= 0 {
-			log.WarnfWithRequestId(c, "[bank_of_canada_datasource.ToLatestExchangeRateResponse] rate is invalid, rate is %s", exchangeRate)
+			log.WarnfWithRequestId(c, "[bank_of_canada_datasource.toExchangeRateResponse] rate is invalid, rate is %s", exchangeRate)
 			continue
 		}
 
@@ -106,15 +121,15 @@ func (e *BankOfCanadaExchangeRateData) ToLatestExchangeRateResponse(c *core.Cont
 	timezone, err := time.LoadLocation(bankOfCanadaDataUpdateDateTimezone)
 
 	if err != nil {
-		log.ErrorfWithRequestId(c, "[bank_of_canada_datasource.ToLatestExchangeRateResponse] failed to get timezone, timezone name is %s", bankOfCanadaDataUpdateDateTimezone)
+		log.ErrorfWithRequestId(c, "[bank_of_canada_datasource.toExchangeRateResponse] failed to get timezone, timezone name is %s", bankOfCanadaDataUpdateDateTimezone)
 		return nil
 	}
 
-	updateDateTime := latestUpdateDate + " 16:30" // Daily average exchange rates - published once each business day by 16:30 ET.
+	updateDateTime := updateDate + " 16:30" // Daily average exchange rates - published once each business day by 16:30 ET.
 	updateTime, err := time.ParseInLocation(bankOfCanadaDataUpdateDateFormat, updateDateTime, timezone)
 
 	if err != nil {
-		log.ErrorfWithRequestId(c, "[bank_of_canada_datasource.ToLatestExchangeRateResponse] failed to parse update date, datetime is %s", updateDateTime)
+		log.ErrorfWithRequestId(c, "[bank_of_canada_datasource.toExchangeRateResponse] failed to parse update date, datetime is %s", updateDateTime)
 		return nil
 	}
 
@@ -129,11 +144,47 @@ func (e *BankOfCanadaExchangeRateData) ToLatestExchangeRateResponse(c *core.Cont
 	return latestExchangeRateResp
 }
 
+func (o BankOfCanadaObservationData) getUpdateDate() string {
+	if updateDate, ok := o["d"].(string); ok {
+		return updateDate
+	}
+
+	return ""
+}
+
+func (o BankOfCanadaObservationData) fillExchangeRates(exchangeRateMap map[string]string) {
+	for typeName, exchangeRateData := range o {
+		if len(typeName) < 8 || !strings.HasPrefix(typeName, "FX") || !strings.HasSuffix(typeName, bankOfCanadaBaseCurrency) {
+			continue
+		}
+
+		currencyCode := utils.SubString(typeName, 2, 3)
+
+		if data, ok := exchangeRateData.(map[string]any); ok {
+			exchangeRate := data["v"]
+
+			if exchangeRateValue, ok2 := exchangeRate.(string); ok2 {
+				exchangeRateMap[currencyCode] = exchangeRateValue
+			}
+		}
+	}
+}
+
 // GetRequestUrls returns the bank of Canada data source urls
 func (e *BankOfCanadaDataSource) GetRequestUrls() []string {
 	return []string{bankOfCanadaExchangeRateUrl}
 }
 
+// GetPublishingInterval returns the publishing interval of bank of Canada data source, which publishes new exchange rates once per business day
+func (e *BankOfCanadaDataSource) GetPublishingInterval() time.Duration {
+	return dailyExchangeRatesPublishingInterval
+}
+
+// GetHistoricalRequestUrls returns the bank of Canada historical data source urls
+func (e *BankOfCanadaDataSource) GetHistoricalRequestUrls() []string {
+	return []string{bankOfCanadaHistoricalExchangeRateUrl}
+}
+
 // Parse returns the common response entity according to the bank of Canada data source raw response
 func (e *BankOfCanadaDataSource) Parse(c *core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
 	bankOfCanadaData := &BankOfCanadaExchangeRateData{}
@@ -153,3 +204,23 @@ func (e *BankOfCanadaDataSource) Parse(c *core.Context, content []byte) (*models
 
 	return latestExchangeRateResponse, nil
 }
+
+// ParseHistorical returns the common response entities of all dates according to the bank of Canada historical data source raw response
+func (e *BankOfCanadaDataSource) ParseHistorical(c *core.Context, content []byte) ([]*models.LatestExchangeRateResponse, error) {
+	bankOfCanadaData := &BankOfCanadaExchangeRateData{}
+	err := json.Unmarshal(content, bankOfCanadaData)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[bank_of_canada_datasource.ParseHistorical] failed to parse json data, because %s", err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	historicalExchangeRateResponses := bankOfCanadaData.ToHistoricalExchangeRateResponses(c)
+
+	if historicalExchangeRateResponses == nil {
+		log.ErrorfWithRequestId(c, "[bank_of_canada_datasource.ParseHistorical] failed to parse historical exchange rate data")
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return historicalExchangeRateResponses, nil
+}
@@ -194,3 +194,38 @@ func TestBankOfCanadaDataSource_InvalidRate(t *testing.T) {
 	assert.Equal(t, nil, err)
 	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 0)
 }
+
+func TestBankOfCanadaDataSource_HistoricalDataExtractAllDates(t *testing.T) {
+	dataSource := &BankOfCanadaDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualHistoricalExchangeRateResponses, err := dataSource.ParseHistorical(context, []byte(bankOfCanadaMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(actualHistoricalExchangeRateResponses))
+
+	assert.Equal(t, int64(1577827800), actualHistoricalExchangeRateResponses[0].UpdateTime)
+	assert.Equal(t, 1, len(actualHistoricalExchangeRateResponses[0].ExchangeRates))
+	assert.Contains(t, actualHistoricalExchangeRateResponses[0].ExchangeRates, &models.LatestExchangeRate{
+		Currency: "VND",
+		Rate:     "17857.14285714286",
+	})
+
+	assert.Equal(t, int64(1617309000), actualHistoricalExchangeRateResponses[1].UpdateTime)
+	assert.Equal(t, 2, len(actualHistoricalExchangeRateResponses[1].ExchangeRates))
+	assert.Contains(t, actualHistoricalExchangeRateResponses[1].ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "0.7958615200955034",
+	})
+}
+
+func TestBankOfCanadaDataSource_HistoricalBlankContent(t *testing.T) {
+	dataSource := &BankOfCanadaDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, err := dataSource.ParseHistorical(context, []byte(""))
+	assert.NotEqual(t, nil, err)
+}
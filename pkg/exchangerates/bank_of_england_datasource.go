@@ -0,0 +1,270 @@
+package exchangerates
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/log"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+	"github.com/kyy-me/ezbookkeeping/pkg/utils"
+)
+
+const bankOfEnglandExchangeRateUrlFormat = "https://www.bankofengland.co.uk/boeapps/database/_iadb-fromshowcolumns.asp?csv.x=yes&Datefrom=%s&Dateto=now&SeriesCodes=%s&CSVF=TN&UsingCodes=Y&VPD=Y&VFD=N"
+const bankOfEnglandExchangeRateReferenceUrl = "https://www.bankofengland.co.uk/boeapps/database/Rates.asp?into=GBP"
+const bankOfEnglandDataSource = "Bank of England"
+const bankOfEnglandBaseCurrency = "GBP"
+
+const bankOfEnglandRequestDateFormat = "02/Jan/2006"
+const bankOfEnglandRequestDays = 14
+const bankOfEnglandHistoricalStartDate = "02/Jan/1975" // The spot exchange rates of the most currencies are available from 1975
+const bankOfEnglandDataUpdateDateFormat = "02 Jan 2006 15:04"
+const bankOfEnglandDataUpdateDateTimezone = "Europe/London"
+
+// The spot exchange rates of foreign currency into sterling, each series code represents the amount of foreign currency per pound
+var bankOfEnglandSeriesCodeCurrencies = map[string]string{
+	"XUDLADS":  "AUD",
+	"XUDLCDS":  "CAD",
+	"XUDLBK89": "CNY",
+	"XUDLBK25": "CZK",
+	"XUDLDKS":  "DKK",
+	"XUDLERS":  "EUR",
+	"XUDLHDS":  "HKD",
+	"XUDLBK33": "HUF",
+	"XUDLBK97": "INR",
+	"XUDLBK78": "ILS",
+	"XUDLJYS":  "JPY",
+	"XUDLBK83": "MYR",
+	"XUDLNDS":  "NZD",
+	"XUDLNKS":  "NOK",
+	"XUDLBK47": "PLN",
+	"XUDLSRS":  "SAR",
+	"XUDLSGS":  "SGD",
+	"XUDLZRS":  "ZAR",
+	"XUDLBK93": "KRW",
+	"XUDLSKS":  "SEK",
+	"XUDLSFS":  "CHF",
+	"XUDLTWS":  "TWD",
+	"XUDLBK87": "THB",
+	"XUDLBK95": "TRY",
+	"XUDLUSS":  "USD",
+}
+
+// BankOfEnglandDataSource defines the structure of exchange rates data source of bank of England
+type BankOfEnglandDataSource struct {
+	ExchangeRatesDataSource
+}
+
+// GetRequestUrls returns the bank of England data source urls
+func (e *BankOfEnglandDataSource) GetRequestUrls() []string {
+	startDate := time.Now().AddDate(0, 0, -bankOfEnglandRequestDays).Format(bankOfEnglandRequestDateFormat)
+
+	return []string{e.getRequestUrl(startDate)}
+}
+
+// GetPublishingInterval returns the publishing interval of bank of England data source, which publishes new exchange rates once per business day
+func (e *BankOfEnglandDataSource) GetPublishingInterval() time.Duration {
+	return dailyExchangeRatesPublishingInterval
+}
+
+// GetHistoricalRequestUrls returns the bank of England historical data source urls
+func (e *BankOfEnglandDataSource) GetHistoricalRequestUrls() []string {
+	return []string{e.getRequestUrl(bankOfEnglandHistoricalStartDate)}
+}
+
+// Parse returns the common response entity according to the bank of England data source raw response
+func (e *BankOfEnglandDataSource) Parse(c *core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
+	allLines, err := e.readAllLines(c, content)
+
+	if err != nil {
+		return nil, err
+	}
+
+	titleLine := allLines[0]
+	exchangeRateMap := make(map[string]string)
+	exchangeRateUpdateTimes := make(map[string]int64)
+	latestUpdateTime := int64(0)
+
+	for i := 1; i < len(allLines); i++ {
+		items := allLines[i]
+		updateTime, err := e.parseUpdateTime(c, items[0])
+
+		if err != nil {
+			continue
+		}
+
+		for j := 1; j < len(items) && j < len(titleLine); j++ {
+			currencyCode, exists := bankOfEnglandSeriesCodeCurrencies[strings.TrimSpace(titleLine[j])]
+			rate := strings.TrimSpace(items[j])
+
+			if !exists || rate == "" || updateTime.Unix() < exchangeRateUpdateTimes[currencyCode] {
+				continue
+			}
+
+			exchangeRateMap[currencyCode] = rate
+			exchangeRateUpdateTimes[currencyCode] = updateTime.Unix()
+
+			if updateTime.Unix() > latestUpdateTime {
+				latestUpdateTime = updateTime.Unix()
+			}
+		}
+	}
+
+	if latestUpdateTime == 0 {
+		log.ErrorfWithRequestId(c, "[bank_of_england_datasource.Parse] no valid exchange rate, content is %s", string(content))
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	exchangeRates := make(models.LatestExchangeRateSlice, 0, len(exchangeRateMap))
+
+	for currencyCode, exchangeRate := range exchangeRateMap {
+		finalExchangeRate := e.parseExchangeRate(c, currencyCode, exchangeRate)
+
+		if finalExchangeRate == nil {
+			continue
+		}
+
+		exchangeRates = append(exchangeRates, finalExchangeRate)
+	}
+
+	latestExchangeRateResp := &models.LatestExchangeRateResponse{
+		DataSource:    bankOfEnglandDataSource,
+		ReferenceUrl:  bankOfEnglandExchangeRateReferenceUrl,
+		UpdateTime:    latestUpdateTime,
+		BaseCurrency:  bankOfEnglandBaseCurrency,
+		ExchangeRates: exchangeRates,
+	}
+
+	return latestExchangeRateResp, nil
+}
+
+// ParseHistorical returns the common response entities of all dates according to the bank of England historical data source raw response
+func (e *BankOfEnglandDataSource) ParseHistorical(c *core.Context, content []byte) ([]*models.LatestExchangeRateResponse, error) {
+	allLines, err := e.readAllLines(c, content)
+
+	if err != nil {
+		return nil, err
+	}
+
+	titleLine := allLines[0]
+	historicalExchangeRateResponses := make([]*models.LatestExchangeRateResponse, 0, len(allLines)-1)
+
+	for i := 1; i < len(allLines); i++ {
+		items := allLines[i]
+		updateTime, err := e.parseUpdateTime(c, items[0])
+
+		if err != nil {
+			continue
+		}
+
+		exchangeRates := make(models.LatestExchangeRateSlice, 0, len(items)-1)
+
+		for j := 1; j < len(items) && j < len(titleLine); j++ {
+			currencyCode, exists := bankOfEnglandSeriesCodeCurrencies[strings.TrimSpace(titleLine[j])]
+			rate := strings.TrimSpace(items[j])
+
+			if !exists || rate == "" {
+				continue
+			}
+
+			finalExchangeRate := e.parseExchangeRate(c, currencyCode, rate)
+
+			if finalExchangeRate == nil {
+				continue
+			}
+
+			exchangeRates = append(exchangeRates, finalExchangeRate)
+		}
+
+		if len(exchangeRates) < 1 {
+			continue
+		}
+
+		historicalExchangeRateResponses = append(historicalExchangeRateResponses, &models.LatestExchangeRateResponse{
+			DataSource:    bankOfEnglandDataSource,
+			ReferenceUrl:  bankOfEnglandExchangeRateReferenceUrl,
+			UpdateTime:    updateTime.Unix(),
+			BaseCurrency:  bankOfEnglandBaseCurrency,
+			ExchangeRates: exchangeRates,
+		})
+	}
+
+	if len(historicalExchangeRateResponses) < 1 {
+		log.ErrorfWithRequestId(c, "[bank_of_england_datasource.ParseHistorical] no valid historical exchange rate")
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return historicalExchangeRateResponses, nil
+}
+
+func (e *BankOfEnglandDataSource) getRequestUrl(startDate string) string {
+	seriesCodes := make([]string, 0, len(bankOfEnglandSeriesCodeCurrencies))
+
+	for seriesCode := range bankOfEnglandSeriesCodeCurrencies {
+		seriesCodes = append(seriesCodes, seriesCode)
+	}
+
+	return fmt.Sprintf(bankOfEnglandExchangeRateUrlFormat, startDate, strings.Join(seriesCodes, ","))
+}
+
+func (e *BankOfEnglandDataSource) readAllLines(c *core.Context, content []byte) ([][]string, error) {
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = -1
+
+	allLines, err := reader.ReadAll()
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[bank_of_england_datasource.readAllLines] failed to parse csv data, because %s", err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	if len(allLines) < 2 || len(allLines[0]) < 2 || strings.TrimSpace(allLines[0][0]) != "DATE" {
+		log.ErrorfWithRequestId(c, "[bank_of_england_datasource.readAllLines] content is invalid, content is %s", string(content))
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return allLines, nil
+}
+
+func (e *BankOfEnglandDataSource) parseUpdateTime(c *core.Context, date string) (time.Time, error) {
+	timezone, err := time.LoadLocation(bankOfEnglandDataUpdateDateTimezone)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[bank_of_england_datasource.parseUpdateTime] failed to get timezone, timezone name is %s", bankOfEnglandDataUpdateDateTimezone)
+		return time.Time{}, err
+	}
+
+	updateDateTime := strings.TrimSpace(date) + " 16:00" // The spot exchange rates are observed by the bank's foreign exchange desk around 4 p.m. London time
+	updateTime, err := time.ParseInLocation(bankOfEnglandDataUpdateDateFormat, updateDateTime, timezone)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[bank_of_england_datasource.parseUpdateTime] failed to parse update date, datetime is %s", updateDateTime)
+		return time.Time{}, err
+	}
+
+	return updateTime, nil
+}
+
+func (e *BankOfEnglandDataSource) parseExchangeRate(c *core.Context, currencyCode string, exchangeRate string) *models.LatestExchangeRate {
+	rate, err := utils.StringToFloat64(exchangeRate)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[bank_of_england_datasource.parseExchangeRate] failed to parse rate, currency is %s, rate is %s", currencyCode, exchangeRate)
+		return nil
+	}
+
+	if math.IsNaN(rate) || math.IsInf(rate, 0) || rate <= 0 {
+		log.WarnfWithRequestId(c, "[bank_of_england_datasource.parseExchangeRate] rate is invalid, currency is %s, rate is %s", currencyCode, exchangeRate)
+		return nil
+	}
+
+	return &models.LatestExchangeRate{
+		Currency: currencyCode,
+		Rate:     exchangeRate,
+	}
+}
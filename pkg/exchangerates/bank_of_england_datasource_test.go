@@ -0,0 +1,139 @@
+package exchangerates
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+)
+
+const bankOfEnglandMinimumRequiredContent = "DATE,XUDLUSS,XUDLERS,XUDLJYS\n" +
+	"15 Apr 2024,1.2448,1.1707,192.31\n" +
+	"16 Apr 2024,1.2437,1.1711,\n" +
+	"17 Apr 2024,1.2436,1.1702,\n"
+
+func TestBankOfEnglandDataSource_StandardDataExtractBaseCurrency(t *testing.T) {
+	dataSource := &BankOfEnglandDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(bankOfEnglandMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "GBP", actualLatestExchangeRateResponse.BaseCurrency)
+}
+
+func TestBankOfEnglandDataSource_StandardDataExtractExchangeRates(t *testing.T) {
+	dataSource := &BankOfEnglandDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(bankOfEnglandMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1713366000), actualLatestExchangeRateResponse.UpdateTime)
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "1.2436",
+	})
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "EUR",
+		Rate:     "1.1702",
+	})
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "JPY",
+		Rate:     "192.31",
+	})
+}
+
+func TestBankOfEnglandDataSource_BlankContent(t *testing.T) {
+	dataSource := &BankOfEnglandDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, err := dataSource.Parse(context, []byte(""))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestBankOfEnglandDataSource_OnlyTitle(t *testing.T) {
+	dataSource := &BankOfEnglandDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, err := dataSource.Parse(context, []byte("DATE,XUDLUSS,XUDLERS\n"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestBankOfEnglandDataSource_ErrorPage(t *testing.T) {
+	dataSource := &BankOfEnglandDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, err := dataSource.Parse(context, []byte("<html><body>Error</body></html>\n"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestBankOfEnglandDataSource_UnknownSeriesCode(t *testing.T) {
+	dataSource := &BankOfEnglandDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte("DATE,XUDLXXX,XUDLUSS\n"+
+		"17 Apr 2024,1,1.2436\n"))
+	assert.Equal(t, nil, err)
+	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 1)
+}
+
+func TestBankOfEnglandDataSource_InvalidRate(t *testing.T) {
+	dataSource := &BankOfEnglandDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte("DATE,XUDLERS,XUDLUSS\n"+
+		"17 Apr 2024,n/a,1.2436\n"))
+	assert.Equal(t, nil, err)
+	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 1)
+}
+
+func TestBankOfEnglandDataSource_HistoricalDataExtractAllDates(t *testing.T) {
+	dataSource := &BankOfEnglandDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualHistoricalExchangeRateResponses, err := dataSource.ParseHistorical(context, []byte(bankOfEnglandMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 3, len(actualHistoricalExchangeRateResponses))
+
+	assert.Equal(t, int64(1713193200), actualHistoricalExchangeRateResponses[0].UpdateTime)
+	assert.Equal(t, 3, len(actualHistoricalExchangeRateResponses[0].ExchangeRates))
+	assert.Contains(t, actualHistoricalExchangeRateResponses[0].ExchangeRates, &models.LatestExchangeRate{
+		Currency: "JPY",
+		Rate:     "192.31",
+	})
+
+	assert.Equal(t, int64(1713366000), actualHistoricalExchangeRateResponses[2].UpdateTime)
+	assert.Equal(t, 2, len(actualHistoricalExchangeRateResponses[2].ExchangeRates))
+	assert.Contains(t, actualHistoricalExchangeRateResponses[2].ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "1.2436",
+	})
+}
+
+func TestBankOfEnglandDataSource_HistoricalBlankContent(t *testing.T) {
+	dataSource := &BankOfEnglandDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, err := dataSource.ParseHistorical(context, []byte(""))
+	assert.NotEqual(t, nil, err)
+}
@@ -0,0 +1,288 @@
+package exchangerates
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/log"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+	"github.com/kyy-me/ezbookkeeping/pkg/utils"
+	"github.com/kyy-me/ezbookkeeping/pkg/validators"
+)
+
+const bankOfIsraelExchangeRateUrl = "https://boi.org.il/PublicApi/GetExchangeRates"
+const bankOfIsraelHistoricalExchangeRateUrl = "https://edge.boi.gov.il/FusionEdgeServer/sdmx/v2/data/dataflow/BOI.STATISTICS/EXR/1.0/?startperiod=1999-01-01&format=csv"
+const bankOfIsraelExchangeRateReferenceUrl = "https://www.boi.org.il/en/economic-roles/financial-markets/exchange-rates/"
+const bankOfIsraelDataSource = "Bank of Israel"
+const bankOfIsraelBaseCurrency = "ILS"
+
+const bankOfIsraelHistoricalDailyFrequency = "D"
+const bankOfIsraelHistoricalDataUpdateDateFormat = "2006-01-02 15:04"
+const bankOfIsraelHistoricalDataUpdateDateTimezone = "Asia/Jerusalem"
+
+// BankOfIsraelDataSource defines the structure of exchange rates data source of bank of Israel
+type BankOfIsraelDataSource struct {
+	ExchangeRatesDataSource
+}
+
+// BankOfIsraelExchangeRateData represents the whole data from bank of Israel
+type BankOfIsraelExchangeRateData struct {
+	ExchangeRates []*BankOfIsraelExchangeRate `json:"exchangeRates"`
+}
+
+// BankOfIsraelExchangeRate represents the exchange rate data from bank of Israel
+type BankOfIsraelExchangeRate struct {
+	Currency   string  `json:"key"`
+	Rate       float64 `json:"currentExchangeRate"`
+	Unit       float64 `json:"unit"`
+	LastUpdate string  `json:"lastUpdate"`
+}
+
+// ToLatestExchangeRateResponse returns a view-object according to original data from bank of Israel
+func (e *BankOfIsraelExchangeRateData) ToLatestExchangeRateResponse(c *core.Context) *models.LatestExchangeRateResponse {
+	if len(e.ExchangeRates) < 1 {
+		log.ErrorfWithRequestId(c, "[bank_of_israel_datasource.ToLatestExchangeRateResponse] exchange rates is empty")
+		return nil
+	}
+
+	exchangeRates := make(models.LatestExchangeRateSlice, 0, len(e.ExchangeRates))
+	var latestUpdateTime time.Time
+
+	for i := 0; i < len(e.ExchangeRates); i++ {
+		exchangeRate := e.ExchangeRates[i]
+
+		if _, exists := validators.AllCurrencyNames[exchangeRate.Currency]; !exists {
+			continue
+		}
+
+		finalExchangeRate := exchangeRate.ToLatestExchangeRate(c)
+
+		if finalExchangeRate == nil {
+			continue
+		}
+
+		updateTime, err := time.Parse(time.RFC3339Nano, exchangeRate.LastUpdate)
+
+		if err != nil {
+			log.WarnfWithRequestId(c, "[bank_of_israel_datasource.ToLatestExchangeRateResponse] failed to parse update time, currency is %s, time is %s", exchangeRate.Currency, exchangeRate.LastUpdate)
+			continue
+		}
+
+		if updateTime.After(latestUpdateTime) {
+			latestUpdateTime = updateTime
+		}
+
+		exchangeRates = append(exchangeRates, finalExchangeRate)
+	}
+
+	if len(exchangeRates) < 1 {
+		log.ErrorfWithRequestId(c, "[bank_of_israel_datasource.ToLatestExchangeRateResponse] no valid exchange rate")
+		return nil
+	}
+
+	latestExchangeRateResp := &models.LatestExchangeRateResponse{
+		DataSource:    bankOfIsraelDataSource,
+		ReferenceUrl:  bankOfIsraelExchangeRateReferenceUrl,
+		UpdateTime:    latestUpdateTime.Unix(),
+		BaseCurrency:  bankOfIsraelBaseCurrency,
+		ExchangeRates: exchangeRates,
+	}
+
+	return latestExchangeRateResp
+}
+
+// ToLatestExchangeRate returns a data pair according to original data from bank of Israel
+func (e *BankOfIsraelExchangeRate) ToLatestExchangeRate(c *core.Context) *models.LatestExchangeRate {
+	if e.Rate <= 0 || e.Unit <= 0 {
+		log.WarnfWithRequestId(c, "[bank_of_israel_datasource.ToLatestExchangeRate] rate is invalid, currency is %s, rate is %f, unit is %f", e.Currency, e.Rate, e.Unit)
+		return nil
+	}
+
+	finalRate := e.Unit / e.Rate // The rates are the amount of new shekel per unit of foreign currency
+
+	if math.IsInf(finalRate, 0) {
+		return nil
+	}
+
+	return &models.LatestExchangeRate{
+		Currency: e.Currency,
+		Rate:     utils.Float64ToString(finalRate),
+	}
+}
+
+// GetRequestUrls returns the bank of Israel data source urls
+func (e *BankOfIsraelDataSource) GetRequestUrls() []string {
+	return []string{bankOfIsraelExchangeRateUrl}
+}
+
+// GetPublishingInterval returns the publishing interval of bank of Israel data source, which publishes new exchange rates once per business day
+func (e *BankOfIsraelDataSource) GetPublishingInterval() time.Duration {
+	return dailyExchangeRatesPublishingInterval
+}
+
+// GetHistoricalRequestUrls returns the bank of Israel historical data source urls
+func (e *BankOfIsraelDataSource) GetHistoricalRequestUrls() []string {
+	return []string{bankOfIsraelHistoricalExchangeRateUrl}
+}
+
+// Parse returns the common response entity according to the bank of Israel data source raw response
+func (e *BankOfIsraelDataSource) Parse(c *core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
+	bankOfIsraelData := &BankOfIsraelExchangeRateData{}
+	err := json.Unmarshal(content, bankOfIsraelData)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[bank_of_israel_datasource.Parse] failed to parse json data, content is %s, because %s", string(content), err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	latestExchangeRateResponse := bankOfIsraelData.ToLatestExchangeRateResponse(c)
+
+	if latestExchangeRateResponse == nil {
+		log.ErrorfWithRequestId(c, "[bank_of_israel_datasource.Parse] failed to parse latest exchange rate data, content is %s", string(content))
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return latestExchangeRateResponse, nil
+}
+
+// ParseHistorical returns the common response entities of all dates according to the bank of Israel historical data source raw response
+func (e *BankOfIsraelDataSource) ParseHistorical(c *core.Context, content []byte) ([]*models.LatestExchangeRateResponse, error) {
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = -1
+
+	allLines, err := reader.ReadAll()
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[bank_of_israel_datasource.ParseHistorical] failed to parse csv data, because %s", err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	if len(allLines) < 2 {
+		log.ErrorfWithRequestId(c, "[bank_of_israel_datasource.ParseHistorical] content is invalid, content is %s", string(content))
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	titleItemMap := make(map[string]int)
+
+	for i := 0; i < len(allLines[0]); i++ {
+		titleItemMap[strings.TrimSpace(allLines[0][i])] = i
+	}
+
+	currencyCodeColumnIndex, currencyCodeColumnExists := titleItemMap["BASE_CURRENCY"]
+	counterCurrencyColumnIndex, counterCurrencyColumnExists := titleItemMap["COUNTER_CURRENCY"]
+	dateColumnIndex, dateColumnExists := titleItemMap["TIME_PERIOD"]
+	rateColumnIndex, rateColumnExists := titleItemMap["OBS_VALUE"]
+
+	if !currencyCodeColumnExists || !counterCurrencyColumnExists || !dateColumnExists || !rateColumnExists {
+		log.ErrorfWithRequestId(c, "[bank_of_israel_datasource.ParseHistorical] missing column in title line, title line is %s", strings.Join(allLines[0], ","))
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	frequencyColumnIndex, frequencyColumnExists := titleItemMap["FREQ"]
+	unitMultiplierColumnIndex, unitMultiplierColumnExists := titleItemMap["UNIT_MULT"]
+
+	timezone, err := time.LoadLocation(bankOfIsraelHistoricalDataUpdateDateTimezone)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[bank_of_israel_datasource.ParseHistorical] failed to get timezone, timezone name is %s", bankOfIsraelHistoricalDataUpdateDateTimezone)
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	dates := make([]string, 0)
+	allExchangeRates := make(map[string]models.LatestExchangeRateSlice)
+
+	for i := 1; i < len(allLines); i++ {
+		items := allLines[i]
+
+		if currencyCodeColumnIndex >= len(items) || counterCurrencyColumnIndex >= len(items) || dateColumnIndex >= len(items) || rateColumnIndex >= len(items) {
+			continue
+		}
+
+		if strings.TrimSpace(items[counterCurrencyColumnIndex]) != bankOfIsraelBaseCurrency {
+			continue
+		}
+
+		if frequencyColumnExists && frequencyColumnIndex < len(items) && strings.TrimSpace(items[frequencyColumnIndex]) != bankOfIsraelHistoricalDailyFrequency {
+			continue
+		}
+
+		currencyCode := strings.TrimSpace(items[currencyCodeColumnIndex])
+
+		if _, exists := validators.AllCurrencyNames[currencyCode]; !exists {
+			continue
+		}
+
+		rate, err := utils.StringToFloat64(strings.TrimSpace(items[rateColumnIndex]))
+
+		if err != nil {
+			log.WarnfWithRequestId(c, "[bank_of_israel_datasource.ParseHistorical] failed to parse rate, currency is %s, rate is %s", currencyCode, items[rateColumnIndex])
+			continue
+		}
+
+		unitMultiplier := 0
+
+		if unitMultiplierColumnExists && unitMultiplierColumnIndex < len(items) && strings.TrimSpace(items[unitMultiplierColumnIndex]) != "" {
+			unitMultiplier, err = utils.StringToInt(strings.TrimSpace(items[unitMultiplierColumnIndex]))
+
+			if err != nil || unitMultiplier < 0 {
+				log.WarnfWithRequestId(c, "[bank_of_israel_datasource.ParseHistorical] failed to parse unit multiplier, currency is %s, unit multiplier is %s", currencyCode, items[unitMultiplierColumnIndex])
+				continue
+			}
+		}
+
+		exchangeRate := &BankOfIsraelExchangeRate{
+			Currency: currencyCode,
+			Rate:     rate,
+			Unit:     math.Pow10(unitMultiplier), // The rates are the amount of new shekel per 10^unit_mult units of foreign currency
+		}
+
+		finalExchangeRate := exchangeRate.ToLatestExchangeRate(c)
+
+		if finalExchangeRate == nil {
+			continue
+		}
+
+		date := strings.TrimSpace(items[dateColumnIndex])
+
+		if _, exists := allExchangeRates[date]; !exists {
+			dates = append(dates, date)
+		}
+
+		allExchangeRates[date] = append(allExchangeRates[date], finalExchangeRate)
+	}
+
+	sort.Strings(dates)
+	historicalExchangeRateResponses := make([]*models.LatestExchangeRateResponse, 0, len(dates))
+
+	for i := 0; i < len(dates); i++ {
+		updateDateTime := dates[i] + " 15:30" // The representative exchange rates are published around 3:30 p.m. on business days
+		updateTime, err := time.ParseInLocation(bankOfIsraelHistoricalDataUpdateDateFormat, updateDateTime, timezone)
+
+		if err != nil {
+			log.ErrorfWithRequestId(c, "[bank_of_israel_datasource.ParseHistorical] failed to parse update date, datetime is %s", updateDateTime)
+			return nil, errs.ErrFailedToRequestRemoteApi
+		}
+
+		historicalExchangeRateResponses = append(historicalExchangeRateResponses, &models.LatestExchangeRateResponse{
+			DataSource:    bankOfIsraelDataSource,
+			ReferenceUrl:  bankOfIsraelExchangeRateReferenceUrl,
+			UpdateTime:    updateTime.Unix(),
+			BaseCurrency:  bankOfIsraelBaseCurrency,
+			ExchangeRates: allExchangeRates[dates[i]],
+		})
+	}
+
+	if len(historicalExchangeRateResponses) < 1 {
+		log.ErrorfWithRequestId(c, "[bank_of_israel_datasource.ParseHistorical] no valid historical exchange rate")
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return historicalExchangeRateResponses, nil
+}
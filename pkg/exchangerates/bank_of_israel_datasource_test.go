@@ -0,0 +1,173 @@
+package exchangerates
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+)
+
+const bankOfIsraelMinimumRequiredContent = "{\n" +
+	"  \"exchangeRates\": [\n" +
+	"    {\n" +
+	"      \"key\": \"USD\",\n" +
+	"      \"currentExchangeRate\": 3.765,\n" +
+	"      \"currentChange\": 0.187,\n" +
+	"      \"unit\": 1,\n" +
+	"      \"lastUpdate\": \"2024-04-17T12:25:06.7213457Z\"\n" +
+	"    },\n" +
+	"    {\n" +
+	"      \"key\": \"JPY\",\n" +
+	"      \"currentExchangeRate\": 2.4342,\n" +
+	"      \"currentChange\": -0.101,\n" +
+	"      \"unit\": 100,\n" +
+	"      \"lastUpdate\": \"2024-04-17T12:25:06.7213457Z\"\n" +
+	"    }\n" +
+	"  ]\n" +
+	"}"
+
+func TestBankOfIsraelDataSource_StandardDataExtractBaseCurrency(t *testing.T) {
+	dataSource := &BankOfIsraelDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(bankOfIsraelMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "ILS", actualLatestExchangeRateResponse.BaseCurrency)
+}
+
+func TestBankOfIsraelDataSource_StandardDataExtractExchangeRates(t *testing.T) {
+	dataSource := &BankOfIsraelDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(bankOfIsraelMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1713356706), actualLatestExchangeRateResponse.UpdateTime)
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "0.2656042496679947",
+	})
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "JPY",
+		Rate:     "41.08125872976748",
+	})
+}
+
+func TestBankOfIsraelDataSource_BlankContent(t *testing.T) {
+	dataSource := &BankOfIsraelDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, err := dataSource.Parse(context, []byte(""))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestBankOfIsraelDataSource_EmptyExchangeRates(t *testing.T) {
+	dataSource := &BankOfIsraelDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, err := dataSource.Parse(context, []byte("{\"exchangeRates\":[]}"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestBankOfIsraelDataSource_InvalidCurrency(t *testing.T) {
+	dataSource := &BankOfIsraelDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte("{\"exchangeRates\":["+
+		"{\"key\":\"XXX\",\"currentExchangeRate\":1,\"unit\":1,\"lastUpdate\":\"2024-04-17T12:25:06.7213457Z\"},"+
+		"{\"key\":\"USD\",\"currentExchangeRate\":3.765,\"unit\":1,\"lastUpdate\":\"2024-04-17T12:25:06.7213457Z\"}"+
+		"]}"))
+	assert.Equal(t, nil, err)
+	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 1)
+}
+
+func TestBankOfIsraelDataSource_InvalidRate(t *testing.T) {
+	dataSource := &BankOfIsraelDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte("{\"exchangeRates\":["+
+		"{\"key\":\"EUR\",\"currentExchangeRate\":0,\"unit\":1,\"lastUpdate\":\"2024-04-17T12:25:06.7213457Z\"},"+
+		"{\"key\":\"USD\",\"currentExchangeRate\":3.765,\"unit\":1,\"lastUpdate\":\"2024-04-17T12:25:06.7213457Z\"}"+
+		"]}"))
+	assert.Equal(t, nil, err)
+	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 1)
+}
+
+func TestBankOfIsraelDataSource_InvalidUpdateTime(t *testing.T) {
+	dataSource := &BankOfIsraelDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, err := dataSource.Parse(context, []byte("{\"exchangeRates\":["+
+		"{\"key\":\"USD\",\"currentExchangeRate\":3.765,\"unit\":1,\"lastUpdate\":\"2024/04/17\"}"+
+		"]}"))
+	assert.NotEqual(t, nil, err)
+}
+
+const bankOfIsraelHistoricalContent = "SERIES_CODE,FREQ,BASE_CURRENCY,COUNTER_CURRENCY,UNIT_MULT,TIME_PERIOD,OBS_VALUE\n" +
+	"RER_USD_ILS,D,USD,ILS,0,2024-04-16,3.765\n" +
+	"RER_USD_ILS,D,USD,ILS,0,2024-04-17,3.8\n" +
+	"RER_JPY_ILS,D,JPY,ILS,2,2024-04-17,2.4342\n" +
+	"RER_USD_ILS_M,M,USD,ILS,0,2024-04,3.7\n" +
+	"RER_ILS_USD,D,ILS,USD,0,2024-04-17,0.26\n"
+
+func TestBankOfIsraelDataSource_HistoricalDataExtractAllDates(t *testing.T) {
+	dataSource := &BankOfIsraelDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualHistoricalExchangeRateResponses, err := dataSource.ParseHistorical(context, []byte(bankOfIsraelHistoricalContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(actualHistoricalExchangeRateResponses))
+
+	assert.Equal(t, int64(1713270600), actualHistoricalExchangeRateResponses[0].UpdateTime)
+	assert.Equal(t, 1, len(actualHistoricalExchangeRateResponses[0].ExchangeRates))
+	assert.Contains(t, actualHistoricalExchangeRateResponses[0].ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "0.2656042496679947",
+	})
+
+	assert.Equal(t, int64(1713357000), actualHistoricalExchangeRateResponses[1].UpdateTime)
+	assert.Equal(t, 2, len(actualHistoricalExchangeRateResponses[1].ExchangeRates))
+	assert.Contains(t, actualHistoricalExchangeRateResponses[1].ExchangeRates, &models.LatestExchangeRate{
+		Currency: "JPY",
+		Rate:     "41.08125872976748",
+	})
+}
+
+func TestBankOfIsraelDataSource_HistoricalMissingColumn(t *testing.T) {
+	dataSource := &BankOfIsraelDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, err := dataSource.ParseHistorical(context, []byte("SERIES_CODE,FREQ,BASE_CURRENCY,TIME_PERIOD,OBS_VALUE\n"+
+		"RER_USD_ILS,D,USD,2024-04-17,3.8\n"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestBankOfIsraelDataSource_HistoricalBlankContent(t *testing.T) {
+	dataSource := &BankOfIsraelDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, err := dataSource.ParseHistorical(context, []byte(""))
+	assert.NotEqual(t, nil, err)
+}
@@ -0,0 +1,75 @@
+package exchangerates
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/log"
+	"github.com/kyy-me/ezbookkeeping/pkg/settings"
+	"github.com/kyy-me/ezbookkeeping/pkg/utils"
+)
+
+const commodityQuoteCodePlaceholder = "{code}"
+const commodityQuoteCurrencyPlaceholder = "{currency}"
+const commodityQuoteMaxPriceLength = 32
+
+// GetCommodityQuotePrice requests the commodity quote endpoint and returns the textual price of 1 unit of the specified commodity in the specified currency
+func GetCommodityQuotePrice(c *core.Context, commodityCode string, currency string) (string, error) {
+	quoteUrl := settings.Container.Current.CommodityQuoteUrl
+
+	if quoteUrl == "" {
+		return "", errs.ErrCommodityQuoteEndpointNotSet
+	}
+
+	requestUrl := strings.NewReplacer(
+		commodityQuoteCodePlaceholder, url.QueryEscape(commodityCode),
+		commodityQuoteCurrencyPlaceholder, url.QueryEscape(currency),
+	).Replace(quoteUrl)
+
+	body, err := requestDataSource(c, requestUrl)
+
+	if err != nil {
+		return "", err
+	}
+
+	price, err := ParseCommodityQuotePrice(body, settings.Container.Current.CommodityQuotePricePath)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[commodity_quote_requester.GetCommodityQuotePrice] failed to parse price of commodity \"%s\" in \"%s\", content is %s", commodityCode, currency, string(body))
+		return "", err
+	}
+
+	return price, nil
+}
+
+// ParseCommodityQuotePrice returns the textual price which is read from the specified path of json content
+func ParseCommodityQuotePrice(content []byte, pricePath string) (string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+
+	var root any
+	err := decoder.Decode(&root)
+
+	if err != nil {
+		return "", errs.ErrFailedToRequestRemoteApi
+	}
+
+	price, exists := getCustomDataSourceJsonText(getCustomDataSourceJsonValue(root, pricePath))
+	price = strings.TrimSpace(price)
+
+	if !exists || price == "" || len(price) > commodityQuoteMaxPriceLength {
+		return "", errs.ErrFailedToRequestRemoteApi
+	}
+
+	value, err := utils.ParseDecimal(price)
+
+	if err != nil || value.Sign() <= 0 {
+		return "", errs.ErrFailedToRequestRemoteApi
+	}
+
+	return price, nil
+}
@@ -0,0 +1,76 @@
+package exchangerates
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/settings"
+)
+
+const commodityQuoteJsonContent = "{\n" +
+	"  \"data\": {\n" +
+	"    \"symbol\": \"BTC\",\n" +
+	"    \"quotes\": [\n" +
+	"      {\"currency\": \"USD\", \"price\": 64321.57}\n" +
+	"    ]\n" +
+	"  }\n" +
+	"}"
+
+func TestGetCommodityQuotePrice(t *testing.T) {
+	requestedQuery := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedQuery = r.URL.RawQuery
+		w.Write([]byte(commodityQuoteJsonContent))
+	}))
+	defer server.Close()
+
+	settings.SetCurrentConfig(&settings.Config{
+		ExchangeRatesProxy:          "none",
+		ExchangeRatesRequestTimeout: 5000,
+		CommodityQuoteUrl:           server.URL + "/quote?symbol={code}&convert={currency}",
+		CommodityQuotePricePath:     "data.quotes.0.price",
+	})
+
+	context := &core.Context{Context: &gin.Context{}}
+	actualPrice, err := GetCommodityQuotePrice(context, "BTC", "USD")
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "symbol=BTC&convert=USD", requestedQuery)
+	assert.Equal(t, "64321.57", actualPrice)
+}
+
+func TestGetCommodityQuotePrice_QuoteUrlNotSet(t *testing.T) {
+	settings.SetCurrentConfig(&settings.Config{})
+
+	context := &core.Context{Context: &gin.Context{}}
+	_, err := GetCommodityQuotePrice(context, "BTC", "USD")
+
+	assert.Equal(t, errs.ErrCommodityQuoteEndpointNotSet, err)
+}
+
+func TestParseCommodityQuotePrice_TextualPrice(t *testing.T) {
+	actualPrice, err := ParseCommodityQuotePrice([]byte("{\"price\": \" 2315.4 \"}"), "price")
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "2315.4", actualPrice)
+}
+
+func TestParseCommodityQuotePrice_InvalidContent(t *testing.T) {
+	_, err := ParseCommodityQuotePrice([]byte("null"), "price")
+	assert.NotEqual(t, nil, err)
+
+	_, err = ParseCommodityQuotePrice([]byte("{\"price\": 1}"), "data.price")
+	assert.NotEqual(t, nil, err)
+
+	_, err = ParseCommodityQuotePrice([]byte("{\"price\": 0}"), "price")
+	assert.NotEqual(t, nil, err)
+
+	_, err = ParseCommodityQuotePrice([]byte("{\"price\": \"N/A\"}"), "price")
+	assert.NotEqual(t, nil, err)
+}
@@ -0,0 +1,503 @@
+package exchangerates
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/log"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+	"github.com/kyy-me/ezbookkeeping/pkg/settings"
+	"github.com/kyy-me/ezbookkeeping/pkg/utils"
+	"github.com/kyy-me/ezbookkeeping/pkg/validators"
+)
+
+// The special paths which can be used in the currency path and rate path of each exchange rate item
+const customDataSourceItemSelfPath = "$"
+const customDataSourceItemKeyPath = "$key"
+
+const customDataSourceJsonPathSeparator = "."
+const customDataSourceXmlPathSeparator = "/"
+const customDataSourceXmlAttributePrefix = "@"
+
+const customDataSourceUnixTimeFormat = "unix"
+const customDataSourceUnixMilliTimeFormat = "unix_milli"
+
+// customDataSourceValueGetter returns the text value of the specified path, returns false if the path does not exist
+type customDataSourceValueGetter func(path string) (string, bool)
+
+// CustomDataSource defines the structure of exchange rates data source which is declaratively configured in config file
+type CustomDataSource struct {
+	ExchangeRatesDataSource
+	name             string
+	url              string
+	referenceUrl     string
+	format           string
+	baseCurrency     string
+	baseCurrencyPath string
+	ratesPath        string
+	currencyPath     string
+	ratePath         string
+	rateInverted     bool
+	updateTimePath   string
+	updateTimeFormat string
+	updateInterval   time.Duration
+}
+
+// customDataSourceXmlNode represents an element of xml document
+type customDataSourceXmlNode struct {
+	name       string
+	attributes map[string]string
+	children   []*customDataSourceXmlNode
+	text       strings.Builder
+}
+
+// NewCustomDataSource returns a new custom exchange rates data source according to the config
+func NewCustomDataSource(config *settings.Config) *CustomDataSource {
+	return &CustomDataSource{
+		name:             config.ExchangeRatesCustomDataSourceName,
+		url:              config.ExchangeRatesCustomDataSourceUrl,
+		referenceUrl:     config.ExchangeRatesCustomDataSourceReferenceUrl,
+		format:           config.ExchangeRatesCustomDataSourceFormat,
+		baseCurrency:     config.ExchangeRatesCustomDataSourceBaseCurrency,
+		baseCurrencyPath: config.ExchangeRatesCustomDataSourceBaseCurrencyPath,
+		ratesPath:        config.ExchangeRatesCustomDataSourceRatesPath,
+		currencyPath:     config.ExchangeRatesCustomDataSourceCurrencyPath,
+		ratePath:         config.ExchangeRatesCustomDataSourceRatePath,
+		rateInverted:     config.ExchangeRatesCustomDataSourceRateInverted,
+		updateTimePath:   config.ExchangeRatesCustomDataSourceUpdateTimePath,
+		updateTimeFormat: config.ExchangeRatesCustomDataSourceUpdateTimeFormat,
+		updateInterval:   time.Duration(config.ExchangeRatesCustomDataSourceUpdateInterval) * time.Second,
+	}
+}
+
+// GetRequestUrls returns the custom data source urls
+func (e *CustomDataSource) GetRequestUrls() []string {
+	return []string{e.url}
+}
+
+// GetPublishingInterval returns the update interval of custom data source which is configured in config file
+func (e *CustomDataSource) GetPublishingInterval() time.Duration {
+	return e.updateInterval
+}
+
+// Parse returns the common response entity according to the custom data source raw response
+func (e *CustomDataSource) Parse(c *core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
+	var rootValueGetter customDataSourceValueGetter
+	var itemValueGetters []customDataSourceValueGetter
+	var err error
+
+	if e.format == settings.CustomExchangeRatesDataSourceJsonFormat {
+		rootValueGetter, itemValueGetters, err = e.parseJson(content)
+	} else if e.format == settings.CustomExchangeRatesDataSourceXmlFormat {
+		rootValueGetter, itemValueGetters, err = e.parseXml(content)
+	} else if e.format == settings.CustomExchangeRatesDataSourceCsvFormat {
+		rootValueGetter, itemValueGetters, err = e.parseCsv(content)
+	} else {
+		return nil, errs.ErrInvalidCustomExchangeRatesDataSourceConfig
+	}
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[custom_datasource.Parse] failed to parse %s data, content is %s, because %s", e.format, string(content), err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	baseCurrency := e.baseCurrency
+
+	if e.baseCurrencyPath != "" {
+		if value, exists := rootValueGetter(e.baseCurrencyPath); exists && value != "" {
+			baseCurrency = strings.ToUpper(strings.TrimSpace(value))
+		}
+	}
+
+	if _, exists := validators.AllCurrencyNames[baseCurrency]; !exists {
+		log.ErrorfWithRequestId(c, "[custom_datasource.Parse] base currency \"%s\" is invalid", baseCurrency)
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	updateTime := time.Now().Unix()
+
+	if e.updateTimePath != "" {
+		updateTimeValue, exists := rootValueGetter(e.updateTimePath)
+
+		if !exists {
+			log.ErrorfWithRequestId(c, "[custom_datasource.Parse] missing update time, path is %s", e.updateTimePath)
+			return nil, errs.ErrFailedToRequestRemoteApi
+		}
+
+		updateTime, err = e.parseUpdateTime(strings.TrimSpace(updateTimeValue))
+
+		if err != nil {
+			log.ErrorfWithRequestId(c, "[custom_datasource.Parse] failed to parse update time \"%s\", because %s", updateTimeValue, err.Error())
+			return nil, errs.ErrFailedToRequestRemoteApi
+		}
+	}
+
+	exchangeRates := make(models.LatestExchangeRateSlice, 0, len(itemValueGetters))
+
+	for i := 0; i < len(itemValueGetters); i++ {
+		exchangeRate := e.parseExchangeRate(c, baseCurrency, itemValueGetters[i])
+
+		if exchangeRate != nil {
+			exchangeRates = append(exchangeRates, exchangeRate)
+		}
+	}
+
+	if len(exchangeRates) < 1 {
+		log.ErrorfWithRequestId(c, "[custom_datasource.Parse] no valid exchange rate, content is %s", string(content))
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	latestExchangeRateResp := &models.LatestExchangeRateResponse{
+		DataSource:    e.name,
+		ReferenceUrl:  e.referenceUrl,
+		UpdateTime:    updateTime,
+		BaseCurrency:  baseCurrency,
+		ExchangeRates: exchangeRates,
+	}
+
+	return latestExchangeRateResp, nil
+}
+
+func (e *CustomDataSource) parseExchangeRate(c *core.Context, baseCurrency string, valueGetter customDataSourceValueGetter) *models.LatestExchangeRate {
+	currency, exists := valueGetter(e.currencyPath)
+
+	if !exists {
+		log.WarnfWithRequestId(c, "[custom_datasource.parseExchangeRate] missing currency, path is %s", e.currencyPath)
+		return nil
+	}
+
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+
+	if _, exists := validators.AllCurrencyNames[currency]; !exists || currency == baseCurrency {
+		return nil
+	}
+
+	rateValue, exists := valueGetter(e.ratePath)
+
+	if !exists {
+		log.WarnfWithRequestId(c, "[custom_datasource.parseExchangeRate] missing rate, currency is %s, path is %s", currency, e.ratePath)
+		return nil
+	}
+
+	rate, err := utils.StringToFloat64(strings.TrimSpace(rateValue))
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[custom_datasource.parseExchangeRate] failed to parse rate, currency is %s, rate is %s", currency, rateValue)
+		return nil
+	}
+
+	if math.IsNaN(rate) || math.IsInf(rate, 0) || rate <= 0 {
+		log.WarnfWithRequestId(c, "[custom_datasource.parseExchangeRate] rate is invalid, currency is %s, rate is %s", currency, rateValue)
+		return nil
+	}
+
+	if e.rateInverted {
+		rate = 1 / rate
+	}
+
+	return &models.LatestExchangeRate{
+		Currency: currency,
+		Rate:     utils.Float64ToString(rate),
+	}
+}
+
+func (e *CustomDataSource) parseUpdateTime(value string) (int64, error) {
+	if e.updateTimeFormat == customDataSourceUnixTimeFormat || e.updateTimeFormat == customDataSourceUnixMilliTimeFormat {
+		unixTime, err := utils.StringToInt64(value)
+
+		if err != nil {
+			return 0, err
+		}
+
+		if e.updateTimeFormat == customDataSourceUnixMilliTimeFormat {
+			unixTime = unixTime / 1000
+		}
+
+		return unixTime, nil
+	}
+
+	updateTime, err := time.Parse(e.updateTimeFormat, value)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return updateTime.Unix(), nil
+}
+
+func (e *CustomDataSource) parseJson(content []byte) (customDataSourceValueGetter, []customDataSourceValueGetter, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+
+	var root any
+	err := decoder.Decode(&root)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rootValueGetter := func(path string) (string, bool) {
+		return getCustomDataSourceJsonText(getCustomDataSourceJsonValue(root, path))
+	}
+
+	rates, exists := getCustomDataSourceJsonValue(root, e.ratesPath)
+
+	if !exists {
+		return nil, nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	var itemValueGetters []customDataSourceValueGetter
+
+	if items, ok := rates.([]any); ok {
+		itemValueGetters = make([]customDataSourceValueGetter, 0, len(items))
+
+		for i := 0; i < len(items); i++ {
+			itemValueGetters = append(itemValueGetters, newCustomDataSourceJsonItemValueGetter("", items[i]))
+		}
+	} else if items, ok := rates.(map[string]any); ok {
+		keys := make([]string, 0, len(items))
+
+		for key := range items {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+		itemValueGetters = make([]customDataSourceValueGetter, 0, len(keys))
+
+		for i := 0; i < len(keys); i++ {
+			itemValueGetters = append(itemValueGetters, newCustomDataSourceJsonItemValueGetter(keys[i], items[keys[i]]))
+		}
+	} else {
+		return nil, nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return rootValueGetter, itemValueGetters, nil
+}
+
+func (e *CustomDataSource) parseXml(content []byte) (customDataSourceValueGetter, []customDataSourceValueGetter, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	var root *customDataSourceXmlNode
+	var stack []*customDataSourceXmlNode
+
+	for {
+		token, err := decoder.Token()
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, nil, err
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			node := &customDataSourceXmlNode{
+				name:       element.Name.Local,
+				attributes: make(map[string]string, len(element.Attr)),
+			}
+
+			for i := 0; i < len(element.Attr); i++ {
+				node.attributes[element.Attr[i].Name.Local] = element.Attr[i].Value
+			}
+
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			} else if root == nil {
+				root = node
+			}
+
+			stack = append(stack, node)
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(element)
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	document := &customDataSourceXmlNode{
+		children: []*customDataSourceXmlNode{root},
+	}
+
+	rootValueGetter := func(path string) (string, bool) {
+		return document.getValue(path)
+	}
+
+	items := document.findAll(e.ratesPath)
+	itemValueGetters := make([]customDataSourceValueGetter, 0, len(items))
+
+	for i := 0; i < len(items); i++ {
+		itemValueGetters = append(itemValueGetters, items[i].getValue)
+	}
+
+	return rootValueGetter, itemValueGetters, nil
+}
+
+func (e *CustomDataSource) parseCsv(content []byte) (customDataSourceValueGetter, []customDataSourceValueGetter, error) {
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = -1
+
+	allLines, err := reader.ReadAll()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(allLines) < 2 {
+		return nil, nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	titleItemMap := make(map[string]int, len(allLines[0]))
+
+	for i := 0; i < len(allLines[0]); i++ {
+		titleItemMap[strings.TrimSpace(allLines[0][i])] = i
+	}
+
+	itemValueGetters := make([]customDataSourceValueGetter, 0, len(allLines)-1)
+
+	for i := 1; i < len(allLines); i++ {
+		items := allLines[i]
+
+		itemValueGetters = append(itemValueGetters, func(path string) (string, bool) {
+			index, exists := titleItemMap[path]
+
+			if !exists || index >= len(items) {
+				return "", false
+			}
+
+			return items[index], true
+		})
+	}
+
+	// The base currency and update time are read from the first data line
+	return itemValueGetters[0], itemValueGetters, nil
+}
+
+func newCustomDataSourceJsonItemValueGetter(key string, item any) customDataSourceValueGetter {
+	return func(path string) (string, bool) {
+		if path == customDataSourceItemKeyPath {
+			return key, key != ""
+		} else if path == customDataSourceItemSelfPath {
+			return getCustomDataSourceJsonText(item, true)
+		}
+
+		return getCustomDataSourceJsonText(getCustomDataSourceJsonValue(item, path))
+	}
+}
+
+func getCustomDataSourceJsonValue(node any, path string) (any, bool) {
+	if path == "" {
+		return node, true
+	}
+
+	segments := strings.Split(path, customDataSourceJsonPathSeparator)
+
+	for i := 0; i < len(segments); i++ {
+		switch value := node.(type) {
+		case map[string]any:
+			child, exists := value[segments[i]]
+
+			if !exists {
+				return nil, false
+			}
+
+			node = child
+		case []any:
+			index, err := utils.StringToInt(segments[i])
+
+			if err != nil || index < 0 || index >= len(value) {
+				return nil, false
+			}
+
+			node = value[index]
+		default:
+			return nil, false
+		}
+	}
+
+	return node, true
+}
+
+func getCustomDataSourceJsonText(value any, exists bool) (string, bool) {
+	if !exists {
+		return "", false
+	}
+
+	switch text := value.(type) {
+	case string:
+		return text, true
+	case json.Number:
+		return text.String(), true
+	default:
+		return "", false
+	}
+}
+
+func (n *customDataSourceXmlNode) findAll(path string) []*customDataSourceXmlNode {
+	nodes := []*customDataSourceXmlNode{n}
+	segments := strings.Split(strings.Trim(path, customDataSourceXmlPathSeparator), customDataSourceXmlPathSeparator)
+
+	for i := 0; i < len(segments); i++ {
+		if segments[i] == "" {
+			continue
+		}
+
+		children := make([]*customDataSourceXmlNode, 0)
+
+		for j := 0; j < len(nodes); j++ {
+			for k := 0; k < len(nodes[j].children); k++ {
+				if nodes[j].children[k].name == segments[i] {
+					children = append(children, nodes[j].children[k])
+				}
+			}
+		}
+
+		nodes = children
+	}
+
+	return nodes
+}
+
+func (n *customDataSourceXmlNode) getValue(path string) (string, bool) {
+	if path == customDataSourceItemSelfPath {
+		return n.text.String(), true
+	}
+
+	elementPath := path
+	attributeName := ""
+
+	if index := strings.LastIndex(path, customDataSourceXmlAttributePrefix); index >= 0 {
+		elementPath = path[:index]
+		attributeName = path[index+len(customDataSourceXmlAttributePrefix):]
+	}
+
+	nodes := n.findAll(elementPath)
+
+	if len(nodes) < 1 {
+		return "", false
+	}
+
+	if attributeName == "" {
+		return nodes[0].text.String(), true
+	}
+
+	value, exists := nodes[0].attributes[attributeName]
+
+	return value, exists
+}
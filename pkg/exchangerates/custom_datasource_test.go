@@ -0,0 +1,254 @@
+package exchangerates
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+	"github.com/kyy-me/ezbookkeeping/pkg/settings"
+)
+
+const customDataSourceJsonContent = "{\n" +
+	"  \"base\": \"EUR\",\n" +
+	"  \"timestamp\": 1713312000,\n" +
+	"  \"rates\": {\n" +
+	"    \"USD\": 1.0637,\n" +
+	"    \"JPY\": \"164.54\",\n" +
+	"    \"XXX\": 1\n" +
+	"  }\n" +
+	"}"
+
+const customDataSourceXmlContent = "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n" +
+	"<gesmes:Envelope xmlns:gesmes=\"http://www.gesmes.org/xml/2002-08-01\" xmlns=\"http://www.ecb.int/vocabulary/2002-08-01/eurofxref\">\n" +
+	"  <Cube>\n" +
+	"    <Cube time=\"2024-04-17\">\n" +
+	"      <Cube currency=\"USD\" rate=\"1.0637\"/>\n" +
+	"      <Cube currency=\"JPY\" rate=\"164.54\"/>\n" +
+	"    </Cube>\n" +
+	"  </Cube>\n" +
+	"</gesmes:Envelope>"
+
+const customDataSourceCsvContent = "base,currency,rate,time\n" +
+	"USD,EUR,2,2024-04-17T16:00:00Z\n" +
+	"USD,JPY,0.0064,2024-04-17T16:00:00Z\n"
+
+func newTestCustomDataSource(t *testing.T, content string, config *settings.Config) *CustomDataSource {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(content))
+	}))
+	t.Cleanup(server.Close)
+
+	config.ExchangeRatesProxy = "none"
+	config.ExchangeRatesRequestTimeout = 5000
+	config.ExchangeRatesCustomDataSourceName = "Test"
+	config.ExchangeRatesCustomDataSourceUrl = server.URL
+	settings.SetCurrentConfig(config)
+
+	return NewCustomDataSource(config)
+}
+
+func TestCustomDataSource_JsonFormat(t *testing.T) {
+	dataSource := newTestCustomDataSource(t, customDataSourceJsonContent, &settings.Config{
+		ExchangeRatesCustomDataSourceFormat:           settings.CustomExchangeRatesDataSourceJsonFormat,
+		ExchangeRatesCustomDataSourceBaseCurrencyPath: "base",
+		ExchangeRatesCustomDataSourceRatesPath:        "rates",
+		ExchangeRatesCustomDataSourceCurrencyPath:     "$key",
+		ExchangeRatesCustomDataSourceRatePath:         "$",
+		ExchangeRatesCustomDataSourceUpdateTimePath:   "timestamp",
+		ExchangeRatesCustomDataSourceUpdateTimeFormat: "unix",
+	})
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualLatestExchangeRateResponse, err := GetLatestExchangeRates(context, dataSource)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "Test", actualLatestExchangeRateResponse.DataSource)
+	assert.Equal(t, "EUR", actualLatestExchangeRateResponse.BaseCurrency)
+	assert.Equal(t, int64(1713312000), actualLatestExchangeRateResponse.UpdateTime)
+	assert.Equal(t, 3, len(actualLatestExchangeRateResponse.ExchangeRates))
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "1.0637",
+	})
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "JPY",
+		Rate:     "164.54",
+	})
+}
+
+func TestCustomDataSource_JsonFormatArrayItems(t *testing.T) {
+	dataSource := NewCustomDataSource(&settings.Config{
+		ExchangeRatesCustomDataSourceFormat:       settings.CustomExchangeRatesDataSourceJsonFormat,
+		ExchangeRatesCustomDataSourceBaseCurrency: "EUR",
+		ExchangeRatesCustomDataSourceRatesPath:    "data.items",
+		ExchangeRatesCustomDataSourceCurrencyPath: "code",
+		ExchangeRatesCustomDataSourceRatePath:     "value",
+	})
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte("{\"data\":{\"items\":[{\"code\":\"usd\",\"value\":1.0637},{\"code\":\"JPY\",\"value\":164.54}]}}"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "EUR", actualLatestExchangeRateResponse.BaseCurrency)
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "1.0637",
+	})
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "JPY",
+		Rate:     "164.54",
+	})
+}
+
+func TestCustomDataSource_XmlFormat(t *testing.T) {
+	dataSource := newTestCustomDataSource(t, customDataSourceXmlContent, &settings.Config{
+		ExchangeRatesCustomDataSourceFormat:           settings.CustomExchangeRatesDataSourceXmlFormat,
+		ExchangeRatesCustomDataSourceBaseCurrency:     "EUR",
+		ExchangeRatesCustomDataSourceRatesPath:        "Envelope/Cube/Cube/Cube",
+		ExchangeRatesCustomDataSourceCurrencyPath:     "@currency",
+		ExchangeRatesCustomDataSourceRatePath:         "@rate",
+		ExchangeRatesCustomDataSourceUpdateTimePath:   "Envelope/Cube/Cube/@time",
+		ExchangeRatesCustomDataSourceUpdateTimeFormat: "2006-01-02",
+	})
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualLatestExchangeRateResponse, err := GetLatestExchangeRates(context, dataSource)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "EUR", actualLatestExchangeRateResponse.BaseCurrency)
+	assert.Equal(t, int64(1713312000), actualLatestExchangeRateResponse.UpdateTime)
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "1.0637",
+	})
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "JPY",
+		Rate:     "164.54",
+	})
+}
+
+func TestCustomDataSource_CsvFormatWithInvertedRate(t *testing.T) {
+	dataSource := newTestCustomDataSource(t, customDataSourceCsvContent, &settings.Config{
+		ExchangeRatesCustomDataSourceFormat:           settings.CustomExchangeRatesDataSourceCsvFormat,
+		ExchangeRatesCustomDataSourceBaseCurrencyPath: "base",
+		ExchangeRatesCustomDataSourceCurrencyPath:     "currency",
+		ExchangeRatesCustomDataSourceRatePath:         "rate",
+		ExchangeRatesCustomDataSourceRateInverted:     true,
+		ExchangeRatesCustomDataSourceUpdateTimePath:   "time",
+		ExchangeRatesCustomDataSourceUpdateTimeFormat: "2006-01-02T15:04:05Z07:00",
+	})
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualLatestExchangeRateResponse, err := GetLatestExchangeRates(context, dataSource)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "USD", actualLatestExchangeRateResponse.BaseCurrency)
+	assert.Equal(t, int64(1713369600), actualLatestExchangeRateResponse.UpdateTime)
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "EUR",
+		Rate:     "0.5",
+	})
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "JPY",
+		Rate:     "156.25",
+	})
+}
+
+func TestCustomDataSource_BlankContent(t *testing.T) {
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	formats := []string{
+		settings.CustomExchangeRatesDataSourceJsonFormat,
+		settings.CustomExchangeRatesDataSourceXmlFormat,
+		settings.CustomExchangeRatesDataSourceCsvFormat,
+	}
+
+	for i := 0; i < len(formats); i++ {
+		dataSource := NewCustomDataSource(&settings.Config{
+			ExchangeRatesCustomDataSourceFormat:       formats[i],
+			ExchangeRatesCustomDataSourceBaseCurrency: "EUR",
+			ExchangeRatesCustomDataSourceCurrencyPath: "currency",
+			ExchangeRatesCustomDataSourceRatePath:     "rate",
+		})
+
+		_, err := dataSource.Parse(context, []byte(""))
+		assert.NotEqual(t, nil, err)
+	}
+}
+
+func TestCustomDataSource_MissingRatesPath(t *testing.T) {
+	dataSource := NewCustomDataSource(&settings.Config{
+		ExchangeRatesCustomDataSourceFormat:       settings.CustomExchangeRatesDataSourceJsonFormat,
+		ExchangeRatesCustomDataSourceBaseCurrency: "EUR",
+		ExchangeRatesCustomDataSourceRatesPath:    "data.rates",
+		ExchangeRatesCustomDataSourceCurrencyPath: "$key",
+		ExchangeRatesCustomDataSourceRatePath:     "$",
+	})
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, err := dataSource.Parse(context, []byte(customDataSourceJsonContent))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestCustomDataSource_InvalidBaseCurrency(t *testing.T) {
+	dataSource := NewCustomDataSource(&settings.Config{
+		ExchangeRatesCustomDataSourceFormat:           settings.CustomExchangeRatesDataSourceJsonFormat,
+		ExchangeRatesCustomDataSourceBaseCurrencyPath: "base",
+		ExchangeRatesCustomDataSourceRatesPath:        "rates",
+		ExchangeRatesCustomDataSourceCurrencyPath:     "$key",
+		ExchangeRatesCustomDataSourceRatePath:         "$",
+	})
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, err := dataSource.Parse(context, []byte("{\"base\":\"XXX\",\"rates\":{\"USD\":1.0637}}"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestCustomDataSource_InvalidUpdateTime(t *testing.T) {
+	dataSource := NewCustomDataSource(&settings.Config{
+		ExchangeRatesCustomDataSourceFormat:           settings.CustomExchangeRatesDataSourceJsonFormat,
+		ExchangeRatesCustomDataSourceBaseCurrencyPath: "base",
+		ExchangeRatesCustomDataSourceRatesPath:        "rates",
+		ExchangeRatesCustomDataSourceCurrencyPath:     "$key",
+		ExchangeRatesCustomDataSourceRatePath:         "$",
+		ExchangeRatesCustomDataSourceUpdateTimePath:   "timestamp",
+		ExchangeRatesCustomDataSourceUpdateTimeFormat: "2006-01-02",
+	})
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, err := dataSource.Parse(context, []byte(customDataSourceJsonContent))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestCustomDataSource_InvalidRate(t *testing.T) {
+	dataSource := NewCustomDataSource(&settings.Config{
+		ExchangeRatesCustomDataSourceFormat:       settings.CustomExchangeRatesDataSourceJsonFormat,
+		ExchangeRatesCustomDataSourceBaseCurrency: "EUR",
+		ExchangeRatesCustomDataSourceRatesPath:    "rates",
+		ExchangeRatesCustomDataSourceCurrencyPath: "$key",
+		ExchangeRatesCustomDataSourceRatePath:     "$",
+	})
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, err := dataSource.Parse(context, []byte("{\"rates\":{\"USD\":\"null\",\"JPY\":0,\"GBP\":\"NaN\"}}"))
+	assert.NotEqual(t, nil, err)
+}
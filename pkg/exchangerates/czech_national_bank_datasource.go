@@ -1,6 +1,7 @@
 package exchangerates
 
 import (
+	"fmt"
 	"math"
 	"strings"
 	"time"
@@ -15,11 +16,16 @@ import (
 
 const czechNationalBankDailyExchangeRateUrl = "https://www.cnb.cz/en/financial-markets/foreign-exchange-market/central-bank-exchange-rate-fixing/central-bank-exchange-rate-fixing/daily.txt"
 const czechNationalBankMonthlyOtherExchangeRateUrl = "https://www.cnb.cz/en/financial-markets/foreign-exchange-market/fx-rates-of-other-currencies/fx-rates-of-other-currencies/fx_rates.txt"
+const czechNationalBankHistoricalExchangeRateUrlFormat = "https://www.cnb.cz/en/financial-markets/foreign-exchange-market/central-bank-exchange-rate-fixing/central-bank-exchange-rate-fixing/year.txt?year=%d"
 const czechNationalBankExchangeRateReferenceUrl = "https://www.cnb.cz/en/financial-markets/foreign-exchange-market/central-bank-exchange-rate-fixing/central-bank-exchange-rate-fixing/"
 const czechNationalBankDataSource = "Česká národní banka"
 const czechNationalBankBaseCurrency = "CZK"
 
+const czechNationalBankHistoricalStartYear = 1991
+const czechNationalBankHistoricalDateColumnTitle = "Date"
+
 const czechNationalBankDataUpdateDateFormat = "02 Jan 2006 15:04"
+const czechNationalBankHistoricalDataUpdateDateFormat = "02.01.2006 15:04"
 const czechNationalBankDataUpdateDateTimezone = "Europe/Prague"
 
 // CzechNationalBankDataSource defines the structure of exchange rates data source of Czech National Bank
@@ -32,6 +38,23 @@ func (e *CzechNationalBankDataSource) GetRequestUrls() []string {
 	return []string{czechNationalBankMonthlyOtherExchangeRateUrl, czechNationalBankDailyExchangeRateUrl}
 }
 
+// GetPublishingInterval returns the publishing interval of Czech National Bank data source, which publishes new exchange rates once per business day
+func (e *CzechNationalBankDataSource) GetPublishingInterval() time.Duration {
+	return dailyExchangeRatesPublishingInterval
+}
+
+// GetHistoricalRequestUrls returns the czech nation bank historical data source urls, each of which contains the exchange rates of one year
+func (e *CzechNationalBankDataSource) GetHistoricalRequestUrls() []string {
+	currentYear := time.Now().Year()
+	urls := make([]string, 0, currentYear-czechNationalBankHistoricalStartYear+1)
+
+	for year := czechNationalBankHistoricalStartYear; year <= currentYear; year++ {
+		urls = append(urls, fmt.Sprintf(czechNationalBankHistoricalExchangeRateUrlFormat, year))
+	}
+
+	return urls
+}
+
 // Parse returns the common response entity according to the czech nation bank data source raw response
 func (e *CzechNationalBankDataSource) Parse(c *core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
 	lines := strings.Split(string(content), "\n")
@@ -115,6 +138,79 @@ func (e *CzechNationalBankDataSource) Parse(c *core.Context, content []byte) (*m
 	return latestExchangeRateResp, nil
 }
 
+// ParseHistorical returns the common response entities of all dates according to the czech nation bank historical data source raw response
+func (e *CzechNationalBankDataSource) ParseHistorical(c *core.Context, content []byte) ([]*models.LatestExchangeRateResponse, error) {
+	lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+
+	timezone, err := time.LoadLocation(czechNationalBankDataUpdateDateTimezone)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[czech_national_bank_datasource.ParseHistorical] failed to get timezone, timezone name is %s", czechNationalBankDataUpdateDateTimezone)
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	historicalExchangeRateResponses := make([]*models.LatestExchangeRateResponse, 0, len(lines))
+	var currencyCodes []string
+	var amounts []int64
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+
+		if len(line) < 1 {
+			continue
+		}
+
+		// The title line would appear again when the published currencies are changed
+		if strings.HasPrefix(line, czechNationalBankHistoricalDateColumnTitle+"|") {
+			currencyCodes, amounts = e.parseHistoricalTitleLine(line)
+			continue
+		}
+
+		if currencyCodes == nil {
+			log.ErrorfWithRequestId(c, "[czech_national_bank_datasource.ParseHistorical] missing title line before data line, line is %s", line)
+			return nil, errs.ErrFailedToRequestRemoteApi
+		}
+
+		items := strings.Split(line, "|")
+		updateDateTime := strings.TrimSpace(items[0]) + " 14:30" // Exchange rates of commonly traded currencies are declared every working day after 2.30 p.m.
+		updateTime, err := time.ParseInLocation(czechNationalBankHistoricalDataUpdateDateFormat, updateDateTime, timezone)
+
+		if err != nil {
+			log.ErrorfWithRequestId(c, "[czech_national_bank_datasource.ParseHistorical] failed to parse update date, datetime is %s", updateDateTime)
+			return nil, errs.ErrFailedToRequestRemoteApi
+		}
+
+		exchangeRates := make(models.LatestExchangeRateSlice, 0, len(items)-1)
+
+		for j := 1; j < len(items) && j < len(currencyCodes); j++ {
+			if _, exists := validators.AllCurrencyNames[currencyCodes[j]]; !exists {
+				continue
+			}
+
+			exchangeRate := e.toLatestExchangeRate(c, currencyCodes[j], amounts[j], strings.TrimSpace(items[j]))
+
+			if exchangeRate != nil {
+				exchangeRates = append(exchangeRates, exchangeRate)
+			}
+		}
+
+		historicalExchangeRateResponses = append(historicalExchangeRateResponses, &models.LatestExchangeRateResponse{
+			DataSource:    czechNationalBankDataSource,
+			ReferenceUrl:  czechNationalBankExchangeRateReferenceUrl,
+			UpdateTime:    updateTime.Unix(),
+			BaseCurrency:  czechNationalBankBaseCurrency,
+			ExchangeRates: exchangeRates,
+		})
+	}
+
+	if len(historicalExchangeRateResponses) < 1 {
+		log.ErrorfWithRequestId(c, "[czech_national_bank_datasource.ParseHistorical] no valid historical exchange rate")
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return historicalExchangeRateResponses, nil
+}
+
 func (e *CzechNationalBankDataSource) parseExchangeRate(c *core.Context, line string, currencyCodeColumnIndex int, amountColumnIndex int, rateColumnIndex int) *models.LatestExchangeRate {
 	if len(line) < 1 {
 		return nil
@@ -140,15 +236,45 @@ func (e *CzechNationalBankDataSource) parseExchangeRate(c *core.Context, line st
 		return nil
 	}
 
-	rate, err := utils.StringToFloat64(items[rateColumnIndex])
+	return e.toLatestExchangeRate(c, currencyCode, amount, items[rateColumnIndex])
+}
+
+func (e *CzechNationalBankDataSource) parseHistoricalTitleLine(line string) ([]string, []int64) {
+	items := strings.Split(line, "|")
+	currencyCodes := make([]string, len(items))
+	amounts := make([]int64, len(items))
+
+	// The title of each currency column is the amount and the code of the currency, e.g. "100 JPY"
+	for i := 1; i < len(items); i++ {
+		titleItems := strings.Split(strings.TrimSpace(items[i]), " ")
+
+		if len(titleItems) != 2 {
+			continue
+		}
+
+		amount, err := utils.StringToInt64(titleItems[0])
+
+		if err != nil {
+			continue
+		}
+
+		currencyCodes[i] = titleItems[1]
+		amounts[i] = amount
+	}
+
+	return currencyCodes, amounts
+}
+
+func (e *CzechNationalBankDataSource) toLatestExchangeRate(c *core.Context, currencyCode string, amount int64, rateValue string) *models.LatestExchangeRate {
+	rate, err := utils.StringToFloat64(rateValue)
 
 	if err != nil {
-		log.WarnfWithRequestId(c, "[czech_national_bank_datasource.parseExchangeRate] failed to parse rate, line is %s", line)
+		log.WarnfWithRequestId(c, "[czech_national_bank_datasource.toLatestExchangeRate] failed to parse rate, currency is %s, rate is %s", currencyCode, rateValue)
 		return nil
 	}
 
 	if rate <= 0 {
-		log.WarnfWithRequestId(c, "[czech_national_bank_datasource.parseExchangeRate] rate is invalid, line is %s", line)
+		log.WarnfWithRequestId(c, "[czech_national_bank_datasource.toLatestExchangeRate] rate is invalid, currency is %s, rate is %s", currencyCode, rateValue)
 		return nil
 	}
 
@@ -139,3 +139,53 @@ func TestCzechNationalBankDataSource_InvalidRate(t *testing.T) {
 	assert.Equal(t, nil, err)
 	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 0)
 }
+
+const czechNationalBankHistoricalContent = "Date|1 CNY|100 JPY|1 USD\n" +
+	"31.03.2021|3.391|20.123|22.255\n" +
+	"Date|1 CNY|1 USD\n" +
+	"01.04.2021|3.379|22.206\n"
+
+func TestCzechNationalBankDataSource_HistoricalDataExtractAllDates(t *testing.T) {
+	dataSource := &CzechNationalBankDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualHistoricalExchangeRateResponses, err := dataSource.ParseHistorical(context, []byte(czechNationalBankHistoricalContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(actualHistoricalExchangeRateResponses))
+
+	assert.Equal(t, int64(1617193800), actualHistoricalExchangeRateResponses[0].UpdateTime)
+	assert.Equal(t, 3, len(actualHistoricalExchangeRateResponses[0].ExchangeRates))
+	assert.Contains(t, actualHistoricalExchangeRateResponses[0].ExchangeRates, &models.LatestExchangeRate{
+		Currency: "JPY",
+		Rate:     "4.969437956567112",
+	})
+
+	assert.Equal(t, int64(1617280200), actualHistoricalExchangeRateResponses[1].UpdateTime)
+	assert.Equal(t, 2, len(actualHistoricalExchangeRateResponses[1].ExchangeRates))
+	assert.Contains(t, actualHistoricalExchangeRateResponses[1].ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "0.04503287399801856",
+	})
+}
+
+func TestCzechNationalBankDataSource_HistoricalMissingTitleLine(t *testing.T) {
+	dataSource := &CzechNationalBankDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, err := dataSource.ParseHistorical(context, []byte("01.04.2021|3.379|22.206\n"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestCzechNationalBankDataSource_HistoricalBlankContent(t *testing.T) {
+	dataSource := &CzechNationalBankDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, err := dataSource.ParseHistorical(context, []byte(""))
+	assert.NotEqual(t, nil, err)
+}
@@ -0,0 +1,211 @@
+package exchangerates
+
+import (
+	"encoding/xml"
+	"math"
+	"time"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/log"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+	"github.com/kyy-me/ezbookkeeping/pkg/utils"
+	"github.com/kyy-me/ezbookkeeping/pkg/validators"
+)
+
+const danmarksNationalbankExchangeRateUrl = "https://www.nationalbanken.dk/api/currencyratesxml?lang=en"
+const danmarksNationalbankHistoricalExchangeRateUrl = "https://www.nationalbanken.dk/api/currencyrateshistoryxml?lang=en"
+const danmarksNationalbankExchangeRateReferenceUrl = "https://www.nationalbanken.dk/en/what-we-do/stable-prices-monetary-policy-and-the-danish-economy/exchange-rates"
+const danmarksNationalbankDataSource = "Danmarks Nationalbank"
+const danmarksNationalbankBaseCurrency = "DKK"
+
+const danmarksNationalbankDataUpdateDateFormat = "2006-01-02 15:04"
+const danmarksNationalbankDataUpdateDateTimezone = "Europe/Copenhagen"
+
+// DanmarksNationalbankDataSource defines the structure of exchange rates data source of Danmarks Nationalbank
+type DanmarksNationalbankDataSource struct {
+	ExchangeRatesDataSource
+}
+
+// DanmarksNationalbankExchangeRateData represents the whole data from Danmarks Nationalbank
+type DanmarksNationalbankExchangeRateData struct {
+	XMLName          xml.Name                             `xml:"exchangerates"`
+	ReferenceAmount  string                               `xml:"refamt,attr"`
+	AllExchangeRates []*DanmarksNationalbankExchangeRates `xml:"dailyrates"`
+}
+
+// DanmarksNationalbankExchangeRates represents the exchange rates data of one date from Danmarks Nationalbank
+type DanmarksNationalbankExchangeRates struct {
+	Date          string                              `xml:"id,attr"`
+	ExchangeRates []*DanmarksNationalbankExchangeRate `xml:"currency"`
+}
+
+// DanmarksNationalbankExchangeRate represents the exchange rate data from Danmarks Nationalbank
+type DanmarksNationalbankExchangeRate struct {
+	Currency string `xml:"code,attr"`
+	Rate     string `xml:"rate,attr"`
+}
+
+// ToLatestExchangeRateResponse returns a view-object according to original data from Danmarks Nationalbank
+func (e *DanmarksNationalbankExchangeRateData) ToLatestExchangeRateResponse(c *core.Context) *models.LatestExchangeRateResponse {
+	if len(e.AllExchangeRates) < 1 {
+		log.ErrorfWithRequestId(c, "[danmarks_nationalbank_datasource.ToLatestExchangeRateResponse] all exchange rates is empty")
+		return nil
+	}
+
+	return e.AllExchangeRates[0].ToLatestExchangeRateResponse(c)
+}
+
+// ToHistoricalExchangeRateResponses returns view-objects of all dates according to original data from Danmarks Nationalbank
+func (e *DanmarksNationalbankExchangeRateData) ToHistoricalExchangeRateResponses(c *core.Context) []*models.LatestExchangeRateResponse {
+	if len(e.AllExchangeRates) < 1 {
+		log.ErrorfWithRequestId(c, "[danmarks_nationalbank_datasource.ToHistoricalExchangeRateResponses] all exchange rates is empty")
+		return nil
+	}
+
+	exchangeRateResps := make([]*models.LatestExchangeRateResponse, 0, len(e.AllExchangeRates))
+
+	for i := 0; i < len(e.AllExchangeRates); i++ {
+		exchangeRateResp := e.AllExchangeRates[i].ToLatestExchangeRateResponse(c)
+
+		if exchangeRateResp == nil {
+			return nil
+		}
+
+		exchangeRateResps = append(exchangeRateResps, exchangeRateResp)
+	}
+
+	return exchangeRateResps
+}
+
+// ToLatestExchangeRateResponse returns a view-object of one date according to original data from Danmarks Nationalbank
+func (e *DanmarksNationalbankExchangeRates) ToLatestExchangeRateResponse(c *core.Context) *models.LatestExchangeRateResponse {
+	if len(e.ExchangeRates) < 1 {
+		log.ErrorfWithRequestId(c, "[danmarks_nationalbank_datasource.ToLatestExchangeRateResponse] exchange rates is empty")
+		return nil
+	}
+
+	exchangeRates := make(models.LatestExchangeRateSlice, 0, len(e.ExchangeRates))
+
+	for i := 0; i < len(e.ExchangeRates); i++ {
+		exchangeRate := e.ExchangeRates[i]
+
+		if _, exists := validators.AllCurrencyNames[exchangeRate.Currency]; !exists {
+			continue
+		}
+
+		finalExchangeRate := exchangeRate.ToLatestExchangeRate(c)
+
+		if finalExchangeRate == nil {
+			continue
+		}
+
+		exchangeRates = append(exchangeRates, finalExchangeRate)
+	}
+
+	timezone, err := time.LoadLocation(danmarksNationalbankDataUpdateDateTimezone)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[danmarks_nationalbank_datasource.ToLatestExchangeRateResponse] failed to get timezone, timezone name is %s", danmarksNationalbankDataUpdateDateTimezone)
+		return nil
+	}
+
+	updateDateTime := e.Date + " 16:00" // The exchange rates are updated around 4 p.m. on banking days
+	updateTime, err := time.ParseInLocation(danmarksNationalbankDataUpdateDateFormat, updateDateTime, timezone)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[danmarks_nationalbank_datasource.ToLatestExchangeRateResponse] failed to parse update date, datetime is %s", updateDateTime)
+		return nil
+	}
+
+	latestExchangeRateResp := &models.LatestExchangeRateResponse{
+		DataSource:    danmarksNationalbankDataSource,
+		ReferenceUrl:  danmarksNationalbankExchangeRateReferenceUrl,
+		UpdateTime:    updateTime.Unix(),
+		BaseCurrency:  danmarksNationalbankBaseCurrency,
+		ExchangeRates: exchangeRates,
+	}
+
+	return latestExchangeRateResp
+}
+
+// ToLatestExchangeRate returns a data pair according to original data from Danmarks Nationalbank
+func (e *DanmarksNationalbankExchangeRate) ToLatestExchangeRate(c *core.Context) *models.LatestExchangeRate {
+	rate, err := utils.StringToFloat64(e.Rate)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[danmarks_nationalbank_datasource.ToLatestExchangeRate] failed to parse rate, currency is %s, rate is %s", e.Currency, e.Rate)
+		return nil
+	}
+
+	if math.IsNaN(rate) || rate <= 0 {
+		log.WarnfWithRequestId(c, "[danmarks_nationalbank_datasource.ToLatestExchangeRate] rate is invalid, currency is %s, rate is %s", e.Currency, e.Rate)
+		return nil
+	}
+
+	finalRate := 100 / rate // The rates are the amount of danish krone per 100 units of foreign currency
+
+	if math.IsInf(finalRate, 0) {
+		return nil
+	}
+
+	return &models.LatestExchangeRate{
+		Currency: e.Currency,
+		Rate:     utils.Float64ToString(finalRate),
+	}
+}
+
+// GetRequestUrls returns the Danmarks Nationalbank data source urls
+func (e *DanmarksNationalbankDataSource) GetRequestUrls() []string {
+	return []string{danmarksNationalbankExchangeRateUrl}
+}
+
+// GetPublishingInterval returns the publishing interval of Danmarks Nationalbank data source, which publishes new exchange rates once per business day
+func (e *DanmarksNationalbankDataSource) GetPublishingInterval() time.Duration {
+	return dailyExchangeRatesPublishingInterval
+}
+
+// GetHistoricalRequestUrls returns the Danmarks Nationalbank historical data source urls
+func (e *DanmarksNationalbankDataSource) GetHistoricalRequestUrls() []string {
+	return []string{danmarksNationalbankHistoricalExchangeRateUrl}
+}
+
+// Parse returns the common response entity according to the Danmarks Nationalbank data source raw response
+func (e *DanmarksNationalbankDataSource) Parse(c *core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
+	danmarksNationalbankData := &DanmarksNationalbankExchangeRateData{}
+	err := xml.Unmarshal(content, danmarksNationalbankData)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[danmarks_nationalbank_datasource.Parse] failed to parse xml data, content is %s, because %s", string(content), err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	latestExchangeRateResponse := danmarksNationalbankData.ToLatestExchangeRateResponse(c)
+
+	if latestExchangeRateResponse == nil {
+		log.ErrorfWithRequestId(c, "[danmarks_nationalbank_datasource.Parse] failed to parse latest exchange rate data, content is %s", string(content))
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return latestExchangeRateResponse, nil
+}
+
+// ParseHistorical returns the common response entities of all dates according to the Danmarks Nationalbank historical data source raw response
+func (e *DanmarksNationalbankDataSource) ParseHistorical(c *core.Context, content []byte) ([]*models.LatestExchangeRateResponse, error) {
+	danmarksNationalbankData := &DanmarksNationalbankExchangeRateData{}
+	err := xml.Unmarshal(content, danmarksNationalbankData)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[danmarks_nationalbank_datasource.ParseHistorical] failed to parse xml data, because %s", err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	historicalExchangeRateResponses := danmarksNationalbankData.ToHistoricalExchangeRateResponses(c)
+
+	if historicalExchangeRateResponses == nil {
+		log.ErrorfWithRequestId(c, "[danmarks_nationalbank_datasource.ParseHistorical] failed to parse historical exchange rate data")
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return historicalExchangeRateResponses, nil
+}
@@ -0,0 +1,143 @@
+package exchangerates
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+)
+
+const danmarksNationalbankMinimumRequiredContent = "<?xml version=\"1.0\" encoding=\"utf-8\"?>\n" +
+	"<exchangerates type=\"Exchange rates\" author=\"Danmarks Nationalbank\" refcur=\"DKK\" refamt=\"1\">\n" +
+	"  <dailyrates id=\"2024-04-17\">\n" +
+	"    <currency code=\"USD\" desc=\"US dollars\" rate=\"700.89\" />\n" +
+	"    <currency code=\"EUR\" desc=\"Euro\" rate=\"745.90\" />\n" +
+	"  </dailyrates>\n" +
+	"</exchangerates>"
+
+func TestDanmarksNationalbankDataSource_StandardDataExtractBaseCurrency(t *testing.T) {
+	dataSource := &DanmarksNationalbankDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(danmarksNationalbankMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "DKK", actualLatestExchangeRateResponse.BaseCurrency)
+}
+
+func TestDanmarksNationalbankDataSource_StandardDataExtractExchangeRates(t *testing.T) {
+	dataSource := &DanmarksNationalbankDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(danmarksNationalbankMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1713362400), actualLatestExchangeRateResponse.UpdateTime)
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "0.14267574084378434",
+	})
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "EUR",
+		Rate:     "0.1340662287169862",
+	})
+}
+
+func TestDanmarksNationalbankDataSource_BlankContent(t *testing.T) {
+	dataSource := &DanmarksNationalbankDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, err := dataSource.Parse(context, []byte(""))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestDanmarksNationalbankDataSource_EmptyDailyRates(t *testing.T) {
+	dataSource := &DanmarksNationalbankDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, err := dataSource.Parse(context, []byte("<exchangerates type=\"Exchange rates\" author=\"Danmarks Nationalbank\" refcur=\"DKK\" refamt=\"1\">\n"+
+		"  <dailyrates id=\"2024-04-17\">\n"+
+		"  </dailyrates>\n"+
+		"</exchangerates>"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestDanmarksNationalbankDataSource_InvalidCurrency(t *testing.T) {
+	dataSource := &DanmarksNationalbankDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte("<exchangerates type=\"Exchange rates\" author=\"Danmarks Nationalbank\" refcur=\"DKK\" refamt=\"1\">\n"+
+		"  <dailyrates id=\"2024-04-17\">\n"+
+		"    <currency code=\"XXX\" desc=\"Unknown\" rate=\"1\" />\n"+
+		"  </dailyrates>\n"+
+		"</exchangerates>"))
+	assert.Equal(t, nil, err)
+	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 0)
+}
+
+func TestDanmarksNationalbankDataSource_InvalidRate(t *testing.T) {
+	dataSource := &DanmarksNationalbankDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte("<exchangerates type=\"Exchange rates\" author=\"Danmarks Nationalbank\" refcur=\"DKK\" refamt=\"1\">\n"+
+		"  <dailyrates id=\"2024-04-17\">\n"+
+		"    <currency code=\"USD\" desc=\"US dollars\" rate=\"-\" />\n"+
+		"  </dailyrates>\n"+
+		"</exchangerates>"))
+	assert.Equal(t, nil, err)
+	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 0)
+}
+
+const danmarksNationalbankHistoricalContent = "<?xml version=\"1.0\" encoding=\"utf-8\"?>\n" +
+	"<exchangerates type=\"Exchange rates\" author=\"Danmarks Nationalbank\" refcur=\"DKK\" refamt=\"1\">\n" +
+	"  <dailyrates id=\"2024-04-17\">\n" +
+	"    <currency code=\"USD\" desc=\"US dollars\" rate=\"700.89\" />\n" +
+	"    <currency code=\"EUR\" desc=\"Euro\" rate=\"745.90\" />\n" +
+	"  </dailyrates>\n" +
+	"  <dailyrates id=\"2024-04-16\">\n" +
+	"    <currency code=\"USD\" desc=\"US dollars\" rate=\"702.00\" />\n" +
+	"  </dailyrates>\n" +
+	"</exchangerates>"
+
+func TestDanmarksNationalbankDataSource_HistoricalDataExtractAllDates(t *testing.T) {
+	dataSource := &DanmarksNationalbankDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualHistoricalExchangeRateResponses, err := dataSource.ParseHistorical(context, []byte(danmarksNationalbankHistoricalContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(actualHistoricalExchangeRateResponses))
+
+	assert.Equal(t, int64(1713362400), actualHistoricalExchangeRateResponses[0].UpdateTime)
+	assert.Equal(t, 2, len(actualHistoricalExchangeRateResponses[0].ExchangeRates))
+
+	assert.Equal(t, int64(1713276000), actualHistoricalExchangeRateResponses[1].UpdateTime)
+	assert.Contains(t, actualHistoricalExchangeRateResponses[1].ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "0.14245014245014245",
+	})
+}
+
+func TestDanmarksNationalbankDataSource_HistoricalBlankContent(t *testing.T) {
+	dataSource := &DanmarksNationalbankDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, err := dataSource.ParseHistorical(context, []byte(""))
+	assert.NotEqual(t, nil, err)
+}
@@ -13,6 +13,7 @@ import (
 )
 
 const euroCentralBankExchangeRateUrl = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+const euroCentralBankHistoricalExchangeRateUrl = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist.xml"
 const euroCentralBankExchangeRateReferenceUrl = "https://www.ecb.europa.eu/stats/policy_and_exchange_rates/euro_reference_exchange_rates/html/index.en.html"
 const euroCentralBankDataSource = "European Central Bank"
 const euroCentralBankBaseCurrency = "EUR"
@@ -50,17 +51,42 @@ func (e *EuroCentralBankExchangeRateData) ToLatestExchangeRateResponse(c *core.C
 		return nil
 	}
 
-	latestEuroCentralBankExchangeRate := e.AllExchangeRates[0]
+	return e.AllExchangeRates[0].ToLatestExchangeRateResponse(c)
+}
+
+// ToHistoricalExchangeRateResponses returns view-objects of all dates according to original data from euro central bank
+func (e *EuroCentralBankExchangeRateData) ToHistoricalExchangeRateResponses(c *core.Context) []*models.LatestExchangeRateResponse {
+	if len(e.AllExchangeRates) < 1 {
+		log.ErrorfWithRequestId(c, "[euro_central_bank_datasource.ToHistoricalExchangeRateResponses] all exchange rates is empty")
+		return nil
+	}
+
+	exchangeRateResps := make([]*models.LatestExchangeRateResponse, 0, len(e.AllExchangeRates))
 
-	if len(latestEuroCentralBankExchangeRate.ExchangeRates) < 1 {
+	for i := 0; i < len(e.AllExchangeRates); i++ {
+		exchangeRateResp := e.AllExchangeRates[i].ToLatestExchangeRateResponse(c)
+
+		if exchangeRateResp == nil {
+			return nil
+		}
+
+		exchangeRateResps = append(exchangeRateResps, exchangeRateResp)
+	}
+
+	return exchangeRateResps
+}
+
+// ToLatestExchangeRateResponse returns a view-object of one date according to original data from euro central bank
+func (e *EuroCentralBankExchangeRates) ToLatestExchangeRateResponse(c *core.Context) *models.LatestExchangeRateResponse {
+	if len(e.ExchangeRates) < 1 {
 		log.ErrorfWithRequestId(c, "[euro_central_bank_datasource.ToLatestExchangeRateResponse] exchange rates is empty")
 		return nil
 	}
 
-	exchangeRates := make(models.LatestExchangeRateSlice, 0, len(latestEuroCentralBankExchangeRate.ExchangeRates))
+	exchangeRates := make(models.LatestExchangeRateSlice, 0, len(e.ExchangeRates))
 
-	for i := 0; i < len(latestEuroCentralBankExchangeRate.ExchangeRates); i++ {
-		exchangeRate := latestEuroCentralBankExchangeRate.ExchangeRates[i]
+	for i := 0; i < len(e.ExchangeRates); i++ {
+		exchangeRate := e.ExchangeRates[i]
 
 		if _, exists := validators.AllCurrencyNames[exchangeRate.Currency]; !exists {
 			continue
@@ -80,7 +106,7 @@ func (e *EuroCentralBankExchangeRateData) ToLatestExchangeRateResponse(c *core.C
 		return nil
 	}
 
-	updateDateTime := latestEuroCentralBankExchangeRate.Date + " 16" // The reference rates are usually updated around 16:00 CET on every working day
+	updateDateTime := e.Date + " 16" // The reference rates are usually updated around 16:00 CET on every working day
 	updateTime, err := time.ParseInLocation(euroCentralBankDataUpdateDateFormat, updateDateTime, timezone)
 
 	if err != nil {
@@ -112,6 +138,16 @@ func (e *EuroCentralBankDataSource) GetRequestUrls() []string {
 	return []string{euroCentralBankExchangeRateUrl}
 }
 
+// GetPublishingInterval returns the publishing interval of euro central bank data source, which publishes new exchange rates once per business day
+func (e *EuroCentralBankDataSource) GetPublishingInterval() time.Duration {
+	return dailyExchangeRatesPublishingInterval
+}
+
+// GetHistoricalRequestUrls returns the euro central bank historical data source urls
+func (e *EuroCentralBankDataSource) GetHistoricalRequestUrls() []string {
+	return []string{euroCentralBankHistoricalExchangeRateUrl}
+}
+
 // Parse returns the common response entity according to the euro central bank data source raw response
 func (e *EuroCentralBankDataSource) Parse(c *core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
 	euroCentralBankData := &EuroCentralBankExchangeRateData{}
@@ -131,3 +167,23 @@ func (e *EuroCentralBankDataSource) Parse(c *core.Context, content []byte) (*mod
 
 	return latestExchangeRateResponse, nil
 }
+
+// ParseHistorical returns the common response entities of all dates according to the euro central bank historical data source raw response
+func (e *EuroCentralBankDataSource) ParseHistorical(c *core.Context, content []byte) ([]*models.LatestExchangeRateResponse, error) {
+	euroCentralBankData := &EuroCentralBankExchangeRateData{}
+	err := xml.Unmarshal(content, euroCentralBankData)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[euro_central_bank_datasource.ParseHistorical] failed to parse xml data, because %s", err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	historicalExchangeRateResponses := euroCentralBankData.ToHistoricalExchangeRateResponses(c)
+
+	if historicalExchangeRateResponses == nil {
+		log.ErrorfWithRequestId(c, "[euro_central_bank_datasource.ParseHistorical] failed to parse historical exchange rate data")
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return historicalExchangeRateResponses, nil
+}
@@ -148,3 +148,67 @@ func TestEuroCentralBankDataSource_InvalidRate(t *testing.T) {
 	assert.Equal(t, nil, err)
 	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 0)
 }
+
+const euroCentralBankHistoricalContent = "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n" +
+	"<gesmes:Envelope xmlns:gesmes=\"http://www.gesmes.org/xml/2002-08-01\" xmlns=\"http://www.ecb.int/vocabulary/2002-08-01/eurofxref\">\n" +
+	"  <Cube>\n" +
+	"    <Cube time=\"2021-04-01\">\n" +
+	"      <Cube currency=\"USD\" rate=\"1.1746\" />\n" +
+	"      <Cube currency=\"CNY\" rate=\"7.7195\" />\n" +
+	"    </Cube>\n" +
+	"    <Cube time=\"2021-03-31\">\n" +
+	"      <Cube currency=\"USD\" rate=\"1.1725\" />\n" +
+	"      <Cube currency=\"CNY\" rate=\"7.6812\" />\n" +
+	"    </Cube>\n" +
+	"  </Cube>\n" +
+	"</gesmes:Envelope>"
+
+func TestEuroCentralBankDataSource_HistoricalDataExtractAllDates(t *testing.T) {
+	dataSource := &EuroCentralBankDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualHistoricalExchangeRateResponses, err := dataSource.ParseHistorical(context, []byte(euroCentralBankHistoricalContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(actualHistoricalExchangeRateResponses))
+
+	assert.Equal(t, int64(1617285600), actualHistoricalExchangeRateResponses[0].UpdateTime)
+	assert.Contains(t, actualHistoricalExchangeRateResponses[0].ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "1.1746",
+	})
+
+	assert.Equal(t, int64(1617199200), actualHistoricalExchangeRateResponses[1].UpdateTime)
+	assert.Contains(t, actualHistoricalExchangeRateResponses[1].ExchangeRates, &models.LatestExchangeRate{
+		Currency: "CNY",
+		Rate:     "7.6812",
+	})
+}
+
+func TestEuroCentralBankDataSource_HistoricalDataToExchangeRates(t *testing.T) {
+	dataSource := &EuroCentralBankDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualHistoricalExchangeRateResponses, err := dataSource.ParseHistorical(context, []byte(euroCentralBankHistoricalContent))
+	assert.Equal(t, nil, err)
+
+	actualExchangeRates := actualHistoricalExchangeRateResponses[1].ToExchangeRates("euro_central_bank")
+	assert.Equal(t, 2, len(actualExchangeRates))
+	assert.Equal(t, "EUR", actualExchangeRates[0].BaseCurrency)
+	assert.Equal(t, "USD", actualExchangeRates[0].Currency)
+	assert.Equal(t, int32(20210331), actualExchangeRates[0].Date)
+	assert.Equal(t, "1.1725", actualExchangeRates[0].Rate)
+}
+
+func TestEuroCentralBankDataSource_HistoricalBlankContent(t *testing.T) {
+	dataSource := &EuroCentralBankDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, err := dataSource.ParseHistorical(context, []byte(""))
+	assert.NotEqual(t, nil, err)
+}
@@ -0,0 +1,189 @@
+package exchangerates
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/log"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+)
+
+const exchangeRatesMinRefreshInterval = 30 * time.Minute
+const exchangeRatesBackgroundCheckInterval = time.Minute
+
+// ExchangeRatesCache represents the in-process cache of the latest exchange rates of one data source
+type ExchangeRatesCache struct {
+	dataSourceName      string
+	dataSource          ExchangeRatesDataSource
+	mutex               sync.Mutex
+	latestResponse      *models.LatestExchangeRateResponse
+	fetchUnixTime       int64
+	lastAttemptUnixTime int64
+	lastErrorUnixTime   int64
+	lastError           string
+	lastErr             error
+	fetchFailureCount   int64
+	parseFailureCount   int64
+	pendingFetch        *exchangeRatesFetchCall
+	fetchedCallback     func(c *core.Context, dataSourceName string, latestResponse *models.LatestExchangeRateResponse)
+}
+
+type exchangeRatesFetchCall struct {
+	done           chan struct{}
+	latestResponse *models.LatestExchangeRateResponse
+	parseFailed    bool
+	err            error
+}
+
+// NewExchangeRatesCache returns a new exchange rates cache of the specified data source
+func NewExchangeRatesCache(dataSourceName string, dataSource ExchangeRatesDataSource) *ExchangeRatesCache {
+	return &ExchangeRatesCache{
+		dataSourceName: dataSourceName,
+		dataSource:     dataSource,
+	}
+}
+
+// SetFetchedCallback sets the callback which would be called after the latest exchange rates are fetched successfully
+func (e *ExchangeRatesCache) SetFetchedCallback(callback func(c *core.Context, dataSourceName string, latestResponse *models.LatestExchangeRateResponse)) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.fetchedCallback = callback
+}
+
+// GetLatestExchangeRates returns the cached latest exchange rates and the unix time when they were fetched,
+// it requests the data source when there is no cached data, and refreshes the expired cached data in background,
+// the last error would be returned directly if there is no cached data and the last request failed within the minimum refresh interval
+func (e *ExchangeRatesCache) GetLatestExchangeRates(c *core.Context) (*models.LatestExchangeRateResponse, int64, error) {
+	e.mutex.Lock()
+	latestResponse := e.latestResponse
+	fetchUnixTime := e.fetchUnixTime
+	fetching := e.pendingFetch != nil
+	expired := e.isExpired(time.Now().Unix())
+	lastErr := e.lastErr
+	e.mutex.Unlock()
+
+	if latestResponse == nil {
+		if fetching || expired {
+			return e.Refresh(c)
+		}
+
+		return nil, 0, lastErr
+	}
+
+	if expired {
+		go e.Refresh(newBackgroundContext())
+	}
+
+	return latestResponse, fetchUnixTime, nil
+}
+
+// Refresh requests the latest exchange rates from data source and updates the cache, the concurrent requests would share one fetch,
+// and the cached data would be returned without error if the data source fails
+func (e *ExchangeRatesCache) Refresh(c *core.Context) (*models.LatestExchangeRateResponse, int64, error) {
+	e.mutex.Lock()
+	call := e.pendingFetch
+
+	if call == nil {
+		call = &exchangeRatesFetchCall{
+			done: make(chan struct{}),
+		}
+		e.pendingFetch = call
+		e.mutex.Unlock()
+
+		call.latestResponse, call.parseFailed, call.err = requestLatestExchangeRates(c, e.dataSource)
+
+		e.mutex.Lock()
+		e.pendingFetch = nil
+		e.lastAttemptUnixTime = time.Now().Unix()
+
+		if call.err == nil {
+			e.latestResponse = call.latestResponse
+			e.fetchUnixTime = e.lastAttemptUnixTime
+		} else {
+			e.lastErrorUnixTime = e.lastAttemptUnixTime
+			e.lastError = call.err.Error()
+			e.lastErr = call.err
+
+			if call.parseFailed {
+				e.parseFailureCount++
+			} else {
+				e.fetchFailureCount++
+			}
+		}
+
+		callback := e.fetchedCallback
+		e.mutex.Unlock()
+		close(call.done)
+
+		if call.err == nil && callback != nil {
+			callback(c, e.dataSourceName, call.latestResponse)
+		}
+	} else {
+		e.mutex.Unlock()
+		<-call.done
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if call.err != nil {
+		if e.latestResponse == nil {
+			return nil, 0, call.err
+		}
+
+		log.WarnfWithRequestId(c, "[exchange_rates_cache.Refresh] failed to refresh latest exchange rates of data source \"%s\", use the cached data fetched at %d, because %s", e.dataSourceName, e.fetchUnixTime, call.err.Error())
+	}
+
+	return e.latestResponse, e.fetchUnixTime, nil
+}
+
+// StartBackgroundRefresh starts a goroutine which refreshes the cached exchange rates when they are expired
+func (e *ExchangeRatesCache) StartBackgroundRefresh() {
+	go func() {
+		ticker := time.NewTicker(exchangeRatesBackgroundCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			e.mutex.Lock()
+			expired := e.isExpired(time.Now().Unix())
+			e.mutex.Unlock()
+
+			if expired {
+				_, _, err := e.Refresh(newBackgroundContext())
+
+				if err != nil {
+					log.Warnf("[exchange_rates_cache.StartBackgroundRefresh] failed to refresh latest exchange rates of data source \"%s\", because %s", e.dataSourceName, err.Error())
+				}
+			}
+
+			<-ticker.C
+		}
+	}()
+}
+
+func (e *ExchangeRatesCache) isExpired(now int64) bool {
+	if e.pendingFetch != nil {
+		return false
+	}
+
+	if now-e.lastAttemptUnixTime < int64(exchangeRatesMinRefreshInterval/time.Second) {
+		return false
+	}
+
+	if e.latestResponse == nil {
+		return true
+	}
+
+	// The next exchange rates are expected one publishing interval after the update time of current exchange rates
+	return now >= e.latestResponse.UpdateTime+int64(e.dataSource.GetPublishingInterval()/time.Second)
+}
+
+func newBackgroundContext() *core.Context {
+	return &core.Context{
+		Context: &gin.Context{},
+	}
+}
@@ -0,0 +1,191 @@
+package exchangerates
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+	"github.com/kyy-me/ezbookkeeping/pkg/settings"
+)
+
+type testEuroCentralBankDataSource struct {
+	EuroCentralBankDataSource
+	url string
+}
+
+func (e *testEuroCentralBankDataSource) GetRequestUrls() []string {
+	return []string{e.url}
+}
+
+func newTestExchangeRatesCache(t *testing.T, handler http.HandlerFunc) *ExchangeRatesCache {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	settings.SetCurrentConfig(&settings.Config{
+		ExchangeRatesProxy:          "none",
+		ExchangeRatesRequestTimeout: 5000,
+	})
+
+	return NewExchangeRatesCache("test", &testEuroCentralBankDataSource{
+		url: server.URL,
+	})
+}
+
+func TestExchangeRatesCache_ConcurrentRequestsShareOneFetch(t *testing.T) {
+	var requestCount int32
+	cache := newTestExchangeRatesCache(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requestCount, 1)
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte(euroCentralBankMinimumRequiredContent))
+	})
+
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	var waitGroup sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+			latestExchangeRateResponse, _, err := cache.GetLatestExchangeRates(context)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, "EUR", latestExchangeRateResponse.BaseCurrency)
+		}()
+	}
+
+	waitGroup.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requestCount))
+}
+
+func TestExchangeRatesCache_ServeStaleWhenDataSourceFails(t *testing.T) {
+	var failed atomic.Bool
+	cache := newTestExchangeRatesCache(t, func(w http.ResponseWriter, r *http.Request) {
+		if failed.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Write([]byte(euroCentralBankMinimumRequiredContent))
+	})
+
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, expectedFetchUnixTime, err := cache.Refresh(context)
+	assert.Equal(t, nil, err)
+
+	failed.Store(true)
+
+	actualLatestExchangeRateResponse, actualFetchUnixTime, err := cache.Refresh(context)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "EUR", actualLatestExchangeRateResponse.BaseCurrency)
+	assert.Equal(t, expectedFetchUnixTime, actualFetchUnixTime)
+}
+
+func TestExchangeRatesCache_ReturnErrorWhenDataSourceFailsWithoutCache(t *testing.T) {
+	cache := newTestExchangeRatesCache(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, _, err := cache.GetLatestExchangeRates(context)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestExchangeRatesCache_ReturnLastErrorWithoutRequestWithinMinRefreshInterval(t *testing.T) {
+	var requestCount int32
+	cache := newTestExchangeRatesCache(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requestCount, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, _, expectedErr := cache.GetLatestExchangeRates(context)
+	assert.NotEqual(t, nil, expectedErr)
+
+	_, _, actualErr := cache.GetLatestExchangeRates(context)
+	assert.Equal(t, expectedErr, actualErr)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requestCount))
+}
+
+func TestExchangeRatesCache_IsExpired(t *testing.T) {
+	cache := &ExchangeRatesCache{}
+	assert.Equal(t, true, cache.isExpired(time.Now().Unix()))
+
+	cache.lastAttemptUnixTime = 1617285600
+	assert.Equal(t, false, cache.isExpired(1617285600+60))
+	assert.Equal(t, true, cache.isExpired(1617285600+1800))
+}
+
+func TestExchangeRatesCache_IsExpiredAfterPublishingIntervalOfDataSource(t *testing.T) {
+	cache := &ExchangeRatesCache{
+		dataSource: &EuroCentralBankDataSource{},
+		latestResponse: &models.LatestExchangeRateResponse{
+			UpdateTime: 1617285600,
+		},
+		lastAttemptUnixTime: 1617285600,
+	}
+	assert.Equal(t, false, cache.isExpired(1617285600+3600))
+	assert.Equal(t, true, cache.isExpired(1617285600+86400))
+
+	cache.dataSource = &CustomDataSource{
+		updateInterval: time.Hour,
+	}
+	assert.Equal(t, true, cache.isExpired(1617285600+3600))
+}
+
+func TestExchangeRatesCache_GetHealthRecordsFailures(t *testing.T) {
+	var responseType atomic.Int32
+	cache := newTestExchangeRatesCache(t, func(w http.ResponseWriter, r *http.Request) {
+		if responseType.Load() == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		} else if responseType.Load() == 2 {
+			w.Write([]byte("<gesmes:Envelope"))
+			return
+		}
+
+		w.Write([]byte(euroCentralBankMinimumRequiredContent))
+	})
+
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, _, err := cache.Refresh(context)
+	assert.Equal(t, nil, err)
+
+	responseType.Store(1)
+	cache.Refresh(context)
+
+	responseType.Store(2)
+	cache.Refresh(context)
+
+	health := cache.GetHealth(time.Now().Unix())
+	assert.Equal(t, "test", health.DataSource)
+	assert.NotEqual(t, int64(0), health.LastSuccessfulFetchTime)
+	assert.NotEqual(t, int64(0), health.LastErrorTime)
+	assert.NotEqual(t, "", health.LastError)
+	assert.Equal(t, int64(1), health.FetchFailureCount)
+	assert.Equal(t, int64(1), health.ParseFailureCount)
+	assert.Equal(t, []string{"CNY", "EUR", "USD"}, health.Currencies)
+	assert.Equal(t, true, health.Stale)
+}
@@ -1,15 +1,34 @@
 package exchangerates
 
 import (
+	"time"
+
 	"github.com/kyy-me/ezbookkeeping/pkg/core"
 	"github.com/kyy-me/ezbookkeeping/pkg/models"
 )
 
+// dailyExchangeRatesPublishingInterval is the publishing interval of the data sources which publish new exchange rates once per business day
+const dailyExchangeRatesPublishingInterval = 24 * time.Hour
+
 // ExchangeRatesDataSource defines the structure of exchange rates data source
 type ExchangeRatesDataSource interface {
 	// GetRequestUrl returns the data source urls
 	GetRequestUrls() []string
 
+	// GetPublishingInterval returns the expected interval between the update time of two published exchange rates
+	GetPublishingInterval() time.Duration
+
 	// Parse returns the common response entity according to the data source raw response
 	Parse(c *core.Context, content []byte) (*models.LatestExchangeRateResponse, error)
 }
+
+// HistoricalExchangeRatesDataSource defines the structure of exchange rates data source which also provides historical exchange rates
+type HistoricalExchangeRatesDataSource interface {
+	ExchangeRatesDataSource
+
+	// GetHistoricalRequestUrls returns the historical data source urls
+	GetHistoricalRequestUrls() []string
+
+	// ParseHistorical returns the common response entities of all dates according to the data source historical raw response
+	ParseHistorical(c *core.Context, content []byte) ([]*models.LatestExchangeRateResponse, error)
+}
@@ -1,13 +1,20 @@
 package exchangerates
 
 import (
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
 	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/log"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
 	"github.com/kyy-me/ezbookkeeping/pkg/settings"
 )
 
-// ExchangeRatesDataSourceContainer contains the current exchange rates data source
+// ExchangeRatesDataSourceContainer contains the current exchange rates data source and all fallback data sources
 type ExchangeRatesDataSourceContainer struct {
-	Current ExchangeRatesDataSource
+	Current         ExchangeRatesDataSource
+	Cache           *ExchangeRatesCache
+	DataSources     []ExchangeRatesDataSource
+	DataSourceNames []string
+	Caches          []*ExchangeRatesCache
 }
 
 // Initialize a exchange rates data source container singleton instance
@@ -15,27 +22,105 @@ var (
 	Container = &ExchangeRatesDataSourceContainer{}
 )
 
-// InitializeExchangeRatesDataSource initializes the current exchange rates data source according to the config
+// InitializeExchangeRatesDataSource initializes the current exchange rates data source and all fallback data sources according to the config
 func InitializeExchangeRatesDataSource(config *settings.Config) error {
-	if config.ExchangeRatesDataSource == settings.EuroCentralBankDataSource {
-		Container.Current = &EuroCentralBankDataSource{}
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.BankOfCanadaDataSource {
-		Container.Current = &BankOfCanadaDataSource{}
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.ReserveBankOfAustraliaDataSource {
-		Container.Current = &ReserveBankOfAustraliaDataSource{}
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.CzechNationalBankDataSource {
-		Container.Current = &CzechNationalBankDataSource{}
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.NationalBankOfPolandDataSource {
-		Container.Current = &NationalBankOfPolandDataSource{}
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.MonetaryAuthorityOfSingaporeDataSource {
-		Container.Current = &MonetaryAuthorityOfSingaporeDataSource{}
-		return nil
+	dataSourceNames := config.ExchangeRatesDataSources
+
+	if len(dataSourceNames) < 1 {
+		dataSourceNames = []string{config.ExchangeRatesDataSource}
+	}
+
+	dataSources := make([]ExchangeRatesDataSource, 0, len(dataSourceNames))
+	caches := make([]*ExchangeRatesCache, 0, len(dataSourceNames))
+
+	for i := 0; i < len(dataSourceNames); i++ {
+		dataSource := newExchangeRatesDataSource(config, dataSourceNames[i])
+
+		if dataSource == nil {
+			return errs.ErrInvalidExchangeRatesDataSource
+		}
+
+		dataSources = append(dataSources, dataSource)
+		caches = append(caches, NewExchangeRatesCache(dataSourceNames[i], dataSource))
+	}
+
+	Container.Current = dataSources[0]
+	Container.Cache = caches[0]
+	Container.DataSources = dataSources
+	Container.DataSourceNames = dataSourceNames
+	Container.Caches = caches
+
+	return nil
+}
+
+// GetLatestExchangeRates returns the merged latest exchange rates of all data sources and the unix time when the exchange rates of the first available data source were fetched,
+// the next data source would be consulted only if the previous data sources fail or lack any of the required currencies,
+// and the rates of data sources with different base currencies would be converted into cross rates based on the base currency of the first available data source
+func (e *ExchangeRatesDataSourceContainer) GetLatestExchangeRates(c *core.Context, requiredCurrencies []string) (*models.LatestExchangeRateResponse, int64, error) {
+	var mergedExchangeRates *ExchangeRatesMerger
+	var fetchUnixTime int64
+	var lastErr error
+
+	for i := 0; i < len(e.Caches); i++ {
+		latestExchangeRateResponse, currentFetchUnixTime, err := e.Caches[i].GetLatestExchangeRates(c)
+
+		if err != nil {
+			log.WarnfWithRequestId(c, "[exchange_rates_datasource_container.GetLatestExchangeRates] failed to get latest exchange rates of data source \"%s\", because %s", e.Caches[i].dataSourceName, err.Error())
+			lastErr = err
+			continue
+		}
+
+		if mergedExchangeRates == nil {
+			mergedExchangeRates = NewExchangeRatesMerger(latestExchangeRateResponse)
+			fetchUnixTime = currentFetchUnixTime
+		} else if !mergedExchangeRates.Merge(latestExchangeRateResponse) {
+			log.WarnfWithRequestId(c, "[exchange_rates_datasource_container.GetLatestExchangeRates] cannot derive cross rates from data source \"%s\"", e.Caches[i].dataSourceName)
+		}
+
+		if mergedExchangeRates.ContainsAll(requiredCurrencies) {
+			break
+		}
+	}
+
+	if mergedExchangeRates == nil {
+		if lastErr == nil {
+			lastErr = errs.ErrInvalidExchangeRatesDataSource
+		}
+
+		return nil, 0, lastErr
+	}
+
+	return mergedExchangeRates.ToLatestExchangeRateResponse(), fetchUnixTime, nil
+}
+
+func newExchangeRatesDataSource(config *settings.Config, dataSourceName string) ExchangeRatesDataSource {
+	if dataSourceName == settings.EuroCentralBankDataSource {
+		return &EuroCentralBankDataSource{}
+	} else if dataSourceName == settings.BankOfCanadaDataSource {
+		return &BankOfCanadaDataSource{}
+	} else if dataSourceName == settings.ReserveBankOfAustraliaDataSource {
+		return &ReserveBankOfAustraliaDataSource{}
+	} else if dataSourceName == settings.CzechNationalBankDataSource {
+		return &CzechNationalBankDataSource{}
+	} else if dataSourceName == settings.NationalBankOfPolandDataSource {
+		return &NationalBankOfPolandDataSource{}
+	} else if dataSourceName == settings.MonetaryAuthorityOfSingaporeDataSource {
+		return &MonetaryAuthorityOfSingaporeDataSource{}
+	} else if dataSourceName == settings.BankOfEnglandDataSource {
+		return &BankOfEnglandDataSource{}
+	} else if dataSourceName == settings.SwissNationalBankDataSource {
+		return &SwissNationalBankDataSource{}
+	} else if dataSourceName == settings.BankOfIsraelDataSource {
+		return &BankOfIsraelDataSource{}
+	} else if dataSourceName == settings.DanmarksNationalbankDataSource {
+		return &DanmarksNationalbankDataSource{}
+	} else if dataSourceName == settings.NorgesBankDataSource {
+		return &NorgesBankDataSource{}
+	} else if dataSourceName == settings.InternationalMonetaryFundDataSource {
+		return &InternationalMonetaryFundDataSource{}
+	} else if dataSourceName == settings.CustomExchangeRatesDataSource {
+		return NewCustomDataSource(config)
 	}
 
-	return errs.ErrInvalidExchangeRatesDataSource
+	return nil
 }
@@ -0,0 +1,149 @@
+package exchangerates
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+	"github.com/kyy-me/ezbookkeeping/pkg/utils"
+	"github.com/kyy-me/ezbookkeeping/pkg/validators"
+)
+
+// The data sources do not publish exchange rates on weekends and public holidays,
+// so the exchange rates are regarded as stale only if they have not been updated for several days
+const exchangeRatesStaleThreshold = 4 * 24 * time.Hour
+
+// GetHealth returns the fetching status of this data source, and the currencies and problems of cached exchange rates
+func (e *ExchangeRatesCache) GetHealth(now int64) *models.ExchangeRatesDataSourceHealth {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	health := &models.ExchangeRatesDataSourceHealth{
+		DataSource:              e.dataSourceName,
+		Stale:                   isExchangeRatesStale(e.latestResponse, now),
+		LastSuccessfulFetchTime: e.fetchUnixTime,
+		LastAttemptTime:         e.lastAttemptUnixTime,
+		LastErrorTime:           e.lastErrorUnixTime,
+		LastError:               e.lastError,
+		FetchFailureCount:       e.fetchFailureCount,
+		ParseFailureCount:       e.parseFailureCount,
+		Currencies:              make([]string, 0),
+		Problems:                make([]string, 0),
+	}
+
+	if e.latestResponse != nil {
+		health.UpdateTime = e.latestResponse.UpdateTime
+		health.Currencies = getExchangeRatesCurrencies(e.latestResponse)
+		health.Problems = ValidateLatestExchangeRates(e.latestResponse, now)
+	}
+
+	return health
+}
+
+// GetHealth returns the fetching status of all data sources and the used currencies which are not covered by any data source,
+// the parent account placeholder and user-defined commodities in used currencies are ignored
+func (e *ExchangeRatesDataSourceContainer) GetHealth(usedCurrencies []string, now int64) *models.ExchangeRatesHealthResponse {
+	dataSources := make([]*models.ExchangeRatesDataSourceHealth, 0, len(e.Caches))
+	coveredCurrencies := make(map[string]bool)
+
+	for i := 0; i < len(e.Caches); i++ {
+		health := e.Caches[i].GetHealth(now)
+
+		for j := 0; j < len(health.Currencies); j++ {
+			coveredCurrencies[health.Currencies[j]] = true
+		}
+
+		dataSources = append(dataSources, health)
+	}
+
+	return &models.ExchangeRatesHealthResponse{
+		DataSources:         dataSources,
+		UncoveredCurrencies: GetUncoveredCurrencies(usedCurrencies, coveredCurrencies),
+	}
+}
+
+// GetUncoveredCurrencies returns the sorted currencies in ISO 4217 which are used but not in covered currencies
+func GetUncoveredCurrencies(usedCurrencies []string, coveredCurrencies map[string]bool) []string {
+	uncoveredCurrencies := make([]string, 0)
+	uncoveredCurrencyMap := make(map[string]bool)
+
+	for i := 0; i < len(usedCurrencies); i++ {
+		currency := usedCurrencies[i]
+
+		if _, exists := validators.AllCurrencyNames[currency]; !exists {
+			continue
+		}
+
+		if coveredCurrencies[currency] || uncoveredCurrencyMap[currency] {
+			continue
+		}
+
+		uncoveredCurrencies = append(uncoveredCurrencies, currency)
+		uncoveredCurrencyMap[currency] = true
+	}
+
+	sort.Strings(uncoveredCurrencies)
+
+	return uncoveredCurrencies
+}
+
+// ValidateLatestExchangeRates returns the problems of the latest exchange rates which are fetched from data source,
+// it returns empty slice if the exchange rates are valid and not stale
+func ValidateLatestExchangeRates(latestResponse *models.LatestExchangeRateResponse, now int64) []string {
+	problems := make([]string, 0)
+
+	if latestResponse == nil {
+		return append(problems, "no exchange rates")
+	}
+
+	if _, exists := validators.AllCurrencyNames[latestResponse.BaseCurrency]; !exists {
+		problems = append(problems, fmt.Sprintf("base currency \"%s\" is invalid", latestResponse.BaseCurrency))
+	}
+
+	if len(latestResponse.ExchangeRates) < 2 {
+		problems = append(problems, "no exchange rates except base currency")
+	}
+
+	for i := 0; i < len(latestResponse.ExchangeRates); i++ {
+		exchangeRate := latestResponse.ExchangeRates[i]
+
+		if _, exists := validators.AllCurrencyNames[exchangeRate.Currency]; !exists {
+			problems = append(problems, fmt.Sprintf("currency \"%s\" is invalid", exchangeRate.Currency))
+		}
+
+		rate, err := utils.ParseDecimal(exchangeRate.Rate)
+
+		if err != nil || rate.Sign() <= 0 {
+			problems = append(problems, fmt.Sprintf("rate \"%s\" of currency \"%s\" is invalid", exchangeRate.Rate, exchangeRate.Currency))
+		}
+	}
+
+	if latestResponse.UpdateTime > now {
+		problems = append(problems, fmt.Sprintf("update time %d is in the future", latestResponse.UpdateTime))
+	} else if isExchangeRatesStale(latestResponse, now) {
+		problems = append(problems, fmt.Sprintf("update time %d is stale", latestResponse.UpdateTime))
+	}
+
+	return problems
+}
+
+func isExchangeRatesStale(latestResponse *models.LatestExchangeRateResponse, now int64) bool {
+	if latestResponse == nil {
+		return true
+	}
+
+	return now >= latestResponse.UpdateTime+int64(exchangeRatesStaleThreshold/time.Second)
+}
+
+func getExchangeRatesCurrencies(latestResponse *models.LatestExchangeRateResponse) []string {
+	currencies := make([]string, 0, len(latestResponse.ExchangeRates))
+
+	for i := 0; i < len(latestResponse.ExchangeRates); i++ {
+		currencies = append(currencies, latestResponse.ExchangeRates[i].Currency)
+	}
+
+	sort.Strings(currencies)
+
+	return currencies
+}
@@ -0,0 +1,72 @@
+package exchangerates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+)
+
+func TestGetUncoveredCurrencies(t *testing.T) {
+	coveredCurrencies := map[string]bool{
+		"EUR": true,
+		"USD": true,
+	}
+
+	actualCurrencies := GetUncoveredCurrencies([]string{"USD", "JPY", "---", "BTC", "EUR", "CNY", "JPY"}, coveredCurrencies)
+	assert.Equal(t, []string{"CNY", "JPY"}, actualCurrencies)
+
+	actualCurrencies = GetUncoveredCurrencies([]string{"USD"}, coveredCurrencies)
+	assert.Equal(t, []string{}, actualCurrencies)
+}
+
+func TestValidateLatestExchangeRates_ValidExchangeRates(t *testing.T) {
+	latestResponse := &models.LatestExchangeRateResponse{
+		UpdateTime:   1617235200,
+		BaseCurrency: "EUR",
+		ExchangeRates: models.LatestExchangeRateSlice{
+			{Currency: "EUR", Rate: "1"},
+			{Currency: "USD", Rate: "1.1746"},
+		},
+	}
+
+	actualProblems := ValidateLatestExchangeRates(latestResponse, 1617235200+86400)
+	assert.Equal(t, []string{}, actualProblems)
+}
+
+func TestValidateLatestExchangeRates_InvalidExchangeRates(t *testing.T) {
+	latestResponse := &models.LatestExchangeRateResponse{
+		UpdateTime:   1617235200,
+		BaseCurrency: "EUR",
+		ExchangeRates: models.LatestExchangeRateSlice{
+			{Currency: "EUR", Rate: "1"},
+			{Currency: "XYZ", Rate: "1.1746"},
+			{Currency: "USD", Rate: "0"},
+		},
+	}
+
+	actualProblems := ValidateLatestExchangeRates(latestResponse, 1617235200-60)
+	assert.Equal(t, []string{
+		"currency \"XYZ\" is invalid",
+		"rate \"0\" of currency \"USD\" is invalid",
+		"update time 1617235200 is in the future",
+	}, actualProblems)
+}
+
+func TestValidateLatestExchangeRates_StaleExchangeRates(t *testing.T) {
+	latestResponse := &models.LatestExchangeRateResponse{
+		UpdateTime:   1617235200,
+		BaseCurrency: "EUR",
+		ExchangeRates: models.LatestExchangeRateSlice{
+			{Currency: "EUR", Rate: "1"},
+			{Currency: "USD", Rate: "1.1746"},
+		},
+	}
+
+	actualProblems := ValidateLatestExchangeRates(latestResponse, 1617235200+5*86400)
+	assert.Equal(t, []string{"update time 1617235200 is stale"}, actualProblems)
+
+	actualProblems = ValidateLatestExchangeRates(nil, 1617235200)
+	assert.Equal(t, []string{"no exchange rates"}, actualProblems)
+}
@@ -0,0 +1,148 @@
+package exchangerates
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+	"github.com/kyy-me/ezbookkeeping/pkg/utils"
+)
+
+const crossRateDecimalPlaces = 18
+
+// ExchangeRatesMerger merges the latest exchange rates of multiple data sources into the base currency of the first data source
+type ExchangeRatesMerger struct {
+	first       *models.LatestExchangeRateResponse
+	rates       map[string]*big.Rat
+	rateTexts   map[string]string
+	dataSources map[string]string
+}
+
+// NewExchangeRatesMerger returns a new exchange rates merger which uses the specified exchange rates as the base exchange rates
+func NewExchangeRatesMerger(first *models.LatestExchangeRateResponse) *ExchangeRatesMerger {
+	merger := &ExchangeRatesMerger{
+		first:       first,
+		rates:       make(map[string]*big.Rat, len(first.ExchangeRates)+1),
+		rateTexts:   make(map[string]string, len(first.ExchangeRates)+1),
+		dataSources: make(map[string]string, len(first.ExchangeRates)+1),
+	}
+
+	merger.rates[first.BaseCurrency] = big.NewRat(1, 1)
+	merger.rateTexts[first.BaseCurrency] = "1"
+	merger.dataSources[first.BaseCurrency] = first.DataSource
+
+	for i := 0; i < len(first.ExchangeRates); i++ {
+		exchangeRate := first.ExchangeRates[i]
+		rate, err := utils.ParseDecimal(exchangeRate.Rate)
+
+		if err != nil || rate.Sign() <= 0 {
+			continue
+		}
+
+		merger.rates[exchangeRate.Currency] = rate
+		merger.rateTexts[exchangeRate.Currency] = exchangeRate.Rate
+		merger.dataSources[exchangeRate.Currency] = first.DataSource
+	}
+
+	return merger
+}
+
+// Merge adds the rates of currencies which do not exist yet from the specified exchange rates,
+// the rates would be converted by a currency which exists in both exchange rates if their base currencies are different,
+// returns false if there is no such currency
+func (m *ExchangeRatesMerger) Merge(latestExchangeRateResponse *models.LatestExchangeRateResponse) bool {
+	rates := make(map[string]*big.Rat, len(latestExchangeRateResponse.ExchangeRates)+1)
+	rates[latestExchangeRateResponse.BaseCurrency] = big.NewRat(1, 1)
+
+	for i := 0; i < len(latestExchangeRateResponse.ExchangeRates); i++ {
+		exchangeRate := latestExchangeRateResponse.ExchangeRates[i]
+		rate, err := utils.ParseDecimal(exchangeRate.Rate)
+
+		if err != nil || rate.Sign() <= 0 {
+			continue
+		}
+
+		rates[exchangeRate.Currency] = rate
+	}
+
+	bridgeCurrency := m.getBridgeCurrency(latestExchangeRateResponse.BaseCurrency, rates)
+
+	if bridgeCurrency == "" {
+		return false
+	}
+
+	factor := new(big.Rat).Quo(m.rates[bridgeCurrency], rates[bridgeCurrency])
+
+	for currency, rate := range rates {
+		if _, exists := m.rates[currency]; exists {
+			continue
+		}
+
+		crossRate := new(big.Rat).Mul(rate, factor)
+		m.rates[currency] = crossRate
+		m.rateTexts[currency] = utils.FormatDecimal(crossRate, crossRateDecimalPlaces)
+		m.dataSources[currency] = latestExchangeRateResponse.DataSource
+	}
+
+	return true
+}
+
+// ContainsAll returns whether the merged exchange rates contain all the specified currencies
+func (m *ExchangeRatesMerger) ContainsAll(currencies []string) bool {
+	for i := 0; i < len(currencies); i++ {
+		if _, exists := m.rates[currencies[i]]; !exists {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ToLatestExchangeRateResponse returns a view-object of the merged exchange rates, each rate contains the data source which supplied it
+func (m *ExchangeRatesMerger) ToLatestExchangeRateResponse() *models.LatestExchangeRateResponse {
+	exchangeRates := make(models.LatestExchangeRateSlice, 0, len(m.rateTexts))
+
+	for currency, rate := range m.rateTexts {
+		exchangeRates = append(exchangeRates, &models.LatestExchangeRate{
+			Currency:   currency,
+			Rate:       rate,
+			DataSource: m.dataSources[currency],
+		})
+	}
+
+	sort.Sort(exchangeRates)
+
+	return &models.LatestExchangeRateResponse{
+		DataSource:    m.first.DataSource,
+		ReferenceUrl:  m.first.ReferenceUrl,
+		UpdateTime:    m.first.UpdateTime,
+		BaseCurrency:  m.first.BaseCurrency,
+		ExchangeRates: exchangeRates,
+	}
+}
+
+func (m *ExchangeRatesMerger) getBridgeCurrency(baseCurrency string, rates map[string]*big.Rat) string {
+	if _, exists := m.rates[baseCurrency]; exists {
+		return baseCurrency
+	}
+
+	if _, exists := rates[m.first.BaseCurrency]; exists {
+		return m.first.BaseCurrency
+	}
+
+	commonCurrencies := make([]string, 0)
+
+	for currency := range rates {
+		if _, exists := m.rates[currency]; exists {
+			commonCurrencies = append(commonCurrencies, currency)
+		}
+	}
+
+	if len(commonCurrencies) < 1 {
+		return ""
+	}
+
+	sort.Strings(commonCurrencies)
+
+	return commonCurrencies[0]
+}
@@ -0,0 +1,132 @@
+package exchangerates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+)
+
+func getTestExchangeRate(exchangeRates models.LatestExchangeRateSlice, currency string) *models.LatestExchangeRate {
+	for i := 0; i < len(exchangeRates); i++ {
+		if exchangeRates[i].Currency == currency {
+			return exchangeRates[i]
+		}
+	}
+
+	return nil
+}
+
+func TestExchangeRatesMerger_MergeSameBaseCurrency(t *testing.T) {
+	merger := NewExchangeRatesMerger(&models.LatestExchangeRateResponse{
+		DataSource:   "First",
+		BaseCurrency: "EUR",
+		ExchangeRates: models.LatestExchangeRateSlice{
+			{Currency: "USD", Rate: "1.1"},
+		},
+	})
+
+	assert.Equal(t, true, merger.Merge(&models.LatestExchangeRateResponse{
+		DataSource:   "Second",
+		BaseCurrency: "EUR",
+		ExchangeRates: models.LatestExchangeRateSlice{
+			{Currency: "USD", Rate: "1.2"},
+			{Currency: "CNY", Rate: "7.5"},
+		},
+	}))
+
+	actualResponse := merger.ToLatestExchangeRateResponse()
+	assert.Equal(t, "EUR", actualResponse.BaseCurrency)
+	assert.Equal(t, &models.LatestExchangeRate{Currency: "EUR", Rate: "1", DataSource: "First"}, getTestExchangeRate(actualResponse.ExchangeRates, "EUR"))
+	assert.Equal(t, &models.LatestExchangeRate{Currency: "USD", Rate: "1.1", DataSource: "First"}, getTestExchangeRate(actualResponse.ExchangeRates, "USD"))
+	assert.Equal(t, &models.LatestExchangeRate{Currency: "CNY", Rate: "7.5", DataSource: "Second"}, getTestExchangeRate(actualResponse.ExchangeRates, "CNY"))
+}
+
+func TestExchangeRatesMerger_MergeCrossRatesByBaseCurrency(t *testing.T) {
+	merger := NewExchangeRatesMerger(&models.LatestExchangeRateResponse{
+		DataSource:   "European Central Bank",
+		BaseCurrency: "EUR",
+		ExchangeRates: models.LatestExchangeRateSlice{
+			{Currency: "CAD", Rate: "1.5"},
+		},
+	})
+
+	assert.Equal(t, true, merger.Merge(&models.LatestExchangeRateResponse{
+		DataSource:   "Bank of Canada",
+		BaseCurrency: "CAD",
+		ExchangeRates: models.LatestExchangeRateSlice{
+			{Currency: "TWD", Rate: "23"},
+		},
+	}))
+
+	actualResponse := merger.ToLatestExchangeRateResponse()
+	assert.Equal(t, &models.LatestExchangeRate{Currency: "CAD", Rate: "1.5", DataSource: "European Central Bank"}, getTestExchangeRate(actualResponse.ExchangeRates, "CAD"))
+	assert.Equal(t, &models.LatestExchangeRate{Currency: "TWD", Rate: "34.5", DataSource: "Bank of Canada"}, getTestExchangeRate(actualResponse.ExchangeRates, "TWD"))
+	assert.Equal(t, true, merger.ContainsAll([]string{"EUR", "CAD", "TWD"}))
+	assert.Equal(t, false, merger.ContainsAll([]string{"EUR", "JPY"}))
+}
+
+func TestExchangeRatesMerger_MergeCrossRatesByCommonCurrency(t *testing.T) {
+	merger := NewExchangeRatesMerger(&models.LatestExchangeRateResponse{
+		DataSource:   "First",
+		BaseCurrency: "EUR",
+		ExchangeRates: models.LatestExchangeRateSlice{
+			{Currency: "USD", Rate: "1.25"},
+		},
+	})
+
+	assert.Equal(t, true, merger.Merge(&models.LatestExchangeRateResponse{
+		DataSource:   "Second",
+		BaseCurrency: "AUD",
+		ExchangeRates: models.LatestExchangeRateSlice{
+			{Currency: "USD", Rate: "0.5"},
+			{Currency: "JPY", Rate: "100"},
+		},
+	}))
+
+	actualResponse := merger.ToLatestExchangeRateResponse()
+	assert.Equal(t, &models.LatestExchangeRate{Currency: "AUD", Rate: "2.5", DataSource: "Second"}, getTestExchangeRate(actualResponse.ExchangeRates, "AUD"))
+	assert.Equal(t, &models.LatestExchangeRate{Currency: "JPY", Rate: "250", DataSource: "Second"}, getTestExchangeRate(actualResponse.ExchangeRates, "JPY"))
+}
+
+func TestExchangeRatesMerger_MergeWithoutCommonCurrency(t *testing.T) {
+	merger := NewExchangeRatesMerger(&models.LatestExchangeRateResponse{
+		DataSource:   "First",
+		BaseCurrency: "EUR",
+		ExchangeRates: models.LatestExchangeRateSlice{
+			{Currency: "USD", Rate: "1.25"},
+		},
+	})
+
+	assert.Equal(t, false, merger.Merge(&models.LatestExchangeRateResponse{
+		DataSource:   "Second",
+		BaseCurrency: "AUD",
+		ExchangeRates: models.LatestExchangeRateSlice{
+			{Currency: "JPY", Rate: "100"},
+		},
+	}))
+}
+
+func TestExchangeRatesMerger_MergeCrossRatesWithExactDecimal(t *testing.T) {
+	merger := NewExchangeRatesMerger(&models.LatestExchangeRateResponse{
+		DataSource:   "First",
+		BaseCurrency: "EUR",
+		ExchangeRates: models.LatestExchangeRateSlice{
+			{Currency: "USD", Rate: "1.0637"},
+		},
+	})
+
+	assert.Equal(t, true, merger.Merge(&models.LatestExchangeRateResponse{
+		DataSource:   "Second",
+		BaseCurrency: "USD",
+		ExchangeRates: models.LatestExchangeRateSlice{
+			{Currency: "IDR", Rate: "123456.789012"},
+			{Currency: "BHD", Rate: "3"},
+		},
+	}))
+
+	actualResponse := merger.ToLatestExchangeRateResponse()
+	assert.Equal(t, &models.LatestExchangeRate{Currency: "IDR", Rate: "131320.9864720644", DataSource: "Second"}, getTestExchangeRate(actualResponse.ExchangeRates, "IDR"))
+	assert.Equal(t, &models.LatestExchangeRate{Currency: "BHD", Rate: "3.1911", DataSource: "Second"}, getTestExchangeRate(actualResponse.ExchangeRates, "BHD"))
+}
@@ -0,0 +1,180 @@
+package exchangerates
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/log"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+	"github.com/kyy-me/ezbookkeeping/pkg/settings"
+	"github.com/kyy-me/ezbookkeeping/pkg/utils"
+)
+
+// GetLatestExchangeRates requests all urls of the specified data source and returns the merged latest exchange rates
+func GetLatestExchangeRates(c *core.Context, dataSource ExchangeRatesDataSource) (*models.LatestExchangeRateResponse, error) {
+	latestExchangeRateResponse, _, err := requestLatestExchangeRates(c, dataSource)
+	return latestExchangeRateResponse, err
+}
+
+// requestLatestExchangeRates returns the merged latest exchange rates of the specified data source,
+// and whether the error is caused by failing to parse the response rather than failing to request the data source
+func requestLatestExchangeRates(c *core.Context, dataSource ExchangeRatesDataSource) (*models.LatestExchangeRateResponse, bool, error) {
+	if dataSource == nil {
+		return nil, false, errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	urls := dataSource.GetRequestUrls()
+	exchangeRateResps := make([]*models.LatestExchangeRateResponse, 0, len(urls))
+
+	for i := 0; i < len(urls); i++ {
+		body, err := requestDataSource(c, urls[i])
+
+		if err != nil {
+			return nil, false, err
+		}
+
+		exchangeRateResp, err := dataSource.Parse(c, body)
+
+		if err != nil {
+			log.ErrorfWithRequestId(c, "[exchange_rates_requester.requestLatestExchangeRates] failed to parse response of \"%s\", because %s", urls[i], err.Error())
+			return nil, true, errs.Or(err, errs.ErrFailedToRequestRemoteApi)
+		}
+
+		if exchangeRateResp == nil {
+			log.ErrorfWithRequestId(c, "[exchange_rates_requester.requestLatestExchangeRates] response of \"%s\" contains no exchange rates", urls[i])
+			return nil, true, errs.ErrFailedToRequestRemoteApi
+		}
+
+		exchangeRateResps = append(exchangeRateResps, exchangeRateResp)
+	}
+
+	lastExchangeRateResponse := exchangeRateResps[len(exchangeRateResps)-1]
+	allExchangeRatesMap := make(map[string]string)
+
+	for i := 0; i < len(exchangeRateResps); i++ {
+		exchangeRateResp := exchangeRateResps[i]
+
+		for j := 0; j < len(exchangeRateResp.ExchangeRates); j++ {
+			exchangeRate := exchangeRateResp.ExchangeRates[j]
+			allExchangeRatesMap[exchangeRate.Currency] = exchangeRate.Rate
+		}
+	}
+
+	allExchangeRatesMap[lastExchangeRateResponse.BaseCurrency] = "1"
+	allExchangeRates := make(models.LatestExchangeRateSlice, 0, len(allExchangeRatesMap))
+
+	for currency, rate := range allExchangeRatesMap {
+		allExchangeRates = append(allExchangeRates, &models.LatestExchangeRate{
+			Currency: currency,
+			Rate:     rate,
+		})
+	}
+
+	sort.Sort(allExchangeRates)
+
+	finalExchangeRateResponse := &models.LatestExchangeRateResponse{
+		DataSource:    lastExchangeRateResponse.DataSource,
+		ReferenceUrl:  lastExchangeRateResponse.ReferenceUrl,
+		UpdateTime:    lastExchangeRateResponse.UpdateTime,
+		BaseCurrency:  lastExchangeRateResponse.BaseCurrency,
+		ExchangeRates: allExchangeRates,
+	}
+
+	return finalExchangeRateResponse, false, nil
+}
+
+// GetHistoricalExchangeRates requests all historical urls of the specified data source and returns the exchange rates of all dates
+func GetHistoricalExchangeRates(c *core.Context, dataSource ExchangeRatesDataSource) ([]*models.LatestExchangeRateResponse, error) {
+	historicalDataSource, ok := dataSource.(HistoricalExchangeRatesDataSource)
+
+	if !ok {
+		return nil, errs.ErrHistoricalExchangeRatesNotSupported
+	}
+
+	urls := historicalDataSource.GetHistoricalRequestUrls()
+	allExchangeRateResps := make([]*models.LatestExchangeRateResponse, 0)
+
+	for i := 0; i < len(urls); i++ {
+		body, err := requestDataSource(c, urls[i])
+
+		if err != nil {
+			return nil, err
+		}
+
+		exchangeRateResps, err := ParseHistoricalExchangeRates(c, historicalDataSource, body)
+
+		if err != nil {
+			return nil, err
+		}
+
+		allExchangeRateResps = append(allExchangeRateResps, exchangeRateResps...)
+	}
+
+	return allExchangeRateResps, nil
+}
+
+// ParseHistoricalExchangeRates returns the exchange rates of all dates according to the historical raw content of the specified data source
+func ParseHistoricalExchangeRates(c *core.Context, dataSource ExchangeRatesDataSource, content []byte) ([]*models.LatestExchangeRateResponse, error) {
+	historicalDataSource, ok := dataSource.(HistoricalExchangeRatesDataSource)
+
+	if !ok {
+		return nil, errs.ErrHistoricalExchangeRatesNotSupported
+	}
+
+	exchangeRateResps, err := historicalDataSource.ParseHistorical(c, content)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[exchange_rates_requester.ParseHistoricalExchangeRates] failed to parse historical response, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrFailedToRequestRemoteApi)
+	}
+
+	return exchangeRateResps, nil
+}
+
+func requestDataSource(c *core.Context, url string) ([]byte, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	utils.SetProxyUrl(transport, settings.Container.Current.ExchangeRatesProxy)
+
+	if settings.Container.Current.ExchangeRatesSkipTLSVerify {
+		transport.TLSClientConfig = &tls.Config{
+			InsecureSkipVerify: true,
+		}
+	}
+
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   time.Duration(settings.Container.Current.ExchangeRatesRequestTimeout) * time.Millisecond,
+	}
+
+	req, _ := http.NewRequest("GET", url, nil)
+	req.Header.Set("User-Agent", fmt.Sprintf("ezBookkeeping/%s ", settings.Version))
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[exchange_rates_requester.requestDataSource] failed to request exchange rate data from \"%s\", because %s", url, err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		log.ErrorfWithRequestId(c, "[exchange_rates_requester.requestDataSource] failed to get exchange rate data response from \"%s\", because response code is %d", url, resp.StatusCode)
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	body, err := io.ReadAll(resp.Body)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[exchange_rates_requester.requestDataSource] failed to read exchange rate data response from \"%s\", because %s", url, err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return body, nil
+}
@@ -0,0 +1,312 @@
+package exchangerates
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/log"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+	"github.com/kyy-me/ezbookkeeping/pkg/utils"
+	"github.com/kyy-me/ezbookkeeping/pkg/validators"
+)
+
+const internationalMonetaryFundExchangeRateUrl = "https://www.imf.org/external/np/fin/data/rms_five.aspx?tsvflag=Y"
+const internationalMonetaryFundHistoricalExchangeRateUrlFormat = "https://www.imf.org/external/np/fin/data/rms_mth.aspx?SelectDate=%s&reportType=REP&tsvflag=Y"
+const internationalMonetaryFundExchangeRateReferenceUrl = "https://www.imf.org/external/np/fin/data/rms_five.aspx"
+const internationalMonetaryFundDataSource = "International Monetary Fund"
+const internationalMonetaryFundBaseCurrency = "USD"
+
+const internationalMonetaryFundCurrencyUnitsPerSDRSectionTitle = "Currency units per SDR"
+const internationalMonetaryFundCurrencyColumnTitle = "Currency"
+const internationalMonetaryFundNotAvailableValue = "NA"
+
+const internationalMonetaryFundHistoricalStartYear = 1995
+const internationalMonetaryFundRequestDateFormat = "2006-01-02"
+
+const internationalMonetaryFundDataUpdateDateFormat = "January 2, 2006 15:04"
+const internationalMonetaryFundDataUpdateDateTimezone = "Europe/London"
+
+// The currency names in the representative exchange rates of the IMF
+var internationalMonetaryFundCurrencyNameCodes = map[string]string{
+	"Algerian dinar":      "DZD",
+	"Australian dollar":   "AUD",
+	"Botswana pula":       "BWP",
+	"Brazilian real":      "BRL",
+	"Brunei dollar":       "BND",
+	"Canadian dollar":     "CAD",
+	"Chilean peso":        "CLP",
+	"Chinese yuan":        "CNY",
+	"Colombian peso":      "COP",
+	"Czech koruna":        "CZK",
+	"Danish krone":        "DKK",
+	"Euro":                "EUR",
+	"Indian rupee":        "INR",
+	"Israeli New Shekel":  "ILS",
+	"Japanese yen":        "JPY",
+	"Korean won":          "KRW",
+	"Kuwaiti dinar":       "KWD",
+	"Malaysian ringgit":   "MYR",
+	"Mauritian rupee":     "MUR",
+	"Mexican peso":        "MXN",
+	"New Zealand dollar":  "NZD",
+	"Norwegian krone":     "NOK",
+	"Omani rial":          "OMR",
+	"Peruvian sol":        "PEN",
+	"Philippine peso":     "PHP",
+	"Polish zloty":        "PLN",
+	"Qatari riyal":        "QAR",
+	"Russian ruble":       "RUB",
+	"Saudi Arabian riyal": "SAR",
+	"Singapore dollar":    "SGD",
+	"South African rand":  "ZAR",
+	"Swedish krona":       "SEK",
+	"Swiss franc":         "CHF",
+	"Thai baht":           "THB",
+	"Trinidadian dollar":  "TTD",
+	"U.A.E. dirham":       "AED",
+	"U.K. pound":          "GBP",
+	"U.S. dollar":         "USD",
+	"Uruguayan peso":      "UYU",
+}
+
+// InternationalMonetaryFundDataSource defines the structure of exchange rates data source of the international monetary fund
+type InternationalMonetaryFundDataSource struct {
+	ExchangeRatesDataSource
+}
+
+// GetRequestUrls returns the international monetary fund data source urls
+func (e *InternationalMonetaryFundDataSource) GetRequestUrls() []string {
+	return []string{internationalMonetaryFundExchangeRateUrl}
+}
+
+// GetPublishingInterval returns the publishing interval of international monetary fund data source, which publishes new exchange rates once per business day
+func (e *InternationalMonetaryFundDataSource) GetPublishingInterval() time.Duration {
+	return dailyExchangeRatesPublishingInterval
+}
+
+// GetHistoricalRequestUrls returns the international monetary fund historical data source urls, each of which contains the exchange rates of one month
+func (e *InternationalMonetaryFundDataSource) GetHistoricalRequestUrls() []string {
+	startMonth := time.Date(internationalMonetaryFundHistoricalStartYear, time.January, 1, 0, 0, 0, 0, time.UTC)
+	today := time.Now()
+	urls := make([]string, 0)
+
+	for month := startMonth; !month.After(today); month = month.AddDate(0, 1, 0) {
+		lastDayOfMonth := month.AddDate(0, 1, -1)
+		urls = append(urls, fmt.Sprintf(internationalMonetaryFundHistoricalExchangeRateUrlFormat, lastDayOfMonth.Format(internationalMonetaryFundRequestDateFormat)))
+	}
+
+	return urls
+}
+
+// Parse returns the common response entity according to the international monetary fund data source raw response
+func (e *InternationalMonetaryFundDataSource) Parse(c *core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
+	dates, currencyRatesPerSDR, err := e.parseCurrencyRatesPerSDR(c, content)
+
+	if err != nil {
+		return nil, err
+	}
+
+	baseCurrencyRatesPerSDR := currencyRatesPerSDR[internationalMonetaryFundBaseCurrency]
+
+	// The rates of the most recent date on which the rate of base currency is available would be used
+	for i := 0; i < len(dates) && i < len(baseCurrencyRatesPerSDR); i++ {
+		if e.parseRatePerSDR(baseCurrencyRatesPerSDR[i]) <= 0 {
+			continue
+		}
+
+		latestExchangeRateResp := e.toExchangeRateResponse(c, dates[i], i, currencyRatesPerSDR)
+
+		if latestExchangeRateResp == nil {
+			log.ErrorfWithRequestId(c, "[international_monetary_fund_datasource.Parse] failed to parse latest exchange rate data, content is %s", string(content))
+			return nil, errs.ErrFailedToRequestRemoteApi
+		}
+
+		return latestExchangeRateResp, nil
+	}
+
+	log.ErrorfWithRequestId(c, "[international_monetary_fund_datasource.Parse] no valid rate of base currency, content is %s", string(content))
+	return nil, errs.ErrFailedToRequestRemoteApi
+}
+
+// ParseHistorical returns the common response entities of all dates according to the international monetary fund historical data source raw response
+func (e *InternationalMonetaryFundDataSource) ParseHistorical(c *core.Context, content []byte) ([]*models.LatestExchangeRateResponse, error) {
+	dates, currencyRatesPerSDR, err := e.parseCurrencyRatesPerSDR(c, content)
+
+	if err != nil {
+		return nil, err
+	}
+
+	baseCurrencyRatesPerSDR := currencyRatesPerSDR[internationalMonetaryFundBaseCurrency]
+	historicalExchangeRateResponses := make([]*models.LatestExchangeRateResponse, 0, len(dates))
+
+	for i := 0; i < len(dates) && i < len(baseCurrencyRatesPerSDR); i++ {
+		if e.parseRatePerSDR(baseCurrencyRatesPerSDR[i]) <= 0 {
+			continue
+		}
+
+		exchangeRateResp := e.toExchangeRateResponse(c, dates[i], i, currencyRatesPerSDR)
+
+		if exchangeRateResp == nil {
+			log.ErrorfWithRequestId(c, "[international_monetary_fund_datasource.ParseHistorical] failed to parse historical exchange rate data of date %s", dates[i])
+			return nil, errs.ErrFailedToRequestRemoteApi
+		}
+
+		historicalExchangeRateResponses = append(historicalExchangeRateResponses, exchangeRateResp)
+	}
+
+	if len(historicalExchangeRateResponses) < 1 {
+		log.ErrorfWithRequestId(c, "[international_monetary_fund_datasource.ParseHistorical] no valid rate of base currency")
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return historicalExchangeRateResponses, nil
+}
+
+func (e *InternationalMonetaryFundDataSource) parseCurrencyRatesPerSDR(c *core.Context, content []byte) ([]string, map[string][]string, error) {
+	allLines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+	dates, currencyLines := e.getCurrencyUnitsPerSDRSection(allLines)
+
+	if len(dates) < 1 || len(currencyLines) < 1 {
+		log.ErrorfWithRequestId(c, "[international_monetary_fund_datasource.parseCurrencyRatesPerSDR] content is invalid, content is %s", string(content))
+		return nil, nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	currencyRatesPerSDR := make(map[string][]string, len(currencyLines))
+
+	for i := 0; i < len(currencyLines); i++ {
+		items := strings.Split(currencyLines[i], "\t")
+		currencyCode, exists := internationalMonetaryFundCurrencyNameCodes[strings.TrimSpace(items[0])]
+
+		if !exists {
+			continue
+		}
+
+		currencyRatesPerSDR[currencyCode] = items[1:]
+	}
+
+	if _, exists := currencyRatesPerSDR[internationalMonetaryFundBaseCurrency]; !exists {
+		log.ErrorfWithRequestId(c, "[international_monetary_fund_datasource.parseCurrencyRatesPerSDR] missing base currency, content is %s", string(content))
+		return nil, nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return dates, currencyRatesPerSDR, nil
+}
+
+func (e *InternationalMonetaryFundDataSource) toExchangeRateResponse(c *core.Context, date string, dateColumnIndex int, currencyRatesPerSDR map[string][]string) *models.LatestExchangeRateResponse {
+	baseCurrencyRatePerSDR := e.parseRatePerSDR(currencyRatesPerSDR[internationalMonetaryFundBaseCurrency][dateColumnIndex])
+	exchangeRates := make(models.LatestExchangeRateSlice, 0, len(currencyRatesPerSDR))
+
+	for currencyCode, ratesPerSDR := range currencyRatesPerSDR {
+		if currencyCode == internationalMonetaryFundBaseCurrency || dateColumnIndex >= len(ratesPerSDR) {
+			continue
+		}
+
+		if _, exists := validators.AllCurrencyNames[currencyCode]; !exists {
+			continue
+		}
+
+		rate := e.parseRatePerSDR(ratesPerSDR[dateColumnIndex])
+
+		if rate <= 0 {
+			continue
+		}
+
+		exchangeRates = append(exchangeRates, &models.LatestExchangeRate{
+			Currency: currencyCode,
+			Rate:     utils.Float64ToString(rate / baseCurrencyRatePerSDR),
+		})
+	}
+
+	timezone, err := time.LoadLocation(internationalMonetaryFundDataUpdateDateTimezone)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[international_monetary_fund_datasource.toExchangeRateResponse] failed to get timezone, timezone name is %s", internationalMonetaryFundDataUpdateDateTimezone)
+		return nil
+	}
+
+	updateDateTime := date + " 12:00" // The representative rates are the noon rates in the London market
+	updateTime, err := time.ParseInLocation(internationalMonetaryFundDataUpdateDateFormat, updateDateTime, timezone)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[international_monetary_fund_datasource.toExchangeRateResponse] failed to parse update date, datetime is %s", updateDateTime)
+		return nil
+	}
+
+	return &models.LatestExchangeRateResponse{
+		DataSource:    internationalMonetaryFundDataSource,
+		ReferenceUrl:  internationalMonetaryFundExchangeRateReferenceUrl,
+		UpdateTime:    updateTime.Unix(),
+		BaseCurrency:  internationalMonetaryFundBaseCurrency,
+		ExchangeRates: exchangeRates,
+	}
+}
+
+func (e *InternationalMonetaryFundDataSource) getCurrencyUnitsPerSDRSection(allLines []string) ([]string, []string) {
+	sectionStartIndex := -1
+
+	for i := 0; i < len(allLines); i++ {
+		if strings.HasPrefix(strings.TrimSpace(allLines[i]), internationalMonetaryFundCurrencyUnitsPerSDRSectionTitle) {
+			sectionStartIndex = i + 1
+			break
+		}
+	}
+
+	if sectionStartIndex < 0 {
+		return nil, nil
+	}
+
+	var dates []string
+	var currencyLines []string
+
+	for i := sectionStartIndex; i < len(allLines); i++ {
+		line := strings.TrimRight(allLines[i], " \t")
+
+		if dates == nil {
+			if line == "" {
+				continue
+			}
+
+			items := strings.Split(line, "\t")
+
+			if strings.TrimSpace(items[0]) != internationalMonetaryFundCurrencyColumnTitle {
+				return nil, nil
+			}
+
+			dates = make([]string, 0, len(items)-1)
+
+			for j := 1; j < len(items); j++ {
+				dates = append(dates, strings.TrimSpace(items[j]))
+			}
+
+			continue
+		}
+
+		if line == "" || !strings.Contains(line, "\t") {
+			break
+		}
+
+		currencyLines = append(currencyLines, line)
+	}
+
+	return dates, currencyLines
+}
+
+func (e *InternationalMonetaryFundDataSource) parseRatePerSDR(value string) float64 {
+	value = strings.ReplaceAll(strings.TrimSpace(value), ",", "")
+
+	if value == "" || value == internationalMonetaryFundNotAvailableValue {
+		return 0
+	}
+
+	rate, err := utils.StringToFloat64(value)
+
+	if err != nil || math.IsNaN(rate) || math.IsInf(rate, 0) || rate <= 0 {
+		return 0
+	}
+
+	return rate
+}
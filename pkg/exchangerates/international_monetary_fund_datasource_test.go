@@ -0,0 +1,191 @@
+package exchangerates
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+)
+
+const internationalMonetaryFundMinimumRequiredContent = "SDRs per Currency unit and Currency units per SDR (1)\r\n" +
+	"last five days\r\n" +
+	"\r\n" +
+	"SDRs per Currency unit (2)\r\n" +
+	"\r\n" +
+	"Currency\tApril 17, 2024\tApril 16, 2024\r\n" +
+	"Euro\t0.8100445524503848\t0.8064516129032258\r\n" +
+	"Korean won\t0.0005452205962533\t0.0005447342007\r\n" +
+	"U.S. dollar\t0.7570595806\t0.7575757576\r\n" +
+	"\r\n" +
+	"Currency units per SDR(3)\r\n" +
+	"\r\n" +
+	"Currency\tApril 17, 2024\tApril 16, 2024\r\n" +
+	"Euro\t1.2345\t1.24\r\n" +
+	"Korean won\t1,834.12\t1,835.76\r\n" +
+	"U.S. dollar\t1.3209\t1.32\r\n" +
+	"\r\n" +
+	"(1) Exchange rates are published for currencies for which the IMF has received information.\r\n"
+
+func TestInternationalMonetaryFundDataSource_StandardDataExtractBaseCurrency(t *testing.T) {
+	dataSource := &InternationalMonetaryFundDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(internationalMonetaryFundMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "USD", actualLatestExchangeRateResponse.BaseCurrency)
+}
+
+func TestInternationalMonetaryFundDataSource_StandardDataExtractExchangeRates(t *testing.T) {
+	dataSource := &InternationalMonetaryFundDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(internationalMonetaryFundMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1713351600), actualLatestExchangeRateResponse.UpdateTime)
+	assert.Equal(t, 2, len(actualLatestExchangeRateResponse.ExchangeRates))
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "EUR",
+		Rate:     "0.934590052237111",
+	})
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "KRW",
+		Rate:     "1388.5381179498827",
+	})
+}
+
+func TestInternationalMonetaryFundDataSource_BaseCurrencyNotAvailableInLatestDate(t *testing.T) {
+	dataSource := &InternationalMonetaryFundDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte("Currency units per SDR(3)\n"+
+		"\n"+
+		"Currency\tApril 17, 2024\tApril 16, 2024\n"+
+		"Euro\t1.2345\t1.24\n"+
+		"U.S. dollar\tNA\t1.32\n"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1713265200), actualLatestExchangeRateResponse.UpdateTime)
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "EUR",
+		Rate:     "0.9393939393939393",
+	})
+}
+
+func TestInternationalMonetaryFundDataSource_BlankContent(t *testing.T) {
+	dataSource := &InternationalMonetaryFundDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, err := dataSource.Parse(context, []byte(""))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestInternationalMonetaryFundDataSource_MissingCurrencyUnitsPerSDRSection(t *testing.T) {
+	dataSource := &InternationalMonetaryFundDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, err := dataSource.Parse(context, []byte("SDRs per Currency unit (2)\n"+
+		"\n"+
+		"Currency\tApril 17, 2024\n"+
+		"Euro\t0.8100445524503848\n"+
+		"U.S. dollar\t0.7570595806\n"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestInternationalMonetaryFundDataSource_MissingBaseCurrency(t *testing.T) {
+	dataSource := &InternationalMonetaryFundDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, err := dataSource.Parse(context, []byte("Currency units per SDR(3)\n"+
+		"\n"+
+		"Currency\tApril 17, 2024\n"+
+		"Euro\t1.2345\n"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestInternationalMonetaryFundDataSource_InvalidCurrency(t *testing.T) {
+	dataSource := &InternationalMonetaryFundDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte("Currency units per SDR(3)\n"+
+		"\n"+
+		"Currency\tApril 17, 2024\n"+
+		"Unknown currency\t1.2345\n"+
+		"U.S. dollar\t1.3209\n"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(actualLatestExchangeRateResponse.ExchangeRates))
+}
+
+func TestInternationalMonetaryFundDataSource_InvalidRate(t *testing.T) {
+	dataSource := &InternationalMonetaryFundDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte("Currency units per SDR(3)\n"+
+		"\n"+
+		"Currency\tApril 17, 2024\n"+
+		"Euro\tnull\n"+
+		"U.S. dollar\t1.3209\n"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(actualLatestExchangeRateResponse.ExchangeRates))
+
+	_, err = dataSource.Parse(context, []byte("Currency units per SDR(3)\n"+
+		"\n"+
+		"Currency\tApril 17, 2024\n"+
+		"Euro\t1.2345\n"+
+		"U.S. dollar\tNA\n"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestInternationalMonetaryFundDataSource_HistoricalDataExtractAllDates(t *testing.T) {
+	dataSource := &InternationalMonetaryFundDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualHistoricalExchangeRateResponses, err := dataSource.ParseHistorical(context, []byte("Currency units per SDR(3)\n"+
+		"\n"+
+		"Currency\tApril 15, 2024\tApril 16, 2024\tApril 17, 2024\n"+
+		"Euro\t1.25\t1.24\t1.2345\n"+
+		"U.S. dollar\t1.33\t1.32\tNA\n"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(actualHistoricalExchangeRateResponses))
+
+	assert.Equal(t, int64(1713178800), actualHistoricalExchangeRateResponses[0].UpdateTime)
+	assert.Contains(t, actualHistoricalExchangeRateResponses[0].ExchangeRates, &models.LatestExchangeRate{
+		Currency: "EUR",
+		Rate:     "0.9398496240601504",
+	})
+
+	assert.Equal(t, int64(1713265200), actualHistoricalExchangeRateResponses[1].UpdateTime)
+	assert.Contains(t, actualHistoricalExchangeRateResponses[1].ExchangeRates, &models.LatestExchangeRate{
+		Currency: "EUR",
+		Rate:     "0.9393939393939393",
+	})
+}
+
+func TestInternationalMonetaryFundDataSource_HistoricalBlankContent(t *testing.T) {
+	dataSource := &InternationalMonetaryFundDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, err := dataSource.ParseHistorical(context, []byte(""))
+	assert.NotEqual(t, nil, err)
+}
@@ -158,6 +158,11 @@ func (e *MonetaryAuthorityOfSingaporeDataSource) GetRequestUrls() []string {
 	return []string{monetaryAuthorityOfSingaporeExchangeRateUrl}
 }
 
+// GetPublishingInterval returns the publishing interval of Monetary Authority of Singapore data source, which publishes new exchange rates once per business day
+func (e *MonetaryAuthorityOfSingaporeDataSource) GetPublishingInterval() time.Duration {
+	return dailyExchangeRatesPublishingInterval
+}
+
 // Parse returns the common response entity according to the Monetary Authority of Singapore data source raw response
 func (e *MonetaryAuthorityOfSingaporeDataSource) Parse(c *core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
 	monetaryAuthorityOfSingaporeData := &MonetaryAuthorityOfSingaporeExchangeRateData{}
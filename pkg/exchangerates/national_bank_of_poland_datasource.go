@@ -3,6 +3,7 @@ package exchangerates
 import (
 	"bytes"
 	"encoding/xml"
+	"fmt"
 	"math"
 	"time"
 
@@ -16,10 +17,15 @@ import (
 
 const nationalBankOfPolandDailyExchangeRateUrl = "https://api.nbp.pl/api/exchangerates/tables/A?format=xml"
 const nationalBankOfPolandInconvertibleCurrencyExchangeRateUrl = "https://api.nbp.pl/api/exchangerates/tables/B?format=xml"
+const nationalBankOfPolandHistoricalExchangeRateUrlFormat = "https://api.nbp.pl/api/exchangerates/tables/%s/%s/%s/?format=xml"
 const nationalBankOfPolandExchangeRateReferenceUrl = "https://nbp.pl/en/statistic-and-financial-reporting/rates/"
 const nationalBankOfPolandDataSource = "Narodowy Bank Polski"
 const nationalBankOfPolandBaseCurrency = "PLN"
 
+const nationalBankOfPolandHistoricalStartDate = "2002-01-02"
+const nationalBankOfPolandHistoricalMaxDaysPerRequest = 93
+const nationalBankOfPolandRequestDateFormat = "2006-01-02"
+
 const nationalBankOfPolandDataUpdateDateFormat = "2006-01-02 15:04"
 const nationalBankOfPolandDataUpdateDateTimezone = "Europe/Warsaw"
 
@@ -35,6 +41,18 @@ type NationalBankOfPolandExchangeRateData struct {
 	AllExchangeRates []*NationalBankOfPolandExchangeRate `xml:"ExchangeRatesTable>Rates>Rate"`
 }
 
+// NationalBankOfPolandHistoricalExchangeRateData represents the whole historical data from National Bank of Poland
+type NationalBankOfPolandHistoricalExchangeRateData struct {
+	XMLName               xml.Name                                 `xml:"ArrayOfExchangeRatesTable"`
+	AllExchangeRateTables []*NationalBankOfPolandExchangeRateTable `xml:"ExchangeRatesTable"`
+}
+
+// NationalBankOfPolandExchangeRateTable represents the exchange rates table of one date from National Bank of Poland
+type NationalBankOfPolandExchangeRateTable struct {
+	Date             string                              `xml:"EffectiveDate"`
+	AllExchangeRates []*NationalBankOfPolandExchangeRate `xml:"Rates>Rate"`
+}
+
 // NationalBankOfPolandExchangeRate represents the exchange rate data from National Bank of Poland
 type NationalBankOfPolandExchangeRate struct {
 	Currency string `xml:"Code"`
@@ -43,6 +61,38 @@ type NationalBankOfPolandExchangeRate struct {
 
 // ToLatestExchangeRateResponse returns a view-object according to original data from National Bank of Poland
 func (e *NationalBankOfPolandExchangeRateData) ToLatestExchangeRateResponse(c *core.Context) *models.LatestExchangeRateResponse {
+	exchangeRateTable := &NationalBankOfPolandExchangeRateTable{
+		Date:             e.Date,
+		AllExchangeRates: e.AllExchangeRates,
+	}
+
+	return exchangeRateTable.ToLatestExchangeRateResponse(c)
+}
+
+// ToHistoricalExchangeRateResponses returns view-objects of all dates according to original data from National Bank of Poland
+func (e *NationalBankOfPolandHistoricalExchangeRateData) ToHistoricalExchangeRateResponses(c *core.Context) []*models.LatestExchangeRateResponse {
+	if len(e.AllExchangeRateTables) < 1 {
+		log.ErrorfWithRequestId(c, "[national_bank_of_poland_datasource.ToHistoricalExchangeRateResponses] all exchange rate tables is empty")
+		return nil
+	}
+
+	exchangeRateResps := make([]*models.LatestExchangeRateResponse, 0, len(e.AllExchangeRateTables))
+
+	for i := 0; i < len(e.AllExchangeRateTables); i++ {
+		exchangeRateResp := e.AllExchangeRateTables[i].ToLatestExchangeRateResponse(c)
+
+		if exchangeRateResp == nil {
+			return nil
+		}
+
+		exchangeRateResps = append(exchangeRateResps, exchangeRateResp)
+	}
+
+	return exchangeRateResps
+}
+
+// ToLatestExchangeRateResponse returns a view-object of one date according to original data from National Bank of Poland
+func (e *NationalBankOfPolandExchangeRateTable) ToLatestExchangeRateResponse(c *core.Context) *models.LatestExchangeRateResponse {
 	if len(e.AllExchangeRates) < 1 {
 		log.ErrorfWithRequestId(c, "[national_bank_of_poland_datasource.ToLatestExchangeRateResponse] all exchange rates is empty")
 		return nil
@@ -123,6 +173,34 @@ func (e *NationalBankOfPolandDataSource) GetRequestUrls() []string {
 	return []string{nationalBankOfPolandInconvertibleCurrencyExchangeRateUrl, nationalBankOfPolandDailyExchangeRateUrl}
 }
 
+// GetPublishingInterval returns the publishing interval of National Bank of Poland data source, which publishes new exchange rates once per business day
+func (e *NationalBankOfPolandDataSource) GetPublishingInterval() time.Duration {
+	return dailyExchangeRatesPublishingInterval
+}
+
+// GetHistoricalRequestUrls returns the National Bank of Poland historical data source urls, each of which contains the exchange rates of one table in at most 93 days
+func (e *NationalBankOfPolandDataSource) GetHistoricalRequestUrls() []string {
+	startDate, _ := time.Parse(nationalBankOfPolandRequestDateFormat, nationalBankOfPolandHistoricalStartDate)
+	urls := make([]string, 0)
+
+	// The date ranges are split backward from today, so that every range contains business days and the api would not return not found
+	for tableEndDate := time.Now(); !tableEndDate.Before(startDate); tableEndDate = tableEndDate.AddDate(0, 0, -nationalBankOfPolandHistoricalMaxDaysPerRequest) {
+		tableStartDate := tableEndDate.AddDate(0, 0, 1-nationalBankOfPolandHistoricalMaxDaysPerRequest)
+
+		if tableStartDate.Before(startDate) {
+			tableStartDate = startDate
+		}
+
+		startDateValue := tableStartDate.Format(nationalBankOfPolandRequestDateFormat)
+		endDateValue := tableEndDate.Format(nationalBankOfPolandRequestDateFormat)
+
+		urls = append(urls, fmt.Sprintf(nationalBankOfPolandHistoricalExchangeRateUrlFormat, "A", startDateValue, endDateValue))
+		urls = append(urls, fmt.Sprintf(nationalBankOfPolandHistoricalExchangeRateUrlFormat, "B", startDateValue, endDateValue))
+	}
+
+	return urls
+}
+
 // Parse returns the common response entity according to the National Bank of Poland data source raw response
 func (e *NationalBankOfPolandDataSource) Parse(c *core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
 	nationalBankOfPolandData := &NationalBankOfPolandExchangeRateData{}
@@ -145,3 +223,26 @@ func (e *NationalBankOfPolandDataSource) Parse(c *core.Context, content []byte)
 
 	return latestExchangeRateResponse, nil
 }
+
+// ParseHistorical returns the common response entities of all dates according to the National Bank of Poland historical data source raw response
+func (e *NationalBankOfPolandDataSource) ParseHistorical(c *core.Context, content []byte) ([]*models.LatestExchangeRateResponse, error) {
+	nationalBankOfPolandData := &NationalBankOfPolandHistoricalExchangeRateData{}
+
+	xmlDecoder := xml.NewDecoder(bytes.NewReader(content))
+	xmlDecoder.CharsetReader = utils.IdentReader
+	err := xmlDecoder.Decode(&nationalBankOfPolandData)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[national_bank_of_poland_datasource.ParseHistorical] failed to parse xml data, because %s", err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	historicalExchangeRateResponses := nationalBankOfPolandData.ToHistoricalExchangeRateResponses(c)
+
+	if historicalExchangeRateResponses == nil {
+		log.ErrorfWithRequestId(c, "[national_bank_of_poland_datasource.ParseHistorical] failed to parse historical exchange rate data")
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return historicalExchangeRateResponses, nil
+}
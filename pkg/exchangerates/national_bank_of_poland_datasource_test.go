@@ -184,3 +184,70 @@ func TestNationalBankOfPolandDataSource_InvalidRate(t *testing.T) {
 	assert.Equal(t, nil, err)
 	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 0)
 }
+
+const nationalBankOfPolandHistoricalContent = "<?xml version=\"1.0\" encoding=\"utf-8\"?>\n" +
+	"<ArrayOfExchangeRatesTable xmlns:xsd=\"http://www.w3.org/2001/XMLSchema\" xmlns:xsi=\"http://www.w3.org/2001/XMLSchema-instance\">\n" +
+	"  <ExchangeRatesTable>\n" +
+	"    <EffectiveDate>2024-02-27</EffectiveDate>\n" +
+	"    <Rates>\n" +
+	"      <Rate>\n" +
+	"        <Code>USD</Code>\n" +
+	"        <Mid>4.0000</Mid>\n" +
+	"      </Rate>\n" +
+	"    </Rates>\n" +
+	"  </ExchangeRatesTable>\n" +
+	"  <ExchangeRatesTable>\n" +
+	"    <EffectiveDate>2024-02-28</EffectiveDate>\n" +
+	"    <Rates>\n" +
+	"      <Rate>\n" +
+	"        <Code>USD</Code>\n" +
+	"        <Mid>3.9922</Mid>\n" +
+	"      </Rate>\n" +
+	"      <Rate>\n" +
+	"        <Code>CNY</Code>\n" +
+	"        <Mid>0.5545</Mid>\n" +
+	"      </Rate>\n" +
+	"    </Rates>\n" +
+	"  </ExchangeRatesTable>\n" +
+	"</ArrayOfExchangeRatesTable>"
+
+func TestNationalBankOfPolandDataSource_HistoricalRequestUrls(t *testing.T) {
+	dataSource := &NationalBankOfPolandDataSource{}
+	urls := dataSource.GetHistoricalRequestUrls()
+
+	assert.True(t, len(urls) > 2)
+	assert.Equal(t, 0, len(urls)%2)
+	assert.Contains(t, urls[len(urls)-2], "/tables/A/2002-01-02/")
+	assert.Contains(t, urls[len(urls)-1], "/tables/B/2002-01-02/")
+}
+
+func TestNationalBankOfPolandDataSource_HistoricalDataExtractAllDates(t *testing.T) {
+	dataSource := &NationalBankOfPolandDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualHistoricalExchangeRateResponses, err := dataSource.ParseHistorical(context, []byte(nationalBankOfPolandHistoricalContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(actualHistoricalExchangeRateResponses))
+
+	assert.Equal(t, int64(1709032500), actualHistoricalExchangeRateResponses[0].UpdateTime)
+	assert.Equal(t, 1, len(actualHistoricalExchangeRateResponses[0].ExchangeRates))
+	assert.Contains(t, actualHistoricalExchangeRateResponses[0].ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "0.25",
+	})
+
+	assert.Equal(t, int64(1709118900), actualHistoricalExchangeRateResponses[1].UpdateTime)
+	assert.Equal(t, 2, len(actualHistoricalExchangeRateResponses[1].ExchangeRates))
+}
+
+func TestNationalBankOfPolandDataSource_HistoricalBlankContent(t *testing.T) {
+	dataSource := &NationalBankOfPolandDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, err := dataSource.ParseHistorical(context, []byte(""))
+	assert.NotEqual(t, nil, err)
+}
@@ -0,0 +1,246 @@
+package exchangerates
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/log"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+	"github.com/kyy-me/ezbookkeeping/pkg/utils"
+	"github.com/kyy-me/ezbookkeeping/pkg/validators"
+)
+
+const norgesBankExchangeRateUrl = "https://data.norges-bank.no/api/data/EXR/B..NOK.SP?format=csv&lastNObservations=1&locale=en&bom=exclude"
+const norgesBankHistoricalExchangeRateUrl = "https://data.norges-bank.no/api/data/EXR/B..NOK.SP?format=csv&startPeriod=1999-01-01&locale=en&bom=exclude"
+const norgesBankExchangeRateReferenceUrl = "https://www.norges-bank.no/en/topics/Statistics/exchange_rates/"
+const norgesBankDataSource = "Norges Bank"
+const norgesBankBaseCurrency = "NOK"
+
+const norgesBankDataUpdateDateFormat = "2006-01-02 15:04"
+const norgesBankDataUpdateDateTimezone = "Europe/Oslo"
+
+// NorgesBankDataSource defines the structure of exchange rates data source of Norges Bank
+type NorgesBankDataSource struct {
+	ExchangeRatesDataSource
+}
+
+// GetRequestUrls returns the Norges Bank data source urls
+func (e *NorgesBankDataSource) GetRequestUrls() []string {
+	return []string{norgesBankExchangeRateUrl}
+}
+
+// GetPublishingInterval returns the publishing interval of Norges Bank data source, which publishes new exchange rates once per business day
+func (e *NorgesBankDataSource) GetPublishingInterval() time.Duration {
+	return dailyExchangeRatesPublishingInterval
+}
+
+// GetHistoricalRequestUrls returns the Norges Bank historical data source urls
+func (e *NorgesBankDataSource) GetHistoricalRequestUrls() []string {
+	return []string{norgesBankHistoricalExchangeRateUrl}
+}
+
+// Parse returns the common response entity according to the Norges Bank data source raw response
+func (e *NorgesBankDataSource) Parse(c *core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
+	dates, allExchangeRates, err := e.parseAllExchangeRates(c, content)
+
+	if err != nil {
+		return nil, err
+	}
+
+	exchangeRates := make(models.LatestExchangeRateSlice, 0)
+	latestUpdateDate := ""
+
+	for i := 0; i < len(dates); i++ {
+		if strings.Compare(dates[i], latestUpdateDate) > 0 {
+			latestUpdateDate = dates[i]
+		}
+
+		exchangeRates = append(exchangeRates, allExchangeRates[dates[i]]...)
+	}
+
+	latestExchangeRateResp := e.toExchangeRateResponse(c, latestUpdateDate, exchangeRates)
+
+	if latestExchangeRateResp == nil {
+		log.ErrorfWithRequestId(c, "[norges_bank_datasource.Parse] failed to parse latest exchange rate data, content is %s", string(content))
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return latestExchangeRateResp, nil
+}
+
+// ParseHistorical returns the common response entities of all dates according to the Norges Bank historical data source raw response
+func (e *NorgesBankDataSource) ParseHistorical(c *core.Context, content []byte) ([]*models.LatestExchangeRateResponse, error) {
+	dates, allExchangeRates, err := e.parseAllExchangeRates(c, content)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(dates)
+	historicalExchangeRateResponses := make([]*models.LatestExchangeRateResponse, 0, len(dates))
+
+	for i := 0; i < len(dates); i++ {
+		exchangeRateResp := e.toExchangeRateResponse(c, dates[i], allExchangeRates[dates[i]])
+
+		if exchangeRateResp == nil {
+			log.ErrorfWithRequestId(c, "[norges_bank_datasource.ParseHistorical] failed to parse historical exchange rate data of date %s", dates[i])
+			return nil, errs.ErrFailedToRequestRemoteApi
+		}
+
+		historicalExchangeRateResponses = append(historicalExchangeRateResponses, exchangeRateResp)
+	}
+
+	return historicalExchangeRateResponses, nil
+}
+
+func (e *NorgesBankDataSource) parseAllExchangeRates(c *core.Context, content []byte) ([]string, map[string]models.LatestExchangeRateSlice, error) {
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.Comma = ';'
+	reader.FieldsPerRecord = -1
+
+	allLines, err := reader.ReadAll()
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[norges_bank_datasource.parseAllExchangeRates] failed to parse csv data, because %s", err.Error())
+		return nil, nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	if len(allLines) < 2 {
+		log.ErrorfWithRequestId(c, "[norges_bank_datasource.parseAllExchangeRates] content is invalid, content is %s", string(content))
+		return nil, nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	titleItemMap := make(map[string]int)
+
+	for i := 0; i < len(allLines[0]); i++ {
+		titleItemMap[strings.TrimSpace(allLines[0][i])] = i
+	}
+
+	currencyCodeColumnIndex, exists := titleItemMap["BASE_CUR"]
+
+	if !exists {
+		log.ErrorfWithRequestId(c, "[norges_bank_datasource.parseAllExchangeRates] missing currency code column in title line, title line is %s", strings.Join(allLines[0], ";"))
+		return nil, nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	unitMultiplierColumnIndex, exists := titleItemMap["UNIT_MULT"]
+
+	if !exists {
+		log.ErrorfWithRequestId(c, "[norges_bank_datasource.parseAllExchangeRates] missing unit multiplier column in title line, title line is %s", strings.Join(allLines[0], ";"))
+		return nil, nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	dateColumnIndex, exists := titleItemMap["TIME_PERIOD"]
+
+	if !exists {
+		log.ErrorfWithRequestId(c, "[norges_bank_datasource.parseAllExchangeRates] missing date column in title line, title line is %s", strings.Join(allLines[0], ";"))
+		return nil, nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	rateColumnIndex, exists := titleItemMap["OBS_VALUE"]
+
+	if !exists {
+		log.ErrorfWithRequestId(c, "[norges_bank_datasource.parseAllExchangeRates] missing rate column in title line, title line is %s", strings.Join(allLines[0], ";"))
+		return nil, nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	dates := make([]string, 0)
+	allExchangeRates := make(map[string]models.LatestExchangeRateSlice)
+
+	for i := 1; i < len(allLines); i++ {
+		items := allLines[i]
+
+		if currencyCodeColumnIndex >= len(items) || unitMultiplierColumnIndex >= len(items) || dateColumnIndex >= len(items) || rateColumnIndex >= len(items) {
+			log.WarnfWithRequestId(c, "[norges_bank_datasource.parseAllExchangeRates] missing column in data line, line is %s", strings.Join(items, ";"))
+			continue
+		}
+
+		exchangeRate := e.parseExchangeRate(c, items[currencyCodeColumnIndex], items[unitMultiplierColumnIndex], items[rateColumnIndex])
+
+		if exchangeRate == nil {
+			continue
+		}
+
+		date := items[dateColumnIndex]
+
+		if _, exists := allExchangeRates[date]; !exists {
+			dates = append(dates, date)
+		}
+
+		allExchangeRates[date] = append(allExchangeRates[date], exchangeRate)
+	}
+
+	if len(dates) < 1 {
+		log.ErrorfWithRequestId(c, "[norges_bank_datasource.parseAllExchangeRates] no valid exchange rate, content is %s", string(content))
+		return nil, nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return dates, allExchangeRates, nil
+}
+
+func (e *NorgesBankDataSource) toExchangeRateResponse(c *core.Context, updateDate string, exchangeRates models.LatestExchangeRateSlice) *models.LatestExchangeRateResponse {
+	timezone, err := time.LoadLocation(norgesBankDataUpdateDateTimezone)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[norges_bank_datasource.toExchangeRateResponse] failed to get timezone, timezone name is %s", norgesBankDataUpdateDateTimezone)
+		return nil
+	}
+
+	updateDateTime := updateDate + " 16:00" // The exchange rates are published around 4 p.m. on banking days
+	updateTime, err := time.ParseInLocation(norgesBankDataUpdateDateFormat, updateDateTime, timezone)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[norges_bank_datasource.toExchangeRateResponse] failed to parse update date, datetime is %s", updateDateTime)
+		return nil
+	}
+
+	return &models.LatestExchangeRateResponse{
+		DataSource:    norgesBankDataSource,
+		ReferenceUrl:  norgesBankExchangeRateReferenceUrl,
+		UpdateTime:    updateTime.Unix(),
+		BaseCurrency:  norgesBankBaseCurrency,
+		ExchangeRates: exchangeRates,
+	}
+}
+
+func (e *NorgesBankDataSource) parseExchangeRate(c *core.Context, currencyCode string, unitMultiplierValue string, rateValue string) *models.LatestExchangeRate {
+	if _, exists := validators.AllCurrencyNames[currencyCode]; !exists {
+		return nil
+	}
+
+	unitMultiplier, err := utils.StringToInt(unitMultiplierValue)
+
+	if err != nil || unitMultiplier < 0 {
+		log.WarnfWithRequestId(c, "[norges_bank_datasource.parseExchangeRate] failed to parse unit multiplier, currency is %s, unit multiplier is %s", currencyCode, unitMultiplierValue)
+		return nil
+	}
+
+	rate, err := utils.StringToFloat64(rateValue)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[norges_bank_datasource.parseExchangeRate] failed to parse rate, currency is %s, rate is %s", currencyCode, rateValue)
+		return nil
+	}
+
+	if math.IsNaN(rate) || rate <= 0 {
+		log.WarnfWithRequestId(c, "[norges_bank_datasource.parseExchangeRate] rate is invalid, currency is %s, rate is %s", currencyCode, rateValue)
+		return nil
+	}
+
+	finalRate := math.Pow10(unitMultiplier) / rate // The rates are the amount of norwegian krone per 10^unit_mult units of foreign currency
+
+	if math.IsInf(finalRate, 0) {
+		return nil
+	}
+
+	return &models.LatestExchangeRate{
+		Currency: currencyCode,
+		Rate:     utils.Float64ToString(finalRate),
+	}
+}
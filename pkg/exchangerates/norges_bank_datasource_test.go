@@ -0,0 +1,142 @@
+package exchangerates
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+)
+
+const norgesBankMinimumRequiredContent = "FREQ;Frequency;BASE_CUR;Base Currency;QUOTE_CUR;Quote Currency;TENOR;Tenor;DECIMALS;CALCULATED;UNIT_MULT;Unit Multiplier;COLLECTION;Collection Indicator;TIME_PERIOD;OBS_VALUE\n" +
+	"B;Business;USD;US dollar;NOK;Norwegian krone;SP;Spot;4;false;0;Units;C;ECB concertation time 14:15 CET;2024-04-17;10.9913\n" +
+	"B;Business;JPY;Japanese yen;NOK;Norwegian krone;SP;Spot;4;false;2;Hundreds;C;ECB concertation time 14:15 CET;2024-04-17;7.1191\n"
+
+func TestNorgesBankDataSource_StandardDataExtractBaseCurrency(t *testing.T) {
+	dataSource := &NorgesBankDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(norgesBankMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "NOK", actualLatestExchangeRateResponse.BaseCurrency)
+}
+
+func TestNorgesBankDataSource_StandardDataExtractExchangeRates(t *testing.T) {
+	dataSource := &NorgesBankDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(norgesBankMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1713362400), actualLatestExchangeRateResponse.UpdateTime)
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "0.0909810486475667",
+	})
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "JPY",
+		Rate:     "14.046719388686771",
+	})
+}
+
+func TestNorgesBankDataSource_BlankContent(t *testing.T) {
+	dataSource := &NorgesBankDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, err := dataSource.Parse(context, []byte(""))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestNorgesBankDataSource_OnlyTitle(t *testing.T) {
+	dataSource := &NorgesBankDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, err := dataSource.Parse(context, []byte("FREQ;BASE_CUR;QUOTE_CUR;UNIT_MULT;TIME_PERIOD;OBS_VALUE\n"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestNorgesBankDataSource_TitleMissingRate(t *testing.T) {
+	dataSource := &NorgesBankDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, err := dataSource.Parse(context, []byte("FREQ;BASE_CUR;QUOTE_CUR;UNIT_MULT;TIME_PERIOD\n"+
+		"B;USD;NOK;0;2024-04-17\n"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestNorgesBankDataSource_InvalidCurrency(t *testing.T) {
+	dataSource := &NorgesBankDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte("FREQ;BASE_CUR;QUOTE_CUR;UNIT_MULT;TIME_PERIOD;OBS_VALUE\n"+
+		"B;XXX;NOK;0;2024-04-17;1\n"+
+		"B;USD;NOK;0;2024-04-17;10.9913\n"))
+	assert.Equal(t, nil, err)
+	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 1)
+}
+
+func TestNorgesBankDataSource_InvalidRate(t *testing.T) {
+	dataSource := &NorgesBankDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte("FREQ;BASE_CUR;QUOTE_CUR;UNIT_MULT;TIME_PERIOD;OBS_VALUE\n"+
+		"B;EUR;NOK;0;2024-04-17;NaN\n"+
+		"B;USD;NOK;0;2024-04-17;10.9913\n"))
+	assert.Equal(t, nil, err)
+	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 1)
+}
+
+const norgesBankHistoricalContent = "FREQ;Frequency;BASE_CUR;Base Currency;QUOTE_CUR;Quote Currency;TENOR;Tenor;DECIMALS;CALCULATED;UNIT_MULT;Unit Multiplier;COLLECTION;Collection Indicator;TIME_PERIOD;OBS_VALUE\n" +
+	"B;Business;USD;US dollar;NOK;Norwegian krone;SP;Spot;4;false;0;Units;C;ECB concertation time 14:15 CET;2024-04-16;11.0000\n" +
+	"B;Business;USD;US dollar;NOK;Norwegian krone;SP;Spot;4;false;0;Units;C;ECB concertation time 14:15 CET;2024-04-17;10.9913\n" +
+	"B;Business;JPY;Japanese yen;NOK;Norwegian krone;SP;Spot;4;false;2;Hundreds;C;ECB concertation time 14:15 CET;2024-04-17;7.1191\n"
+
+func TestNorgesBankDataSource_HistoricalDataExtractAllDates(t *testing.T) {
+	dataSource := &NorgesBankDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualHistoricalExchangeRateResponses, err := dataSource.ParseHistorical(context, []byte(norgesBankHistoricalContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(actualHistoricalExchangeRateResponses))
+
+	assert.Equal(t, int64(1713276000), actualHistoricalExchangeRateResponses[0].UpdateTime)
+	assert.Equal(t, 1, len(actualHistoricalExchangeRateResponses[0].ExchangeRates))
+	assert.Contains(t, actualHistoricalExchangeRateResponses[0].ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "0.09090909090909091",
+	})
+
+	assert.Equal(t, int64(1713362400), actualHistoricalExchangeRateResponses[1].UpdateTime)
+	assert.Equal(t, 2, len(actualHistoricalExchangeRateResponses[1].ExchangeRates))
+	assert.Contains(t, actualHistoricalExchangeRateResponses[1].ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "0.0909810486475667",
+	})
+}
+
+func TestNorgesBankDataSource_HistoricalBlankContent(t *testing.T) {
+	dataSource := &NorgesBankDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, err := dataSource.ParseHistorical(context, []byte(""))
+	assert.NotEqual(t, nil, err)
+}
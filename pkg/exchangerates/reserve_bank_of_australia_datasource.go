@@ -127,6 +127,11 @@ func (e *ReserveBankOfAustraliaDataSource) GetRequestUrls() []string {
 	return []string{reserveBankOfAustraliaExchangeRateUrl}
 }
 
+// GetPublishingInterval returns the publishing interval of reserve bank of Australia data source, which publishes new exchange rates once per business day
+func (e *ReserveBankOfAustraliaDataSource) GetPublishingInterval() time.Duration {
+	return dailyExchangeRatesPublishingInterval
+}
+
 // Parse returns the common response entity according to the the reserve bank of Australia data source raw response
 func (e *ReserveBankOfAustraliaDataSource) Parse(c *core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
 	reserveBankOfAustraliaData := &ReserveBankOfAustraliaData{}
@@ -0,0 +1,338 @@
+package exchangerates
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/log"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+	"github.com/kyy-me/ezbookkeeping/pkg/utils"
+	"github.com/kyy-me/ezbookkeeping/pkg/validators"
+)
+
+const swissNationalBankExchangeRateUrl = "https://www.snb.ch/selector/en/mmr/exfeed/rss"
+const swissNationalBankHistoricalExchangeRateUrl = "https://data.snb.ch/api/cube/devkum/data/csv/en"
+const swissNationalBankExchangeRateReferenceUrl = "https://data.snb.ch/en/topics/ziredev#!/cube/devkum"
+const swissNationalBankDataSource = "Swiss National Bank"
+const swissNationalBankBaseCurrency = "CHF"
+
+const swissNationalBankHistoricalDateColumnTitle = "Date"
+const swissNationalBankHistoricalTypeColumnTitle = "D0"
+const swissNationalBankHistoricalCurrencyColumnTitle = "D1"
+const swissNationalBankHistoricalRateColumnTitle = "Value"
+const swissNationalBankHistoricalMonthEndRateType = "M1"
+const swissNationalBankHistoricalDataMonthFormat = "2006-01 15:04"
+const swissNationalBankHistoricalDataUpdateDateTimezone = "Europe/Zurich"
+
+// SwissNationalBankDataSource defines the structure of exchange rates data source of Swiss National Bank
+type SwissNationalBankDataSource struct {
+	ExchangeRatesDataSource
+}
+
+// SwissNationalBankExchangeRateData represents the whole data from Swiss National Bank
+type SwissNationalBankExchangeRateData struct {
+	XMLName xml.Name                    `xml:"RDF"`
+	Items   []*SwissNationalBankRssItem `xml:"item"`
+}
+
+// SwissNationalBankRssItem represents the rss item data from Swiss National Bank
+type SwissNationalBankRssItem struct {
+	Date         string                         `xml:"date"`
+	ExchangeRate *SwissNationalBankExchangeRate `xml:"statistics>exchangeRate"`
+}
+
+// SwissNationalBankExchangeRate represents the exchange rate data from Swiss National Bank
+type SwissNationalBankExchangeRate struct {
+	Rate           string                     `xml:"observation>value"`
+	BaseCurrency   *SwissNationalBankCurrency `xml:"baseCurrency"`
+	TargetCurrency string                     `xml:"targetCurrency"`
+	Date           string                     `xml:"observationPeriod"`
+}
+
+// SwissNationalBankCurrency represents the currency and its unit from Swiss National Bank
+type SwissNationalBankCurrency struct {
+	Currency string `xml:",chardata"`
+	Unit     string `xml:"unit,attr"`
+}
+
+// ToLatestExchangeRateResponse returns a view-object according to original data from Swiss National Bank
+func (e *SwissNationalBankExchangeRateData) ToLatestExchangeRateResponse(c *core.Context) *models.LatestExchangeRateResponse {
+	if len(e.Items) < 1 {
+		log.ErrorfWithRequestId(c, "[swiss_national_bank_datasource.ToLatestExchangeRateResponse] all exchange rates is empty")
+		return nil
+	}
+
+	exchangeRateMap := make(map[string]*models.LatestExchangeRate)
+	exchangeRateDates := make(map[string]string)
+	var latestUpdateTime time.Time
+
+	for i := 0; i < len(e.Items); i++ {
+		item := e.Items[i]
+		exchangeRate := item.ExchangeRate
+
+		if exchangeRate == nil || exchangeRate.BaseCurrency == nil || strings.TrimSpace(exchangeRate.TargetCurrency) != swissNationalBankBaseCurrency {
+			continue
+		}
+
+		currencyCode := strings.TrimSpace(exchangeRate.BaseCurrency.Currency)
+
+		if _, exists := validators.AllCurrencyNames[currencyCode]; !exists {
+			continue
+		}
+
+		if strings.Compare(exchangeRate.Date, exchangeRateDates[currencyCode]) < 0 {
+			continue
+		}
+
+		finalExchangeRate := exchangeRate.ToLatestExchangeRate(c)
+
+		if finalExchangeRate == nil {
+			continue
+		}
+
+		updateTime, err := time.Parse(time.RFC3339, strings.TrimSpace(item.Date))
+
+		if err != nil {
+			log.WarnfWithRequestId(c, "[swiss_national_bank_datasource.ToLatestExchangeRateResponse] failed to parse update time, currency is %s, time is %s", currencyCode, item.Date)
+			continue
+		}
+
+		if updateTime.After(latestUpdateTime) {
+			latestUpdateTime = updateTime
+		}
+
+		exchangeRateMap[currencyCode] = finalExchangeRate
+		exchangeRateDates[currencyCode] = exchangeRate.Date
+	}
+
+	if len(exchangeRateMap) < 1 {
+		log.ErrorfWithRequestId(c, "[swiss_national_bank_datasource.ToLatestExchangeRateResponse] no valid exchange rate")
+		return nil
+	}
+
+	exchangeRates := make(models.LatestExchangeRateSlice, 0, len(exchangeRateMap))
+
+	for _, exchangeRate := range exchangeRateMap {
+		exchangeRates = append(exchangeRates, exchangeRate)
+	}
+
+	latestExchangeRateResp := &models.LatestExchangeRateResponse{
+		DataSource:    swissNationalBankDataSource,
+		ReferenceUrl:  swissNationalBankExchangeRateReferenceUrl,
+		UpdateTime:    latestUpdateTime.Unix(),
+		BaseCurrency:  swissNationalBankBaseCurrency,
+		ExchangeRates: exchangeRates,
+	}
+
+	return latestExchangeRateResp
+}
+
+// ToLatestExchangeRate returns a data pair according to original data from Swiss National Bank
+func (e *SwissNationalBankExchangeRate) ToLatestExchangeRate(c *core.Context) *models.LatestExchangeRate {
+	currencyCode := strings.TrimSpace(e.BaseCurrency.Currency)
+	unit := float64(1)
+
+	if e.BaseCurrency.Unit != "" {
+		parsedUnit, err := utils.StringToFloat64(e.BaseCurrency.Unit)
+
+		if err != nil || math.IsNaN(parsedUnit) || parsedUnit <= 0 {
+			log.WarnfWithRequestId(c, "[swiss_national_bank_datasource.ToLatestExchangeRate] failed to parse unit, currency is %s, unit is %s", currencyCode, e.BaseCurrency.Unit)
+			return nil
+		}
+
+		unit = parsedUnit
+	}
+
+	rate, err := utils.StringToFloat64(strings.TrimSpace(e.Rate))
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[swiss_national_bank_datasource.ToLatestExchangeRate] failed to parse rate, currency is %s, rate is %s", currencyCode, e.Rate)
+		return nil
+	}
+
+	if math.IsNaN(rate) || rate <= 0 {
+		log.WarnfWithRequestId(c, "[swiss_national_bank_datasource.ToLatestExchangeRate] rate is invalid, currency is %s, rate is %s", currencyCode, e.Rate)
+		return nil
+	}
+
+	finalRate := unit / rate // The rates are the amount of swiss franc per unit of foreign currency
+
+	if math.IsInf(finalRate, 0) {
+		return nil
+	}
+
+	return &models.LatestExchangeRate{
+		Currency: currencyCode,
+		Rate:     utils.Float64ToString(finalRate),
+	}
+}
+
+// GetRequestUrls returns the Swiss National Bank data source urls
+func (e *SwissNationalBankDataSource) GetRequestUrls() []string {
+	return []string{swissNationalBankExchangeRateUrl}
+}
+
+// GetPublishingInterval returns the publishing interval of Swiss National Bank data source, which publishes new exchange rates once per business day
+func (e *SwissNationalBankDataSource) GetPublishingInterval() time.Duration {
+	return dailyExchangeRatesPublishingInterval
+}
+
+// GetHistoricalRequestUrls returns the Swiss National Bank historical data source urls, which only contains the monthly exchange rates
+func (e *SwissNationalBankDataSource) GetHistoricalRequestUrls() []string {
+	return []string{swissNationalBankHistoricalExchangeRateUrl}
+}
+
+// Parse returns the common response entity according to the Swiss National Bank data source raw response
+func (e *SwissNationalBankDataSource) Parse(c *core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
+	swissNationalBankData := &SwissNationalBankExchangeRateData{}
+	err := xml.Unmarshal(content, swissNationalBankData)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[swiss_national_bank_datasource.Parse] failed to parse xml data, content is %s, because %s", string(content), err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	latestExchangeRateResponse := swissNationalBankData.ToLatestExchangeRateResponse(c)
+
+	if latestExchangeRateResponse == nil {
+		log.ErrorfWithRequestId(c, "[swiss_national_bank_datasource.Parse] failed to parse latest exchange rate data, content is %s", string(content))
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return latestExchangeRateResponse, nil
+}
+
+// ParseHistorical returns the common response entities of all months according to the Swiss National Bank historical data source raw response,
+// the month-end exchange rates would be used as the exchange rates on the last day of each month
+func (e *SwissNationalBankDataSource) ParseHistorical(c *core.Context, content []byte) ([]*models.LatestExchangeRateResponse, error) {
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.Comma = ';'
+	reader.FieldsPerRecord = -1
+
+	allLines, err := reader.ReadAll()
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[swiss_national_bank_datasource.ParseHistorical] failed to parse csv data, because %s", err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	// The data lines are after the metadata lines and the title line
+	titleLineIndex := -1
+
+	for i := 0; i < len(allLines); i++ {
+		if len(allLines[i]) > 0 && strings.TrimSpace(allLines[i][0]) == swissNationalBankHistoricalDateColumnTitle {
+			titleLineIndex = i
+			break
+		}
+	}
+
+	if titleLineIndex < 0 {
+		log.ErrorfWithRequestId(c, "[swiss_national_bank_datasource.ParseHistorical] missing title line, content is %s", string(content))
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	titleItemMap := make(map[string]int)
+
+	for i := 0; i < len(allLines[titleLineIndex]); i++ {
+		titleItemMap[strings.TrimSpace(allLines[titleLineIndex][i])] = i
+	}
+
+	typeColumnIndex, typeColumnExists := titleItemMap[swissNationalBankHistoricalTypeColumnTitle]
+	currencyColumnIndex, currencyColumnExists := titleItemMap[swissNationalBankHistoricalCurrencyColumnTitle]
+	rateColumnIndex, rateColumnExists := titleItemMap[swissNationalBankHistoricalRateColumnTitle]
+
+	if !typeColumnExists || !currencyColumnExists || !rateColumnExists {
+		log.ErrorfWithRequestId(c, "[swiss_national_bank_datasource.ParseHistorical] missing column in title line, title line is %s", strings.Join(allLines[titleLineIndex], ";"))
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	timezone, err := time.LoadLocation(swissNationalBankHistoricalDataUpdateDateTimezone)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[swiss_national_bank_datasource.ParseHistorical] failed to get timezone, timezone name is %s", swissNationalBankHistoricalDataUpdateDateTimezone)
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	months := make([]string, 0)
+	allExchangeRates := make(map[string]models.LatestExchangeRateSlice)
+
+	for i := titleLineIndex + 1; i < len(allLines); i++ {
+		items := allLines[i]
+
+		if typeColumnIndex >= len(items) || currencyColumnIndex >= len(items) || rateColumnIndex >= len(items) {
+			continue
+		}
+
+		if strings.TrimSpace(items[typeColumnIndex]) != swissNationalBankHistoricalMonthEndRateType {
+			continue
+		}
+
+		exchangeRate := e.parseHistoricalExchangeRate(c, strings.TrimSpace(items[currencyColumnIndex]), strings.TrimSpace(items[rateColumnIndex]))
+
+		if exchangeRate == nil {
+			continue
+		}
+
+		month := strings.TrimSpace(items[0])
+
+		if _, exists := allExchangeRates[month]; !exists {
+			months = append(months, month)
+		}
+
+		allExchangeRates[month] = append(allExchangeRates[month], exchangeRate)
+	}
+
+	historicalExchangeRateResponses := make([]*models.LatestExchangeRateResponse, 0, len(months))
+
+	for i := 0; i < len(months); i++ {
+		updateDateTime := months[i] + " 11:00"
+		firstDayOfMonth, err := time.ParseInLocation(swissNationalBankHistoricalDataMonthFormat, updateDateTime, timezone)
+
+		if err != nil {
+			log.ErrorfWithRequestId(c, "[swiss_national_bank_datasource.ParseHistorical] failed to parse update month, datetime is %s", updateDateTime)
+			return nil, errs.ErrFailedToRequestRemoteApi
+		}
+
+		historicalExchangeRateResponses = append(historicalExchangeRateResponses, &models.LatestExchangeRateResponse{
+			DataSource:    swissNationalBankDataSource,
+			ReferenceUrl:  swissNationalBankExchangeRateReferenceUrl,
+			UpdateTime:    firstDayOfMonth.AddDate(0, 1, -1).Unix(),
+			BaseCurrency:  swissNationalBankBaseCurrency,
+			ExchangeRates: allExchangeRates[months[i]],
+		})
+	}
+
+	if len(historicalExchangeRateResponses) < 1 {
+		log.ErrorfWithRequestId(c, "[swiss_national_bank_datasource.ParseHistorical] no valid historical exchange rate")
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return historicalExchangeRateResponses, nil
+}
+
+func (e *SwissNationalBankDataSource) parseHistoricalExchangeRate(c *core.Context, currencyAndUnit string, rate string) *models.LatestExchangeRate {
+	// The currency column contains the currency code and its unit, e.g. "JPY100"
+	if len(currencyAndUnit) <= 3 {
+		return nil
+	}
+
+	exchangeRate := &SwissNationalBankExchangeRate{
+		Rate: rate,
+		BaseCurrency: &SwissNationalBankCurrency{
+			Currency: currencyAndUnit[0:3],
+			Unit:     currencyAndUnit[3:],
+		},
+	}
+
+	if _, exists := validators.AllCurrencyNames[exchangeRate.BaseCurrency.Currency]; !exists {
+		return nil
+	}
+
+	return exchangeRate.ToLatestExchangeRate(c)
+}
@@ -0,0 +1,194 @@
+package exchangerates
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+)
+
+const swissNationalBankMinimumRequiredContent = "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n" +
+	"<rdf:RDF xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\" xmlns=\"http://purl.org/rss/1.0/\" xmlns:cb=\"http://www.cbwiki.net/wiki/index.php/Specification_1.2/\" xmlns:dc=\"http://purl.org/dc/elements/1.1/\">\n" +
+	"  <item rdf:about=\"https://www.snb.ch/en/iabout/stat/statrep/id/current_interest_exchange_rates#2024-04-17-EUR\">\n" +
+	"    <title>CH: 0.9704 CHF = 1 EUR 2024-04-17 SNB foreign exchange rates</title>\n" +
+	"    <dc:date>2024-04-17T11:00:00+02:00</dc:date>\n" +
+	"    <cb:statistics>\n" +
+	"      <cb:country>CH</cb:country>\n" +
+	"      <cb:exchangeRate>\n" +
+	"        <cb:observation>\n" +
+	"          <cb:value>0.9704</cb:value>\n" +
+	"          <cb:unit>CHF</cb:unit>\n" +
+	"          <cb:decimals>4</cb:decimals>\n" +
+	"        </cb:observation>\n" +
+	"        <cb:baseCurrency unit=\"1\">EUR</cb:baseCurrency>\n" +
+	"        <cb:targetCurrency>CHF</cb:targetCurrency>\n" +
+	"        <cb:observationPeriod frequency=\"daily\">2024-04-17</cb:observationPeriod>\n" +
+	"      </cb:exchangeRate>\n" +
+	"    </cb:statistics>\n" +
+	"  </item>\n" +
+	"  <item rdf:about=\"https://www.snb.ch/en/iabout/stat/statrep/id/current_interest_exchange_rates#2024-04-17-JPY\">\n" +
+	"    <title>CH: 0.5877 CHF = 100 JPY 2024-04-17 SNB foreign exchange rates</title>\n" +
+	"    <dc:date>2024-04-17T11:00:00+02:00</dc:date>\n" +
+	"    <cb:statistics>\n" +
+	"      <cb:country>CH</cb:country>\n" +
+	"      <cb:exchangeRate>\n" +
+	"        <cb:observation>\n" +
+	"          <cb:value>0.5877</cb:value>\n" +
+	"          <cb:unit>CHF</cb:unit>\n" +
+	"          <cb:decimals>4</cb:decimals>\n" +
+	"        </cb:observation>\n" +
+	"        <cb:baseCurrency unit=\"100\">JPY</cb:baseCurrency>\n" +
+	"        <cb:targetCurrency>CHF</cb:targetCurrency>\n" +
+	"        <cb:observationPeriod frequency=\"daily\">2024-04-17</cb:observationPeriod>\n" +
+	"      </cb:exchangeRate>\n" +
+	"    </cb:statistics>\n" +
+	"  </item>\n" +
+	"</rdf:RDF>"
+
+func TestSwissNationalBankDataSource_StandardDataExtractBaseCurrency(t *testing.T) {
+	dataSource := &SwissNationalBankDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(swissNationalBankMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "CHF", actualLatestExchangeRateResponse.BaseCurrency)
+}
+
+func TestSwissNationalBankDataSource_StandardDataExtractExchangeRates(t *testing.T) {
+	dataSource := &SwissNationalBankDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(swissNationalBankMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1713344400), actualLatestExchangeRateResponse.UpdateTime)
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "EUR",
+		Rate:     "1.030502885408079",
+	})
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "JPY",
+		Rate:     "170.15484090522375",
+	})
+}
+
+func TestSwissNationalBankDataSource_BlankContent(t *testing.T) {
+	dataSource := &SwissNationalBankDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, err := dataSource.Parse(context, []byte(""))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestSwissNationalBankDataSource_EmptyItems(t *testing.T) {
+	dataSource := &SwissNationalBankDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, err := dataSource.Parse(context, []byte("<rdf:RDF xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\" xmlns=\"http://purl.org/rss/1.0/\"></rdf:RDF>"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestSwissNationalBankDataSource_InvalidCurrency(t *testing.T) {
+	dataSource := &SwissNationalBankDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, err := dataSource.Parse(context, []byte("<rdf:RDF xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\" xmlns=\"http://purl.org/rss/1.0/\" xmlns:cb=\"http://www.cbwiki.net/wiki/index.php/Specification_1.2/\" xmlns:dc=\"http://purl.org/dc/elements/1.1/\">\n"+
+		"  <item>\n"+
+		"    <dc:date>2024-04-17T11:00:00+02:00</dc:date>\n"+
+		"    <cb:statistics><cb:exchangeRate>\n"+
+		"      <cb:observation><cb:value>1</cb:value></cb:observation>\n"+
+		"      <cb:baseCurrency unit=\"1\">XXX</cb:baseCurrency>\n"+
+		"      <cb:targetCurrency>CHF</cb:targetCurrency>\n"+
+		"      <cb:observationPeriod frequency=\"daily\">2024-04-17</cb:observationPeriod>\n"+
+		"    </cb:exchangeRate></cb:statistics>\n"+
+		"  </item>\n"+
+		"</rdf:RDF>"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestSwissNationalBankDataSource_InvalidRate(t *testing.T) {
+	dataSource := &SwissNationalBankDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, err := dataSource.Parse(context, []byte("<rdf:RDF xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\" xmlns=\"http://purl.org/rss/1.0/\" xmlns:cb=\"http://www.cbwiki.net/wiki/index.php/Specification_1.2/\" xmlns:dc=\"http://purl.org/dc/elements/1.1/\">\n"+
+		"  <item>\n"+
+		"    <dc:date>2024-04-17T11:00:00+02:00</dc:date>\n"+
+		"    <cb:statistics><cb:exchangeRate>\n"+
+		"      <cb:observation><cb:value>n/a</cb:value></cb:observation>\n"+
+		"      <cb:baseCurrency unit=\"1\">USD</cb:baseCurrency>\n"+
+		"      <cb:targetCurrency>CHF</cb:targetCurrency>\n"+
+		"      <cb:observationPeriod frequency=\"daily\">2024-04-17</cb:observationPeriod>\n"+
+		"    </cb:exchangeRate></cb:statistics>\n"+
+		"  </item>\n"+
+		"</rdf:RDF>"))
+	assert.NotEqual(t, nil, err)
+}
+
+const swissNationalBankHistoricalContent = "\"CubeId\";\"devkum\"\n" +
+	"\"PublishingDate\";\"2024-04-02 14:30\"\n" +
+	"\n" +
+	"\"Date\";\"D0\";\"D1\";\"Value\"\n" +
+	"\"2024-02\";\"M0\";\"EUR1\";\"0.9436\"\n" +
+	"\"2024-02\";\"M1\";\"EUR1\";\"0.9545\"\n" +
+	"\"2024-02\";\"M1\";\"JPY100\";\"0.5873\"\n" +
+	"\"2024-03\";\"M1\";\"EUR1\";\"0.9766\"\n" +
+	"\"2024-03\";\"M1\";\"XYZ1\";\"1.2345\"\n"
+
+func TestSwissNationalBankDataSource_HistoricalDataExtractAllMonths(t *testing.T) {
+	dataSource := &SwissNationalBankDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualHistoricalExchangeRateResponses, err := dataSource.ParseHistorical(context, []byte(swissNationalBankHistoricalContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(actualHistoricalExchangeRateResponses))
+
+	assert.Equal(t, int64(1709200800), actualHistoricalExchangeRateResponses[0].UpdateTime)
+	assert.Equal(t, 2, len(actualHistoricalExchangeRateResponses[0].ExchangeRates))
+	assert.Contains(t, actualHistoricalExchangeRateResponses[0].ExchangeRates, &models.LatestExchangeRate{
+		Currency: "JPY",
+		Rate:     "170.27073046143366",
+	})
+
+	assert.Equal(t, int64(1711875600), actualHistoricalExchangeRateResponses[1].UpdateTime)
+	assert.Equal(t, 1, len(actualHistoricalExchangeRateResponses[1].ExchangeRates))
+	assert.Contains(t, actualHistoricalExchangeRateResponses[1].ExchangeRates, &models.LatestExchangeRate{
+		Currency: "EUR",
+		Rate:     "1.0239606799098915",
+	})
+}
+
+func TestSwissNationalBankDataSource_HistoricalMissingTitleLine(t *testing.T) {
+	dataSource := &SwissNationalBankDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, err := dataSource.ParseHistorical(context, []byte("\"CubeId\";\"devkum\"\n"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestSwissNationalBankDataSource_HistoricalBlankContent(t *testing.T) {
+	dataSource := &SwissNationalBankDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	_, err := dataSource.ParseHistorical(context, []byte(""))
+	assert.NotEqual(t, nil, err)
+}
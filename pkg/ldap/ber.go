@@ -0,0 +1,226 @@
+package ldap
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+)
+
+// Classes and forms of BER identifier octet
+const (
+	berClassUniversal   byte = 0x00
+	berClassApplication byte = 0x40
+	berClassContext     byte = 0x80
+	berConstructed      byte = 0x20
+)
+
+// Universal tags which are used by LDAP
+const (
+	berTagBoolean     byte = 0x01
+	berTagInteger     byte = 0x02
+	berTagOctetString byte = 0x04
+	berTagEnumerated  byte = 0x0a
+	berTagSequence    byte = 0x10
+	berTagSet         byte = 0x11
+)
+
+const berMaxPacketLength = 16 * 1024 * 1024
+
+var errBerPacketInvalid = errors.New("ber packet is invalid")
+
+// berPacket represents a decoded BER element, the children are only parsed for constructed element
+type berPacket struct {
+	identifier byte
+	value      []byte
+	children   []*berPacket
+}
+
+func (p *berPacket) tagNumber() byte {
+	return p.identifier & 0x1f
+}
+
+func (p *berPacket) isConstructed() bool {
+	return p.identifier&berConstructed == berConstructed
+}
+
+func (p *berPacket) int64Value() (int64, error) {
+	if len(p.value) < 1 || len(p.value) > 8 {
+		return 0, errBerPacketInvalid
+	}
+
+	value := int64(int8(p.value[0]))
+
+	for i := 1; i < len(p.value); i++ {
+		value = value<<8 | int64(p.value[i])
+	}
+
+	return value, nil
+}
+
+func (p *berPacket) stringValue() string {
+	return string(p.value)
+}
+
+func encodeBerElement(identifier byte, content []byte) []byte {
+	length := len(content)
+	result := make([]byte, 0, length+6)
+	result = append(result, identifier)
+
+	if length < 0x80 {
+		result = append(result, byte(length))
+	} else {
+		lengthBytes := make([]byte, 0, 4)
+
+		for l := length; l > 0; l >>= 8 {
+			lengthBytes = append([]byte{byte(l)}, lengthBytes...)
+		}
+
+		result = append(result, 0x80|byte(len(lengthBytes)))
+		result = append(result, lengthBytes...)
+	}
+
+	return append(result, content...)
+}
+
+func encodeBerConstructed(identifier byte, children ...[]byte) []byte {
+	content := make([]byte, 0)
+
+	for i := 0; i < len(children); i++ {
+		content = append(content, children[i]...)
+	}
+
+	return encodeBerElement(identifier|berConstructed, content)
+}
+
+func encodeBerSequence(children ...[]byte) []byte {
+	return encodeBerConstructed(berClassUniversal|berTagSequence, children...)
+}
+
+func encodeBerOctetString(value string) []byte {
+	return encodeBerElement(berClassUniversal|berTagOctetString, []byte(value))
+}
+
+func encodeBerBoolean(value bool) []byte {
+	if value {
+		return encodeBerElement(berClassUniversal|berTagBoolean, []byte{0xff})
+	}
+
+	return encodeBerElement(berClassUniversal|berTagBoolean, []byte{0x00})
+}
+
+func encodeBerInteger(value int64) []byte {
+	return encodeBerElement(berClassUniversal|berTagInteger, encodeBerIntegerContent(value))
+}
+
+func encodeBerEnumerated(value int64) []byte {
+	return encodeBerElement(berClassUniversal|berTagEnumerated, encodeBerIntegerContent(value))
+}
+
+func encodeBerIntegerContent(value int64) []byte {
+	content := []byte{byte(value)}
+
+	// two's complement in minimum octets
+	for value > 0x7f || value < -0x80 {
+		value >>= 8
+		content = append([]byte{byte(value)}, content...)
+	}
+
+	return content
+}
+
+func readBerPacket(reader *bufio.Reader) (*berPacket, error) {
+	identifier, err := reader.ReadByte()
+
+	if err != nil {
+		return nil, err
+	}
+
+	packet, err := readBerPacketAfterIdentifier(reader, identifier)
+
+	if err == io.EOF {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	return packet, err
+}
+
+func readBerPacketAfterIdentifier(reader *bufio.Reader, identifier byte) (*berPacket, error) {
+	if identifier&0x1f == 0x1f {
+		// LDAP does not use tag numbers which are larger than 30
+		return nil, errBerPacketInvalid
+	}
+
+	firstLengthByte, err := reader.ReadByte()
+
+	if err != nil {
+		return nil, err
+	}
+
+	length := int(firstLengthByte)
+
+	if firstLengthByte&0x80 == 0x80 {
+		lengthBytesCount := int(firstLengthByte & 0x7f)
+
+		if lengthBytesCount < 1 || lengthBytesCount > 4 {
+			return nil, errBerPacketInvalid
+		}
+
+		length = 0
+
+		for i := 0; i < lengthBytesCount; i++ {
+			b, err := reader.ReadByte()
+
+			if err != nil {
+				return nil, err
+			}
+
+			length = length<<8 | int(b)
+		}
+	}
+
+	if length > berMaxPacketLength {
+		return nil, errBerPacketInvalid
+	}
+
+	value := make([]byte, length)
+	_, err = io.ReadFull(reader, value)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return parseBerPacket(identifier, value)
+}
+
+func parseBerPacket(identifier byte, value []byte) (*berPacket, error) {
+	packet := &berPacket{
+		identifier: identifier,
+		value:      value,
+	}
+
+	if !packet.isConstructed() {
+		return packet, nil
+	}
+
+	packet.children = make([]*berPacket, 0)
+	reader := bufio.NewReader(bytes.NewReader(value))
+
+	for {
+		child, err := readBerPacket(reader)
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			if err == io.ErrUnexpectedEOF {
+				return nil, errBerPacketInvalid
+			}
+
+			return nil, err
+		}
+
+		packet.children = append(packet.children, child)
+	}
+
+	return packet, nil
+}
@@ -0,0 +1,413 @@
+package ldap
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Application tags of LDAP protocol operations
+const (
+	opTagBindRequest           byte = 0
+	opTagBindResponse          byte = 1
+	opTagUnbindRequest         byte = 2
+	opTagSearchRequest         byte = 3
+	opTagSearchResultEntry     byte = 4
+	opTagSearchResultDone      byte = 5
+	opTagSearchResultReference byte = 19
+	opTagExtendedRequest       byte = 23
+	opTagExtendedResponse      byte = 24
+)
+
+// Result codes of LDAP operations
+const (
+	ResultCodeSuccess            = 0
+	ResultCodeSizeLimitExceeded  = 4
+	ResultCodeInvalidCredentials = 49
+)
+
+// Search scopes
+const (
+	ScopeBaseObject   = 0
+	ScopeSingleLevel  = 1
+	ScopeWholeSubtree = 2
+)
+
+const (
+	ldapProtocolVersion = 3
+	startTLSOID         = "1.3.6.1.4.1.1466.20037"
+)
+
+// Error represents the error result which is returned by LDAP server
+type Error struct {
+	ResultCode        int64
+	DiagnosticMessage string
+}
+
+// Error returns the error message
+func (e *Error) Error() string {
+	return fmt.Sprintf("ldap result code %d: %s", e.ResultCode, e.DiagnosticMessage)
+}
+
+// IsResultCode returns whether the error is returned by LDAP server with the specified result code
+func IsResultCode(err error, resultCode int64) bool {
+	var ldapErr *Error
+	return errors.As(err, &ldapErr) && ldapErr.ResultCode == resultCode
+}
+
+// Entry represents an entry which is returned by search operation
+type Entry struct {
+	DN         string
+	Attributes map[string][]string
+}
+
+// GetAttributeValue returns the first value of the attribute, the attribute name is case-insensitive
+func (e *Entry) GetAttributeValue(name string) string {
+	values := e.GetAttributeValues(name)
+
+	if len(values) < 1 {
+		return ""
+	}
+
+	return values[0]
+}
+
+// GetAttributeValues returns all values of the attribute, the attribute name is case-insensitive
+func (e *Entry) GetAttributeValues(name string) []string {
+	for attributeName, values := range e.Attributes {
+		if strings.EqualFold(attributeName, name) {
+			return values
+		}
+	}
+
+	return nil
+}
+
+// SearchRequest represents all parameters of search operation
+type SearchRequest struct {
+	BaseDN     string
+	Scope      int64
+	Filter     string
+	Attributes []string
+	SizeLimit  int64
+}
+
+// Conn represents a connection to LDAP server, the operations on the same connection must not be called concurrently
+type Conn struct {
+	conn      net.Conn
+	reader    *bufio.Reader
+	messageId int64
+	timeout   time.Duration
+}
+
+// Dial connects to the LDAP server of the url which starts with "ldap://" or "ldaps://",
+// and upgrades the connection by StartTLS if required
+func Dial(serverUrl string, startTLS bool, tlsConfig *tls.Config, timeout time.Duration) (*Conn, error) {
+	parsedUrl, err := url.Parse(serverUrl)
+
+	if err != nil {
+		return nil, err
+	}
+
+	host := parsedUrl.Host
+	dialer := &net.Dialer{Timeout: timeout}
+	var conn net.Conn
+
+	switch strings.ToLower(parsedUrl.Scheme) {
+	case "ldap":
+		if parsedUrl.Port() == "" {
+			host = net.JoinHostPort(host, "389")
+		}
+
+		conn, err = dialer.Dial("tcp", host)
+	case "ldaps":
+		if parsedUrl.Port() == "" {
+			host = net.JoinHostPort(host, "636")
+		}
+
+		conn, err = tls.DialWithDialer(dialer, "tcp", host, getTLSConfig(tlsConfig, parsedUrl.Hostname()))
+	default:
+		return nil, fmt.Errorf("ldap url scheme \"%s\" is not supported", parsedUrl.Scheme)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	c := newConn(conn, timeout)
+
+	if startTLS && strings.ToLower(parsedUrl.Scheme) == "ldap" {
+		err = c.startTLS(getTLSConfig(tlsConfig, parsedUrl.Hostname()))
+
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+func newConn(conn net.Conn, timeout time.Duration) *Conn {
+	return &Conn{
+		conn:    conn,
+		reader:  bufio.NewReader(conn),
+		timeout: timeout,
+	}
+}
+
+// Bind authenticates to the server by simple authentication, the empty password is rejected
+// as the server would regard it as an unauthenticated bind and return success
+func (c *Conn) Bind(dn string, password string) error {
+	if password == "" {
+		return &Error{ResultCode: ResultCodeInvalidCredentials, DiagnosticMessage: "empty password is not allowed"}
+	}
+
+	request := encodeBerConstructed(berClassApplication|opTagBindRequest,
+		encodeBerInteger(ldapProtocolVersion),
+		encodeBerOctetString(dn),
+		encodeBerElement(berClassContext|0, []byte(password)),
+	)
+
+	response, err := c.doRequest(request, opTagBindResponse)
+
+	if err != nil {
+		return err
+	}
+
+	return getResultError(response)
+}
+
+// Search returns all entries which match the search request
+func (c *Conn) Search(searchRequest *SearchRequest) ([]*Entry, error) {
+	filter, err := compileFilter(searchRequest.Filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := make([][]byte, len(searchRequest.Attributes))
+
+	for i := 0; i < len(searchRequest.Attributes); i++ {
+		attributes[i] = encodeBerOctetString(searchRequest.Attributes[i])
+	}
+
+	request := encodeBerConstructed(berClassApplication|opTagSearchRequest,
+		encodeBerOctetString(searchRequest.BaseDN),
+		encodeBerEnumerated(searchRequest.Scope),
+		encodeBerEnumerated(0), // never dereference aliases
+		encodeBerInteger(searchRequest.SizeLimit),
+		encodeBerInteger(int64(c.timeout/time.Second)),
+		encodeBerBoolean(false),
+		filter,
+		encodeBerSequence(attributes...),
+	)
+
+	messageId, err := c.sendRequest(request)
+
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*Entry, 0)
+
+	for {
+		response, err := c.readResponse(messageId)
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch response.tagNumber() {
+		case opTagSearchResultEntry:
+			entry, err := parseSearchResultEntry(response)
+
+			if err != nil {
+				return nil, err
+			}
+
+			entries = append(entries, entry)
+		case opTagSearchResultReference:
+			continue
+		case opTagSearchResultDone:
+			err = getResultError(response)
+
+			if err != nil {
+				return entries, err
+			}
+
+			return entries, nil
+		default:
+			return nil, errBerPacketInvalid
+		}
+	}
+}
+
+// Close sends unbind request and closes the connection
+func (c *Conn) Close() error {
+	_, _ = c.sendRequest(encodeBerElement(berClassApplication|opTagUnbindRequest, nil))
+	return c.conn.Close()
+}
+
+func (c *Conn) startTLS(tlsConfig *tls.Config) error {
+	request := encodeBerConstructed(berClassApplication|opTagExtendedRequest,
+		encodeBerElement(berClassContext|0, []byte(startTLSOID)),
+	)
+
+	response, err := c.doRequest(request, opTagExtendedResponse)
+
+	if err != nil {
+		return err
+	}
+
+	err = getResultError(response)
+
+	if err != nil {
+		return err
+	}
+
+	tlsConn := tls.Client(c.conn, tlsConfig)
+	c.conn = tlsConn
+	c.reader = bufio.NewReader(tlsConn)
+
+	return nil
+}
+
+func (c *Conn) doRequest(request []byte, expectedResponseTag byte) (*berPacket, error) {
+	messageId, err := c.sendRequest(request)
+
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.readResponse(messageId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if response.tagNumber() != expectedResponseTag {
+		return nil, errBerPacketInvalid
+	}
+
+	return response, nil
+}
+
+func (c *Conn) sendRequest(protocolOp []byte) (int64, error) {
+	c.messageId++
+	message := encodeBerSequence(encodeBerInteger(c.messageId), protocolOp)
+
+	if c.timeout > 0 {
+		_ = c.conn.SetDeadline(time.Now().Add(c.timeout))
+	}
+
+	_, err := c.conn.Write(message)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return c.messageId, nil
+}
+
+func (c *Conn) readResponse(expectedMessageId int64) (*berPacket, error) {
+	message, err := readBerPacket(c.reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if message.identifier != berClassUniversal|berConstructed|berTagSequence || len(message.children) < 2 {
+		return nil, errBerPacketInvalid
+	}
+
+	messageId, err := message.children[0].int64Value()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if messageId != expectedMessageId {
+		// message id 0 is used by notice of disconnection
+		return nil, fmt.Errorf("unexpected ldap message id %d", messageId)
+	}
+
+	response := message.children[1]
+
+	if response.identifier&0xc0 != berClassApplication {
+		return nil, errBerPacketInvalid
+	}
+
+	return response, nil
+}
+
+func getResultError(response *berPacket) error {
+	if len(response.children) < 3 {
+		return errBerPacketInvalid
+	}
+
+	resultCode, err := response.children[0].int64Value()
+
+	if err != nil {
+		return err
+	}
+
+	if resultCode == ResultCodeSuccess {
+		return nil
+	}
+
+	return &Error{
+		ResultCode:        resultCode,
+		DiagnosticMessage: response.children[2].stringValue(),
+	}
+}
+
+func parseSearchResultEntry(response *berPacket) (*Entry, error) {
+	if len(response.children) < 2 {
+		return nil, errBerPacketInvalid
+	}
+
+	entry := &Entry{
+		DN:         response.children[0].stringValue(),
+		Attributes: make(map[string][]string),
+	}
+
+	attributes := response.children[1].children
+
+	for i := 0; i < len(attributes); i++ {
+		attribute := attributes[i]
+
+		if len(attribute.children) < 2 {
+			return nil, errBerPacketInvalid
+		}
+
+		values := make([]string, 0, len(attribute.children[1].children))
+
+		for j := 0; j < len(attribute.children[1].children); j++ {
+			values = append(values, attribute.children[1].children[j].stringValue())
+		}
+
+		entry.Attributes[attribute.children[0].stringValue()] = values
+	}
+
+	return entry, nil
+}
+
+func getTLSConfig(tlsConfig *tls.Config, serverName string) *tls.Config {
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{}
+	} else {
+		tlsConfig = tlsConfig.Clone()
+	}
+
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = serverName
+	}
+
+	return tlsConfig
+}
@@ -0,0 +1,281 @@
+package ldap
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockLDAPEntry struct {
+	dn         string
+	password   string
+	attributes map[string][]string
+}
+
+// mockLDAPServer is a local LDAP server which supports simple bind and search with equality filter of one attribute
+type mockLDAPServer struct {
+	listener net.Listener
+	entries  []*mockLDAPEntry
+}
+
+func newMockLDAPServer(t *testing.T, entries []*mockLDAPEntry) *mockLDAPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+
+	server := &mockLDAPServer{
+		listener: listener,
+		entries:  entries,
+	}
+
+	go server.serve()
+	t.Cleanup(func() {
+		listener.Close()
+	})
+
+	return server
+}
+
+func (s *mockLDAPServer) url() string {
+	return "ldap://" + s.listener.Addr().String()
+}
+
+func (s *mockLDAPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+
+		if err != nil {
+			return
+		}
+
+		go s.handleConn(conn)
+	}
+}
+
+func (s *mockLDAPServer) handleConn(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	for {
+		message, err := readBerPacket(reader)
+
+		if err != nil {
+			return
+		}
+
+		messageId, _ := message.children[0].int64Value()
+		request := message.children[1]
+
+		switch request.tagNumber() {
+		case opTagBindRequest:
+			dn := request.children[1].stringValue()
+			password := request.children[2].stringValue()
+			resultCode := int64(ResultCodeInvalidCredentials)
+
+			for i := 0; i < len(s.entries); i++ {
+				if s.entries[i].dn == dn && s.entries[i].password == password {
+					resultCode = ResultCodeSuccess
+				}
+			}
+
+			s.writeResponse(conn, messageId, encodeLDAPResult(opTagBindResponse, resultCode))
+		case opTagSearchRequest:
+			baseDN := request.children[0].stringValue()
+			filter := request.children[6]
+
+			for i := 0; i < len(s.entries); i++ {
+				if s.matchEntry(s.entries[i], baseDN, filter) {
+					s.writeResponse(conn, messageId, encodeSearchResultEntry(s.entries[i]))
+				}
+			}
+
+			s.writeResponse(conn, messageId, encodeLDAPResult(opTagSearchResultDone, ResultCodeSuccess))
+		case opTagUnbindRequest:
+			return
+		}
+	}
+}
+
+func (s *mockLDAPServer) matchEntry(entry *mockLDAPEntry, baseDN string, filter *berPacket) bool {
+	if !bytes.HasSuffix([]byte(entry.dn), []byte(baseDN)) {
+		return false
+	}
+
+	if filter.tagNumber() == filterTagPresent {
+		return true
+	}
+
+	if filter.tagNumber() != filterTagEqualityMatch {
+		return false
+	}
+
+	attributeName := filter.children[0].stringValue()
+	attributeValue := filter.children[1].stringValue()
+	values := entry.attributes[attributeName]
+
+	for i := 0; i < len(values); i++ {
+		if values[i] == attributeValue {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *mockLDAPServer) writeResponse(conn net.Conn, messageId int64, response []byte) {
+	_, _ = conn.Write(encodeBerSequence(encodeBerInteger(messageId), response))
+}
+
+func encodeLDAPResult(tag byte, resultCode int64) []byte {
+	return encodeBerConstructed(berClassApplication|tag,
+		encodeBerEnumerated(resultCode),
+		encodeBerOctetString(""),
+		encodeBerOctetString(""),
+	)
+}
+
+func encodeSearchResultEntry(entry *mockLDAPEntry) []byte {
+	attributes := make([][]byte, 0, len(entry.attributes))
+
+	for name, values := range entry.attributes {
+		encodedValues := make([][]byte, len(values))
+
+		for i := 0; i < len(values); i++ {
+			encodedValues[i] = encodeBerOctetString(values[i])
+		}
+
+		attributes = append(attributes, encodeBerSequence(
+			encodeBerOctetString(name),
+			encodeBerConstructed(berClassUniversal|berTagSet, encodedValues...),
+		))
+	}
+
+	return encodeBerConstructed(berClassApplication|opTagSearchResultEntry,
+		encodeBerOctetString(entry.dn),
+		encodeBerSequence(attributes...),
+	)
+}
+
+var mockLDAPEntries = []*mockLDAPEntry{
+	{
+		dn:       "cn=admin,dc=example,dc=com",
+		password: "admin-password",
+	},
+	{
+		dn:       "uid=user1,ou=people,dc=example,dc=com",
+		password: "user1-password",
+		attributes: map[string][]string{
+			"uid":  {"user1"},
+			"mail": {"user1@example.com"},
+			"cn":   {"User 1"},
+		},
+	},
+}
+
+func TestConn_BindAndSearch(t *testing.T) {
+	server := newMockLDAPServer(t, mockLDAPEntries)
+
+	conn, err := Dial(server.url(), false, nil, 5*time.Second)
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	err = conn.Bind("cn=admin,dc=example,dc=com", "admin-password")
+	assert.Nil(t, err)
+
+	entries, err := conn.Search(&SearchRequest{
+		BaseDN:     "dc=example,dc=com",
+		Scope:      ScopeWholeSubtree,
+		Filter:     "(uid=" + EscapeFilterValue("user1") + ")",
+		Attributes: []string{"uid", "mail", "cn"},
+		SizeLimit:  2,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(entries))
+	assert.Equal(t, "uid=user1,ou=people,dc=example,dc=com", entries[0].DN)
+	assert.Equal(t, "user1@example.com", entries[0].GetAttributeValue("MAIL"))
+	assert.Equal(t, "User 1", entries[0].GetAttributeValue("cn"))
+	assert.Equal(t, "", entries[0].GetAttributeValue("sn"))
+
+	entries, err = conn.Search(&SearchRequest{
+		BaseDN: "dc=example,dc=com",
+		Scope:  ScopeWholeSubtree,
+		Filter: "(uid=user2)",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(entries))
+
+	err = conn.Bind("uid=user1,ou=people,dc=example,dc=com", "user1-password")
+	assert.Nil(t, err)
+}
+
+func TestConn_BindWithWrongPassword(t *testing.T) {
+	server := newMockLDAPServer(t, mockLDAPEntries)
+
+	conn, err := Dial(server.url(), false, nil, 5*time.Second)
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	err = conn.Bind("uid=user1,ou=people,dc=example,dc=com", "wrong-password")
+	assert.True(t, IsResultCode(err, ResultCodeInvalidCredentials))
+}
+
+func TestConn_BindWithEmptyPassword(t *testing.T) {
+	server := newMockLDAPServer(t, mockLDAPEntries)
+
+	conn, err := Dial(server.url(), false, nil, 5*time.Second)
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	err = conn.Bind("uid=user1,ou=people,dc=example,dc=com", "")
+	assert.True(t, IsResultCode(err, ResultCodeInvalidCredentials))
+}
+
+func TestDial_UnsupportedScheme(t *testing.T) {
+	_, err := Dial("http://127.0.0.1:389", false, nil, time.Second)
+	assert.NotNil(t, err)
+}
+
+func TestEncodeBindRequest(t *testing.T) {
+	request := encodeBerSequence(encodeBerInteger(1), encodeBerConstructed(berClassApplication|opTagBindRequest,
+		encodeBerInteger(ldapProtocolVersion),
+		encodeBerOctetString("cn=admin"),
+		encodeBerElement(berClassContext|0, []byte("pw")),
+	))
+
+	expected := []byte{0x30, 0x16, 0x02, 0x01, 0x01, 0x60, 0x11, 0x02, 0x01, 0x03, 0x04, 0x08}
+	expected = append(expected, []byte("cn=admin")...)
+	expected = append(expected, 0x80, 0x02, 'p', 'w')
+
+	assert.Equal(t, expected, request)
+}
+
+func TestEncodeBerInteger(t *testing.T) {
+	assert.Equal(t, []byte{0x02, 0x01, 0x00}, encodeBerInteger(0))
+	assert.Equal(t, []byte{0x02, 0x01, 0x7f}, encodeBerInteger(127))
+	assert.Equal(t, []byte{0x02, 0x02, 0x00, 0x80}, encodeBerInteger(128))
+	assert.Equal(t, []byte{0x02, 0x02, 0x01, 0x00}, encodeBerInteger(256))
+	assert.Equal(t, []byte{0x02, 0x01, 0xff}, encodeBerInteger(-1))
+	assert.Equal(t, []byte{0x02, 0x02, 0xff, 0x7f}, encodeBerInteger(-129))
+}
+
+func TestEncodeBerElement_LongLength(t *testing.T) {
+	content := make([]byte, 300)
+	element := encodeBerElement(berClassUniversal|berTagOctetString, content)
+	assert.Equal(t, []byte{0x04, 0x82, 0x01, 0x2c}, element[:4])
+	assert.Equal(t, 304, len(element))
+
+	packet, err := readBerPacket(bufio.NewReader(bytes.NewReader(element)))
+	assert.Nil(t, err)
+	assert.Equal(t, 300, len(packet.value))
+}
+
+func TestReadBerPacket_Truncated(t *testing.T) {
+	_, err := readBerPacket(bufio.NewReader(bytes.NewReader([]byte{0x30, 0x05, 0x02, 0x01})))
+	assert.NotNil(t, err)
+
+	_, err = readBerPacket(bufio.NewReader(bytes.NewReader([]byte{0x30, 0x03, 0x02, 0x05, 0x01})))
+	assert.Equal(t, errBerPacketInvalid, err)
+}
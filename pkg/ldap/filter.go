@@ -0,0 +1,261 @@
+package ldap
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Context-specific tags of search filter choices
+const (
+	filterTagAnd            byte = 0
+	filterTagOr             byte = 1
+	filterTagNot            byte = 2
+	filterTagEqualityMatch  byte = 3
+	filterTagSubstrings     byte = 4
+	filterTagGreaterOrEqual byte = 5
+	filterTagLessOrEqual    byte = 6
+	filterTagPresent        byte = 7
+	filterTagApproxMatch    byte = 8
+)
+
+// Context-specific tags of substring filter choices
+const (
+	substringTagInitial byte = 0
+	substringTagAny     byte = 1
+	substringTagFinal   byte = 2
+)
+
+var errFilterInvalid = errors.New("ldap filter is invalid")
+
+// EscapeFilterValue returns the escaped value which can be used in assertion value of search filter safely
+func EscapeFilterValue(value string) string {
+	var builder strings.Builder
+
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+
+		if c == '*' || c == '(' || c == ')' || c == '\\' || c == 0 {
+			builder.WriteString(fmt.Sprintf("\\%02x", c))
+		} else {
+			builder.WriteByte(c)
+		}
+	}
+
+	return builder.String()
+}
+
+// compileFilter returns the BER encoded search filter according to the string representation in RFC 4515,
+// extensible match filter is not supported
+func compileFilter(filter string) ([]byte, error) {
+	filter = strings.TrimSpace(filter)
+
+	if filter == "" {
+		return nil, errFilterInvalid
+	}
+
+	// the outermost parentheses can be omitted
+	if filter[0] != '(' {
+		filter = "(" + filter + ")"
+	}
+
+	result, pos, err := compileFilterAt(filter, 0)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if pos != len(filter) {
+		return nil, errFilterInvalid
+	}
+
+	return result, nil
+}
+
+func compileFilterAt(filter string, pos int) ([]byte, int, error) {
+	if pos >= len(filter) || filter[pos] != '(' {
+		return nil, pos, errFilterInvalid
+	}
+
+	pos++
+
+	if pos >= len(filter) {
+		return nil, pos, errFilterInvalid
+	}
+
+	switch filter[pos] {
+	case '&', '|':
+		tag := filterTagAnd
+
+		if filter[pos] == '|' {
+			tag = filterTagOr
+		}
+
+		pos++
+		children := make([][]byte, 0)
+
+		for pos < len(filter) && filter[pos] == '(' {
+			child, nextPos, err := compileFilterAt(filter, pos)
+
+			if err != nil {
+				return nil, nextPos, err
+			}
+
+			children = append(children, child)
+			pos = nextPos
+		}
+
+		if len(children) < 1 || pos >= len(filter) || filter[pos] != ')' {
+			return nil, pos, errFilterInvalid
+		}
+
+		return encodeBerConstructed(berClassContext|tag, children...), pos + 1, nil
+	case '!':
+		child, nextPos, err := compileFilterAt(filter, pos+1)
+
+		if err != nil {
+			return nil, nextPos, err
+		}
+
+		if nextPos >= len(filter) || filter[nextPos] != ')' {
+			return nil, nextPos, errFilterInvalid
+		}
+
+		return encodeBerConstructed(berClassContext|filterTagNot, child), nextPos + 1, nil
+	default:
+		end := strings.IndexByte(filter[pos:], ')')
+
+		if end < 0 {
+			return nil, pos, errFilterInvalid
+		}
+
+		item, err := compileFilterItem(filter[pos : pos+end])
+
+		if err != nil {
+			return nil, pos, err
+		}
+
+		return item, pos + end + 1, nil
+	}
+}
+
+func compileFilterItem(item string) ([]byte, error) {
+	equalIndex := strings.IndexByte(item, '=')
+
+	if equalIndex < 1 {
+		return nil, errFilterInvalid
+	}
+
+	attribute := item[:equalIndex]
+	value := item[equalIndex+1:]
+	tag := filterTagEqualityMatch
+
+	switch attribute[len(attribute)-1] {
+	case '>':
+		tag = filterTagGreaterOrEqual
+		attribute = attribute[:len(attribute)-1]
+	case '<':
+		tag = filterTagLessOrEqual
+		attribute = attribute[:len(attribute)-1]
+	case '~':
+		tag = filterTagApproxMatch
+		attribute = attribute[:len(attribute)-1]
+	case ':':
+		return nil, errors.New("extensible match filter is not supported")
+	}
+
+	if attribute == "" || strings.ContainsAny(attribute, "()*\\ ") {
+		return nil, errFilterInvalid
+	}
+
+	if tag == filterTagEqualityMatch && value == "*" {
+		return encodeBerElement(berClassContext|filterTagPresent, []byte(attribute)), nil
+	}
+
+	if tag == filterTagEqualityMatch && strings.Contains(value, "*") {
+		return compileSubstringsFilter(attribute, value)
+	}
+
+	unescapedValue, err := unescapeFilterValue(value)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return encodeBerConstructed(berClassContext|tag, encodeBerOctetString(attribute), encodeBerOctetString(unescapedValue)), nil
+}
+
+func compileSubstringsFilter(attribute string, value string) ([]byte, error) {
+	parts := strings.Split(value, "*")
+	substrings := make([][]byte, 0, len(parts))
+
+	for i := 0; i < len(parts); i++ {
+		if parts[i] == "" {
+			if i > 0 && i < len(parts)-1 {
+				// two adjacent asterisks
+				return nil, errFilterInvalid
+			}
+
+			continue
+		}
+
+		unescapedPart, err := unescapeFilterValue(parts[i])
+
+		if err != nil {
+			return nil, err
+		}
+
+		tag := substringTagAny
+
+		if i == 0 {
+			tag = substringTagInitial
+		} else if i == len(parts)-1 {
+			tag = substringTagFinal
+		}
+
+		substrings = append(substrings, encodeBerElement(berClassContext|tag, []byte(unescapedPart)))
+	}
+
+	return encodeBerConstructed(berClassContext|filterTagSubstrings, encodeBerOctetString(attribute), encodeBerSequence(substrings...)), nil
+}
+
+func unescapeFilterValue(value string) (string, error) {
+	if !strings.Contains(value, "\\") {
+		if strings.ContainsAny(value, "()") {
+			return "", errFilterInvalid
+		}
+
+		return value, nil
+	}
+
+	var builder strings.Builder
+
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+
+		if c == '(' || c == ')' {
+			return "", errFilterInvalid
+		}
+
+		if c != '\\' {
+			builder.WriteByte(c)
+			continue
+		}
+
+		if i+3 > len(value) {
+			return "", errFilterInvalid
+		}
+
+		decoded, err := hex.DecodeString(value[i+1 : i+3])
+
+		if err != nil {
+			return "", errFilterInvalid
+		}
+
+		builder.Write(decoded)
+		i += 2
+	}
+
+	return builder.String(), nil
+}
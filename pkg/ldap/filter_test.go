@@ -0,0 +1,100 @@
+package ldap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompileFilter_And(t *testing.T) {
+	actualFilter, err := compileFilter("(&(objectClass=person)(uid=john))")
+	assert.Nil(t, err)
+
+	expectedFilter := []byte{0xa0, 0x24, 0xa3, 0x15, 0x04, 0x0b}
+	expectedFilter = append(expectedFilter, []byte("objectClass")...)
+	expectedFilter = append(expectedFilter, 0x04, 0x06)
+	expectedFilter = append(expectedFilter, []byte("person")...)
+	expectedFilter = append(expectedFilter, 0xa3, 0x0b, 0x04, 0x03)
+	expectedFilter = append(expectedFilter, []byte("uid")...)
+	expectedFilter = append(expectedFilter, 0x04, 0x04)
+	expectedFilter = append(expectedFilter, []byte("john")...)
+
+	assert.Equal(t, expectedFilter, actualFilter)
+}
+
+func TestCompileFilter_WithoutOutermostParentheses(t *testing.T) {
+	actualFilter, err := compileFilter("uid=john")
+	assert.Nil(t, err)
+
+	expectedFilter, err := compileFilter("(uid=john)")
+	assert.Nil(t, err)
+	assert.Equal(t, expectedFilter, actualFilter)
+}
+
+func TestCompileFilter_Present(t *testing.T) {
+	actualFilter, err := compileFilter("(objectClass=*)")
+	assert.Nil(t, err)
+	assert.Equal(t, append([]byte{0x87, 0x0b}, []byte("objectClass")...), actualFilter)
+}
+
+func TestCompileFilter_Substrings(t *testing.T) {
+	actualFilter, err := compileFilter("(cn=jo*n*e)")
+	assert.Nil(t, err)
+
+	expectedFilter := []byte{0xa4, 0x10, 0x04, 0x02, 'c', 'n', 0x30, 0x0a, 0x80, 0x02, 'j', 'o', 0x81, 0x01, 'n', 0x82, 0x01, 'e'}
+	assert.Equal(t, expectedFilter, actualFilter)
+
+	actualFilter, err = compileFilter("(cn=*john)")
+	assert.Nil(t, err)
+
+	expectedFilter = []byte{0xa4, 0x0c, 0x04, 0x02, 'c', 'n', 0x30, 0x06, 0x82, 0x04, 'j', 'o', 'h', 'n'}
+	assert.Equal(t, expectedFilter, actualFilter)
+}
+
+func TestCompileFilter_NotAndOr(t *testing.T) {
+	actualFilter, err := compileFilter("(|(!(uid=a))(uid>=b)(uid<=c)(uid~=d))")
+	assert.Nil(t, err)
+	assert.Equal(t, byte(0xa1), actualFilter[0])
+	assert.Equal(t, byte(0xa2), actualFilter[2])
+	assert.Equal(t, byte(0xa3), actualFilter[4])
+}
+
+func TestCompileFilter_EscapedValue(t *testing.T) {
+	actualFilter, err := compileFilter("(cn=a\\2ab\\28\\29)")
+	assert.Nil(t, err)
+
+	expectedFilter := []byte{0xa3, 0x0b, 0x04, 0x02, 'c', 'n', 0x04, 0x05, 'a', '*', 'b', '(', ')'}
+	assert.Equal(t, expectedFilter, actualFilter)
+}
+
+func TestCompileFilter_InvalidFilter(t *testing.T) {
+	invalidFilters := []string{
+		"",
+		"(",
+		"()",
+		"(uid=john",
+		"(uid=john))",
+		"(&)",
+		"(!(uid=john)",
+		"(=john)",
+		"(uid=jo(hn)",
+		"(uid=john\\2)",
+		"(uid=john\\zz)",
+		"(cn=a**b)",
+		"(uid:dn:=john)",
+	}
+
+	for i := 0; i < len(invalidFilters); i++ {
+		_, err := compileFilter(invalidFilters[i])
+		assert.NotNil(t, err, "filter \"%s\" should be invalid", invalidFilters[i])
+	}
+}
+
+func TestEscapeFilterValue(t *testing.T) {
+	assert.Equal(t, "john", EscapeFilterValue("john"))
+	assert.Equal(t, "\\2a\\28\\29\\5c\\00", EscapeFilterValue("*()\\\x00"))
+
+	filter, err := compileFilter("(uid=" + EscapeFilterValue("*)(uid=*") + ")")
+	assert.Nil(t, err)
+	assert.Equal(t, byte(0xa3), filter[0])
+}
@@ -4,9 +4,12 @@ import "github.com/kyy-me/ezbookkeeping/pkg/models"
 
 // LocaleTextItems represents all text items need to be translated
 type LocaleTextItems struct {
-	DefaultTypes                *DefaultTypes
-	VerifyEmailTextItems        *VerifyEmailTextItems
-	ForgetPasswordMailTextItems *ForgetPasswordMailTextItems
+	DefaultTypes                    *DefaultTypes
+	VerifyEmailTextItems            *VerifyEmailTextItems
+	ForgetPasswordMailTextItems     *ForgetPasswordMailTextItems
+	AccountLockedMailTextItems      *AccountLockedMailTextItems
+	EmailChangeConfirmMailTextItems *EmailChangeConfirmMailTextItems
+	EmailChangeNotifyMailTextItems  *EmailChangeNotifyMailTextItems
 }
 
 type DefaultTypes struct {
@@ -31,3 +34,28 @@ type ForgetPasswordMailTextItems struct {
 	ResetPassword             string
 	DescriptionBelowBtnFormat string
 }
+
+// AccountLockedMailTextItems represents text items need to be translated in account locked mail
+type AccountLockedMailTextItems struct {
+	Title             string
+	SalutationFormat  string
+	DescriptionFormat string
+	Suggestion        string
+}
+
+// EmailChangeConfirmMailTextItems represents text items need to be translated in email change confirmation mail
+type EmailChangeConfirmMailTextItems struct {
+	Title                     string
+	SalutationFormat          string
+	DescriptionAboveBtnFormat string
+	ConfirmEmailChange        string
+	DescriptionBelowBtnFormat string
+}
+
+// EmailChangeNotifyMailTextItems represents text items need to be translated in email change notification mail
+type EmailChangeNotifyMailTextItems struct {
+	Title             string
+	SalutationFormat  string
+	DescriptionFormat string
+	Suggestion        string
+}
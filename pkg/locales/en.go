@@ -21,4 +21,23 @@ var en = &LocaleTextItems{
 		ResetPassword:             "Reset Password",
 		DescriptionBelowBtnFormat: "If you did not request to reset your password, please simply disregard this email. If you cannot click the link above, please copy the above url and paste it into your browser. The password reset link will be expired after %v minutes.",
 	},
+	AccountLockedMailTextItems: &AccountLockedMailTextItems{
+		Title:             "Your Account Has Been Locked",
+		SalutationFormat:  "Hi %s,",
+		DescriptionFormat: "Your account has been locked for %v minutes because of %d consecutive failed login attempts. The last failed attempt came from %s.",
+		Suggestion:        "If these attempts were not made by you, please change your password and enable two-factor authorization after the lockout ends.",
+	},
+	EmailChangeConfirmMailTextItems: &EmailChangeConfirmMailTextItems{
+		Title:                     "Confirm Your New Email",
+		SalutationFormat:          "Hi %s,",
+		DescriptionAboveBtnFormat: "We recently received a request to change the email address of your %s account to this address. Please click the link below to confirm the change.",
+		ConfirmEmailChange:        "Confirm Email Change",
+		DescriptionBelowBtnFormat: "If you did not request to change your email address, please simply disregard this email. If you cannot click the link above, please copy the above url and paste it into your browser. The confirmation link will be expired after %v minutes.",
+	},
+	EmailChangeNotifyMailTextItems: &EmailChangeNotifyMailTextItems{
+		Title:             "Email Change Requested",
+		SalutationFormat:  "Hi %s,",
+		DescriptionFormat: "We recently received a request to change the email address of your account to %s. The change will not take effect until it is confirmed from the new email address.",
+		Suggestion:        "If you did not request this change, please change your password and enable two-factor authorization immediately.",
+	},
 }
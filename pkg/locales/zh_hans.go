@@ -21,4 +21,23 @@ var zhHans = &LocaleTextItems{
 		ResetPassword:             "重置密码",
 		DescriptionBelowBtnFormat: "如果您没有请求重置密码，请直接忽略本邮件。如果您无法点击上述链接，请复制下方的地址然后在您的浏览器中粘贴。重置密码链接将在 %v 分钟后过期。",
 	},
+	AccountLockedMailTextItems: &AccountLockedMailTextItems{
+		Title:             "您的账户已被锁定",
+		SalutationFormat:  "%s 您好，",
+		DescriptionFormat: "由于连续 %[2]d 次登录失败，您的账户已被锁定 %[1]v 分钟。最近一次失败的登录来自 %[3]s。",
+		Suggestion:        "如果这些登录不是您本人的操作，请在锁定结束后修改您的密码并启用两步验证。",
+	},
+	EmailChangeConfirmMailTextItems: &EmailChangeConfirmMailTextItems{
+		Title:                     "确认您的新邮箱",
+		SalutationFormat:          "%s 您好，",
+		DescriptionAboveBtnFormat: "我们刚才收到将您的 %s 账户邮箱地址修改为本地址的请求。请点击下方的链接确认修改。",
+		ConfirmEmailChange:        "确认修改邮箱",
+		DescriptionBelowBtnFormat: "如果您没有请求修改邮箱地址，请直接忽略本邮件。如果您无法点击上述链接，请复制下方的地址然后在您的浏览器中粘贴。确认链接将在 %v 分钟后过期。",
+	},
+	EmailChangeNotifyMailTextItems: &EmailChangeNotifyMailTextItems{
+		Title:             "邮箱修改请求",
+		SalutationFormat:  "%s 您好，",
+		DescriptionFormat: "我们刚才收到将您的账户邮箱地址修改为 %s 的请求。该修改需要在新邮箱中确认后才会生效。",
+		Suggestion:        "如果这不是您本人的操作，请立即修改您的密码并启用两步验证。",
+	},
 }
@@ -1,12 +1,15 @@
 package middlewares
 
 import (
+	"slices"
+
 	"github.com/golang-jwt/jwt/v5"
 
 	"github.com/kyy-me/ezbookkeeping/pkg/core"
 	"github.com/kyy-me/ezbookkeeping/pkg/errs"
 	"github.com/kyy-me/ezbookkeeping/pkg/log"
 	"github.com/kyy-me/ezbookkeeping/pkg/services"
+	"github.com/kyy-me/ezbookkeeping/pkg/settings"
 	"github.com/kyy-me/ezbookkeeping/pkg/utils"
 )
 
@@ -24,17 +27,24 @@ const tokenQueryStringParam = "token"
 
 // JWTAuthorization verifies whether current request is valid by jwt token in header
 func JWTAuthorization(c *core.Context) {
-	jwtAuthorization(c, TOKEN_SOURCE_TYPE_HEADER)
+	jwtAuthorization(c, TOKEN_SOURCE_TYPE_HEADER, "")
+}
+
+// JWTAuthorizationWithScope verifies whether current request is valid by jwt token in header, and the personal access token is also allowed if it has the specified scope
+func JWTAuthorizationWithScope(scope core.TokenScope) core.MiddlewareHandlerFunc {
+	return func(c *core.Context) {
+		jwtAuthorization(c, TOKEN_SOURCE_TYPE_HEADER, scope)
+	}
 }
 
 // JWTAuthorizationByQueryString verifies whether current request is valid by jwt token in query string
 func JWTAuthorizationByQueryString(c *core.Context) {
-	jwtAuthorization(c, TOKEN_SOURCE_TYPE_ARGUMENT)
+	jwtAuthorization(c, TOKEN_SOURCE_TYPE_ARGUMENT, "")
 }
 
 // JWTAuthorizationByCookie verifies whether current request is valid by jwt token in cookie
 func JWTAuthorizationByCookie(c *core.Context) {
-	jwtAuthorization(c, TOKEN_SOURCE_TYPE_COOKIE)
+	jwtAuthorization(c, TOKEN_SOURCE_TYPE_COOKIE, "")
 }
 
 // JWTTwoFactorAuthorization verifies whether current request is valid by 2fa passcode
@@ -75,6 +85,25 @@ func JWTEmailVerifyAuthorization(c *core.Context) {
 	c.Next()
 }
 
+// JWTEmailChangeAuthorization verifies whether current request is email change confirmation
+func JWTEmailChangeAuthorization(c *core.Context) {
+	claims, err := getTokenClaims(c, TOKEN_SOURCE_TYPE_ARGUMENT)
+
+	if err != nil {
+		utils.PrintJsonErrorResult(c, errs.ErrEmailChangeTokenIsInvalidOrExpired)
+		return
+	}
+
+	if claims.Type != core.USER_TOKEN_TYPE_EMAIL_CHANGE {
+		log.WarnfWithRequestId(c, "[authorization.JWTEmailChangeAuthorization] user \"uid:%d\" token is not for email change", claims.Uid)
+		utils.PrintJsonErrorResult(c, errs.ErrCurrentInvalidToken)
+		return
+	}
+
+	c.SetTokenClaims(claims)
+	c.Next()
+}
+
 // JWTResetPasswordAuthorization verifies whether current request is password reset
 func JWTResetPasswordAuthorization(c *core.Context) {
 	claims, err := getTokenClaims(c, TOKEN_SOURCE_TYPE_ARGUMENT)
@@ -94,7 +123,35 @@ func JWTResetPasswordAuthorization(c *core.Context) {
 	c.Next()
 }
 
-func jwtAuthorization(c *core.Context, source TokenSourceType) {
+// AdminAuthorization verifies whether current user has the administrator role or is one of the administrators in config, it must be used after jwt authorization
+func AdminAuthorization(config *settings.Config) core.MiddlewareHandlerFunc {
+	return func(c *core.Context) {
+		uid := c.GetCurrentUid()
+		user, err := services.Users.GetUserById(c, uid)
+
+		if err != nil {
+			log.WarnfWithRequestId(c, "[authorization.AdminAuthorization] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
+			utils.PrintJsonErrorResult(c, errs.ErrUserNotFound)
+			return
+		}
+
+		if user.Disabled {
+			log.WarnfWithRequestId(c, "[authorization.AdminAuthorization] user \"uid:%d\" is disabled", uid)
+			utils.PrintJsonErrorResult(c, errs.ErrUserIsDisabled)
+			return
+		}
+
+		if !user.IsAdmin && !slices.Contains(config.AdminUsernames, user.Username) {
+			log.WarnfWithRequestId(c, "[authorization.AdminAuthorization] user \"uid:%d\" is not administrator", uid)
+			utils.PrintJsonErrorResult(c, errs.ErrUserIsNotAdministrator)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func jwtAuthorization(c *core.Context, source TokenSourceType, scope core.TokenScope) {
 	claims, err := getTokenClaims(c, source)
 
 	if err != nil {
@@ -108,7 +165,13 @@ func jwtAuthorization(c *core.Context, source TokenSourceType) {
 		return
 	}
 
-	if claims.Type != core.USER_TOKEN_TYPE_NORMAL {
+	if claims.Type == core.USER_TOKEN_TYPE_PERSONAL_ACCESS && (scope == "" || !claims.HasScope(scope)) {
+		log.WarnfWithRequestId(c, "[authorization.jwtAuthorization] user \"uid:%d\" personal access token does not have scope \"%s\"", claims.Uid, scope)
+		utils.PrintJsonErrorResult(c, errs.ErrCurrentTokenScopeNotAllowed)
+		return
+	}
+
+	if claims.Type != core.USER_TOKEN_TYPE_NORMAL && claims.Type != core.USER_TOKEN_TYPE_PERSONAL_ACCESS {
 		log.WarnfWithRequestId(c, "[authorization.jwtAuthorization] user \"uid:%d\" token type is invalid", claims.Uid)
 		utils.PrintJsonErrorResult(c, errs.ErrCurrentInvalidTokenType)
 		return
@@ -17,9 +17,13 @@ func ServerSettingsCookie(config *settings.Config) core.MiddlewareHandlerFunc {
 	return func(c *core.Context) {
 		settingsArr := []string{
 			buildBooleanSetting("r", config.EnableUserRegister),
+			buildBooleanSetting("ri", config.RegisterRequireInvitationCode),
 			buildBooleanSetting("f", config.EnableUserForgetPassword),
 			buildBooleanSetting("v", config.EnableUserVerifyEmail),
 			buildBooleanSetting("e", config.EnableDataExport),
+			buildBooleanSetting("o", config.EnableOIDC),
+			buildBooleanSetting("w", config.EnableWebAuthn),
+			buildBooleanSetting("wp", config.EnableWebAuthnPasswordlessLogin),
 			buildStringSetting("m", strings.Replace(config.MapProvider, "_", "-", -1)),
 		}
 
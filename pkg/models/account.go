@@ -63,7 +63,7 @@ type Account struct {
 	DisplayOrder    int32           `xorm:"INDEX(IDX_account_uid_deleted_parent_account_id_order) NOT NULL"`
 	Icon            int64           `xorm:"NOT NULL"`
 	Color           string          `xorm:"VARCHAR(6) NOT NULL"`
-	Currency        string          `xorm:"VARCHAR(3) NOT NULL"`
+	Currency        string          `xorm:"VARCHAR(10) NOT NULL"`
 	Balance         int64           `xorm:"NOT NULL"`
 	Comment         string          `xorm:"VARCHAR(255) NOT NULL"`
 	Hidden          bool            `xorm:"NOT NULL"`
@@ -81,7 +81,7 @@ type AccountCreateRequest struct {
 	ExpirationDate int64                   `json:"expirationDate"`
 	Icon           int64                   `json:"icon,string" binding:"required,min=1"`
 	Color          string                  `json:"color" binding:"required,len=6,validHexRGBColor"`
-	Currency       string                  `json:"currency" binding:"required,len=3,validCurrency"`
+	Currency       string                  `json:"currency" binding:"required,min=2,max=10,validCurrencyOrCommodityCode"`
 	Balance        int64                   `json:"balance"`
 	Comment        string                  `json:"comment" binding:"max=255"`
 	SubAccounts    []*AccountCreateRequest `json:"subAccounts" binding:"omitempty"`
@@ -176,6 +176,16 @@ func (a *Account) ToAccountInfoResponse() *AccountInfoResponse {
 	}
 }
 
+// IsAssetAccountCategory returns whether the account category is asset
+func IsAssetAccountCategory(category AccountCategory) bool {
+	return assetAccountCategory[category]
+}
+
+// IsLiabilityAccountCategory returns whether the account category is liability
+func IsLiabilityAccountCategory(category AccountCategory) bool {
+	return liabilityAccountCategory[category]
+}
+
 // AccountInfoResponseSlice represents the slice data structure of AccountInfoResponse
 type AccountInfoResponseSlice []*AccountInfoResponse
 
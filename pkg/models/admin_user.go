@@ -0,0 +1,68 @@
+package models
+
+// AdminUserListRequest represents all parameters of administrator listing users request
+type AdminUserListRequest struct {
+	Keyword string `form:"keyword" binding:"max=100"`
+	MaxId   int64  `form:"max_id,string" binding:"min=0"`
+	Count   int32  `form:"count" binding:"required,min=1,max=50"`
+}
+
+// AdminUserGetRequest represents all parameters of administrator getting user request
+type AdminUserGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// AdminUserOperateRequest represents all parameters of administrator operating user request
+type AdminUserOperateRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// AdminUserInfoResponse represents a view-object of user for administrator
+type AdminUserInfoResponse struct {
+	Id                  int64  `json:"id,string"`
+	Username            string `json:"username"`
+	Email               string `json:"email"`
+	Nickname            string `json:"nickname"`
+	DefaultCurrency     string `json:"defaultCurrency"`
+	Disabled            bool   `json:"disabled"`
+	IsAdmin             bool   `json:"isAdmin"`
+	EmailVerified       bool   `json:"emailVerified"`
+	PendingEmail        string `json:"pendingEmail,omitempty"`
+	CreatedAt           int64  `json:"createdAt"`
+	UpdatedAt           int64  `json:"updatedAt"`
+	LastLoginAt         int64  `json:"lastLoginAt"`
+	DeletionScheduledAt int64  `json:"deletionScheduledAt,omitempty"`
+	InvitationCodeId    int64  `json:"invitationCodeId,string,omitempty"`
+}
+
+// AdminUserInfoPageWrapperResponse represents a response of users for administrator which contains items and next id
+type AdminUserInfoPageWrapperResponse struct {
+	Items     []*AdminUserInfoResponse `json:"items"`
+	NextMaxId *int64                   `json:"nextMaxId,string"`
+}
+
+// AdminUserTransactionCheckResponse represents the result of checking transactions and accounts of user
+type AdminUserTransactionCheckResponse struct {
+	Passed  bool   `json:"passed"`
+	Message string `json:"message,omitempty"`
+}
+
+// ToAdminUserInfoResponse returns a view-object for administrator according to database model
+func (u *User) ToAdminUserInfoResponse() *AdminUserInfoResponse {
+	return &AdminUserInfoResponse{
+		Id:                  u.Uid,
+		Username:            u.Username,
+		Email:               u.Email,
+		Nickname:            u.Nickname,
+		DefaultCurrency:     u.DefaultCurrency,
+		Disabled:            u.Disabled,
+		IsAdmin:             u.IsAdmin,
+		EmailVerified:       u.EmailVerified,
+		PendingEmail:        u.PendingEmail,
+		CreatedAt:           u.CreatedUnixTime,
+		UpdatedAt:           u.UpdatedUnixTime,
+		LastLoginAt:         u.LastLoginUnixTime,
+		DeletionScheduledAt: u.DeletionScheduledUnixTime,
+		InvitationCodeId:    u.InvitationCodeId,
+	}
+}
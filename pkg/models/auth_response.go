@@ -1,10 +1,17 @@
 package models
 
+// Two-factor authorization methods
+const (
+	TWO_FACTOR_METHOD_PASSCODE = "passcode"
+	TWO_FACTOR_METHOD_WEBAUTHN = "webauthn"
+)
+
 // AuthResponse returns a view-object of user authorization
 type AuthResponse struct {
-	Token   string         `json:"token"`
-	Need2FA bool           `json:"need2FA"`
-	User    *UserBasicInfo `json:"user"`
+	Token            string         `json:"token"`
+	Need2FA          bool           `json:"need2FA"`
+	TwoFactorMethods []string       `json:"twoFactorMethods,omitempty"`
+	User             *UserBasicInfo `json:"user"`
 }
 
 // RegisterResponse returns a view-object of user register response
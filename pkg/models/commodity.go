@@ -0,0 +1,149 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/utils"
+)
+
+// CurrencyDecimalPlaces represents the implied decimal places of amounts in all currencies
+const CurrencyDecimalPlaces int32 = 2
+
+// CommodityPriceSource represents where the price of commodity comes from
+type CommodityPriceSource byte
+
+// Commodity price sources
+const (
+	COMMODITY_PRICE_SOURCE_MANUAL CommodityPriceSource = 1
+	COMMODITY_PRICE_SOURCE_QUOTE  CommodityPriceSource = 2
+)
+
+// Commodity represents user-defined commodity (e.g. cryptocurrency, airline miles or precious metal) data stored in database,
+// the amounts of accounts denominated in commodity are the integers in the smallest unit of its decimal places,
+// and the price is the amount of price currency per 1 unit of commodity
+type Commodity struct {
+	CommodityId          int64                `xorm:"PK"`
+	Uid                  int64                `xorm:"INDEX(IDX_commodity_uid_deleted_code) NOT NULL"`
+	Deleted              bool                 `xorm:"INDEX(IDX_commodity_uid_deleted_code) NOT NULL"`
+	Code                 string               `xorm:"INDEX(IDX_commodity_uid_deleted_code) VARCHAR(10) NOT NULL"`
+	Name                 string               `xorm:"VARCHAR(64) NOT NULL"`
+	DecimalPlaces        int32                `xorm:"NOT NULL"`
+	PriceSource          CommodityPriceSource `xorm:"NOT NULL"`
+	PriceCurrency        string               `xorm:"VARCHAR(3) NOT NULL"`
+	Price                string               `xorm:"VARCHAR(32) NOT NULL"`
+	PriceUpdatedUnixTime int64
+	CreatedUnixTime      int64
+	UpdatedUnixTime      int64
+	DeletedUnixTime      int64
+}
+
+// CommodityGetRequest represents all parameters of commodity getting request
+type CommodityGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// CommodityCreateRequest represents all parameters of commodity creation request
+type CommodityCreateRequest struct {
+	Code          string               `json:"code" binding:"required,min=2,max=10,validCommodityCode"`
+	Name          string               `json:"name" binding:"required,notBlank,max=64"`
+	DecimalPlaces int32                `json:"decimalPlaces" binding:"min=0,max=8"`
+	PriceSource   CommodityPriceSource `json:"priceSource" binding:"required"`
+	PriceCurrency string               `json:"priceCurrency" binding:"required,len=3,validCurrency"`
+	Price         string               `json:"price" binding:"max=32"`
+}
+
+// CommodityModifyRequest represents all parameters of commodity modification request,
+// the code and decimal places cannot be modified because the amounts of accounts depend on them
+type CommodityModifyRequest struct {
+	Id            int64                `json:"id,string" binding:"required,min=1"`
+	Name          string               `json:"name" binding:"required,notBlank,max=64"`
+	PriceSource   CommodityPriceSource `json:"priceSource" binding:"required"`
+	PriceCurrency string               `json:"priceCurrency" binding:"required,len=3,validCurrency"`
+	Price         string               `json:"price" binding:"max=32"`
+}
+
+// CommodityRefreshPriceRequest represents all parameters of commodity price refreshing request
+type CommodityRefreshPriceRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// CommodityDeleteRequest represents all parameters of commodity deleting request
+type CommodityDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// CommodityInfoResponse represents a view-object of commodity
+type CommodityInfoResponse struct {
+	Id              int64                `json:"id,string"`
+	Code            string               `json:"code"`
+	Name            string               `json:"name"`
+	DecimalPlaces   int32                `json:"decimalPlaces"`
+	PriceSource     CommodityPriceSource `json:"priceSource"`
+	PriceCurrency   string               `json:"priceCurrency"`
+	Price           string               `json:"price"`
+	PriceUpdateTime int64                `json:"priceUpdateTime"`
+}
+
+// IsValid returns whether the price source is supported
+func (s CommodityPriceSource) IsValid() bool {
+	return s == COMMODITY_PRICE_SOURCE_MANUAL || s == COMMODITY_PRICE_SOURCE_QUOTE
+}
+
+// IsValidCommodityPrice returns whether the textual price is a non-negative decimal number, the empty price means the price is unknown
+func IsValidCommodityPrice(price string) bool {
+	if price == "" {
+		return true
+	}
+
+	value, err := utils.ParseDecimal(price)
+
+	return err == nil && value.Sign() >= 0
+}
+
+// ConvertAmountToPriceCurrency returns the amount in price currency which is converted from the amount in smallest unit of this commodity
+func (c *Commodity) ConvertAmountToPriceCurrency(amount int64, roundingMode utils.RoundingMode) (int64, error) {
+	if c.Price == "" {
+		return 0, errs.ErrCommodityPriceInvalid
+	}
+
+	convertedAmount, err := utils.ConvertAmountByPrice(amount, c.DecimalPlaces, c.Price, CurrencyDecimalPlaces, roundingMode)
+
+	if err != nil {
+		return 0, errs.ErrCommodityPriceInvalid
+	}
+
+	return convertedAmount, nil
+}
+
+// ToCommodityInfoResponse returns a view-object according to database model
+func (c *Commodity) ToCommodityInfoResponse() *CommodityInfoResponse {
+	return &CommodityInfoResponse{
+		Id:              c.CommodityId,
+		Code:            c.Code,
+		Name:            c.Name,
+		DecimalPlaces:   c.DecimalPlaces,
+		PriceSource:     c.PriceSource,
+		PriceCurrency:   c.PriceCurrency,
+		Price:           c.Price,
+		PriceUpdateTime: c.PriceUpdatedUnixTime,
+	}
+}
+
+// CommodityInfoResponseSlice represents the slice data structure of CommodityInfoResponse
+type CommodityInfoResponseSlice []*CommodityInfoResponse
+
+// Len returns the count of items
+func (s CommodityInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s CommodityInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s CommodityInfoResponseSlice) Less(i, j int) bool {
+	return strings.Compare(s[i].Code, s[j].Code) < 0
+}
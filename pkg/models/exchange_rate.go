@@ -1,6 +1,47 @@
 package models
 
-import "strings"
+import (
+	"math"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/utils"
+)
+
+const exchangeRateCrossRateDecimalPlaces = 18
+
+// ExchangeRate represents historical exchange rate data stored in database
+type ExchangeRate struct {
+	DataSource      string `xorm:"VARCHAR(64) PK INDEX(IDX_exchange_rate_data_source_base_currency_date) NOT NULL"`
+	BaseCurrency    string `xorm:"VARCHAR(3) PK INDEX(IDX_exchange_rate_data_source_base_currency_date) NOT NULL"`
+	Currency        string `xorm:"VARCHAR(3) PK NOT NULL"`
+	Date            int32  `xorm:"PK INDEX(IDX_exchange_rate_data_source_base_currency_date) NOT NULL"`
+	Rate            string `xorm:"VARCHAR(32) NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+}
+
+// ExchangeRateConversionRequest represents all parameters of converting amounts into one target currency
+type ExchangeRateConversionRequest struct {
+	TargetCurrency   string `form:"target_currency" binding:"omitempty,len=3,validCurrency"`
+	ExchangeRateDate string `form:"exchange_rate_date"`
+}
+
+// HistoricalExchangeRateRequest represents all parameters of historical exchange rate request
+type HistoricalExchangeRateRequest struct {
+	Date string `form:"date" binding:"required"`
+}
+
+// ExchangeRateConvertRequest represents all parameters of exchange rate conversion request
+type ExchangeRateConvertRequest struct {
+	From   string `form:"from" binding:"required,len=3,validCurrency"`
+	To     string `form:"to" binding:"required,len=3,validCurrency"`
+	Amount int64  `form:"amount" binding:"min=-99999999999,max=99999999999"`
+	Date   string `form:"date"`
+}
 
 // LatestExchangeRateResponse returns a view-object which contains latest exchange rate
 type LatestExchangeRateResponse struct {
@@ -9,12 +50,512 @@ type LatestExchangeRateResponse struct {
 	UpdateTime    int64                   `json:"updateTime"`
 	BaseCurrency  string                  `json:"baseCurrency"`
 	ExchangeRates LatestExchangeRateSlice `json:"exchangeRates"`
+	FetchTime     int64                   `json:"fetchTime,omitempty"`
+	CacheAge      int64                   `json:"cacheAge"`
 }
 
 // LatestExchangeRate represents a data pair of currency and exchange rate
 type LatestExchangeRate struct {
-	Currency string `json:"currency"`
-	Rate     string `json:"rate"`
+	Currency     string `json:"currency"`
+	Rate         string `json:"rate"`
+	DataSource   string `json:"dataSource,omitempty"`
+	UserProvided bool   `json:"userProvided,omitempty"`
+}
+
+// HistoricalExchangeRateResponse returns a view-object which contains the exchange rates of the specified date
+type HistoricalExchangeRateResponse struct {
+	DataSource    string                      `json:"dataSource"`
+	Date          string                      `json:"date"`
+	BaseCurrency  string                      `json:"baseCurrency"`
+	ExchangeRates HistoricalExchangeRateSlice `json:"exchangeRates"`
+}
+
+// HistoricalExchangeRate represents a data pair of currency and exchange rate, and the actual date of the exchange rate
+type HistoricalExchangeRate struct {
+	Currency     string `json:"currency"`
+	Rate         string `json:"rate"`
+	Date         string `json:"date"`
+	UserProvided bool   `json:"userProvided,omitempty"`
+}
+
+// ExchangeRateConvertResponse represents a view-object of the amount converted from one currency into another currency
+type ExchangeRateConvertResponse struct {
+	FromCurrency    string `json:"fromCurrency"`
+	ToCurrency      string `json:"toCurrency"`
+	Amount          int64  `json:"amount"`
+	ConvertedAmount int64  `json:"convertedAmount"`
+	Rate            string `json:"rate"`
+	DataSource      string `json:"dataSource"`
+	Date            string `json:"date,omitempty"`
+	UserProvided    bool   `json:"userProvided,omitempty"`
+}
+
+// ExchangeRatesHealthResponse represents a view-object of the health of all configured exchange rates data sources
+type ExchangeRatesHealthResponse struct {
+	DataSources         []*ExchangeRatesDataSourceHealth `json:"dataSources"`
+	UncoveredCurrencies []string                         `json:"uncoveredCurrencies"`
+}
+
+// ExchangeRatesDataSourceHealth represents the fetching status of one exchange rates data source
+type ExchangeRatesDataSourceHealth struct {
+	DataSource              string   `json:"dataSource"`
+	Stale                   bool     `json:"stale"`
+	UpdateTime              int64    `json:"updateTime"`
+	LastSuccessfulFetchTime int64    `json:"lastSuccessfulFetchTime"`
+	LastAttemptTime         int64    `json:"lastAttemptTime"`
+	LastErrorTime           int64    `json:"lastErrorTime"`
+	LastError               string   `json:"lastError,omitempty"`
+	FetchFailureCount       int64    `json:"fetchFailureCount"`
+	ParseFailureCount       int64    `json:"parseFailureCount"`
+	Currencies              []string `json:"currencies"`
+	Problems                []string `json:"problems"`
+}
+
+// ExchangeRateConversionInfo represents the dates of exchange rates which are used to convert a currency
+type ExchangeRateConversionInfo struct {
+	Currency    string `json:"currency"`
+	MinRateDate string `json:"minRateDate"`
+	MaxRateDate string `json:"maxRateDate"`
+}
+
+// ExchangeRateHistory represents all historical exchange rates of one data source in a specific date range
+type ExchangeRateHistory struct {
+	DataSource    string
+	BaseCurrency  string
+	currencyRates map[string][]*ExchangeRate
+}
+
+// ExchangeRateAmountConverter converts account amounts into one target currency by historical exchange rates
+type ExchangeRateAmountConverter struct {
+	history           *ExchangeRateHistory
+	targetCurrency    string
+	fixedDate         int32
+	accountCurrencies map[int64]string
+	commodities       map[string]*Commodity
+	usedRateDates     map[int32]map[string][]int32
+	roundingMode      utils.RoundingMode
+}
+
+// IsConversionRequired returns whether amounts need to be converted into target currency
+func (r *ExchangeRateConversionRequest) IsConversionRequired() bool {
+	return r.TargetCurrency != ""
+}
+
+// GetNumericExchangeRateDate returns the numeric fixed exchange rate date, or returns zero if the exchange rate of transaction date should be used
+func (r *ExchangeRateConversionRequest) GetNumericExchangeRateDate() (int32, error) {
+	if r.ExchangeRateDate == "" {
+		return 0, nil
+	}
+
+	date, err := utils.ParseNumericDate(r.ExchangeRateDate)
+
+	if err != nil {
+		return 0, errs.ErrExchangeRateDateInvalid
+	}
+
+	return date, nil
+}
+
+// ToExchangeRates returns the exchange rates which can be stored in database of the specified data source,
+// the date of these exchange rates is the update date in UTC
+func (r *LatestExchangeRateResponse) ToExchangeRates(dataSource string) []*ExchangeRate {
+	date := utils.FormatUnixTimeToNumericDate(r.UpdateTime, time.UTC)
+	exchangeRates := make([]*ExchangeRate, 0, len(r.ExchangeRates))
+
+	for i := 0; i < len(r.ExchangeRates); i++ {
+		exchangeRate := r.ExchangeRates[i]
+
+		if exchangeRate.Currency == r.BaseCurrency {
+			continue
+		}
+
+		exchangeRates = append(exchangeRates, &ExchangeRate{
+			DataSource:   dataSource,
+			BaseCurrency: r.BaseCurrency,
+			Currency:     exchangeRate.Currency,
+			Date:         date,
+			Rate:         exchangeRate.Rate,
+		})
+	}
+
+	return exchangeRates
+}
+
+// NewExchangeRateHistory returns a new exchange rate history by the exchange rates of one data source and base currency
+func NewExchangeRateHistory(dataSource string, baseCurrency string, exchangeRates []*ExchangeRate) *ExchangeRateHistory {
+	currencyRates := make(map[string][]*ExchangeRate)
+
+	for i := 0; i < len(exchangeRates); i++ {
+		exchangeRate := exchangeRates[i]
+
+		if exchangeRate.DataSource != dataSource || exchangeRate.BaseCurrency != baseCurrency {
+			continue
+		}
+
+		currencyRates[exchangeRate.Currency] = append(currencyRates[exchangeRate.Currency], exchangeRate)
+	}
+
+	for _, rates := range currencyRates {
+		sort.Sort(ExchangeRateSlice(rates))
+	}
+
+	return &ExchangeRateHistory{
+		DataSource:    dataSource,
+		BaseCurrency:  baseCurrency,
+		currencyRates: currencyRates,
+	}
+}
+
+// GetExchangeRate returns the exchange rate of specified currency on the specified date, or on the nearest earlier date if there is no exchange rate on that date
+func (h *ExchangeRateHistory) GetExchangeRate(currency string, date int32) *ExchangeRate {
+	if currency == h.BaseCurrency {
+		return &ExchangeRate{
+			DataSource:   h.DataSource,
+			BaseCurrency: h.BaseCurrency,
+			Currency:     currency,
+			Date:         date,
+			Rate:         "1",
+		}
+	}
+
+	rates := h.currencyRates[currency]
+	index := sort.Search(len(rates), func(i int) bool {
+		return rates[i].Date > date
+	})
+
+	if index < 1 {
+		return nil
+	}
+
+	return rates[index-1]
+}
+
+// GetNearestExchangeRate returns the exchange rate of specified currency on the specified date, or on the nearest earlier date if there is no exchange rate on that date,
+// or the earliest exchange rate if all exchange rates are later than the specified date
+func (h *ExchangeRateHistory) GetNearestExchangeRate(currency string, date int32) *ExchangeRate {
+	exchangeRate := h.GetExchangeRate(currency, date)
+
+	if exchangeRate != nil {
+		return exchangeRate
+	}
+
+	rates := h.currencyRates[currency]
+
+	if len(rates) < 1 {
+		return nil
+	}
+
+	return rates[0]
+}
+
+// GetCurrencies returns all currencies which have exchange rates in this history except the base currency
+func (h *ExchangeRateHistory) GetCurrencies() []string {
+	currencies := make([]string, 0, len(h.currencyRates))
+
+	for currency := range h.currencyRates {
+		currencies = append(currencies, currency)
+	}
+
+	sort.Strings(currencies)
+
+	return currencies
+}
+
+// MergeMissingCurrencies adds the historical exchange rates of the specified history of fallback data source on the dates which are missing in this history,
+// that is all dates of the currencies which do not exist yet, and the dates before the earliest exchange rate of the currencies which already exist,
+// the rates would be converted by a currency which exists in both histories if their base currencies are different,
+// returns false if there is no such currency
+func (h *ExchangeRateHistory) MergeMissingCurrencies(other *ExchangeRateHistory) bool {
+	bridgeCurrency := h.getBridgeCurrency(other)
+
+	if bridgeCurrency == "" {
+		return false
+	}
+
+	currencies := other.GetCurrencies()
+
+	if other.BaseCurrency != h.BaseCurrency {
+		currencies = append(currencies, other.BaseCurrency)
+	}
+
+	for i := 0; i < len(currencies); i++ {
+		currency := currencies[i]
+
+		if currency == h.BaseCurrency {
+			continue
+		}
+
+		existedRates := h.currencyRates[currency]
+		var earliestExistedDate int32 = math.MaxInt32
+
+		if len(existedRates) > 0 {
+			earliestExistedDate = existedRates[0].Date
+		}
+
+		// the rates of the base currency of fallback data source are derived on all dates of the bridge currency
+		otherRates := other.currencyRates[currency]
+
+		if currency == other.BaseCurrency {
+			otherRates = other.currencyRates[bridgeCurrency]
+		}
+
+		mergedRates := make([]*ExchangeRate, 0, len(otherRates)+len(existedRates))
+
+		for j := 0; j < len(otherRates) && otherRates[j].Date < earliestExistedDate; j++ {
+			date := otherRates[j].Date
+			rate := other.GetExchangeRate(currency, date)
+			bridgeRate := h.GetExchangeRate(bridgeCurrency, date)
+			otherBridgeRate := other.GetExchangeRate(bridgeCurrency, date)
+
+			if rate == nil || bridgeRate == nil || otherBridgeRate == nil {
+				continue
+			}
+
+			crossRate, err := getExchangeRateCrossRate(rate.Rate, bridgeRate.Rate, otherBridgeRate.Rate)
+
+			if err != nil {
+				continue
+			}
+
+			mergedRates = append(mergedRates, &ExchangeRate{
+				DataSource:      other.DataSource,
+				BaseCurrency:    h.BaseCurrency,
+				Currency:        currency,
+				Date:            date,
+				Rate:            crossRate,
+				CreatedUnixTime: otherRates[j].CreatedUnixTime,
+				UpdatedUnixTime: otherRates[j].UpdatedUnixTime,
+			})
+		}
+
+		if len(mergedRates) > 0 {
+			h.currencyRates[currency] = append(mergedRates, existedRates...)
+		}
+	}
+
+	return true
+}
+
+// ConvertAmount returns the amount in target currency which is converted from the amount in source currency by decimal calculation,
+// the exchange rates on the specified date, or on the nearest earlier date if there is no exchange rate on that date, would be used
+func (h *ExchangeRateHistory) ConvertAmount(amount int64, fromCurrency string, toCurrency string, date int32, roundingMode utils.RoundingMode) (int64, error) {
+	if fromCurrency == toCurrency {
+		return amount, nil
+	}
+
+	fromExchangeRate := h.GetExchangeRate(fromCurrency, date)
+	toExchangeRate := h.GetExchangeRate(toCurrency, date)
+
+	if fromExchangeRate == nil || toExchangeRate == nil {
+		return 0, errs.ErrExchangeRateNotFound
+	}
+
+	convertedAmount, err := utils.ConvertAmountByExchangeRates(amount, fromExchangeRate.Rate, toExchangeRate.Rate, roundingMode)
+
+	if err != nil {
+		return 0, errs.ErrExchangeRateNotFound
+	}
+
+	return convertedAmount, nil
+}
+
+func (h *ExchangeRateHistory) containsCurrency(currency string) bool {
+	return currency == h.BaseCurrency || len(h.currencyRates[currency]) > 0
+}
+
+// getBridgeCurrency returns the currency which exists in both histories, the base currency of this history is preferred
+// because its rate is always available even on the dates before the earliest exchange rate of this history
+func (h *ExchangeRateHistory) getBridgeCurrency(other *ExchangeRateHistory) string {
+	if other.containsCurrency(h.BaseCurrency) {
+		return h.BaseCurrency
+	}
+
+	if h.containsCurrency(other.BaseCurrency) {
+		return other.BaseCurrency
+	}
+
+	currencies := other.GetCurrencies()
+
+	for i := 0; i < len(currencies); i++ {
+		if h.containsCurrency(currencies[i]) {
+			return currencies[i]
+		}
+	}
+
+	return ""
+}
+
+// getExchangeRateCrossRate returns the textual rate which is converted from the rate based on the other base currency,
+// the bridge rates are the rates of the same currency in both base currencies
+func getExchangeRateCrossRate(rate string, bridgeRate string, otherBridgeRate string) (string, error) {
+	value, err := utils.ParseDecimal(rate)
+
+	if err != nil || value.Sign() <= 0 {
+		return "", errs.ErrParameterInvalid
+	}
+
+	bridge, err := utils.ParseDecimal(bridgeRate)
+
+	if err != nil || bridge.Sign() <= 0 {
+		return "", errs.ErrParameterInvalid
+	}
+
+	otherBridge, err := utils.ParseDecimal(otherBridgeRate)
+
+	if err != nil || otherBridge.Sign() <= 0 {
+		return "", errs.ErrParameterInvalid
+	}
+
+	crossRate := new(big.Rat).Mul(value, bridge)
+	crossRate.Quo(crossRate, otherBridge)
+
+	return utils.FormatDecimal(crossRate, exchangeRateCrossRateDecimalPlaces), nil
+}
+
+// NewExchangeRateAmountConverter returns a new amount converter which converts the amounts of specified accounts into target currency
+// by decimal calculation with the specified rounding mode
+func NewExchangeRateAmountConverter(history *ExchangeRateHistory, targetCurrency string, fixedDate int32, accounts map[int64]*Account, roundingMode utils.RoundingMode) *ExchangeRateAmountConverter {
+	accountCurrencies := make(map[int64]string, len(accounts))
+
+	for accountId, account := range accounts {
+		accountCurrencies[accountId] = account.Currency
+	}
+
+	return &ExchangeRateAmountConverter{
+		history:           history,
+		targetCurrency:    targetCurrency,
+		fixedDate:         fixedDate,
+		accountCurrencies: accountCurrencies,
+		usedRateDates:     make(map[int32]map[string][]int32),
+		roundingMode:      roundingMode,
+	}
+}
+
+// SetCommodities sets the commodities of the accounts which are denominated in commodity,
+// the amounts of these accounts would be converted into the price currency of commodity by the current price at first
+func (c *ExchangeRateAmountConverter) SetCommodities(commodities map[string]*Commodity) {
+	c.commodities = commodities
+}
+
+// GetTargetCurrency returns the target currency of this converter
+func (c *ExchangeRateAmountConverter) GetTargetCurrency() string {
+	return c.targetCurrency
+}
+
+// ConvertAccountAmount returns the amount in target currency which is converted from the amount of specified account on the specified date,
+// or on the nearest date which has exchange rate, the exchange rate dates would be recorded in the specified statistic group
+func (c *ExchangeRateAmountConverter) ConvertAccountAmount(statisticGroup int32, accountId int64, amount int64, date int32) (int64, error) {
+	currency, exists := c.accountCurrencies[accountId]
+
+	if !exists {
+		return 0, errs.ErrAccountNotFound
+	}
+
+	if commodity, exists := c.commodities[currency]; exists {
+		priceCurrencyAmount, err := commodity.ConvertAmountToPriceCurrency(amount, c.roundingMode)
+
+		if err != nil {
+			return 0, err
+		}
+
+		amount = priceCurrencyAmount
+		currency = commodity.PriceCurrency
+	}
+
+	if currency == c.targetCurrency {
+		return amount, nil
+	}
+
+	if c.history == nil {
+		return 0, errs.ErrExchangeRateNotFound
+	}
+
+	if c.fixedDate > 0 {
+		date = c.fixedDate
+	}
+
+	// the earliest exchange rate would be used for the transactions earlier than all exchange rates,
+	// and the actual date of exchange rate would be recorded in conversion infos
+	fromExchangeRate := c.history.GetNearestExchangeRate(currency, date)
+	toExchangeRate := c.history.GetNearestExchangeRate(c.targetCurrency, date)
+
+	if fromExchangeRate == nil || toExchangeRate == nil {
+		return 0, errs.ErrExchangeRateNotFound
+	}
+
+	convertedAmount, err := utils.ConvertAmountByExchangeRates(amount, fromExchangeRate.Rate, toExchangeRate.Rate, c.roundingMode)
+
+	if err != nil {
+		return 0, errs.ErrExchangeRateNotFound
+	}
+
+	c.recordRateDate(statisticGroup, currency, fromExchangeRate.Date)
+
+	if c.targetCurrency != c.history.BaseCurrency {
+		c.recordRateDate(statisticGroup, c.targetCurrency, toExchangeRate.Date)
+	}
+
+	return convertedAmount, nil
+}
+
+// GetConversionInfos returns the dates of exchange rates which are used in the specified statistic group
+func (c *ExchangeRateAmountConverter) GetConversionInfos(statisticGroup int32) []*ExchangeRateConversionInfo {
+	currencyRateDates := c.usedRateDates[statisticGroup]
+	conversionInfos := make(ExchangeRateConversionInfoSlice, 0, len(currencyRateDates))
+
+	for currency, rateDates := range currencyRateDates {
+		conversionInfos = append(conversionInfos, &ExchangeRateConversionInfo{
+			Currency:    currency,
+			MinRateDate: utils.FormatNumericDateToLongDate(rateDates[0]),
+			MaxRateDate: utils.FormatNumericDateToLongDate(rateDates[1]),
+		})
+	}
+
+	sort.Sort(conversionInfos)
+
+	return conversionInfos
+}
+
+func (c *ExchangeRateAmountConverter) recordRateDate(statisticGroup int32, currency string, rateDate int32) {
+	currencyRateDates, exists := c.usedRateDates[statisticGroup]
+
+	if !exists {
+		currencyRateDates = make(map[string][]int32)
+		c.usedRateDates[statisticGroup] = currencyRateDates
+	}
+
+	rateDates, exists := currencyRateDates[currency]
+
+	if !exists {
+		currencyRateDates[currency] = []int32{rateDate, rateDate}
+		return
+	}
+
+	if rateDate < rateDates[0] {
+		rateDates[0] = rateDate
+	}
+
+	if rateDate > rateDates[1] {
+		rateDates[1] = rateDate
+	}
+}
+
+// ExchangeRateSlice represents the slice data structure of ExchangeRate
+type ExchangeRateSlice []*ExchangeRate
+
+// Len returns the count of items
+func (s ExchangeRateSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s ExchangeRateSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s ExchangeRateSlice) Less(i, j int) bool {
+	return s[i].Date < s[j].Date
 }
 
 // LatestExchangeRateSlice represents the slice data structure of LatestExchangeRate
@@ -34,3 +575,39 @@ func (s LatestExchangeRateSlice) Swap(i, j int) {
 func (s LatestExchangeRateSlice) Less(i, j int) bool {
 	return strings.Compare(s[i].Currency, s[j].Currency) < 0
 }
+
+// HistoricalExchangeRateSlice represents the slice data structure of HistoricalExchangeRate
+type HistoricalExchangeRateSlice []*HistoricalExchangeRate
+
+// Len returns the count of items
+func (s HistoricalExchangeRateSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s HistoricalExchangeRateSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s HistoricalExchangeRateSlice) Less(i, j int) bool {
+	return strings.Compare(s[i].Currency, s[j].Currency) < 0
+}
+
+// ExchangeRateConversionInfoSlice represents the slice data structure of ExchangeRateConversionInfo
+type ExchangeRateConversionInfoSlice []*ExchangeRateConversionInfo
+
+// Len returns the count of items
+func (s ExchangeRateConversionInfoSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s ExchangeRateConversionInfoSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s ExchangeRateConversionInfoSlice) Less(i, j int) bool {
+	return strings.Compare(s[i].Currency, s[j].Currency) < 0
+}
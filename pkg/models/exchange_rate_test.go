@@ -0,0 +1,241 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/utils"
+)
+
+func TestExchangeRateHistoryGetExchangeRate(t *testing.T) {
+	history := NewExchangeRateHistory("ecb", "EUR", []*ExchangeRate{
+		{DataSource: "ecb", BaseCurrency: "EUR", Currency: "USD", Date: 20240103, Rate: "1.1"},
+		{DataSource: "ecb", BaseCurrency: "EUR", Currency: "USD", Date: 20240101, Rate: "1.0"},
+		{DataSource: "ecb", BaseCurrency: "EUR", Currency: "USD", Date: 20240105, Rate: "1.2"},
+		{DataSource: "other", BaseCurrency: "EUR", Currency: "USD", Date: 20240102, Rate: "9.9"},
+	})
+
+	actualRate := history.GetExchangeRate("USD", 20240103)
+	assert.Equal(t, int32(20240103), actualRate.Date)
+	assert.Equal(t, "1.1", actualRate.Rate)
+
+	actualRate = history.GetExchangeRate("USD", 20240104)
+	assert.Equal(t, int32(20240103), actualRate.Date)
+	assert.Equal(t, "1.1", actualRate.Rate)
+
+	actualRate = history.GetExchangeRate("USD", 20240102)
+	assert.Equal(t, int32(20240101), actualRate.Date)
+	assert.Equal(t, "1.0", actualRate.Rate)
+
+	actualRate = history.GetExchangeRate("USD", 20231231)
+	assert.Nil(t, actualRate)
+
+	actualRate = history.GetExchangeRate("JPY", 20240103)
+	assert.Nil(t, actualRate)
+
+	actualRate = history.GetExchangeRate("EUR", 20240103)
+	assert.Equal(t, int32(20240103), actualRate.Date)
+	assert.Equal(t, "1", actualRate.Rate)
+}
+
+func TestExchangeRateHistoryGetNearestExchangeRate(t *testing.T) {
+	history := NewExchangeRateHistory("ecb", "EUR", []*ExchangeRate{
+		{DataSource: "ecb", BaseCurrency: "EUR", Currency: "USD", Date: 20240103, Rate: "1.1"},
+		{DataSource: "ecb", BaseCurrency: "EUR", Currency: "USD", Date: 20240105, Rate: "1.2"},
+	})
+
+	actualRate := history.GetNearestExchangeRate("USD", 20240104)
+	assert.Equal(t, int32(20240103), actualRate.Date)
+
+	actualRate = history.GetNearestExchangeRate("USD", 20231231)
+	assert.Equal(t, int32(20240103), actualRate.Date)
+	assert.Equal(t, "1.1", actualRate.Rate)
+
+	actualRate = history.GetNearestExchangeRate("JPY", 20240103)
+	assert.Nil(t, actualRate)
+}
+
+func TestExchangeRateHistoryMergeMissingCurrencies_SameBaseCurrency(t *testing.T) {
+	history := NewExchangeRateHistory("ecb", "EUR", []*ExchangeRate{
+		{DataSource: "ecb", BaseCurrency: "EUR", Currency: "USD", Date: 20240101, Rate: "1.1"},
+	})
+	fallbackHistory := NewExchangeRateHistory("fallback", "EUR", []*ExchangeRate{
+		{DataSource: "fallback", BaseCurrency: "EUR", Currency: "USD", Date: 20240101, Rate: "9.9"},
+		{DataSource: "fallback", BaseCurrency: "EUR", Currency: "TWD", Date: 20240101, Rate: "34.5"},
+	})
+
+	assert.True(t, history.MergeMissingCurrencies(fallbackHistory))
+	assert.Equal(t, []string{"TWD", "USD"}, history.GetCurrencies())
+
+	actualRate := history.GetExchangeRate("USD", 20240101)
+	assert.Equal(t, "ecb", actualRate.DataSource)
+	assert.Equal(t, "1.1", actualRate.Rate)
+
+	actualRate = history.GetExchangeRate("TWD", 20240101)
+	assert.Equal(t, "fallback", actualRate.DataSource)
+	assert.Equal(t, "EUR", actualRate.BaseCurrency)
+	assert.Equal(t, "34.5", actualRate.Rate)
+}
+
+func TestExchangeRateHistoryMergeMissingCurrencies_DifferentBaseCurrency(t *testing.T) {
+	history := NewExchangeRateHistory("ecb", "EUR", []*ExchangeRate{
+		{DataSource: "ecb", BaseCurrency: "EUR", Currency: "CAD", Date: 20240101, Rate: "1.5"},
+	})
+	fallbackHistory := NewExchangeRateHistory("fallback", "CAD", []*ExchangeRate{
+		{DataSource: "fallback", BaseCurrency: "CAD", Currency: "TWD", Date: 20240101, Rate: "23"},
+	})
+
+	assert.True(t, history.MergeMissingCurrencies(fallbackHistory))
+
+	actualRate := history.GetExchangeRate("TWD", 20240101)
+	assert.Equal(t, "34.5", actualRate.Rate)
+
+	actualRate = history.GetExchangeRate("CAD", 20240101)
+	assert.Equal(t, "ecb", actualRate.DataSource)
+	assert.Equal(t, "1.5", actualRate.Rate)
+}
+
+func TestExchangeRateHistoryMergeMissingCurrencies_DerivedBaseCurrency(t *testing.T) {
+	history := NewExchangeRateHistory("ecb", "EUR", []*ExchangeRate{
+		{DataSource: "ecb", BaseCurrency: "EUR", Currency: "USD", Date: 20240101, Rate: "1.1"},
+	})
+	fallbackHistory := NewExchangeRateHistory("fallback", "AUD", []*ExchangeRate{
+		{DataSource: "fallback", BaseCurrency: "AUD", Currency: "EUR", Date: 20240101, Rate: "0.5"},
+	})
+
+	assert.True(t, history.MergeMissingCurrencies(fallbackHistory))
+
+	actualRate := history.GetExchangeRate("AUD", 20240101)
+	assert.Equal(t, "fallback", actualRate.DataSource)
+	assert.Equal(t, "2", actualRate.Rate)
+}
+
+func TestExchangeRateHistoryMergeMissingCurrencies_EarlierDatesOfExistedCurrency(t *testing.T) {
+	history := NewExchangeRateHistory("ecb", "EUR", []*ExchangeRate{
+		{DataSource: "ecb", BaseCurrency: "EUR", Currency: "USD", Date: 20240103, Rate: "1.1"},
+	})
+	fallbackHistory := NewExchangeRateHistory("fallback", "EUR", []*ExchangeRate{
+		{DataSource: "fallback", BaseCurrency: "EUR", Currency: "USD", Date: 20240101, Rate: "1.0"},
+		{DataSource: "fallback", BaseCurrency: "EUR", Currency: "USD", Date: 20240102, Rate: "1.05"},
+		{DataSource: "fallback", BaseCurrency: "EUR", Currency: "USD", Date: 20240103, Rate: "9.9"},
+		{DataSource: "fallback", BaseCurrency: "EUR", Currency: "USD", Date: 20240104, Rate: "9.9"},
+	})
+
+	assert.True(t, history.MergeMissingCurrencies(fallbackHistory))
+
+	actualRate := history.GetExchangeRate("USD", 20240101)
+	assert.Equal(t, "fallback", actualRate.DataSource)
+	assert.Equal(t, "1", actualRate.Rate)
+
+	actualRate = history.GetExchangeRate("USD", 20240102)
+	assert.Equal(t, "fallback", actualRate.DataSource)
+	assert.Equal(t, "1.05", actualRate.Rate)
+
+	actualRate = history.GetExchangeRate("USD", 20240103)
+	assert.Equal(t, "ecb", actualRate.DataSource)
+	assert.Equal(t, "1.1", actualRate.Rate)
+
+	actualRate = history.GetExchangeRate("USD", 20240104)
+	assert.Equal(t, "ecb", actualRate.DataSource)
+	assert.Equal(t, "1.1", actualRate.Rate)
+}
+
+func TestExchangeRateHistoryMergeMissingCurrencies_NoBridgeCurrency(t *testing.T) {
+	history := NewExchangeRateHistory("ecb", "EUR", []*ExchangeRate{
+		{DataSource: "ecb", BaseCurrency: "EUR", Currency: "USD", Date: 20240101, Rate: "1.1"},
+	})
+	fallbackHistory := NewExchangeRateHistory("fallback", "CAD", []*ExchangeRate{
+		{DataSource: "fallback", BaseCurrency: "CAD", Currency: "TWD", Date: 20240101, Rate: "23"},
+	})
+
+	assert.False(t, history.MergeMissingCurrencies(fallbackHistory))
+	assert.Equal(t, []string{"USD"}, history.GetCurrencies())
+}
+
+func TestExchangeRateAmountConverterConvertAccountAmount(t *testing.T) {
+	history := NewExchangeRateHistory("ecb", "EUR", []*ExchangeRate{
+		{DataSource: "ecb", BaseCurrency: "EUR", Currency: "USD", Date: 20240101, Rate: "1.25"},
+		{DataSource: "ecb", BaseCurrency: "EUR", Currency: "USD", Date: 20240105, Rate: "1.6"},
+		{DataSource: "ecb", BaseCurrency: "EUR", Currency: "JPY", Date: 20240101, Rate: "160"},
+	})
+	accounts := map[int64]*Account{
+		1: {AccountId: 1, Currency: "USD"},
+		2: {AccountId: 2, Currency: "EUR"},
+		3: {AccountId: 3, Currency: "JPY"},
+	}
+	converter := NewExchangeRateAmountConverter(history, "EUR", 0, accounts, utils.ROUNDING_MODE_HALF_UP)
+
+	actualAmount, err := converter.ConvertAccountAmount(0, 1, 1000, 20240103)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(800), actualAmount)
+
+	actualAmount, err = converter.ConvertAccountAmount(0, 1, 1000, 20240106)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(625), actualAmount)
+
+	actualAmount, err = converter.ConvertAccountAmount(0, 2, 1000, 20240103)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1000), actualAmount)
+
+	actualAmount, err = converter.ConvertAccountAmount(1, 3, 16000, 20240103)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(100), actualAmount)
+
+	_, err = converter.ConvertAccountAmount(0, 4, 1000, 20240103)
+	assert.Equal(t, errs.ErrAccountNotFound, err)
+
+	conversionInfos := converter.GetConversionInfos(0)
+	assert.Equal(t, 1, len(conversionInfos))
+	assert.Equal(t, "USD", conversionInfos[0].Currency)
+	assert.Equal(t, "2024-01-01", conversionInfos[0].MinRateDate)
+	assert.Equal(t, "2024-01-05", conversionInfos[0].MaxRateDate)
+
+	conversionInfos = converter.GetConversionInfos(1)
+	assert.Equal(t, 1, len(conversionInfos))
+	assert.Equal(t, "JPY", conversionInfos[0].Currency)
+}
+
+func TestExchangeRateAmountConverterConvertAccountAmount_EarlierThanAllExchangeRates(t *testing.T) {
+	history := NewExchangeRateHistory("ecb", "EUR", []*ExchangeRate{
+		{DataSource: "ecb", BaseCurrency: "EUR", Currency: "USD", Date: 20240105, Rate: "1.25"},
+	})
+	accounts := map[int64]*Account{
+		1: {AccountId: 1, Currency: "USD"},
+		2: {AccountId: 2, Currency: "GBP"},
+	}
+	converter := NewExchangeRateAmountConverter(history, "EUR", 0, accounts, utils.ROUNDING_MODE_HALF_UP)
+
+	actualAmount, err := converter.ConvertAccountAmount(0, 1, 1000, 20230101)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(800), actualAmount)
+
+	conversionInfos := converter.GetConversionInfos(0)
+	assert.Equal(t, 1, len(conversionInfos))
+	assert.Equal(t, "2024-01-05", conversionInfos[0].MinRateDate)
+	assert.Equal(t, "2024-01-05", conversionInfos[0].MaxRateDate)
+
+	_, err = converter.ConvertAccountAmount(0, 2, 1000, 20240105)
+	assert.Equal(t, errs.ErrExchangeRateNotFound, err)
+}
+
+func TestExchangeRateAmountConverterConvertAccountAmount_FixedDateAndRoundingMode(t *testing.T) {
+	history := NewExchangeRateHistory("ecb", "EUR", []*ExchangeRate{
+		{DataSource: "ecb", BaseCurrency: "EUR", Currency: "USD", Date: 20240101, Rate: "3"},
+		{DataSource: "ecb", BaseCurrency: "EUR", Currency: "USD", Date: 20240105, Rate: "1.5"},
+	})
+	accounts := map[int64]*Account{
+		1: {AccountId: 1, Currency: "USD"},
+	}
+
+	converter := NewExchangeRateAmountConverter(history, "EUR", 20240101, accounts, utils.ROUNDING_MODE_HALF_UP)
+	actualAmount, err := converter.ConvertAccountAmount(0, 1, 1000, 20240106)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(333), actualAmount)
+
+	converter = NewExchangeRateAmountConverter(history, "EUR", 20240101, accounts, utils.ROUNDING_MODE_UP)
+	actualAmount, err = converter.ConvertAccountAmount(0, 1, 1000, 20240106)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(334), actualAmount)
+}
@@ -0,0 +1,80 @@
+package models
+
+// InvitationCodeLength represents the length of generated invitation code
+const InvitationCodeLength = 16
+
+// InvitationCodeMaxUses represents the maximum times an invitation code can be used
+const InvitationCodeMaxUses = 10000
+
+// InvitationCodeMaxExpiresInDays represents the maximum days before an invitation code expires
+const InvitationCodeMaxExpiresInDays = 365
+
+// InvitationCode represents invitation code data stored in database, which is required for registering when registration requires invitation code
+type InvitationCode struct {
+	CodeId          int64  `xorm:"PK"`
+	Code            string `xorm:"VARCHAR(32) UNIQUE NOT NULL"`
+	CreatorUid      int64  `xorm:"NOT NULL"`
+	BoundEmail      string `xorm:"VARCHAR(100)"`
+	MaxUses         uint32 `xorm:"NOT NULL"`
+	UsedCount       uint32 `xorm:"NOT NULL"`
+	Deleted         bool   `xorm:"NOT NULL"`
+	ExpiredUnixTime int64  `xorm:"NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
+
+// InvitationCodeListRequest represents all parameters of invitation code listing request
+type InvitationCodeListRequest struct {
+	MaxId int64 `form:"max_id,string" binding:"min=0"`
+	Count int32 `form:"count" binding:"required,min=1,max=50"`
+}
+
+// InvitationCodeCreateRequest represents all parameters of invitation code creation request
+type InvitationCodeCreateRequest struct {
+	MaxUses       uint32 `json:"maxUses" binding:"required,min=1,max=10000"`
+	ExpiresInDays uint32 `json:"expiresInDays" binding:"required,min=1,max=365"`
+	BoundEmail    string `json:"boundEmail" binding:"omitempty,max=100,validEmail"`
+}
+
+// InvitationCodeRevokeRequest represents all parameters of invitation code revoking request
+type InvitationCodeRevokeRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// InvitationCodeInfoResponse represents a view-object of invitation code
+type InvitationCodeInfoResponse struct {
+	Id         int64  `json:"id,string"`
+	Code       string `json:"code"`
+	CreatorUid int64  `json:"creatorUid,string"`
+	BoundEmail string `json:"boundEmail,omitempty"`
+	MaxUses    uint32 `json:"maxUses"`
+	UsedCount  uint32 `json:"usedCount"`
+	ExpiredAt  int64  `json:"expiredAt"`
+	CreatedAt  int64  `json:"createdAt"`
+}
+
+// InvitationCodeInfoPageWrapperResponse represents a response of invitation codes which contains items and next id
+type InvitationCodeInfoPageWrapperResponse struct {
+	Items     []*InvitationCodeInfoResponse `json:"items"`
+	NextMaxId *int64                        `json:"nextMaxId,string"`
+}
+
+// IsAvailable returns whether the invitation code can be used for registering at the specified time
+func (i *InvitationCode) IsAvailable(now int64) bool {
+	return !i.Deleted && i.UsedCount < i.MaxUses && now < i.ExpiredUnixTime
+}
+
+// ToInvitationCodeInfoResponse returns a view-object according to database model
+func (i *InvitationCode) ToInvitationCodeInfoResponse() *InvitationCodeInfoResponse {
+	return &InvitationCodeInfoResponse{
+		Id:         i.CodeId,
+		Code:       i.Code,
+		CreatorUid: i.CreatorUid,
+		BoundEmail: i.BoundEmail,
+		MaxUses:    i.MaxUses,
+		UsedCount:  i.UsedCount,
+		ExpiredAt:  i.ExpiredUnixTime,
+		CreatedAt:  i.CreatedUnixTime,
+	}
+}
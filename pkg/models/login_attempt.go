@@ -0,0 +1,37 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/utils"
+)
+
+const (
+	loginAttemptKeyPrefixIp        = "ip:"
+	loginAttemptKeyPrefixUid       = "uid:"
+	loginAttemptKeyPrefixLoginName = "name:"
+)
+
+// LoginAttempt represents the consecutive failed login attempts of an ip address or an account which is stored in database
+type LoginAttempt struct {
+	AttemptKey          string `xorm:"VARCHAR(64) PK"`
+	FailedCount         uint32 `xorm:"NOT NULL"`
+	LastFailedUnixTime  int64  `xorm:"NOT NULL"`
+	LockedUntilUnixTime int64  `xorm:"NOT NULL"`
+}
+
+// GetLoginAttemptKeyByIp returns the login attempt key of the specified ip address
+func GetLoginAttemptKeyByIp(ip string) string {
+	return loginAttemptKeyPrefixIp + ip
+}
+
+// GetLoginAttemptKeyByUid returns the login attempt key of the specified user
+func GetLoginAttemptKeyByUid(uid int64) string {
+	return loginAttemptKeyPrefixUid + strconv.FormatInt(uid, 10)
+}
+
+// GetLoginAttemptKeyByLoginName returns the login attempt key of the specified login name which does not belong to any user
+func GetLoginAttemptKeyByLoginName(loginName string) string {
+	return loginAttemptKeyPrefixLoginName + utils.MD5EncodeToString([]byte(strings.ToLower(loginName)))
+}
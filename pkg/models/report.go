@@ -0,0 +1,252 @@
+package models
+
+// ReportIncomeStatementRequest represents all parameters of income statement report request
+type ReportIncomeStatementRequest struct {
+	StartTime              int64 `form:"start_time" binding:"min=0"`
+	EndTime                int64 `form:"end_time" binding:"min=0"`
+	UseTransactionTimezone bool  `form:"use_transaction_timezone"`
+	ExchangeRateConversionRequest
+}
+
+// ReportBalanceSheetRequest represents all parameters of balance sheet report request
+type ReportBalanceSheetRequest struct {
+	Time int64 `form:"time" binding:"min=0"`
+	ExchangeRateConversionRequest
+}
+
+// ReportCashFlowForecastRequest represents all parameters of cash flow forecast report request
+type ReportCashFlowForecastRequest struct {
+	Months              int32 `form:"months,default=3" binding:"min=1,max=24"`
+	HistoryMonths       int32 `form:"history_months,default=3" binding:"min=1,max=12"`
+	LowBalanceThreshold int64 `form:"low_balance_threshold"`
+	ExchangeRateConversionRequest
+}
+
+// ReportExchangeGainLossRequest represents all parameters of exchange gain and loss report request
+type ReportExchangeGainLossRequest struct {
+	StartTime      int64  `form:"start_time" binding:"min=0"`
+	EndTime        int64  `form:"end_time" binding:"min=0"`
+	TargetCurrency string `form:"target_currency" binding:"omitempty,len=3,validCurrency"`
+}
+
+// ReportIncomeStatementResponse represents a view-object of income statement report
+type ReportIncomeStatementResponse struct {
+	StartTime     int64                         `json:"startTime"`
+	EndTime       int64                         `json:"endTime"`
+	Currency      string                        `json:"currency"`
+	Income        *ReportCategorySection        `json:"income"`
+	Expense       *ReportCategorySection        `json:"expense"`
+	NetIncome     int64                         `json:"netIncome"`
+	ExchangeRates []*ExchangeRateConversionInfo `json:"exchangeRates,omitempty"`
+}
+
+// ReportCategorySection represents a view-object of all categories with the same category type in income statement report
+type ReportCategorySection struct {
+	Items ReportCategoryItemSlice `json:"items"`
+	Total int64                   `json:"total"`
+}
+
+// ReportCategoryItem represents a view-object of category and its sub categories in income statement report
+type ReportCategoryItem struct {
+	CategoryId    int64                   `json:"categoryId,string"`
+	Name          string                  `json:"name"`
+	DisplayOrder  int32                   `json:"-"`
+	Amount        int64                   `json:"amount"`
+	SubCategories ReportCategoryItemSlice `json:"subCategories,omitempty"`
+}
+
+// ReportBalanceSheetResponse represents a view-object of balance sheet report
+type ReportBalanceSheetResponse struct {
+	Time          int64                         `json:"time"`
+	Currency      string                        `json:"currency"`
+	Assets        *ReportAccountSection         `json:"assets"`
+	Liabilities   *ReportAccountSection         `json:"liabilities"`
+	NetEquity     int64                         `json:"netEquity"`
+	ExchangeRates []*ExchangeRateConversionInfo `json:"exchangeRates,omitempty"`
+}
+
+// ReportAccountSection represents a view-object of all asset accounts or all liability accounts in balance sheet report
+type ReportAccountSection struct {
+	Groups []*ReportAccountGroup `json:"groups"`
+	Total  int64                 `json:"total"`
+}
+
+// ReportAccountGroup represents a view-object of accounts with the same account category in balance sheet report
+type ReportAccountGroup struct {
+	Category AccountCategory         `json:"category"`
+	Accounts []*ReportAccountBalance `json:"accounts"`
+	Subtotal int64                   `json:"subtotal"`
+}
+
+// ReportAccountBalance represents a view-object of account balance in balance sheet report,
+// the amount of liability account is the amount owed which is positive when the account is in debt
+type ReportAccountBalance struct {
+	AccountId int64  `json:"accountId,string"`
+	Name      string `json:"name"`
+	Currency  string `json:"currency"`
+	Balance   int64  `json:"balance"`
+	Amount    int64  `json:"amount"`
+}
+
+// ReportCashFlowForecastResponse represents a view-object of cash flow forecast report
+type ReportCashFlowForecastResponse struct {
+	StartDate           string                                   `json:"startDate"`
+	EndDate             string                                   `json:"endDate"`
+	Currency            string                                   `json:"currency"`
+	LowBalanceThreshold int64                                    `json:"lowBalanceThreshold"`
+	LowBalanceDate      string                                   `json:"lowBalanceDate,omitempty"`
+	Balances            []*ReportCashFlowForecastDailyBalance    `json:"balances"`
+	Accounts            []*ReportCashFlowForecastAccount         `json:"accounts"`
+	RecurringItems      ReportCashFlowForecastRecurringItemSlice `json:"recurringItems"`
+	VariableItems       ReportCashFlowForecastVariableItemSlice  `json:"variableItems"`
+	ExchangeRates       []*ExchangeRateConversionInfo            `json:"exchangeRates,omitempty"`
+}
+
+// ReportCashFlowForecastAccount represents a view-object of projected balances of one account in cash flow forecast report,
+// the low balance date is the first date which the projected balance of asset account is below zero
+type ReportCashFlowForecastAccount struct {
+	AccountId      int64                                 `json:"accountId,string"`
+	Currency       string                                `json:"currency"`
+	LowBalanceDate string                                `json:"lowBalanceDate,omitempty"`
+	Balances       []*ReportCashFlowForecastDailyBalance `json:"balances"`
+}
+
+// ReportCashFlowForecastDailyBalance represents a view-object of projected balance at the end of one day
+type ReportCashFlowForecastDailyBalance struct {
+	Date    string `json:"date"`
+	Balance int64  `json:"balance"`
+}
+
+// ReportCashFlowForecastRecurringItem represents a view-object of income or expense which is detected to be repeated every month
+type ReportCashFlowForecastRecurringItem struct {
+	AccountId  int64           `json:"accountId,string"`
+	CategoryId int64           `json:"categoryId,string"`
+	Type       TransactionType `json:"type"`
+	Amount     int64           `json:"amount"`
+	DayOfMonth int32           `json:"dayOfMonth"`
+}
+
+// ReportCashFlowForecastVariableItem represents a view-object of income or expense of one category which is not repeated every month,
+// the amount is estimated in the forecast period by the trailing average of the history amount
+type ReportCashFlowForecastVariableItem struct {
+	AccountId     int64           `json:"accountId,string"`
+	CategoryId    int64           `json:"categoryId,string"`
+	Type          TransactionType `json:"type"`
+	HistoryAmount int64           `json:"historyAmount"`
+	Amount        int64           `json:"amount"`
+}
+
+// ReportExchangeGainLossResponse represents a view-object of exchange gain and loss report,
+// the costs of transfers are realized losses and the gains or losses of account balances are unrealized
+type ReportExchangeGainLossResponse struct {
+	StartTime               int64                                      `json:"startTime"`
+	EndTime                 int64                                      `json:"endTime"`
+	Currency                string                                     `json:"currency"`
+	Transfers               []*ReportExchangeTransferCost              `json:"transfers"`
+	TotalTransferCost       int64                                      `json:"totalTransferCost"`
+	Accounts                []*ReportExchangeAccountUnrealizedGainLoss `json:"accounts"`
+	TotalUnrealizedGainLoss int64                                      `json:"totalUnrealizedGainLoss"`
+}
+
+// ReportExchangeTransferCost represents a view-object of the cost of one cross-currency transfer in exchange gain and loss report,
+// the executed rate is implied by the amounts of transfer, and the cost is the fees and exchange margin paid which is the difference
+// between the destination amount at reference rate and the actual destination amount
+type ReportExchangeTransferCost struct {
+	TransactionId        int64  `json:"transactionId,string"`
+	Time                 int64  `json:"time"`
+	SourceAccountId      int64  `json:"sourceAccountId,string"`
+	SourceCurrency       string `json:"sourceCurrency"`
+	SourceAmount         int64  `json:"sourceAmount"`
+	DestinationAccountId int64  `json:"destinationAccountId,string"`
+	DestinationCurrency  string `json:"destinationCurrency"`
+	DestinationAmount    int64  `json:"destinationAmount"`
+	ExecutedRate         string `json:"executedRate"`
+	ReferenceRate        string `json:"referenceRate"`
+	ReferenceAmount      int64  `json:"referenceAmount"`
+	Cost                 int64  `json:"cost"`
+	Amount               int64  `json:"amount"`
+}
+
+// ReportExchangeAccountUnrealizedGainLoss represents a view-object of unrealized gain or loss of one foreign-currency account in exchange gain and loss report,
+// the gain or loss is the change of balance value in target currency which is not caused by the transactions in this period
+type ReportExchangeAccountUnrealizedGainLoss struct {
+	AccountId     int64  `json:"accountId,string"`
+	Name          string `json:"name"`
+	Currency      string `json:"currency"`
+	StartBalance  int64  `json:"startBalance"`
+	EndBalance    int64  `json:"endBalance"`
+	StartAmount   int64  `json:"startAmount"`
+	EndAmount     int64  `json:"endAmount"`
+	NetFlowAmount int64  `json:"netFlowAmount"`
+	GainLoss      int64  `json:"gainLoss"`
+}
+
+// ReportCategoryItemSlice represents the slice data structure of ReportCategoryItem
+type ReportCategoryItemSlice []*ReportCategoryItem
+
+// Len returns the count of items
+func (s ReportCategoryItemSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s ReportCategoryItemSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s ReportCategoryItemSlice) Less(i, j int) bool {
+	return s[i].DisplayOrder < s[j].DisplayOrder
+}
+
+// ReportCashFlowForecastRecurringItemSlice represents the slice data structure of ReportCashFlowForecastRecurringItem
+type ReportCashFlowForecastRecurringItemSlice []*ReportCashFlowForecastRecurringItem
+
+// Len returns the count of items
+func (s ReportCashFlowForecastRecurringItemSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s ReportCashFlowForecastRecurringItemSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s ReportCashFlowForecastRecurringItemSlice) Less(i, j int) bool {
+	if s[i].DayOfMonth != s[j].DayOfMonth {
+		return s[i].DayOfMonth < s[j].DayOfMonth
+	}
+
+	if s[i].AccountId != s[j].AccountId {
+		return s[i].AccountId < s[j].AccountId
+	}
+
+	return s[i].CategoryId < s[j].CategoryId
+}
+
+// ReportCashFlowForecastVariableItemSlice represents the slice data structure of ReportCashFlowForecastVariableItem
+type ReportCashFlowForecastVariableItemSlice []*ReportCashFlowForecastVariableItem
+
+// Len returns the count of items
+func (s ReportCashFlowForecastVariableItemSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s ReportCashFlowForecastVariableItemSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s ReportCashFlowForecastVariableItemSlice) Less(i, j int) bool {
+	if s[i].AccountId != s[j].AccountId {
+		return s[i].AccountId < s[j].AccountId
+	}
+
+	if s[i].CategoryId != s[j].CategoryId {
+		return s[i].CategoryId < s[j].CategoryId
+	}
+
+	return s[i].Type < s[j].Type
+}
@@ -1,10 +1,17 @@
 package models
 
-import "github.com/kyy-me/ezbookkeeping/pkg/core"
+import (
+	"strings"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+)
 
 // TokenMaxUserAgentLength represents the maximum size of user agent stored in database
 const TokenMaxUserAgentLength = 255
 
+// TokenScopesSeparator represents the separator of scopes of personal access token stored in database
+const TokenScopesSeparator = ","
+
 // TokenRecord represents token data stored in database
 type TokenRecord struct {
 	Uid             int64          `xorm:"PK INDEX(IDX_token_record_uid_type_expired_time)"`
@@ -12,6 +19,8 @@ type TokenRecord struct {
 	TokenType       core.TokenType `xorm:"INDEX(IDX_token_record_uid_type_expired_time) TINYINT NOT NULL"`
 	Secret          string         `xorm:"VARCHAR(10) NOT NULL"`
 	UserAgent       string         `xorm:"VARCHAR(255)"`
+	Name            string         `xorm:"VARCHAR(64)"`
+	Scopes          string         `xorm:"VARCHAR(255)"`
 	CreatedUnixTime int64          `xorm:"PK"`
 	ExpiredUnixTime int64          `xorm:"INDEX(IDX_token_record_uid_type_expired_time)"`
 }
@@ -21,6 +30,25 @@ type TokenRevokeRequest struct {
 	TokenId string `json:"tokenId" binding:"required,notBlank"`
 }
 
+// PersonalAccessTokenCreateRequest represents all parameters of personal access token creation request,
+// the token would never expire if expires in days is zero
+type PersonalAccessTokenCreateRequest struct {
+	Name          string            `json:"name" binding:"required,notBlank,max=64"`
+	Scopes        []core.TokenScope `json:"scopes" binding:"required,min=1"`
+	ExpiresInDays uint32            `json:"expiresInDays" binding:"max=3650"`
+}
+
+// PersonalAccessTokenCreateResponse represents a view-object of the created personal access token,
+// the textual token is returned only once
+type PersonalAccessTokenCreateResponse struct {
+	Token     string            `json:"token"`
+	TokenId   string            `json:"tokenId"`
+	Name      string            `json:"name"`
+	Scopes    []core.TokenScope `json:"scopes"`
+	CreatedAt int64             `json:"createdAt"`
+	ExpiredAt int64             `json:"expiredAt"`
+}
+
 // TokenRefreshResponse represents all parameters of token refreshing request
 type TokenRefreshResponse struct {
 	NewToken   string         `json:"newToken"`
@@ -30,12 +58,30 @@ type TokenRefreshResponse struct {
 
 // TokenInfoResponse represents a view-object of token
 type TokenInfoResponse struct {
-	TokenId   string         `json:"tokenId"`
-	TokenType core.TokenType `json:"tokenType"`
-	UserAgent string         `json:"userAgent"`
-	CreatedAt int64          `json:"createdAt"`
-	ExpiredAt int64          `json:"expiredAt"`
-	IsCurrent bool           `json:"isCurrent"`
+	TokenId   string            `json:"tokenId"`
+	TokenType core.TokenType    `json:"tokenType"`
+	UserAgent string            `json:"userAgent"`
+	Name      string            `json:"name,omitempty"`
+	Scopes    []core.TokenScope `json:"scopes,omitempty"`
+	CreatedAt int64             `json:"createdAt"`
+	ExpiredAt int64             `json:"expiredAt"`
+	IsCurrent bool              `json:"isCurrent"`
+}
+
+// GetScopes returns the scopes of personal access token
+func (t *TokenRecord) GetScopes() []core.TokenScope {
+	if t.Scopes == "" {
+		return nil
+	}
+
+	items := strings.Split(t.Scopes, TokenScopesSeparator)
+	scopes := make([]core.TokenScope, 0, len(items))
+
+	for i := 0; i < len(items); i++ {
+		scopes = append(scopes, core.TokenScope(items[i]))
+	}
+
+	return scopes
 }
 
 // TokenInfoResponseSlice represents the slice data structure of TokenInfoResponse
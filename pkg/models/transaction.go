@@ -2,6 +2,7 @@ package models
 
 import (
 	"strings"
+	"time"
 
 	"github.com/kyy-me/ezbookkeeping/pkg/errs"
 	"github.com/kyy-me/ezbookkeeping/pkg/utils"
@@ -68,8 +69,8 @@ type TransactionCreateRequest struct {
 	UtcOffset            int16                          `json:"utcOffset" binding:"min=-720,max=840"`
 	SourceAccountId      int64                          `json:"sourceAccountId,string" binding:"required,min=1"`
 	DestinationAccountId int64                          `json:"destinationAccountId,string" binding:"min=0"`
-	SourceAmount         int64                          `json:"sourceAmount" binding:"min=-99999999999,max=99999999999"`
-	DestinationAmount    int64                          `json:"destinationAmount" binding:"min=-99999999999,max=99999999999"`
+	SourceAmount         int64                          `json:"sourceAmount" binding:"min=-999999999999999,max=999999999999999"`
+	DestinationAmount    int64                          `json:"destinationAmount" binding:"min=-999999999999999,max=999999999999999"`
 	HideAmount           bool                           `json:"hideAmount"`
 	TagIds               []string                       `json:"tagIds"`
 	Comment              string                         `json:"comment" binding:"max=255"`
@@ -84,8 +85,8 @@ type TransactionModifyRequest struct {
 	UtcOffset            int16                          `json:"utcOffset" binding:"min=-720,max=840"`
 	SourceAccountId      int64                          `json:"sourceAccountId,string" binding:"required,min=1"`
 	DestinationAccountId int64                          `json:"destinationAccountId,string" binding:"min=0"`
-	SourceAmount         int64                          `json:"sourceAmount" binding:"min=-99999999999,max=99999999999"`
-	DestinationAmount    int64                          `json:"destinationAmount" binding:"min=-99999999999,max=99999999999"`
+	SourceAmount         int64                          `json:"sourceAmount" binding:"min=-999999999999999,max=999999999999999"`
+	DestinationAmount    int64                          `json:"destinationAmount" binding:"min=-999999999999999,max=999999999999999"`
 	HideAmount           bool                           `json:"hideAmount"`
 	TagIds               []string                       `json:"tagIds"`
 	Comment              string                         `json:"comment" binding:"max=255"`
@@ -105,33 +106,35 @@ type TransactionCountRequest struct {
 
 // TransactionListByMaxTimeRequest represents all parameters of transaction listing by max time request
 type TransactionListByMaxTimeRequest struct {
-	Type         TransactionDbType `form:"type" binding:"min=0,max=4"`
-	CategoryId   int64             `form:"category_id" binding:"min=0"`
-	AccountId    int64             `form:"account_id" binding:"min=0"`
-	AmountFilter string            `form:"amount_filter" binding:"validAmountFilter"`
-	Keyword      string            `form:"keyword"`
-	MaxTime      int64             `form:"max_time" binding:"min=0"`
-	MinTime      int64             `form:"min_time" binding:"min=0"`
-	Page         int32             `form:"page" binding:"min=0"`
-	Count        int32             `form:"count" binding:"required,min=1,max=50"`
-	WithCount    bool              `form:"with_count"`
-	TrimAccount  bool              `form:"trim_account"`
-	TrimCategory bool              `form:"trim_category"`
-	TrimTag      bool              `form:"trim_tag"`
+	Type                TransactionDbType `form:"type" binding:"min=0,max=4"`
+	CategoryId          int64             `form:"category_id" binding:"min=0"`
+	AccountId           int64             `form:"account_id" binding:"min=0"`
+	AmountFilter        string            `form:"amount_filter" binding:"validAmountFilter"`
+	Keyword             string            `form:"keyword"`
+	MaxTime             int64             `form:"max_time" binding:"min=0"`
+	MinTime             int64             `form:"min_time" binding:"min=0"`
+	Page                int32             `form:"page" binding:"min=0"`
+	Count               int32             `form:"count" binding:"required,min=1,max=50"`
+	WithCount           bool              `form:"with_count"`
+	TrimAccount         bool              `form:"trim_account"`
+	TrimCategory        bool              `form:"trim_category"`
+	TrimTag             bool              `form:"trim_tag"`
+	WithConvertedAmount bool              `form:"with_converted_amount"`
 }
 
 // TransactionListInMonthByPageRequest represents all parameters of transaction listing by month request
 type TransactionListInMonthByPageRequest struct {
-	Year         int32             `form:"year" binding:"required,min=1"`
-	Month        int32             `form:"month" binding:"required,min=1"`
-	Type         TransactionDbType `form:"type" binding:"min=0,max=4"`
-	CategoryId   int64             `form:"category_id" binding:"min=0"`
-	AccountId    int64             `form:"account_id" binding:"min=0"`
-	AmountFilter string            `form:"amount_filter" binding:"validAmountFilter"`
-	Keyword      string            `form:"keyword"`
-	TrimAccount  bool              `form:"trim_account"`
-	TrimCategory bool              `form:"trim_category"`
-	TrimTag      bool              `form:"trim_tag"`
+	Year                int32             `form:"year" binding:"required,min=1"`
+	Month               int32             `form:"month" binding:"required,min=1"`
+	Type                TransactionDbType `form:"type" binding:"min=0,max=4"`
+	CategoryId          int64             `form:"category_id" binding:"min=0"`
+	AccountId           int64             `form:"account_id" binding:"min=0"`
+	AmountFilter        string            `form:"amount_filter" binding:"validAmountFilter"`
+	Keyword             string            `form:"keyword"`
+	TrimAccount         bool              `form:"trim_account"`
+	TrimCategory        bool              `form:"trim_category"`
+	TrimTag             bool              `form:"trim_tag"`
+	WithConvertedAmount bool              `form:"with_converted_amount"`
 }
 
 // TransactionStatisticRequest represents all parameters of transaction statistic request
@@ -139,18 +142,54 @@ type TransactionStatisticRequest struct {
 	StartTime              int64 `form:"start_time" binding:"min=0"`
 	EndTime                int64 `form:"end_time" binding:"min=0"`
 	UseTransactionTimezone bool  `form:"use_transaction_timezone"`
+	ExchangeRateConversionRequest
+}
+
+// TransactionGeoStatisticRequest represents all parameters of transaction geographic location statistic request
+type TransactionGeoStatisticRequest struct {
+	StartTime    int64   `form:"start_time" binding:"min=0"`
+	EndTime      int64   `form:"end_time" binding:"min=0"`
+	MinLongitude float64 `form:"min_longitude" binding:"min=-180,max=180"`
+	MaxLongitude float64 `form:"max_longitude" binding:"min=-180,max=180"`
+	MinLatitude  float64 `form:"min_latitude" binding:"min=-90,max=90"`
+	MaxLatitude  float64 `form:"max_latitude" binding:"min=-90,max=90,gtefield=MinLatitude"`
+	GridSize     int32   `form:"grid_size,default=16" binding:"min=1,max=64"`
+	ExchangeRateConversionRequest
+}
+
+// TransactionStatisticTrendsAggregationType represents the period type of every item in transaction statistic trends
+type TransactionStatisticTrendsAggregationType byte
+
+// Transaction statistic trends aggregation types
+const (
+	TRANSACTION_STATISTIC_TRENDS_AGGREGATION_TYPE_MONTH   TransactionStatisticTrendsAggregationType = 0
+	TRANSACTION_STATISTIC_TRENDS_AGGREGATION_TYPE_DAY     TransactionStatisticTrendsAggregationType = 1
+	TRANSACTION_STATISTIC_TRENDS_AGGREGATION_TYPE_WEEK    TransactionStatisticTrendsAggregationType = 2
+	TRANSACTION_STATISTIC_TRENDS_AGGREGATION_TYPE_QUARTER TransactionStatisticTrendsAggregationType = 3
+	TRANSACTION_STATISTIC_TRENDS_AGGREGATION_TYPE_YEAR    TransactionStatisticTrendsAggregationType = 4
+)
+
+// TransactionStatisticTrendsPeriod represents how to divide transactions into periods in transaction statistic trends
+type TransactionStatisticTrendsPeriod struct {
+	AggregationType      TransactionStatisticTrendsAggregationType
+	FirstDayOfWeek       WeekDay
+	FiscalYearStartMonth uint8
+	MonthStartDay        uint8
 }
 
 // TransactionStatisticTrendsRequest represents all parameters of transaction statistic trends request
 type TransactionStatisticTrendsRequest struct {
 	YearMonthRangeRequest
-	UseTransactionTimezone bool `form:"use_transaction_timezone"`
+	AggregationType        TransactionStatisticTrendsAggregationType `form:"aggregation_type" binding:"min=0,max=4"`
+	UseTransactionTimezone bool                                      `form:"use_transaction_timezone"`
+	ExchangeRateConversionRequest
 }
 
 // TransactionAmountsRequest represents all parameters of transaction amounts request
 type TransactionAmountsRequest struct {
 	Query                  string `form:"query"`
 	UseTransactionTimezone bool   `form:"use_transaction_timezone"`
+	ExchangeRateConversionRequest
 }
 
 // TransactionAmountsRequestItem represents an item of transaction amounts request
@@ -162,10 +201,11 @@ type TransactionAmountsRequestItem struct {
 
 // TransactionGetRequest represents all parameters of transaction getting request
 type TransactionGetRequest struct {
-	Id           int64 `form:"id,string" binding:"required,min=1"`
-	TrimAccount  bool  `form:"trim_account"`
-	TrimCategory bool  `form:"trim_category"`
-	TrimTag      bool  `form:"trim_tag"`
+	Id                  int64 `form:"id,string" binding:"required,min=1"`
+	TrimAccount         bool  `form:"trim_account"`
+	TrimCategory        bool  `form:"trim_category"`
+	TrimTag             bool  `form:"trim_tag"`
+	WithConvertedAmount bool  `form:"with_converted_amount"`
 }
 
 // TransactionDeleteRequest represents all parameters of transaction deleting request
@@ -200,6 +240,8 @@ type TransactionInfoResponse struct {
 	DestinationAccount   *AccountInfoResponse             `json:"destinationAccount,omitempty"`
 	SourceAmount         int64                            `json:"sourceAmount"`
 	DestinationAmount    int64                            `json:"destinationAmount,omitempty"`
+	ConvertedAmount      *int64                           `json:"convertedAmount,omitempty"`
+	ConvertedCurrency    string                           `json:"convertedCurrency,omitempty"`
 	HideAmount           bool                             `json:"hideAmount"`
 	TagIds               []string                         `json:"tagIds"`
 	Tags                 []*TransactionTagInfoResponse    `json:"tags,omitempty"`
@@ -228,9 +270,11 @@ type TransactionInfoPageWrapperResponse2 struct {
 
 // TransactionStatisticResponse represents transaction statistic response
 type TransactionStatisticResponse struct {
-	StartTime int64                               `json:"startTime"`
-	EndTime   int64                               `json:"endTime"`
-	Items     []*TransactionStatisticResponseItem `json:"items"`
+	StartTime     int64                               `json:"startTime"`
+	EndTime       int64                               `json:"endTime"`
+	Currency      string                              `json:"currency,omitempty"`
+	ExchangeRates []*ExchangeRateConversionInfo       `json:"exchangeRates,omitempty"`
+	Items         []*TransactionStatisticResponseItem `json:"items"`
 }
 
 // TransactionStatisticResponseItem represents total amount item for an response
@@ -240,18 +284,57 @@ type TransactionStatisticResponseItem struct {
 	TotalAmount int64 `json:"amount"`
 }
 
-// TransactionStatisticTrendsItem represents the data within each statistic interval
+// TransactionTagStatisticResponse represents transaction statistic response grouped by tag
+type TransactionTagStatisticResponse struct {
+	StartTime     int64                                    `json:"startTime"`
+	EndTime       int64                                    `json:"endTime"`
+	Currency      string                                   `json:"currency,omitempty"`
+	ExchangeRates []*ExchangeRateConversionInfo            `json:"exchangeRates,omitempty"`
+	Items         TransactionTagStatisticResponseItemSlice `json:"items"`
+}
+
+// TransactionTagStatisticResponseItem represents total amount item of one tag for an response, the tag id of untagged transactions is zero
+type TransactionTagStatisticResponseItem struct {
+	TagId       int64           `json:"tagId,string"`
+	AccountId   int64           `json:"accountId,string"`
+	Type        TransactionType `json:"type"`
+	TotalAmount int64           `json:"amount"`
+}
+
+// TransactionGeoStatisticResponse represents transaction geographic location statistic response
+type TransactionGeoStatisticResponse struct {
+	StartTime     int64                              `json:"startTime"`
+	EndTime       int64                              `json:"endTime"`
+	Currency      string                             `json:"currency"`
+	ExchangeRates []*ExchangeRateConversionInfo      `json:"exchangeRates,omitempty"`
+	Clusters      TransactionGeoClusterResponseSlice `json:"clusters"`
+}
+
+// TransactionGeoClusterResponse represents the total expense amount of transactions nearby
+type TransactionGeoClusterResponse struct {
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	Count       int32   `json:"count"`
+	TotalAmount int64   `json:"amount"`
+}
+
+// TransactionStatisticTrendsItem represents the data within each statistic interval, the year, month and day are the first date of the interval
 type TransactionStatisticTrendsItem struct {
-	Year  int32                               `json:"year"`
-	Month int32                               `json:"month"`
-	Items []*TransactionStatisticResponseItem `json:"items"`
+	Year          int32                               `json:"year"`
+	Month         int32                               `json:"month"`
+	Day           int32                               `json:"day,omitempty"`
+	Quarter       int32                               `json:"quarter,omitempty"`
+	Currency      string                              `json:"currency,omitempty"`
+	ExchangeRates []*ExchangeRateConversionInfo       `json:"exchangeRates,omitempty"`
+	Items         []*TransactionStatisticResponseItem `json:"items"`
 }
 
 // TransactionAmountsResponseItem represents an item of transaction amounts
 type TransactionAmountsResponseItem struct {
-	StartTime int64                                       `json:"startTime"`
-	EndTime   int64                                       `json:"endTime"`
-	Amounts   []*TransactionAmountsResponseItemAmountInfo `json:"amounts"`
+	StartTime     int64                                       `json:"startTime"`
+	EndTime       int64                                       `json:"endTime"`
+	ExchangeRates []*ExchangeRateConversionInfo               `json:"exchangeRates,omitempty"`
+	Amounts       []*TransactionAmountsResponseItemAmountInfo `json:"amounts"`
 }
 
 // TransactionMonthAmountsResponseItem represents an item of transaction month amounts
@@ -410,6 +493,49 @@ func (t *YearMonthRangeRequest) GetNumericYearMonthRange() (int32, int32, int32,
 	return startYear, startMonth, endYear, endMonth, nil
 }
 
+// GetPeriodStartDate returns the numeric first date of the period which contains the specified local time
+func (p *TransactionStatisticTrendsPeriod) GetPeriodStartDate(localTime time.Time) int32 {
+	monthStartDay := int(p.MonthStartDay)
+
+	if monthStartDay < 1 {
+		monthStartDay = 1
+	}
+
+	fiscalYearStartMonth := time.Month(p.FiscalYearStartMonth)
+
+	if fiscalYearStartMonth < time.January {
+		fiscalYearStartMonth = time.January
+	}
+
+	switch p.AggregationType {
+	case TRANSACTION_STATISTIC_TRENDS_AGGREGATION_TYPE_DAY:
+		return utils.FormatTimeToNumericDate(utils.GetStartOfDay(localTime))
+	case TRANSACTION_STATISTIC_TRENDS_AGGREGATION_TYPE_WEEK:
+		return utils.FormatTimeToNumericDate(utils.GetStartOfWeek(localTime, time.Weekday(p.FirstDayOfWeek)))
+	case TRANSACTION_STATISTIC_TRENDS_AGGREGATION_TYPE_QUARTER:
+		return utils.FormatTimeToNumericDate(utils.GetStartOfQuarter(localTime, fiscalYearStartMonth, monthStartDay))
+	case TRANSACTION_STATISTIC_TRENDS_AGGREGATION_TYPE_YEAR:
+		return utils.FormatTimeToNumericDate(utils.GetStartOfYear(localTime, fiscalYearStartMonth, monthStartDay))
+	default:
+		return utils.FormatTimeToNumericDate(utils.GetStartOfMonth(localTime, monthStartDay))
+	}
+}
+
+// GetQuarter returns the quarter number of fiscal year of the period which starts on the specified numeric date
+func (p *TransactionStatisticTrendsPeriod) GetQuarter(periodStartDate int32) int32 {
+	if p.AggregationType != TRANSACTION_STATISTIC_TRENDS_AGGREGATION_TYPE_QUARTER {
+		return 0
+	}
+
+	fiscalYearStartMonth := int32(p.FiscalYearStartMonth)
+
+	if fiscalYearStartMonth < 1 {
+		fiscalYearStartMonth = 1
+	}
+
+	return (periodStartDate/100%100-fiscalYearStartMonth+12)%12/3 + 1
+}
+
 // TransactionInfoResponseSlice represents the slice data structure of TransactionInfoResponse
 type TransactionInfoResponseSlice []*TransactionInfoResponse
 
@@ -451,7 +577,11 @@ func (s TransactionStatisticTrendsItemSlice) Less(i, j int) bool {
 		return s[i].Year < s[j].Year
 	}
 
-	return s[i].Month < s[j].Month
+	if s[i].Month != s[j].Month {
+		return s[i].Month < s[j].Month
+	}
+
+	return s[i].Day < s[j].Day
 }
 
 // TransactionAmountsResponseItemAmountInfoSlice represents the slice data structure of TransactionAmountsResponseItemAmountInfo
@@ -471,3 +601,47 @@ func (s TransactionAmountsResponseItemAmountInfoSlice) Swap(i, j int) {
 func (s TransactionAmountsResponseItemAmountInfoSlice) Less(i, j int) bool {
 	return strings.Compare(s[i].Currency, s[j].Currency) < 0
 }
+
+// TransactionTagStatisticResponseItemSlice represents the slice data structure of TransactionTagStatisticResponseItem
+type TransactionTagStatisticResponseItemSlice []*TransactionTagStatisticResponseItem
+
+// Len returns the count of items
+func (s TransactionTagStatisticResponseItemSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s TransactionTagStatisticResponseItemSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s TransactionTagStatisticResponseItemSlice) Less(i, j int) bool {
+	if s[i].TagId != s[j].TagId {
+		return s[i].TagId < s[j].TagId
+	}
+
+	if s[i].Type != s[j].Type {
+		return s[i].Type < s[j].Type
+	}
+
+	return s[i].AccountId < s[j].AccountId
+}
+
+// TransactionGeoClusterResponseSlice represents the slice data structure of TransactionGeoClusterResponse
+type TransactionGeoClusterResponseSlice []*TransactionGeoClusterResponse
+
+// Len returns the count of items
+func (s TransactionGeoClusterResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s TransactionGeoClusterResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s TransactionGeoClusterResponseSlice) Less(i, j int) bool {
+	return s[i].TotalAmount > s[j].TotalAmount
+}
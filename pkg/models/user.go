@@ -49,32 +49,38 @@ func (s TransactionEditScope) String() string {
 
 // User represents user data stored in database
 type User struct {
-	Uid                  int64  `xorm:"PK"`
-	Username             string `xorm:"VARCHAR(32) UNIQUE NOT NULL"`
-	Email                string `xorm:"VARCHAR(100) UNIQUE NOT NULL"`
-	Nickname             string `xorm:"VARCHAR(64) NOT NULL"`
-	Password             string `xorm:"VARCHAR(64) NOT NULL"`
-	Salt                 string `xorm:"VARCHAR(10) NOT NULL"`
-	DefaultAccountId     int64
-	TransactionEditScope TransactionEditScope `xorm:"TINYINT NOT NULL"`
-	Language             string               `xorm:"VARCHAR(10)"`
-	DefaultCurrency      string               `xorm:"VARCHAR(3) NOT NULL"`
-	FirstDayOfWeek       WeekDay              `xorm:"TINYINT NOT NULL"`
-	LongDateFormat       LongDateFormat       `xorm:"TINYINT"`
-	ShortDateFormat      ShortDateFormat      `xorm:"TINYINT"`
-	LongTimeFormat       LongTimeFormat       `xorm:"TINYINT"`
-	ShortTimeFormat      ShortTimeFormat      `xorm:"TINYINT"`
-	DecimalSeparator     DecimalSeparator     `xorm:"TINYINT"`
-	DigitGroupingSymbol  DigitGroupingSymbol  `xorm:"TINYINT"`
-	DigitGrouping        DigitGroupingType    `xorm:"TINYINT"`
-	CurrencyDisplayType  CurrencyDisplayType  `xorm:"TINYINT"`
-	Disabled             bool
-	Deleted              bool `xorm:"NOT NULL"`
-	EmailVerified        bool `xorm:"NOT NULL"`
-	CreatedUnixTime      int64
-	UpdatedUnixTime      int64
-	DeletedUnixTime      int64
-	LastLoginUnixTime    int64
+	Uid                       int64  `xorm:"PK"`
+	Username                  string `xorm:"VARCHAR(32) UNIQUE NOT NULL"`
+	Email                     string `xorm:"VARCHAR(100) UNIQUE NOT NULL"`
+	Nickname                  string `xorm:"VARCHAR(64) NOT NULL"`
+	Password                  string `xorm:"VARCHAR(255) NOT NULL"`
+	Salt                      string `xorm:"VARCHAR(10) NOT NULL"`
+	DefaultAccountId          int64
+	TransactionEditScope      TransactionEditScope `xorm:"TINYINT NOT NULL"`
+	Language                  string               `xorm:"VARCHAR(10)"`
+	DefaultCurrency           string               `xorm:"VARCHAR(3) NOT NULL"`
+	FirstDayOfWeek            WeekDay              `xorm:"TINYINT NOT NULL"`
+	FiscalYearStartMonth      uint8                `xorm:"TINYINT"`
+	MonthStartDay             uint8                `xorm:"TINYINT"`
+	LongDateFormat            LongDateFormat       `xorm:"TINYINT"`
+	ShortDateFormat           ShortDateFormat      `xorm:"TINYINT"`
+	LongTimeFormat            LongTimeFormat       `xorm:"TINYINT"`
+	ShortTimeFormat           ShortTimeFormat      `xorm:"TINYINT"`
+	DecimalSeparator          DecimalSeparator     `xorm:"TINYINT"`
+	DigitGroupingSymbol       DigitGroupingSymbol  `xorm:"TINYINT"`
+	DigitGrouping             DigitGroupingType    `xorm:"TINYINT"`
+	CurrencyDisplayType       CurrencyDisplayType  `xorm:"TINYINT"`
+	Disabled                  bool
+	IsAdmin                   bool   `xorm:"NOT NULL DEFAULT false"`
+	Deleted                   bool   `xorm:"NOT NULL"`
+	EmailVerified             bool   `xorm:"NOT NULL"`
+	PendingEmail              string `xorm:"VARCHAR(100)"`
+	CreatedUnixTime           int64
+	UpdatedUnixTime           int64
+	DeletedUnixTime           int64
+	LastLoginUnixTime         int64
+	DeletionScheduledUnixTime int64 `xorm:"INDEX(IDX_user_deletion_scheduled_unix_time)"`
+	InvitationCodeId          int64
 }
 
 // UserBasicInfo represents a view-object of user basic info
@@ -89,6 +95,8 @@ type UserBasicInfo struct {
 	Language             string               `json:"language"`
 	DefaultCurrency      string               `json:"defaultCurrency"`
 	FirstDayOfWeek       WeekDay              `json:"firstDayOfWeek"`
+	FiscalYearStartMonth uint8                `json:"fiscalYearStartMonth"`
+	MonthStartDay        uint8                `json:"monthStartDay"`
 	LongDateFormat       LongDateFormat       `json:"longDateFormat"`
 	ShortDateFormat      ShortDateFormat      `json:"shortDateFormat"`
 	LongTimeFormat       LongTimeFormat       `json:"longTimeFormat"`
@@ -115,6 +123,7 @@ type UserRegisterRequest struct {
 	Language        string  `json:"language" binding:"required,min=2,max=16"`
 	DefaultCurrency string  `json:"defaultCurrency" binding:"required,len=3,validCurrency"`
 	FirstDayOfWeek  WeekDay `json:"firstDayOfWeek" binding:"min=0,max=6"`
+	InvitationCode  string  `json:"invitationCode" binding:"omitempty,max=32"`
 	TransactionCategoryCreateBatchRequest
 }
 
@@ -135,6 +144,20 @@ type UserResendVerifyEmailRequest struct {
 	Password string `json:"password" binding:"omitempty,min=6,max=128"`
 }
 
+// UserAccountDeletionRequest represents all parameters of user account deletion request,
+// the password can be omitted if user has logged in via external provider recently,
+// and the passcode or webauthn credential is required if user has set any second factor
+type UserAccountDeletionRequest struct {
+	Password           string                `json:"password" binding:"omitempty,min=6,max=128"`
+	Passcode           string                `json:"passcode" binding:"omitempty,len=6"`
+	WebAuthnCredential *WebAuthnLoginRequest `json:"webAuthnCredential" binding:"omitempty"`
+}
+
+// UserAccountDeletionResponse represents all response parameters after user has requested account deletion
+type UserAccountDeletionResponse struct {
+	DeletionScheduledAt int64 `json:"deletionScheduledAt"`
+}
+
 // UserProfileUpdateRequest represents all parameters of user updating profile request
 type UserProfileUpdateRequest struct {
 	Email                string                `json:"email" binding:"omitempty,notBlank,max=100,validEmail"`
@@ -146,6 +169,8 @@ type UserProfileUpdateRequest struct {
 	Language             string                `json:"language" binding:"omitempty,min=2,max=16"`
 	DefaultCurrency      string                `json:"defaultCurrency" binding:"omitempty,len=3,validCurrency"`
 	FirstDayOfWeek       *WeekDay              `json:"firstDayOfWeek" binding:"omitempty,min=0,max=6"`
+	FiscalYearStartMonth *uint8                `json:"fiscalYearStartMonth" binding:"omitempty,min=1,max=12"`
+	MonthStartDay        *uint8                `json:"monthStartDay" binding:"omitempty,min=1,max=28"`
 	LongDateFormat       *LongDateFormat       `json:"longDateFormat" binding:"omitempty,min=0,max=3"`
 	ShortDateFormat      *ShortDateFormat      `json:"shortDateFormat" binding:"omitempty,min=0,max=3"`
 	LongTimeFormat       *LongTimeFormat       `json:"longTimeFormat" binding:"omitempty,min=0,max=3"`
@@ -158,8 +183,9 @@ type UserProfileUpdateRequest struct {
 
 // UserProfileUpdateResponse represents the data returns to frontend after updating profile
 type UserProfileUpdateResponse struct {
-	User     *UserBasicInfo `json:"user"`
-	NewToken string         `json:"newToken,omitempty"`
+	User         *UserBasicInfo `json:"user"`
+	NewToken     string         `json:"newToken,omitempty"`
+	PendingEmail string         `json:"pendingEmail,omitempty"`
 }
 
 // UserProfileResponse represents a view-object of user profile
@@ -174,6 +200,8 @@ type UserProfileResponse struct {
 	Language             string               `json:"language"`
 	DefaultCurrency      string               `json:"defaultCurrency"`
 	FirstDayOfWeek       WeekDay              `json:"firstDayOfWeek"`
+	FiscalYearStartMonth uint8                `json:"fiscalYearStartMonth"`
+	MonthStartDay        uint8                `json:"monthStartDay"`
 	LongDateFormat       LongDateFormat       `json:"longDateFormat"`
 	ShortDateFormat      ShortDateFormat      `json:"shortDateFormat"`
 	LongTimeFormat       LongTimeFormat       `json:"longTimeFormat"`
@@ -183,7 +211,10 @@ type UserProfileResponse struct {
 	DigitGrouping        DigitGroupingType    `json:"digitGrouping"`
 	CurrencyDisplayType  CurrencyDisplayType  `json:"currencyDisplayType"`
 	EmailVerified        bool                 `json:"emailVerified"`
+	PendingEmail         string               `json:"pendingEmail,omitempty"`
+	IsAdmin              bool                 `json:"isAdmin,omitempty"`
 	LastLoginAt          int64                `json:"lastLoginAt"`
+	DeletionScheduledAt  int64                `json:"deletionScheduledAt,omitempty"`
 }
 
 // CanEditTransactionByTransactionTime returns whether this user can edit transaction with specified transaction time
@@ -228,6 +259,24 @@ func (u *User) CanEditTransactionByTransactionTime(transactionTime int64, utcOff
 	return false
 }
 
+// GetFiscalYearStartMonth returns the first month of fiscal year of this user, it returns January if not set
+func (u *User) GetFiscalYearStartMonth() uint8 {
+	if u.FiscalYearStartMonth < 1 || u.FiscalYearStartMonth > 12 {
+		return 1
+	}
+
+	return u.FiscalYearStartMonth
+}
+
+// GetMonthStartDay returns the day of month which every month starts on of this user, it returns the first day if not set
+func (u *User) GetMonthStartDay() uint8 {
+	if u.MonthStartDay < 1 || u.MonthStartDay > 28 {
+		return 1
+	}
+
+	return u.MonthStartDay
+}
+
 // ToUserBasicInfo returns a user basic view-object according to database model
 func (u *User) ToUserBasicInfo() *UserBasicInfo {
 	return &UserBasicInfo{
@@ -241,6 +290,8 @@ func (u *User) ToUserBasicInfo() *UserBasicInfo {
 		Language:             u.Language,
 		DefaultCurrency:      u.DefaultCurrency,
 		FirstDayOfWeek:       u.FirstDayOfWeek,
+		FiscalYearStartMonth: u.GetFiscalYearStartMonth(),
+		MonthStartDay:        u.GetMonthStartDay(),
 		LongDateFormat:       u.LongDateFormat,
 		ShortDateFormat:      u.ShortDateFormat,
 		LongTimeFormat:       u.LongTimeFormat,
@@ -266,6 +317,8 @@ func (u *User) ToUserProfileResponse() *UserProfileResponse {
 		Language:             u.Language,
 		DefaultCurrency:      u.DefaultCurrency,
 		FirstDayOfWeek:       u.FirstDayOfWeek,
+		FiscalYearStartMonth: u.GetFiscalYearStartMonth(),
+		MonthStartDay:        u.GetMonthStartDay(),
 		LongDateFormat:       u.LongDateFormat,
 		ShortDateFormat:      u.ShortDateFormat,
 		LongTimeFormat:       u.LongTimeFormat,
@@ -275,7 +328,10 @@ func (u *User) ToUserProfileResponse() *UserProfileResponse {
 		DigitGrouping:        u.DigitGrouping,
 		CurrencyDisplayType:  u.CurrencyDisplayType,
 		EmailVerified:        u.EmailVerified,
+		PendingEmail:         u.PendingEmail,
+		IsAdmin:              u.IsAdmin,
 		LastLoginAt:          u.LastLoginUnixTime,
+		DeletionScheduledAt:  u.DeletionScheduledUnixTime,
 	}
 }
 
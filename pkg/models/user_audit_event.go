@@ -0,0 +1,145 @@
+package models
+
+// UserAuditEventMaxDetailsLength represents the maximum size of details stored in database
+const UserAuditEventMaxDetailsLength = 255
+
+// UserAuditEventType represents the type of security related event of user account
+type UserAuditEventType byte
+
+// User audit event types
+const (
+	USER_AUDIT_EVENT_TYPE_LOGIN                               UserAuditEventType = 1
+	USER_AUDIT_EVENT_TYPE_LOGIN_FAILED                        UserAuditEventType = 2
+	USER_AUDIT_EVENT_TYPE_LOGOUT                              UserAuditEventType = 3
+	USER_AUDIT_EVENT_TYPE_ACCOUNT_LOCKED                      UserAuditEventType = 4
+	USER_AUDIT_EVENT_TYPE_REGISTER                            UserAuditEventType = 5
+	USER_AUDIT_EVENT_TYPE_PASSWORD_CHANGE                     UserAuditEventType = 10
+	USER_AUDIT_EVENT_TYPE_PASSWORD_RESET                      UserAuditEventType = 11
+	USER_AUDIT_EVENT_TYPE_EMAIL_CHANGE                        UserAuditEventType = 12
+	USER_AUDIT_EVENT_TYPE_EMAIL_CHANGE_REQUEST                UserAuditEventType = 13
+	USER_AUDIT_EVENT_TYPE_TWO_FACTOR_ENABLE                   UserAuditEventType = 20
+	USER_AUDIT_EVENT_TYPE_TWO_FACTOR_DISABLE                  UserAuditEventType = 21
+	USER_AUDIT_EVENT_TYPE_TWO_FACTOR_RECOVERY_CODE_REGENERATE UserAuditEventType = 22
+	USER_AUDIT_EVENT_TYPE_WEBAUTHN_CREDENTIAL_REGISTER        UserAuditEventType = 23
+	USER_AUDIT_EVENT_TYPE_WEBAUTHN_CREDENTIAL_DELETE          UserAuditEventType = 24
+	USER_AUDIT_EVENT_TYPE_TOKEN_REVOKE                        UserAuditEventType = 30
+	USER_AUDIT_EVENT_TYPE_TOKEN_REVOKE_ALL                    UserAuditEventType = 31
+	USER_AUDIT_EVENT_TYPE_PERSONAL_ACCESS_TOKEN_CREATE        UserAuditEventType = 32
+	USER_AUDIT_EVENT_TYPE_DATA_CLEAR                          UserAuditEventType = 40
+	USER_AUDIT_EVENT_TYPE_DATA_EXPORT                         UserAuditEventType = 41
+	USER_AUDIT_EVENT_TYPE_ACCOUNT_DELETION_REQUEST            UserAuditEventType = 50
+	USER_AUDIT_EVENT_TYPE_ACCOUNT_DELETION_CANCEL             UserAuditEventType = 51
+	USER_AUDIT_EVENT_TYPE_ACCOUNT_PURGED                      UserAuditEventType = 52
+	USER_AUDIT_EVENT_TYPE_ADMIN_ACTION                        UserAuditEventType = 60
+	USER_AUDIT_EVENT_TYPE_MODIFIED_BY_ADMIN                   UserAuditEventType = 61
+	USER_AUDIT_EVENT_TYPE_INVITATION_CODE_CREATE              UserAuditEventType = 62
+	USER_AUDIT_EVENT_TYPE_INVITATION_CODE_REVOKE              UserAuditEventType = 63
+)
+
+// String returns a textual representation of the user audit event type
+func (t UserAuditEventType) String() string {
+	switch t {
+	case USER_AUDIT_EVENT_TYPE_LOGIN:
+		return "Login"
+	case USER_AUDIT_EVENT_TYPE_LOGIN_FAILED:
+		return "Login Failed"
+	case USER_AUDIT_EVENT_TYPE_LOGOUT:
+		return "Logout"
+	case USER_AUDIT_EVENT_TYPE_ACCOUNT_LOCKED:
+		return "Account Locked"
+	case USER_AUDIT_EVENT_TYPE_REGISTER:
+		return "Register"
+	case USER_AUDIT_EVENT_TYPE_PASSWORD_CHANGE:
+		return "Password Change"
+	case USER_AUDIT_EVENT_TYPE_PASSWORD_RESET:
+		return "Password Reset"
+	case USER_AUDIT_EVENT_TYPE_EMAIL_CHANGE:
+		return "Email Change"
+	case USER_AUDIT_EVENT_TYPE_EMAIL_CHANGE_REQUEST:
+		return "Email Change Request"
+	case USER_AUDIT_EVENT_TYPE_TWO_FACTOR_ENABLE:
+		return "Two-Factor Enable"
+	case USER_AUDIT_EVENT_TYPE_TWO_FACTOR_DISABLE:
+		return "Two-Factor Disable"
+	case USER_AUDIT_EVENT_TYPE_TWO_FACTOR_RECOVERY_CODE_REGENERATE:
+		return "Two-Factor Recovery Code Regenerate"
+	case USER_AUDIT_EVENT_TYPE_WEBAUTHN_CREDENTIAL_REGISTER:
+		return "WebAuthn Credential Register"
+	case USER_AUDIT_EVENT_TYPE_WEBAUTHN_CREDENTIAL_DELETE:
+		return "WebAuthn Credential Delete"
+	case USER_AUDIT_EVENT_TYPE_TOKEN_REVOKE:
+		return "Token Revoke"
+	case USER_AUDIT_EVENT_TYPE_TOKEN_REVOKE_ALL:
+		return "Token Revoke All"
+	case USER_AUDIT_EVENT_TYPE_PERSONAL_ACCESS_TOKEN_CREATE:
+		return "Personal Access Token Create"
+	case USER_AUDIT_EVENT_TYPE_DATA_CLEAR:
+		return "Data Clear"
+	case USER_AUDIT_EVENT_TYPE_DATA_EXPORT:
+		return "Data Export"
+	case USER_AUDIT_EVENT_TYPE_ACCOUNT_DELETION_REQUEST:
+		return "Account Deletion Request"
+	case USER_AUDIT_EVENT_TYPE_ACCOUNT_DELETION_CANCEL:
+		return "Account Deletion Cancel"
+	case USER_AUDIT_EVENT_TYPE_ACCOUNT_PURGED:
+		return "Account Purged"
+	case USER_AUDIT_EVENT_TYPE_ADMIN_ACTION:
+		return "Admin Action"
+	case USER_AUDIT_EVENT_TYPE_MODIFIED_BY_ADMIN:
+		return "Modified By Admin"
+	case USER_AUDIT_EVENT_TYPE_INVITATION_CODE_CREATE:
+		return "Invitation Code Create"
+	case USER_AUDIT_EVENT_TYPE_INVITATION_CODE_REVOKE:
+		return "Invitation Code Revoke"
+	default:
+		return "Unknown"
+	}
+}
+
+// UserAuditEvent represents a security related event of user account stored in database, which is append-only
+type UserAuditEvent struct {
+	EventId         int64              `xorm:"PK"`
+	Uid             int64              `xorm:"INDEX(IDX_user_audit_event_uid_event_id) NOT NULL"`
+	EventType       UserAuditEventType `xorm:"TINYINT NOT NULL"`
+	ClientIp        string             `xorm:"VARCHAR(64)"`
+	UserAgent       string             `xorm:"VARCHAR(255)"`
+	RequestId       string             `xorm:"VARCHAR(64)"`
+	Details         string             `xorm:"VARCHAR(255)"`
+	CreatedUnixTime int64              `xorm:"NOT NULL"`
+}
+
+// UserAuditEventListRequest represents all parameters of user audit event listing request
+type UserAuditEventListRequest struct {
+	MaxId int64 `form:"max_id,string" binding:"min=0"`
+	Count int32 `form:"count" binding:"required,min=1,max=50"`
+}
+
+// UserAuditEventInfoResponse represents a view-object of user audit event
+type UserAuditEventInfoResponse struct {
+	Id        int64              `json:"id,string"`
+	EventType UserAuditEventType `json:"eventType"`
+	ClientIp  string             `json:"clientIp"`
+	UserAgent string             `json:"userAgent"`
+	RequestId string             `json:"requestId,omitempty"`
+	Details   string             `json:"details,omitempty"`
+	CreatedAt int64              `json:"createdAt"`
+}
+
+// UserAuditEventInfoPageWrapperResponse represents a response of user audit event which contains items and next id
+type UserAuditEventInfoPageWrapperResponse struct {
+	Items     []*UserAuditEventInfoResponse `json:"items"`
+	NextMaxId *int64                        `json:"nextMaxId,string"`
+}
+
+// ToUserAuditEventInfoResponse returns a view-object according to database model
+func (e *UserAuditEvent) ToUserAuditEventInfoResponse() *UserAuditEventInfoResponse {
+	return &UserAuditEventInfoResponse{
+		Id:        e.EventId,
+		EventType: e.EventType,
+		ClientIp:  e.ClientIp,
+		UserAgent: e.UserAgent,
+		RequestId: e.RequestId,
+		Details:   e.Details,
+		CreatedAt: e.CreatedUnixTime,
+	}
+}
@@ -0,0 +1,151 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/utils"
+)
+
+// UserExchangeRate represents the exchange rate provided by user stored in database,
+// the rate is the amount of currency per 1 unit of base currency, and the rate without date is a permanent override
+type UserExchangeRate struct {
+	Uid             int64  `xorm:"PK NOT NULL"`
+	Currency        string `xorm:"VARCHAR(3) PK NOT NULL"`
+	Date            int32  `xorm:"PK NOT NULL"`
+	BaseCurrency    string `xorm:"VARCHAR(3) NOT NULL"`
+	Rate            string `xorm:"VARCHAR(32) NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+}
+
+// UserExchangeRateSaveRequest represents all parameters of user exchange rate saving request
+type UserExchangeRateSaveRequest struct {
+	Currency     string `json:"currency" binding:"required,len=3,validCurrency"`
+	BaseCurrency string `json:"baseCurrency" binding:"required,len=3,validCurrency"`
+	Rate         string `json:"rate" binding:"required,max=32"`
+	Date         string `json:"date"`
+}
+
+// UserExchangeRateDeleteRequest represents all parameters of user exchange rate deleting request
+type UserExchangeRateDeleteRequest struct {
+	Currency string `json:"currency" binding:"required,len=3,validCurrency"`
+	Date     string `json:"date"`
+}
+
+// UserExchangeRateInfoResponse represents a view-object of user exchange rate
+type UserExchangeRateInfoResponse struct {
+	Currency     string `json:"currency"`
+	BaseCurrency string `json:"baseCurrency"`
+	Rate         string `json:"rate"`
+	Date         string `json:"date,omitempty"`
+}
+
+// ToUserExchangeRateInfoResponse returns a view-object according to database model
+func (r *UserExchangeRate) ToUserExchangeRateInfoResponse() *UserExchangeRateInfoResponse {
+	date := ""
+
+	if r.Date > 0 {
+		date = utils.FormatNumericDateToLongDate(r.Date)
+	}
+
+	return &UserExchangeRateInfoResponse{
+		Currency:     r.Currency,
+		BaseCurrency: r.BaseCurrency,
+		Rate:         r.Rate,
+		Date:         date,
+	}
+}
+
+// GetRateInBaseCurrency returns the currency and its textual rate in the specified base currency according to the textual rates of that base currency
+// by decimal calculation, returns false if the rate cannot be converted
+func (r *UserExchangeRate) GetRateInBaseCurrency(baseCurrency string, baseCurrencyRates map[string]string) (string, string, bool) {
+	rate, err := utils.ParseDecimal(r.Rate)
+
+	if err != nil || rate.Sign() <= 0 {
+		return "", "", false
+	}
+
+	if r.BaseCurrency == baseCurrency {
+		return r.Currency, r.Rate, true
+	}
+
+	// The user exchange rate specifies the rate of target base currency, so the rate of its base currency is the reciprocal
+	if r.Currency == baseCurrency {
+		reciprocalRate, err := utils.GetCrossRate(r.Rate, "1", exchangeRateCrossRateDecimalPlaces)
+
+		if err != nil {
+			return "", "", false
+		}
+
+		return r.BaseCurrency, reciprocalRate, true
+	}
+
+	rateOfBaseCurrency, exists := baseCurrencyRates[r.BaseCurrency]
+
+	if !exists {
+		return "", "", false
+	}
+
+	convertedRate, err := utils.MultiplyRates(r.Rate, rateOfBaseCurrency, exchangeRateCrossRateDecimalPlaces)
+
+	if err != nil {
+		return "", "", false
+	}
+
+	return r.Currency, convertedRate, true
+}
+
+// GetUserExchangeRatesOfDate returns the user exchange rates which are effective on the specified date,
+// the rate of the specified date takes precedence over the permanent override of the same currency
+func GetUserExchangeRatesOfDate(userExchangeRates []*UserExchangeRate, date int32) []*UserExchangeRate {
+	effectiveRates := make(map[string]*UserExchangeRate, len(userExchangeRates))
+	currencies := make([]string, 0, len(userExchangeRates))
+
+	for i := 0; i < len(userExchangeRates); i++ {
+		userExchangeRate := userExchangeRates[i]
+
+		if userExchangeRate.Date != 0 && userExchangeRate.Date != date {
+			continue
+		}
+
+		existedRate, exists := effectiveRates[userExchangeRate.Currency]
+
+		if !exists {
+			currencies = append(currencies, userExchangeRate.Currency)
+		} else if existedRate.Date != 0 {
+			continue
+		}
+
+		effectiveRates[userExchangeRate.Currency] = userExchangeRate
+	}
+
+	result := make([]*UserExchangeRate, 0, len(currencies))
+
+	for i := 0; i < len(currencies); i++ {
+		result = append(result, effectiveRates[currencies[i]])
+	}
+
+	return result
+}
+
+// UserExchangeRateInfoResponseSlice represents the slice data structure of UserExchangeRateInfoResponse
+type UserExchangeRateInfoResponseSlice []*UserExchangeRateInfoResponse
+
+// Len returns the count of items
+func (s UserExchangeRateInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s UserExchangeRateInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s UserExchangeRateInfoResponseSlice) Less(i, j int) bool {
+	if s[i].Currency != s[j].Currency {
+		return strings.Compare(s[i].Currency, s[j].Currency) < 0
+	}
+
+	return strings.Compare(s[i].Date, s[j].Date) < 0
+}
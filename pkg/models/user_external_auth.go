@@ -0,0 +1,33 @@
+package models
+
+// UserExternalAuthType represents the type of external authentication
+type UserExternalAuthType string
+
+// External Authentication Types
+const (
+	USER_EXTERNAL_AUTH_TYPE_OIDC UserExternalAuthType = "oidc"
+	USER_EXTERNAL_AUTH_TYPE_LDAP UserExternalAuthType = "ldap"
+)
+
+// UserExternalAuth represents the link between user and external user which is stored in database
+type UserExternalAuth struct {
+	Uid               int64                `xorm:"PK"`
+	ExternalAuthType  UserExternalAuthType `xorm:"VARCHAR(16) PK UNIQUE(UQE_user_external_auth_type_external_user_id) NOT NULL"`
+	ExternalUserId    string               `xorm:"VARCHAR(255) UNIQUE(UQE_user_external_auth_type_external_user_id) NOT NULL"`
+	ExternalUsername  string               `xorm:"VARCHAR(255)"`
+	ExternalEmail     string               `xorm:"VARCHAR(255)"`
+	CreatedUnixTime   int64
+	LastLoginUnixTime int64
+}
+
+// OIDCAuthorizeUrlResponse represents the url of OpenID Connect provider which the user should be redirected to
+type OIDCAuthorizeUrlResponse struct {
+	ProviderName string `json:"providerName"`
+	AuthorizeUrl string `json:"authorizeUrl"`
+}
+
+// OIDCAuthorizeRequest represents all parameters of OpenID Connect login request
+type OIDCAuthorizeRequest struct {
+	Code  string `json:"code" binding:"required,notBlank,max=2048"`
+	State string `json:"state" binding:"required,notBlank,max=64"`
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/webauthn"
+)
+
+// UserWebAuthnCredentialMaxCount represents the maximum count of webauthn credentials of each user
+const UserWebAuthnCredentialMaxCount = 20
+
+// UserWebAuthnCredential represents a webauthn credential of user which is stored in database,
+// the credential id and the COSE public key are base64url encoded
+type UserWebAuthnCredential struct {
+	CredentialId     string `xorm:"VARCHAR(255) PK"`
+	Uid              int64  `xorm:"INDEX(IDX_user_webauthn_credential_uid) NOT NULL"`
+	Name             string `xorm:"VARCHAR(64) NOT NULL"`
+	PublicKey        string `xorm:"VARCHAR(1024) NOT NULL"`
+	SignCount        int64  `xorm:"NOT NULL"`
+	Transports       string `xorm:"VARCHAR(255)"`
+	CreatedUnixTime  int64
+	LastUsedUnixTime int64
+}
+
+// WebAuthnChallenge represents the challenge of an unfinished webauthn ceremony which is stored in database,
+// it is deleted when the ceremony finishes so that each signed session can only be used once
+type WebAuthnChallenge struct {
+	Challenge       string `xorm:"VARCHAR(64) PK"`
+	ExpiredUnixTime int64  `xorm:"INDEX(IDX_webauthn_challenge_expired_time) NOT NULL"`
+	CreatedUnixTime int64
+}
+
+// WebAuthnRegistrationRequestResponse represents the credential creation options which are passed to authenticator and the signed session
+type WebAuthnRegistrationRequestResponse struct {
+	Options *webauthn.CredentialCreationOptions `json:"options"`
+	Session string                              `json:"session"`
+}
+
+// WebAuthnRegistrationConfirmRequest represents all parameters of webauthn credential registration confirm request
+type WebAuthnRegistrationConfirmRequest struct {
+	Name       string                               `json:"name" binding:"required,notBlank,max=64"`
+	Session    string                               `json:"session" binding:"required,notBlank,max=1024"`
+	Credential *webauthn.CredentialCreationResponse `json:"credential" binding:"required"`
+}
+
+// WebAuthnLoginRequestResponse represents the credential request options which are passed to authenticator and the signed session
+type WebAuthnLoginRequestResponse struct {
+	Options *webauthn.CredentialRequestOptions `json:"options"`
+	Session string                             `json:"session"`
+}
+
+// WebAuthnLoginRequest represents all parameters of webauthn login request
+type WebAuthnLoginRequest struct {
+	Session    string                                `json:"session" binding:"required,notBlank,max=1024"`
+	Credential *webauthn.CredentialAssertionResponse `json:"credential" binding:"required"`
+}
+
+// WebAuthnCredentialDeleteRequest represents all parameters of webauthn credential deleting request
+type WebAuthnCredentialDeleteRequest struct {
+	Id string `json:"id" binding:"required,notBlank,max=255"`
+}
+
+// WebAuthnCredentialInfoResponse represents a view-object of webauthn credential
+type WebAuthnCredentialInfoResponse struct {
+	Id         string   `json:"id"`
+	Name       string   `json:"name"`
+	Transports []string `json:"transports"`
+	CreatedAt  int64    `json:"createdAt"`
+	LastUsedAt int64    `json:"lastUsedAt,omitempty"`
+}
+
+// ToWebAuthnCredentialInfoResponse returns a view-object according to database model
+func (c *UserWebAuthnCredential) ToWebAuthnCredentialInfoResponse() *WebAuthnCredentialInfoResponse {
+	return &WebAuthnCredentialInfoResponse{
+		Id:         c.CredentialId,
+		Name:       c.Name,
+		Transports: c.GetTransports(),
+		CreatedAt:  c.CreatedUnixTime,
+		LastUsedAt: c.LastUsedUnixTime,
+	}
+}
+
+// GetTransports returns the transports which the authenticator of credential supports
+func (c *UserWebAuthnCredential) GetTransports() []string {
+	if c.Transports == "" {
+		return []string{}
+	}
+
+	return strings.Split(c.Transports, ",")
+}
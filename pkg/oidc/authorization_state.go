@@ -0,0 +1,109 @@
+package oidc
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/utils"
+)
+
+// AuthorizationStateValidDuration represents how long the user can complete the login in provider
+const AuthorizationStateValidDuration = 10 * time.Minute
+
+const authorizationStateRandomStringLength = 32
+
+// AuthorizationState represents the state, nonce and PKCE code verifier of an authorization request,
+// which are kept by user agent until the provider redirects back
+type AuthorizationState struct {
+	State        string
+	Nonce        string
+	CodeVerifier string
+}
+
+type authorizationStateClaims struct {
+	Nonce                 string `json:"nonce"`
+	EncryptedCodeVerifier string `json:"cv"`
+	jwt.RegisteredClaims
+}
+
+// NewAuthorizationState returns a new authorization state with random values
+func NewAuthorizationState() (*AuthorizationState, error) {
+	state, err := utils.GetRandomNumberOrLetter(authorizationStateRandomStringLength)
+
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := utils.GetRandomNumberOrLetter(authorizationStateRandomStringLength)
+
+	if err != nil {
+		return nil, err
+	}
+
+	codeVerifier, err := GenerateCodeVerifier()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuthorizationState{
+		State:        state,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+	}, nil
+}
+
+// Encode returns the signed authorization state, the code verifier is encrypted as it must not be revealed to others
+func (s *AuthorizationState) Encode(secretKey string, now time.Time) (string, error) {
+	encryptedCodeVerifier, err := utils.EncryptSecret(s.CodeVerifier, secretKey)
+
+	if err != nil {
+		return "", err
+	}
+
+	claims := &authorizationStateClaims{
+		Nonce:                 s.Nonce,
+		EncryptedCodeVerifier: encryptedCodeVerifier,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        s.State,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(AuthorizationStateValidDuration)),
+		},
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secretKey))
+}
+
+// ParseAuthorizationState returns the authorization state if the signed authorization state is valid and not expired
+func ParseAuthorizationState(encodedState string, secretKey string) (*AuthorizationState, error) {
+	claims := &authorizationStateClaims{}
+	_, err := jwt.ParseWithClaims(encodedState, claims,
+		func(token *jwt.Token) (any, error) {
+			return []byte(secretKey), nil
+		},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.ID == "" || claims.Nonce == "" || claims.EncryptedCodeVerifier == "" {
+		return nil, errors.New("authorization state is incomplete")
+	}
+
+	codeVerifier, err := utils.DecryptSecret(claims.EncryptedCodeVerifier, secretKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuthorizationState{
+		State:        claims.ID,
+		Nonce:        claims.Nonce,
+		CodeVerifier: codeVerifier,
+	}, nil
+}
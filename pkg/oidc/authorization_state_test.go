@@ -0,0 +1,60 @@
+package oidc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorizationState_EncodeAndParse(t *testing.T) {
+	expectedState, err := NewAuthorizationState()
+	assert.Nil(t, err)
+
+	encodedState, err := expectedState.Encode("secret", time.Now())
+	assert.Nil(t, err)
+	assert.NotContains(t, encodedState, expectedState.CodeVerifier)
+
+	actualState, err := ParseAuthorizationState(encodedState, "secret")
+	assert.Nil(t, err)
+	assert.Equal(t, expectedState, actualState)
+}
+
+func TestAuthorizationState_ParseWithWrongSecretKey(t *testing.T) {
+	state, err := NewAuthorizationState()
+	assert.Nil(t, err)
+
+	encodedState, err := state.Encode("secret", time.Now())
+	assert.Nil(t, err)
+
+	_, err = ParseAuthorizationState(encodedState, "another-secret")
+	assert.NotNil(t, err)
+}
+
+func TestAuthorizationState_ParseExpiredState(t *testing.T) {
+	state, err := NewAuthorizationState()
+	assert.Nil(t, err)
+
+	encodedState, err := state.Encode("secret", time.Now().Add(-AuthorizationStateValidDuration-time.Second))
+	assert.Nil(t, err)
+
+	_, err = ParseAuthorizationState(encodedState, "secret")
+	assert.NotNil(t, err)
+}
+
+func TestNewAuthorizationState_RandomValues(t *testing.T) {
+	state1, err := NewAuthorizationState()
+	assert.Nil(t, err)
+
+	state2, err := NewAuthorizationState()
+	assert.Nil(t, err)
+
+	assert.NotEqual(t, state1.State, state2.State)
+	assert.NotEqual(t, state1.Nonce, state2.Nonce)
+	assert.NotEqual(t, state1.CodeVerifier, state2.CodeVerifier)
+	assert.Equal(t, 43, len(state1.CodeVerifier))
+}
+
+func TestGetS256CodeChallenge(t *testing.T) {
+	assert.Equal(t, "af62j6m3uXvTuAK5ExMqztzf9k2xoIqXZczC9GWztB0", GetS256CodeChallenge("dBjftJeZ4CVP-mJ92IbMTHwWjEJ9GXMlsbfQw7QT6Ls"))
+}
@@ -0,0 +1,139 @@
+package oidc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+)
+
+// jsonWebKey represents a public key in json web key set of provider
+type jsonWebKey struct {
+	KeyType  string `json:"kty"`
+	KeyId    string `json:"kid"`
+	Use      string `json:"use"`
+	Modulus  string `json:"n"`
+	Exponent string `json:"e"`
+	Curve    string `json:"crv"`
+	X        string `json:"x"`
+	Y        string `json:"y"`
+}
+
+type jsonWebKeySet struct {
+	Keys []*jsonWebKey `json:"keys"`
+}
+
+// parseJsonWebKeySet returns the signing public keys of json web key set which are keyed by key id,
+// the keys which are not for signature or not supported are ignored
+func parseJsonWebKeySet(content []byte) (map[string]any, error) {
+	keySet := &jsonWebKeySet{}
+	err := json.Unmarshal(content, keySet)
+
+	if err != nil {
+		return nil, err
+	}
+
+	publicKeys := make(map[string]any, len(keySet.Keys))
+
+	for i := 0; i < len(keySet.Keys); i++ {
+		key := keySet.Keys[i]
+
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+
+		publicKey, err := key.toPublicKey()
+
+		if err != nil {
+			continue
+		}
+
+		publicKeys[key.KeyId] = publicKey
+	}
+
+	if len(publicKeys) < 1 {
+		return nil, errors.New("no supported public key in json web key set")
+	}
+
+	return publicKeys, nil
+}
+
+func (k *jsonWebKey) toPublicKey() (any, error) {
+	switch k.KeyType {
+	case "RSA":
+		modulus, err := decodeBase64UrlBigInt(k.Modulus)
+
+		if err != nil {
+			return nil, err
+		}
+
+		exponent, err := decodeBase64UrlBigInt(k.Exponent)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+			return nil, errors.New("rsa public exponent is too large")
+		}
+
+		return &rsa.PublicKey{
+			N: modulus,
+			E: int(exponent.Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+
+		switch k.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, errors.New("elliptic curve is not supported")
+		}
+
+		x, err := decodeBase64UrlBigInt(k.X)
+
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeBase64UrlBigInt(k.Y)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on elliptic curve")
+		}
+
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     x,
+			Y:     y,
+		}, nil
+	default:
+		return nil, errors.New("key type is not supported")
+	}
+}
+
+func decodeBase64UrlBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < 1 {
+		return nil, errors.New("value is empty")
+	}
+
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,391 @@
+package oidc
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/log"
+	"github.com/kyy-me/ezbookkeeping/pkg/settings"
+	"github.com/kyy-me/ezbookkeeping/pkg/utils"
+)
+
+const oidcDiscoveryPath = "/.well-known/openid-configuration"
+
+var supportedIdTokenSigningMethods = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+}
+
+// ProviderMetadata represents the metadata which is discovered from the OpenID Connect provider
+type ProviderMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksUri               string `json:"jwks_uri"`
+}
+
+// IdTokenClaims represents the claims of id token which are used to identify the external user
+type IdTokenClaims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Username      string
+	Nickname      string
+}
+
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	IdToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// OIDCProvider represents an OpenID Connect provider which supports authorization code flow with PKCE
+type OIDCProvider struct {
+	issuerUrl     string
+	clientId      string
+	clientSecret  string
+	redirectUrl   string
+	scopes        []string
+	usernameClaim string
+	httpClient    *http.Client
+	mutex         sync.Mutex
+	metadata      *ProviderMetadata
+	publicKeys    map[string]any
+}
+
+// NewOIDCProvider returns a new OpenID Connect provider according to the config
+func NewOIDCProvider(config *settings.Config) *OIDCProvider {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	utils.SetProxyUrl(transport, config.OIDCProxy)
+
+	if config.OIDCSkipTLSVerify {
+		transport.TLSClientConfig = &tls.Config{
+			InsecureSkipVerify: true,
+		}
+	}
+
+	return &OIDCProvider{
+		issuerUrl:     config.OIDCIssuerUrl,
+		clientId:      config.OIDCClientId,
+		clientSecret:  config.OIDCClientSecret,
+		redirectUrl:   config.OIDCRedirectUrl,
+		scopes:        config.OIDCScopes,
+		usernameClaim: config.OIDCUsernameClaim,
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   time.Duration(config.OIDCRequestTimeout) * time.Millisecond,
+		},
+	}
+}
+
+// GetAuthorizationUrl returns the url of provider authorization endpoint which the user agent should be redirected to
+func (p *OIDCProvider) GetAuthorizationUrl(c *core.Context, state *AuthorizationState) (string, error) {
+	metadata, err := p.getMetadata(c)
+
+	if err != nil {
+		return "", err
+	}
+
+	authorizationUrl, err := url.Parse(metadata.AuthorizationEndpoint)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[oidc_provider.GetAuthorizationUrl] authorization endpoint \"%s\" is invalid, because %s", metadata.AuthorizationEndpoint, err.Error())
+		return "", errs.ErrOIDCProviderRequestFailed
+	}
+
+	params := authorizationUrl.Query()
+	params.Set("response_type", "code")
+	params.Set("client_id", p.clientId)
+	params.Set("redirect_uri", p.redirectUrl)
+	params.Set("scope", strings.Join(p.scopes, " "))
+	params.Set("state", state.State)
+	params.Set("nonce", state.Nonce)
+	params.Set("code_challenge", GetS256CodeChallenge(state.CodeVerifier))
+	params.Set("code_challenge_method", "S256")
+	authorizationUrl.RawQuery = params.Encode()
+
+	return authorizationUrl.String(), nil
+}
+
+// Authorize exchanges the authorization code for id token, and returns the verified claims of id token
+func (p *OIDCProvider) Authorize(c *core.Context, code string, state *AuthorizationState) (*IdTokenClaims, error) {
+	rawIdToken, err := p.exchangeCode(c, code, state.CodeVerifier)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return p.verifyIdToken(c, rawIdToken, state.Nonce)
+}
+
+func (p *OIDCProvider) exchangeCode(c *core.Context, code string, codeVerifier string) (string, error) {
+	metadata, err := p.getMetadata(c)
+
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.redirectUrl)
+	form.Set("code_verifier", codeVerifier)
+
+	if p.clientSecret == "" {
+		form.Set("client_id", p.clientId)
+	}
+
+	req, _ := http.NewRequest("POST", metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", fmt.Sprintf("ezBookkeeping/%s ", settings.Version))
+
+	if p.clientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.clientId), url.QueryEscape(p.clientSecret))
+	}
+
+	resp, err := p.httpClient.Do(req)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[oidc_provider.exchangeCode] failed to request token endpoint \"%s\", because %s", metadata.TokenEndpoint, err.Error())
+		return "", errs.ErrOIDCProviderRequestFailed
+	}
+
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[oidc_provider.exchangeCode] failed to read token endpoint response, because %s", err.Error())
+		return "", errs.ErrOIDCProviderRequestFailed
+	}
+
+	tokenResp := &tokenResponse{}
+	err = json.Unmarshal(body, tokenResp)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[oidc_provider.exchangeCode] failed to parse token endpoint response (response code is %d), because %s", resp.StatusCode, err.Error())
+		return "", errs.ErrOIDCProviderRequestFailed
+	}
+
+	if resp.StatusCode != 200 || tokenResp.Error != "" {
+		log.WarnfWithRequestId(c, "[oidc_provider.exchangeCode] token endpoint returns error \"%s\" (%s), response code is %d", tokenResp.Error, tokenResp.ErrorDescription, resp.StatusCode)
+		return "", errs.ErrOIDCAuthorizationCodeInvalid
+	}
+
+	if tokenResp.IdToken == "" {
+		log.WarnfWithRequestId(c, "[oidc_provider.exchangeCode] token endpoint does not return id token")
+		return "", errs.ErrOIDCIdTokenInvalid
+	}
+
+	return tokenResp.IdToken, nil
+}
+
+func (p *OIDCProvider) verifyIdToken(c *core.Context, rawIdToken string, nonce string) (*IdTokenClaims, error) {
+	metadata, err := p.getMetadata(c)
+
+	if err != nil {
+		return nil, err
+	}
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(rawIdToken, claims,
+		func(token *jwt.Token) (any, error) {
+			keyId, _ := token.Header["kid"].(string)
+			return p.getPublicKey(c, keyId)
+		},
+		jwt.WithValidMethods(supportedIdTokenSigningMethods),
+		jwt.WithIssuer(metadata.Issuer),
+		jwt.WithAudience(p.clientId),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[oidc_provider.verifyIdToken] failed to verify id token, because %s", err.Error())
+		return nil, errs.ErrOIDCIdTokenInvalid
+	}
+
+	if tokenNonce, _ := claims["nonce"].(string); tokenNonce == "" || tokenNonce != nonce {
+		log.WarnfWithRequestId(c, "[oidc_provider.verifyIdToken] nonce of id token does not match")
+		return nil, errs.ErrOIDCIdTokenInvalid
+	}
+
+	audiences, _ := claims.GetAudience()
+
+	if len(audiences) > 1 {
+		if authorizedParty, _ := claims["azp"].(string); authorizedParty != p.clientId {
+			log.WarnfWithRequestId(c, "[oidc_provider.verifyIdToken] authorized party \"%s\" of id token does not match", authorizedParty)
+			return nil, errs.ErrOIDCIdTokenInvalid
+		}
+	}
+
+	subject, _ := claims.GetSubject()
+
+	if subject == "" {
+		log.WarnfWithRequestId(c, "[oidc_provider.verifyIdToken] subject of id token is empty")
+		return nil, errs.ErrExternalUserIdIsEmpty
+	}
+
+	idTokenClaims := &IdTokenClaims{
+		Subject:       subject,
+		Email:         getStringClaim(claims, "email"),
+		EmailVerified: getBooleanClaim(claims, "email_verified"),
+		Username:      getStringClaim(claims, p.usernameClaim),
+		Nickname:      getStringClaim(claims, "name"),
+	}
+
+	return idTokenClaims, nil
+}
+
+func (p *OIDCProvider) getMetadata(c *core.Context) (*ProviderMetadata, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	discoveryUrl := p.issuerUrl + oidcDiscoveryPath
+	body, err := p.requestProvider(c, discoveryUrl)
+
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := &ProviderMetadata{}
+	err = json.Unmarshal(body, metadata)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[oidc_provider.getMetadata] failed to parse provider metadata, because %s", err.Error())
+		return nil, errs.ErrOIDCProviderRequestFailed
+	}
+
+	if strings.TrimRight(metadata.Issuer, "/") != p.issuerUrl {
+		log.ErrorfWithRequestId(c, "[oidc_provider.getMetadata] issuer \"%s\" of provider metadata does not match the issuer url \"%s\"", metadata.Issuer, p.issuerUrl)
+		return nil, errs.ErrOIDCProviderRequestFailed
+	}
+
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JwksUri == "" {
+		log.ErrorfWithRequestId(c, "[oidc_provider.getMetadata] provider metadata does not contain authorization endpoint, token endpoint or jwks uri")
+		return nil, errs.ErrOIDCProviderRequestFailed
+	}
+
+	p.metadata = metadata
+
+	return metadata, nil
+}
+
+func (p *OIDCProvider) getPublicKey(c *core.Context, keyId string) (any, error) {
+	metadata, err := p.getMetadata(c)
+
+	if err != nil {
+		return nil, err
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	publicKey := findPublicKey(p.publicKeys, keyId)
+
+	if publicKey != nil {
+		return publicKey, nil
+	}
+
+	// the provider may have rotated its signing keys, so fetch the key set again if the key is not found
+	body, err := p.requestProvider(c, metadata.JwksUri)
+
+	if err != nil {
+		return nil, err
+	}
+
+	publicKeys, err := parseJsonWebKeySet(body)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[oidc_provider.getPublicKey] failed to parse json web key set, because %s", err.Error())
+		return nil, errs.ErrOIDCProviderRequestFailed
+	}
+
+	p.publicKeys = publicKeys
+	publicKey = findPublicKey(p.publicKeys, keyId)
+
+	if publicKey == nil {
+		return nil, fmt.Errorf("public key \"%s\" not found", keyId)
+	}
+
+	return publicKey, nil
+}
+
+func (p *OIDCProvider) requestProvider(c *core.Context, requestUrl string) ([]byte, error) {
+	req, _ := http.NewRequest("GET", requestUrl, nil)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", fmt.Sprintf("ezBookkeeping/%s ", settings.Version))
+
+	resp, err := p.httpClient.Do(req)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[oidc_provider.requestProvider] failed to request \"%s\", because %s", requestUrl, err.Error())
+		return nil, errs.ErrOIDCProviderRequestFailed
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		log.ErrorfWithRequestId(c, "[oidc_provider.requestProvider] failed to get response from \"%s\", because response code is %d", requestUrl, resp.StatusCode)
+		return nil, errs.ErrOIDCProviderRequestFailed
+	}
+
+	body, err := io.ReadAll(resp.Body)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[oidc_provider.requestProvider] failed to read response from \"%s\", because %s", requestUrl, err.Error())
+		return nil, errs.ErrOIDCProviderRequestFailed
+	}
+
+	return body, nil
+}
+
+func findPublicKey(publicKeys map[string]any, keyId string) any {
+	if keyId != "" {
+		return publicKeys[keyId]
+	}
+
+	// the key id can be omitted only if there is only one key in the key set
+	if len(publicKeys) == 1 {
+		for _, publicKey := range publicKeys {
+			return publicKey
+		}
+	}
+
+	return nil
+}
+
+func getStringClaim(claims jwt.MapClaims, name string) string {
+	value, _ := claims[name].(string)
+	return value
+}
+
+func getBooleanClaim(claims jwt.MapClaims, name string) bool {
+	switch value := claims[name].(type) {
+	case bool:
+		return value
+	case string:
+		// some providers return boolean claims as string
+		return value == "true"
+	default:
+		return false
+	}
+}
@@ -0,0 +1,26 @@
+package oidc
+
+import (
+	"github.com/kyy-me/ezbookkeeping/pkg/settings"
+)
+
+// OIDCProviderContainer contains the current OpenID Connect provider
+type OIDCProviderContainer struct {
+	Current *OIDCProvider
+}
+
+// Initialize an OpenID Connect provider container singleton instance
+var (
+	Container = &OIDCProviderContainer{}
+)
+
+// InitializeOIDCProvider initializes the current OpenID Connect provider according to the config
+func InitializeOIDCProvider(config *settings.Config) error {
+	if !config.EnableOIDC {
+		Container.Current = nil
+		return nil
+	}
+
+	Container.Current = NewOIDCProvider(config)
+	return nil
+}
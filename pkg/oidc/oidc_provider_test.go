@@ -0,0 +1,443 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/settings"
+)
+
+const (
+	mockIdPClientId     = "ezbookkeeping"
+	mockIdPClientSecret = "client-secret"
+	mockIdPRedirectUrl  = "https://ezbookkeeping.example.com/login"
+)
+
+type mockAuthorizationRequest struct {
+	codeChallenge string
+	nonce         string
+}
+
+// mockIdP is a local OpenID Connect provider which issues id token by the claims specified by test case
+type mockIdP struct {
+	server        *httptest.Server
+	mutex         sync.Mutex
+	signingKey    *rsa.PrivateKey
+	signingKeyId  string
+	forgedKey     *rsa.PrivateKey
+	codes         map[string]*mockAuthorizationRequest
+	idTokenClaims func(issuer string, nonce string) jwt.MapClaims
+	jwksRequested int
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	idp := &mockIdP{
+		codes: make(map[string]*mockAuthorizationRequest),
+	}
+	idp.rotateSigningKey(t, "key-1")
+	idp.idTokenClaims = func(issuer string, nonce string) jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":                issuer,
+			"aud":                mockIdPClientId,
+			"sub":                "external-user-1",
+			"exp":                time.Now().Add(time.Hour).Unix(),
+			"iat":                time.Now().Unix(),
+			"nonce":              nonce,
+			"email":              "user1@example.com",
+			"email_verified":     true,
+			"preferred_username": "user1",
+			"name":               "User 1",
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(oidcDiscoveryPath, idp.handleDiscovery)
+	mux.HandleFunc("/jwks", idp.handleJwks)
+	mux.HandleFunc("/token", idp.handleToken)
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+
+	return idp
+}
+
+func (idp *mockIdP) rotateSigningKey(t *testing.T, keyId string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+
+	idp.mutex.Lock()
+	defer idp.mutex.Unlock()
+
+	idp.signingKey = key
+	idp.signingKeyId = keyId
+}
+
+// authorize simulates the user has logged in provider by the authorization url and returns the authorization code
+func (idp *mockIdP) authorize(t *testing.T, authorizationUrl string) string {
+	parsedUrl, err := url.Parse(authorizationUrl)
+	assert.Nil(t, err)
+
+	params := parsedUrl.Query()
+	assert.Equal(t, "code", params.Get("response_type"))
+	assert.Equal(t, mockIdPClientId, params.Get("client_id"))
+	assert.Equal(t, mockIdPRedirectUrl, params.Get("redirect_uri"))
+	assert.Equal(t, "S256", params.Get("code_challenge_method"))
+
+	idp.mutex.Lock()
+	defer idp.mutex.Unlock()
+
+	code := "code-" + params.Get("state")
+	idp.codes[code] = &mockAuthorizationRequest{
+		codeChallenge: params.Get("code_challenge"),
+		nonce:         params.Get("nonce"),
+	}
+
+	return code
+}
+
+func (idp *mockIdP) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	writeJson(w, http.StatusOK, map[string]any{
+		"issuer":                 idp.server.URL,
+		"authorization_endpoint": idp.server.URL + "/authorize",
+		"token_endpoint":         idp.server.URL + "/token",
+		"jwks_uri":               idp.server.URL + "/jwks",
+	})
+}
+
+func (idp *mockIdP) handleJwks(w http.ResponseWriter, r *http.Request) {
+	idp.mutex.Lock()
+	defer idp.mutex.Unlock()
+
+	idp.jwksRequested++
+	publicKey := idp.signingKey.PublicKey
+
+	writeJson(w, http.StatusOK, map[string]any{
+		"keys": []map[string]any{
+			{
+				"kty": "RSA",
+				"kid": idp.signingKeyId,
+				"use": "sig",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+			},
+		},
+	})
+}
+
+func (idp *mockIdP) handleToken(w http.ResponseWriter, r *http.Request) {
+	clientId, clientSecret, ok := r.BasicAuth()
+
+	if !ok || clientId != mockIdPClientId || clientSecret != mockIdPClientSecret {
+		writeJson(w, http.StatusUnauthorized, map[string]any{"error": "invalid_client"})
+		return
+	}
+
+	_ = r.ParseForm()
+
+	idp.mutex.Lock()
+	defer idp.mutex.Unlock()
+
+	request, exists := idp.codes[r.PostForm.Get("code")]
+
+	if !exists || r.PostForm.Get("grant_type") != "authorization_code" || r.PostForm.Get("redirect_uri") != mockIdPRedirectUrl {
+		writeJson(w, http.StatusBadRequest, map[string]any{"error": "invalid_grant"})
+		return
+	}
+
+	delete(idp.codes, r.PostForm.Get("code"))
+
+	if GetS256CodeChallenge(r.PostForm.Get("code_verifier")) != request.codeChallenge {
+		writeJson(w, http.StatusBadRequest, map[string]any{"error": "invalid_grant", "error_description": "code verifier mismatch"})
+		return
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, idp.idTokenClaims(idp.server.URL, request.nonce))
+	token.Header["kid"] = idp.signingKeyId
+	signingKey := idp.signingKey
+
+	if idp.forgedKey != nil {
+		signingKey = idp.forgedKey
+	}
+
+	idToken, _ := token.SignedString(signingKey)
+
+	writeJson(w, http.StatusOK, map[string]any{
+		"access_token": "access-token",
+		"token_type":   "Bearer",
+		"id_token":     idToken,
+	})
+}
+
+func writeJson(w http.ResponseWriter, statusCode int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func newTestOIDCProvider(idp *mockIdP) *OIDCProvider {
+	return NewOIDCProvider(&settings.Config{
+		EnableOIDC:         true,
+		OIDCIssuerUrl:      idp.server.URL,
+		OIDCClientId:       mockIdPClientId,
+		OIDCClientSecret:   mockIdPClientSecret,
+		OIDCRedirectUrl:    mockIdPRedirectUrl,
+		OIDCScopes:         []string{"openid", "email", "profile"},
+		OIDCUsernameClaim:  "preferred_username",
+		OIDCRequestTimeout: 10000,
+		OIDCProxy:          "none",
+	})
+}
+
+func newTestContext() *core.Context {
+	return &core.Context{
+		Context: &gin.Context{},
+	}
+}
+
+func authorizeByMockIdP(t *testing.T, idp *mockIdP, provider *OIDCProvider) (*IdTokenClaims, error) {
+	context := newTestContext()
+	state, err := NewAuthorizationState()
+	assert.Nil(t, err)
+
+	authorizationUrl, err := provider.GetAuthorizationUrl(context, state)
+	assert.Nil(t, err)
+
+	code := idp.authorize(t, authorizationUrl)
+
+	return provider.Authorize(context, code, state)
+}
+
+func TestOIDCProvider_GetAuthorizationUrl(t *testing.T) {
+	idp := newMockIdP(t)
+	provider := newTestOIDCProvider(idp)
+	state := &AuthorizationState{
+		State:        "state",
+		Nonce:        "nonce",
+		CodeVerifier: "dBjftJeZ4CVP-mJ92IbMTHwWjEJ9GXMlsbfQw7QT6Ls",
+	}
+
+	authorizationUrl, err := provider.GetAuthorizationUrl(newTestContext(), state)
+	assert.Nil(t, err)
+
+	parsedUrl, err := url.Parse(authorizationUrl)
+	assert.Nil(t, err)
+	assert.Equal(t, idp.server.URL+"/authorize", parsedUrl.Scheme+"://"+parsedUrl.Host+parsedUrl.Path)
+
+	params := parsedUrl.Query()
+	assert.Equal(t, "code", params.Get("response_type"))
+	assert.Equal(t, mockIdPClientId, params.Get("client_id"))
+	assert.Equal(t, mockIdPRedirectUrl, params.Get("redirect_uri"))
+	assert.Equal(t, "openid email profile", params.Get("scope"))
+	assert.Equal(t, "state", params.Get("state"))
+	assert.Equal(t, "nonce", params.Get("nonce"))
+	assert.Equal(t, "af62j6m3uXvTuAK5ExMqztzf9k2xoIqXZczC9GWztB0", params.Get("code_challenge"))
+	assert.Equal(t, "S256", params.Get("code_challenge_method"))
+}
+
+func TestOIDCProvider_GetAuthorizationUrlWithIssuerMismatch(t *testing.T) {
+	idp := newMockIdP(t)
+	provider := newTestOIDCProvider(idp)
+	provider.issuerUrl = idp.server.URL + "/another"
+
+	_, err := provider.GetAuthorizationUrl(newTestContext(), &AuthorizationState{})
+	assert.Equal(t, errs.ErrOIDCProviderRequestFailed, err)
+}
+
+func TestOIDCProvider_Authorize(t *testing.T) {
+	idp := newMockIdP(t)
+	provider := newTestOIDCProvider(idp)
+
+	claims, err := authorizeByMockIdP(t, idp, provider)
+	assert.Nil(t, err)
+	assert.Equal(t, "external-user-1", claims.Subject)
+	assert.Equal(t, "user1@example.com", claims.Email)
+	assert.Equal(t, true, claims.EmailVerified)
+	assert.Equal(t, "user1", claims.Username)
+	assert.Equal(t, "User 1", claims.Nickname)
+}
+
+func TestOIDCProvider_AuthorizeWithStringEmailVerifiedClaim(t *testing.T) {
+	idp := newMockIdP(t)
+	provider := newTestOIDCProvider(idp)
+	defaultClaims := idp.idTokenClaims
+	idp.idTokenClaims = func(issuer string, nonce string) jwt.MapClaims {
+		claims := defaultClaims(issuer, nonce)
+		claims["email_verified"] = "true"
+		return claims
+	}
+
+	claims, err := authorizeByMockIdP(t, idp, provider)
+	assert.Nil(t, err)
+	assert.Equal(t, true, claims.EmailVerified)
+
+	idp.idTokenClaims = func(issuer string, nonce string) jwt.MapClaims {
+		claims := defaultClaims(issuer, nonce)
+		delete(claims, "email_verified")
+		return claims
+	}
+
+	claims, err = authorizeByMockIdP(t, idp, provider)
+	assert.Nil(t, err)
+	assert.Equal(t, false, claims.EmailVerified)
+}
+
+func TestOIDCProvider_AuthorizeWithWrongCodeVerifier(t *testing.T) {
+	idp := newMockIdP(t)
+	provider := newTestOIDCProvider(idp)
+	context := newTestContext()
+	state, err := NewAuthorizationState()
+	assert.Nil(t, err)
+
+	authorizationUrl, err := provider.GetAuthorizationUrl(context, state)
+	assert.Nil(t, err)
+
+	code := idp.authorize(t, authorizationUrl)
+	state.CodeVerifier, err = GenerateCodeVerifier()
+	assert.Nil(t, err)
+
+	_, err = provider.Authorize(context, code, state)
+	assert.Equal(t, errs.ErrOIDCAuthorizationCodeInvalid, err)
+}
+
+func TestOIDCProvider_AuthorizeWithWrongClientSecret(t *testing.T) {
+	idp := newMockIdP(t)
+	provider := newTestOIDCProvider(idp)
+	provider.clientSecret = "wrong-secret"
+
+	_, err := authorizeByMockIdP(t, idp, provider)
+	assert.Equal(t, errs.ErrOIDCAuthorizationCodeInvalid, err)
+}
+
+func TestOIDCProvider_AuthorizeWithWrongNonce(t *testing.T) {
+	idp := newMockIdP(t)
+	provider := newTestOIDCProvider(idp)
+	defaultClaims := idp.idTokenClaims
+	idp.idTokenClaims = func(issuer string, nonce string) jwt.MapClaims {
+		return defaultClaims(issuer, "another-nonce")
+	}
+
+	_, err := authorizeByMockIdP(t, idp, provider)
+	assert.Equal(t, errs.ErrOIDCIdTokenInvalid, err)
+}
+
+func TestOIDCProvider_AuthorizeWithWrongAudience(t *testing.T) {
+	idp := newMockIdP(t)
+	provider := newTestOIDCProvider(idp)
+	defaultClaims := idp.idTokenClaims
+	idp.idTokenClaims = func(issuer string, nonce string) jwt.MapClaims {
+		claims := defaultClaims(issuer, nonce)
+		claims["aud"] = "another-client"
+		return claims
+	}
+
+	_, err := authorizeByMockIdP(t, idp, provider)
+	assert.Equal(t, errs.ErrOIDCIdTokenInvalid, err)
+}
+
+func TestOIDCProvider_AuthorizeWithMultipleAudiences(t *testing.T) {
+	idp := newMockIdP(t)
+	provider := newTestOIDCProvider(idp)
+	defaultClaims := idp.idTokenClaims
+	idp.idTokenClaims = func(issuer string, nonce string) jwt.MapClaims {
+		claims := defaultClaims(issuer, nonce)
+		claims["aud"] = []string{"another-client", mockIdPClientId}
+		return claims
+	}
+
+	_, err := authorizeByMockIdP(t, idp, provider)
+	assert.Equal(t, errs.ErrOIDCIdTokenInvalid, err)
+
+	idp.idTokenClaims = func(issuer string, nonce string) jwt.MapClaims {
+		claims := defaultClaims(issuer, nonce)
+		claims["aud"] = []string{"another-client", mockIdPClientId}
+		claims["azp"] = mockIdPClientId
+		return claims
+	}
+
+	_, err = authorizeByMockIdP(t, idp, provider)
+	assert.Nil(t, err)
+}
+
+func TestOIDCProvider_AuthorizeWithWrongIssuer(t *testing.T) {
+	idp := newMockIdP(t)
+	provider := newTestOIDCProvider(idp)
+	defaultClaims := idp.idTokenClaims
+	idp.idTokenClaims = func(issuer string, nonce string) jwt.MapClaims {
+		return defaultClaims("https://another.example.com", nonce)
+	}
+
+	_, err := authorizeByMockIdP(t, idp, provider)
+	assert.Equal(t, errs.ErrOIDCIdTokenInvalid, err)
+}
+
+func TestOIDCProvider_AuthorizeWithExpiredIdToken(t *testing.T) {
+	idp := newMockIdP(t)
+	provider := newTestOIDCProvider(idp)
+	defaultClaims := idp.idTokenClaims
+	idp.idTokenClaims = func(issuer string, nonce string) jwt.MapClaims {
+		claims := defaultClaims(issuer, nonce)
+		claims["exp"] = time.Now().Add(-time.Minute).Unix()
+		return claims
+	}
+
+	_, err := authorizeByMockIdP(t, idp, provider)
+	assert.Equal(t, errs.ErrOIDCIdTokenInvalid, err)
+}
+
+func TestOIDCProvider_AuthorizeWithEmptySubject(t *testing.T) {
+	idp := newMockIdP(t)
+	provider := newTestOIDCProvider(idp)
+	defaultClaims := idp.idTokenClaims
+	idp.idTokenClaims = func(issuer string, nonce string) jwt.MapClaims {
+		claims := defaultClaims(issuer, nonce)
+		delete(claims, "sub")
+		return claims
+	}
+
+	_, err := authorizeByMockIdP(t, idp, provider)
+	assert.Equal(t, errs.ErrExternalUserIdIsEmpty, err)
+}
+
+func TestOIDCProvider_AuthorizeAfterSigningKeyRotated(t *testing.T) {
+	idp := newMockIdP(t)
+	provider := newTestOIDCProvider(idp)
+
+	_, err := authorizeByMockIdP(t, idp, provider)
+	assert.Nil(t, err)
+
+	_, err = authorizeByMockIdP(t, idp, provider)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, idp.jwksRequested)
+
+	idp.rotateSigningKey(t, "key-2")
+
+	_, err = authorizeByMockIdP(t, idp, provider)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, idp.jwksRequested)
+}
+
+func TestOIDCProvider_AuthorizeWithUnknownSigningKey(t *testing.T) {
+	idp := newMockIdP(t)
+	provider := newTestOIDCProvider(idp)
+	forgedKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	idp.forgedKey = forgedKey
+
+	_, err = authorizeByMockIdP(t, idp, provider)
+	assert.Equal(t, errs.ErrOIDCIdTokenInvalid, err)
+}
@@ -0,0 +1,27 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+const codeVerifierRandomBytesLength = 32
+
+// GenerateCodeVerifier returns a new random PKCE code verifier
+func GenerateCodeVerifier() (string, error) {
+	data := make([]byte, codeVerifierRandomBytesLength)
+	_, err := rand.Read(data)
+
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// GetS256CodeChallenge returns the PKCE code challenge of the code verifier by S256 method
+func GetS256CodeChallenge(codeVerifier string) string {
+	hash := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
@@ -54,6 +54,14 @@ func (s *AccountService) GetAllAccountsByUid(c *core.Context, uid int64) ([]*mod
 	return accounts, err
 }
 
+// GetAllUsedCurrencies returns all distinct currencies of the accounts of all users
+func (s *AccountService) GetAllUsedCurrencies(c *core.Context) ([]string, error) {
+	var currencies []string
+	err := s.UserDataDB(0).NewSession(c).Table(&models.Account{}).Where("deleted=?", false).Distinct("currency").Find(&currencies)
+
+	return currencies, err
+}
+
 // GetAccountAndSubAccountsByAccountId returns account model and sub-account models according to account id
 func (s *AccountService) GetAccountAndSubAccountsByAccountId(c *core.Context, uid int64, accountId int64) ([]*models.Account, error) {
 	if uid <= 0 {
@@ -28,6 +28,11 @@ func (s *ServiceUsingDB) UserDataDB(uid int64) *datastore.Database {
 	return s.container.UserDataStore.Choose(uid)
 }
 
+// ExchangeRateDB returns the datastore which contains exchange rate
+func (s *ServiceUsingDB) ExchangeRateDB() *datastore.Database {
+	return s.container.ExchangeRateStore.Choose(0)
+}
+
 // ServiceUsingConfig represents a service that need to use config
 type ServiceUsingConfig struct {
 	container *settings.ConfigContainer
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/datastore"
+	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+	"github.com/kyy-me/ezbookkeeping/pkg/uuid"
+	"github.com/kyy-me/ezbookkeeping/pkg/validators"
+)
+
+// CommodityService represents user-defined commodity service
+type CommodityService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize a commodity service singleton instance
+var (
+	Commodities = &CommodityService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAllCommoditiesByUid returns all commodity models of user
+func (s *CommodityService) GetAllCommoditiesByUid(c *core.Context, uid int64) ([]*models.Commodity, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var commodities []*models.Commodity
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).Find(&commodities)
+
+	return commodities, err
+}
+
+// GetCommodityByCommodityId returns a commodity model according to commodity id
+func (s *CommodityService) GetCommodityByCommodityId(c *core.Context, uid int64, commodityId int64) (*models.Commodity, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if commodityId <= 0 {
+		return nil, errs.ErrCommodityIdInvalid
+	}
+
+	commodity := &models.Commodity{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(commodityId).Where("uid=? AND deleted=?", uid, false).Get(commodity)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrCommodityNotFound
+	}
+
+	return commodity, nil
+}
+
+// GetCommoditiesByCodes returns commodity models according to commodity codes
+func (s *CommodityService) GetCommoditiesByCodes(c *core.Context, uid int64, codes []string) (map[string]*models.Commodity, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var commodities []*models.Commodity
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).In("code", codes).Find(&commodities)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetCommodityMapByList(commodities), nil
+}
+
+// GetCommodityMapOfAccounts returns the commodity map keyed by commodity code of all accounts which are denominated in commodity
+func (s *CommodityService) GetCommodityMapOfAccounts(c *core.Context, uid int64, accounts []*models.Account) (map[string]*models.Commodity, error) {
+	var commodityCodes []string
+
+	for i := 0; i < len(accounts); i++ {
+		currency := accounts[i].Currency
+
+		if currency == validators.ParentAccountCurrencyPlaceholder {
+			continue
+		}
+
+		if _, exists := validators.AllCurrencyNames[currency]; !exists {
+			commodityCodes = append(commodityCodes, currency)
+		}
+	}
+
+	if len(commodityCodes) < 1 {
+		return make(map[string]*models.Commodity), nil
+	}
+
+	return s.GetCommoditiesByCodes(c, uid, commodityCodes)
+}
+
+// CreateCommodity saves a new commodity model to database
+func (s *CommodityService) CreateCommodity(c *core.Context, commodity *models.Commodity) error {
+	if commodity.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	exists, err := s.UserDataDB(commodity.Uid).NewSession(c).Cols("code").Where("uid=? AND deleted=? AND code=?", commodity.Uid, false, commodity.Code).Exist(&models.Commodity{})
+
+	if err != nil {
+		return err
+	} else if exists {
+		return errs.ErrCommodityCodeAlreadyExists
+	}
+
+	commodity.CommodityId = s.GenerateUuid(uuid.UUID_TYPE_COMMODITY)
+
+	if commodity.CommodityId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	commodity.Deleted = false
+	commodity.CreatedUnixTime = time.Now().Unix()
+	commodity.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(commodity.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Insert(commodity)
+		return err
+	})
+}
+
+// ModifyCommodity saves an existed commodity model to database
+func (s *CommodityService) ModifyCommodity(c *core.Context, commodity *models.Commodity) error {
+	if commodity.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	commodity.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(commodity.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.ID(commodity.CommodityId).Cols("name", "price_source", "price_currency", "price", "price_updated_unix_time", "updated_unix_time").Where("uid=? AND deleted=?", commodity.Uid, false).Update(commodity)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrCommodityNotFound
+		}
+
+		return err
+	})
+}
+
+// DeleteCommodity deletes an existed commodity from database
+func (s *CommodityService) DeleteCommodity(c *core.Context, uid int64, commodityId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.Commodity{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		commodity := &models.Commodity{}
+		has, err := sess.ID(commodityId).Where("uid=? AND deleted=?", uid, false).Get(commodity)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrCommodityNotFound
+		}
+
+		exists, err := sess.Cols("uid", "currency").Where("uid=? AND deleted=? AND currency=?", uid, false, commodity.Code).Limit(1).Exist(&models.Account{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrCommodityInUseCannotBeDeleted
+		}
+
+		deletedRows, err := sess.ID(commodityId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrCommodityNotFound
+		}
+
+		return err
+	})
+}
+
+// GetCommodityMapByList returns a commodity map keyed by commodity code by a list
+func (s *CommodityService) GetCommodityMapByList(commodities []*models.Commodity) map[string]*models.Commodity {
+	commodityMap := make(map[string]*models.Commodity)
+
+	for i := 0; i < len(commodities); i++ {
+		commodity := commodities[i]
+		commodityMap[commodity.Code] = commodity
+	}
+
+	return commodityMap
+}
@@ -0,0 +1,256 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/datastore"
+	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+)
+
+// ExchangeRateService represents exchange rate service
+type ExchangeRateService struct {
+	ServiceUsingDB
+}
+
+// Initialize a exchange rate service singleton instance
+var (
+	ExchangeRates = &ExchangeRateService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+	}
+)
+
+// GetExchangeRateHistory returns the historical exchange rates of specified data source which can be used between the start date and end date
+func (s *ExchangeRateService) GetExchangeRateHistory(c *core.Context, dataSource string, startDate int32, endDate int32) (*models.ExchangeRateHistory, error) {
+	if dataSource == "" {
+		return nil, errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	latestExchangeRate := &models.ExchangeRate{}
+	latestCondition := "data_source=?"
+	latestConditionParams := []any{dataSource}
+
+	if endDate > 0 {
+		latestCondition = latestCondition + " AND date<=?"
+		latestConditionParams = append(latestConditionParams, endDate)
+	}
+
+	has, err := s.ExchangeRateDB().NewSession(c).Where(latestCondition, latestConditionParams...).OrderBy("date desc").Limit(1).Get(latestExchangeRate)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrExchangeRateNotFound
+	}
+
+	baseCurrency := latestExchangeRate.BaseCurrency
+	condition := "data_source=? AND base_currency=?"
+	conditionParams := []any{dataSource, baseCurrency}
+
+	if startDate > 0 {
+		var earliestExchangeRates []*models.ExchangeRate
+		err = s.ExchangeRateDB().NewSession(c).Select("currency, MAX(date) AS date").Where("data_source=? AND base_currency=? AND date<=?", dataSource, baseCurrency, startDate).GroupBy("currency").Find(&earliestExchangeRates)
+
+		if err != nil {
+			return nil, err
+		}
+
+		minDate := startDate
+
+		for i := 0; i < len(earliestExchangeRates); i++ {
+			if earliestExchangeRates[i].Date < minDate {
+				minDate = earliestExchangeRates[i].Date
+			}
+		}
+
+		condition = condition + " AND date>=?"
+		conditionParams = append(conditionParams, minDate)
+	}
+
+	if endDate > 0 {
+		condition = condition + " AND date<=?"
+		conditionParams = append(conditionParams, endDate)
+	}
+
+	var exchangeRates []*models.ExchangeRate
+	err = s.ExchangeRateDB().NewSession(c).Where(condition, conditionParams...).OrderBy("date asc").Find(&exchangeRates)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewExchangeRateHistory(dataSource, baseCurrency, exchangeRates), nil
+}
+
+// GetMergedExchangeRateHistory returns the historical exchange rates of the first data source which has exchange rates between the start date and end date,
+// the currencies and the earlier dates which do not exist in it would be added from the following data sources with the rates converted into its base currency
+func (s *ExchangeRateService) GetMergedExchangeRateHistory(c *core.Context, dataSources []string, startDate int32, endDate int32) (*models.ExchangeRateHistory, error) {
+	var mergedHistory *models.ExchangeRateHistory
+
+	for i := 0; i < len(dataSources); i++ {
+		exchangeRateHistory, err := s.GetExchangeRateHistory(c, dataSources[i], startDate, endDate)
+
+		if err == errs.ErrExchangeRateNotFound {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		if mergedHistory == nil {
+			mergedHistory = exchangeRateHistory
+		} else {
+			mergedHistory.MergeMissingCurrencies(exchangeRateHistory)
+		}
+	}
+
+	if mergedHistory == nil {
+		return nil, errs.ErrExchangeRateNotFound
+	}
+
+	return mergedHistory, nil
+}
+
+// SaveExchangeRates saves the specified exchange rates into database, the existed exchange rates with the same data source, base currency, currency and date would be updated
+func (s *ExchangeRateService) SaveExchangeRates(c *core.Context, exchangeRates []*models.ExchangeRate) (int, error) {
+	groupedExchangeRates := make(map[string][]*models.ExchangeRate)
+	groupKeys := make([]string, 0)
+
+	for i := 0; i < len(exchangeRates); i++ {
+		exchangeRate := exchangeRates[i]
+
+		if exchangeRate.DataSource == "" || exchangeRate.BaseCurrency == "" || exchangeRate.Currency == "" || exchangeRate.Date <= 0 {
+			return 0, errs.ErrExchangeRateDateInvalid
+		}
+
+		groupKey := fmt.Sprintf("%s_%s_%d", exchangeRate.DataSource, exchangeRate.BaseCurrency, exchangeRate.Date)
+
+		if _, exists := groupedExchangeRates[groupKey]; !exists {
+			groupKeys = append(groupKeys, groupKey)
+		}
+
+		groupedExchangeRates[groupKey] = append(groupedExchangeRates[groupKey], exchangeRate)
+	}
+
+	savedCount := 0
+
+	for i := 0; i < len(groupKeys); i++ {
+		rates := groupedExchangeRates[groupKeys[i]]
+		now := time.Now().Unix()
+
+		err := s.ExchangeRateDB().DoTransaction(c, func(sess *xorm.Session) error {
+			var existedExchangeRates []*models.ExchangeRate
+			err := sess.Where("data_source=? AND base_currency=? AND date=?", rates[0].DataSource, rates[0].BaseCurrency, rates[0].Date).Find(&existedExchangeRates)
+
+			if err != nil {
+				return err
+			}
+
+			existedRates := make(map[string]string, len(existedExchangeRates))
+
+			for j := 0; j < len(existedExchangeRates); j++ {
+				existedRates[existedExchangeRates[j].Currency] = existedExchangeRates[j].Rate
+			}
+
+			for j := 0; j < len(rates); j++ {
+				exchangeRate := rates[j]
+				existedRate, exists := existedRates[exchangeRate.Currency]
+
+				if exists && existedRate == exchangeRate.Rate {
+					continue
+				}
+
+				exchangeRate.UpdatedUnixTime = now
+
+				if exists {
+					_, err = sess.Cols("rate", "updated_unix_time").Where("data_source=? AND base_currency=? AND currency=? AND date=?", exchangeRate.DataSource, exchangeRate.BaseCurrency, exchangeRate.Currency, exchangeRate.Date).Update(exchangeRate)
+				} else {
+					exchangeRate.CreatedUnixTime = now
+					_, err = sess.Insert(exchangeRate)
+				}
+
+				if err != nil {
+					return err
+				}
+
+				existedRates[exchangeRate.Currency] = exchangeRate.Rate
+				savedCount++
+			}
+
+			return nil
+		})
+
+		if err != nil {
+			return savedCount, err
+		}
+	}
+
+	return savedCount, nil
+}
+
+// GetAllUserExchangeRatesByUid returns all exchange rates provided by user
+func (s *ExchangeRateService) GetAllUserExchangeRatesByUid(c *core.Context, uid int64) ([]*models.UserExchangeRate, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var userExchangeRates []*models.UserExchangeRate
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=?", uid).OrderBy("currency asc, date asc").Find(&userExchangeRates)
+
+	return userExchangeRates, err
+}
+
+// SaveUserExchangeRate saves the exchange rate provided by user into database, the existed exchange rate with the same currency and date would be updated
+func (s *ExchangeRateService) SaveUserExchangeRate(c *core.Context, userExchangeRate *models.UserExchangeRate) error {
+	if userExchangeRate.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if userExchangeRate.Date < 0 {
+		return errs.ErrExchangeRateDateInvalid
+	}
+
+	now := time.Now().Unix()
+	userExchangeRate.UpdatedUnixTime = now
+
+	return s.UserDataDB(userExchangeRate.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := sess.Where("uid=? AND currency=? AND date=?", userExchangeRate.Uid, userExchangeRate.Currency, userExchangeRate.Date).Exist(&models.UserExchangeRate{})
+
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			_, err = sess.Cols("base_currency", "rate", "updated_unix_time").Where("uid=? AND currency=? AND date=?", userExchangeRate.Uid, userExchangeRate.Currency, userExchangeRate.Date).Update(userExchangeRate)
+		} else {
+			userExchangeRate.CreatedUnixTime = now
+			_, err = sess.Insert(userExchangeRate)
+		}
+
+		return err
+	})
+}
+
+// DeleteUserExchangeRate deletes the exchange rate provided by user of the specified currency and date from database
+func (s *ExchangeRateService) DeleteUserExchangeRate(c *core.Context, uid int64, currency string, date int32) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.Where("uid=? AND currency=? AND date=?", uid, currency, date).Delete(&models.UserExchangeRate{})
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrUserExchangeRateNotFound
+		}
+
+		return nil
+	})
+}
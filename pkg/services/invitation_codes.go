@@ -0,0 +1,196 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/datastore"
+	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+	"github.com/kyy-me/ezbookkeeping/pkg/utils"
+	"github.com/kyy-me/ezbookkeeping/pkg/uuid"
+)
+
+// InvitationCodeService represents invitation code service
+type InvitationCodeService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize an invitation code service singleton instance
+var (
+	InvitationCodes = &InvitationCodeService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetInvitationCodes returns the invitation codes which are not revoked and whose id is not greater than the specified id in descending order
+func (s *InvitationCodeService) GetInvitationCodes(c *core.Context, maxCodeId int64, count int32) ([]*models.InvitationCode, error) {
+	condition := "deleted=?"
+	conditionParams := []any{false}
+
+	if maxCodeId > 0 {
+		condition = condition + " AND code_id<=?"
+		conditionParams = append(conditionParams, maxCodeId)
+	}
+
+	var invitationCodes []*models.InvitationCode
+	err := s.UserDB().NewSession(c).Where(condition, conditionParams...).Limit(int(count), 0).OrderBy("code_id desc").Find(&invitationCodes)
+
+	return invitationCodes, err
+}
+
+// GetAllInvitationCodes returns all invitation codes which are not revoked in descending order
+func (s *InvitationCodeService) GetAllInvitationCodes(c *core.Context) ([]*models.InvitationCode, error) {
+	var invitationCodes []*models.InvitationCode
+	err := s.UserDB().NewSession(c).Where("deleted=?", false).OrderBy("code_id desc").Find(&invitationCodes)
+
+	return invitationCodes, err
+}
+
+// CreateInvitationCode generates a new invitation code with the specified max uses, expiry and optional bound email and saves to database,
+// the creator uid is zero if the invitation code is created by command line
+func (s *InvitationCodeService) CreateInvitationCode(c *core.Context, creatorUid int64, maxUses uint32, expiresInDays uint32, boundEmail string) (*models.InvitationCode, error) {
+	if maxUses < 1 || maxUses > models.InvitationCodeMaxUses {
+		return nil, errs.ErrInvitationCodeMaxUsesInvalid
+	}
+
+	if expiresInDays < 1 || expiresInDays > models.InvitationCodeMaxExpiresInDays {
+		return nil, errs.ErrInvitationCodeExpiryInvalid
+	}
+
+	code, err := utils.GetRandomNumberOrLetter(models.InvitationCodeLength)
+
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	invitationCode := &models.InvitationCode{
+		CodeId:          s.GenerateUuid(uuid.UUID_TYPE_INVITATION_CODE),
+		Code:            code,
+		CreatorUid:      creatorUid,
+		BoundEmail:      strings.TrimSpace(boundEmail),
+		MaxUses:         maxUses,
+		UsedCount:       0,
+		Deleted:         false,
+		ExpiredUnixTime: now.Add(time.Duration(expiresInDays) * 24 * time.Hour).Unix(),
+		CreatedUnixTime: now.Unix(),
+		UpdatedUnixTime: now.Unix(),
+	}
+
+	if invitationCode.CodeId < 1 {
+		return nil, errs.ErrSystemIsBusy
+	}
+
+	err = s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Insert(invitationCode)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return invitationCode, nil
+}
+
+// ConsumeInvitationCode checks whether the invitation code can be used for registering with the specified email,
+// and increases its used count
+func (s *InvitationCodeService) ConsumeInvitationCode(c *core.Context, code string, email string) (*models.InvitationCode, error) {
+	if code == "" {
+		return nil, errs.ErrInvitationCodeIsEmpty
+	}
+
+	invitationCode := &models.InvitationCode{}
+
+	err := s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		has, err := sess.Where("code=? AND deleted=?", code, false).Get(invitationCode)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrInvitationCodeInvalid
+		}
+
+		now := time.Now().Unix()
+
+		if !invitationCode.IsAvailable(now) {
+			return errs.ErrInvitationCodeInvalid
+		}
+
+		if invitationCode.BoundEmail != "" && !strings.EqualFold(invitationCode.BoundEmail, email) {
+			return errs.ErrInvitationCodeEmailMismatch
+		}
+
+		// The conditions make sure the code would not be used more than max uses when it is consumed concurrently
+		updatedRows, err := sess.ID(invitationCode.CodeId).SetExpr("used_count", "used_count+1").Cols("updated_unix_time").
+			Where("deleted=? AND used_count<max_uses AND expired_unix_time>?", false, now).
+			Update(&models.InvitationCode{UpdatedUnixTime: now})
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrInvitationCodeInvalid
+		}
+
+		invitationCode.UsedCount++
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return invitationCode, nil
+}
+
+// ReleaseInvitationCode decreases the used count of the invitation code, it is used when registering failed after the code is consumed
+func (s *InvitationCodeService) ReleaseInvitationCode(c *core.Context, codeId int64) error {
+	if codeId <= 0 {
+		return errs.ErrInvitationCodeIdInvalid
+	}
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.ID(codeId).SetExpr("used_count", "used_count-1").Cols("updated_unix_time").
+			Where("used_count>?", 0).
+			Update(&models.InvitationCode{UpdatedUnixTime: time.Now().Unix()})
+		return err
+	})
+}
+
+// RevokeInvitationCode sets the invitation code deleted, so it can no longer be used for registering
+func (s *InvitationCodeService) RevokeInvitationCode(c *core.Context, codeId int64) error {
+	if codeId <= 0 {
+		return errs.ErrInvitationCodeIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.InvitationCode{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.ID(codeId).Cols("deleted", "deleted_unix_time").Where("deleted=?", false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrInvitationCodeNotFound
+		}
+
+		return nil
+	})
+}
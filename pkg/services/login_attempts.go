@@ -0,0 +1,239 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/datastore"
+	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/locales"
+	"github.com/kyy-me/ezbookkeeping/pkg/mail"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+	"github.com/kyy-me/ezbookkeeping/pkg/settings"
+	"github.com/kyy-me/ezbookkeeping/pkg/templates"
+)
+
+const (
+	loginAttemptMaxBackoffDelay    = 30 * time.Second
+	loginAttemptMaxLockoutDuration = 24 * time.Hour
+	loginAttemptResetDuration      = 24 * time.Hour
+	loginAttemptMaxUpdateRetries   = 3
+)
+
+var errLoginAttemptConcurrentlyUpdated = errors.New("login attempt has been updated concurrently")
+
+// LoginAttemptService represents login attempt service
+type LoginAttemptService struct {
+	ServiceUsingDB
+	ServiceUsingConfig
+	ServiceUsingMailer
+}
+
+// Initialize a login attempt service singleton instance
+var (
+	LoginAttempts = &LoginAttemptService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingConfig: ServiceUsingConfig{
+			container: settings.Container,
+		},
+		ServiceUsingMailer: ServiceUsingMailer{
+			container: mail.Container,
+		},
+	}
+)
+
+// GetBlockedRemainingSeconds returns how many seconds are left before the ip address or the account can try to login again,
+// returns zero if login is allowed now
+func (s *LoginAttemptService) GetBlockedRemainingSeconds(c *core.Context, attemptKey string) (int64, error) {
+	loginAttempt := &models.LoginAttempt{}
+	has, err := s.UserDB().NewSession(c).Where("attempt_key=?", attemptKey).Get(loginAttempt)
+
+	if err != nil {
+		return 0, err
+	} else if !has {
+		return 0, nil
+	}
+
+	remainingSeconds := loginAttempt.LockedUntilUnixTime - time.Now().Unix()
+
+	if remainingSeconds < 0 {
+		return 0, nil
+	}
+
+	return remainingSeconds, nil
+}
+
+// RecordFailedAttempt increases the failed count of the ip address or the account and blocks the next login attempt
+// exponentially, returns the lockout duration if the failed count reaches the maximum failed attempts, otherwise returns zero
+func (s *LoginAttemptService) RecordFailedAttempt(c *core.Context, attemptKey string, maxFailedAttempts uint32) (*models.LoginAttempt, time.Duration, error) {
+	if maxFailedAttempts < 1 {
+		return nil, 0, nil
+	}
+
+	var err error
+
+	for i := 0; i < loginAttemptMaxUpdateRetries; i++ {
+		var loginAttempt *models.LoginAttempt
+		var lockoutDuration time.Duration
+
+		loginAttempt, lockoutDuration, err = s.recordFailedAttempt(c, attemptKey, maxFailedAttempts)
+
+		if err == nil {
+			return loginAttempt, lockoutDuration, nil
+		}
+	}
+
+	return nil, 0, err
+}
+
+// ClearFailedAttempts deletes the failed login attempts of the ip address or the account from database
+func (s *LoginAttemptService) ClearFailedAttempts(c *core.Context, attemptKey string) (bool, error) {
+	var deletedRows int64
+
+	err := s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		var err error
+		deletedRows, err = sess.Where("attempt_key=?", attemptKey).Delete(&models.LoginAttempt{})
+		return err
+	})
+
+	return deletedRows > 0, err
+}
+
+// SendAccountLockedEmail sends an email to notify user that the account has been locked because of failed login attempts
+func (s *LoginAttemptService) SendAccountLockedEmail(user *models.User, loginAttempt *models.LoginAttempt, lockoutDuration time.Duration, clientIp string, backupLocale string) error {
+	if !s.CurrentConfig().EnableSMTP {
+		return errs.ErrSMTPServerNotEnabled
+	}
+
+	locale := user.Language
+
+	if locale == "" {
+		locale = backupLocale
+	}
+
+	localeTextItems := locales.GetLocaleTextItems(locale)
+	accountLockedTextItems := localeTextItems.AccountLockedMailTextItems
+
+	tmpl, err := templates.GetTemplate(templates.TEMPLATE_ACCOUNT_LOCKED)
+
+	if err != nil {
+		return err
+	}
+
+	templateParams := map[string]any{
+		"AppName": s.CurrentConfig().AppName,
+		"AccountLockedMail": map[string]any{
+			"Title":       accountLockedTextItems.Title,
+			"Salutation":  fmt.Sprintf(accountLockedTextItems.SalutationFormat, user.Nickname),
+			"Description": fmt.Sprintf(accountLockedTextItems.DescriptionFormat, lockoutDuration.Minutes(), loginAttempt.FailedCount, clientIp),
+			"Suggestion":  accountLockedTextItems.Suggestion,
+		},
+	}
+
+	var bodyBuffer bytes.Buffer
+	err = tmpl.Execute(&bodyBuffer, templateParams)
+
+	if err != nil {
+		return err
+	}
+
+	message := &mail.MailMessage{
+		To:      user.Email,
+		Subject: accountLockedTextItems.Title,
+		Body:    bodyBuffer.String(),
+	}
+
+	err = s.SendMail(message)
+
+	return err
+}
+
+func (s *LoginAttemptService) recordFailedAttempt(c *core.Context, attemptKey string, maxFailedAttempts uint32) (*models.LoginAttempt, time.Duration, error) {
+	now := time.Now()
+	loginAttempt := &models.LoginAttempt{}
+	var lockoutDuration time.Duration
+
+	err := s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		has, err := sess.Where("attempt_key=?", attemptKey).Get(loginAttempt)
+
+		if err != nil {
+			return err
+		}
+
+		oldFailedCount := loginAttempt.FailedCount
+
+		// the failed count would be reset if there is no failed attempt for a long time after the last block ends
+		if !has || now.Unix()-loginAttempt.LockedUntilUnixTime > int64(loginAttemptResetDuration/time.Second) {
+			loginAttempt.FailedCount = 0
+		}
+
+		loginAttempt.AttemptKey = attemptKey
+		loginAttempt.FailedCount++
+		loginAttempt.LastFailedUnixTime = now.Unix()
+
+		blockDuration, locked := s.getBlockDuration(loginAttempt.FailedCount, maxFailedAttempts)
+		loginAttempt.LockedUntilUnixTime = now.Add(blockDuration).Unix()
+
+		if locked {
+			lockoutDuration = blockDuration
+		}
+
+		if !has {
+			_, err = sess.Insert(loginAttempt)
+			return err
+		}
+
+		updatedRows, err := sess.Cols("failed_count", "last_failed_unix_time", "locked_until_unix_time").Where("attempt_key=? AND failed_count=?", attemptKey, oldFailedCount).Update(loginAttempt)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errLoginAttemptConcurrentlyUpdated
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return loginAttempt, lockoutDuration, nil
+}
+
+// getBlockDuration returns how long the next login attempt should be blocked, the delay doubles for each failed attempt
+// before reaching the maximum failed attempts, and the lockout duration doubles for each failed attempt after that
+func (s *LoginAttemptService) getBlockDuration(failedCount uint32, maxFailedAttempts uint32) (time.Duration, bool) {
+	if failedCount < maxFailedAttempts {
+		delay := time.Second
+
+		for i := uint32(1); i < failedCount && delay < loginAttemptMaxBackoffDelay; i++ {
+			delay *= 2
+		}
+
+		if delay > loginAttemptMaxBackoffDelay {
+			delay = loginAttemptMaxBackoffDelay
+		}
+
+		return delay, false
+	}
+
+	lockoutDuration := s.CurrentConfig().LoginLockoutDurationDuration
+
+	for i := maxFailedAttempts; i < failedCount && lockoutDuration < loginAttemptMaxLockoutDuration; i++ {
+		lockoutDuration *= 2
+	}
+
+	if lockoutDuration > loginAttemptMaxLockoutDuration {
+		lockoutDuration = loginAttemptMaxLockoutDuration
+	}
+
+	return lockoutDuration, true
+}
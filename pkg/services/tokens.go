@@ -19,6 +19,9 @@ import (
 	"github.com/kyy-me/ezbookkeeping/pkg/utils"
 )
 
+// The personal access tokens without expiry would be expired at 9999-12-31 23:59:59 UTC
+const personalAccessTokenNeverExpiredUnixTime int64 = 253402300799
+
 // TokenService represents user token service
 type TokenService struct {
 	ServiceUsingDB
@@ -63,6 +66,20 @@ func (s *TokenService) GetAllUnexpiredNormalTokensByUid(c *core.Context, uid int
 	return tokenRecords, err
 }
 
+// GetAllUnexpiredNormalAndPersonalAccessTokensByUid returns all available session tokens and personal access tokens of given user
+func (s *TokenService) GetAllUnexpiredNormalAndPersonalAccessTokensByUid(c *core.Context, uid int64) ([]*models.TokenRecord, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	var tokenRecords []*models.TokenRecord
+	err := s.TokenDB(uid).NewSession(c).Cols("uid", "user_token_id", "token_type", "user_agent", "name", "scopes", "created_unix_time", "expired_unix_time").Where("uid=? AND (token_type=? OR token_type=?) AND expired_unix_time>?", uid, core.USER_TOKEN_TYPE_NORMAL, core.USER_TOKEN_TYPE_PERSONAL_ACCESS, now).Find(&tokenRecords)
+
+	return tokenRecords, err
+}
+
 // ParseTokenByHeader returns the token model according to request data
 func (s *TokenService) ParseTokenByHeader(c *core.Context) (*jwt.Token, *core.UserTokenClaims, error) {
 	return s.parseToken(c, request.BearerExtractor{})
@@ -93,11 +110,70 @@ func (s *TokenService) CreateEmailVerifyToken(c *core.Context, user *models.User
 	return s.createToken(c, user, core.USER_TOKEN_TYPE_EMAIL_VERIFY, s.getUserAgent(c), s.CurrentConfig().EmailVerifyTokenExpiredTimeDuration)
 }
 
+// CreateEmailChangeToken generates a new email change confirmation token and saves to database
+func (s *TokenService) CreateEmailChangeToken(c *core.Context, user *models.User) (string, *core.UserTokenClaims, error) {
+	return s.createToken(c, user, core.USER_TOKEN_TYPE_EMAIL_CHANGE, s.getUserAgent(c), s.CurrentConfig().EmailVerifyTokenExpiredTimeDuration)
+}
+
 // CreatePasswordResetToken generates a new password reset token and saves to database
 func (s *TokenService) CreatePasswordResetToken(c *core.Context, user *models.User) (string, *core.UserTokenClaims, error) {
 	return s.createToken(c, user, core.USER_TOKEN_TYPE_PASSWORD_RESET, s.getUserAgent(c), s.CurrentConfig().PasswordResetTokenExpiredTimeDuration)
 }
 
+// CreatePersonalAccessToken generates a new personal access token with the specified name and scopes and saves to database,
+// the token would never expire if expires in days is zero
+func (s *TokenService) CreatePersonalAccessToken(c *core.Context, user *models.User, name string, scopes []core.TokenScope, expiresInDays uint32) (string, *models.TokenRecord, error) {
+	if name == "" {
+		return "", nil, errs.ErrParameterInvalid
+	}
+
+	if len(scopes) < 1 {
+		return "", nil, errs.ErrInvalidTokenScope
+	}
+
+	scopeNames := make([]string, 0, len(scopes))
+	scopeMap := make(map[core.TokenScope]bool, len(scopes))
+
+	for i := 0; i < len(scopes); i++ {
+		if !core.AllTokenScopes[scopes[i]] {
+			return "", nil, errs.ErrInvalidTokenScope
+		}
+
+		if scopeMap[scopes[i]] {
+			continue
+		}
+
+		scopeNames = append(scopeNames, string(scopes[i]))
+		scopeMap[scopes[i]] = true
+	}
+
+	now := time.Now()
+	expiredUnixTime := personalAccessTokenNeverExpiredUnixTime
+
+	if expiresInDays > 0 {
+		expiredUnixTime = now.Add(time.Duration(expiresInDays) * 24 * time.Hour).Unix()
+	}
+
+	tokenRecord := &models.TokenRecord{
+		Uid:             user.Uid,
+		UserTokenId:     s.getUserTokenId(),
+		TokenType:       core.USER_TOKEN_TYPE_PERSONAL_ACCESS,
+		UserAgent:       s.getUserAgent(c),
+		Name:            name,
+		Scopes:          strings.Join(scopeNames, models.TokenScopesSeparator),
+		CreatedUnixTime: now.Unix(),
+		ExpiredUnixTime: expiredUnixTime,
+	}
+
+	token, _, err := s.createTokenByRecord(c, user, tokenRecord)
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	return token, tokenRecord, nil
+}
+
 // DeleteToken deletes given token from database
 func (s *TokenService) DeleteToken(c *core.Context, tokenRecord *models.TokenRecord) error {
 	if tokenRecord.Uid <= 0 {
@@ -290,7 +366,6 @@ func (s *TokenService) parseToken(c *core.Context, extractor request.Extractor)
 }
 
 func (s *TokenService) createToken(c *core.Context, user *models.User, tokenType core.TokenType, userAgent string, expiryDate time.Duration) (string, *core.UserTokenClaims, error) {
-	var err error
 	now := time.Now()
 
 	tokenRecord := &models.TokenRecord{
@@ -302,6 +377,12 @@ func (s *TokenService) createToken(c *core.Context, user *models.User, tokenType
 		ExpiredUnixTime: now.Add(expiryDate).Unix(),
 	}
 
+	return s.createTokenByRecord(c, user, tokenRecord)
+}
+
+func (s *TokenService) createTokenByRecord(c *core.Context, user *models.User, tokenRecord *models.TokenRecord) (string, *core.UserTokenClaims, error) {
+	var err error
+
 	if tokenRecord.Secret, err = utils.GetRandomString(10); err != nil {
 		return "", nil, err
 	}
@@ -311,6 +392,7 @@ func (s *TokenService) createToken(c *core.Context, user *models.User, tokenType
 		Uid:         tokenRecord.Uid,
 		Username:    user.Username,
 		Type:        tokenRecord.TokenType,
+		Scopes:      tokenRecord.GetScopes(),
 		IssuedAt:    tokenRecord.CreatedUnixTime,
 		ExpiresAt:   tokenRecord.ExpiredUnixTime,
 	}
@@ -1006,7 +1006,7 @@ func (s *TransactionService) GetRelatedTransferTransaction(originalTransaction *
 }
 
 // GetAccountsTotalIncomeAndExpense returns the every accounts total income and expense amount by specific date range
-func (s *TransactionService) GetAccountsTotalIncomeAndExpense(c *core.Context, uid int64, startUnixTime int64, endUnixTime int64, utcOffset int16, useTransactionTimezone bool) (map[int64]int64, map[int64]int64, error) {
+func (s *TransactionService) GetAccountsTotalIncomeAndExpense(c *core.Context, uid int64, startUnixTime int64, endUnixTime int64, utcOffset int16, useTransactionTimezone bool, amountConverter *models.ExchangeRateAmountConverter) (map[int64]int64, map[int64]int64, error) {
 	if uid <= 0 {
 		return nil, nil, errs.ErrUserIdInvalid
 	}
@@ -1067,12 +1067,25 @@ func (s *TransactionService) GetAccountsTotalIncomeAndExpense(c *core.Context, u
 			timeZone = time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
 		}
 
-		localDateTime := utils.FormatUnixTimeToNumericLocalDateTime(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime), timeZone)
+		transactionUnixTime := utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
+		localDateTime := utils.FormatUnixTimeToNumericLocalDateTime(transactionUnixTime, timeZone)
 
 		if localDateTime < startLocalDateTime || localDateTime > endLocalDateTime {
 			continue
 		}
 
+		amount := transaction.Amount
+
+		if amountConverter != nil {
+			convertedAmount, err := amountConverter.ConvertAccountAmount(0, transaction.AccountId, amount, utils.FormatUnixTimeToNumericDate(transactionUnixTime, timeZone))
+
+			if err != nil {
+				return nil, nil, err
+			}
+
+			amount = convertedAmount
+		}
+
 		var amountsMap map[int64]int64
 
 		if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
@@ -1087,7 +1100,7 @@ func (s *TransactionService) GetAccountsTotalIncomeAndExpense(c *core.Context, u
 			totalAmounts = 0
 		}
 
-		totalAmounts += transaction.Amount
+		totalAmounts += amount
 		amountsMap[transaction.AccountId] = totalAmounts
 	}
 
@@ -1095,7 +1108,7 @@ func (s *TransactionService) GetAccountsTotalIncomeAndExpense(c *core.Context, u
 }
 
 // GetAccountsAndCategoriesTotalIncomeAndExpense returns the every accounts and categories total income and expense amount by specific date range
-func (s *TransactionService) GetAccountsAndCategoriesTotalIncomeAndExpense(c *core.Context, uid int64, startUnixTime int64, endUnixTime int64, utcOffset int16, useTransactionTimezone bool) ([]*models.Transaction, error) {
+func (s *TransactionService) GetAccountsAndCategoriesTotalIncomeAndExpense(c *core.Context, uid int64, startUnixTime int64, endUnixTime int64, utcOffset int16, useTransactionTimezone bool, amountConverter *models.ExchangeRateAmountConverter) ([]*models.Transaction, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
@@ -1169,12 +1182,25 @@ func (s *TransactionService) GetAccountsAndCategoriesTotalIncomeAndExpense(c *co
 			timeZone = time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
 		}
 
-		localDateTime := utils.FormatUnixTimeToNumericLocalDateTime(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime), timeZone)
+		transactionUnixTime := utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
+		localDateTime := utils.FormatUnixTimeToNumericLocalDateTime(transactionUnixTime, timeZone)
 
 		if (startLocalDateTime > 0 && localDateTime < startLocalDateTime) || (endLocalDateTime > 0 && localDateTime > endLocalDateTime) {
 			continue
 		}
 
+		amount := transaction.Amount
+
+		if amountConverter != nil {
+			convertedAmount, err := amountConverter.ConvertAccountAmount(0, transaction.AccountId, amount, utils.FormatUnixTimeToNumericDate(transactionUnixTime, timeZone))
+
+			if err != nil {
+				return nil, err
+			}
+
+			amount = convertedAmount
+		}
+
 		groupKey := fmt.Sprintf("%d_%d", transaction.CategoryId, transaction.AccountId)
 		totalAmounts, exists := transactionTotalAmountsMap[groupKey]
 
@@ -1188,7 +1214,7 @@ func (s *TransactionService) GetAccountsAndCategoriesTotalIncomeAndExpense(c *co
 			transactionTotalAmountsMap[groupKey] = totalAmounts
 		}
 
-		totalAmounts.Amount += transaction.Amount
+		totalAmounts.Amount += amount
 	}
 
 	transactionTotalAmounts := make([]*models.Transaction, 0, len(transactionTotalAmountsMap))
@@ -1200,18 +1226,27 @@ func (s *TransactionService) GetAccountsAndCategoriesTotalIncomeAndExpense(c *co
 	return transactionTotalAmounts, nil
 }
 
-// GetAccountsAndCategoriesMonthlyIncomeAndExpense returns the every accounts monthly income and expense amount by specific date range
-func (s *TransactionService) GetAccountsAndCategoriesMonthlyIncomeAndExpense(c *core.Context, uid int64, startYear int32, startMonth int32, endYear int32, endMonth int32, utcOffset int16, useTransactionTimezone bool) (map[int32][]*models.Transaction, error) {
+// GetAccountsAndCategoriesPeriodicIncomeAndExpense returns the every accounts and categories income and expense amount of every period by specific month range,
+// the key of returned map is the numeric first date of period, and every month in range starts on the month start day of period settings
+func (s *TransactionService) GetAccountsAndCategoriesPeriodicIncomeAndExpense(c *core.Context, uid int64, startYear int32, startMonth int32, endYear int32, endMonth int32, period *models.TransactionStatisticTrendsPeriod, utcOffset int16, useTransactionTimezone bool, amountConverter *models.ExchangeRateAmountConverter) (map[int32][]*models.Transaction, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
 
 	clientLocation := time.FixedZone("Client Timezone", int(utcOffset)*60)
+	monthStartDay := int(period.MonthStartDay)
+
+	if monthStartDay < 1 {
+		monthStartDay = 1
+	}
+
+	var startDate, endDate int32
 	var startTransactionTime, endTransactionTime int64
 	var err error
 
 	if startYear > 0 && startMonth > 0 {
-		startTransactionTime, _, err = utils.GetTransactionTimeRangeByYearMonth(startYear, startMonth)
+		startDate = utils.FormatTimeToNumericDate(time.Date(int(startYear), time.Month(startMonth), monthStartDay, 0, 0, 0, 0, time.UTC))
+		startTransactionTime, _, err = utils.GetTransactionTimeRangeByNumericDateRange(startDate, startDate)
 
 		if err != nil {
 			return nil, errs.ErrSystemError
@@ -1219,7 +1254,8 @@ func (s *TransactionService) GetAccountsAndCategoriesMonthlyIncomeAndExpense(c *
 	}
 
 	if endYear > 0 && endMonth > 0 {
-		_, endTransactionTime, err = utils.GetTransactionTimeRangeByYearMonth(endYear, endMonth)
+		endDate = utils.FormatTimeToNumericDate(time.Date(int(endYear), time.Month(endMonth)+1, monthStartDay-1, 0, 0, 0, 0, time.UTC))
+		_, endTransactionTime, err = utils.GetTransactionTimeRangeByNumericDateRange(endDate, endDate)
 
 		if err != nil {
 			return nil, errs.ErrSystemError
@@ -1270,10 +1306,8 @@ func (s *TransactionService) GetAccountsAndCategoriesMonthlyIncomeAndExpense(c *
 		maxTransactionTime = transactions[len(transactions)-1].TransactionTime - 1
 	}
 
-	startYearMonth := startYear*100 + startMonth
-	endYearMonth := endYear*100 + endMonth
-	transactionsMonthlyAmountsMap := make(map[string]*models.Transaction)
-	transactionsMonthlyAmounts := make(map[int32][]*models.Transaction)
+	transactionsPeriodicAmountsMap := make(map[string]*models.Transaction)
+	transactionsPeriodicAmounts := make(map[int32][]*models.Transaction)
 
 	for i := 0; i < len(allTransactions); i++ {
 		transaction := allTransactions[i]
@@ -1283,40 +1317,365 @@ func (s *TransactionService) GetAccountsAndCategoriesMonthlyIncomeAndExpense(c *
 			timeZone = time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
 		}
 
-		yearMonth := utils.FormatUnixTimeToNumericYearMonth(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime), timeZone)
+		transactionUnixTime := utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
+		localTime := time.Unix(transactionUnixTime, 0).In(timeZone)
+		localDate := utils.FormatTimeToNumericDate(localTime)
 
-		if (startYearMonth > 0 && yearMonth < startYearMonth) || (endYearMonth > 0 && yearMonth > endYearMonth) {
+		if (startDate > 0 && localDate < startDate) || (endDate > 0 && localDate > endDate) {
 			continue
 		}
 
-		groupKey := fmt.Sprintf("%d_%d_%d", yearMonth, transaction.CategoryId, transaction.AccountId)
-		transactionAmounts, exists := transactionsMonthlyAmountsMap[groupKey]
+		periodStartDate := period.GetPeriodStartDate(localTime)
+
+		amount := transaction.Amount
+
+		if amountConverter != nil {
+			convertedAmount, err := amountConverter.ConvertAccountAmount(periodStartDate, transaction.AccountId, amount, localDate)
+
+			if err != nil {
+				return nil, err
+			}
+
+			amount = convertedAmount
+		}
+
+		groupKey := fmt.Sprintf("%d_%d_%d", periodStartDate, transaction.CategoryId, transaction.AccountId)
+		transactionAmounts, exists := transactionsPeriodicAmountsMap[groupKey]
 
 		if !exists {
 			transactionAmounts = &models.Transaction{
 				CategoryId: transaction.CategoryId,
 				AccountId:  transaction.AccountId,
 			}
-			transactionsMonthlyAmountsMap[groupKey] = transactionAmounts
+			transactionsPeriodicAmountsMap[groupKey] = transactionAmounts
 		}
 
-		transactionAmounts.Amount += transaction.Amount
+		transactionAmounts.Amount += amount
 	}
 
-	for groupKey, transaction := range transactionsMonthlyAmountsMap {
+	for groupKey, transaction := range transactionsPeriodicAmountsMap {
 		groupKeyParts := strings.Split(groupKey, "_")
-		yearMonth, _ := utils.StringToInt32(groupKeyParts[0])
-		monthlyAmounts, exists := transactionsMonthlyAmounts[yearMonth]
+		periodStartDate, _ := utils.StringToInt32(groupKeyParts[0])
+		periodicAmounts, exists := transactionsPeriodicAmounts[periodStartDate]
 
 		if !exists {
-			monthlyAmounts = make([]*models.Transaction, 0, 0)
+			periodicAmounts = make([]*models.Transaction, 0, 0)
+		}
+
+		periodicAmounts = append(periodicAmounts, transaction)
+		transactionsPeriodicAmounts[periodStartDate] = periodicAmounts
+	}
+
+	return transactionsPeriodicAmounts, nil
+}
+
+// GetTagsTotalIncomeAndExpense returns the every tags and accounts total income and expense amount by specific date range,
+// the transaction with multiple tags is counted in each tag, and the untagged transactions are counted in tag id zero
+func (s *TransactionService) GetTagsTotalIncomeAndExpense(c *core.Context, uid int64, startUnixTime int64, endUnixTime int64, utcOffset int16, useTransactionTimezone bool, amountConverter *models.ExchangeRateAmountConverter) (map[int64][]*models.Transaction, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	clientLocation := time.FixedZone("Client Timezone", int(utcOffset)*60)
+	var startLocalDateTime, endLocalDateTime, startTransactionTime, endTransactionTime int64
+
+	if startUnixTime > 0 {
+		startLocalDateTime = utils.FormatUnixTimeToNumericLocalDateTime(startUnixTime, clientLocation)
+		startUnixTime = utils.GetMinUnixTimeWithSameLocalDateTime(startUnixTime, utcOffset)
+		startTransactionTime = utils.GetMinTransactionTimeFromUnixTime(startUnixTime)
+	}
+
+	if endUnixTime > 0 {
+		endLocalDateTime = utils.FormatUnixTimeToNumericLocalDateTime(endUnixTime, clientLocation)
+		endUnixTime = utils.GetMaxUnixTimeWithSameLocalDateTime(endUnixTime, utcOffset)
+		endTransactionTime = utils.GetMaxTransactionTimeFromUnixTime(endUnixTime)
+	}
+
+	condition := "uid=? AND deleted=? AND (type=? OR type=?)"
+	conditionParams := make([]any, 0, 4)
+	conditionParams = append(conditionParams, uid)
+	conditionParams = append(conditionParams, false)
+	conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_INCOME)
+	conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_EXPENSE)
+
+	minTransactionTime := startTransactionTime
+	maxTransactionTime := endTransactionTime
+	var allTransactions []*models.Transaction
+	allTransactionTagIds := make(map[int64][]int64)
+
+	for maxTransactionTime >= 0 {
+		var transactions []*models.Transaction
+
+		finalCondition := condition
+		finalConditionParams := make([]any, 0, 6)
+		finalConditionParams = append(finalConditionParams, conditionParams...)
+
+		if minTransactionTime > 0 {
+			finalCondition = finalCondition + " AND transaction_time>=?"
+			finalConditionParams = append(finalConditionParams, minTransactionTime)
+		}
+
+		if maxTransactionTime > 0 {
+			finalCondition = finalCondition + " AND transaction_time<=?"
+			finalConditionParams = append(finalConditionParams, maxTransactionTime)
+		}
+
+		err := s.UserDataDB(uid).NewSession(c).Select("transaction_id, type, account_id, transaction_time, timezone_utc_offset, amount").Where(finalCondition, finalConditionParams...).Limit(pageCountForLoadTransactionAmounts, 0).OrderBy("transaction_time desc").Find(&transactions)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(transactions) > 0 {
+			transactionIds := make([]int64, len(transactions))
+
+			for i := 0; i < len(transactions); i++ {
+				transactionIds[i] = transactions[i].TransactionId
+			}
+
+			var tagIndexs []*models.TransactionTagIndex
+			err = s.UserDataDB(uid).NewSession(c).Select("tag_id, transaction_id").Where("uid=? AND deleted=?", uid, false).In("transaction_id", transactionIds).Find(&tagIndexs)
+
+			if err != nil {
+				return nil, err
+			}
+
+			for i := 0; i < len(tagIndexs); i++ {
+				tagIndex := tagIndexs[i]
+				allTransactionTagIds[tagIndex.TransactionId] = append(allTransactionTagIds[tagIndex.TransactionId], tagIndex.TagId)
+			}
+		}
+
+		allTransactions = append(allTransactions, transactions...)
+
+		if len(transactions) < pageCountForLoadTransactionAmounts {
+			maxTransactionTime = -1
+			break
+		}
+
+		maxTransactionTime = transactions[len(transactions)-1].TransactionTime - 1
+	}
+
+	transactionTotalAmountsMap := make(map[string]*models.Transaction)
+	tagsTotalAmounts := make(map[int64][]*models.Transaction)
+
+	for i := 0; i < len(allTransactions); i++ {
+		transaction := allTransactions[i]
+		timeZone := clientLocation
+
+		if useTransactionTimezone {
+			timeZone = time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
+		}
+
+		transactionUnixTime := utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
+		localDateTime := utils.FormatUnixTimeToNumericLocalDateTime(transactionUnixTime, timeZone)
+
+		if (startLocalDateTime > 0 && localDateTime < startLocalDateTime) || (endLocalDateTime > 0 && localDateTime > endLocalDateTime) {
+			continue
+		}
+
+		amount := transaction.Amount
+
+		if amountConverter != nil {
+			convertedAmount, err := amountConverter.ConvertAccountAmount(0, transaction.AccountId, amount, utils.FormatUnixTimeToNumericDate(transactionUnixTime, timeZone))
+
+			if err != nil {
+				return nil, err
+			}
+
+			amount = convertedAmount
 		}
 
-		monthlyAmounts = append(monthlyAmounts, transaction)
-		transactionsMonthlyAmounts[yearMonth] = monthlyAmounts
+		tagIds, exists := allTransactionTagIds[transaction.TransactionId]
+
+		if !exists {
+			tagIds = []int64{0}
+		}
+
+		for j := 0; j < len(tagIds); j++ {
+			groupKey := fmt.Sprintf("%d_%d_%d", tagIds[j], transaction.AccountId, transaction.Type)
+			totalAmounts, exists := transactionTotalAmountsMap[groupKey]
+
+			if !exists {
+				totalAmounts = &models.Transaction{
+					Type:      transaction.Type,
+					AccountId: transaction.AccountId,
+					Amount:    0,
+				}
+
+				transactionTotalAmountsMap[groupKey] = totalAmounts
+				tagsTotalAmounts[tagIds[j]] = append(tagsTotalAmounts[tagIds[j]], totalAmounts)
+			}
+
+			totalAmounts.Amount += amount
+		}
+	}
+
+	return tagsTotalAmounts, nil
+}
+
+// GetExpenseTransactionsInGeoRange returns all expense transactions which have geographic location in the specific range and date range,
+// the range crosses the antimeridian when the minimum longitude is greater than the maximum longitude
+func (s *TransactionService) GetExpenseTransactionsInGeoRange(c *core.Context, uid int64, startUnixTime int64, endUnixTime int64, minLongitude float64, maxLongitude float64, minLatitude float64, maxLatitude float64) ([]*models.Transaction, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	condition := "uid=? AND deleted=? AND type=? AND geo_latitude>=? AND geo_latitude<=? AND (geo_longitude<>? OR geo_latitude<>?)"
+	conditionParams := make([]any, 0, 12)
+	conditionParams = append(conditionParams, uid)
+	conditionParams = append(conditionParams, false)
+	conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_EXPENSE)
+	conditionParams = append(conditionParams, minLatitude)
+	conditionParams = append(conditionParams, maxLatitude)
+	conditionParams = append(conditionParams, 0)
+	conditionParams = append(conditionParams, 0)
+
+	if minLongitude <= maxLongitude {
+		condition = condition + " AND geo_longitude>=? AND geo_longitude<=?"
+	} else {
+		condition = condition + " AND (geo_longitude>=? OR geo_longitude<=?)"
+	}
+
+	conditionParams = append(conditionParams, minLongitude)
+	conditionParams = append(conditionParams, maxLongitude)
+
+	if startUnixTime > 0 {
+		condition = condition + " AND transaction_time>=?"
+		conditionParams = append(conditionParams, utils.GetMinTransactionTimeFromUnixTime(startUnixTime))
+	}
+
+	maxTransactionTime := int64(0)
+
+	if endUnixTime > 0 {
+		maxTransactionTime = utils.GetMaxTransactionTimeFromUnixTime(endUnixTime)
+	}
+
+	var allTransactions []*models.Transaction
+
+	for maxTransactionTime >= 0 {
+		var transactions []*models.Transaction
+
+		finalCondition := condition
+		finalConditionParams := make([]any, 0, 12)
+		finalConditionParams = append(finalConditionParams, conditionParams...)
+
+		if maxTransactionTime > 0 {
+			finalCondition = finalCondition + " AND transaction_time<=?"
+			finalConditionParams = append(finalConditionParams, maxTransactionTime)
+		}
+
+		err := s.UserDataDB(uid).NewSession(c).Select("account_id, transaction_time, timezone_utc_offset, amount, geo_longitude, geo_latitude").Where(finalCondition, finalConditionParams...).Limit(pageCountForLoadTransactionAmounts, 0).OrderBy("transaction_time desc").Find(&transactions)
+
+		if err != nil {
+			return nil, err
+		}
+
+		allTransactions = append(allTransactions, transactions...)
+
+		if len(transactions) < pageCountForLoadTransactionAmounts {
+			break
+		}
+
+		maxTransactionTime = transactions[len(transactions)-1].TransactionTime - 1
+	}
+
+	return allTransactions, nil
+}
+
+// GetAllTransactionsInTimeRange returns all transactions (including both transfer out and transfer in) between the specific time range (both included)
+func (s *TransactionService) GetAllTransactionsInTimeRange(c *core.Context, uid int64, minUnixTime int64, maxUnixTime int64) ([]*models.Transaction, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	minTransactionTime := utils.GetMinTransactionTimeFromUnixTime(minUnixTime)
+	maxTransactionTime := utils.GetMaxTransactionTimeFromUnixTime(maxUnixTime)
+	var allTransactions []*models.Transaction
+
+	for maxTransactionTime >= minTransactionTime {
+		var transactions []*models.Transaction
+
+		err := s.UserDataDB(uid).NewSession(c).Select("transaction_id, type, category_id, account_id, transaction_time, timezone_utc_offset, amount, related_account_amount").Where("uid=? AND deleted=? AND transaction_time>=? AND transaction_time<=?", uid, false, minTransactionTime, maxTransactionTime).Limit(pageCountForLoadTransactionAmounts, 0).OrderBy("transaction_time desc").Find(&transactions)
+
+		if err != nil {
+			return nil, err
+		}
+
+		allTransactions = append(allTransactions, transactions...)
+
+		if len(transactions) < pageCountForLoadTransactionAmounts {
+			break
+		}
+
+		maxTransactionTime = transactions[len(transactions)-1].TransactionTime - 1
+	}
+
+	return allTransactions, nil
+}
+
+// GetTransferOutTransactionsInTimeRange returns all transfer out transactions between the specific time range (both included)
+func (s *TransactionService) GetTransferOutTransactionsInTimeRange(c *core.Context, uid int64, minUnixTime int64, maxUnixTime int64) ([]*models.Transaction, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	minTransactionTime := utils.GetMinTransactionTimeFromUnixTime(minUnixTime)
+	maxTransactionTime := utils.GetMaxTransactionTimeFromUnixTime(maxUnixTime)
+	var allTransactions []*models.Transaction
+
+	for maxTransactionTime >= minTransactionTime {
+		var transactions []*models.Transaction
+
+		err := s.UserDataDB(uid).NewSession(c).Select("transaction_id, type, account_id, related_account_id, transaction_time, timezone_utc_offset, amount, related_account_amount").Where("uid=? AND deleted=? AND type=? AND transaction_time>=? AND transaction_time<=?", uid, false, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, minTransactionTime, maxTransactionTime).Limit(pageCountForLoadTransactionAmounts, 0).OrderBy("transaction_time desc").Find(&transactions)
+
+		if err != nil {
+			return nil, err
+		}
+
+		allTransactions = append(allTransactions, transactions...)
+
+		if len(transactions) < pageCountForLoadTransactionAmounts {
+			break
+		}
+
+		maxTransactionTime = transactions[len(transactions)-1].TransactionTime - 1
+	}
+
+	return allTransactions, nil
+}
+
+// GetAccountsBalanceByMaxTime returns the every accounts balance at the specific time
+func (s *TransactionService) GetAccountsBalanceByMaxTime(c *core.Context, uid int64, maxUnixTime int64) (map[int64]int64, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var accountTypeAmounts []*models.Transaction
+	maxTransactionTime := utils.GetMaxTransactionTimeFromUnixTime(maxUnixTime)
+
+	err := s.UserDataDB(uid).NewSession(c).Select("account_id, type, SUM(amount) AS amount, SUM(related_account_amount) AS related_account_amount").Where("uid=? AND deleted=? AND transaction_time<=?", uid, false, maxTransactionTime).GroupBy("account_id, type").Find(&accountTypeAmounts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	accountsBalance := make(map[int64]int64)
+
+	for i := 0; i < len(accountTypeAmounts); i++ {
+		accountTypeAmount := accountTypeAmounts[i]
+
+		switch accountTypeAmount.Type {
+		case models.TRANSACTION_DB_TYPE_MODIFY_BALANCE:
+			accountsBalance[accountTypeAmount.AccountId] += accountTypeAmount.RelatedAccountAmount
+		case models.TRANSACTION_DB_TYPE_INCOME, models.TRANSACTION_DB_TYPE_TRANSFER_IN:
+			accountsBalance[accountTypeAmount.AccountId] += accountTypeAmount.Amount
+		case models.TRANSACTION_DB_TYPE_EXPENSE, models.TRANSACTION_DB_TYPE_TRANSFER_OUT:
+			accountsBalance[accountTypeAmount.AccountId] -= accountTypeAmount.Amount
+		}
 	}
 
-	return transactionsMonthlyAmounts, nil
+	return accountsBalance, nil
 }
 
 // GetTransactionMapByList returns a transaction map by a list
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/datastore"
+	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+	"github.com/kyy-me/ezbookkeeping/pkg/utils"
+	"github.com/kyy-me/ezbookkeeping/pkg/uuid"
+)
+
+// UserAuditEventService represents user audit event service
+type UserAuditEventService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize a user audit event service singleton instance
+var (
+	UserAuditEvents = &UserAuditEventService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetEventsByUid returns the audit events of user which are earlier than the specified event id in descending order
+func (s *UserAuditEventService) GetEventsByUid(c *core.Context, uid int64, maxEventId int64, count int32) ([]*models.UserAuditEvent, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	condition := "uid=?"
+	conditionParams := []any{uid}
+
+	if maxEventId > 0 {
+		condition = condition + " AND event_id<=?"
+		conditionParams = append(conditionParams, maxEventId)
+	}
+
+	var events []*models.UserAuditEvent
+	err := s.UserDB().NewSession(c).Where(condition, conditionParams...).Limit(int(count), 0).OrderBy("event_id desc").Find(&events)
+
+	return events, err
+}
+
+// GetAllEventsByUid returns all audit events of user in descending order
+func (s *UserAuditEventService) GetAllEventsByUid(c *core.Context, uid int64) ([]*models.UserAuditEvent, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var events []*models.UserAuditEvent
+	err := s.UserDB().NewSession(c).Where("uid=?", uid).OrderBy("event_id desc").Find(&events)
+
+	return events, err
+}
+
+// CreateEvent appends a new audit event of user with the client info of current request to database
+func (s *UserAuditEventService) CreateEvent(c *core.Context, uid int64, eventType models.UserAuditEventType, details string) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if len(details) > models.UserAuditEventMaxDetailsLength {
+		details = utils.SubString(details, 0, models.UserAuditEventMaxDetailsLength)
+	}
+
+	event := &models.UserAuditEvent{
+		EventId:         s.GenerateUuid(uuid.UUID_TYPE_AUDIT_EVENT),
+		Uid:             uid,
+		EventType:       eventType,
+		Details:         details,
+		CreatedUnixTime: time.Now().Unix(),
+	}
+
+	if event.EventId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	if c != nil {
+		event.ClientIp = c.ClientIP()
+		event.RequestId = c.GetRequestId()
+
+		if c.Request != nil {
+			event.UserAgent = c.Request.UserAgent()
+		}
+	}
+
+	if len(event.UserAgent) > models.TokenMaxUserAgentLength {
+		event.UserAgent = utils.SubString(event.UserAgent, 0, models.TokenMaxUserAgentLength)
+	}
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Insert(event)
+		return err
+	})
+}
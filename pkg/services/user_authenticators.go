@@ -0,0 +1,356 @@
+package services
+
+import (
+	"crypto/tls"
+	"strings"
+	"time"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/ldap"
+	"github.com/kyy-me/ezbookkeeping/pkg/log"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+	"github.com/kyy-me/ezbookkeeping/pkg/settings"
+	"github.com/kyy-me/ezbookkeeping/pkg/utils"
+	"github.com/kyy-me/ezbookkeeping/pkg/validators"
+)
+
+// UserAuthenticator is the backend which verifies the login name and password of user
+type UserAuthenticator interface {
+	// Name returns the name of the authenticator
+	Name() string
+
+	// Authenticate returns the user if the login name and password are valid
+	Authenticate(c *core.Context, loginName string, password string) (*models.User, error)
+}
+
+// UserAuthenticatorChain represents the user authenticators which are tried in order until one of them succeeds
+type UserAuthenticatorChain []UserAuthenticator
+
+// Authenticate returns the user which is authenticated by the first succeeded authenticator,
+// or returns the most meaningful error of all authenticators
+func (chain UserAuthenticatorChain) Authenticate(c *core.Context, loginName string, password string) (*models.User, error) {
+	var finalErr error = errs.ErrLoginNameInvalid
+
+	for i := 0; i < len(chain); i++ {
+		user, err := chain[i].Authenticate(c, loginName, password)
+
+		if err == nil {
+			return user, nil
+		}
+
+		log.DebugfWithRequestId(c, "[user_authenticators.Authenticate] authenticator \"%s\" failed to authenticate \"%s\", because %s", chain[i].Name(), loginName, err.Error())
+
+		// the error that the login name is not handled by the authenticator should not hide the error from others
+		if (err == errs.ErrLoginNameInvalid || err == errs.ErrUserNotFound) && finalErr != errs.ErrLoginNameInvalid {
+			continue
+		}
+
+		finalErr = err
+	}
+
+	return nil, finalErr
+}
+
+// LocalUserAuthenticator represents the authenticator which verifies the password stored in database
+type LocalUserAuthenticator struct {
+	users *UserService
+}
+
+// LDAPUserAuthenticator represents the authenticator which binds to LDAP directory, and creates local user on first login
+type LDAPUserAuthenticator struct {
+	ServiceUsingConfig
+	users             *UserService
+	userExternalAuths *UserExternalAuthService
+}
+
+// Initialize user authenticator singleton instances
+var (
+	LocalUserAuthenticators = &LocalUserAuthenticator{
+		users: Users,
+	}
+	LDAPUserAuthenticators = &LDAPUserAuthenticator{
+		ServiceUsingConfig: ServiceUsingConfig{
+			container: settings.Container,
+		},
+		users:             Users,
+		userExternalAuths: UserExternalAuths,
+	}
+)
+
+// Name returns the name of the authenticator
+func (a *LocalUserAuthenticator) Name() string {
+	return "local"
+}
+
+// Authenticate returns the user if the username or email and password are valid
+func (a *LocalUserAuthenticator) Authenticate(c *core.Context, loginName string, password string) (*models.User, error) {
+	user, err := a.users.GetUserByUsernameOrEmail(c, loginName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !a.users.IsPasswordEqualsUserPassword(password, user) {
+		return nil, errs.ErrUserPasswordWrong
+	}
+
+	if a.users.IsUserPasswordHashOutdated(user) {
+		err = a.users.UpdateUserPasswordHash(c, user, password)
+
+		if err != nil {
+			log.WarnfWithRequestId(c, "[user_authenticators.Authenticate] failed to rehash password of user \"uid:%d\", because %s", user.Uid, err.Error())
+		} else {
+			log.InfofWithRequestId(c, "[user_authenticators.Authenticate] password hash of user \"uid:%d\" has been upgraded", user.Uid)
+		}
+	}
+
+	return user, nil
+}
+
+// Name returns the name of the authenticator
+func (a *LDAPUserAuthenticator) Name() string {
+	return "ldap"
+}
+
+// Authenticate returns the local user of the LDAP user if the login name and password are valid in LDAP directory
+func (a *LDAPUserAuthenticator) Authenticate(c *core.Context, loginName string, password string) (*models.User, error) {
+	config := a.CurrentConfig()
+
+	if loginName == "" {
+		return nil, errs.ErrLoginNameInvalid
+	}
+
+	if password == "" {
+		return nil, errs.ErrPasswordIsEmpty
+	}
+
+	conn, err := ldap.Dial(config.LDAPServerUrl, config.LDAPStartTLS, &tls.Config{InsecureSkipVerify: config.LDAPSkipTLSVerify}, time.Duration(config.LDAPRequestTimeout)*time.Millisecond)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[user_authenticators.Authenticate] failed to connect to ldap server \"%s\", because %s", config.LDAPServerUrl, err.Error())
+		return nil, errs.ErrLDAPServerRequestFailed
+	}
+
+	defer conn.Close()
+
+	entry, err := a.searchUser(c, conn, loginName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = conn.Bind(entry.DN, password)
+
+	if ldap.IsResultCode(err, ldap.ResultCodeInvalidCredentials) {
+		return nil, errs.ErrUserPasswordWrong
+	} else if err != nil {
+		log.ErrorfWithRequestId(c, "[user_authenticators.Authenticate] failed to bind ldap user \"%s\", because %s", entry.DN, err.Error())
+		return nil, errs.ErrLDAPServerRequestFailed
+	}
+
+	externalUserId := entry.GetAttributeValue(config.LDAPUsernameAttribute)
+
+	if externalUserId == "" {
+		log.WarnfWithRequestId(c, "[user_authenticators.Authenticate] ldap user \"%s\" does not have attribute \"%s\"", entry.DN, config.LDAPUsernameAttribute)
+		return nil, errs.ErrExternalUserIdIsEmpty
+	}
+
+	if config.LDAPRequiredGroupDN != "" {
+		err = a.checkUserInRequiredGroup(c, conn, entry, externalUserId)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return a.getOrCreateUser(c, entry, externalUserId)
+}
+
+func (a *LDAPUserAuthenticator) searchUser(c *core.Context, conn *ldap.Conn, loginName string) (*ldap.Entry, error) {
+	config := a.CurrentConfig()
+
+	if config.LDAPBindDN != "" {
+		err := conn.Bind(config.LDAPBindDN, config.LDAPBindPassword)
+
+		if err != nil {
+			log.ErrorfWithRequestId(c, "[user_authenticators.searchUser] failed to bind ldap server by \"%s\", because %s", config.LDAPBindDN, err.Error())
+			return nil, errs.ErrLDAPServerRequestFailed
+		}
+	}
+
+	entries, err := conn.Search(&ldap.SearchRequest{
+		BaseDN:     config.LDAPBaseDN,
+		Scope:      ldap.ScopeWholeSubtree,
+		Filter:     strings.ReplaceAll(config.LDAPUserFilter, "{username}", ldap.EscapeFilterValue(loginName)),
+		Attributes: []string{config.LDAPUsernameAttribute, config.LDAPEmailAttribute, config.LDAPNicknameAttribute},
+		SizeLimit:  2,
+	})
+
+	if err != nil && !ldap.IsResultCode(err, ldap.ResultCodeSizeLimitExceeded) {
+		log.ErrorfWithRequestId(c, "[user_authenticators.searchUser] failed to search ldap user \"%s\", because %s", loginName, err.Error())
+		return nil, errs.ErrLDAPServerRequestFailed
+	}
+
+	if len(entries) < 1 {
+		return nil, errs.ErrUserNotFound
+	} else if len(entries) > 1 {
+		log.WarnfWithRequestId(c, "[user_authenticators.searchUser] more than one ldap user matches \"%s\"", loginName)
+		return nil, errs.ErrLDAPUserNotUnique
+	}
+
+	return entries[0], nil
+}
+
+func (a *LDAPUserAuthenticator) checkUserInRequiredGroup(c *core.Context, conn *ldap.Conn, entry *ldap.Entry, username string) error {
+	config := a.CurrentConfig()
+
+	// the group may not be readable by the user, so search it by the configured account again
+	if config.LDAPBindDN != "" {
+		err := conn.Bind(config.LDAPBindDN, config.LDAPBindPassword)
+
+		if err != nil {
+			log.ErrorfWithRequestId(c, "[user_authenticators.checkUserInRequiredGroup] failed to bind ldap server by \"%s\", because %s", config.LDAPBindDN, err.Error())
+			return errs.ErrLDAPServerRequestFailed
+		}
+	}
+
+	memberValue := entry.DN
+
+	if strings.EqualFold(config.LDAPGroupMemberAttribute, "memberUid") {
+		memberValue = username
+	}
+
+	groups, err := conn.Search(&ldap.SearchRequest{
+		BaseDN:     config.LDAPRequiredGroupDN,
+		Scope:      ldap.ScopeBaseObject,
+		Filter:     "(" + config.LDAPGroupMemberAttribute + "=" + ldap.EscapeFilterValue(memberValue) + ")",
+		Attributes: []string{"1.1"}, // no attributes
+		SizeLimit:  1,
+	})
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[user_authenticators.checkUserInRequiredGroup] failed to search ldap group \"%s\", because %s", config.LDAPRequiredGroupDN, err.Error())
+		return errs.ErrLDAPServerRequestFailed
+	}
+
+	if len(groups) < 1 {
+		log.WarnfWithRequestId(c, "[user_authenticators.checkUserInRequiredGroup] ldap user \"%s\" is not in group \"%s\"", entry.DN, config.LDAPRequiredGroupDN)
+		return errs.ErrLDAPUserNotInRequiredGroup
+	}
+
+	return nil
+}
+
+func (a *LDAPUserAuthenticator) getOrCreateUser(c *core.Context, entry *ldap.Entry, externalUserId string) (*models.User, error) {
+	config := a.CurrentConfig()
+	email := strings.TrimSpace(entry.GetAttributeValue(config.LDAPEmailAttribute))
+	userExternalAuth, err := a.userExternalAuths.GetUserExternalAuthByExternalUserId(c, models.USER_EXTERNAL_AUTH_TYPE_LDAP, externalUserId)
+
+	if err == nil {
+		user, err := a.users.GetUserById(c, userExternalAuth.Uid)
+
+		if err != nil {
+			return nil, err
+		}
+
+		userExternalAuth.ExternalUsername = entry.DN
+		userExternalAuth.ExternalEmail = email
+		err = a.userExternalAuths.UpdateUserExternalAuthLastLogin(c, userExternalAuth)
+
+		if err != nil {
+			log.WarnfWithRequestId(c, "[user_authenticators.getOrCreateUser] failed to update external auth last login time for user \"uid:%d\", because %s", user.Uid, err.Error())
+		}
+
+		return user, nil
+	} else if err != errs.ErrExternalUserNotLinked {
+		return nil, err
+	}
+
+	if email == "" {
+		return nil, errs.ErrExternalUserEmailIsEmpty
+	}
+
+	user, err := a.users.GetUserByUsername(c, externalUserId)
+
+	if err == nil {
+		// only link to the existed user which has the same username and email
+		if !strings.EqualFold(user.Email, email) {
+			return nil, errs.ErrUsernameAlreadyExists
+		}
+	} else if err == errs.ErrUserNotFound {
+		user, err = a.createUser(c, entry, externalUserId, email)
+
+		if err != nil {
+			return nil, err
+		}
+
+		log.InfofWithRequestId(c, "[user_authenticators.getOrCreateUser] user \"%s\" has been created for ldap user \"%s\", uid is %d", user.Username, entry.DN, user.Uid)
+	} else {
+		return nil, err
+	}
+
+	err = a.userExternalAuths.CreateUserExternalAuth(c, &models.UserExternalAuth{
+		Uid:              user.Uid,
+		ExternalAuthType: models.USER_EXTERNAL_AUTH_TYPE_LDAP,
+		ExternalUserId:   externalUserId,
+		ExternalUsername: entry.DN,
+		ExternalEmail:    email,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	log.InfofWithRequestId(c, "[user_authenticators.getOrCreateUser] ldap user \"%s\" has been linked to user \"uid:%d\"", entry.DN, user.Uid)
+
+	return user, nil
+}
+
+func (a *LDAPUserAuthenticator) createUser(c *core.Context, entry *ldap.Entry, username string, email string) (*models.User, error) {
+	config := a.CurrentConfig()
+
+	if len(username) > 32 || !utils.IsValidUsername(username) {
+		return nil, errs.ErrLoginNameInvalid
+	}
+
+	if len(email) > 100 || !utils.IsValidEmail(email) {
+		return nil, errs.ErrEmailIsEmptyOrInvalid
+	}
+
+	if _, exists := validators.AllCurrencyNames[config.LDAPDefaultUserCurrency]; !exists {
+		return nil, errs.ErrUserDefaultCurrencyIsInvalid
+	}
+
+	nickname := strings.TrimSpace(entry.GetAttributeValue(config.LDAPNicknameAttribute))
+
+	if nickname == "" {
+		nickname = username
+	}
+
+	// the password is random and never revealed, so the user can only login via ldap
+	password, err := utils.GetRandomString(32)
+
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		Username:             username,
+		Email:                email,
+		Nickname:             utils.SubString(nickname, 0, 64),
+		Password:             password,
+		DefaultCurrency:      config.LDAPDefaultUserCurrency,
+		FirstDayOfWeek:       models.WEEKDAY_SUNDAY,
+		TransactionEditScope: models.TRANSACTION_EDIT_SCOPE_ALL,
+		EmailVerified:        true,
+	}
+
+	err = a.users.CreateUser(c, user)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1,280 @@
+package services
+
+import (
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/log"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+)
+
+const userDataCheckPageCountForGettingTransactions = 1000
+
+// UserDataCheckService represents user data check service
+type UserDataCheckService struct {
+	accounts     *AccountService
+	transactions *TransactionService
+	categories   *TransactionCategoryService
+	tags         *TransactionTagService
+}
+
+// Initialize a user data check service singleton instance
+var (
+	UserDataChecks = &UserDataCheckService{
+		accounts:     Accounts,
+		transactions: Transactions,
+		categories:   TransactionCategories,
+		tags:         TransactionTags,
+	}
+)
+
+// CheckTransactionAndAccount checks whether all transactions and all accounts of the specified user are correct
+func (s *UserDataCheckService) CheckTransactionAndAccount(c *core.Context, uid int64) (bool, error) {
+	if uid <= 0 {
+		return false, errs.ErrUserIdInvalid
+	}
+
+	accountMap, categoryMap, tagMap, tagIndexs, err := s.getUserEssentialData(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[user_data_checks.CheckTransactionAndAccount] failed to get essential data for user \"uid:%d\", because %s", uid, err.Error())
+		return false, err
+	}
+
+	accountHasChild := make(map[int64]bool)
+
+	for _, account := range accountMap {
+		if account.ParentAccountId > models.LevelOneAccountParentId {
+			accountHasChild[account.ParentAccountId] = true
+		}
+	}
+
+	allTransactions, err := s.transactions.GetAllTransactions(c, uid, userDataCheckPageCountForGettingTransactions, false)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[user_data_checks.CheckTransactionAndAccount] failed to get all transactions for user \"uid:%d\", because %s", uid, err.Error())
+		return false, err
+	}
+
+	transactionMap := s.transactions.GetTransactionMapByList(allTransactions)
+	accountBalance := make(map[int64]int64)
+
+	for i := len(allTransactions) - 1; i >= 0; i-- {
+		transaction := allTransactions[i]
+
+		err := s.checkTransactionAccount(c, transaction, accountMap, accountHasChild)
+
+		if err != nil {
+			return false, err
+		}
+
+		err = s.checkTransactionCategory(c, transaction, categoryMap)
+
+		if err != nil {
+			return false, err
+		}
+
+		err = s.checkTransactionTag(c, transaction.TransactionId, tagIndexs, tagMap)
+
+		if err != nil {
+			return false, err
+		}
+
+		err = s.checkTransactionRelatedTransaction(c, transaction, transactionMap, accountMap)
+
+		if err != nil {
+			return false, err
+		}
+
+		balance, exists := accountBalance[transaction.AccountId]
+
+		if !exists {
+			balance = 0
+		}
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+			balance = balance + transaction.RelatedAccountAmount
+		} else if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+			balance = balance + transaction.Amount
+		} else if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+			balance = balance - transaction.Amount
+		} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+			balance = balance - transaction.Amount
+		} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			balance = balance + transaction.Amount
+		} else {
+			log.ErrorfWithRequestId(c, "[user_data_checks.CheckAccountBalance] transaction type of transaction \"id:%d\" is invalid", transaction.TransactionId)
+			return false, errs.ErrOperationFailed
+		}
+
+		accountBalance[transaction.AccountId] = balance
+	}
+
+	for _, account := range accountMap {
+		actualBalance, exists := accountBalance[account.AccountId]
+
+		if !exists && account.Balance == 0 {
+			continue
+		}
+
+		if !exists && account.Balance != 0 {
+			log.ErrorfWithRequestId(c, "[user_data_checks.CheckAccountBalance] account \"id:%d\" balance is not correct, expected balance is %d, but there is no transaction actually", account.AccountId, account.Balance)
+			return false, errs.ErrOperationFailed
+		}
+
+		if account.Balance != actualBalance {
+			log.ErrorfWithRequestId(c, "[user_data_checks.CheckAccountBalance] account \"id:%d\" balance is not correct, expected balance is %d, but actual balance is %d", account.AccountId, account.Balance, actualBalance)
+			return false, errs.ErrOperationFailed
+		}
+	}
+
+	for accountId, actualBalance := range accountBalance {
+		_, exists := accountMap[accountId]
+
+		if !exists {
+			log.ErrorfWithRequestId(c, "[user_data_checks.CheckAccountBalance] account \"id:%d\" does not exist, but there are some transactions of this account actually, and actual balance is %d", accountId, actualBalance)
+			return false, errs.ErrOperationFailed
+		}
+	}
+
+	return true, nil
+}
+
+func (s *UserDataCheckService) getUserEssentialData(c *core.Context, uid int64) (accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, tagIndexs map[int64][]int64, err error) {
+	accounts, err := s.accounts.GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	categories, err := s.categories.GetAllCategoriesByUid(c, uid, 0, -1)
+
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	tags, err := s.tags.GetAllTagsByUid(c, uid)
+
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	tagIndexs, err = s.tags.GetAllTagIdsOfAllTransactions(c, uid)
+
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	return s.accounts.GetAccountMapByList(accounts), s.categories.GetCategoryMapByList(categories), s.tags.GetTagMapByList(tags), tagIndexs, nil
+}
+
+func (s *UserDataCheckService) checkTransactionAccount(c *core.Context, transaction *models.Transaction, accountMap map[int64]*models.Account, accountHasChild map[int64]bool) error {
+	account, exists := accountMap[transaction.AccountId]
+
+	if !exists {
+		log.ErrorfWithRequestId(c, "[user_data_checks.checkTransactionAccount] the account \"id:%d\" of transaction \"id:%d\" does not exist", transaction.AccountId, transaction.TransactionId)
+		return errs.ErrAccountNotFound
+	}
+
+	if account.ParentAccountId == models.LevelOneAccountParentId && accountHasChild[account.AccountId] {
+		log.ErrorfWithRequestId(c, "[user_data_checks.checkTransactionAccount] the account \"id:%d\" of transaction \"id:%d\" is not a sub-account", transaction.AccountId, transaction.TransactionId)
+		return errs.ErrOperationFailed
+	}
+
+	if transaction.RelatedAccountId > 0 {
+		relatedAccount, exists := accountMap[transaction.RelatedAccountId]
+
+		if !exists {
+			log.ErrorfWithRequestId(c, "[user_data_checks.checkTransactionAccount] the related account \"id:%d\" of transaction \"id:%d\" does not exist", transaction.RelatedAccountId, transaction.TransactionId)
+			return errs.ErrAccountNotFound
+		}
+
+		if relatedAccount.ParentAccountId == models.LevelOneAccountParentId && accountHasChild[relatedAccount.AccountId] {
+			log.ErrorfWithRequestId(c, "[user_data_checks.checkTransactionAccount] the related account \"id:%d\" of transaction \"id:%d\" is not a sub-account", transaction.RelatedAccountId, transaction.TransactionId)
+			return errs.ErrOperationFailed
+		}
+	}
+
+	return nil
+}
+
+func (s *UserDataCheckService) checkTransactionCategory(c *core.Context, transaction *models.Transaction, categoryMap map[int64]*models.TransactionCategory) error {
+	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+		if transaction.CategoryId > 0 {
+			log.ErrorfWithRequestId(c, "[user_data_checks.checkTransactionCategory] transaction \"id:%d\" is balance modification transaction, but has category \"id:%d\"", transaction.TransactionId, transaction.CategoryId)
+			return errs.ErrBalanceModificationTransactionCannotSetCategory
+		} else {
+			return nil
+		}
+	}
+
+	category, exists := categoryMap[transaction.CategoryId]
+
+	if !exists {
+		log.ErrorfWithRequestId(c, "[user_data_checks.checkTransactionCategory] the transaction category \"id:%d\" of transaction \"id:%d\" does not exist", transaction.CategoryId, transaction.TransactionId)
+		return errs.ErrTransactionCategoryNotFound
+	}
+
+	if category.ParentCategoryId == models.LevelOneTransactionParentId {
+		log.ErrorfWithRequestId(c, "[user_data_checks.checkTransactionCategory] the transaction category \"id:%d\" of transaction \"id:%d\" is not a sub category", transaction.CategoryId, transaction.TransactionId)
+		return errs.ErrOperationFailed
+	}
+
+	return nil
+}
+
+func (s *UserDataCheckService) checkTransactionTag(c *core.Context, transactionId int64, allTagIndexs map[int64][]int64, tagMap map[int64]*models.TransactionTag) error {
+	tagIndexs, exists := allTagIndexs[transactionId]
+
+	if !exists {
+		return nil
+	}
+
+	for i := 0; i < len(tagIndexs); i++ {
+		tagIndex := tagIndexs[i]
+		tag, exists := tagMap[tagIndex]
+
+		if !exists {
+			log.ErrorfWithRequestId(c, "[user_data_checks.checkTransactionTag] the transaction tag \"id:%d\" of transaction \"id:%d\" does not exist", tag.TagId, transactionId)
+			return errs.ErrTransactionTagNotFound
+		}
+	}
+
+	return nil
+}
+
+func (s *UserDataCheckService) checkTransactionRelatedTransaction(c *core.Context, transaction *models.Transaction, transactionMap map[int64]*models.Transaction, accountMap map[int64]*models.Account) error {
+	if transaction.Type != models.TRANSACTION_DB_TYPE_TRANSFER_OUT && transaction.Type != models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		return nil
+	}
+
+	relatedTransaction, exists := transactionMap[transaction.RelatedId]
+
+	if !exists {
+		log.ErrorfWithRequestId(c, "[user_data_checks.checkTransactionRelatedTransaction] the related transaction \"id:%d\" of transaction \"id:%d\" does not exist", transaction.RelatedId, transaction.TransactionId)
+		return errs.ErrTransactionNotFound
+	}
+
+	if transaction.RelatedId != relatedTransaction.TransactionId || transaction.TransactionId != relatedTransaction.RelatedId {
+		log.ErrorfWithRequestId(c, "[user_data_checks.checkTransactionRelatedTransaction] related ids of transaction \"id:%d\" and transaction \"id:%d\" are not equal", transaction.RelatedId, transaction.TransactionId)
+		return errs.ErrOperationFailed
+	}
+
+	if transaction.RelatedAccountId != relatedTransaction.AccountId || transaction.AccountId != relatedTransaction.RelatedAccountId {
+		log.ErrorfWithRequestId(c, "[user_data_checks.checkTransactionRelatedTransaction] related account ids of transaction \"id:%d\" and transaction \"id:%d\" are not equal", transaction.RelatedId, transaction.TransactionId)
+		return errs.ErrOperationFailed
+	}
+
+	if transaction.RelatedAccountAmount != relatedTransaction.Amount || transaction.Amount != relatedTransaction.RelatedAccountAmount {
+		log.ErrorfWithRequestId(c, "[user_data_checks.checkTransactionRelatedTransaction] related amounts of transaction \"id:%d\" and transaction \"id:%d\" are not equal", transaction.RelatedId, transaction.TransactionId)
+		return errs.ErrOperationFailed
+	}
+
+	account := accountMap[transaction.AccountId]
+	relatedAccount := accountMap[transaction.RelatedAccountId]
+
+	if account.Currency == relatedAccount.Currency && transaction.Amount != transaction.RelatedAccountAmount {
+		log.WarnfWithRequestId(c, "[user_data_checks.checkTransactionRelatedTransaction] transfer-in amount and transfer-out amount of transaction \"id:%d\" are not equal", transaction.TransactionId)
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/datastore"
+	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/log"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+)
+
+const userDeletionBackgroundCheckInterval = time.Hour
+
+// UserDeletionService represents user deletion service
+type UserDeletionService struct {
+	ServiceUsingDB
+	userAuditEvents *UserAuditEventService
+}
+
+// Initialize a user deletion service singleton instance
+var (
+	UserDeletions = &UserDeletionService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		userAuditEvents: UserAuditEvents,
+	}
+)
+
+// GetUsersToPurge returns all users whose scheduled deletion time has been reached
+func (s *UserDeletionService) GetUsersToPurge(c *core.Context, now int64) ([]*models.User, error) {
+	var users []*models.User
+	err := s.UserDB().NewSession(c).Cols("uid", "username").Where("deletion_scheduled_unix_time>? AND deletion_scheduled_unix_time<=?", 0, now).Find(&users)
+
+	return users, err
+}
+
+// PurgeUser permanently deletes the user and all data of the user from the user data store, the token store and the user store,
+// except the audit events which are kept with a final account purged event
+func (s *UserDeletionService) PurgeUser(c *core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		userDataModels := []any{
+			&models.TransactionTagIndex{},
+			&models.Transaction{},
+			&models.TransactionTag{},
+			&models.TransactionCategory{},
+			&models.Account{},
+			&models.Commodity{},
+			&models.UserExchangeRate{},
+		}
+
+		for i := 0; i < len(userDataModels); i++ {
+			if _, err := sess.Where("uid=?", uid).Delete(userDataModels[i]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	err = s.TokenDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Where("uid=?", uid).Delete(&models.TokenRecord{})
+		return err
+	})
+
+	if err != nil {
+		return err
+	}
+
+	// The audit events are kept after the user has been purged, so that the security related history can still be traced
+	err = s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		userModels := []any{
+			&models.TwoFactor{},
+			&models.TwoFactorRecoveryCode{},
+			&models.UserExternalAuth{},
+			&models.UserWebAuthnCredential{},
+		}
+
+		for i := 0; i < len(userModels); i++ {
+			if _, err := sess.Where("uid=?", uid).Delete(userModels[i]); err != nil {
+				return err
+			}
+		}
+
+		if _, err := sess.Where("attempt_key=?", models.GetLoginAttemptKeyByUid(uid)).Delete(&models.LoginAttempt{}); err != nil {
+			return err
+		}
+
+		// The user would be deleted at last, so purging can be retried if any previous step failed
+		deletedRows, err := sess.ID(uid).Delete(&models.User{})
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrUserNotFound
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	err = s.userAuditEvents.CreateEvent(c, uid, models.USER_AUDIT_EVENT_TYPE_ACCOUNT_PURGED, "")
+
+	if err != nil {
+		log.Warnf("[user_deletions.PurgeUser] failed to record account purged event of user \"uid:%d\", because %s", uid, err.Error())
+	}
+
+	return nil
+}
+
+// PurgeScheduledUsers permanently deletes all users whose scheduled deletion time has been reached
+func (s *UserDeletionService) PurgeScheduledUsers(c *core.Context) (int, error) {
+	users, err := s.GetUsersToPurge(c, time.Now().Unix())
+
+	if err != nil {
+		return 0, err
+	}
+
+	purgedCount := 0
+
+	for i := 0; i < len(users); i++ {
+		err = s.PurgeUser(c, users[i].Uid)
+
+		if err != nil {
+			log.Errorf("[user_deletions.PurgeScheduledUsers] failed to purge user \"uid:%d\", because %s", users[i].Uid, err.Error())
+			continue
+		}
+
+		log.Infof("[user_deletions.PurgeScheduledUsers] user \"%s\" (uid:%d) has been purged", users[i].Username, users[i].Uid)
+		purgedCount++
+	}
+
+	return purgedCount, nil
+}
+
+// StartBackgroundPurge starts a goroutine which purges the users whose scheduled deletion time has been reached periodically
+func (s *UserDeletionService) StartBackgroundPurge() {
+	go func() {
+		ticker := time.NewTicker(userDeletionBackgroundCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			_, err := s.PurgeScheduledUsers(nil)
+
+			if err != nil {
+				log.Warnf("[user_deletions.StartBackgroundPurge] failed to purge scheduled users, because %s", err.Error())
+			}
+
+			<-ticker.C
+		}
+	}()
+}
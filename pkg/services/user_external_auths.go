@@ -0,0 +1,103 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/datastore"
+	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+)
+
+// UserExternalAuthService represents user external authentication service
+type UserExternalAuthService struct {
+	ServiceUsingDB
+}
+
+// Initialize a user external authentication service singleton instance
+var (
+	UserExternalAuths = &UserExternalAuthService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+	}
+)
+
+// GetUserExternalAuthByExternalUserId returns the link between user and the specified external user
+func (s *UserExternalAuthService) GetUserExternalAuthByExternalUserId(c *core.Context, externalAuthType models.UserExternalAuthType, externalUserId string) (*models.UserExternalAuth, error) {
+	if externalUserId == "" {
+		return nil, errs.ErrExternalUserIdIsEmpty
+	}
+
+	userExternalAuth := &models.UserExternalAuth{}
+	has, err := s.UserDB().NewSession(c).Where("external_auth_type=? AND external_user_id=?", externalAuthType, externalUserId).Get(userExternalAuth)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrExternalUserNotLinked
+	}
+
+	return userExternalAuth, nil
+}
+
+// GetUserExternalAuthByUid returns the link between the specified user and external user of the specified type
+func (s *UserExternalAuthService) GetUserExternalAuthByUid(c *core.Context, uid int64, externalAuthType models.UserExternalAuthType) (*models.UserExternalAuth, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	userExternalAuth := &models.UserExternalAuth{}
+	has, err := s.UserDB().NewSession(c).Where("uid=? AND external_auth_type=?", uid, externalAuthType).Get(userExternalAuth)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrExternalUserNotLinked
+	}
+
+	return userExternalAuth, nil
+}
+
+// CreateUserExternalAuth saves a new link between user and external user to database
+func (s *UserExternalAuthService) CreateUserExternalAuth(c *core.Context, userExternalAuth *models.UserExternalAuth) error {
+	if userExternalAuth.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if userExternalAuth.ExternalUserId == "" {
+		return errs.ErrExternalUserIdIsEmpty
+	}
+
+	userExternalAuth.CreatedUnixTime = time.Now().Unix()
+	userExternalAuth.LastLoginUnixTime = time.Now().Unix()
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := sess.Where("(uid=? AND external_auth_type=?) OR (external_auth_type=? AND external_user_id=?)", userExternalAuth.Uid, userExternalAuth.ExternalAuthType, userExternalAuth.ExternalAuthType, userExternalAuth.ExternalUserId).Exist(&models.UserExternalAuth{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrExternalAuthAlreadyLinked
+		}
+
+		_, err = sess.Insert(userExternalAuth)
+		return err
+	})
+}
+
+// UpdateUserExternalAuthLastLogin updates the external username, email and last login time of an existed link
+func (s *UserExternalAuthService) UpdateUserExternalAuthLastLogin(c *core.Context, userExternalAuth *models.UserExternalAuth) error {
+	if userExternalAuth.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	userExternalAuth.LastLoginUnixTime = time.Now().Unix()
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Cols("external_username", "external_email", "last_login_unix_time").Where("uid=? AND external_auth_type=?", userExternalAuth.Uid, userExternalAuth.ExternalAuthType).Update(userExternalAuth)
+		return err
+	})
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/core"
+	"github.com/kyy-me/ezbookkeeping/pkg/datastore"
+	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+	"github.com/kyy-me/ezbookkeeping/pkg/models"
+)
+
+// UserWebAuthnCredentialService represents user webauthn credential service
+type UserWebAuthnCredentialService struct {
+	ServiceUsingDB
+}
+
+// Initialize a user webauthn credential service singleton instance
+var (
+	UserWebAuthnCredentials = &UserWebAuthnCredentialService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+	}
+)
+
+// GetAllCredentialsByUid returns all webauthn credentials of user
+func (s *UserWebAuthnCredentialService) GetAllCredentialsByUid(c *core.Context, uid int64) ([]*models.UserWebAuthnCredential, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var credentials []*models.UserWebAuthnCredential
+	err := s.UserDB().NewSession(c).Where("uid=?", uid).OrderBy("created_unix_time asc").Find(&credentials)
+
+	return credentials, err
+}
+
+// GetCredentialById returns the webauthn credential of the specified credential id
+func (s *UserWebAuthnCredentialService) GetCredentialById(c *core.Context, credentialId string) (*models.UserWebAuthnCredential, error) {
+	if credentialId == "" {
+		return nil, errs.ErrWebAuthnCredentialIdInvalid
+	}
+
+	credential := &models.UserWebAuthnCredential{}
+	has, err := s.UserDB().NewSession(c).Where("credential_id=?", credentialId).Get(credential)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrWebAuthnCredentialNotFound
+	}
+
+	return credential, nil
+}
+
+// ExistsCredential returns whether the given user has any webauthn credential
+func (s *UserWebAuthnCredentialService) ExistsCredential(c *core.Context, uid int64) (bool, error) {
+	if uid <= 0 {
+		return false, errs.ErrUserIdInvalid
+	}
+
+	return s.UserDB().NewSession(c).Cols("credential_id").Where("uid=?", uid).Exist(&models.UserWebAuthnCredential{})
+}
+
+// CreateCredential saves a new webauthn credential to database
+func (s *UserWebAuthnCredentialService) CreateCredential(c *core.Context, credential *models.UserWebAuthnCredential) error {
+	if credential.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if credential.CredentialId == "" {
+		return errs.ErrWebAuthnCredentialIdInvalid
+	}
+
+	credential.CreatedUnixTime = time.Now().Unix()
+	credential.LastUsedUnixTime = 0
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := sess.Cols("credential_id").Where("credential_id=?", credential.CredentialId).Exist(&models.UserWebAuthnCredential{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrWebAuthnCredentialAlreadyExists
+		}
+
+		count, err := sess.Where("uid=?", credential.Uid).Count(&models.UserWebAuthnCredential{})
+
+		if err != nil {
+			return err
+		} else if count >= models.UserWebAuthnCredentialMaxCount {
+			return errs.ErrWebAuthnCredentialCountExceeded
+		}
+
+		_, err = sess.Insert(credential)
+		return err
+	})
+}
+
+// UpdateCredentialLastUsed updates the signature counter and the last used time of an existed webauthn credential
+func (s *UserWebAuthnCredentialService) UpdateCredentialLastUsed(c *core.Context, credential *models.UserWebAuthnCredential) error {
+	if credential.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	credential.LastUsedUnixTime = time.Now().Unix()
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Cols("sign_count", "last_used_unix_time").Where("uid=? AND credential_id=?", credential.Uid, credential.CredentialId).Update(credential)
+		return err
+	})
+}
+
+// DeleteCredential deletes an existed webauthn credential of user from database
+func (s *UserWebAuthnCredentialService) DeleteCredential(c *core.Context, uid int64, credentialId string) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.Where("uid=? AND credential_id=?", uid, credentialId).Delete(&models.UserWebAuthnCredential{})
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrWebAuthnCredentialNotFound
+		}
+
+		return nil
+	})
+}
+
+// DeleteAllCredentials deletes all webauthn credentials of user from database
+func (s *UserWebAuthnCredentialService) DeleteAllCredentials(c *core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Where("uid=?", uid).Delete(&models.UserWebAuthnCredential{})
+		return err
+	})
+}
+
+// CreateChallenge saves the challenge of a new webauthn ceremony which can be used until the expired time, and deletes all expired challenges
+func (s *UserWebAuthnCredentialService) CreateChallenge(c *core.Context, challenge string, expiredUnixTime int64) error {
+	if challenge == "" {
+		return errs.ErrWebAuthnSessionInvalid
+	}
+
+	now := time.Now().Unix()
+	webAuthnChallenge := &models.WebAuthnChallenge{
+		Challenge:       challenge,
+		ExpiredUnixTime: expiredUnixTime,
+		CreatedUnixTime: now,
+	}
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Where("expired_unix_time<=?", now).Delete(&models.WebAuthnChallenge{})
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Insert(webAuthnChallenge)
+		return err
+	})
+}
+
+// ConsumeChallenge deletes the challenge of a finishing webauthn ceremony, returns error if the challenge has been used or expired
+func (s *UserWebAuthnCredentialService) ConsumeChallenge(c *core.Context, challenge string) error {
+	if challenge == "" {
+		return errs.ErrWebAuthnSessionInvalid
+	}
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		// the challenge is deleted by one statement, so only one of the concurrent requests with the same session can succeed
+		deletedRows, err := sess.Where("challenge=? AND expired_unix_time>?", challenge, time.Now().Unix()).Delete(&models.WebAuthnChallenge{})
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrWebAuthnSessionInvalid
+		}
+
+		return nil
+	})
+}
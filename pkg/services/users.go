@@ -21,6 +21,7 @@ import (
 )
 
 const verifyEmailUrlFormat = "%sdesktop/#/verify_email?token=%s"
+const confirmEmailChangeUrlFormat = "%sdesktop/#/confirm_email_change?token=%s"
 
 // UserService represents user service
 type UserService struct {
@@ -48,28 +49,10 @@ var (
 	}
 )
 
-// GetUserByUsernameOrEmailAndPassword returns the user model according to login name and password
+// GetUserByUsernameOrEmailAndPassword returns the user model according to login name and password,
+// which are verified by all enabled user authenticators in order
 func (s *UserService) GetUserByUsernameOrEmailAndPassword(c *core.Context, loginname string, password string) (*models.User, error) {
-	var user *models.User
-	var err error
-
-	if utils.IsValidUsername(loginname) {
-		user, err = s.GetUserByUsername(c, loginname)
-	} else if utils.IsValidEmail(loginname) {
-		user, err = s.GetUserByEmail(c, loginname)
-	} else {
-		err = errs.ErrLoginNameInvalid
-	}
-
-	if err != nil {
-		return nil, err
-	}
-
-	if !s.IsPasswordEqualsUserPassword(password, user) {
-		return nil, errs.ErrUserPasswordWrong
-	}
-
-	return user, nil
+	return s.getUserAuthenticatorChain().Authenticate(c, loginname, password)
 }
 
 // GetUserById returns the user model according to user uid
@@ -90,6 +73,28 @@ func (s *UserService) GetUserById(c *core.Context, uid int64) (*models.User, err
 	return user, nil
 }
 
+// GetUsers returns the users whose user name, email or nickname contains the keyword, and whose uid is not greater
+// than the specified uid in descending order
+func (s *UserService) GetUsers(c *core.Context, keyword string, maxUid int64, count int32) ([]*models.User, error) {
+	condition := "deleted=?"
+	conditionParams := []any{false}
+
+	if maxUid > 0 {
+		condition = condition + " AND uid<=?"
+		conditionParams = append(conditionParams, maxUid)
+	}
+
+	if keyword != "" {
+		condition = condition + " AND (username LIKE ? OR email LIKE ? OR nickname LIKE ?)"
+		conditionParams = append(conditionParams, "%"+keyword+"%", "%"+keyword+"%", "%"+keyword+"%")
+	}
+
+	var users []*models.User
+	err := s.UserDB().NewSession(c).Where(condition, conditionParams...).Limit(int(count), 0).OrderBy("uid desc").Find(&users)
+
+	return users, err
+}
+
 // GetUserByUsername returns the user model according to user name
 func (s *UserService) GetUserByUsername(c *core.Context, username string) (*models.User, error) {
 	if username == "" {
@@ -126,6 +131,17 @@ func (s *UserService) GetUserByEmail(c *core.Context, email string) (*models.Use
 	return user, nil
 }
 
+// GetUserByUsernameOrEmail returns the user model according to login name which is user name or email
+func (s *UserService) GetUserByUsernameOrEmail(c *core.Context, loginName string) (*models.User, error) {
+	if utils.IsValidUsername(loginName) {
+		return s.GetUserByUsername(c, loginName)
+	} else if utils.IsValidEmail(loginName) {
+		return s.GetUserByEmail(c, loginName)
+	}
+
+	return nil, errs.ErrLoginNameInvalid
+}
+
 // CreateUser saves a new user model to database
 func (s *UserService) CreateUser(c *core.Context, user *models.User) error {
 	exists, err := s.ExistsUsername(c, user.Username)
@@ -158,7 +174,9 @@ func (s *UserService) CreateUser(c *core.Context, user *models.User) error {
 		return errs.ErrSystemIsBusy
 	}
 
-	user.Password = utils.EncodePassword(user.Password, user.Salt)
+	if user.Password, err = s.encodePassword(user.Password); err != nil {
+		return err
+	}
 
 	user.Deleted = false
 
@@ -202,7 +220,9 @@ func (s *UserService) UpdateUser(c *core.Context, user *models.User, modifyUserL
 	}
 
 	if user.Password != "" {
-		user.Password = utils.EncodePassword(user.Password, user.Salt)
+		if user.Password, err = s.encodePassword(user.Password); err != nil {
+			return false, false, err
+		}
 
 		keyProfileUpdated = true
 		updateCols = append(updateCols, "password")
@@ -232,6 +252,14 @@ func (s *UserService) UpdateUser(c *core.Context, user *models.User, modifyUserL
 		updateCols = append(updateCols, "first_day_of_week")
 	}
 
+	if 1 <= user.FiscalYearStartMonth && user.FiscalYearStartMonth <= 12 {
+		updateCols = append(updateCols, "fiscal_year_start_month")
+	}
+
+	if 1 <= user.MonthStartDay && user.MonthStartDay <= 28 {
+		updateCols = append(updateCols, "month_start_day")
+	}
+
 	if models.LONG_DATE_FORMAT_DEFAULT <= user.LongDateFormat && user.LongDateFormat <= models.LONG_DATE_FORMAT_D_M_YYYY {
 		updateCols = append(updateCols, "long_date_format")
 	}
@@ -344,6 +372,29 @@ func (s *UserService) DisableUser(c *core.Context, username string) error {
 	return nil
 }
 
+// SetUserAdmin grants or revokes the administrator role of user
+func (s *UserService) SetUserAdmin(c *core.Context, username string, isAdmin bool) error {
+	if username == "" {
+		return errs.ErrUsernameIsEmpty
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.User{
+		IsAdmin:         isAdmin,
+		UpdatedUnixTime: now,
+	}
+
+	updatedRows, err := s.UserDB().NewSession(c).Cols("is_admin", "updated_unix_time").Where("username=? AND deleted=?", username, false).Update(updateModel)
+
+	if err != nil {
+		return err
+	} else if updatedRows < 1 {
+		return errs.ErrUserNotFound
+	}
+	return nil
+}
+
 // SetUserEmailVerified sets user email address verified
 func (s *UserService) SetUserEmailVerified(c *core.Context, username string) error {
 	if username == "" {
@@ -413,6 +464,145 @@ func (s *UserService) DeleteUser(c *core.Context, username string) error {
 	return nil
 }
 
+// SetUserPendingEmail saves the new email address which user requests to change to, the email address of user
+// would not be changed until the new email address is confirmed
+func (s *UserService) SetUserPendingEmail(c *core.Context, uid int64, email string) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	exists, err := s.ExistsEmail(c, email)
+
+	if err != nil {
+		return err
+	} else if exists {
+		return errs.ErrUserEmailAlreadyExists
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.User{
+		PendingEmail:    email,
+		UpdatedUnixTime: now,
+	}
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.ID(uid).Cols("pending_email", "updated_unix_time").Where("deleted=?", false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrUserNotFound
+		}
+
+		return nil
+	})
+}
+
+// ConfirmUserPendingEmail replaces the email address of user with the confirmed pending email address, and sets it verified
+func (s *UserService) ConfirmUserPendingEmail(c *core.Context, uid int64) (oldEmail string, newEmail string, err error) {
+	if uid <= 0 {
+		return "", "", errs.ErrUserIdInvalid
+	}
+
+	err = s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		user := &models.User{}
+		has, err := sess.ID(uid).Where("deleted=?", false).Get(user)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrUserNotFound
+		}
+
+		if user.PendingEmail == "" {
+			return errs.ErrUserEmailChangeNotRequested
+		}
+
+		exists, err := sess.Cols("email").Where("email=? AND deleted=?", user.PendingEmail, false).Exist(&models.User{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrUserEmailAlreadyExists
+		}
+
+		oldEmail = user.Email
+		newEmail = user.PendingEmail
+
+		updateModel := &models.User{
+			Email:           newEmail,
+			EmailVerified:   true,
+			PendingEmail:    "",
+			UpdatedUnixTime: time.Now().Unix(),
+		}
+
+		updatedRows, err := sess.ID(uid).Cols("email", "email_verified", "pending_email", "updated_unix_time").Where("pending_email=? AND deleted=?", newEmail, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrUserEmailChangeNotRequested
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return "", "", err
+	}
+
+	return oldEmail, newEmail, nil
+}
+
+// ScheduleUserDeletion sets the time when all data of user would be purged
+func (s *UserService) ScheduleUserDeletion(c *core.Context, uid int64, deletionUnixTime int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	updateModel := &models.User{
+		DeletionScheduledUnixTime: deletionUnixTime,
+		UpdatedUnixTime:           time.Now().Unix(),
+	}
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.ID(uid).Cols("deletion_scheduled_unix_time", "updated_unix_time").Where("deletion_scheduled_unix_time=? AND deleted=?", 0, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrAccountDeletionAlreadyScheduled
+		}
+
+		return nil
+	})
+}
+
+// CancelUserDeletion cancels the scheduled deletion of user
+func (s *UserService) CancelUserDeletion(c *core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	updateModel := &models.User{
+		DeletionScheduledUnixTime: 0,
+		UpdatedUnixTime:           time.Now().Unix(),
+	}
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.ID(uid).Cols("deletion_scheduled_unix_time", "updated_unix_time").Where("deletion_scheduled_unix_time>? AND deleted=?", 0, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrAccountDeletionNotScheduled
+		}
+
+		return nil
+	})
+}
+
 // ExistsUsername returns whether the given user name exists
 func (s *UserService) ExistsUsername(c *core.Context, username string) (bool, error) {
 	if username == "" {
@@ -485,7 +675,184 @@ func (s *UserService) SendVerifyEmail(user *models.User, verifyEmailToken string
 	return err
 }
 
+// SendEmailChangeConfirmEmail sends the email change confirmation email to the new email address
+func (s *UserService) SendEmailChangeConfirmEmail(user *models.User, newEmail string, emailChangeToken string, backupLocale string) error {
+	if !s.CurrentConfig().EnableSMTP {
+		return errs.ErrSMTPServerNotEnabled
+	}
+
+	locale := user.Language
+
+	if locale == "" {
+		locale = backupLocale
+	}
+
+	localeTextItems := locales.GetLocaleTextItems(locale)
+	emailChangeConfirmTextItems := localeTextItems.EmailChangeConfirmMailTextItems
+
+	expireTimeInMinutes := s.CurrentConfig().EmailVerifyTokenExpiredTimeDuration.Minutes()
+	confirmEmailChangeUrl := fmt.Sprintf(confirmEmailChangeUrlFormat, s.CurrentConfig().RootUrl, url.QueryEscape(emailChangeToken))
+
+	tmpl, err := templates.GetTemplate(templates.TEMPLATE_EMAIL_CHANGE_CONFIRM)
+
+	if err != nil {
+		return err
+	}
+
+	templateParams := map[string]any{
+		"AppName": s.CurrentConfig().AppName,
+		"EmailChangeConfirmMail": map[string]any{
+			"Title":                 emailChangeConfirmTextItems.Title,
+			"Salutation":            fmt.Sprintf(emailChangeConfirmTextItems.SalutationFormat, user.Nickname),
+			"DescriptionAboveBtn":   fmt.Sprintf(emailChangeConfirmTextItems.DescriptionAboveBtnFormat, s.CurrentConfig().AppName),
+			"ConfirmEmailChangeUrl": confirmEmailChangeUrl,
+			"ConfirmEmailChange":    emailChangeConfirmTextItems.ConfirmEmailChange,
+			"DescriptionBelowBtn":   fmt.Sprintf(emailChangeConfirmTextItems.DescriptionBelowBtnFormat, expireTimeInMinutes),
+		},
+	}
+
+	var bodyBuffer bytes.Buffer
+	err = tmpl.Execute(&bodyBuffer, templateParams)
+
+	if err != nil {
+		return err
+	}
+
+	message := &mail.MailMessage{
+		To:      newEmail,
+		Subject: emailChangeConfirmTextItems.Title,
+		Body:    bodyBuffer.String(),
+	}
+
+	err = s.SendMail(message)
+
+	return err
+}
+
+// SendEmailChangeNotifyEmail sends an email to the current email address to notify user that email change has been requested
+func (s *UserService) SendEmailChangeNotifyEmail(user *models.User, newEmail string, backupLocale string) error {
+	if !s.CurrentConfig().EnableSMTP {
+		return errs.ErrSMTPServerNotEnabled
+	}
+
+	locale := user.Language
+
+	if locale == "" {
+		locale = backupLocale
+	}
+
+	localeTextItems := locales.GetLocaleTextItems(locale)
+	emailChangeNotifyTextItems := localeTextItems.EmailChangeNotifyMailTextItems
+
+	tmpl, err := templates.GetTemplate(templates.TEMPLATE_EMAIL_CHANGE_NOTIFY)
+
+	if err != nil {
+		return err
+	}
+
+	templateParams := map[string]any{
+		"AppName": s.CurrentConfig().AppName,
+		"EmailChangeNotifyMail": map[string]any{
+			"Title":       emailChangeNotifyTextItems.Title,
+			"Salutation":  fmt.Sprintf(emailChangeNotifyTextItems.SalutationFormat, user.Nickname),
+			"Description": fmt.Sprintf(emailChangeNotifyTextItems.DescriptionFormat, newEmail),
+			"Suggestion":  emailChangeNotifyTextItems.Suggestion,
+		},
+	}
+
+	var bodyBuffer bytes.Buffer
+	err = tmpl.Execute(&bodyBuffer, templateParams)
+
+	if err != nil {
+		return err
+	}
+
+	message := &mail.MailMessage{
+		To:      user.Email,
+		Subject: emailChangeNotifyTextItems.Title,
+		Body:    bodyBuffer.String(),
+	}
+
+	err = s.SendMail(message)
+
+	return err
+}
+
 // IsPasswordEqualsUserPassword returns whether the given password is correct
 func (s *UserService) IsPasswordEqualsUserPassword(password string, user *models.User) bool {
-	return user.Password == utils.EncodePassword(password, user.Salt)
+	return utils.VerifyPasswordHash(password, user.Password, user.Salt)
+}
+
+// IsUserPasswordHashOutdated returns whether the password hash of user is not encoded by the current algorithm and parameters
+func (s *UserService) IsUserPasswordHashOutdated(user *models.User) bool {
+	return utils.IsPasswordHashOutdated(user.Password, s.getPasswordHashOptions())
+}
+
+// UpdateUserPasswordHash rehashes the verified password of user by the current algorithm and parameters and saves it to database
+func (s *UserService) UpdateUserPasswordHash(c *core.Context, user *models.User, password string) error {
+	if user.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	passwordHash, err := s.encodePassword(password)
+
+	if err != nil {
+		return err
+	}
+
+	updateModel := &models.User{
+		Password: passwordHash,
+	}
+
+	err = s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.ID(user.Uid).Cols("password").Where("password=? AND deleted=?", user.Password, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrUserNotFound
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	user.Password = passwordHash
+
+	return nil
+}
+
+func (s *UserService) encodePassword(password string) (string, error) {
+	return utils.EncodePasswordHash(password, s.getPasswordHashOptions())
+}
+
+func (s *UserService) getPasswordHashOptions() *utils.PasswordHashOptions {
+	config := s.CurrentConfig()
+	options := &utils.PasswordHashOptions{
+		Argon2Memory:      config.Argon2Memory,
+		Argon2Iterations:  config.Argon2Iterations,
+		Argon2Parallelism: config.Argon2Parallelism,
+		Pbkdf2Iterations:  config.Pbkdf2Iterations,
+	}
+
+	if config.PasswordHashAlgorithm == settings.Pbkdf2PasswordHashAlgorithm {
+		options.Algorithm = utils.PASSWORD_HASH_ALGORITHM_PBKDF2_SHA256
+	} else {
+		options.Algorithm = utils.PASSWORD_HASH_ALGORITHM_ARGON2ID
+	}
+
+	return options
+}
+
+func (s *UserService) getUserAuthenticatorChain() UserAuthenticatorChain {
+	chain := UserAuthenticatorChain{LocalUserAuthenticators}
+
+	if s.CurrentConfig().EnableLDAP {
+		chain = append(chain, LDAPUserAuthenticators)
+	}
+
+	return chain
 }
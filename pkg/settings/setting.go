@@ -2,6 +2,7 @@ package settings
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -97,6 +98,34 @@ const (
 	CzechNationalBankDataSource            string = "czech_national_bank"
 	NationalBankOfPolandDataSource         string = "national_bank_of_poland"
 	MonetaryAuthorityOfSingaporeDataSource string = "monetary_authority_of_singapore"
+	BankOfEnglandDataSource                string = "bank_of_england"
+	SwissNationalBankDataSource            string = "swiss_national_bank"
+	BankOfIsraelDataSource                 string = "bank_of_israel"
+	DanmarksNationalbankDataSource         string = "danmarks_nationalbank"
+	NorgesBankDataSource                   string = "norges_bank"
+	InternationalMonetaryFundDataSource    string = "international_monetary_fund"
+	CustomExchangeRatesDataSource          string = "custom"
+)
+
+// Custom exchange rates data source formats
+const (
+	CustomExchangeRatesDataSourceJsonFormat string = "json"
+	CustomExchangeRatesDataSourceXmlFormat  string = "xml"
+	CustomExchangeRatesDataSourceCsvFormat  string = "csv"
+)
+
+// Exchange rates conversion rounding modes
+const (
+	HalfUpRoundingMode   string = "half_up"
+	HalfEvenRoundingMode string = "half_even"
+	DownRoundingMode     string = "down"
+	UpRoundingMode       string = "up"
+)
+
+// Password hash algorithms
+const (
+	Argon2idPasswordHashAlgorithm string = "argon2id"
+	Pbkdf2PasswordHashAlgorithm   string = "pbkdf2"
 )
 
 const (
@@ -120,8 +149,38 @@ const (
 	defaultTemporaryTokenExpiredTime     uint32 = 300    // 5 minutes
 	defaultEmailVerifyTokenExpiredTime   uint32 = 3600   // 60 minutes
 	defaultPasswordResetTokenExpiredTime uint32 = 3600   // 60 minutes
-
-	defaultExchangeRatesDataRequestTimeout uint32 = 10000 // 10 seconds
+	defaultMaxFailedLoginAttempts        uint32 = 5
+	defaultMaxFailedLoginAttemptsPerIp   uint32 = 20
+	defaultLoginLockoutDuration          uint32 = 900   // 15 minutes
+	maxLoginLockoutDuration              uint32 = 86400 // 1 day
+	defaultPasswordHashAlgorithm         string = Argon2idPasswordHashAlgorithm
+	defaultArgon2Memory                  uint32 = 19456 // 19 MiB
+	maxArgon2Memory                      uint32 = 4194304
+	defaultArgon2Iterations              uint32 = 2
+	defaultArgon2Parallelism             uint8  = 1
+	defaultPbkdf2Iterations              uint32 = 600000
+
+	defaultAccountDeletionGracePeriod uint32 = 7   // 7 days
+	maxAccountDeletionGracePeriod     uint32 = 365 // 1 year
+
+	defaultExchangeRatesDataRequestTimeout       uint32 = 10000  // 10 seconds
+	defaultExchangeRatesCustomDataSourceInterval uint32 = 86400  // 1 day
+	minExchangeRatesCustomDataSourceInterval     uint32 = 1800   // 30 minutes
+	maxExchangeRatesCustomDataSourceInterval     uint32 = 604800 // 7 days
+
+	defaultOIDCProviderName   string = "OpenID Connect"
+	defaultOIDCScopes         string = "openid email profile"
+	defaultOIDCUsernameClaim  string = "preferred_username"
+	defaultOIDCRequestTimeout uint32 = 10000 // 10 seconds
+	defaultOIDCUserCurrency   string = "USD"
+
+	defaultLDAPUserFilter        string = "(uid={username})"
+	defaultLDAPUsernameAttribute string = "uid"
+	defaultLDAPEmailAttribute    string = "mail"
+	defaultLDAPNicknameAttribute string = "cn"
+	defaultLDAPGroupAttribute    string = "member"
+	defaultLDAPRequestTimeout    uint32 = 10000 // 10 seconds
+	defaultLDAPUserCurrency      string = "USD"
 )
 
 // DatabaseConfig represents the database setting config
@@ -208,14 +267,65 @@ type Config struct {
 	PasswordResetTokenExpiredTime         uint32
 	PasswordResetTokenExpiredTimeDuration time.Duration
 	EnableRequestIdHeader                 bool
+	AdminUsernames                        []string
+	EnableWebAuthn                        bool
+	EnableWebAuthnPasswordlessLogin       bool
+	WebAuthnRPId                          string
+	WebAuthnRPOrigins                     []string
+	MaxFailedLoginAttempts                uint32
+	MaxFailedLoginAttemptsPerIp           uint32
+	LoginLockoutDuration                  uint32
+	LoginLockoutDurationDuration          time.Duration
+	PasswordHashAlgorithm                 string
+	Argon2Memory                          uint32
+	Argon2Iterations                      uint32
+	Argon2Parallelism                     uint8
+	Pbkdf2Iterations                      uint32
 
 	// User
-	EnableUserRegister               bool
-	EnableUserVerifyEmail            bool
-	EnableUserForceVerifyEmail       bool
-	EnableUserForgetPassword         bool
-	ForgetPasswordRequireVerifyEmail bool
-	AvatarProvider                   string
+	EnableUserRegister                 bool
+	RegisterRequireInvitationCode      bool
+	EnableUserVerifyEmail              bool
+	EnableUserForceVerifyEmail         bool
+	EnableUserForgetPassword           bool
+	ForgetPasswordRequireVerifyEmail   bool
+	EnableUserAccountDeletion          bool
+	AccountDeletionGracePeriod         uint32
+	AccountDeletionGracePeriodDuration time.Duration
+	AvatarProvider                     string
+
+	// OpenID Connect
+	EnableOIDC                  bool
+	OIDCProviderName            string
+	OIDCIssuerUrl               string
+	OIDCClientId                string
+	OIDCClientSecret            string
+	OIDCRedirectUrl             string
+	OIDCScopes                  []string
+	OIDCUsernameClaim           string
+	OIDCAutoProvisionUser       bool
+	OIDCLinkExistingUserByEmail bool
+	OIDCDefaultUserCurrency     string
+	OIDCRequestTimeout          uint32
+	OIDCProxy                   string
+	OIDCSkipTLSVerify           bool
+
+	// LDAP
+	EnableLDAP               bool
+	LDAPServerUrl            string
+	LDAPStartTLS             bool
+	LDAPSkipTLSVerify        bool
+	LDAPBindDN               string
+	LDAPBindPassword         string
+	LDAPBaseDN               string
+	LDAPUserFilter           string
+	LDAPUsernameAttribute    string
+	LDAPEmailAttribute       string
+	LDAPNicknameAttribute    string
+	LDAPRequiredGroupDN      string
+	LDAPGroupMemberAttribute string
+	LDAPDefaultUserCurrency  string
+	LDAPRequestTimeout       uint32
 
 	// Data
 	EnableDataExport bool
@@ -238,9 +348,28 @@ type Config struct {
 
 	// Exchange Rates
 	ExchangeRatesDataSource     string
+	ExchangeRatesDataSources    []string
 	ExchangeRatesRequestTimeout uint32
 	ExchangeRatesProxy          string
 	ExchangeRatesSkipTLSVerify  bool
+	ExchangeRatesRoundingMode   string
+
+	ExchangeRatesCustomDataSourceName             string
+	ExchangeRatesCustomDataSourceUrl              string
+	ExchangeRatesCustomDataSourceReferenceUrl     string
+	ExchangeRatesCustomDataSourceFormat           string
+	ExchangeRatesCustomDataSourceBaseCurrency     string
+	ExchangeRatesCustomDataSourceBaseCurrencyPath string
+	ExchangeRatesCustomDataSourceRatesPath        string
+	ExchangeRatesCustomDataSourceCurrencyPath     string
+	ExchangeRatesCustomDataSourceRatePath         string
+	ExchangeRatesCustomDataSourceRateInverted     bool
+	ExchangeRatesCustomDataSourceUpdateTimePath   string
+	ExchangeRatesCustomDataSourceUpdateTimeFormat string
+	ExchangeRatesCustomDataSourceUpdateInterval   uint32
+
+	CommodityQuoteUrl       string
+	CommodityQuotePricePath string
 }
 
 // LoadConfiguration loads setting config from given config file path
@@ -308,6 +437,18 @@ func LoadConfiguration(configFilePath string) (*Config, error) {
 		return nil, err
 	}
 
+	err = loadOIDCConfiguration(config, cfgFile, "oidc")
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = loadLDAPConfiguration(config, cfgFile, "ldap")
+
+	if err != nil {
+		return nil, err
+	}
+
 	err = loadDataConfiguration(config, cfgFile, "data")
 
 	if err != nil {
@@ -504,15 +645,91 @@ func loadSecurityConfiguration(config *Config, configFile *ini.File, sectionName
 
 	config.EnableRequestIdHeader = getConfigItemBoolValue(configFile, sectionName, "request_id_header", true)
 
+	adminUsernames := strings.Split(getConfigItemStringValue(configFile, sectionName, "admin_usernames"), ",")
+	config.AdminUsernames = make([]string, 0, len(adminUsernames))
+
+	for i := 0; i < len(adminUsernames); i++ {
+		adminUsername := strings.TrimSpace(adminUsernames[i])
+
+		if adminUsername != "" {
+			config.AdminUsernames = append(config.AdminUsernames, adminUsername)
+		}
+	}
+
+	config.EnableWebAuthn = getConfigItemBoolValue(configFile, sectionName, "enable_webauthn", false)
+	config.EnableWebAuthnPasswordlessLogin = config.EnableWebAuthn && getConfigItemBoolValue(configFile, sectionName, "enable_webauthn_passwordless_login", false)
+
+	rootUrl, err := url.Parse(config.RootUrl)
+
+	if err != nil {
+		return errs.ErrInvalidWebAuthnConfig
+	}
+
+	config.WebAuthnRPId = getConfigItemStringValue(configFile, sectionName, "webauthn_rp_id", rootUrl.Hostname())
+
+	webAuthnRPOrigins := strings.Split(getConfigItemStringValue(configFile, sectionName, "webauthn_rp_origins", rootUrl.Scheme+"://"+rootUrl.Host), ",")
+	config.WebAuthnRPOrigins = make([]string, 0, len(webAuthnRPOrigins))
+
+	for i := 0; i < len(webAuthnRPOrigins); i++ {
+		webAuthnRPOrigin := strings.TrimRight(strings.TrimSpace(webAuthnRPOrigins[i]), "/")
+
+		if webAuthnRPOrigin != "" {
+			config.WebAuthnRPOrigins = append(config.WebAuthnRPOrigins, webAuthnRPOrigin)
+		}
+	}
+
+	if config.EnableWebAuthn && (config.WebAuthnRPId == "" || len(config.WebAuthnRPOrigins) < 1) {
+		return errs.ErrInvalidWebAuthnConfig
+	}
+
+	config.MaxFailedLoginAttempts = getConfigItemUint32Value(configFile, sectionName, "max_failed_login_attempts", defaultMaxFailedLoginAttempts)
+	config.MaxFailedLoginAttemptsPerIp = getConfigItemUint32Value(configFile, sectionName, "max_failed_login_attempts_per_ip", defaultMaxFailedLoginAttemptsPerIp)
+	config.LoginLockoutDuration = getConfigItemUint32Value(configFile, sectionName, "login_lockout_duration", defaultLoginLockoutDuration)
+
+	if config.LoginLockoutDuration < 1 || config.LoginLockoutDuration > maxLoginLockoutDuration {
+		config.LoginLockoutDuration = defaultLoginLockoutDuration
+	}
+
+	config.LoginLockoutDurationDuration = time.Duration(config.LoginLockoutDuration) * time.Second
+
+	passwordHashAlgorithm := getConfigItemStringValue(configFile, sectionName, "password_hash_algorithm", defaultPasswordHashAlgorithm)
+
+	if passwordHashAlgorithm == Argon2idPasswordHashAlgorithm || passwordHashAlgorithm == Pbkdf2PasswordHashAlgorithm {
+		config.PasswordHashAlgorithm = passwordHashAlgorithm
+	} else {
+		return errs.ErrInvalidPasswordHashConfig
+	}
+
+	config.Argon2Memory = getConfigItemUint32Value(configFile, sectionName, "argon2_memory", defaultArgon2Memory)
+	config.Argon2Iterations = getConfigItemUint32Value(configFile, sectionName, "argon2_iterations", defaultArgon2Iterations)
+	argon2Parallelism := getConfigItemUint32Value(configFile, sectionName, "argon2_parallelism", uint32(defaultArgon2Parallelism))
+	config.Pbkdf2Iterations = getConfigItemUint32Value(configFile, sectionName, "pbkdf2_iterations", defaultPbkdf2Iterations)
+
+	if argon2Parallelism < 1 || argon2Parallelism > 255 || config.Argon2Iterations < 1 ||
+		config.Argon2Memory < 8*argon2Parallelism || config.Argon2Memory > maxArgon2Memory || config.Pbkdf2Iterations < 1 {
+		return errs.ErrInvalidPasswordHashConfig
+	}
+
+	config.Argon2Parallelism = uint8(argon2Parallelism)
+
 	return nil
 }
 
 func loadUserConfiguration(config *Config, configFile *ini.File, sectionName string) error {
 	config.EnableUserRegister = getConfigItemBoolValue(configFile, sectionName, "enable_register", false)
+	config.RegisterRequireInvitationCode = config.EnableUserRegister && getConfigItemBoolValue(configFile, sectionName, "register_require_invitation_code", false)
 	config.EnableUserVerifyEmail = getConfigItemBoolValue(configFile, sectionName, "enable_email_verify", false)
 	config.EnableUserForceVerifyEmail = getConfigItemBoolValue(configFile, sectionName, "enable_force_email_verify", false)
 	config.EnableUserForgetPassword = getConfigItemBoolValue(configFile, sectionName, "enable_forget_password", false)
 	config.ForgetPasswordRequireVerifyEmail = getConfigItemBoolValue(configFile, sectionName, "forget_password_require_email_verify", false)
+	config.EnableUserAccountDeletion = getConfigItemBoolValue(configFile, sectionName, "enable_account_deletion", false)
+	config.AccountDeletionGracePeriod = getConfigItemUint32Value(configFile, sectionName, "account_deletion_grace_period", defaultAccountDeletionGracePeriod)
+
+	if config.AccountDeletionGracePeriod < 1 || config.AccountDeletionGracePeriod > maxAccountDeletionGracePeriod {
+		config.AccountDeletionGracePeriod = defaultAccountDeletionGracePeriod
+	}
+
+	config.AccountDeletionGracePeriodDuration = time.Duration(config.AccountDeletionGracePeriod) * 24 * time.Hour
 
 	if getConfigItemStringValue(configFile, sectionName, "avatar_provider") == "" {
 		config.AvatarProvider = ""
@@ -523,6 +740,53 @@ func loadUserConfiguration(config *Config, configFile *ini.File, sectionName str
 	return nil
 }
 
+func loadOIDCConfiguration(config *Config, configFile *ini.File, sectionName string) error {
+	config.EnableOIDC = getConfigItemBoolValue(configFile, sectionName, "enable_oidc", false)
+	config.OIDCProviderName = getConfigItemStringValue(configFile, sectionName, "provider_name", defaultOIDCProviderName)
+	config.OIDCIssuerUrl = strings.TrimRight(getConfigItemStringValue(configFile, sectionName, "issuer_url"), "/")
+	config.OIDCClientId = getConfigItemStringValue(configFile, sectionName, "client_id")
+	config.OIDCClientSecret = getConfigItemStringValue(configFile, sectionName, "client_secret")
+	config.OIDCRedirectUrl = getConfigItemStringValue(configFile, sectionName, "redirect_url", config.RootUrl)
+	config.OIDCScopes = strings.Fields(getConfigItemStringValue(configFile, sectionName, "scopes", defaultOIDCScopes))
+	config.OIDCUsernameClaim = getConfigItemStringValue(configFile, sectionName, "username_claim", defaultOIDCUsernameClaim)
+	config.OIDCAutoProvisionUser = getConfigItemBoolValue(configFile, sectionName, "auto_provision_user", false)
+	config.OIDCLinkExistingUserByEmail = getConfigItemBoolValue(configFile, sectionName, "link_existing_user_by_email", false)
+	config.OIDCDefaultUserCurrency = getConfigItemStringValue(configFile, sectionName, "default_user_currency", defaultOIDCUserCurrency)
+	config.OIDCRequestTimeout = getConfigItemUint32Value(configFile, sectionName, "request_timeout", defaultOIDCRequestTimeout)
+	config.OIDCProxy = getConfigItemStringValue(configFile, sectionName, "proxy", "system")
+	config.OIDCSkipTLSVerify = getConfigItemBoolValue(configFile, sectionName, "skip_tls_verify", false)
+
+	if config.EnableOIDC && (config.OIDCIssuerUrl == "" || config.OIDCClientId == "") {
+		return errs.ErrInvalidOIDCConfig
+	}
+
+	return nil
+}
+
+func loadLDAPConfiguration(config *Config, configFile *ini.File, sectionName string) error {
+	config.EnableLDAP = getConfigItemBoolValue(configFile, sectionName, "enable_ldap", false)
+	config.LDAPServerUrl = getConfigItemStringValue(configFile, sectionName, "server_url")
+	config.LDAPStartTLS = getConfigItemBoolValue(configFile, sectionName, "start_tls", false)
+	config.LDAPSkipTLSVerify = getConfigItemBoolValue(configFile, sectionName, "skip_tls_verify", false)
+	config.LDAPBindDN = getConfigItemStringValue(configFile, sectionName, "bind_dn")
+	config.LDAPBindPassword = getConfigItemStringValue(configFile, sectionName, "bind_password")
+	config.LDAPBaseDN = getConfigItemStringValue(configFile, sectionName, "base_dn")
+	config.LDAPUserFilter = getConfigItemStringValue(configFile, sectionName, "user_filter", defaultLDAPUserFilter)
+	config.LDAPUsernameAttribute = getConfigItemStringValue(configFile, sectionName, "username_attribute", defaultLDAPUsernameAttribute)
+	config.LDAPEmailAttribute = getConfigItemStringValue(configFile, sectionName, "email_attribute", defaultLDAPEmailAttribute)
+	config.LDAPNicknameAttribute = getConfigItemStringValue(configFile, sectionName, "nickname_attribute", defaultLDAPNicknameAttribute)
+	config.LDAPRequiredGroupDN = getConfigItemStringValue(configFile, sectionName, "required_group_dn")
+	config.LDAPGroupMemberAttribute = getConfigItemStringValue(configFile, sectionName, "group_member_attribute", defaultLDAPGroupAttribute)
+	config.LDAPDefaultUserCurrency = getConfigItemStringValue(configFile, sectionName, "default_user_currency", defaultLDAPUserCurrency)
+	config.LDAPRequestTimeout = getConfigItemUint32Value(configFile, sectionName, "request_timeout", defaultLDAPRequestTimeout)
+
+	if config.EnableLDAP && (config.LDAPServerUrl == "" || config.LDAPBaseDN == "" || !strings.Contains(config.LDAPUserFilter, "{username}")) {
+		return errs.ErrInvalidLDAPConfig
+	}
+
+	return nil
+}
+
 func loadDataConfiguration(config *Config, configFile *ini.File, sectionName string) error {
 	config.EnableDataExport = getConfigItemBoolValue(configFile, sectionName, "enable_export", false)
 
@@ -590,28 +854,97 @@ func loadMapConfiguration(config *Config, configFile *ini.File, sectionName stri
 	return nil
 }
 func loadExchangeRatesConfiguration(config *Config, configFile *ini.File, sectionName string) error {
-	dataSource := getConfigItemStringValue(configFile, sectionName, "data_source")
-
-	if dataSource == EuroCentralBankDataSource {
-		config.ExchangeRatesDataSource = EuroCentralBankDataSource
-	} else if dataSource == BankOfCanadaDataSource {
-		config.ExchangeRatesDataSource = BankOfCanadaDataSource
-	} else if dataSource == ReserveBankOfAustraliaDataSource {
-		config.ExchangeRatesDataSource = ReserveBankOfAustraliaDataSource
-	} else if dataSource == CzechNationalBankDataSource {
-		config.ExchangeRatesDataSource = CzechNationalBankDataSource
-	} else if dataSource == NationalBankOfPolandDataSource {
-		config.ExchangeRatesDataSource = NationalBankOfPolandDataSource
-	} else if dataSource == MonetaryAuthorityOfSingaporeDataSource {
-		config.ExchangeRatesDataSource = MonetaryAuthorityOfSingaporeDataSource
-	} else {
-		return errs.ErrInvalidExchangeRatesDataSource
+	dataSources := strings.Split(getConfigItemStringValue(configFile, sectionName, "data_source"), ",")
+	config.ExchangeRatesDataSources = make([]string, 0, len(dataSources))
+
+	for i := 0; i < len(dataSources); i++ {
+		dataSource := strings.TrimSpace(dataSources[i])
+
+		if dataSource == EuroCentralBankDataSource ||
+			dataSource == BankOfCanadaDataSource ||
+			dataSource == ReserveBankOfAustraliaDataSource ||
+			dataSource == CzechNationalBankDataSource ||
+			dataSource == NationalBankOfPolandDataSource ||
+			dataSource == MonetaryAuthorityOfSingaporeDataSource ||
+			dataSource == BankOfEnglandDataSource ||
+			dataSource == SwissNationalBankDataSource ||
+			dataSource == BankOfIsraelDataSource ||
+			dataSource == DanmarksNationalbankDataSource ||
+			dataSource == NorgesBankDataSource ||
+			dataSource == InternationalMonetaryFundDataSource ||
+			dataSource == CustomExchangeRatesDataSource {
+			config.ExchangeRatesDataSources = append(config.ExchangeRatesDataSources, dataSource)
+		} else {
+			return errs.ErrInvalidExchangeRatesDataSource
+		}
 	}
 
+	config.ExchangeRatesDataSource = config.ExchangeRatesDataSources[0]
+
 	config.ExchangeRatesProxy = getConfigItemStringValue(configFile, sectionName, "proxy", "system")
 	config.ExchangeRatesRequestTimeout = getConfigItemUint32Value(configFile, sectionName, "request_timeout", defaultExchangeRatesDataRequestTimeout)
 	config.ExchangeRatesSkipTLSVerify = getConfigItemBoolValue(configFile, sectionName, "skip_tls_verify", false)
 
+	roundingMode := getConfigItemStringValue(configFile, sectionName, "rounding_mode", HalfUpRoundingMode)
+
+	if roundingMode == HalfUpRoundingMode ||
+		roundingMode == HalfEvenRoundingMode ||
+		roundingMode == DownRoundingMode ||
+		roundingMode == UpRoundingMode {
+		config.ExchangeRatesRoundingMode = roundingMode
+	} else {
+		return errs.ErrInvalidExchangeRatesRoundingMode
+	}
+
+	config.ExchangeRatesCustomDataSourceName = getConfigItemStringValue(configFile, sectionName, "custom_data_source_name", "Custom")
+	config.ExchangeRatesCustomDataSourceUrl = getConfigItemStringValue(configFile, sectionName, "custom_data_source_url")
+	config.ExchangeRatesCustomDataSourceReferenceUrl = getConfigItemStringValue(configFile, sectionName, "custom_data_source_reference_url")
+	config.ExchangeRatesCustomDataSourceBaseCurrency = getConfigItemStringValue(configFile, sectionName, "custom_data_source_base_currency")
+	config.ExchangeRatesCustomDataSourceBaseCurrencyPath = getConfigItemStringValue(configFile, sectionName, "custom_data_source_base_currency_path")
+	config.ExchangeRatesCustomDataSourceRatesPath = getConfigItemStringValue(configFile, sectionName, "custom_data_source_rates_path")
+	config.ExchangeRatesCustomDataSourceCurrencyPath = getConfigItemStringValue(configFile, sectionName, "custom_data_source_currency_path")
+	config.ExchangeRatesCustomDataSourceRatePath = getConfigItemStringValue(configFile, sectionName, "custom_data_source_rate_path")
+	config.ExchangeRatesCustomDataSourceRateInverted = getConfigItemBoolValue(configFile, sectionName, "custom_data_source_rate_inverted", false)
+	config.ExchangeRatesCustomDataSourceUpdateTimePath = getConfigItemStringValue(configFile, sectionName, "custom_data_source_update_time_path")
+	config.ExchangeRatesCustomDataSourceUpdateTimeFormat = getConfigItemStringValue(configFile, sectionName, "custom_data_source_update_time_format", "unix")
+	config.ExchangeRatesCustomDataSourceUpdateInterval = getConfigItemUint32Value(configFile, sectionName, "custom_data_source_update_interval", defaultExchangeRatesCustomDataSourceInterval)
+
+	if config.ExchangeRatesCustomDataSourceUpdateInterval < minExchangeRatesCustomDataSourceInterval || config.ExchangeRatesCustomDataSourceUpdateInterval > maxExchangeRatesCustomDataSourceInterval {
+		config.ExchangeRatesCustomDataSourceUpdateInterval = defaultExchangeRatesCustomDataSourceInterval
+	}
+
+	config.CommodityQuoteUrl = getConfigItemStringValue(configFile, sectionName, "commodity_quote_url")
+	config.CommodityQuotePricePath = getConfigItemStringValue(configFile, sectionName, "commodity_quote_price_path")
+
+	customDataSourceFormat := getConfigItemStringValue(configFile, sectionName, "custom_data_source_format", CustomExchangeRatesDataSourceJsonFormat)
+
+	if customDataSourceFormat == CustomExchangeRatesDataSourceJsonFormat {
+		config.ExchangeRatesCustomDataSourceFormat = CustomExchangeRatesDataSourceJsonFormat
+	} else if customDataSourceFormat == CustomExchangeRatesDataSourceXmlFormat {
+		config.ExchangeRatesCustomDataSourceFormat = CustomExchangeRatesDataSourceXmlFormat
+	} else if customDataSourceFormat == CustomExchangeRatesDataSourceCsvFormat {
+		config.ExchangeRatesCustomDataSourceFormat = CustomExchangeRatesDataSourceCsvFormat
+	} else {
+		return errs.ErrInvalidCustomExchangeRatesDataSourceConfig
+	}
+
+	for i := 0; i < len(config.ExchangeRatesDataSources); i++ {
+		if config.ExchangeRatesDataSources[i] != CustomExchangeRatesDataSource {
+			continue
+		}
+
+		if config.ExchangeRatesCustomDataSourceUrl == "" ||
+			config.ExchangeRatesCustomDataSourceCurrencyPath == "" ||
+			config.ExchangeRatesCustomDataSourceRatePath == "" ||
+			(config.ExchangeRatesCustomDataSourceBaseCurrency == "" && config.ExchangeRatesCustomDataSourceBaseCurrencyPath == "") {
+			return errs.ErrInvalidCustomExchangeRatesDataSourceConfig
+		}
+	}
+
+	if config.CommodityQuoteUrl != "" && config.CommodityQuotePricePath == "" {
+		return errs.ErrInvalidCommodityQuoteConfig
+	}
+
 	return nil
 }
 
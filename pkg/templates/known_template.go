@@ -4,6 +4,9 @@ type KnownTemplate string
 
 // Known templates
 const (
-	TEMPLATE_VERIFY_EMAIL   KnownTemplate = "email/verify_email"
-	TEMPLATE_PASSWORD_RESET KnownTemplate = "email/password_reset"
+	TEMPLATE_VERIFY_EMAIL         KnownTemplate = "email/verify_email"
+	TEMPLATE_PASSWORD_RESET       KnownTemplate = "email/password_reset"
+	TEMPLATE_ACCOUNT_LOCKED       KnownTemplate = "email/account_locked"
+	TEMPLATE_EMAIL_CHANGE_CONFIRM KnownTemplate = "email/email_change_confirm"
+	TEMPLATE_EMAIL_CHANGE_NOTIFY  KnownTemplate = "email/email_change_notify"
 )
@@ -12,6 +12,7 @@ const (
 	longDateTimeFormat              = "2006-01-02 15:04:05"
 	longDateTimeWithoutSecondFormat = "2006-01-02 15:04"
 	shortDateTimeFormat             = "2006-1-2 15:4:5"
+	longDateFormat                  = "2006-01-02"
 	yearMonthDateTimeFormat         = "2006-01"
 	westernmostTimezoneUtcOffset    = -720 // Etc/GMT+12 (UTC-12:00)
 	easternmostTimezoneUtcOffset    = 840  // Pacific/Kiritimati (UTC+14:00)
@@ -40,6 +41,22 @@ func ParseNumericYearMonth(yearMonth string) (int32, int32, error) {
 	return year, month, nil
 }
 
+// ParseNumericDate returns numeric year, month and day from textual content in long date format
+func ParseNumericDate(date string) (int32, error) {
+	t, err := time.Parse(longDateFormat, date)
+
+	if err != nil {
+		return 0, errs.ErrParameterInvalid
+	}
+
+	return int32(t.Year())*10000 + int32(t.Month())*100 + int32(t.Day()), nil
+}
+
+// FormatNumericDateToLongDate returns a textual representation of the numeric date formatted by long date format
+func FormatNumericDateToLongDate(date int32) string {
+	return fmt.Sprintf("%04d-%02d-%02d", date/10000, date/100%100, date%100)
+}
+
 // FormatUnixTimeToLongDateTime returns a textual representation of the unix time formatted by long date time format
 func FormatUnixTimeToLongDateTime(unixTime int64, timezone *time.Location) string {
 	t := parseFromUnixTime(unixTime)
@@ -89,6 +106,17 @@ func FormatUnixTimeToNumericYearMonth(unixTime int64, timezone *time.Location) i
 	return int32(t.Year())*100 + int32(t.Month())
 }
 
+// FormatUnixTimeToNumericDate returns numeric year, month and day of specified unix time
+func FormatUnixTimeToNumericDate(unixTime int64, timezone *time.Location) int32 {
+	t := parseFromUnixTime(unixTime)
+
+	if timezone != nil {
+		t = t.In(timezone)
+	}
+
+	return int32(t.Year())*10000 + int32(t.Month())*100 + int32(t.Day())
+}
+
 // FormatUnixTimeToNumericLocalDateTime returns numeric year, month, day, hour, minute and second of specified unix time
 func FormatUnixTimeToNumericLocalDateTime(unixTime int64, timezone *time.Location) int64 {
 	t := parseFromUnixTime(unixTime)
@@ -267,6 +295,70 @@ func GetTransactionTimeRangeByYearMonth(year int32, month int32) (int64, int64,
 	return minTransactionTime, maxTransactionTime, nil
 }
 
+// GetTransactionTimeRangeByNumericDateRange returns the transaction time range by specified start date and end date (both included)
+func GetTransactionTimeRangeByNumericDateRange(startDate int32, endDate int32) (int64, int64, error) {
+	startMinUnixTime, err := ParseFromLongDateTimeToMinUnixTime(FormatNumericDateToLongDate(startDate) + " 00:00:00")
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	endMaxUnixTime, err := ParseFromLongDateTimeToMaxUnixTime(FormatNumericDateToLongDate(endDate) + " 00:00:00")
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	minTransactionTime := GetMinTransactionTimeFromUnixTime(startMinUnixTime.Unix())
+	maxTransactionTime := GetMinTransactionTimeFromUnixTime(endMaxUnixTime.AddDate(0, 0, 1).Unix()) - 1
+
+	return minTransactionTime, maxTransactionTime, nil
+}
+
+// FormatTimeToNumericDate returns numeric year, month and day of specified time
+func FormatTimeToNumericDate(t time.Time) int32 {
+	return int32(t.Year())*10000 + int32(t.Month())*100 + int32(t.Day())
+}
+
+// GetStartOfDay returns the beginning of the day which contains the specified time
+func GetStartOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// GetStartOfWeek returns the beginning of the week which contains the specified time and starts on the specified week day
+func GetStartOfWeek(t time.Time, firstDayOfWeek time.Weekday) time.Time {
+	dayOffset := (int(t.Weekday()) - int(firstDayOfWeek) + 7) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-dayOffset, 0, 0, 0, 0, t.Location())
+}
+
+// GetStartOfMonth returns the beginning of the month which contains the specified time and starts on the specified day of month
+func GetStartOfMonth(t time.Time, monthStartDay int) time.Time {
+	if t.Day() >= monthStartDay {
+		return time.Date(t.Year(), t.Month(), monthStartDay, 0, 0, 0, 0, t.Location())
+	}
+
+	return time.Date(t.Year(), t.Month()-1, monthStartDay, 0, 0, 0, 0, t.Location())
+}
+
+// GetStartOfQuarter returns the beginning of the quarter which contains the specified time,
+// the quarters are counted from the specified first month of year, and every month starts on the specified day of month
+func GetStartOfQuarter(t time.Time, firstMonthOfYear time.Month, monthStartDay int) time.Time {
+	return getStartOfMonths(t, firstMonthOfYear, monthStartDay, 3)
+}
+
+// GetStartOfYear returns the beginning of the year which contains the specified time,
+// the year starts on the specified first month of year, and every month starts on the specified day of month
+func GetStartOfYear(t time.Time, firstMonthOfYear time.Month, monthStartDay int) time.Time {
+	return getStartOfMonths(t, firstMonthOfYear, monthStartDay, 12)
+}
+
+func getStartOfMonths(t time.Time, firstMonthOfYear time.Month, monthStartDay int, monthCount int) time.Time {
+	monthStart := GetStartOfMonth(t, monthStartDay)
+	monthOffset := (int(monthStart.Month()) - int(firstMonthOfYear) + 12) % 12 % monthCount
+
+	return time.Date(monthStart.Year(), monthStart.Month()-time.Month(monthOffset), monthStartDay, 0, 0, 0, 0, t.Location())
+}
+
 // parseFromUnixTime parses a unix time and returns a golang time struct
 func parseFromUnixTime(unixTime int64) time.Time {
 	return time.Unix(unixTime, 0)
@@ -16,6 +16,27 @@ func TestParseNumericYearMonth(t *testing.T) {
 	assert.Equal(t, expectedMonth, actualMonth)
 }
 
+func TestParseNumericDate(t *testing.T) {
+	expectedValue := int32(20240305)
+	actualValue, err := ParseNumericDate("2024-03-05")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestParseNumericDate_InvalidDate(t *testing.T) {
+	_, err := ParseNumericDate("2024-3-5")
+	assert.NotEqual(t, nil, err)
+
+	_, err = ParseNumericDate("2024-02-30")
+	assert.NotEqual(t, nil, err)
+}
+
+func TestFormatNumericDateToLongDate(t *testing.T) {
+	expectedValue := "2024-03-05"
+	actualValue := FormatNumericDateToLongDate(20240305)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
 func TestFormatUnixTimeToLongDateTime(t *testing.T) {
 	unixTime := int64(1617228083)
 	utcTimezone := time.FixedZone("Test Timezone", 0)      // UTC
@@ -72,6 +93,20 @@ func TestFormatUnixTimeToNumericYearMonth(t *testing.T) {
 	assert.Equal(t, expectedValue, actualValue)
 }
 
+func TestFormatUnixTimeToNumericDate(t *testing.T) {
+	unixTime := int64(1617228083)
+	utcTimezone := time.FixedZone("Test Timezone", 0)      // UTC
+	utc8Timezone := time.FixedZone("Test Timezone", 28800) // UTC+8
+
+	expectedValue := int32(20210331)
+	actualValue := FormatUnixTimeToNumericDate(unixTime, utcTimezone)
+	assert.Equal(t, expectedValue, actualValue)
+
+	expectedValue = int32(20210401)
+	actualValue = FormatUnixTimeToNumericDate(unixTime, utc8Timezone)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
 func TestFormatUnixTimeToNumericLocalDateTime(t *testing.T) {
 	unixTime := int64(1617228083)
 	utcTimezone := time.FixedZone("Test Timezone", 0)      // UTC
@@ -300,3 +335,49 @@ func TestParseFromUnixTime(t *testing.T) {
 	actualValue := actualTime.Unix()
 	assert.Equal(t, expectedValue, actualValue)
 }
+
+func TestGetTransactionTimeRangeByNumericDateRange(t *testing.T) {
+	expectedMinValue := int64(1704016800000)
+	expectedMaxValue := int64(1706788799999)
+	actualMinValue, actualMaxValue, err := GetTransactionTimeRangeByNumericDateRange(20240101, 20240131)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, expectedMinValue, actualMinValue)
+	assert.Equal(t, expectedMaxValue, actualMaxValue)
+}
+
+func TestFormatTimeToNumericDate(t *testing.T) {
+	expectedValue := int32(20240229)
+	actualValue := FormatTimeToNumericDate(time.Date(2024, 2, 29, 23, 59, 59, 0, time.UTC))
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetStartOfWeek(t *testing.T) {
+	wednesday := time.Date(2024, 3, 6, 12, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC), GetStartOfWeek(wednesday, time.Sunday))
+	assert.Equal(t, time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), GetStartOfWeek(wednesday, time.Monday))
+	assert.Equal(t, time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC), GetStartOfWeek(wednesday, time.Wednesday))
+	assert.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), GetStartOfWeek(wednesday, time.Thursday))
+}
+
+func TestGetStartOfMonth(t *testing.T) {
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), GetStartOfMonth(time.Date(2024, 3, 6, 12, 30, 0, 0, time.UTC), 1))
+	assert.Equal(t, time.Date(2024, 2, 25, 0, 0, 0, 0, time.UTC), GetStartOfMonth(time.Date(2024, 3, 6, 12, 30, 0, 0, time.UTC), 25))
+	assert.Equal(t, time.Date(2024, 3, 25, 0, 0, 0, 0, time.UTC), GetStartOfMonth(time.Date(2024, 3, 25, 0, 0, 0, 0, time.UTC), 25))
+	assert.Equal(t, time.Date(2023, 12, 25, 0, 0, 0, 0, time.UTC), GetStartOfMonth(time.Date(2024, 1, 24, 23, 59, 59, 0, time.UTC), 25))
+}
+
+func TestGetStartOfQuarter(t *testing.T) {
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), GetStartOfQuarter(time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), time.January, 1))
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), GetStartOfQuarter(time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), time.April, 1))
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), GetStartOfQuarter(time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), time.May, 1))
+	assert.Equal(t, time.Date(2023, 12, 25, 0, 0, 0, 0, time.UTC), GetStartOfQuarter(time.Date(2024, 3, 24, 0, 0, 0, 0, time.UTC), time.March, 25))
+}
+
+func TestGetStartOfYear(t *testing.T) {
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), GetStartOfYear(time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), time.January, 1))
+	assert.Equal(t, time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC), GetStartOfYear(time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), time.April, 1))
+	assert.Equal(t, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), GetStartOfYear(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), time.April, 1))
+	assert.Equal(t, time.Date(2023, 4, 25, 0, 0, 0, 0, time.UTC), GetStartOfYear(time.Date(2024, 4, 24, 0, 0, 0, 0, time.UTC), time.April, 25))
+	assert.Equal(t, time.Date(2024, 4, 25, 0, 0, 0, 0, time.UTC), GetStartOfYear(time.Date(2024, 4, 25, 0, 0, 0, 0, time.UTC), time.April, 25))
+}
@@ -0,0 +1,155 @@
+package utils
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/errs"
+)
+
+// RoundingMode represents the rounding mode of decimal calculation
+type RoundingMode byte
+
+// Rounding modes
+const (
+	ROUNDING_MODE_HALF_UP   RoundingMode = 0
+	ROUNDING_MODE_HALF_EVEN RoundingMode = 1
+	ROUNDING_MODE_DOWN      RoundingMode = 2
+	ROUNDING_MODE_UP        RoundingMode = 3
+)
+
+// ParseDecimal returns the exact rational number of the textual decimal number
+func ParseDecimal(str string) (*big.Rat, error) {
+	str = strings.TrimSpace(str)
+
+	// the fraction format (e.g. "1/3") is also accepted by big.Rat, but it is not a decimal number
+	if strings.Contains(str, "/") {
+		return nil, errs.ErrFormatInvalid
+	}
+
+	value, ok := new(big.Rat).SetString(str)
+
+	if !ok {
+		return nil, errs.ErrFormatInvalid
+	}
+
+	return value, nil
+}
+
+// ConvertAmountByExchangeRates returns the amount in target currency which is converted from the amount in source currency,
+// the rates are the amounts of source currency and target currency per 1 unit of the same base currency
+func ConvertAmountByExchangeRates(amount int64, fromRate string, toRate string, roundingMode RoundingMode) (int64, error) {
+	from, err := ParseDecimal(fromRate)
+
+	if err != nil || from.Sign() <= 0 {
+		return 0, errs.ErrParameterInvalid
+	}
+
+	to, err := ParseDecimal(toRate)
+
+	if err != nil || to.Sign() <= 0 {
+		return 0, errs.ErrParameterInvalid
+	}
+
+	result := new(big.Rat).SetInt64(amount)
+	result.Mul(result, to)
+	result.Quo(result, from)
+
+	return RoundDecimalToInt64(result, roundingMode)
+}
+
+// ConvertAmountByPrice returns the amount in price currency which is converted from the amount of commodity by the textual unit price,
+// the amounts are the integers in the smallest units of the specified decimal places
+func ConvertAmountByPrice(amount int64, amountDecimalPlaces int32, price string, priceDecimalPlaces int32, roundingMode RoundingMode) (int64, error) {
+	unitPrice, err := ParseDecimal(price)
+
+	if err != nil || unitPrice.Sign() < 0 || amountDecimalPlaces < 0 || priceDecimalPlaces < 0 {
+		return 0, errs.ErrParameterInvalid
+	}
+
+	amountScale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(amountDecimalPlaces)), nil)
+	priceScale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(priceDecimalPlaces)), nil)
+
+	result := new(big.Rat).SetInt64(amount)
+	result.Mul(result, unitPrice)
+	result.Mul(result, new(big.Rat).SetInt(priceScale))
+	result.Quo(result, new(big.Rat).SetInt(amountScale))
+
+	return RoundDecimalToInt64(result, roundingMode)
+}
+
+// GetCrossRate returns the textual amount of target currency per 1 unit of source currency with the specified decimal places,
+// the rates are the amounts of source currency and target currency per 1 unit of the same base currency
+func GetCrossRate(fromRate string, toRate string, decimalPlaces int) (string, error) {
+	from, err := ParseDecimal(fromRate)
+
+	if err != nil || from.Sign() <= 0 {
+		return "", errs.ErrParameterInvalid
+	}
+
+	to, err := ParseDecimal(toRate)
+
+	if err != nil || to.Sign() <= 0 {
+		return "", errs.ErrParameterInvalid
+	}
+
+	return FormatDecimal(new(big.Rat).Quo(to, from), decimalPlaces), nil
+}
+
+// MultiplyRates returns the textual product of two rates with the specified decimal places,
+// it is used to convert the rate based on one currency into the rate based on another currency
+func MultiplyRates(rate string, factor string, decimalPlaces int) (string, error) {
+	value, err := ParseDecimal(rate)
+
+	if err != nil || value.Sign() <= 0 {
+		return "", errs.ErrParameterInvalid
+	}
+
+	multiplier, err := ParseDecimal(factor)
+
+	if err != nil || multiplier.Sign() <= 0 {
+		return "", errs.ErrParameterInvalid
+	}
+
+	return FormatDecimal(new(big.Rat).Mul(value, multiplier), decimalPlaces), nil
+}
+
+// FormatDecimal returns the textual decimal number which is rounded to the specified decimal places without trailing zeros
+func FormatDecimal(value *big.Rat, decimalPlaces int) string {
+	text := value.FloatString(decimalPlaces)
+
+	if strings.Contains(text, ".") {
+		text = strings.TrimRight(strings.TrimRight(text, "0"), ".")
+	}
+
+	return text
+}
+
+// RoundDecimalToInt64 returns the integer which is rounded from the rational number by the specified rounding mode
+func RoundDecimalToInt64(value *big.Rat, roundingMode RoundingMode) (int64, error) {
+	quotient, remainder := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int))
+
+	if remainder.Sign() != 0 {
+		// the doubled remainder is compared with denominator to find out whether the fraction is less than, equal to or greater than half
+		halfComparison := new(big.Int).Abs(new(big.Int).Mul(remainder, big.NewInt(2))).Cmp(value.Denom())
+		roundAwayFromZero := false
+
+		if roundingMode == ROUNDING_MODE_HALF_UP {
+			roundAwayFromZero = halfComparison >= 0
+		} else if roundingMode == ROUNDING_MODE_HALF_EVEN {
+			roundAwayFromZero = halfComparison > 0 || (halfComparison == 0 && quotient.Bit(0) == 1)
+		} else if roundingMode == ROUNDING_MODE_UP {
+			roundAwayFromZero = true
+		}
+
+		if roundAwayFromZero {
+			quotient.Add(quotient, big.NewInt(int64(value.Sign())))
+		}
+	}
+
+	if !quotient.IsInt64() {
+		return 0, errs.ErrParameterInvalid
+	}
+
+	return quotient.Int64(), nil
+}
@@ -0,0 +1,169 @@
+package utils
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDecimal(t *testing.T) {
+	actualValue, err := ParseDecimal("1.0637")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "10637/10000", actualValue.String())
+
+	actualValue, err = ParseDecimal("1e-3")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "1/1000", actualValue.String())
+}
+
+func TestParseDecimal_InvalidNumber(t *testing.T) {
+	_, err := ParseDecimal("")
+	assert.NotEqual(t, nil, err)
+
+	_, err = ParseDecimal("null")
+	assert.NotEqual(t, nil, err)
+
+	_, err = ParseDecimal("1/3")
+	assert.NotEqual(t, nil, err)
+}
+
+func TestConvertAmountByExchangeRates(t *testing.T) {
+	actualValue, err := ConvertAmountByExchangeRates(10000, "1", "1.0637", ROUNDING_MODE_HALF_UP)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(10637), actualValue)
+
+	actualValue, err = ConvertAmountByExchangeRates(10637, "1.0637", "1", ROUNDING_MODE_HALF_UP)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(10000), actualValue)
+
+	actualValue, err = ConvertAmountByExchangeRates(12345, "1.0637", "164.54", ROUNDING_MODE_HALF_UP)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1909604), actualValue)
+}
+
+func TestConvertAmountByExchangeRates_PreciseDecimal(t *testing.T) {
+	// the results are exactly half, which may be slightly less or more than half in float64 calculation
+	actualValue, err := ConvertAmountByExchangeRates(5, "0.2", "0.1", ROUNDING_MODE_HALF_EVEN)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(2), actualValue)
+
+	actualValue, err = ConvertAmountByExchangeRates(1, "1", "0.145", ROUNDING_MODE_HALF_UP)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(0), actualValue)
+
+	actualValue, err = ConvertAmountByExchangeRates(100, "1", "0.145", ROUNDING_MODE_HALF_UP)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(15), actualValue)
+}
+
+func TestConvertAmountByExchangeRates_InvalidRate(t *testing.T) {
+	_, err := ConvertAmountByExchangeRates(100, "0", "1", ROUNDING_MODE_HALF_UP)
+	assert.NotEqual(t, nil, err)
+
+	_, err = ConvertAmountByExchangeRates(100, "1", "-1", ROUNDING_MODE_HALF_UP)
+	assert.NotEqual(t, nil, err)
+
+	_, err = ConvertAmountByExchangeRates(100, "null", "1", ROUNDING_MODE_HALF_UP)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestConvertAmountByExchangeRates_OutOfRange(t *testing.T) {
+	_, err := ConvertAmountByExchangeRates(9223372036854775807, "1", "2", ROUNDING_MODE_HALF_UP)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestConvertAmountByPrice(t *testing.T) {
+	actualValue, err := ConvertAmountByPrice(150000000, 8, "64321.57", 2, ROUNDING_MODE_HALF_UP)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(9648236), actualValue)
+
+	actualValue, err = ConvertAmountByPrice(12345, 0, "0.0125", 2, ROUNDING_MODE_HALF_UP)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(15431), actualValue)
+
+	actualValue, err = ConvertAmountByPrice(12345, 0, "0.0125", 2, ROUNDING_MODE_UP)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(15432), actualValue)
+
+	actualValue, err = ConvertAmountByPrice(1000, 2, "3", 2, ROUNDING_MODE_HALF_UP)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(3000), actualValue)
+}
+
+func TestConvertAmountByPrice_InvalidPrice(t *testing.T) {
+	_, err := ConvertAmountByPrice(100, 8, "-1", 2, ROUNDING_MODE_HALF_UP)
+	assert.NotEqual(t, nil, err)
+
+	_, err = ConvertAmountByPrice(100, 8, "", 2, ROUNDING_MODE_HALF_UP)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestGetCrossRate(t *testing.T) {
+	actualValue, err := GetCrossRate("1", "1.0637", 10)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "1.0637", actualValue)
+
+	actualValue, err = GetCrossRate("3", "1", 10)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "0.3333333333", actualValue)
+
+	actualValue, err = GetCrossRate("2", "4", 10)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "2", actualValue)
+}
+
+func TestMultiplyRates(t *testing.T) {
+	actualValue, err := MultiplyRates("0.0036725", "1.0637", 18)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "0.00390643825", actualValue)
+
+	actualValue, err = MultiplyRates("1.5", "2", 18)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "3", actualValue)
+
+	_, err = MultiplyRates("0", "2", 18)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestFormatDecimal(t *testing.T) {
+	assert.Equal(t, "0.0036725", FormatDecimal(big.NewRat(36725, 10000000), 10))
+	assert.Equal(t, "0.3333333333", FormatDecimal(big.NewRat(1, 3), 10))
+	assert.Equal(t, "2", FormatDecimal(big.NewRat(4, 2), 10))
+	assert.Equal(t, "34.5", FormatDecimal(big.NewRat(69, 2), 10))
+}
+
+func TestRoundDecimalToInt64_HalfUp(t *testing.T) {
+	assertRoundDecimal(t, ROUNDING_MODE_HALF_UP, "2.5", 3)
+	assertRoundDecimal(t, ROUNDING_MODE_HALF_UP, "-2.5", -3)
+	assertRoundDecimal(t, ROUNDING_MODE_HALF_UP, "2.4", 2)
+	assertRoundDecimal(t, ROUNDING_MODE_HALF_UP, "-2.6", -3)
+}
+
+func TestRoundDecimalToInt64_HalfEven(t *testing.T) {
+	assertRoundDecimal(t, ROUNDING_MODE_HALF_EVEN, "2.5", 2)
+	assertRoundDecimal(t, ROUNDING_MODE_HALF_EVEN, "3.5", 4)
+	assertRoundDecimal(t, ROUNDING_MODE_HALF_EVEN, "-2.5", -2)
+	assertRoundDecimal(t, ROUNDING_MODE_HALF_EVEN, "-3.5", -4)
+	assertRoundDecimal(t, ROUNDING_MODE_HALF_EVEN, "2.51", 3)
+}
+
+func TestRoundDecimalToInt64_Down(t *testing.T) {
+	assertRoundDecimal(t, ROUNDING_MODE_DOWN, "2.9", 2)
+	assertRoundDecimal(t, ROUNDING_MODE_DOWN, "-2.9", -2)
+}
+
+func TestRoundDecimalToInt64_Up(t *testing.T) {
+	assertRoundDecimal(t, ROUNDING_MODE_UP, "2.1", 3)
+	assertRoundDecimal(t, ROUNDING_MODE_UP, "-2.1", -3)
+	assertRoundDecimal(t, ROUNDING_MODE_UP, "2", 2)
+}
+
+func assertRoundDecimal(t *testing.T, roundingMode RoundingMode, value string, expectedValue int64) {
+	decimal, err := ParseDecimal(value)
+	assert.Equal(t, nil, err)
+
+	actualValue, err := RoundDecimalToInt64(decimal, roundingMode)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, expectedValue, actualValue)
+}
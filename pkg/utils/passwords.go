@@ -0,0 +1,204 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const passwordHashSaltLength = 16
+const passwordHashKeyLength = 32
+
+// Password hash algorithms
+const (
+	PASSWORD_HASH_ALGORITHM_PBKDF2_SHA256 = "pbkdf2-sha256"
+	PASSWORD_HASH_ALGORITHM_ARGON2ID      = "argon2id"
+)
+
+// PasswordHashOptions represents the algorithm and the cost parameters used for hashing new passwords
+type PasswordHashOptions struct {
+	Algorithm         string
+	Pbkdf2Iterations  uint32
+	Argon2Memory      uint32
+	Argon2Iterations  uint32
+	Argon2Parallelism uint8
+}
+
+type passwordHash struct {
+	algorithm   string
+	version     int
+	iterations  uint32
+	memory      uint32
+	parallelism uint8
+	salt        []byte
+	key         []byte
+}
+
+// EncodePasswordHash returns a versioned password hash in PHC string format (e.g. $argon2id$v=19$m=65536,t=3,p=2$salt$hash),
+// which contains the algorithm, the cost parameters and a random salt
+func EncodePasswordHash(password string, options *PasswordHashOptions) (string, error) {
+	salt := make([]byte, passwordHashSaltLength)
+
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	hash := &passwordHash{
+		algorithm: options.Algorithm,
+		salt:      salt,
+	}
+
+	switch options.Algorithm {
+	case PASSWORD_HASH_ALGORITHM_ARGON2ID:
+		hash.version = argon2.Version
+		hash.memory = options.Argon2Memory
+		hash.iterations = options.Argon2Iterations
+		hash.parallelism = options.Argon2Parallelism
+	case PASSWORD_HASH_ALGORITHM_PBKDF2_SHA256:
+		hash.iterations = options.Pbkdf2Iterations
+	default:
+		return "", fmt.Errorf("unsupported password hash algorithm \"%s\"", options.Algorithm)
+	}
+
+	if hash.iterations < 1 || (hash.algorithm == PASSWORD_HASH_ALGORITHM_ARGON2ID && (hash.memory < 1 || hash.parallelism < 1)) {
+		return "", fmt.Errorf("invalid cost parameters of password hash algorithm \"%s\"", options.Algorithm)
+	}
+
+	hash.key = hash.derive(password)
+
+	return hash.String(), nil
+}
+
+// VerifyPasswordHash returns whether the password matches the password hash, the password hash can be either
+// a versioned password hash or a legacy password hash which is encoded by EncodePassword with the specified salt
+func VerifyPasswordHash(password string, encodedPasswordHash string, legacySalt string) bool {
+	if !strings.HasPrefix(encodedPasswordHash, "$") {
+		return subtle.ConstantTimeCompare([]byte(EncodePassword(password, legacySalt)), []byte(encodedPasswordHash)) == 1
+	}
+
+	hash, err := parsePasswordHash(encodedPasswordHash)
+
+	if err != nil {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare(hash.derive(password), hash.key) == 1
+}
+
+// IsPasswordHashOutdated returns whether the password hash is a legacy password hash, or is not encoded
+// by the algorithm and the cost parameters of the specified options, so it should be rehashed
+func IsPasswordHashOutdated(encodedPasswordHash string, options *PasswordHashOptions) bool {
+	if !strings.HasPrefix(encodedPasswordHash, "$") {
+		return true
+	}
+
+	hash, err := parsePasswordHash(encodedPasswordHash)
+
+	if err != nil || hash.algorithm != options.Algorithm {
+		return true
+	}
+
+	switch hash.algorithm {
+	case PASSWORD_HASH_ALGORITHM_ARGON2ID:
+		return hash.version != argon2.Version || hash.memory != options.Argon2Memory || hash.iterations != options.Argon2Iterations || hash.parallelism != options.Argon2Parallelism
+	case PASSWORD_HASH_ALGORITHM_PBKDF2_SHA256:
+		return hash.iterations != options.Pbkdf2Iterations
+	}
+
+	return true
+}
+
+func (h *passwordHash) derive(password string) []byte {
+	switch h.algorithm {
+	case PASSWORD_HASH_ALGORITHM_ARGON2ID:
+		return argon2.IDKey([]byte(password), h.salt, h.iterations, h.memory, h.parallelism, passwordHashKeyLength)
+	case PASSWORD_HASH_ALGORITHM_PBKDF2_SHA256:
+		return pbkdf2.Key([]byte(password), h.salt, int(h.iterations), passwordHashKeyLength, sha256.New)
+	}
+
+	return nil
+}
+
+func (h *passwordHash) String() string {
+	salt := base64.RawStdEncoding.EncodeToString(h.salt)
+	key := base64.RawStdEncoding.EncodeToString(h.key)
+
+	if h.algorithm == PASSWORD_HASH_ALGORITHM_ARGON2ID {
+		return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s", h.algorithm, h.version, h.memory, h.iterations, h.parallelism, salt, key)
+	}
+
+	return fmt.Sprintf("$%s$i=%d$%s$%s", h.algorithm, h.iterations, salt, key)
+}
+
+func parsePasswordHash(encodedPasswordHash string) (*passwordHash, error) {
+	items := strings.Split(encodedPasswordHash, "$")
+
+	if len(items) < 2 || items[0] != "" {
+		return nil, fmt.Errorf("invalid password hash format")
+	}
+
+	hash := &passwordHash{
+		algorithm: items[1],
+	}
+
+	var encodedSalt, encodedKey string
+
+	switch hash.algorithm {
+	case PASSWORD_HASH_ALGORITHM_ARGON2ID:
+		if len(items) != 6 {
+			return nil, fmt.Errorf("invalid argon2id password hash format")
+		}
+
+		if _, err := fmt.Sscanf(items[2], "v=%d", &hash.version); err != nil {
+			return nil, err
+		}
+
+		if _, err := fmt.Sscanf(items[3], "m=%d,t=%d,p=%d", &hash.memory, &hash.iterations, &hash.parallelism); err != nil {
+			return nil, err
+		}
+
+		if hash.version != argon2.Version || hash.memory < 1 || hash.iterations < 1 || hash.parallelism < 1 {
+			return nil, fmt.Errorf("invalid argon2id password hash parameters")
+		}
+
+		encodedSalt, encodedKey = items[4], items[5]
+	case PASSWORD_HASH_ALGORITHM_PBKDF2_SHA256:
+		if len(items) != 5 {
+			return nil, fmt.Errorf("invalid pbkdf2 password hash format")
+		}
+
+		if _, err := fmt.Sscanf(items[2], "i=%d", &hash.iterations); err != nil {
+			return nil, err
+		}
+
+		if hash.iterations < 1 {
+			return nil, fmt.Errorf("invalid pbkdf2 password hash parameters")
+		}
+
+		encodedSalt, encodedKey = items[3], items[4]
+	default:
+		return nil, fmt.Errorf("unsupported password hash algorithm \"%s\"", hash.algorithm)
+	}
+
+	var err error
+
+	if hash.salt, err = base64.RawStdEncoding.DecodeString(encodedSalt); err != nil {
+		return nil, err
+	}
+
+	if hash.key, err = base64.RawStdEncoding.DecodeString(encodedKey); err != nil {
+		return nil, err
+	}
+
+	if len(hash.key) != passwordHashKeyLength {
+		return nil, fmt.Errorf("invalid password hash length")
+	}
+
+	return hash, nil
+}
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testArgon2idPasswordHashOptions = &PasswordHashOptions{
+	Algorithm:         PASSWORD_HASH_ALGORITHM_ARGON2ID,
+	Argon2Memory:      1024,
+	Argon2Iterations:  1,
+	Argon2Parallelism: 1,
+}
+
+var testPbkdf2PasswordHashOptions = &PasswordHashOptions{
+	Algorithm:        PASSWORD_HASH_ALGORITHM_PBKDF2_SHA256,
+	Pbkdf2Iterations: 1000,
+}
+
+func TestEncodePasswordHash_Argon2id(t *testing.T) {
+	passwordHash, err := EncodePasswordHash("foobar", testArgon2idPasswordHashOptions)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(passwordHash, "$argon2id$v=19$m=1024,t=1,p=1$"))
+	assert.LessOrEqual(t, len(passwordHash), 255)
+
+	assert.True(t, VerifyPasswordHash("foobar", passwordHash, ""))
+	assert.False(t, VerifyPasswordHash("foobaz", passwordHash, ""))
+}
+
+func TestEncodePasswordHash_Pbkdf2(t *testing.T) {
+	passwordHash, err := EncodePasswordHash("foobar", testPbkdf2PasswordHashOptions)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(passwordHash, "$pbkdf2-sha256$i=1000$"))
+
+	assert.True(t, VerifyPasswordHash("foobar", passwordHash, ""))
+	assert.False(t, VerifyPasswordHash("foobaz", passwordHash, ""))
+}
+
+func TestEncodePasswordHash_RandomSalt(t *testing.T) {
+	passwordHash1, err := EncodePasswordHash("foobar", testArgon2idPasswordHashOptions)
+	assert.Nil(t, err)
+
+	passwordHash2, err := EncodePasswordHash("foobar", testArgon2idPasswordHashOptions)
+	assert.Nil(t, err)
+
+	assert.NotEqual(t, passwordHash1, passwordHash2)
+}
+
+func TestEncodePasswordHash_InvalidOptions(t *testing.T) {
+	_, err := EncodePasswordHash("foobar", &PasswordHashOptions{Algorithm: "md5"})
+	assert.NotNil(t, err)
+
+	_, err = EncodePasswordHash("foobar", &PasswordHashOptions{Algorithm: PASSWORD_HASH_ALGORITHM_ARGON2ID})
+	assert.NotNil(t, err)
+
+	_, err = EncodePasswordHash("foobar", &PasswordHashOptions{Algorithm: PASSWORD_HASH_ALGORITHM_PBKDF2_SHA256})
+	assert.NotNil(t, err)
+}
+
+func TestVerifyPasswordHash_LegacyPasswordHash(t *testing.T) {
+	passwordHash := "QrpKShMygoe4Ym4ibnA7cNDzCcSonBkgFl69IrtnDmp3oROft3/Td/DNXjsweosa"
+
+	assert.True(t, VerifyPasswordHash("foobar", passwordHash, "salt"))
+	assert.False(t, VerifyPasswordHash("foobar", passwordHash, "salt2"))
+	assert.False(t, VerifyPasswordHash("foobaz", passwordHash, "salt"))
+}
+
+func TestVerifyPasswordHash_InvalidPasswordHash(t *testing.T) {
+	assert.False(t, VerifyPasswordHash("foobar", "", ""))
+	assert.False(t, VerifyPasswordHash("foobar", "$", ""))
+	assert.False(t, VerifyPasswordHash("foobar", "$md5$foobar", ""))
+	assert.False(t, VerifyPasswordHash("foobar", "$argon2id$v=19$m=1024,t=1,p=1$c2FsdA", ""))
+	assert.False(t, VerifyPasswordHash("foobar", "$argon2id$v=16$m=1024,t=1,p=1$c2FsdA$c2FsdA", ""))
+	assert.False(t, VerifyPasswordHash("foobar", "$argon2id$v=19$m=1024,t=1,p=1$c2FsdA$c2FsdA", ""))
+	assert.False(t, VerifyPasswordHash("foobar", "$pbkdf2-sha256$i=0$c2FsdA$c2FsdA", ""))
+}
+
+func TestIsPasswordHashOutdated(t *testing.T) {
+	assert.True(t, IsPasswordHashOutdated("QrpKShMygoe4Ym4ibnA7cNDzCcSonBkgFl69IrtnDmp3oROft3/Td/DNXjsweosa", testArgon2idPasswordHashOptions))
+
+	passwordHash, err := EncodePasswordHash("foobar", testArgon2idPasswordHashOptions)
+	assert.Nil(t, err)
+	assert.False(t, IsPasswordHashOutdated(passwordHash, testArgon2idPasswordHashOptions))
+	assert.True(t, IsPasswordHashOutdated(passwordHash, testPbkdf2PasswordHashOptions))
+	assert.True(t, IsPasswordHashOutdated(passwordHash, &PasswordHashOptions{
+		Algorithm:         PASSWORD_HASH_ALGORITHM_ARGON2ID,
+		Argon2Memory:      2048,
+		Argon2Iterations:  1,
+		Argon2Parallelism: 1,
+	}))
+
+	passwordHash, err = EncodePasswordHash("foobar", testPbkdf2PasswordHashOptions)
+	assert.Nil(t, err)
+	assert.False(t, IsPasswordHashOutdated(passwordHash, testPbkdf2PasswordHashOptions))
+	assert.True(t, IsPasswordHashOutdated(passwordHash, testArgon2idPasswordHashOptions))
+	assert.True(t, IsPasswordHashOutdated(passwordHash, &PasswordHashOptions{
+		Algorithm:        PASSWORD_HASH_ALGORITHM_PBKDF2_SHA256,
+		Pbkdf2Iterations: 2000,
+	}))
+}
@@ -112,3 +112,37 @@ func TestIsValidHexRGBColor_InvalidHexRGBColor(t *testing.T) {
 	actualValue = IsValidHexRGBColor(color)
 	assert.Equal(t, expectedValue, actualValue)
 }
+
+func TestIsValidCommodityCode_ValidCommodityCode(t *testing.T) {
+	code := "BTC"
+	expectedValue := true
+	actualValue := IsValidCommodityCode(code)
+	assert.Equal(t, expectedValue, actualValue)
+
+	code = "XAUG1"
+	expectedValue = true
+	actualValue = IsValidCommodityCode(code)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestIsValidCommodityCode_InvalidCommodityCode(t *testing.T) {
+	code := "B"
+	expectedValue := false
+	actualValue := IsValidCommodityCode(code)
+	assert.Equal(t, expectedValue, actualValue)
+
+	code = "btc"
+	expectedValue = false
+	actualValue = IsValidCommodityCode(code)
+	assert.Equal(t, expectedValue, actualValue)
+
+	code = "1INCH"
+	expectedValue = false
+	actualValue = IsValidCommodityCode(code)
+	assert.Equal(t, expectedValue, actualValue)
+
+	code = "MILESMILESM"
+	expectedValue = false
+	actualValue = IsValidCommodityCode(code)
+	assert.Equal(t, expectedValue, actualValue)
+}
@@ -5,11 +5,14 @@ type UuidType uint8
 
 // Types of uuid
 const (
-	UUID_TYPE_DEFAULT     UuidType = 0
-	UUID_TYPE_USER        UuidType = 1
-	UUID_TYPE_ACCOUNT     UuidType = 2
-	UUID_TYPE_TRANSACTION UuidType = 3
-	UUID_TYPE_CATEGORY    UuidType = 4
-	UUID_TYPE_TAG         UuidType = 5
-	UUID_TYPE_TAG_INDEX   UuidType = 6
+	UUID_TYPE_DEFAULT         UuidType = 0
+	UUID_TYPE_USER            UuidType = 1
+	UUID_TYPE_ACCOUNT         UuidType = 2
+	UUID_TYPE_TRANSACTION     UuidType = 3
+	UUID_TYPE_CATEGORY        UuidType = 4
+	UUID_TYPE_TAG             UuidType = 5
+	UUID_TYPE_TAG_INDEX       UuidType = 6
+	UUID_TYPE_COMMODITY       UuidType = 7
+	UUID_TYPE_AUDIT_EVENT     UuidType = 8
+	UUID_TYPE_INVITATION_CODE UuidType = 9
 )
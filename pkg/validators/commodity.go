@@ -0,0 +1,38 @@
+package validators
+
+import (
+	"github.com/go-playground/validator/v10"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/utils"
+)
+
+// ValidCommodityCode returns whether the given commodity code is valid and not conflicts with any currency
+func ValidCommodityCode(fl validator.FieldLevel) bool {
+	if value, ok := fl.Field().Interface().(string); ok {
+		if _, exists := AllCurrencyNames[value]; exists {
+			return false
+		}
+
+		return utils.IsValidCommodityCode(value)
+	}
+
+	return false
+}
+
+// ValidCurrencyOrCommodityCode returns whether the given value is a valid currency or a valid commodity code,
+// the commodity code still needs to be checked whether it is defined by user
+func ValidCurrencyOrCommodityCode(fl validator.FieldLevel) bool {
+	if value, ok := fl.Field().Interface().(string); ok {
+		if value == ParentAccountCurrencyPlaceholder {
+			return true
+		}
+
+		if _, exists := AllCurrencyNames[value]; exists {
+			return true
+		}
+
+		return utils.IsValidCommodityCode(value)
+	}
+
+	return false
+}
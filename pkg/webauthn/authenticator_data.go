@@ -0,0 +1,100 @@
+package webauthn
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// Flags of authenticator data
+const (
+	authenticatorDataFlagUserPresent            byte = 0x01
+	authenticatorDataFlagUserVerified           byte = 0x04
+	authenticatorDataFlagAttestedCredentialData byte = 0x40
+	authenticatorDataFlagExtensionData          byte = 0x80
+)
+
+const (
+	authenticatorDataMinLength = 37
+	aaguidLength               = 16
+	maxCredentialIdLength      = 1023
+)
+
+var errAuthenticatorDataInvalid = errors.New("authenticator data is invalid")
+
+type attestedCredentialData struct {
+	aaguid       []byte
+	credentialId []byte
+	publicKey    []byte
+	coseKey      *coseKey
+}
+
+type authenticatorData struct {
+	rpIdHash               []byte
+	flags                  byte
+	signCount              uint32
+	attestedCredentialData *attestedCredentialData
+}
+
+// parseAuthenticatorData returns the parsed authenticator data, the extension data is skipped
+func parseAuthenticatorData(data []byte) (*authenticatorData, error) {
+	if len(data) < authenticatorDataMinLength {
+		return nil, errAuthenticatorDataInvalid
+	}
+
+	authData := &authenticatorData{
+		rpIdHash:  data[0:32],
+		flags:     data[32],
+		signCount: binary.BigEndian.Uint32(data[33:37]),
+	}
+
+	remain := data[authenticatorDataMinLength:]
+
+	if authData.hasFlag(authenticatorDataFlagAttestedCredentialData) {
+		if len(remain) < aaguidLength+2 {
+			return nil, errAuthenticatorDataInvalid
+		}
+
+		credentialIdLength := int(binary.BigEndian.Uint16(remain[aaguidLength : aaguidLength+2]))
+
+		if credentialIdLength < 1 || credentialIdLength > maxCredentialIdLength || len(remain) < aaguidLength+2+credentialIdLength {
+			return nil, errAuthenticatorDataInvalid
+		}
+
+		credentialData := &attestedCredentialData{
+			aaguid:       remain[0:aaguidLength],
+			credentialId: remain[aaguidLength+2 : aaguidLength+2+credentialIdLength],
+		}
+
+		remain = remain[aaguidLength+2+credentialIdLength:]
+		key, keyLength, err := parseCOSEKey(remain)
+
+		if err != nil {
+			return nil, err
+		}
+
+		credentialData.publicKey = remain[0:keyLength]
+		credentialData.coseKey = key
+		authData.attestedCredentialData = credentialData
+		remain = remain[keyLength:]
+	}
+
+	if authData.hasFlag(authenticatorDataFlagExtensionData) {
+		_, extensionsLength, err := decodeCbor(remain)
+
+		if err != nil {
+			return nil, err
+		}
+
+		remain = remain[extensionsLength:]
+	}
+
+	if len(remain) > 0 {
+		return nil, errAuthenticatorDataInvalid
+	}
+
+	return authData, nil
+}
+
+func (d *authenticatorData) hasFlag(flag byte) bool {
+	return d.flags&flag == flag
+}
@@ -0,0 +1,231 @@
+package webauthn
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// CBOR major types
+const (
+	cborMajorTypeUnsignedInt byte = 0
+	cborMajorTypeNegativeInt byte = 1
+	cborMajorTypeByteString  byte = 2
+	cborMajorTypeTextString  byte = 3
+	cborMajorTypeArray       byte = 4
+	cborMajorTypeMap         byte = 5
+	cborMajorTypeTag         byte = 6
+	cborMajorTypeSimple      byte = 7
+)
+
+const cborMaxNestingDepth = 16
+
+var errCborDataInvalid = errors.New("cbor data is invalid")
+
+// decodeCbor returns the first data item in the CBOR encoded data (RFC 8949) and the count of bytes it takes,
+// the integer is decoded to int64, the map is decoded to map[any]any whose keys are int64 or string,
+// indefinite length items are not supported as authenticators must use the CTAP2 canonical CBOR encoding
+func decodeCbor(data []byte) (any, int, error) {
+	decoder := &cborDecoder{
+		data: data,
+	}
+
+	value, err := decoder.decodeItem(0)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return value, decoder.pos, nil
+}
+
+type cborDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *cborDecoder) decodeItem(depth int) (any, error) {
+	if depth > cborMaxNestingDepth {
+		return nil, errCborDataInvalid
+	}
+
+	if d.pos >= len(d.data) {
+		return nil, errCborDataInvalid
+	}
+
+	initialByte := d.data[d.pos]
+	d.pos++
+
+	majorType := initialByte >> 5
+	additionalInfo := initialByte & 0x1f
+
+	if majorType == cborMajorTypeSimple {
+		return d.decodeSimpleValue(additionalInfo)
+	}
+
+	argument, err := d.readArgument(additionalInfo)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch majorType {
+	case cborMajorTypeUnsignedInt:
+		if argument > math.MaxInt64 {
+			return nil, errCborDataInvalid
+		}
+
+		return int64(argument), nil
+	case cborMajorTypeNegativeInt:
+		if argument > math.MaxInt64 {
+			return nil, errCborDataInvalid
+		}
+
+		return -1 - int64(argument), nil
+	case cborMajorTypeByteString:
+		return d.readBytes(argument)
+	case cborMajorTypeTextString:
+		value, err := d.readBytes(argument)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return string(value), nil
+	case cborMajorTypeArray:
+		if argument > uint64(len(d.data)-d.pos) {
+			return nil, errCborDataInvalid
+		}
+
+		items := make([]any, 0, argument)
+
+		for i := uint64(0); i < argument; i++ {
+			item, err := d.decodeItem(depth + 1)
+
+			if err != nil {
+				return nil, err
+			}
+
+			items = append(items, item)
+		}
+
+		return items, nil
+	case cborMajorTypeMap:
+		if argument > uint64(len(d.data)-d.pos) {
+			return nil, errCborDataInvalid
+		}
+
+		items := make(map[any]any, argument)
+
+		for i := uint64(0); i < argument; i++ {
+			key, err := d.decodeItem(depth + 1)
+
+			if err != nil {
+				return nil, err
+			}
+
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, errCborDataInvalid
+			}
+
+			if _, exists := items[key]; exists {
+				return nil, errCborDataInvalid
+			}
+
+			value, err := d.decodeItem(depth + 1)
+
+			if err != nil {
+				return nil, err
+			}
+
+			items[key] = value
+		}
+
+		return items, nil
+	case cborMajorTypeTag:
+		// tags are ignored and the tagged item is returned
+		return d.decodeItem(depth + 1)
+	}
+
+	return nil, errCborDataInvalid
+}
+
+func (d *cborDecoder) readArgument(additionalInfo byte) (uint64, error) {
+	if additionalInfo < 24 {
+		return uint64(additionalInfo), nil
+	}
+
+	var length int
+
+	switch additionalInfo {
+	case 24:
+		length = 1
+	case 25:
+		length = 2
+	case 26:
+		length = 4
+	case 27:
+		length = 8
+	default:
+		return 0, errCborDataInvalid
+	}
+
+	value, err := d.readBytes(uint64(length))
+
+	if err != nil {
+		return 0, err
+	}
+
+	switch length {
+	case 1:
+		return uint64(value[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(value)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(value)), nil
+	default:
+		return binary.BigEndian.Uint64(value), nil
+	}
+}
+
+func (d *cborDecoder) decodeSimpleValue(additionalInfo byte) (any, error) {
+	switch additionalInfo {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22, 23:
+		return nil, nil
+	case 26:
+		value, err := d.readArgument(additionalInfo)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return float64(math.Float32frombits(uint32(value))), nil
+	case 27:
+		value, err := d.readArgument(additionalInfo)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return math.Float64frombits(value), nil
+	}
+
+	return nil, errCborDataInvalid
+}
+
+func (d *cborDecoder) readBytes(length uint64) ([]byte, error) {
+	if length > uint64(len(d.data)-d.pos) {
+		return nil, errCborDataInvalid
+	}
+
+	value := d.data[d.pos : d.pos+int(length)]
+	d.pos += int(length)
+
+	return value, nil
+}
@@ -0,0 +1,81 @@
+package webauthn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeCbor(t *testing.T) {
+	value, length, err := decodeCbor([]byte{0x18, 0x64})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(100), value)
+	assert.Equal(t, 2, length)
+
+	value, _, err = decodeCbor([]byte{0x39, 0x01, 0x00})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(-257), value)
+
+	value, _, err = decodeCbor([]byte{0x43, 0x01, 0x02, 0x03})
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x01, 0x02, 0x03}, value)
+
+	value, _, err = decodeCbor([]byte{0x82, 0x63, 'f', 'm', 't', 0xf5})
+	assert.Nil(t, err)
+	assert.Equal(t, []any{"fmt", true}, value)
+
+	value, length, err = decodeCbor([]byte{0xa2, 0x01, 0x02, 0x20, 0xf6, 0xff})
+	assert.Nil(t, err)
+	assert.Equal(t, map[any]any{int64(1): int64(2), int64(-1): nil}, value)
+	assert.Equal(t, 5, length)
+
+	value, _, err = decodeCbor([]byte{0xc0, 0x61, 'a'})
+	assert.Nil(t, err)
+	assert.Equal(t, "a", value)
+}
+
+func TestDecodeCbor_InvalidData(t *testing.T) {
+	invalidData := [][]byte{
+		{},
+		{0x18},
+		{0x43, 0x01, 0x02},
+		{0x5f, 0x41, 0x01, 0xff},
+		{0x82, 0x01},
+		{0xa1, 0x41, 0x01, 0x02},
+		{0xa2, 0x01, 0x02, 0x01, 0x03},
+		{0x1b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		{0x9b, 0x00, 0x00, 0x00, 0x00, 0xff, 0xff, 0xff, 0xff},
+	}
+
+	for i := 0; i < len(invalidData); i++ {
+		_, _, err := decodeCbor(invalidData[i])
+		assert.NotNil(t, err, "data %x should be invalid", invalidData[i])
+	}
+
+	nestedData := make([]byte, cborMaxNestingDepth+2)
+
+	for i := 0; i < len(nestedData)-1; i++ {
+		nestedData[i] = 0x81
+	}
+
+	_, _, err := decodeCbor(nestedData)
+	assert.NotNil(t, err)
+}
+
+func TestParseCOSEKey_UnsupportedAlgorithm(t *testing.T) {
+	_, _, err := parseCOSEKey(encodeCbor(map[any]any{
+		coseKeyParameterKty: coseKeyTypeEC2,
+		coseKeyParameterAlg: int64(-35),
+		coseKeyParameterCrv: int64(2),
+	}))
+	assert.Equal(t, errCOSEKeyAlgorithmNotSupported, err)
+
+	_, _, err = parseCOSEKey(encodeCbor(map[any]any{
+		coseKeyParameterKty: coseKeyTypeEC2,
+		coseKeyParameterAlg: COSEAlgorithmES256,
+		coseKeyParameterCrv: coseCurveP256,
+		coseKeyParameterX:   make([]byte, 32),
+		coseKeyParameterY:   make([]byte, 32),
+	}))
+	assert.Equal(t, errCOSEKeyInvalid, err)
+}
@@ -0,0 +1,174 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+)
+
+// COSE algorithm identifiers which are supported
+const (
+	COSEAlgorithmES256 int64 = -7
+	COSEAlgorithmEdDSA int64 = -8
+	COSEAlgorithmRS256 int64 = -257
+)
+
+// SupportedCOSEAlgorithms represents all COSE algorithms which can be used by authenticators, in order of preference
+var SupportedCOSEAlgorithms = []int64{
+	COSEAlgorithmES256,
+	COSEAlgorithmEdDSA,
+	COSEAlgorithmRS256,
+}
+
+// COSE key parameters (RFC 9053)
+const (
+	coseKeyParameterKty int64 = 1
+	coseKeyParameterAlg int64 = 3
+	coseKeyParameterCrv int64 = -1
+	coseKeyParameterX   int64 = -2
+	coseKeyParameterY   int64 = -3
+	coseKeyParameterN   int64 = -1
+	coseKeyParameterE   int64 = -2
+)
+
+// COSE key types and curves
+const (
+	coseKeyTypeOKP     int64 = 1
+	coseKeyTypeEC2     int64 = 2
+	coseKeyTypeRSA     int64 = 3
+	coseCurveP256      int64 = 1
+	coseCurveEd25519   int64 = 6
+	minRSAKeyBitLength       = 2048
+)
+
+var errCOSEKeyInvalid = errors.New("cose key is invalid")
+var errCOSEKeyAlgorithmNotSupported = errors.New("cose key algorithm is not supported")
+var errSignatureInvalid = errors.New("signature is invalid")
+
+type coseKey struct {
+	algorithm int64
+	publicKey crypto.PublicKey
+}
+
+// parseCOSEKey returns the public key of the CBOR encoded COSE key and the count of bytes it takes
+func parseCOSEKey(data []byte) (*coseKey, int, error) {
+	value, length, err := decodeCbor(data)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	parameters, ok := value.(map[any]any)
+
+	if !ok {
+		return nil, 0, errCOSEKeyInvalid
+	}
+
+	keyType, _ := parameters[coseKeyParameterKty].(int64)
+	algorithm, _ := parameters[coseKeyParameterAlg].(int64)
+
+	key := &coseKey{
+		algorithm: algorithm,
+	}
+
+	switch {
+	case keyType == coseKeyTypeEC2 && algorithm == COSEAlgorithmES256:
+		key.publicKey, err = parseEC2PublicKey(parameters)
+	case keyType == coseKeyTypeOKP && algorithm == COSEAlgorithmEdDSA:
+		key.publicKey, err = parseOKPPublicKey(parameters)
+	case keyType == coseKeyTypeRSA && algorithm == COSEAlgorithmRS256:
+		key.publicKey, err = parseRSAPublicKey(parameters)
+	default:
+		return nil, 0, errCOSEKeyAlgorithmNotSupported
+	}
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return key, length, nil
+}
+
+// verify returns nil if the signature of the data is valid
+func (k *coseKey) verify(data []byte, signature []byte) error {
+	valid := false
+
+	switch k.algorithm {
+	case COSEAlgorithmES256:
+		hashed := sha256.Sum256(data)
+		valid = ecdsa.VerifyASN1(k.publicKey.(*ecdsa.PublicKey), hashed[:], signature)
+	case COSEAlgorithmEdDSA:
+		valid = ed25519.Verify(k.publicKey.(ed25519.PublicKey), data, signature)
+	case COSEAlgorithmRS256:
+		hashed := sha256.Sum256(data)
+		valid = rsa.VerifyPKCS1v15(k.publicKey.(*rsa.PublicKey), crypto.SHA256, hashed[:], signature) == nil
+	}
+
+	if !valid {
+		return errSignatureInvalid
+	}
+
+	return nil
+}
+
+func parseEC2PublicKey(parameters map[any]any) (*ecdsa.PublicKey, error) {
+	curve, _ := parameters[coseKeyParameterCrv].(int64)
+	x, _ := parameters[coseKeyParameterX].([]byte)
+	y, _ := parameters[coseKeyParameterY].([]byte)
+
+	if curve != coseCurveP256 || len(x) != 32 || len(y) != 32 {
+		return nil, errCOSEKeyInvalid
+	}
+
+	// make sure the point is on the curve
+	point := append([]byte{0x04}, x...)
+	point = append(point, y...)
+
+	if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+		return nil, errCOSEKeyInvalid
+	}
+
+	return &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(x),
+		Y:     new(big.Int).SetBytes(y),
+	}, nil
+}
+
+func parseOKPPublicKey(parameters map[any]any) (ed25519.PublicKey, error) {
+	curve, _ := parameters[coseKeyParameterCrv].(int64)
+	x, _ := parameters[coseKeyParameterX].([]byte)
+
+	if curve != coseCurveEd25519 || len(x) != ed25519.PublicKeySize {
+		return nil, errCOSEKeyInvalid
+	}
+
+	return ed25519.PublicKey(x), nil
+}
+
+func parseRSAPublicKey(parameters map[any]any) (*rsa.PublicKey, error) {
+	n, _ := parameters[coseKeyParameterN].([]byte)
+	e, _ := parameters[coseKeyParameterE].([]byte)
+
+	if len(e) < 1 || len(e) > 4 {
+		return nil, errCOSEKeyInvalid
+	}
+
+	modulus := new(big.Int).SetBytes(n)
+	exponent := new(big.Int).SetBytes(e)
+
+	if modulus.BitLen() < minRSAKeyBitLength || exponent.Int64() < 3 || exponent.Bit(0) == 0 {
+		return nil, errCOSEKeyInvalid
+	}
+
+	return &rsa.PublicKey{
+		N: modulus,
+		E: int(exponent.Int64()),
+	}, nil
+}
@@ -0,0 +1,471 @@
+package webauthn
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/settings"
+)
+
+// PublicKeyCredentialType represents the only credential type of WebAuthn
+const PublicKeyCredentialType = "public-key"
+
+const (
+	challengeLength = 32
+	ceremonyTimeout = uint64(SessionValidDuration / time.Millisecond)
+)
+
+// Client data types
+const (
+	clientDataTypeCreate = "webauthn.create"
+	clientDataTypeGet    = "webauthn.get"
+)
+
+// User verification requirements
+const (
+	UserVerificationRequired  = "required"
+	UserVerificationPreferred = "preferred"
+)
+
+var (
+	errClientDataInvalid     = errors.New("client data is invalid")
+	errCredentialTypeInvalid = errors.New("credential type is invalid")
+	errCredentialIdMismatch  = errors.New("credential id mismatch")
+	errUserHandleMismatch    = errors.New("user handle mismatch")
+	errChallengeMismatch     = errors.New("challenge mismatch")
+	errOriginNotAllowed      = errors.New("origin is not allowed")
+	errRPIdHashMismatch      = errors.New("relying party id hash mismatch")
+	errUserNotPresent        = errors.New("user is not present")
+	errUserNotVerified       = errors.New("user is not verified")
+	errSignCountInvalid      = errors.New("signature counter does not increase, the authenticator may be cloned")
+)
+
+// RelyingPartyEntity represents the relying party in credential creation options
+type RelyingPartyEntity struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// UserEntity represents the user account in credential creation options, the id is base64url encoded user handle
+type UserEntity struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+// CredentialParameter represents the type and the algorithm of the credential to be created
+type CredentialParameter struct {
+	Type      string `json:"type"`
+	Algorithm int64  `json:"alg"`
+}
+
+// CredentialDescriptor represents an existing credential, the id is base64url encoded credential id
+type CredentialDescriptor struct {
+	Type       string   `json:"type"`
+	Id         string   `json:"id"`
+	Transports []string `json:"transports,omitempty"`
+}
+
+// AuthenticatorSelection represents the requirements of authenticator
+type AuthenticatorSelection struct {
+	ResidentKey        string `json:"residentKey,omitempty"`
+	RequireResidentKey bool   `json:"requireResidentKey"`
+	UserVerification   string `json:"userVerification,omitempty"`
+}
+
+// CredentialCreationOptions represents the options which are passed to navigator.credentials.create() in JSON form
+type CredentialCreationOptions struct {
+	RelyingParty           *RelyingPartyEntity     `json:"rp"`
+	User                   *UserEntity             `json:"user"`
+	Challenge              string                  `json:"challenge"`
+	PubKeyCredParams       []CredentialParameter   `json:"pubKeyCredParams"`
+	Timeout                uint64                  `json:"timeout"`
+	ExcludeCredentials     []CredentialDescriptor  `json:"excludeCredentials"`
+	AuthenticatorSelection *AuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                  `json:"attestation"`
+}
+
+// CredentialRequestOptions represents the options which are passed to navigator.credentials.get() in JSON form
+type CredentialRequestOptions struct {
+	Challenge        string                 `json:"challenge"`
+	Timeout          uint64                 `json:"timeout"`
+	RelyingPartyId   string                 `json:"rpId"`
+	AllowCredentials []CredentialDescriptor `json:"allowCredentials"`
+	UserVerification string                 `json:"userVerification"`
+}
+
+// AuthenticatorAttestationResponse represents the response of navigator.credentials.create() in JSON form
+type AuthenticatorAttestationResponse struct {
+	ClientDataJSON    string   `json:"clientDataJSON" binding:"required"`
+	AttestationObject string   `json:"attestationObject" binding:"required"`
+	Transports        []string `json:"transports"`
+}
+
+// CredentialCreationResponse represents the created credential in JSON form
+type CredentialCreationResponse struct {
+	Id       string                            `json:"id" binding:"required"`
+	Type     string                            `json:"type" binding:"required"`
+	Response *AuthenticatorAttestationResponse `json:"response" binding:"required"`
+}
+
+// AuthenticatorAssertionResponse represents the response of navigator.credentials.get() in JSON form
+type AuthenticatorAssertionResponse struct {
+	ClientDataJSON    string `json:"clientDataJSON" binding:"required"`
+	AuthenticatorData string `json:"authenticatorData" binding:"required"`
+	Signature         string `json:"signature" binding:"required"`
+	UserHandle        string `json:"userHandle"`
+}
+
+// CredentialAssertionResponse represents the assertion of credential in JSON form
+type CredentialAssertionResponse struct {
+	Id       string                          `json:"id" binding:"required"`
+	Type     string                          `json:"type" binding:"required"`
+	Response *AuthenticatorAssertionResponse `json:"response" binding:"required"`
+}
+
+// Credential represents a registered public key credential
+type Credential struct {
+	Id         []byte
+	PublicKey  []byte
+	SignCount  uint32
+	Transports []string
+}
+
+type clientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin"`
+}
+
+// RelyingParty represents the WebAuthn relying party which verifies registrations and assertions of credentials,
+// the attestation statement is not verified as attestation is not requested
+type RelyingParty struct {
+	id       string
+	name     string
+	origins  []string
+	rpIdHash [32]byte
+}
+
+// NewRelyingParty returns a new WebAuthn relying party according to the config
+func NewRelyingParty(config *settings.Config) *RelyingParty {
+	return &RelyingParty{
+		id:       config.WebAuthnRPId,
+		name:     config.AppName,
+		origins:  config.WebAuthnRPOrigins,
+		rpIdHash: sha256.Sum256([]byte(config.WebAuthnRPId)),
+	}
+}
+
+// BeginRegistration returns the credential creation options and the session of a new registration ceremony,
+// the user id is used as user handle and must not contain personally identifying information
+func (rp *RelyingParty) BeginRegistration(userId string, userName string, userDisplayName string, excludeCredentials []CredentialDescriptor) (*CredentialCreationOptions, *Session, error) {
+	challenge, err := generateChallenge()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pubKeyCredParams := make([]CredentialParameter, len(SupportedCOSEAlgorithms))
+
+	for i := 0; i < len(SupportedCOSEAlgorithms); i++ {
+		pubKeyCredParams[i] = CredentialParameter{
+			Type:      PublicKeyCredentialType,
+			Algorithm: SupportedCOSEAlgorithms[i],
+		}
+	}
+
+	options := &CredentialCreationOptions{
+		RelyingParty: &RelyingPartyEntity{
+			Id:   rp.id,
+			Name: rp.name,
+		},
+		User: &UserEntity{
+			Id:          EncodeBase64Url([]byte(userId)),
+			Name:        userName,
+			DisplayName: userDisplayName,
+		},
+		Challenge:          challenge,
+		PubKeyCredParams:   pubKeyCredParams,
+		Timeout:            ceremonyTimeout,
+		ExcludeCredentials: excludeCredentials,
+		AuthenticatorSelection: &AuthenticatorSelection{
+			ResidentKey:        "preferred",
+			RequireResidentKey: false,
+			UserVerification:   UserVerificationPreferred,
+		},
+		Attestation: "none",
+	}
+
+	session := &Session{
+		Type:      SESSION_TYPE_REGISTRATION,
+		Challenge: challenge,
+		UserId:    userId,
+	}
+
+	return options, session, nil
+}
+
+// FinishRegistration returns the new credential if the response of authenticator is valid for the session
+func (rp *RelyingParty) FinishRegistration(session *Session, response *CredentialCreationResponse) (*Credential, error) {
+	if response.Type != PublicKeyCredentialType {
+		return nil, errCredentialTypeInvalid
+	}
+
+	clientDataJSON, err := DecodeBase64Url(response.Response.ClientDataJSON)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = rp.verifyClientData(clientDataJSON, clientDataTypeCreate, session)
+
+	if err != nil {
+		return nil, err
+	}
+
+	attestationObjectData, err := DecodeBase64Url(response.Response.AttestationObject)
+
+	if err != nil {
+		return nil, err
+	}
+
+	attestationObject, _, err := decodeCbor(attestationObjectData)
+
+	if err != nil {
+		return nil, err
+	}
+
+	attestationObjectItems, ok := attestationObject.(map[any]any)
+
+	if !ok {
+		return nil, errAuthenticatorDataInvalid
+	}
+
+	authDataBytes, ok := attestationObjectItems["authData"].([]byte)
+
+	if !ok {
+		return nil, errAuthenticatorDataInvalid
+	}
+
+	authData, err := rp.verifyAuthenticatorData(authDataBytes, session)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if authData.attestedCredentialData == nil {
+		return nil, errAuthenticatorDataInvalid
+	}
+
+	credentialId, err := DecodeBase64Url(response.Id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(credentialId, authData.attestedCredentialData.credentialId) {
+		return nil, errCredentialIdMismatch
+	}
+
+	credential := &Credential{
+		Id:         authData.attestedCredentialData.credentialId,
+		PublicKey:  authData.attestedCredentialData.publicKey,
+		SignCount:  authData.signCount,
+		Transports: response.Response.Transports,
+	}
+
+	return credential, nil
+}
+
+// BeginLogin returns the credential request options and the session of a new authentication ceremony,
+// the allowed credentials should be empty when the user is not identified yet (login by discoverable credential)
+func (rp *RelyingParty) BeginLogin(userId string, allowCredentials []CredentialDescriptor, userVerificationRequire bool) (*CredentialRequestOptions, *Session, error) {
+	challenge, err := generateChallenge()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	userVerification := UserVerificationPreferred
+
+	if userVerificationRequire {
+		userVerification = UserVerificationRequired
+	}
+
+	options := &CredentialRequestOptions{
+		Challenge:        challenge,
+		Timeout:          ceremonyTimeout,
+		RelyingPartyId:   rp.id,
+		AllowCredentials: allowCredentials,
+		UserVerification: userVerification,
+	}
+
+	session := &Session{
+		Type:                    SESSION_TYPE_AUTHENTICATION,
+		Challenge:               challenge,
+		UserId:                  userId,
+		UserVerificationRequire: userVerificationRequire,
+	}
+
+	return options, session, nil
+}
+
+// FinishLogin returns the new signature counter if the assertion of the stored credential is valid for the session,
+// the user handle must be equal to the user id if it is returned by authenticator
+func (rp *RelyingParty) FinishLogin(session *Session, response *CredentialAssertionResponse, credential *Credential, userId string) (uint32, error) {
+	if response.Type != PublicKeyCredentialType {
+		return 0, errCredentialTypeInvalid
+	}
+
+	credentialId, err := DecodeBase64Url(response.Id)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if !bytes.Equal(credentialId, credential.Id) {
+		return 0, errCredentialIdMismatch
+	}
+
+	if response.Response.UserHandle != "" {
+		userHandle, err := DecodeBase64Url(response.Response.UserHandle)
+
+		if err != nil {
+			return 0, err
+		}
+
+		if string(userHandle) != userId {
+			return 0, errUserHandleMismatch
+		}
+	}
+
+	clientDataJSON, err := DecodeBase64Url(response.Response.ClientDataJSON)
+
+	if err != nil {
+		return 0, err
+	}
+
+	err = rp.verifyClientData(clientDataJSON, clientDataTypeGet, session)
+
+	if err != nil {
+		return 0, err
+	}
+
+	authDataBytes, err := DecodeBase64Url(response.Response.AuthenticatorData)
+
+	if err != nil {
+		return 0, err
+	}
+
+	authData, err := rp.verifyAuthenticatorData(authDataBytes, session)
+
+	if err != nil {
+		return 0, err
+	}
+
+	signature, err := DecodeBase64Url(response.Response.Signature)
+
+	if err != nil {
+		return 0, err
+	}
+
+	key, _, err := parseCOSEKey(credential.PublicKey)
+
+	if err != nil {
+		return 0, err
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signedData := make([]byte, 0, len(authDataBytes)+len(clientDataHash))
+	signedData = append(signedData, authDataBytes...)
+	signedData = append(signedData, clientDataHash[:]...)
+
+	err = key.verify(signedData, signature)
+
+	if err != nil {
+		return 0, err
+	}
+
+	// authenticators which do not support signature counter always return zero
+	if (authData.signCount != 0 || credential.SignCount != 0) && authData.signCount <= credential.SignCount {
+		return 0, errSignCountInvalid
+	}
+
+	return authData.signCount, nil
+}
+
+func (rp *RelyingParty) verifyClientData(clientDataJSON []byte, expectedType string, session *Session) error {
+	data := &clientData{}
+	err := json.Unmarshal(clientDataJSON, data)
+
+	if err != nil {
+		return errClientDataInvalid
+	}
+
+	if data.Type != expectedType || data.CrossOrigin {
+		return errClientDataInvalid
+	}
+
+	if subtle.ConstantTimeCompare([]byte(data.Challenge), []byte(session.Challenge)) != 1 {
+		return errChallengeMismatch
+	}
+
+	for i := 0; i < len(rp.origins); i++ {
+		if data.Origin == rp.origins[i] {
+			return nil
+		}
+	}
+
+	return errOriginNotAllowed
+}
+
+func (rp *RelyingParty) verifyAuthenticatorData(data []byte, session *Session) (*authenticatorData, error) {
+	authData, err := parseAuthenticatorData(data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare(authData.rpIdHash, rp.rpIdHash[:]) != 1 {
+		return nil, errRPIdHashMismatch
+	}
+
+	if !authData.hasFlag(authenticatorDataFlagUserPresent) {
+		return nil, errUserNotPresent
+	}
+
+	if session.UserVerificationRequire && !authData.hasFlag(authenticatorDataFlagUserVerified) {
+		return nil, errUserNotVerified
+	}
+
+	return authData, nil
+}
+
+// EncodeBase64Url returns the base64url encoded data without padding, which is used in JSON form of WebAuthn
+func EncodeBase64Url(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeBase64Url returns the data of the base64url encoded string, the padding is optional
+func DecodeBase64Url(data string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(data, "="))
+}
+
+func generateChallenge() (string, error) {
+	challenge := make([]byte, challengeLength)
+	_, err := rand.Read(challenge)
+
+	if err != nil {
+		return "", err
+	}
+
+	return EncodeBase64Url(challenge), nil
+}
@@ -0,0 +1,26 @@
+package webauthn
+
+import (
+	"github.com/kyy-me/ezbookkeeping/pkg/settings"
+)
+
+// RelyingPartyContainer contains the current WebAuthn relying party
+type RelyingPartyContainer struct {
+	Current *RelyingParty
+}
+
+// Initialize a WebAuthn relying party container singleton instance
+var (
+	Container = &RelyingPartyContainer{}
+)
+
+// InitializeRelyingParty initializes the current WebAuthn relying party according to the config
+func InitializeRelyingParty(config *settings.Config) error {
+	if !config.EnableWebAuthn {
+		Container.Current = nil
+		return nil
+	}
+
+	Container.Current = NewRelyingParty(config)
+	return nil
+}
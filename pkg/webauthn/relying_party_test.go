@@ -0,0 +1,350 @@
+package webauthn
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kyy-me/ezbookkeeping/pkg/settings"
+)
+
+const (
+	testRPId   = "ezbookkeeping.example.com"
+	testOrigin = "https://ezbookkeeping.example.com"
+	testUserId = "1234567890"
+)
+
+// encodeCbor returns the CBOR encoded data of int64, []byte, string, []any and map[any]any
+func encodeCbor(value any) []byte {
+	switch v := value.(type) {
+	case int:
+		return encodeCbor(int64(v))
+	case int64:
+		if v < 0 {
+			return encodeCborHead(cborMajorTypeNegativeInt, uint64(-1-v))
+		}
+
+		return encodeCborHead(cborMajorTypeUnsignedInt, uint64(v))
+	case []byte:
+		return append(encodeCborHead(cborMajorTypeByteString, uint64(len(v))), v...)
+	case string:
+		return append(encodeCborHead(cborMajorTypeTextString, uint64(len(v))), []byte(v)...)
+	case []any:
+		result := encodeCborHead(cborMajorTypeArray, uint64(len(v)))
+
+		for i := 0; i < len(v); i++ {
+			result = append(result, encodeCbor(v[i])...)
+		}
+
+		return result
+	case map[any]any:
+		encodedItems := make([][]byte, 0, len(v))
+
+		for key, item := range v {
+			encodedItems = append(encodedItems, append(encodeCbor(key), encodeCbor(item)...))
+		}
+
+		sort.Slice(encodedItems, func(i, j int) bool {
+			return string(encodedItems[i]) < string(encodedItems[j])
+		})
+
+		result := encodeCborHead(cborMajorTypeMap, uint64(len(v)))
+
+		for i := 0; i < len(encodedItems); i++ {
+			result = append(result, encodedItems[i]...)
+		}
+
+		return result
+	}
+
+	panic("unsupported type")
+}
+
+func encodeCborHead(majorType byte, argument uint64) []byte {
+	switch {
+	case argument < 24:
+		return []byte{majorType<<5 | byte(argument)}
+	case argument <= 0xff:
+		return []byte{majorType<<5 | 24, byte(argument)}
+	case argument <= 0xffff:
+		return binary.BigEndian.AppendUint16([]byte{majorType<<5 | 25}, uint16(argument))
+	case argument <= 0xffffffff:
+		return binary.BigEndian.AppendUint32([]byte{majorType<<5 | 26}, uint32(argument))
+	default:
+		return binary.BigEndian.AppendUint64([]byte{majorType<<5 | 27}, argument)
+	}
+}
+
+// softwareAuthenticator is an authenticator which keeps one ES256 or EdDSA credential in memory
+type softwareAuthenticator struct {
+	credentialId []byte
+	ecdsaKey     *ecdsa.PrivateKey
+	ed25519Key   ed25519.PrivateKey
+	signCount    uint32
+	rpId         string
+	origin       string
+	flags        byte
+}
+
+func newSoftwareAuthenticator(t *testing.T, algorithm int64) *softwareAuthenticator {
+	authenticator := &softwareAuthenticator{
+		credentialId: make([]byte, 16),
+		rpId:         testRPId,
+		origin:       testOrigin,
+		flags:        authenticatorDataFlagUserPresent | authenticatorDataFlagUserVerified,
+	}
+
+	_, err := rand.Read(authenticator.credentialId)
+	assert.Nil(t, err)
+
+	if algorithm == COSEAlgorithmEdDSA {
+		_, authenticator.ed25519Key, err = ed25519.GenerateKey(rand.Reader)
+	} else {
+		authenticator.ecdsaKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	}
+
+	assert.Nil(t, err)
+
+	return authenticator
+}
+
+func (a *softwareAuthenticator) coseKey() []byte {
+	if a.ed25519Key != nil {
+		return encodeCbor(map[any]any{
+			coseKeyParameterKty: coseKeyTypeOKP,
+			coseKeyParameterAlg: COSEAlgorithmEdDSA,
+			coseKeyParameterCrv: coseCurveEd25519,
+			coseKeyParameterX:   []byte(a.ed25519Key.Public().(ed25519.PublicKey)),
+		})
+	}
+
+	x := make([]byte, 32)
+	y := make([]byte, 32)
+	a.ecdsaKey.X.FillBytes(x)
+	a.ecdsaKey.Y.FillBytes(y)
+
+	return encodeCbor(map[any]any{
+		coseKeyParameterKty: coseKeyTypeEC2,
+		coseKeyParameterAlg: COSEAlgorithmES256,
+		coseKeyParameterCrv: coseCurveP256,
+		coseKeyParameterX:   x,
+		coseKeyParameterY:   y,
+	})
+}
+
+func (a *softwareAuthenticator) authenticatorData(flags byte) []byte {
+	rpIdHash := sha256.Sum256([]byte(a.rpId))
+	data := append(rpIdHash[:], flags)
+	data = binary.BigEndian.AppendUint32(data, a.signCount)
+
+	if flags&authenticatorDataFlagAttestedCredentialData != 0 {
+		data = append(data, make([]byte, aaguidLength)...)
+		data = binary.BigEndian.AppendUint16(data, uint16(len(a.credentialId)))
+		data = append(data, a.credentialId...)
+		data = append(data, a.coseKey()...)
+	}
+
+	return data
+}
+
+func (a *softwareAuthenticator) clientDataJSON(clientDataType string, challenge string) []byte {
+	data, _ := json.Marshal(&clientData{
+		Type:      clientDataType,
+		Challenge: challenge,
+		Origin:    a.origin,
+	})
+
+	return data
+}
+
+func (a *softwareAuthenticator) create(options *CredentialCreationOptions) *CredentialCreationResponse {
+	attestationObject := encodeCbor(map[any]any{
+		"fmt":      "none",
+		"attStmt":  map[any]any{},
+		"authData": a.authenticatorData(a.flags | authenticatorDataFlagAttestedCredentialData),
+	})
+
+	return &CredentialCreationResponse{
+		Id:   EncodeBase64Url(a.credentialId),
+		Type: PublicKeyCredentialType,
+		Response: &AuthenticatorAttestationResponse{
+			ClientDataJSON:    EncodeBase64Url(a.clientDataJSON(clientDataTypeCreate, options.Challenge)),
+			AttestationObject: EncodeBase64Url(attestationObject),
+			Transports:        []string{"usb"},
+		},
+	}
+}
+
+func (a *softwareAuthenticator) get(options *CredentialRequestOptions, userHandle string) *CredentialAssertionResponse {
+	a.signCount++
+	authData := a.authenticatorData(a.flags)
+	clientDataJSON := a.clientDataJSON(clientDataTypeGet, options.Challenge)
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signedData := append(append([]byte{}, authData...), clientDataHash[:]...)
+
+	var signature []byte
+
+	if a.ed25519Key != nil {
+		signature = ed25519.Sign(a.ed25519Key, signedData)
+	} else {
+		hashed := sha256.Sum256(signedData)
+		signature, _ = ecdsa.SignASN1(rand.Reader, a.ecdsaKey, hashed[:])
+	}
+
+	return &CredentialAssertionResponse{
+		Id:   EncodeBase64Url(a.credentialId),
+		Type: PublicKeyCredentialType,
+		Response: &AuthenticatorAssertionResponse{
+			ClientDataJSON:    EncodeBase64Url(clientDataJSON),
+			AuthenticatorData: EncodeBase64Url(authData),
+			Signature:         EncodeBase64Url(signature),
+			UserHandle:        EncodeBase64Url([]byte(userHandle)),
+		},
+	}
+}
+
+func newTestRelyingParty() *RelyingParty {
+	return NewRelyingParty(&settings.Config{
+		AppName:           "ezBookkeeping",
+		WebAuthnRPId:      testRPId,
+		WebAuthnRPOrigins: []string{testOrigin},
+	})
+}
+
+func registerTestCredential(t *testing.T, rp *RelyingParty, authenticator *softwareAuthenticator) *Credential {
+	options, session, err := rp.BeginRegistration(testUserId, "user", "User", nil)
+	assert.Nil(t, err)
+
+	credential, err := rp.FinishRegistration(session, authenticator.create(options))
+	assert.Nil(t, err)
+
+	return credential
+}
+
+func TestRelyingParty_RegisterAndLogin(t *testing.T) {
+	algorithms := []int64{COSEAlgorithmES256, COSEAlgorithmEdDSA}
+
+	for i := 0; i < len(algorithms); i++ {
+		rp := newTestRelyingParty()
+		authenticator := newSoftwareAuthenticator(t, algorithms[i])
+
+		creationOptions, _, err := rp.BeginRegistration(testUserId, "user", "User", nil)
+		assert.Nil(t, err)
+		assert.Equal(t, testRPId, creationOptions.RelyingParty.Id)
+		assert.Equal(t, EncodeBase64Url([]byte(testUserId)), creationOptions.User.Id)
+
+		credential := registerTestCredential(t, rp, authenticator)
+		assert.Equal(t, authenticator.credentialId, credential.Id)
+		assert.Equal(t, authenticator.coseKey(), credential.PublicKey)
+		assert.Equal(t, []string{"usb"}, credential.Transports)
+
+		requestOptions, session, err := rp.BeginLogin(testUserId, nil, true)
+		assert.Nil(t, err)
+		assert.Equal(t, UserVerificationRequired, requestOptions.UserVerification)
+
+		signCount, err := rp.FinishLogin(session, authenticator.get(requestOptions, testUserId), credential, testUserId)
+		assert.Nil(t, err)
+		assert.Equal(t, uint32(1), signCount)
+	}
+}
+
+func TestRelyingParty_FinishRegistrationWithInvalidResponse(t *testing.T) {
+	rp := newTestRelyingParty()
+
+	authenticator := newSoftwareAuthenticator(t, COSEAlgorithmES256)
+	authenticator.origin = "https://evil.example.com"
+	options, session, _ := rp.BeginRegistration(testUserId, "user", "User", nil)
+	_, err := rp.FinishRegistration(session, authenticator.create(options))
+	assert.Equal(t, errOriginNotAllowed, err)
+
+	authenticator = newSoftwareAuthenticator(t, COSEAlgorithmES256)
+	authenticator.rpId = "example.org"
+	options, session, _ = rp.BeginRegistration(testUserId, "user", "User", nil)
+	_, err = rp.FinishRegistration(session, authenticator.create(options))
+	assert.Equal(t, errRPIdHashMismatch, err)
+
+	authenticator = newSoftwareAuthenticator(t, COSEAlgorithmES256)
+	options, _, _ = rp.BeginRegistration(testUserId, "user", "User", nil)
+	_, anotherSession, _ := rp.BeginRegistration(testUserId, "user", "User", nil)
+	_, err = rp.FinishRegistration(anotherSession, authenticator.create(options))
+	assert.Equal(t, errChallengeMismatch, err)
+
+	authenticator = newSoftwareAuthenticator(t, COSEAlgorithmES256)
+	authenticator.flags = 0
+	options, session, _ = rp.BeginRegistration(testUserId, "user", "User", nil)
+	_, err = rp.FinishRegistration(session, authenticator.create(options))
+	assert.Equal(t, errUserNotPresent, err)
+
+	authenticator = newSoftwareAuthenticator(t, COSEAlgorithmES256)
+	options, session, _ = rp.BeginRegistration(testUserId, "user", "User", nil)
+	response := authenticator.create(options)
+	response.Id = EncodeBase64Url([]byte("another-credential"))
+	_, err = rp.FinishRegistration(session, response)
+	assert.Equal(t, errCredentialIdMismatch, err)
+}
+
+func TestRelyingParty_FinishLoginWithInvalidAssertion(t *testing.T) {
+	rp := newTestRelyingParty()
+	authenticator := newSoftwareAuthenticator(t, COSEAlgorithmES256)
+	credential := registerTestCredential(t, rp, authenticator)
+
+	// user handle of another user
+	options, session, _ := rp.BeginLogin(testUserId, nil, false)
+	_, err := rp.FinishLogin(session, authenticator.get(options, "another-user"), credential, testUserId)
+	assert.Equal(t, errUserHandleMismatch, err)
+
+	// tampered signature
+	options, session, _ = rp.BeginLogin(testUserId, nil, false)
+	response := authenticator.get(options, testUserId)
+	signature, _ := DecodeBase64Url(response.Response.Signature)
+	signature[len(signature)-1] ^= 0xff
+	response.Response.Signature = EncodeBase64Url(signature)
+	_, err = rp.FinishLogin(session, response, credential, testUserId)
+	assert.Equal(t, errSignatureInvalid, err)
+
+	// user verification is required
+	authenticator.flags = authenticatorDataFlagUserPresent
+	options, session, _ = rp.BeginLogin(testUserId, nil, true)
+	_, err = rp.FinishLogin(session, authenticator.get(options, testUserId), credential, testUserId)
+	assert.Equal(t, errUserNotVerified, err)
+
+	// signature counter does not increase
+	credential.SignCount = 100
+	options, session, _ = rp.BeginLogin(testUserId, nil, false)
+	_, err = rp.FinishLogin(session, authenticator.get(options, testUserId), credential, testUserId)
+	assert.Equal(t, errSignCountInvalid, err)
+}
+
+func TestSession_EncodeAndParse(t *testing.T) {
+	rp := newTestRelyingParty()
+	_, session, err := rp.BeginLogin(testUserId, nil, true)
+	assert.Nil(t, err)
+
+	encodedSession, err := session.Encode("secret", time.Now())
+	assert.Nil(t, err)
+
+	actualSession, err := ParseSession(encodedSession, "secret", SESSION_TYPE_AUTHENTICATION)
+	assert.Nil(t, err)
+	assert.Equal(t, session, actualSession)
+
+	_, err = ParseSession(encodedSession, "secret", SESSION_TYPE_REGISTRATION)
+	assert.NotNil(t, err)
+
+	_, err = ParseSession(encodedSession, "another-secret", SESSION_TYPE_AUTHENTICATION)
+	assert.NotNil(t, err)
+
+	encodedSession, err = session.Encode("secret", time.Now().Add(-SessionValidDuration-1))
+	assert.Nil(t, err)
+
+	_, err = ParseSession(encodedSession, "secret", SESSION_TYPE_AUTHENTICATION)
+	assert.NotNil(t, err)
+}
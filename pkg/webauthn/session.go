@@ -0,0 +1,82 @@
+package webauthn
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SessionValidDuration represents how long the user can complete the ceremony in authenticator
+const SessionValidDuration = 5 * time.Minute
+
+// SessionType represents the ceremony type of session
+type SessionType string
+
+// Session types
+const (
+	SESSION_TYPE_REGISTRATION   SessionType = "registration"
+	SESSION_TYPE_AUTHENTICATION SessionType = "authentication"
+)
+
+// Session represents the challenge and the parameters of a registration or authentication ceremony,
+// which are kept by user agent until the authenticator responds
+type Session struct {
+	Type                    SessionType
+	Challenge               string
+	UserId                  string
+	UserVerificationRequire bool
+}
+
+type sessionClaims struct {
+	Type                    SessionType `json:"type"`
+	UserVerificationRequire bool        `json:"uv,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// Encode returns the signed session
+func (s *Session) Encode(secretKey string, now time.Time) (string, error) {
+	claims := &sessionClaims{
+		Type:                    s.Type,
+		UserVerificationRequire: s.UserVerificationRequire,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        s.Challenge,
+			Subject:   s.UserId,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(SessionValidDuration)),
+		},
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secretKey))
+}
+
+// ParseSession returns the session if the signed session is valid, not expired and has the expected type
+func ParseSession(encodedSession string, secretKey string, expectedType SessionType) (*Session, error) {
+	claims := &sessionClaims{}
+	_, err := jwt.ParseWithClaims(encodedSession, claims,
+		func(token *jwt.Token) (any, error) {
+			return []byte(secretKey), nil
+		},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.ID == "" {
+		return nil, errors.New("webauthn session is incomplete")
+	}
+
+	if claims.Type != expectedType {
+		return nil, errors.New("webauthn session type mismatch")
+	}
+
+	return &Session{
+		Type:                    claims.Type,
+		Challenge:               claims.ID,
+		UserId:                  claims.Subject,
+		UserVerificationRequire: claims.UserVerificationRequire,
+	}, nil
+}